@@ -0,0 +1,97 @@
+package statecode
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error 带状态码的错误，由 service 返回，response.Gin.Error 统一输出
+//   - Code: 返回给客户端的状态码，消息由 GetMsg 按语言生成
+//   - Details: 可选的附加信息，原样返回给客户端，不能放内部错误
+//   - Cause: 原始错误，只写入日志
+type Error struct {
+	Code    int
+	Details interface{}
+	Cause   error
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("statecode %d: %v", e.Code, e.Cause)
+	}
+	return fmt.Sprintf("statecode %d", e.Code)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// WithDetails 附加返回给客户端的信息
+func (e *Error) WithDetails(details interface{}) *Error {
+	e.Details = details
+	return e
+}
+
+// New 没有底层原因的业务错误，例如参数错误、记录不存在
+func New(code int) *Error {
+	return &Error{Code: code}
+}
+
+// Wrap 用状态码包装底层错误，客户端只看到状态码对应的消息，cause 写入日志
+func Wrap(code int, cause error) *Error {
+	return &Error{Code: code, Cause: cause}
+}
+
+// FromError 取出错误链中的 *Error，其他错误视为 CommonErrServerErr
+func FromError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return Wrap(CommonErrServerErr, err)
+}
+
+// httpStatus 状态码对应的 HTTP 状态码，未列出的错误码为 400
+var httpStatus = map[int]int{
+	CommonSuccess:            http.StatusOK,
+	CommonErrServerErr:       http.StatusInternalServerError,
+	TooManyRequests:          http.StatusTooManyRequests,
+	ApiDisabled:              http.StatusForbidden,
+	RequestTimeout:           http.StatusRequestTimeout,
+	RequestTooLarge:          http.StatusRequestEntityTooLarge,
+	AccessDenied:             http.StatusForbidden,
+	UnderMaintenance:         http.StatusServiceUnavailable,
+	TokenErr:                 http.StatusUnauthorized,
+	NameOrPasswordErr:        http.StatusUnauthorized,
+	WsConnNotFound:           http.StatusNotFound,
+	WsConnLimit:              http.StatusTooManyRequests,
+	WsMethodErr:              http.StatusNotFound,
+	QuarantineNotFound:       http.StatusNotFound,
+	TokenNotFound:            http.StatusNotFound,
+	TokenContractNotFound:    http.StatusNotFound,
+	TokenExists:              http.StatusConflict,
+	PoolNotFound:             http.StatusNotFound,
+	PoolMetadataNotFound:     http.StatusNotFound,
+	PoolTokenPriceErr:        http.StatusServiceUnavailable,
+	PoolWhitelistUnavailable: http.StatusServiceUnavailable,
+	SubscriptionNotFound:     http.StatusNotFound,
+	SubscriptionExpired:      http.StatusGone,
+	NetworkStatusUnavailable: http.StatusServiceUnavailable,
+	ReferralCodeNotFound:     http.StatusNotFound,
+	ReferralTxAttributed:     http.StatusConflict,
+	DepthSymbolErr:           http.StatusNotFound,
+	OrderBookUnavailable:     http.StatusServiceUnavailable,
+	OracleFeedErr:            http.StatusNotFound,
+	OraclePriceUnavailable:   http.StatusServiceUnavailable,
+	OracleSignerUnavailable:  http.StatusServiceUnavailable,
+}
+
+// HttpStatus 状态码对应的 HTTP 状态码
+func HttpStatus(code int) int {
+	status, ok := httpStatus[code]
+	if ok {
+		return status
+	}
+	return http.StatusBadRequest
+}
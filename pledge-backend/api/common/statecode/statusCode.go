@@ -1,15 +1,27 @@
 package statecode
 
+import (
+	"pledge-backend/i18n"
+	"strconv"
+)
+
 const (
-	// LangZh language
-	LangZh   = 111
-	LangEn   = 112
-	LangZhTw = 113
+	// LangZh language，其他语言由 [i18n] catalog_dir 的翻译文件注册
+	LangZh   = i18n.Zh
+	LangEn   = i18n.En
+	LangZhTw = i18n.ZhTw
 
 	// CommonSuccess common
 	CommonSuccess      = 0
 	CommonErrServerErr = 1000
 	ParameterEmptyErr  = 1001
+	TooManyRequests    = 1002
+	ApiDisabled        = 1003
+	ParameterErr       = 1004
+	RequestTimeout     = 1005
+	RequestTooLarge    = 1006
+	AccessDenied       = 1007
+	UnderMaintenance   = 1008
 
 	TokenErr = 1102 //token error
 
@@ -18,8 +30,56 @@ const (
 	ChainIdEmpty = 1202 //chain id empty
 	ChainIdErr   = 1203 //chain id error
 
+	MultiSignSignerErr    = 1204 //signers do not match the on-chain multisig
+	MultiSignThresholdErr = 1205 //threshold error or does not match the on-chain multisig
+	MultiSignAddressErr   = 1206 //signer address error
+
 	NameOrPasswordErr = 1303 //name or password error
 
+	WsConnNotFound = 1401 //websocket connection not found
+	WsTopicErr     = 1402 //websocket topic error
+	WsConnLimit    = 1403 //websocket too many connections
+	WsMethodErr    = 1404 //websocket rpc method not found
+
+	QuarantineNotFound  = 1501 //quarantined price not found
+	QuarantineActionErr = 1502 //quarantine review action error
+
+	TokenNotFound         = 1601 //token not found
+	TokenAddressErr       = 1602 //token address or checksum error
+	TokenContractNotFound = 1603 //token contract not deployed
+	TokenExists           = 1604 //token already exists
+	TokenPriceSourceErr   = 1605 //token price source error
+	TokenLogoFormatErr    = 1606 //token logo must be png or svg
+	TokenLogoSizeErr      = 1607 //token logo file size or dimensions invalid
+	TokenDecimalsErr      = 1608 //token decimals out of range
+
+	PoolNotFound             = 1701 //pool not found
+	PoolTokenPriceErr        = 1702 //pool token price unavailable
+	PoolMetadataNotFound     = 1703 //pool metadata not found
+	PoolMetadataTagErr       = 1704 //pool metadata tag error
+	PoolWhitelistUnavailable = 1705 //pool privileges not synced yet
+
+	ConfigInvalid = 1801 //config file invalid, reload rejected
+
+	SubscriptionNotFound = 1901 //subscription not found or already unsubscribed
+	SubscriptionLimit    = 1902 //too many subscriptions for this address
+	SubscriptionExpired  = 1903 //verification link expired
+	SubscriptionEventErr = 1904 //unknown subscription event
+	SubscriptionEmailErr = 1905 //email address invalid
+
+	NetworkStatusUnavailable = 2001 //network status not collected yet
+
+	ReferralCodeNotFound = 2101 //referral code not found
+	ReferralTxAttributed = 2102 //transaction already attributed to a referral code
+	ReferralSelf         = 2103 //wallet cannot use its own referral code
+
+	DepthSymbolErr       = 2201 //symbol has no order book depth subscription
+	OrderBookUnavailable = 2202 //order book not received yet or exchange feed down
+
+	OracleFeedErr           = 2301 //token is not written to the oracle
+	OraclePriceUnavailable  = 2302 //exchange price unavailable for the oracle feed
+	OracleSignerUnavailable = 2303 //oracle signer key not configured
+
 )
 
 var Msg = map[int]map[int]string{
@@ -39,6 +99,41 @@ var Msg = map[int]map[int]string{
 		LangZhTw: "参数不能為空",
 		LangEn:   "parameter is empty",
 	},
+	1002: {
+		LangZh:   "请求过于频繁，请稍后重试",
+		LangZhTw: "請求過於頻繁，請稍後重試",
+		LangEn:   "too many requests, please try again later",
+	},
+	1003: {
+		LangZh:   "接口未开放",
+		LangZhTw: "接口未開放",
+		LangEn:   "api disabled",
+	},
+	1004: {
+		LangZh:   "参数错误",
+		LangZhTw: "參數錯誤",
+		LangEn:   "parameter invalid",
+	},
+	1005: {
+		LangZh:   "请求超时，请稍后重试",
+		LangZhTw: "請求超時，請稍後重試",
+		LangEn:   "request timed out, please try again later",
+	},
+	1006: {
+		LangZh:   "请求体过大",
+		LangZhTw: "請求體過大",
+		LangEn:   "request body too large",
+	},
+	1007: {
+		LangZh:   "无权从当前网络访问",
+		LangZhTw: "無權從當前網路訪問",
+		LangEn:   "access denied from this network",
+	},
+	1008: {
+		LangZh:   "系统维护中，请稍后重试",
+		LangZhTw: "系統維護中，請稍後重試",
+		LangEn:   "service under maintenance, please try again later",
+	},
 	1101: {
 		LangZh:   "token 不能为空",
 		LangZhTw: "token 不能為空",
@@ -64,6 +159,21 @@ var Msg = map[int]map[int]string{
 		LangZhTw: "chain_id 錯誤",
 		LangEn:   "chain_id error",
 	},
+	1204: {
+		LangZh:   "签名人与链上多签合约不一致",
+		LangZhTw: "簽名人與鏈上多簽合約不一致",
+		LangEn:   "signers do not match the on-chain multisig",
+	},
+	1205: {
+		LangZh:   "门限错误或与链上多签合约不一致",
+		LangZhTw: "門限錯誤或與鏈上多簽合約不一致",
+		LangEn:   "threshold is invalid or does not match the on-chain multisig",
+	},
+	1206: {
+		LangZh:   "签名人地址错误",
+		LangZhTw: "簽名人地址錯誤",
+		LangEn:   "invalid signer address",
+	},
 	1301: {
 		LangZh:   "name 不能为空",
 		LangZhTw: "name 不能為空",
@@ -79,16 +189,195 @@ var Msg = map[int]map[int]string{
 		LangZhTw: "用戶名或密碼錯誤",
 		LangEn:   "name or password error",
 	},
+	1401: {
+		LangZh:   "连接不存在",
+		LangZhTw: "連接不存在",
+		LangEn:   "connection not found",
+	},
+	1402: {
+		LangZh:   "订阅主题错误",
+		LangZhTw: "訂閱主題錯誤",
+		LangEn:   "topic invalid",
+	},
+	1403: {
+		LangZh:   "连接数过多，请稍后重试",
+		LangZhTw: "連接數過多，請稍後重試",
+		LangEn:   "too many connections, please try again later",
+	},
+	1404: {
+		LangZh:   "不支持的方法",
+		LangZhTw: "不支援的方法",
+		LangEn:   "method not found",
+	},
+	1501: {
+		LangZh:   "待审核价格不存在",
+		LangZhTw: "待審核價格不存在",
+		LangEn:   "quarantined price not found",
+	},
+	1502: {
+		LangZh:   "审核操作错误",
+		LangZhTw: "審核操作錯誤",
+		LangEn:   "action must be approve or reject",
+	},
+	1601: {
+		LangZh:   "代币不存在",
+		LangZhTw: "代幣不存在",
+		LangEn:   "token not found",
+	},
+	1602: {
+		LangZh:   "代币地址错误",
+		LangZhTw: "代幣地址錯誤",
+		LangEn:   "token address or checksum invalid",
+	},
+	1603: {
+		LangZh:   "链上不存在该代币合约",
+		LangZhTw: "鏈上不存在該代幣合約",
+		LangEn:   "token contract not found on chain",
+	},
+	1604: {
+		LangZh:   "代币已存在",
+		LangZhTw: "代幣已存在",
+		LangEn:   "token already exists",
+	},
+	1605: {
+		LangZh:   "价格来源错误",
+		LangZhTw: "價格來源錯誤",
+		LangEn:   "price source must be oracle, exchange or coingecko",
+	},
+	1606: {
+		LangZh:   "logo 仅支持 PNG 或 SVG",
+		LangZhTw: "logo 僅支持 PNG 或 SVG",
+		LangEn:   "logo must be png or svg",
+	},
+	1607: {
+		LangZh:   "logo 文件大小或尺寸不符合要求",
+		LangZhTw: "logo 文件大小或尺寸不符合要求",
+		LangEn:   "logo file size or dimensions invalid",
+	},
+	1608: {
+		LangZh:   "代币精度超出范围 (0-36)",
+		LangZhTw: "代幣精度超出範圍 (0-36)",
+		LangEn:   "token decimals must be between 0 and 36",
+	},
+	1701: {
+		LangZh:   "质押池不存在",
+		LangZhTw: "質押池不存在",
+		LangEn:   "pool not found",
+	},
+	1702: {
+		LangZh:   "代币价格不可用",
+		LangZhTw: "代幣價格不可用",
+		LangEn:   "token price unavailable",
+	},
+	1703: {
+		LangZh:   "池子展示信息不存在",
+		LangZhTw: "池子展示信息不存在",
+		LangEn:   "pool metadata not found",
+	},
+	1704: {
+		LangZh:   "池子标签格式错误",
+		LangZhTw: "池子標籤格式錯誤",
+		LangEn:   "pool metadata tag error",
+	},
+	1705: {
+		LangZh:   "池子权限尚未同步，请稍后重试",
+		LangZhTw: "池子權限尚未同步，請稍後重試",
+		LangEn:   "pool privileges not synced yet, please try again later",
+	},
+	1801: {
+		LangZh:   "配置文件无效，未重新加载",
+		LangZhTw: "配置文件無效，未重新加載",
+		LangEn:   "config file is invalid, reload rejected",
+	},
+	1901: {
+		LangZh:   "订阅不存在或已退订",
+		LangZhTw: "訂閱不存在或已退訂",
+		LangEn:   "subscription not found",
+	},
+	1902: {
+		LangZh:   "该钱包的订阅数已达上限",
+		LangZhTw: "該錢包的訂閱數已達上限",
+		LangEn:   "too many subscriptions for this address",
+	},
+	1903: {
+		LangZh:   "验证链接已过期，请重新订阅",
+		LangZhTw: "驗證鏈接已過期，請重新訂閱",
+		LangEn:   "verification link expired, please subscribe again",
+	},
+	1904: {
+		LangZh:   "订阅事件错误",
+		LangZhTw: "訂閱事件錯誤",
+		LangEn:   "events must be settled, finished, liquidated or claimable",
+	},
+	1905: {
+		LangZh:   "邮箱地址错误",
+		LangZhTw: "郵箱地址錯誤",
+		LangEn:   "email address invalid",
+	},
+	2001: {
+		LangZh:   "暂无该链的网络状态，请稍后重试",
+		LangZhTw: "暫無該鏈的網絡狀態，請稍後重試",
+		LangEn:   "network status unavailable, please try again later",
+	},
+	2101: {
+		LangZh:   "推荐码不存在",
+		LangZhTw: "推薦碼不存在",
+		LangEn:   "referral code not found",
+	},
+	2102: {
+		LangZh:   "该交易已归属推荐码",
+		LangZhTw: "該交易已歸屬推薦碼",
+		LangEn:   "transaction already attributed to a referral code",
+	},
+	2103: {
+		LangZh:   "不能使用自己的推荐码",
+		LangZhTw: "不能使用自己的推薦碼",
+		LangEn:   "cannot use your own referral code",
+	},
+	2201: {
+		LangZh:   "该交易对未订阅深度",
+		LangZhTw: "該交易對未訂閱深度",
+		LangEn:   "order book depth not available for this symbol",
+	},
+	2202: {
+		LangZh:   "暂无盘口数据，请稍后重试",
+		LangZhTw: "暫無盤口數據，請稍後重試",
+		LangEn:   "order book unavailable, please try again later",
+	},
+	2301: {
+		LangZh:   "该代币不写入 Oracle",
+		LangZhTw: "該代幣不寫入 Oracle",
+		LangEn:   "token is not an oracle feed",
+	},
+	2302: {
+		LangZh:   "暂无交易所价格，请指定价格",
+		LangZhTw: "暫無交易所價格，請指定價格",
+		LangEn:   "exchange price unavailable, please specify a price",
+	},
+	2303: {
+		LangZh:   "未配置喂价签名私钥",
+		LangZhTw: "未配置餵價簽名私鑰",
+		LangEn:   "oracle signer key not configured",
+	},
+}
+
+func init() {
+	messages := make(i18n.Messages, len(Msg))
+	for code, texts := range Msg {
+		messages[msgKey(code)] = texts
+	}
+	i18n.Add(messages)
+}
+
+// msgKey 状态码在 i18n 文案目录中的 key，例如 code.1004
+func msgKey(c int) string {
+	return "code." + strconv.Itoa(c)
 }
 
+// GetMsg 状态码对应的消息，没有该语言的翻译时返回英文，未知状态码返回服务器错误的消息
 func GetMsg(c int, lang int) string {
-	_, ok := Msg[c]
-	if ok {
-		msg, ok := Msg[c][lang]
-		if ok {
-			return msg
-		}
-		return Msg[CommonErrServerErr][lang]
+	if !i18n.Has(msgKey(c)) {
+		c = CommonErrServerErr
 	}
-	return Msg[CommonErrServerErr][lang]
+	return i18n.T(lang, msgKey(c))
 }
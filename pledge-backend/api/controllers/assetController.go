@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"net/http"
+	"pledge-backend/api/static"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// assetCacheControl /assets 的地址带内容哈希，内容不会变化
+const assetCacheControl = "public, max-age=31536000, immutable"
+
+// AssetController 带内容哈希的静态文件，供 CDN 长期缓存
+type AssetController struct {
+}
+
+// Asset 返回 static 目录下的文件
+// 【API】GET /assets/{path}.{hash}.{ext}，例如 /assets/img/tokens/97_0xabc.3f2a9c1d5e7b.png
+//
+// 地址由 /token、/token/logo 返回；文件已被替换 (哈希与当前内容不一致) 时 302 跳转到当前地址，跳转不缓存
+func (c *AssetController) Asset(ctx *gin.Context) {
+	hashed := strings.TrimPrefix(ctx.Param("filepath"), "/")
+	file, current, err := static.ResolveHashed(hashed)
+	if err != nil {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if current != hashed {
+		ctx.Header("Cache-Control", "no-cache")
+		ctx.Redirect(http.StatusFound, "/assets/"+current)
+		return
+	}
+	ctx.Header("Cache-Control", assetCacheControl)
+	ctx.File(file)
+}
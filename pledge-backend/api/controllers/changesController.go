@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/services"
+	"pledge-backend/api/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ChangesController struct {
+}
+
+// Changes 增量同步，轮询的客户端只取上次同步后变化的数据，不再重复拉取全部池子、代币和价格
+// 【API】GET /api/v{version}/changes?chainId=56&since=1700000000
+//
+// 请求参数:
+//   - since: Unix 秒、RFC3339 时间或上次返回的 cursor，包含边界；为空时返回全部数据，用于首次同步
+//
+// 返回数据:
+//   - cursor: 下次请求的 since，为服务器时间，避免客户端时钟偏差
+//   - pools / pool_data: 有变化的池子，格式同 poolBaseInfo / poolDataInfo；removed_pools: 已从链上移除的池子
+//   - tokens: 新增或修改的代币；removed_tokens: 已删除的代币
+//   - prices: 价格有变化的代币的最新价格
+func (c *ChangesController) Changes(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.Changes{}
+	result := response.Changes{}
+
+	errCode := validate.NewChanges().Changes(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewChanges().Changes(ctx.Request.Context(), &req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/services"
+	"pledge-backend/api/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConfigController struct {
+}
+
+// Reload 热加载配置文件
+// 【API】POST /api/v{version}/admin/config/reload
+func (c *ConfigController) Reload(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	result := response.ConfigReload{}
+
+	err := services.NewConfig().Reload(&result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// LogLevel 查询当前日志级别
+// 【API】GET /api/v{version}/admin/log/level
+func (c *ConfigController) LogLevel(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	result := response.LogLevel{}
+
+	services.NewConfig().LogLevel(&result)
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// SetLogLevel 运行时修改日志级别
+// 【API】POST /api/v{version}/admin/log/level
+func (c *ConfigController) SetLogLevel(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.LogLevel{}
+	result := response.LogLevel{}
+
+	errCode := validate.NewLogLevel().SetLogLevel(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewConfig().SetLogLevel(req.Level, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/services"
+	"pledge-backend/api/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ContractController struct {
+}
+
+// Contracts 合约地址，前端和集成方按环境读取，不再硬编码
+// 【API】GET /api/v{version}/contracts?chainId=97
+//
+// 返回数据:
+//   - pledge_pool、oracle、plgr、multi_sign: 合约地址，未配置时为空字符串
+//   - pools: 每个池子的 SP / JP 代币和出借、抵押代币地址
+func (c *ContractController) Contracts(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.Contracts{}
+	result := response.Contracts{}
+
+	errCode := validate.NewContract().Contracts(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewContract().Contracts(ctx.Request.Context(), &req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
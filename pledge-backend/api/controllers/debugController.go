@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"net/http/pprof"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DebugController struct {
+}
+
+// Pprof net/http/pprof 性能分析，用于排查 goroutine 泄漏、阻塞等线上问题
+// 【API】GET /api/v{version}/admin/debug/pprof/:name
+//
+// name 为空时返回 profile 列表，其余与 net/http/pprof 相同:
+// goroutine、heap、allocs、block、mutex、threadcreate、cmdline、symbol、profile?seconds=30、trace?seconds=5
+func (c *DebugController) Pprof(ctx *gin.Context) {
+	switch name := ctx.Param("name"); name {
+	case "":
+		// pprof.Index 按 /debug/pprof/ 前缀解析 profile 名称，列表页中的链接是相对路径
+		r := ctx.Request.Clone(ctx.Request.Context())
+		r.URL.Path = "/debug/pprof/"
+		pprof.Index(ctx.Writer, r)
+	case "cmdline":
+		pprof.Cmdline(ctx.Writer, ctx.Request)
+	case "profile":
+		pprof.Profile(ctx.Writer, ctx.Request)
+	case "symbol":
+		pprof.Symbol(ctx.Writer, ctx.Request)
+	case "trace":
+		pprof.Trace(ctx.Writer, ctx.Request)
+	default:
+		pprof.Handler(name).ServeHTTP(ctx.Writer, ctx.Request)
+	}
+}
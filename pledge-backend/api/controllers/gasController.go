@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/services"
+	"pledge-backend/api/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GasController struct {
+}
+
+// Summary 后端发送的交易 (Oracle 喂价等) 的月度 gas 花费
+// 【API】GET /api/v{version}/admin/gas/summary?month=2006-01&chainId=56
+func (c *GasController) Summary(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.GasSummary{}
+	result := response.GasSummary{}
+
+	errCode := validate.NewGasSpend().Summary(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewGasSpend().Summary(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"net/http"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/graphql"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GraphqlController struct {
+}
+
+// Query 只读 GraphQL 查询
+// 【API】POST /api/v{version}/graphql
+//
+// 请求体: {"query": "...", "operationName": "...", "variables": {...}}
+// 返回标准 GraphQL 响应 {"data": ..., "errors": [...]}，不使用统一的 code/msg 格式
+func (c *GraphqlController) Query(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.Graphql{}
+
+	errCode := validate.NewGraphql().Query(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, graphql.Exec(ctx.Request.Context(), req.Query, req.OperationName, req.Variables))
+}
@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"net/http"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/services"
+	"pledge-backend/api/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+}
+
+// Readyz 就绪检查
+// 【API】GET /readyz
+// MySQL 或 Redis 不可用时返回 503
+func (c *HealthController) Readyz(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	result := response.Readyz{}
+
+	err := services.NewHealth().Readyz(&result)
+	if err != nil {
+		// 与 res.Error 不同，附带各项检查结果
+		res.Response(ctx, statecode.FromError(err).Code, result, http.StatusServiceUnavailable)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// ChainsHealth 各链 RPC 节点的延迟、区块高度和落后参考节点的区块数
+// 【API】GET /api/v{version}/admin/chains/health
+func (c *HealthController) ChainsHealth(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	result := response.ChainsHealth{}
+
+	err := services.NewHealth().ChainsHealth(&result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// OracleStatus 主网 Oracle 各喂价代币的链上价格、最近一次写链时间、交易所价格、偏离、熔断器状态和最近一次写入交易的状态
+// 【API】GET /api/v{version}/admin/oracle/status
+func (c *HealthController) OracleStatus(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	result := response.OracleStatus{}
+
+	services.NewOracleStatus().Status(&result)
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// SimulateSetPrice 以喂价签名地址模拟 Oracle setPrice: 预计 gas、revert 原因和写入后的价格，不发送交易
+// 【API】POST /api/v{version}/admin/oracle/simulateSetPrice
+func (c *HealthController) SimulateSetPrice(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.OracleSimulateSetPrice{}
+	result := response.OracleSimulateSetPrice{}
+
+	errCode := validate.NewOracle().SimulateSetPrice(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewOracleSimulate().SimulateSetPrice(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// Integrity 各链最近一次抽查池子、代币的链上数据与 MySQL、Redis 的结果: 抽查数、不一致数、修复数和不一致的字段
+// 【API】GET /api/v{version}/admin/integrity
+func (c *HealthController) Integrity(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	var result []models.IntegrityReport
+
+	err := services.NewHealth().Integrity(&result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// Network 链的最新区块、平均出块时间和 gas 价格建议 (slow / standard / fast, wei)，前端据此估算交易费用
+// read_blocks 为池子、价格等同步任务最近一次读取的区块 (最新区块之前第 read_lag 个)，用于排查接口数据与链上不一致
+// 【API】GET /api/v{version}/network/{chainId}
+func (c *HealthController) Network(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.Network{}
+	result := models.NetworkStatus{}
+
+	errCode := validate.NewNetwork().Network(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewHealth().Network(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// Jobs 定时任务的执行次数、失败、超时、跳过次数和耗时
+// 【API】GET /api/v{version}/admin/jobs
+func (c *HealthController) Jobs(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	var result []models.JobStats
+
+	err := services.NewHealth().Jobs(&result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// JobRuns 定时任务最近的执行记录: 开始、结束时间、结果、处理成功和失败的条目数
+// 【API】GET /api/v{version}/admin/jobs/runs?name=UpdateAllPoolInfo&limit=50
+func (c *HealthController) JobRuns(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.JobRuns{}
+	var result []models.JobRun
+
+	errCode := validate.NewJobRun().Runs(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewHealth().JobRuns(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// JobRetries 定时任务处理失败的条目，默认查询已放弃重试的 (dead)
+// 【API】GET /api/v{version}/admin/jobs/retries?status=dead
+func (c *HealthController) JobRetries(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.JobRetries{}
+	var result []models.JobRetry
+
+	errCode := validate.NewJobRun().Retries(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewHealth().JobRetries(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
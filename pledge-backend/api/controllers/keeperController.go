@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/services"
+	"pledge-backend/api/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KeeperController struct {
+}
+
+// Txs keeper 发送的 settle / finish / liquidate 交易，包括 dry-run、超出 gas 上限和发送失败的尝试
+// 【API】GET /api/v{version}/admin/keeper/txs?chainId=97&poolId=&status=&limit=50
+func (c *KeeperController) Txs(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.KeeperTxs{}
+	result := make([]models.KeeperTx, 0)
+
+	errCode := validate.NewKeeperTx().List(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewKeeperTx().List(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
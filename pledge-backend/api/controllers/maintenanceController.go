@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/services"
+	"pledge-backend/api/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MaintenanceController struct {
+}
+
+// Maintenance 查询维护模式状态
+// 【API】GET /api/v{version}/admin/maintenance
+func (c *MaintenanceController) Maintenance(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	result := models.Maintenance{}
+
+	services.NewMaintenance().Get(&result)
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// SetMaintenance 开启或关闭维护模式
+// 【API】POST /api/v{version}/admin/maintenance
+func (c *MaintenanceController) SetMaintenance(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.Maintenance{}
+	result := models.Maintenance{}
+
+	errCode := validate.NewMaintenance().SetMaintenance(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	username, _ := ctx.Get("username")
+	err := services.NewMaintenance().Set(&req, username.(string), &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
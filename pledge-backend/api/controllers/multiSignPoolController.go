@@ -13,9 +13,12 @@ import (
 type MultiSignPoolController struct {
 }
 
+// SetMultiSign 保存多签配置并记录版本
+// 【API】POST /api/v{version}/pool/setMultiSign
 func (c *MultiSignPoolController) SetMultiSign(ctx *gin.Context) {
 	res := response.Gin{Res: ctx}
 	req := request.SetMultiSign{}
+	result := response.MultiSignVersion{}
 	log.Logger.Sugar().Info("SetMultiSign req ", req)
 
 	errCode := validate.NewMutiSign().SetMultiSign(ctx, &req)
@@ -24,15 +27,14 @@ func (c *MultiSignPoolController) SetMultiSign(ctx *gin.Context) {
 		return
 	}
 
-	errCode, err := services.NewMutiSign().SetMultiSign(&req)
-	if errCode != statecode.CommonSuccess {
-		log.Logger.Error(err.Error())
-		res.Response(ctx, errCode, nil)
+	username, _ := ctx.Get("username")
+	err := services.NewMutiSign().SetMultiSign(ctx.Request.Context(), &req, username.(string), &result)
+	if err != nil {
+		res.Error(ctx, err)
 		return
 	}
 
-	res.Response(ctx, statecode.CommonSuccess, nil)
-	return
+	res.Response(ctx, statecode.CommonSuccess, result)
 }
 
 func (c *MultiSignPoolController) GetMultiSign(ctx *gin.Context) {
@@ -47,13 +49,34 @@ func (c *MultiSignPoolController) GetMultiSign(ctx *gin.Context) {
 		return
 	}
 
-	errCode, err := services.NewMutiSign().GetMultiSign(&result, req.ChainId)
+	err := services.NewMutiSign().GetMultiSign(ctx.Request.Context(), &result, req.ChainId)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+	return
+}
+
+// MultiSignHistory 多签配置的版本历史，包含修改人和与上一版本的差异
+// 【API】GET /api/v{version}/admin/multiSign/history?chainId=97&limit=20
+func (c *MultiSignPoolController) MultiSignHistory(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.MultiSignHistory{}
+	result := make([]response.MultiSignVersion, 0)
+
+	errCode := validate.NewMutiSign().History(ctx, &req)
 	if errCode != statecode.CommonSuccess {
-		log.Logger.Error(err.Error())
 		res.Response(ctx, errCode, nil)
 		return
 	}
 
+	err := services.NewMutiSign().History(ctx.Request.Context(), &req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
 	res.Response(ctx, statecode.CommonSuccess, result)
-	return
 }
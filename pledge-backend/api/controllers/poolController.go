@@ -7,19 +7,37 @@
  * 该控制器处理所有与借贷池相关的 HTTP API 请求，包括：
  * - 获取池子基础信息 (PoolBaseInfo)
  * - 获取池子动态数据 (PoolDataInfo)
- * - 获取代币列表 (TokenList)
- * - 搜索池子 (Search)
+ * - 获取单个池子详情及计算字段 (PoolDetail)
+ * - 获取单个池子的历史快照 (PoolHistory)，支持导出 CSV
+ * - 估算出借/借款的利息、手续费和到期价值 (PoolEstimate)
+ * - 获取单个池子的参与者和存入统计 (PoolStats)
+ * - 获取单个池子的链上权限状态 (PoolWhitelist)，检查地址能否借款 (PoolWhitelistCheck)
+ * - 获取代币列表 (TokenList)，带版本号和可选的 EIP-712 签名
+ * - 获取代币列表版本变更记录 (TokenListChangelog)
+ * - 获取代币当前 logo 的内容哈希地址 (TokenLogo)
+ * - 搜索池子 (Search)，以及无需登录的公开搜索 (PublicSearch)，支持关键字模糊匹配和组合筛选
+ * - 搜索代币 (TokenSearch)
  * - 获取债务代币列表 (DebtTokenList)
  *
  * 【数据来源】
- * 所有数据来自 MySQL 数据库，这些数据由 schedule 模块从链上同步。
+ * 所有数据来自 MySQL 数据库和 Redis，这些数据由 schedule 模块从链上同步。
  * 控制器本身不直接与区块链交互。
  *
  * 【路由映射】
  * GET  /api/v{version}/poolBaseInfo   --> PoolBaseInfo()
  * GET  /api/v{version}/poolDataInfo   --> PoolDataInfo()
+ * GET  /api/v{version}/pool/:chainId/:poolId --> PoolDetail()
+ * GET  /api/v{version}/pool/:chainId/:poolId/history --> PoolHistory()
+ * GET  /api/v{version}/pool/:chainId/:poolId/estimate --> PoolEstimate()
+ * GET  /api/v{version}/pool/:chainId/:poolId/stats --> PoolStats()
+ * GET  /api/v{version}/pool/:chainId/:poolId/whitelist --> PoolWhitelist()
+ * GET  /api/v{version}/pool/:chainId/:poolId/whitelist/check --> PoolWhitelistCheck()
  * GET  /api/v{version}/token          --> TokenList()
+ * GET  /api/v{version}/token/changelog --> TokenListChangelog()
+ * GET  /api/v{version}/token/logo     --> TokenLogo()
  * POST /api/v{version}/pool/search    --> Search()
+ * GET  /api/v{version}/pool/search    --> PublicSearch()
+ * GET  /api/v{version}/token/search   --> TokenSearch()
  * POST /api/v{version}/pool/debtTokenList --> DebtTokenList()
  * ==================================================================================
  */
@@ -27,6 +45,7 @@
 package controllers
 
 import (
+	"fmt"
 	"pledge-backend/api/common/statecode"
 	"pledge-backend/api/models"
 	"pledge-backend/api/models/request"
@@ -34,9 +53,9 @@ import (
 	"pledge-backend/api/services"
 	"pledge-backend/api/validate"
 	"pledge-backend/config"
+	"pledge-backend/log"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -49,10 +68,12 @@ type PoolController struct {
 // 【API】GET /api/v{version}/poolBaseInfo?chainId={chainId}
 //
 // 请求参数:
-//   - chainId: 链 ID (97=测试网, 56=主网)
+//   - chainId: 链 ID (97=测试网, 56=主网)，多条链用逗号分隔 (97,56) 或 all，此时 data 为按链分组的 [{chain_id, data}]
+//   - archived: exclude (默认，不返回已归档的池子) / include / only
+//   - fields: 可选，逗号分隔的字段，只返回这些字段，嵌套字段用 . 连接，例如 index,pool_data.pool_id,pool_data.state
 //
 // 返回数据:
-//   - 所有池子的基础配置信息列表 (来自 MySQL poolbases 表)
+//   - 所有池子的基础配置信息列表 (来自 MySQL poolbases 表)，池子从链上移除 (软删除) 后不再返回
 //
 // 返回字段说明:
 //   - pool_id: 池子 ID
@@ -62,10 +83,11 @@ type PoolController struct {
 //   - state: 池子状态 (0=MATCH, 1=EXECUTION, 2=FINISH, 3=LIQUIDATION, 4=UNDONE)
 //   - lend_token_info: 出借代币详情 (JSON)
 //   - borrow_token_info: 抵押代币详情 (JSON)
+//   - archived: 是否已归档
 func (c *PoolController) PoolBaseInfo(ctx *gin.Context) {
 	res := response.Gin{Res: ctx}
 	req := request.PoolBaseInfo{}
-	var result []models.PoolBaseInfoRes
+	groups := make([]response.ChainData, 0)
 
 	// 1. 验证请求参数
 	errCode := validate.NewPoolBaseInfo().PoolBaseInfo(ctx, &req)
@@ -74,23 +96,207 @@ func (c *PoolController) PoolBaseInfo(ctx *gin.Context) {
 		return
 	}
 
-	// 2. 从数据库查询池子信息
-	errCode = services.NewPool().PoolBaseInfo(req.ChainId, &result)
+	// Last-Modified 在查询池子之前读取，查询期间的同步不会被当作客户端已有的版本
+	res.LastModified(services.NewSynced().LastModified(ctx.Request.Context(), req.ChainIds, "poolbases", "pool_metadata"))
+
+	// 2. 从数据库查询每条链的池子信息
+	for _, chainId := range req.ChainIds {
+		var result []models.PoolBaseInfoRes
+		err := services.NewPool().PoolBaseInfo(ctx.Request.Context(), chainId, req.Archived, &result)
+		if err != nil {
+			res.Error(ctx, err)
+			return
+		}
+		if !req.Multi {
+			res.Response(ctx, statecode.CommonSuccess, response.SelectFields(result, req.Fields))
+			return
+		}
+		groups = append(groups, response.ChainData{ChainId: chainId, Data: response.SelectFields(result, req.Fields)})
+	}
+
+	// 3. 多条链时按链分组返回
+	res.Response(ctx, statecode.CommonSuccess, groups)
+	return
+}
+
+// PoolDetail - 获取单个借贷池详情
+// 【API】GET /api/v{version}/pool/{chainId}/{poolId}?fields=pool_id,state,lender_apy
+//
+// 请求参数:
+//   - fields: 可选，逗号分隔的字段，只返回这些字段
+//
+// 返回数据:
+//   - 合并的 poolbases + pooldata 记录
+//   - 计算字段: 利用率、出借人预期年化、当前价格下的抵押率、距结算/结束时间、当前阶段倒计时
+func (c *PoolController) PoolDetail(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.PoolDetail{}
+	result := models.PoolDetail{}
+
+	errCode := validate.NewPoolBaseInfo().PoolDetail(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	res.LastModified(services.NewSynced().LastModified(ctx.Request.Context(), []int{req.ChainId}, "poolbases", "pooldata", "pool_metadata"))
+
+	err := services.NewPool().PoolDetail(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, response.SelectFields(result, req.Fields))
+}
+
+// PoolHistory - 获取单个借贷池的历史快照
+// 【API】GET /api/v{version}/pool/{chainId}/{poolId}/history?from={from}&to={to}&limit={limit}&format=csv
+//
+// 请求参数:
+//   - from/to: 时间范围 (Unix 时间戳)，可选
+//   - limit: 最多返回条数，默认且最大 1000，超出时保留最近的记录
+//   - format: json / csv，csv 时以附件返回 points
+//
+// 返回数据:
+//   - points: 按时间升序的快照 (状态、出借供给、抵押供给、结算金额)，用于绘制曲线
+//   - state_changes: 状态变化点
+func (c *PoolController) PoolHistory(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.PoolHistory{}
+	result := response.PoolHistory{}
+
+	errCode := validate.NewPoolBaseInfo().PoolHistory(ctx, &req)
 	if errCode != statecode.CommonSuccess {
 		res.Response(ctx, errCode, nil)
 		return
 	}
 
-	// 3. 返回结果
+	err := services.NewPool().PoolHistory(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	if req.Format == "csv" {
+		res.Csv(ctx, fmt.Sprintf("pool_%d_%d_history.csv", req.ChainId, req.PoolId), result.CsvHeader(), result.CsvRows())
+		return
+	}
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// PoolEstimate - 估算出借/借款的利息、手续费和到期价值
+// 【API】GET /api/v{version}/pool/{chainId}/{poolId}/estimate?amount={amount}&side=lend|borrow
+//
+// 请求参数:
+//   - amount: 金额 (代币最小单位)，lend 为出借代币，borrow 为抵押代币
+//   - side: lend / borrow
+//
+// 返回数据:
+//   - 按池子固定利率和 settleTime ~ endTime 计算的利息、lendFee/borrowFee、到期价值，前端无需重复实现定点数计算
+func (c *PoolController) PoolEstimate(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.PoolEstimate{}
+	result := response.PoolEstimate{}
+
+	errCode := validate.NewPoolBaseInfo().PoolEstimate(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewPool().PoolEstimate(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// PoolStats - 获取单个借贷池的参与者和存入统计
+// 【API】GET /api/v{version}/pool/{chainId}/{poolId}/stats?top={top}
+//
+// 返回数据:
+//   - lend/borrow 两侧的去重地址数、存入次数、累计和平均存入、累计存入最多的 top 个地址
+//   - 数据来自 schedule 索引的 DepositLend / DepositBorrow 事件
+func (c *PoolController) PoolStats(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.PoolStats{}
+	result := response.PoolStats{}
+
+	errCode := validate.NewPoolBaseInfo().PoolStats(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewPool().PoolStats(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// PoolWhitelist - 获取单个借贷池的链上权限状态
+// 【API】GET /api/v{version}/pool/{chainId}/{poolId}/whitelist
+//
+// 返回数据:
+//   - spCoin / jpCoin 的 minter 白名单、PledgePool 是否暂停，以及读取的区块
+//   - 数据来自 schedule 的 SyncPrivileges，还没有同步时返回 PoolWhitelistUnavailable
+func (c *PoolController) PoolWhitelist(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.PoolWhitelist{}
+	result := models.PoolWhitelist{}
+
+	errCode := validate.NewPoolBaseInfo().PoolWhitelist(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewPool().PoolWhitelist(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// PoolWhitelistCheck - 检查地址现在能否在借贷池中借款
+// 【API】GET /api/v{version}/pool/{chainId}/{poolId}/whitelist/check?address={address}
+//
+// 返回数据:
+//   - allowed 为 false 时 reasons 列出原因 (暂停、不在匹配阶段、已过结算时间、PledgePool 不是 jpCoin / spCoin 的 minter)
+func (c *PoolController) PoolWhitelistCheck(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.PoolWhitelistCheck{}
+	result := response.PoolWhitelistCheck{}
+
+	errCode := validate.NewPoolBaseInfo().PoolWhitelistCheck(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewPool().PoolWhitelistCheck(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
 	res.Response(ctx, statecode.CommonSuccess, result)
-	return
 }
 
 // PoolDataInfo - 获取借贷池动态数据
 // 【API】GET /api/v{version}/poolDataInfo?chainId={chainId}
 //
 // 请求参数:
-//   - chainId: 链 ID
+//   - chainId: 链 ID，多条链用逗号分隔或 all，此时 data 为按链分组的 [{chain_id, data}]
+//   - fields: 可选，逗号分隔的字段，例如 index,pool_data.settle_amount_lend
 //
 // 返回数据:
 //   - 所有池子的运行时数据列表 (来自 MySQL pooldata 表)
@@ -105,7 +311,7 @@ func (c *PoolController) PoolBaseInfo(ctx *gin.Context) {
 func (c *PoolController) PoolDataInfo(ctx *gin.Context) {
 	res := response.Gin{Res: ctx}
 	req := request.PoolDataInfo{}
-	var result []models.PoolDataInfoRes
+	groups := make([]response.ChainData, 0)
 
 	errCode := validate.NewPoolDataInfo().PoolDataInfo(ctx, &req)
 	if errCode != statecode.CommonSuccess {
@@ -113,13 +319,22 @@ func (c *PoolController) PoolDataInfo(ctx *gin.Context) {
 		return
 	}
 
-	errCode = services.NewPool().PoolDataInfo(req.ChainId, &result)
-	if errCode != statecode.CommonSuccess {
-		res.Response(ctx, errCode, nil)
-		return
+	res.LastModified(services.NewSynced().LastModified(ctx.Request.Context(), req.ChainIds, "pooldata"))
+	for _, chainId := range req.ChainIds {
+		var result []models.PoolDataInfoRes
+		err := services.NewPool().PoolDataInfo(ctx.Request.Context(), chainId, &result)
+		if err != nil {
+			res.Error(ctx, err)
+			return
+		}
+		if !req.Multi {
+			res.Response(ctx, statecode.CommonSuccess, response.SelectFields(result, req.Fields))
+			return
+		}
+		groups = append(groups, response.ChainData{ChainId: chainId, Data: response.SelectFields(result, req.Fields)})
 	}
 
-	res.Response(ctx, statecode.CommonSuccess, result)
+	res.Response(ctx, statecode.CommonSuccess, groups)
 	return
 }
 
@@ -127,18 +342,20 @@ func (c *PoolController) PoolDataInfo(ctx *gin.Context) {
 // 【API】GET /api/v{version}/token?chainId={chainId}
 //
 // 请求参数:
-//   - chainId: 链 ID
+//   - chainId: 链 ID，多条链用逗号分隔或 all
+//   - fields: 可选，逗号分隔的代币字段，例如 address,symbol；签名针对完整的 tokens，校验签名时不要使用 fields
 //
 // 返回数据:
 //   - 符合 TokenList 标准格式的代币列表 (用于钱包/DEX 集成)
+//   - 多条链时返回 {"chains": [{chain_id, data}]}，data 为每条链各自的代币列表，版本号和签名按链独立
 //
 // 返回格式: 符合 Uniswap Token List 标准
 func (c *PoolController) TokenList(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.ChainTokenList{}
+	groups := make([]response.ChainData, 0)
 
-	req := request.TokenList{}
-	result := response.TokenList{}
-
-	errCode := validate.NewTokenList().TokenList(ctx, &req)
+	errCode := validate.NewTokenList().ChainTokenList(ctx, &req)
 	if errCode != statecode.CommonSuccess {
 		ctx.JSON(200, map[string]string{
 			"error": "chainId error",
@@ -146,38 +363,119 @@ func (c *PoolController) TokenList(ctx *gin.Context) {
 		return
 	}
 
+	res.LastModified(services.NewSynced().LastModified(ctx.Request.Context(), req.ChainIds, "token_info"))
+
+	// fields 只筛选 tokens 中的代币字段，列表名称、版本号和签名始终返回
+	fields := response.NestedFields(req.Fields, "tokens", "name", "logoURI", "version", "timestamp", "signature")
+	for _, chainId := range req.ChainIds {
+		result := response.TokenList{}
+		if msg := c.tokenList(chainId, &result); msg != "" {
+			ctx.JSON(200, map[string]string{
+				"error": msg,
+			})
+			return
+		}
+		if !req.Multi {
+			ctx.JSON(200, response.SelectFields(result, fields))
+			return
+		}
+		groups = append(groups, response.ChainData{ChainId: chainId, Data: response.SelectFields(result, fields)})
+	}
+
+	ctx.JSON(200, response.TokenLists{Chains: groups})
+	return
+}
+
+// tokenList 构造一条链的代币列表，失败时返回错误信息
+func (c *PoolController) tokenList(chainId int, result *response.TokenList) string {
 	// 从数据库获取代币列表
-	errCode, data := services.NewTokenList().GetTokenList(&req)
-	if errCode != statecode.CommonSuccess {
-		ctx.JSON(200, map[string]string{
-			"error": "chainId error",
-		})
-		return
+	tokens, err := services.NewTokenList().Tokens(chainId)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return "chainId error"
 	}
 
 	// 构造符合 TokenList 标准的响应
 	var BaseUrl = c.GetBaseUrl()
 	result.Name = "Pledge Token List"
-	result.LogoURI = BaseUrl + "storage/img/Pledge-project-logo.png"
-	result.Timestamp = time.Now()
+	// logo 使用 /assets 下带内容哈希的地址，logo 更换后地址变化，钱包和 CDN 不会继续使用旧图片
+	result.LogoURI = services.NewAsset().Url(BaseUrl + "storage/img/Pledge-project-logo.png")
+	result.Tokens = tokens
+
+	// 版本号在代币写入时生成 (代币管理接口、schedule 同步任务)，这里只读取最新版本，timestamp 为该版本的生成时间
+	version := models.TokenListVersion{}
+	err = services.NewTokenList().Current(chainId, &version)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return "token list version error"
+	}
+	result.Timestamp = services.VersionTime(&version)
 	result.Version = response.Version{
-		Major: 2,
-		Minor: 16,
-		Patch: 12,
-	}
-	for _, v := range data {
-		result.Tokens = append(result.Tokens, response.Token{
-			Name:     v.Symbol,
-			Symbol:   v.Symbol,
-			Decimals: v.Decimals,
-			Address:  v.Token,
-			ChainID:  v.ChainId,
-			LogoURI:  v.Logo,
-		})
+		Major: version.Major,
+		Minor: version.Minor,
+		Patch: version.Patch,
 	}
 
-	ctx.JSON(200, result)
-	return
+	// 可选的 EIP-712 签名，钱包可用 signature.signer 校验列表来源
+	err = services.NewTokenList().Sign(chainId, result)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return "token list sign error"
+	}
+	return ""
+}
+
+// TokenListChangelog - 获取 Token List 的版本变更记录
+// 【API】GET /api/v{version}/token/changelog?chainId={chainId}&limit={limit}
+//
+// 返回数据:
+//   - 每个版本新增、删除、修改的代币地址，新版本在前
+func (c *PoolController) TokenListChangelog(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.TokenListChangelog{}
+	result := make([]response.TokenListChange, 0)
+
+	errCode := validate.NewTokenList().Changelog(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	res.LastModified(services.NewSynced().LastModified(ctx.Request.Context(), []int{req.ChainId}, "token_list_version"))
+
+	err := services.NewTokenList().Changelog(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// TokenLogo - 代币当前 logo 的内容哈希地址
+// 【API】GET /api/v{version}/token/logo?chainId={chainId}&address={address}
+//
+// 返回数据:
+//   - logo: /assets 下带内容哈希的地址，logo 更换后地址随之变化，可以长期缓存；远程图片返回原地址
+//   - variants: 上传的 PNG 的缩略图，尺寸 -> 地址
+func (c *PoolController) TokenLogo(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.TokenLogoUrl{}
+	result := response.TokenLogoUrl{}
+
+	errCode := validate.NewTokenList().LogoUrl(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewAsset().TokenLogo(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
 }
 
 // Search - 搜索借贷池
@@ -185,10 +483,11 @@ func (c *PoolController) TokenList(ctx *gin.Context) {
 //
 // 请求参数 (JSON Body):
 //   - 搜索条件 (具体字段见 request.Search)
+//   - fields: query 参数，可选，逗号分隔的 rows 字段
 //
 // 返回数据:
 //   - 符合条件的池子列表
-//   - 总数量
+//   - 总数量，分页信息见 meta.pagination
 func (c *PoolController) Search(ctx *gin.Context) {
 	res := response.Gin{Res: ctx}
 	req := request.Search{}
@@ -200,16 +499,75 @@ func (c *PoolController) Search(ctx *gin.Context) {
 		return
 	}
 
-	errCode, count, pools := services.NewSearch().Search(&req)
+	count, pools, err := services.NewSearch().Search(ctx.Request.Context(), &req)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	result.Rows = pools
+	result.Count = count
+	res.ResponsePage(ctx, response.SelectFields(result, response.NestedFields(req.Fields, "rows", "count")), response.NewPagination(req.Page, req.PageSize, count))
+	return
+}
+
+// PublicSearch - 公开搜索借贷池，无需登录，按 IP 限流
+// 【API】GET /api/v{version}/pool/search?chainID={chainID}&lend_token_symbol=&state=&page=&pageSize=&fields=
+//
+// 请求参数:
+//   - fields: 可选，逗号分隔的 rows 字段，count 始终返回
+//
+// 返回数据:
+//   - 符合条件的池子列表（仅公开字段，见 response.PublicPool）
+//   - 总数量，分页信息见 meta.pagination
+func (c *PoolController) PublicSearch(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.Search{}
+	result := response.PublicSearch{}
+
+	errCode := validate.NewSearch().PublicSearch(ctx, &req)
 	if errCode != statecode.CommonSuccess {
 		res.Response(ctx, errCode, nil)
 		return
 	}
 
+	count, pools, err := services.NewSearch().PublicSearch(ctx.Request.Context(), &req)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
 	result.Rows = pools
 	result.Count = count
-	res.Response(ctx, statecode.CommonSuccess, result)
-	return
+	res.ResponsePage(ctx, response.SelectFields(result, response.NestedFields(req.Fields, "rows", "count")), response.NewPagination(req.Page, req.PageSize, count))
+}
+
+// TokenSearch - 按 symbol、name、地址模糊搜索代币
+// 【API】GET /api/v{version}/token/search?chainId={chainId}&keyword={keyword}&fields=
+//
+// 请求参数:
+//   - fields: 可选，逗号分隔的代币字段
+//
+// 返回数据:
+//   - 匹配的代币，最多 20 条
+func (c *PoolController) TokenSearch(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.TokenSearch{}
+	result := make([]models.TokenList, 0)
+
+	errCode := validate.NewSearch().TokenSearch(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewSearch().TokenSearch(ctx.Request.Context(), &req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, response.SelectFields(result, req.Fields))
 }
 
 // DebtTokenList - 获取债务代币列表 (SP Token / JP Token)
@@ -231,9 +589,9 @@ func (c *PoolController) DebtTokenList(ctx *gin.Context) {
 		return
 	}
 
-	errCode, result := services.NewTokenList().DebtTokenList(&req)
-	if errCode != statecode.CommonSuccess {
-		res.Response(ctx, errCode, nil)
+	result, err := services.NewTokenList().DebtTokenList(&req)
+	if err != nil {
+		res.Error(ctx, err)
 		return
 	}
 
@@ -245,15 +603,15 @@ func (c *PoolController) DebtTokenList(ctx *gin.Context) {
 // 用于生成静态资源的完整 URL (如代币 Logo)
 func (c *PoolController) GetBaseUrl() string {
 
-	domainName := config.Config.Env.DomainName
+	domainName := config.Config().Env.DomainName
 	domainNameSlice := strings.Split(domainName, "")
 	pattern := "\\d+"
 	// 判断域名是否以数字开头 (IP 地址)
 	isNumber, _ := regexp.MatchString(pattern, domainNameSlice[0])
 	if isNumber {
 		// IP 地址格式: http://192.168.1.1:8080/
-		return config.Config.Env.Protocol + "://" + config.Config.Env.DomainName + ":" + config.Config.Env.Port + "/"
+		return config.Config().Env.Protocol + "://" + config.Config().Env.DomainName + ":" + config.Config().Env.Port + "/"
 	}
 	// 域名格式: https://api.pledge.finance/
-	return config.Config.Env.Protocol + "://" + config.Config.Env.DomainName + "/"
+	return config.Config().Env.Protocol + "://" + config.Config().Env.DomainName + "/"
 }
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/services"
+	"pledge-backend/api/validate"
+)
+
+type PoolMetadataController struct {
+}
+
+// List 查看指定链的池子展示信息
+// 【API】GET /api/v{version}/admin/pool/metadata?chainId={chainId}
+func (c *PoolMetadataController) List(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.PoolMetadataList{}
+	result := make([]response.PoolMetadata, 0)
+
+	errCode := validate.NewPoolMetadata().List(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewPoolMetadata().List(ctx.Request.Context(), &req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// Save 新增或覆盖池子的名称、说明、风险标签和推荐标记
+// 【API】POST /api/v{version}/admin/pool/metadata/save
+func (c *PoolMetadataController) Save(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.SavePoolMetadata{}
+	result := response.PoolMetadata{}
+
+	errCode := validate.NewPoolMetadata().Save(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	username, _ := ctx.Get("username")
+	err := services.NewPoolMetadata().Save(&req, username.(string), &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// Delete 删除池子展示信息，poolBaseInfo 恢复只返回链上数据
+// 【API】POST /api/v{version}/admin/pool/metadata/delete
+func (c *PoolMetadataController) Delete(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.DeletePoolMetadata{}
+
+	errCode := validate.NewPoolMetadata().Delete(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewPoolMetadata().Delete(&req)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, nil)
+}
@@ -27,9 +27,17 @@ package controllers
 
 import (
 	"net/http"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
 	"pledge-backend/api/models/ws"
+	"pledge-backend/api/services"
+	"pledge-backend/api/validate"
+	"pledge-backend/config"
 	"pledge-backend/log"
 	"pledge-backend/utils"
+	"strconv"
 	"strings"
 	"time"
 
@@ -65,6 +73,34 @@ type PriceController struct {
 // 【心跳保活】
 // 客户端需要定期发送 "ping" 消息保持连接，服务器会回复 "pong"。
 // 超时未收到心跳，服务器会主动断开连接。
+//
+// 【主题订阅】
+// 连接建立后默认订阅 "price" 并立即收到当前价格，也可以通过 ?topic=price,pool:97 指定连接时订阅的主题。
+// 发送 {"op":"subscribe","topic":"pool:97"} 订阅池子主题，订阅后立即收到当前池子快照；
+// 发送 {"op":"subscribe","topic":"deadline:97"} 订阅池子结算倒计时，订阅后立即收到最近一天的通知；
+// 发送 {"op":"subscribe","topic":"price:BTC-USDT"} 订阅其他已配置交易对的价格。
+//
+// 【RPC 请求】
+// 发送 {"id":1,"method":"getPoolBaseInfo","params":{"chainId":97}}，收到 {"id":1,"result":[...]}，
+// 出错时为 {"id":1,"error":{"code":...,"message":"..."}}，message 使用连接时 Accept-Language 的语言；
+// 可用的方法见 routes 中的 ws.RegisterMethod，每个连接最多同时执行 ws.RpcMaxInFlight 个请求。
+//
+// 【私有主题】
+// jobs (定时任务执行记录) 和 ops (运维事件) 需要管理员登录的 authCode 或 [env] wss_api_keys 中的 API key，
+// 在连接时通过 ?authCode= / ?apiKey= (或请求头 authCode / X-Api-Key) 提供，或连接后发送 {"op":"auth","apiKey":"..."}，也可以放在订阅消息中。
+// claimable:{chainId}:{address} (钱包可提取金额变化) 只能由该钱包订阅: 先请求 /user/{address}/nonce，
+// 钱包对返回的 message 签名 (personal_sign)，在连接时通过 ?address=&signature= 提供，
+// 或连接后发送 {"op":"auth","address":"0x...","signature":"0x..."}；authCode / API key 不能订阅 claimable。
+// price、pool、deadline 主题仍然公开。
+//
+// 【断线重放】
+// 广播消息带递增序号 seq，重连时携带 ?last_seq={最后收到的 seq} 和原来的 ?topic=，
+// 服务端补发断线期间遗漏的消息，不再推送快照；遗漏超出 [env] wss_replay_size 条时改为推送当前快照。
+// 补发与实时消息不会重复，客户端仍可按 seq 丢弃已处理的消息。
+//
+// 【二进制编码】
+// 连接时提供子协议 msgpack (new WebSocket(url, ['msgpack'])) 或 ?encoding=msgpack，
+// 服务端消息改为 msgpack 二进制帧，内容与 JSON 相同；客户端消息可以是 JSON 文本帧或 msgpack 二进制帧。
 func (c *PriceController) NewPrice(ctx *gin.Context) {
 
 	// ============================================================
@@ -79,7 +115,26 @@ func (c *PriceController) NewPrice(ctx *gin.Context) {
 	}()
 
 	// ============================================================
-	// Step 1: HTTP 升级为 WebSocket
+	// Step 1: 参数检查和连接数限制
+	// ============================================================
+	// 在升级之前占用名额，超出总数或单 IP 限制直接返回 429
+	// 名额在 ReadAndWrite() 退出时释放
+	res := response.Gin{Res: ctx}
+	req := request.WsPrice{}
+	errCode := validate.NewWsConnection().WsPrice(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	randomId, ip := connId(ctx)
+	if !ws.Limiter.Acquire(ip) {
+		res.Response(ctx, statecode.WsConnLimit, nil, http.StatusTooManyRequests)
+		return
+	}
+
+	// ============================================================
+	// Step 2: HTTP 升级为 WebSocket
 	// ============================================================
 	// 使用 gorilla/websocket 库进行协议升级
 	conn, err := (&websocket.Upgrader{
@@ -89,33 +144,27 @@ func (c *PriceController) NewPrice(ctx *gin.Context) {
 		WriteBufferSize: 1024,
 		// 握手超时时间: 5秒（防止恶意连接）
 		HandshakeTimeout: 5 * time.Second,
-		// 跨域检查: 允许所有来源
-		// ⚠️ 生产环境建议根据实际需求限制来源
+		// 子协议: msgpack / json，客户端同时提供时优先 msgpack
+		Subprotocols: ws.Subprotocols,
+		// 跨域检查: 与 HTTP 接口相同，按 [cors] allow_origins 限制来源
+		// 非浏览器客户端不带 Origin，不做限制
 		CheckOrigin: func(r *http.Request) bool {
-			return true // 允许所有跨域请求
+			origin := r.Header.Get("Origin")
+			return origin == "" || config.Config().Cors.OriginAllowed(origin)
 		},
 	}).Upgrade(ctx.Writer, ctx.Request, nil)
 
 	// 升级失败（可能是客户端不支持 WebSocket）
 	if err != nil {
+		ws.Limiter.Release(ip)
 		log.Logger.Sugar().Error("websocket request err:", err)
 		return
 	}
 
-	// ============================================================
-	// Step 2: 生成连接唯一标识符
-	// ============================================================
-	// 格式: {IP地址}_{随机字符串}
-	// 例如: 192_168_1_100_abc123xyz...
-	// 用于在日志中追踪特定连接，便于调试
-	randomId := ""
-	remoteIP, ok := ctx.RemoteIP()
-	if ok {
-		// 将 IP 中的点替换为下划线，拼接随机字符串
-		randomId = strings.Replace(remoteIP.String(), ".", "_", -1) + "_" + utils.GetRandomString(23)
-	} else {
-		// 无法获取 IP 时，使用纯随机 ID
-		randomId = utils.GetRandomString(32)
+	// 协商了子协议时以子协议为准，否则使用 ?encoding=
+	encoding := req.Encoding
+	if conn.Subprotocol() != "" {
+		encoding = conn.Subprotocol()
 	}
 
 	// ============================================================
@@ -124,22 +173,347 @@ func (c *PriceController) NewPrice(ctx *gin.Context) {
 	// 每个连接对应一个 Server 实例，包含:
 	// - Id: 唯一标识符（用于日志和调试）
 	// - Socket: 底层 WebSocket 连接
-	// - Send: 发送消息的缓冲通道（当前未使用，预留扩展）
+	// - Send: 发送消息的缓冲通道（Hub 广播写入，连接的写协程负责发送，写满即被剔除）
 	// - LastTime: 最后心跳时间（用于超时检测）
+	// - Ip/ConnectAt/Topics: 连接元信息（用于管理端查看）
 	server := &ws.Server{
-		Id:       randomId,
-		Socket:   conn,
-		Send:     make(chan []byte, 800), // 缓冲区大小 800 条消息
-		LastTime: time.Now().Unix(),      // 初始化为当前时间
+		Id:          randomId,
+		Socket:      conn,
+		Send:        make(chan *ws.TopicMessage, 800), // 缓冲区大小 800 条消息
+		LastTime:    time.Now().Unix(),                // 初始化为当前时间
+		Ip:          ip,
+		ConnectAt:   time.Now().Unix(),
+		Topics:      strings.Split(req.Topic, ","), // 默认订阅价格推送
+		LastEventId: req.LastSeq,
+		Lang:        response.Language(ctx),
+		Encoding:    encoding,
+		Authorized:  req.Authorized,
+	}
+	if req.Wallet != "" {
+		server.Wallets = []string{req.Wallet}
 	}
 
 	// ============================================================
 	// Step 4: 启动连接处理协程
 	// ============================================================
 	// ReadAndWrite() 会:
-	// 1. 将连接注册到全局连接池 (ws.Manager.Servers)
-	// 2. 启动心跳检测循环
+	// 1. 通过 ws.Manager.Register() 将连接交给 Hub 注册
+	// 2. 启动写协程和心跳检测循环
 	// 3. 监听客户端消息（处理 ping/pong）
 	// 4. 连接断开或超时时自动清理
 	go server.ReadAndWrite()
 }
+
+// PriceSse SSE 价格推送（WebSocket 被代理拦截时的兜底方案）
+// 【API】GET /api/v{version}/price/sse?topic=price,pool:97
+//
+// 与 WebSocket 共用同一个 Hub，推送内容一致。
+// 断线重连时浏览器会自动携带 Last-Event-ID 请求头，服务端补发之后遗漏的消息。
+func (c *PriceController) PriceSse(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.PriceSse{}
+
+	errCode := validate.NewWsConnection().PriceSse(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	randomId, ip := connId(ctx)
+	if !ws.Limiter.Acquire(ip) {
+		res.Response(ctx, statecode.WsConnLimit, nil, http.StatusTooManyRequests)
+		return
+	}
+	defer ws.Limiter.Release(ip)
+
+	server := &ws.Server{
+		Id:          randomId,
+		Send:        make(chan *ws.TopicMessage, 800),
+		LastTime:    time.Now().Unix(),
+		Ip:          ip,
+		ConnectAt:   time.Now().Unix(),
+		Topics:      strings.Split(req.Topic, ","),
+		LastEventId: req.LastEventId,
+	}
+
+	err := server.ServeSSE(ctx.Writer, ctx.Request.Context().Done())
+	if err != nil {
+		log.Logger.Sugar().Error("sse request err:", err)
+	}
+}
+
+// PriceSources 代币的 Oracle 价格与 Chainlink 价格，供前端和风控交叉校验
+// 【API】GET /api/v{version}/price/sources?chainId=56
+func (c *PriceController) PriceSources(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.TokenList{}
+
+	errCode := validate.NewTokenList().TokenList(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	// 价格写入 token_info 时同时更新 updated_at
+	res.LastModified(services.NewSynced().LastModified(ctx.Request.Context(), []int{req.ChainId}, "token_info"))
+	data, err := services.NewTokenList().GetTokenPriceSources(&req)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, data)
+}
+
+// Slippage 按交易所盘口估算市价成交的滑点，供清算机器人和前端大额交易提示
+// 【API】GET /api/v{version}/price/{symbol}/slippage?amount=100000&side=sell
+//
+// 请求参数:
+//   - symbol: 交易对，需在 [exchange] depth_symbols 中
+//   - amount: 成交数量 (基础币单位)
+//   - side: sell (默认，吃买盘) / buy (吃卖盘)
+//
+// 返回数据:
+//   - avg_price、worst_price、mid_price 及 slippage (成交均价相对中间价的不利偏离比例)
+//   - filled、complete: 50 档深度不足以全部成交时 complete=false，按能成交的部分计算
+func (c *PriceController) Slippage(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.Slippage{}
+	result := response.Slippage{}
+
+	errCode := validate.NewPrice().Slippage(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewPrice().Slippage(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// Prices 批量查询代币价格，一次请求取回页面上所有代币的价格
+// 【API】GET /api/v{version}/prices?chainId=56&tokens={token},{token}
+//
+// 返回数据:
+//   - prices: 按请求顺序的代币价格 (1e8 精度) 和精度
+//   - missing: 未收录的代币地址
+func (c *PriceController) Prices(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.Prices{}
+	result := response.Prices{}
+
+	errCode := validate.NewPrice().Prices(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	res.LastModified(services.NewSynced().LastModified(ctx.Request.Context(), []int{req.ChainId}, "token_info"))
+
+	err := services.NewPrice().Prices(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// Convert 按当前价格和代币精度换算金额
+// 【API】GET /api/v{version}/convert?chainId=56&from={token}&to={token}&amount={amount}
+//
+// 请求参数:
+//   - amount: 源代币最小单位
+//
+// 返回数据:
+//   - result: 目标代币最小单位，向下取整
+//   - rate、value_usd 及两个代币的价格和精度
+func (c *PriceController) Convert(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.Convert{}
+	result := response.Convert{}
+
+	errCode := validate.NewPrice().Convert(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewPrice().Convert(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// PriceHistory 代币价格变化记录
+// 【API】GET /api/v{version}/price/history?chainId=97&token={token}&from={from}&to={to}&limit={limit}&format=csv
+//
+// 请求参数:
+//   - from/to: 时间范围 (Unix 时间戳)，可选
+//   - limit: 最多返回条数，默认且最大 1000，超出时保留最近的记录
+//   - format: json / csv，csv 时以附件返回
+func (c *PriceController) PriceHistory(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.PriceHistory{}
+	result := response.PriceHistory{}
+
+	errCode := validate.NewPriceHistory().PriceHistory(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	res.LastModified(services.NewSynced().LastModified(ctx.Request.Context(), []int{req.ChainId}, "token_price_history"))
+
+	err := services.NewPriceHistory().PriceHistory(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	if req.Format == "csv" {
+		res.Csv(ctx, "price_"+strconv.Itoa(req.ChainId)+"_"+strings.ToLower(req.Token)+"_history.csv", result.CsvHeader(), result.CsvRows())
+		return
+	}
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// PriceQuarantine 查看被异常检测隔离的价格
+// 【API】GET /api/v{version}/admin/price/quarantine?status=pending
+func (c *PriceController) PriceQuarantine(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.PriceQuarantineList{}
+	var result []models.PriceQuarantine
+
+	errCode := validate.NewPriceQuarantine().List(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewPriceQuarantine().List(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// ReviewPriceQuarantine 审核隔离价格，approve 写入 token_info，reject 丢弃
+// 【API】POST /api/v{version}/admin/price/quarantine/review
+func (c *PriceController) ReviewPriceQuarantine(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.ReviewPriceQuarantine{}
+
+	errCode := validate.NewPriceQuarantine().Review(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewPriceQuarantine().Review(&req)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, nil)
+}
+
+// PriceBackfills 查看历史价格回填的进度
+// 【API】GET /api/v{version}/admin/price/backfill?chainId=56
+func (c *PriceController) PriceBackfills(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.PriceBackfillList{}
+	var result []models.PriceBackfill
+
+	errCode := validate.NewPriceBackfill().List(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewPriceBackfill().List(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// SubmitPriceBackfill 提交代币的历史价格回填，由 schedule 在历史区块上读取 Oracle 价格写入 token_price_history
+// 【API】POST /api/v{version}/admin/price/backfill
+//
+// 请求参数: {chain_id, token, from_block}
+// 同一代币未完成的回填直接返回当前进度；失败的回填用相同的 from_block 重新提交时从检查点继续
+func (c *PriceController) SubmitPriceBackfill(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.PriceBackfill{}
+	result := models.PriceBackfill{}
+
+	errCode := validate.NewPriceBackfill().Submit(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewPriceBackfill().Submit(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// connId 生成连接唯一标识符
+// 格式: {IP地址}_{随机字符串}
+// 例如: 192_168_1_100_abc123xyz...
+// 用于在日志中追踪特定连接，便于调试
+func connId(ctx *gin.Context) (string, string) {
+	remoteIP, ok := ctx.RemoteIP()
+	if !ok {
+		// 无法获取 IP 时，使用纯随机 ID
+		return utils.GetRandomString(32), ""
+	}
+	ip := remoteIP.String()
+	// 将 IP 中的点替换为下划线，拼接随机字符串
+	return strings.Replace(ip, ".", "_", -1) + "_" + utils.GetRandomString(23), ip
+}
+
+// Connections 查看当前所有在线的 WebSocket 连接
+// 【API】GET /api/v{version}/admin/ws/connections
+func (c *PriceController) Connections(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	res.Response(ctx, statecode.CommonSuccess, ws.Manager.Connections())
+}
+
+// CloseConnection 强制断开指定的 WebSocket 连接
+// 【API】POST /api/v{version}/admin/ws/connections/close
+func (c *PriceController) CloseConnection(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.CloseWsConnection{}
+
+	errCode := validate.NewWsConnection().CloseWsConnection(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	if !ws.Manager.CloseConnection(req.Id) {
+		res.Response(ctx, statecode.WsConnNotFound, nil)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, nil)
+}
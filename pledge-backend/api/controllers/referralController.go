@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/services"
+	"pledge-backend/api/validate"
+)
+
+type ReferralController struct {
+}
+
+// Code 钱包的推荐码，没有时生成，所有链通用
+// 【API】POST /api/v{version}/user/{address}/referral/code
+func (c *ReferralController) Code(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.ReferralCode{}
+	result := response.ReferralCode{}
+
+	errCode := validate.NewReferral().Code(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewReferral().Code(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// Attribute 前端在存入交易上链后提交推荐码
+// 【API】POST /api/v{version}/referral/attribute
+//
+// 请求参数: {chain_id, code, tx_hash}
+// 交易已被索引时返回 verified 和存入信息，否则返回 pending，由 schedule 继续校验
+func (c *ReferralController) Attribute(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.ReferralAttribute{}
+	result := response.ReferralAttribution{}
+
+	errCode := validate.NewReferral().Attribute(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewReferral().Attribute(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// Referral 推荐人的推荐码和推荐数据
+// 【API】GET /api/v{version}/user/{address}/referral?chainId=97&window=30d
+func (c *ReferralController) Referral(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.Referral{}
+	result := response.Referral{}
+
+	errCode := validate.NewReferral().Referral(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewReferral().Referral(ctx.Request.Context(), &req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// Stats 推荐人排行
+// 【API】GET /api/v{version}/stats/referrals?chainId=97&window=30d&limit=20
+//
+// 返回数据: 按推荐存入的美元价值排序的推荐人、推荐码、存入交易数和被推荐地址数
+func (c *ReferralController) Stats(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.ReferralStats{}
+	result := response.ReferralStats{}
+
+	errCode := validate.NewReferral().Stats(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewReferral().Stats(ctx.Request.Context(), &req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/services"
+	"pledge-backend/api/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatsController struct {
+}
+
+// Tvl 数据分析页的 TVL 和利用率图表
+// 【API】GET /api/v{version}/stats/tvl?chainId=&interval=1d&range=90d
+//
+// 请求参数:
+//   - chainId: 可选，多条链用逗号分隔，为空或 all 时返回所有链
+//   - interval: 1h / 4h / 1d，默认 1d，按 UTC 对齐
+//   - range: 数字加单位 h / d，默认 30d，最长 365d，最多 1000 个点
+//
+// 返回数据:
+//   - 每条链每个时间段的 TVL、出借、抵押价值 (美元) 和利用率 (lendSupply / maxSupply)
+//   - 每个代币锁定的数量和价值
+func (c *StatsController) Tvl(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.Tvl{}
+	result := response.Tvl{}
+
+	errCode := validate.NewStats().Tvl(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewStats().Tvl(ctx.Request.Context(), &req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// Fees 协议手续费收入
+// 【API】GET /api/v{version}/stats/fees?chainId=&group=month&from=2024-01-01&to=2024-12-31
+//
+// 请求参数:
+//   - chainId: 可选，为空时合计所有链；多条链用逗号分隔或 all 时合计这些链，并在 chains 中返回每条链的合计
+//   - group: month / day，默认 month，按 UTC 划分
+//   - from / to: UTC 日期，包含两端；默认到今天，按月为最近 12 个月，按天为最近 30 天；按天最多 366 天，按月最多 120 个月
+//
+// 返回数据:
+//   - 合计和每个时间段的 lendFee、borrowFee 收入 (美元) 和完成或清算的池子数
+//   - 每个池子的手续费数量和价值，由 schedule 的 AccountFeeRevenue 在池子完成或清算后记入
+func (c *StatsController) Fees(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.Fees{}
+	result := response.Fees{}
+
+	errCode := validate.NewStats().Fees(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewStats().Fees(ctx.Request.Context(), &req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// Leaderboard 出借、抵押存入排行榜，用于社区激励活动
+// 【API】GET /api/v{version}/stats/leaderboard?chainId=97&side=lend&poolId=&window=30d&limit=20
+//
+// 请求参数:
+//   - chainId: 必填
+//   - poolId: 可选，为空时为全协议排行榜
+//   - side: lend / borrow，默认 lend
+//   - window: 按区块时间统计最近一段时间的存入，数字加单位 h / d，最长 365d，默认 all 不限时间
+//   - limit: 默认 20，最多 100
+//
+// 返回数据:
+//   - 地址按存入价值排序的排名、存入数量 (单个池子)、美元价值、存入次数和池子数
+//   - 单个已结算的池子还返回地址当前持有的 SP / JP 数量
+func (c *StatsController) Leaderboard(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.Leaderboard{}
+	result := response.Leaderboard{}
+
+	errCode := validate.NewStats().Leaderboard(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewStats().Leaderboard(ctx.Request.Context(), &req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
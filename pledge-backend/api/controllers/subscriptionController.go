@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/services"
+	"pledge-backend/api/validate"
+)
+
+type SubscriptionController struct {
+}
+
+// Subscribe 钱包订阅池子事件的邮件通知，新的邮箱需要点击验证邮件中的链接后才会收到通知
+// 【API】POST /api/v{version}/user/{address}/subscribe
+//
+// 请求参数: {chain_id, pool_id, email, events}，events 为 settled / finished / liquidated / claimable
+// 验证邮件和通知邮件使用请求头 Accept-Language 选择的语言
+func (c *SubscriptionController) Subscribe(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.Subscribe{}
+	result := response.EmailSubscription{}
+
+	errCode := validate.NewEmailSubscription().Subscribe(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewEmailSubscription().Subscribe(&req, response.Language(ctx), &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// Verify 验证邮件中的确认链接
+// 【API】GET /api/v{version}/subscription/verify?token={token}
+func (c *SubscriptionController) Verify(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.SubscriptionToken{}
+
+	errCode := validate.NewEmailSubscription().Token(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewEmailSubscription().Verify(&req)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, nil)
+}
+
+// Unsubscribe 验证邮件和通知邮件中的退订链接
+// 【API】GET /api/v{version}/subscription/unsubscribe?token={token}
+func (c *SubscriptionController) Unsubscribe(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.SubscriptionToken{}
+
+	errCode := validate.NewEmailSubscription().Token(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewEmailSubscription().Unsubscribe(&req)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, nil)
+}
+
+// List 钱包的订阅，邮箱打码返回
+// 【API】GET /api/v{version}/user/{address}/subscriptions?chainId={chainId}
+func (c *SubscriptionController) List(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.Subscriptions{}
+	result := make([]response.EmailSubscription, 0)
+
+	errCode := validate.NewEmailSubscription().List(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewEmailSubscription().List(ctx.Request.Context(), &req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/services"
+	"pledge-backend/api/validate"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TokenController 代币管理，替代直接修改 token_info 表
+type TokenController struct {
+}
+
+// List 查看指定链上的代币
+// 【API】GET /api/v{version}/admin/token?chainId={chainId}
+func (c *TokenController) List(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.TokenList{}
+	result := make([]models.TokenAdmin, 0)
+
+	errCode := validate.NewTokenList().TokenList(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewTokenAdmin().List(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// Create 新增代币，校验地址 checksum 和链上合约
+// 【API】POST /api/v{version}/admin/token/create
+func (c *TokenController) Create(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.CreateToken{}
+	result := models.TokenAdmin{}
+
+	errCode := validate.NewTokenAdmin().Create(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewTokenAdmin().Create(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// Update 修改代币的 logo、symbol、decimals、价格来源
+// 【API】POST /api/v{version}/admin/token/update
+func (c *TokenController) Update(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.UpdateToken{}
+
+	errCode := validate.NewTokenAdmin().Update(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewTokenAdmin().Update(&req)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, nil)
+}
+
+// Delete 软删除代币
+// 【API】POST /api/v{version}/admin/token/delete
+func (c *TokenController) Delete(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.DeleteToken{}
+
+	errCode := validate.NewTokenAdmin().Delete(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewTokenAdmin().Delete(&req)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, nil)
+}
+
+// UploadLogo 上传代币 logo，支持 PNG / SVG，字段名 logo
+// 【API】POST /api/v{version}/admin/token/:address/logo?chainId={chainId}
+// chainId 也可以作为 multipart 字段提交
+func (c *TokenController) UploadLogo(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.UploadTokenLogo{}
+	result := response.TokenLogo{}
+
+	errCode := validate.NewTokenAdmin().UploadLogo(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	file, err := ctx.FormFile("logo")
+	if err != nil {
+		res.Response(ctx, statecode.ParameterEmptyErr, nil)
+		return
+	}
+
+	poolController := PoolController{}
+	err = services.NewTokenLogo().Upload(&req, file, poolController.GetBaseUrl(), &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
@@ -24,9 +24,9 @@ func (c *UserController) Login(ctx *gin.Context) {
 		return
 	}
 
-	errCode = services.NewUser().Login(&req, &result)
-	if errCode != statecode.CommonSuccess {
-		res.Response(ctx, errCode, nil)
+	err := services.NewUser().Login(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
 		return
 	}
 
@@ -45,3 +45,58 @@ func (c *UserController) Logout(ctx *gin.Context) {
 	res.Response(ctx, statecode.CommonSuccess, nil)
 	return
 }
+
+// WalletNonce - 钱包证明地址所有权的一次性 nonce
+// 【API】GET /api/v{version}/user/{address}/nonce
+//
+// 返回数据:
+//   - message: 钱包需要签名 (personal_sign) 的消息，包含地址和 nonce
+//   - expires_in: 有效期 (秒)，签名只能使用一次
+//
+// 签名后在 WebSocket 连接时通过 ?address=&signature= 提供，或发送 {"op":"auth","address":"0x...","signature":"0x..."}，
+// 之后可以订阅 claimable:{chainId}:{address}
+func (c *UserController) WalletNonce(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.WalletNonce{}
+	result := response.WalletNonce{}
+
+	errCode := validate.NewUser().WalletNonce(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewUser().WalletNonce(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
+
+// Claimable - 钱包在已结束/已清算池子中可提取的金额
+// 【API】GET /api/v{version}/user/{address}/claimable?chainId={chainId}
+//
+// 返回数据:
+//   - 每个 FINISH / LIQUIDATION 池子的 SP/JP 余额，以及 withdrawLend / withdrawBorrow 可取回的代币数量
+//   - 没有 SP/JP 余额的池子不返回
+func (c *UserController) Claimable(ctx *gin.Context) {
+	res := response.Gin{Res: ctx}
+	req := request.UserClaimable{}
+	result := response.Claimable{}
+
+	errCode := validate.NewUser().Claimable(ctx, &req)
+	if errCode != statecode.CommonSuccess {
+		res.Response(ctx, errCode, nil)
+		return
+	}
+
+	err := services.NewClaimable().Claimable(&req, &result)
+	if err != nil {
+		res.Error(ctx, err)
+		return
+	}
+
+	res.Response(ctx, statecode.CommonSuccess, result)
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/services"
+	"pledge-backend/api/validate"
+)
+
+// WsRpcController /price WebSocket 连接上的 RPC 方法，在 routes 中通过 ws.RegisterMethod 注册
+// 返回的数据与对应的 HTTP 接口相同，错误以 statecode 返回
+type WsRpcController struct {
+}
+
+// PoolBaseInfo 同 /poolBaseInfo，只支持单条链
+// 【RPC】getPoolBaseInfo {"chainId":97,"archived":"include","fields":["pool_id"]}
+func (c *WsRpcController) PoolBaseInfo(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	req := request.PoolBaseInfo{}
+	var result []models.PoolBaseInfoRes
+
+	errCode := validate.NewWsConnection().WsPoolBaseInfo(params, &req)
+	if errCode != statecode.CommonSuccess {
+		return nil, statecode.New(errCode)
+	}
+
+	err := services.NewPool().PoolBaseInfo(ctx, req.ChainIds[0], req.Archived, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.SelectFields(result, req.Fields), nil
+}
+
+// PoolDataInfo 同 /poolDataInfo，只支持单条链
+// 【RPC】getPoolDataInfo {"chainId":97}
+func (c *WsRpcController) PoolDataInfo(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	req := request.PoolDataInfo{}
+	var result []models.PoolDataInfoRes
+
+	errCode := validate.NewWsConnection().WsPoolDataInfo(params, &req)
+	if errCode != statecode.CommonSuccess {
+		return nil, statecode.New(errCode)
+	}
+
+	err := services.NewPool().PoolDataInfo(ctx, req.ChainIds[0], &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.SelectFields(result, req.Fields), nil
+}
+
+// PoolDetail 同 /pool/{chainId}/{poolId}
+// 【RPC】getPoolDetail {"chainId":97,"poolId":1}
+func (c *WsRpcController) PoolDetail(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	req := request.PoolDetail{}
+	result := models.PoolDetail{}
+
+	errCode := validate.NewWsConnection().WsPoolDetail(params, &req)
+	if errCode != statecode.CommonSuccess {
+		return nil, statecode.New(errCode)
+	}
+
+	err := services.NewPool().PoolDetail(&req, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.SelectFields(result, req.Fields), nil
+}
+
+// Changes 同 /changes，收到推送后用上次的 cursor 取回变化的数据
+// 【RPC】getChanges {"chainId":97,"since":1700000000}
+func (c *WsRpcController) Changes(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	req := request.Changes{}
+	result := response.Changes{}
+
+	errCode := validate.NewWsConnection().WsChanges(params, &req)
+	if errCode != statecode.CommonSuccess {
+		return nil, statecode.New(errCode)
+	}
+
+	err := services.NewChanges().Changes(ctx, &req, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,36 @@
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"sync"
+
+	"pledge-backend/config"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaString string
+
+var (
+	schema     *graphqlgo.Schema
+	schemaOnce sync.Once
+)
+
+// Schema 解析 schema.graphql 并绑定 Resolver，只解析一次
+func Schema() *graphqlgo.Schema {
+	schemaOnce.Do(func() {
+		opts := []graphqlgo.SchemaOpt{graphqlgo.MaxParallelism(10)}
+		if config.Config().Graphql.MaxDepth > 0 {
+			opts = append(opts, graphqlgo.MaxDepth(config.Config().Graphql.MaxDepth))
+		}
+		schema = graphqlgo.MustParseSchema(schemaString, &Resolver{}, opts...)
+	})
+	return schema
+}
+
+// Exec 执行查询，每次请求使用独立的 loader 缓存代币和池子数据
+func Exec(ctx context.Context, query, operationName string, variables map[string]interface{}) *graphqlgo.Response {
+	return Schema().Exec(context.WithValue(ctx, loaderKey{}, newLoader(ctx)), query, operationName, variables)
+}
@@ -0,0 +1,96 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+)
+
+type loaderKey struct{}
+
+// loader 单次请求内的缓存，嵌套查询中的代币和 pooldata 按链整表加载一次，避免 N+1 查询
+type loader struct {
+	ctx      context.Context // 请求的 ctx，用于链路追踪
+	mu       sync.Mutex
+	tokens   map[int]map[string]*tokenResolver
+	poolData map[int]map[int]models.PoolData
+}
+
+func newLoader(ctx context.Context) *loader {
+	return &loader{
+		ctx:      ctx,
+		tokens:   map[int]map[string]*tokenResolver{},
+		poolData: map[int]map[int]models.PoolData{},
+	}
+}
+
+func getLoader(ctx context.Context) *loader {
+	if l, ok := ctx.Value(loaderKey{}).(*loader); ok {
+		return l
+	}
+	return newLoader(ctx)
+}
+
+// Tokens 指定链上未删除的代币，key 为小写地址
+func (l *loader) Tokens(chainId int) (map[string]*tokenResolver, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if tokens, ok := l.tokens[chainId]; ok {
+		return tokens, nil
+	}
+
+	var list []models.TokenAdmin
+	err := models.NewTokenAdmin().List(chainId, &list)
+	if err != nil {
+		return nil, err
+	}
+	err, sources := models.NewTokenInfo().GetTokenPriceSources(&request.TokenList{ChainId: chainId})
+	if err != nil {
+		return nil, err
+	}
+	sourceMap := map[string]models.TokenPriceSource{}
+	for _, v := range sources {
+		sourceMap[strings.ToLower(v.Token)] = v
+	}
+
+	tokens := map[string]*tokenResolver{}
+	for _, v := range list {
+		key := strings.ToLower(v.Token)
+		tokens[key] = &tokenResolver{token: v, chainId: chainId, source: sourceMap[key]}
+	}
+	l.tokens[chainId] = tokens
+	return tokens, nil
+}
+
+// Token 查询单个代币，不存在时返回 nil
+func (l *loader) Token(chainId int, address string) (*tokenResolver, error) {
+	tokens, err := l.Tokens(chainId)
+	if err != nil {
+		return nil, err
+	}
+	return tokens[strings.ToLower(address)], nil
+}
+
+// PoolData 查询池子的 pooldata，不存在时返回 false
+func (l *loader) PoolData(chainId, poolId int) (models.PoolData, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	data, ok := l.poolData[chainId]
+	if !ok {
+		var list []models.PoolDataInfoRes
+		err := models.NewPoolData().PoolDataInfo(l.ctx, chainId, &list)
+		if err != nil {
+			return models.PoolData{}, false, err
+		}
+		data = map[int]models.PoolData{}
+		for _, v := range list {
+			data[v.PoolData.PoolID] = v.PoolData
+		}
+		l.poolData[chainId] = data
+	}
+	poolData, ok := data[poolId]
+	return poolData, ok, nil
+}
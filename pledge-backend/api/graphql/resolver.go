@@ -0,0 +1,146 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"pledge-backend/api/models"
+	"pledge-backend/config"
+	"pledge-backend/log"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// errServer 数据库错误只记录日志，不把细节返回给调用方
+var errServer = errors.New("server is busy, please try again later")
+
+var errChainId = errors.New("chainId must be 97 or 56")
+
+var errArchived = errors.New("archived must be exclude, include or only")
+
+// Resolver 根查询
+type Resolver struct{}
+
+func checkChainId(chainId int32) error {
+	if chainId != 97 && chainId != 56 {
+		return errChainId
+	}
+	return nil
+}
+
+// first 列表参数，默认值由 schema 给出，不超过 [graphql] max_first
+func first(n int32, def int) int {
+	limit := def
+	if n > 0 {
+		limit = int(n)
+	}
+	if config.Config().Graphql.MaxFirst > 0 && limit > config.Config().Graphql.MaxFirst {
+		limit = config.Config().Graphql.MaxFirst
+	}
+	return limit
+}
+
+func (r *Resolver) Pools(ctx context.Context, args struct {
+	ChainId  int32
+	State    *string
+	Archived string
+	First    int32
+}) ([]*poolResolver, error) {
+	if err := checkChainId(args.ChainId); err != nil {
+		return nil, err
+	}
+	switch args.Archived {
+	case models.ArchivedExclude, models.ArchivedInclude, models.ArchivedOnly:
+	default:
+		return nil, errArchived
+	}
+	state := ""
+	if args.State != nil {
+		state = *args.State
+	}
+	var pools []models.PoolBases
+	err := models.NewPoolBases().List(int(args.ChainId), state, args.Archived, first(args.First, 50), &pools)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return nil, errServer
+	}
+	res := make([]*poolResolver, 0, len(pools))
+	for _, v := range pools {
+		res = append(res, &poolResolver{pool: v, chainId: int(args.ChainId)})
+	}
+	return res, nil
+}
+
+func (r *Resolver) Pool(ctx context.Context, args struct {
+	ChainId int32
+	PoolId  int32
+}) (*poolResolver, error) {
+	if err := checkChainId(args.ChainId); err != nil {
+		return nil, err
+	}
+	pool := models.NewPoolBases()
+	err := pool.Get(int(args.ChainId), int(args.PoolId))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Logger.Error(err.Error())
+		return nil, errServer
+	}
+	return &poolResolver{pool: *pool, chainId: int(args.ChainId)}, nil
+}
+
+func (r *Resolver) Tokens(ctx context.Context, args struct{ ChainId int32 }) ([]*tokenResolver, error) {
+	if err := checkChainId(args.ChainId); err != nil {
+		return nil, err
+	}
+	tokens, err := getLoader(ctx).Tokens(int(args.ChainId))
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return nil, errServer
+	}
+	res := make([]*tokenResolver, 0, len(tokens))
+	for _, v := range tokens {
+		res = append(res, v)
+	}
+	sortTokens(res)
+	return res, nil
+}
+
+func (r *Resolver) Token(ctx context.Context, args struct {
+	ChainId int32
+	Address string
+}) (*tokenResolver, error) {
+	if err := checkChainId(args.ChainId); err != nil {
+		return nil, err
+	}
+	token, err := getLoader(ctx).Token(int(args.ChainId), args.Address)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return nil, errServer
+	}
+	return token, nil
+}
+
+func (r *Resolver) Positions(ctx context.Context, args struct {
+	ChainId int32
+	Address string
+}) ([]*positionResolver, error) {
+	if err := checkChainId(args.ChainId); err != nil {
+		return nil, err
+	}
+	if !common.IsHexAddress(args.Address) {
+		return nil, errors.New("invalid address")
+	}
+	var positions []models.AddressPosition
+	err := models.NewPoolEvent().Positions(int(args.ChainId), common.HexToAddress(args.Address).Hex(), &positions)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return nil, errServer
+	}
+	res := make([]*positionResolver, 0, len(positions))
+	for _, v := range positions {
+		res = append(res, &positionResolver{position: v, chainId: int(args.ChainId)})
+	}
+	return res, nil
+}
@@ -0,0 +1,189 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"pledge-backend/api/models"
+	"pledge-backend/log"
+	"sort"
+)
+
+type poolResolver struct {
+	pool    models.PoolBases
+	chainId int
+}
+
+func (r *poolResolver) PoolId() int32                  { return int32(r.pool.PoolID) }
+func (r *poolResolver) ChainId() int32                 { return int32(r.chainId) }
+func (r *poolResolver) State() string                  { return r.pool.State }
+func (r *poolResolver) SettleTime() string             { return r.pool.SettleTime }
+func (r *poolResolver) EndTime() string                { return r.pool.EndTime }
+func (r *poolResolver) InterestRate() string           { return r.pool.InterestRate }
+func (r *poolResolver) MaxSupply() string              { return r.pool.MaxSupply }
+func (r *poolResolver) LendSupply() string             { return r.pool.LendSupply }
+func (r *poolResolver) BorrowSupply() string           { return r.pool.BorrowSupply }
+func (r *poolResolver) MartgageRate() string           { return r.pool.MartgageRate }
+func (r *poolResolver) AutoLiquidateThreshold() string { return r.pool.AutoLiquidateThreshold }
+func (r *poolResolver) SpCoin() string                 { return r.pool.SpCoin }
+func (r *poolResolver) JpCoin() string                 { return r.pool.JpCoin }
+func (r *poolResolver) Archived() bool                 { return r.pool.ArchivedAt != nil }
+
+func (r *poolResolver) LendToken(ctx context.Context) (*tokenResolver, error) {
+	return r.token(ctx, r.pool.LendToken)
+}
+
+func (r *poolResolver) BorrowToken(ctx context.Context) (*tokenResolver, error) {
+	return r.token(ctx, r.pool.BorrowToken)
+}
+
+func (r *poolResolver) token(ctx context.Context, address string) (*tokenResolver, error) {
+	token, err := getLoader(ctx).Token(r.chainId, address)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return nil, errServer
+	}
+	return token, nil
+}
+
+func (r *poolResolver) Data(ctx context.Context) (*poolDataResolver, error) {
+	data, ok, err := getLoader(ctx).PoolData(r.chainId, r.pool.PoolID)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return nil, errServer
+	}
+	if !ok {
+		return nil, nil
+	}
+	return &poolDataResolver{data: data}, nil
+}
+
+func (r *poolResolver) History(args struct {
+	From  *int32
+	To    *int32
+	First int32
+}) ([]*poolSnapshotResolver, error) {
+	var from, to int64
+	if args.From != nil {
+		from = int64(*args.From)
+	}
+	if args.To != nil {
+		to = int64(*args.To)
+	}
+	var snapshots []models.PoolSnapshot
+	err := models.NewPoolSnapshot().History(r.chainId, r.pool.PoolID, from, to, first(args.First, 100), &snapshots)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return nil, errServer
+	}
+	res := make([]*poolSnapshotResolver, 0, len(snapshots))
+	for _, v := range snapshots {
+		res = append(res, &poolSnapshotResolver{snapshot: v})
+	}
+	return res, nil
+}
+
+func (r *poolResolver) Events(args struct {
+	Event *string
+	First int32
+}) ([]*poolEventResolver, error) {
+	event := ""
+	if args.Event != nil {
+		event = *args.Event
+	}
+	var events []models.PoolEvent
+	err := models.NewPoolEvent().List(r.chainId, r.pool.PoolID, event, first(args.First, 50), &events)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return nil, errServer
+	}
+	res := make([]*poolEventResolver, 0, len(events))
+	for _, v := range events {
+		res = append(res, &poolEventResolver{event: v})
+	}
+	return res, nil
+}
+
+type poolDataResolver struct {
+	data models.PoolData
+}
+
+func (r *poolDataResolver) SettleAmountLend() string        { return r.data.SettleAmountLend }
+func (r *poolDataResolver) SettleAmountBorrow() string      { return r.data.SettleAmountBorrow }
+func (r *poolDataResolver) FinishAmountLend() string        { return r.data.FinishAmountLend }
+func (r *poolDataResolver) FinishAmountBorrow() string      { return r.data.FinishAmountBorrow }
+func (r *poolDataResolver) LiquidationAmountLend() string   { return r.data.LiquidationAmounLend }
+func (r *poolDataResolver) LiquidationAmountBorrow() string { return r.data.LiquidationAmounBorrow }
+
+type tokenResolver struct {
+	token   models.TokenAdmin
+	source  models.TokenPriceSource
+	chainId int
+}
+
+func (r *tokenResolver) Address() string           { return r.token.Token }
+func (r *tokenResolver) ChainId() int32            { return int32(r.chainId) }
+func (r *tokenResolver) Symbol() string            { return r.token.Symbol }
+func (r *tokenResolver) Name() string              { return r.token.Name }
+func (r *tokenResolver) Decimals() int32           { return int32(r.token.Decimals) }
+func (r *tokenResolver) Logo() string              { return r.token.Logo }
+func (r *tokenResolver) Price() string             { return r.token.Price }
+func (r *tokenResolver) PriceSource() string       { return r.token.PriceSource }
+func (r *tokenResolver) ChainlinkPrice() string    { return r.source.ChainlinkPrice }
+func (r *tokenResolver) ChainlinkUpdatedAt() int32 { return int32(r.source.ChainlinkUpdatedAt) }
+
+// sortTokens 按 symbol 排序，保证返回顺序稳定
+func sortTokens(tokens []*tokenResolver) {
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].token.Symbol != tokens[j].token.Symbol {
+			return tokens[i].token.Symbol < tokens[j].token.Symbol
+		}
+		return tokens[i].token.Token < tokens[j].token.Token
+	})
+}
+
+type poolSnapshotResolver struct {
+	snapshot models.PoolSnapshot
+}
+
+func (r *poolSnapshotResolver) State() string              { return r.snapshot.State }
+func (r *poolSnapshotResolver) LendSupply() string         { return r.snapshot.LendSupply }
+func (r *poolSnapshotResolver) BorrowSupply() string       { return r.snapshot.BorrowSupply }
+func (r *poolSnapshotResolver) SettleAmountLend() string   { return r.snapshot.SettleAmountLend }
+func (r *poolSnapshotResolver) SettleAmountBorrow() string { return r.snapshot.SettleAmountBorrow }
+func (r *poolSnapshotResolver) BlockNumber() int32         { return int32(r.snapshot.BlockNumber) }
+func (r *poolSnapshotResolver) SnapshotAt() int32          { return int32(r.snapshot.SnapshotAt) }
+
+type poolEventResolver struct {
+	event models.PoolEvent
+}
+
+func (r *poolEventResolver) Event() string      { return r.event.Event }
+func (r *poolEventResolver) Address() string    { return r.event.Address }
+func (r *poolEventResolver) Token() string      { return r.event.Token }
+func (r *poolEventResolver) Amount() string     { return r.event.Amount }
+func (r *poolEventResolver) BlockNumber() int32 { return int32(r.event.BlockNumber) }
+func (r *poolEventResolver) TxHash() string     { return r.event.TxHash }
+func (r *poolEventResolver) LogIndex() int32    { return int32(r.event.LogIndex) }
+
+type positionResolver struct {
+	position models.AddressPosition
+	chainId  int
+}
+
+func (r *positionResolver) LendDeposit() string   { return r.position.LendDeposit }
+func (r *positionResolver) BorrowDeposit() string { return r.position.BorrowDeposit }
+func (r *positionResolver) Deposits() int32       { return int32(r.position.Deposits) }
+
+func (r *positionResolver) Pool() (*poolResolver, error) {
+	pool := models.NewPoolBases()
+	err := pool.Get(r.chainId, r.position.PoolId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Logger.Error(err.Error())
+		return nil, errServer
+	}
+	return &poolResolver{pool: *pool, chainId: r.chainId}, nil
+}
@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/response"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// adminPaths /admin/* 之外的管理接口
+var adminPaths = []string{"/pool/setMultiSign", "/pool/getMultiSign"}
+
+// IsAdminRoute 路由是否为管理接口: /admin/ 下的所有路由和 adminPaths
+func IsAdminRoute(fullPath string) bool {
+	if strings.Contains(fullPath, "/admin/") {
+		return true
+	}
+	for _, path := range adminPaths {
+		if strings.HasSuffix(fullPath, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// AdminGuard 限制管理接口的访问来源，其他路由直接放行
+// 客户端 IP 不在 [admin] allow_cidrs 中，或 require_mtls 时请求未通过客户端证书校验，返回 403
+func AdminGuard() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsAdminRoute(c.FullPath()) {
+			c.Next()
+			return
+		}
+		admin := config.Config().Admin
+		ip := c.ClientIP()
+		reason := ""
+		if !admin.IpAllowed(ip) {
+			reason = "ip not in allow_cidrs"
+		} else if admin.RequireMtls && (c.Request.TLS == nil || len(c.Request.TLS.VerifiedChains) == 0) {
+			reason = "client certificate required"
+		}
+		if reason != "" {
+			log.Logger.Warn("admin access denied", zap.String("path", c.FullPath()), zap.String("ip", ip), zap.String("reason", reason))
+			res := response.Gin{Res: c}
+			res.Response(c, statecode.AccessDenied, nil)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
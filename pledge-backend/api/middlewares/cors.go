@@ -1,25 +1,47 @@
 package middlewares
 
 import (
-	"github.com/gin-gonic/gin"
 	"net/http"
+	"pledge-backend/config"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
 )
 
-// Cors 跨域中间件
+// Cors 跨域中间件，允许的来源、方法、请求头和凭证由 [cors] 配置
+// 来源不在 allow_origins 中时不返回 CORS 响应头，浏览器拒绝读取响应；预检请求直接返回 403
 func Cors() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		method := c.Request.Method
 		origin := c.Request.Header.Get("Origin")
+		cors := config.Config().Cors
 
 		if origin != "" {
-			c.Header("Access-Control-Allow-Origin", "*")
-			c.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE, UPDATE")
-			c.Header("Access-Control-Allow-Headers", "Origin, X-Requested-With, authCode, token, Content-Type, Accept, Authorization")
-			c.Header("Access-Control-Expose-Headers", "Content-Length, Access-Control-Allow-Origin, Access-Control-Allow-Headers, Cache-Control, Content-Language, Content-Type")
-			c.Header("Access-Control-Allow-Credentials", "false")
+			c.Header("Vary", "Origin")
+			if !cors.OriginAllowed(origin) {
+				if method == http.MethodOptions {
+					c.AbortWithStatus(http.StatusForbidden)
+					return
+				}
+				c.Next()
+				return
+			}
+			if cors.AllowAll() && !cors.AllowCredentials {
+				c.Header("Access-Control-Allow-Origin", "*")
+			} else {
+				c.Header("Access-Control-Allow-Origin", origin)
+			}
+			c.Header("Access-Control-Allow-Methods", strings.Join(cors.AllowMethods, ", "))
+			c.Header("Access-Control-Allow-Headers", strings.Join(cors.AllowHeaders, ", "))
+			c.Header("Access-Control-Expose-Headers", strings.Join(cors.ExposeHeaders, ", "))
+			c.Header("Access-Control-Allow-Credentials", strconv.FormatBool(cors.AllowCredentials))
+			if cors.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", strconv.FormatInt(cors.MaxAge, 10))
+			}
 			c.Set("content-type", "application/json")
 		}
-		if method == "OPTIONS" {
+		if method == http.MethodOptions {
 			c.AbortWithStatus(http.StatusNoContent)
 		}
 		c.Next()
@@ -0,0 +1,108 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"pledge-backend/api/models/response"
+	"pledge-backend/config"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HttpCache 热点读接口的 HTTP 缓存头和条件请求，放在 ResponseCache 之前，缓存命中的响应同样处理
+//   - 成功响应附带 Cache-Control ([cache] max_age)、Vary: Accept-Language、ETag，以及 handler 设置的 Last-Modified
+//   - If-None-Match 包含当前 ETag，或没有 If-None-Match 且 If-Modified-Since 不早于 Last-Modified 时返回 304，不返回响应体
+//
+// ETag 由去掉 meta 的响应体计算: meta 的 request_id、timestamp 每次请求都不同，内容相同的响应 ETag 相同，因此为弱 ETag
+func HttpCache() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+		w := &bufferWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		body := w.body.Bytes()
+		if w.Status() != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") || !response.Succeeded(body) {
+			_, _ = w.ResponseWriter.Write(body)
+			return
+		}
+		etag, err := entityTag(body)
+		if err != nil {
+			_, _ = w.ResponseWriter.Write(body)
+			return
+		}
+		header := w.Header()
+		header.Set("ETag", etag)
+		header.Set("Cache-Control", cacheControl())
+		header.Add("Vary", "Accept-Language")
+		if notModified(c.Request, etag, header.Get("Last-Modified")) {
+			header.Del("Content-Type")
+			w.ResponseWriter.WriteHeader(http.StatusNotModified)
+			w.ResponseWriter.WriteHeaderNow()
+			return
+		}
+		_, _ = w.ResponseWriter.Write(body)
+	}
+}
+
+// NoCache 原地覆盖的静态文件 (/storage)，要求浏览器和 CDN 每次用 Last-Modified 重新验证
+func NoCache() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "no-cache")
+		c.Next()
+	}
+}
+
+// entityTag 去掉 meta 后的响应体的 SHA-256 前 16 字节，json.Marshal 按 key 排序，结果稳定
+func entityTag(body []byte) (string, error) {
+	rsp := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(body, &rsp); err != nil {
+		return "", err
+	}
+	delete(rsp, "meta")
+	content, err := json.Marshal(rsp)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// cacheControl [cache] max_age 为 0 时要求浏览器和 CDN 每次都重新验证
+func cacheControl() string {
+	maxAge := config.Config().Cache.MaxAge
+	if maxAge <= 0 {
+		return "no-cache"
+	}
+	return "public, max-age=" + strconv.FormatInt(maxAge, 10)
+}
+
+// notModified 条件请求是否命中，ETag 按弱比较；有 If-None-Match 时忽略 If-Modified-Since (RFC 7232)
+func notModified(r *http.Request, etag, lastModified string) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+	if lastModified == "" {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	return err == nil && !modified.After(since)
+}
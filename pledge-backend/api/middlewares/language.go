@@ -0,0 +1,19 @@
+package middlewares
+
+import (
+	"pledge-backend/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Language 按请求头 Accept-Language 选择接口消息的语言，保存在 gin.Context 的 lang 中
+// 响应头 Content-Language 为实际使用的语言，Vary 告知缓存代理不同语言的响应不能共用
+func Language() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Parse(c.GetHeader("Accept-Language"))
+		c.Set("lang", lang)
+		c.Header("Content-Language", i18n.Tag(lang))
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Next()
+	}
+}
@@ -0,0 +1,222 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/response"
+	"pledge-backend/config"
+	"pledge-backend/i18n"
+	"pledge-backend/log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maintenanceExempt 维护期间照常处理的路由: 切换维护模式本身、登录登出、就绪检查
+var maintenanceExempt = []string{"/admin/maintenance", "/user/login", "/user/logout", "/readyz"}
+
+// readPosts 只读的 POST 接口，维护期间实时查询并附带维护信息
+var readPosts = []string{"/graphql"}
+
+// Maintenance 维护模式
+//   - 未维护: GET 读接口的成功响应缓存到 Redis ([maintenance] cache_ttl)，每个 key 每个 cache_ttl 写入一次
+//   - 维护中: GET 读接口优先返回缓存，没有缓存时实时查询，响应都附带 maintenance 字段；
+//     写接口和管理接口返回 503
+//
+// WebSocket、SSE 等长连接不经过缓存，由 ws.TopicMaintenance 通知
+func Maintenance() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.FullPath()
+		if path == "" || longLived(c) || hasSuffix(path, maintenanceExempt) {
+			c.Next()
+			return
+		}
+		state := models.CurrentMaintenance()
+		read := !IsAdminRoute(path) && (c.Request.Method == http.MethodGet || hasSuffix(path, readPosts))
+
+		switch {
+		case !state.Enabled && c.Request.Method == http.MethodGet && read:
+			cacheResponse(c)
+		case !state.Enabled:
+			c.Next()
+		case read:
+			serveMaintenanceRead(c, state)
+		default:
+			res := response.Gin{Res: c}
+			res.Response(c, statecode.UnderMaintenance, state)
+			c.Abort()
+		}
+	}
+}
+
+func hasSuffix(path string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheResponse 处理请求，状态码 200 的成功 JSON 响应 (见 response.Succeeded) 写入缓存
+// 同一 key 在 cache_ttl 内只写入一次，之后的请求不再缓冲响应
+func cacheResponse(c *gin.Context) {
+	ttl := config.Config().Maintenance.CacheTtl
+	key := cacheKey(c)
+	if ttl <= 0 || cachedKeys.fresh(key) {
+		c.Next()
+		return
+	}
+	w := &teeWriter{ResponseWriter: c.Writer, limit: int(config.Config().Maintenance.CacheMaxSize)}
+	c.Writer = w
+	c.Next()
+
+	if w.overflow || w.Status() != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") || !response.Succeeded(w.body.Bytes()) {
+		return
+	}
+	if err := models.NewMaintenance().CacheResponse(key, w.body.Bytes(), int(ttl)); err != nil {
+		log.Logger.Sugar().Warn("maintenance cache err ", c.Request.RequestURI, " ", err)
+		return
+	}
+	cachedKeys.add(key, time.Duration(ttl)*time.Second)
+}
+
+// cachedKeys 本进程已写入 (或其它实例已写入) 维护缓存的 key，过期前跳过缓存
+var cachedKeys = &cacheWrites{until: make(map[string]time.Time)}
+
+// cacheWrites 记录缓存 key 的过期时间，超过 cacheWritesMax 个时清理已过期的 key
+type cacheWrites struct {
+	lock  sync.Mutex
+	until map[string]time.Time
+}
+
+const cacheWritesMax = 10000
+
+func (w *cacheWrites) fresh(key string) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return time.Now().Before(w.until[key])
+}
+
+func (w *cacheWrites) add(key string, ttl time.Duration) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	now := time.Now()
+	if len(w.until) >= cacheWritesMax {
+		for k, until := range w.until {
+			if !now.Before(until) {
+				delete(w.until, k)
+			}
+		}
+		if len(w.until) >= cacheWritesMax {
+			return
+		}
+	}
+	w.until[key] = now.Add(ttl)
+}
+
+// cacheKey 读接口缓存的 key，同一 URI 不同语言的响应分开缓存
+func cacheKey(c *gin.Context) string {
+	return i18n.Tag(response.Language(c)) + ":" + c.Request.RequestURI
+}
+
+// serveMaintenanceRead 维护期间的读请求: GET 命中缓存直接返回，否则实时查询，响应中加入 maintenance 字段
+func serveMaintenanceRead(c *gin.Context, state models.Maintenance) {
+	if c.Request.Method == http.MethodGet {
+		if body, ok := models.NewMaintenance().CachedResponse(cacheKey(c)); ok {
+			if banner, err := withMaintenance(c, body, state); err == nil {
+				c.Header("X-Maintenance", "cached")
+				c.Data(http.StatusOK, "application/json; charset=utf-8", banner)
+				c.Abort()
+				return
+			}
+		}
+	}
+
+	w := &bufferWriter{ResponseWriter: c.Writer}
+	c.Writer = w
+	c.Next()
+	c.Writer = w.ResponseWriter
+
+	body := w.body.Bytes()
+	if strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		if banner, err := withMaintenance(c, body, state); err == nil {
+			body = banner
+		}
+	}
+	c.Header("X-Maintenance", "live")
+	_, _ = w.ResponseWriter.Write(body)
+}
+
+// withMaintenance 在 JSON 响应对象中加入 maintenance 字段
+func withMaintenance(c *gin.Context, body []byte, state models.Maintenance) ([]byte, error) {
+	banner, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	return withMeta(c, body, map[string]json.RawMessage{"maintenance": banner})
+}
+
+// withMeta 在 JSON 响应对象中加入 fields 中的字段
+// 缓存的响应中 meta 的 request_id、timestamp 是缓存时的请求，替换为当前请求，分页信息保留
+func withMeta(c *gin.Context, body []byte, fields map[string]json.RawMessage) ([]byte, error) {
+	rsp := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(body, &rsp); err != nil {
+		return nil, err
+	}
+	for name, value := range fields {
+		rsp[name] = value
+	}
+	if cached, ok := rsp["meta"]; ok {
+		meta := response.NewMeta(c)
+		var old response.Meta
+		if json.Unmarshal(cached, &old) == nil {
+			meta.Pagination = old.Pagination
+		}
+		var err error
+		if rsp["meta"], err = json.Marshal(meta); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(rsp)
+}
+
+// teeWriter 写出响应的同时保留不超过 limit 字节的副本
+type teeWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (w *teeWriter) Write(data []byte) (int, error) {
+	if !w.overflow && w.body.Len()+len(data) <= w.limit {
+		w.body.Write(data)
+	} else {
+		w.overflow = true
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *teeWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// bufferWriter 暂存响应体，由调用方修改后写出；状态码和响应头照常记录在底层 Writer
+type bufferWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/response"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	"strconv"
+	"time"
+)
+
+// RateLimit 按 IP 固定窗口限流，计数存放在 Redis，多实例部署时共享
+// limits 每次请求时调用，返回限流次数和窗口 (s)，配置热加载后立即生效
+// limit 为 0 时不限流；Redis 不可用时放行，不影响接口可用性
+func RateLimit(name string, limits func() (limit, windowSeconds int)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, windowSeconds := limits()
+		if limit <= 0 || windowSeconds <= 0 {
+			c.Next()
+			return
+		}
+
+		window := time.Now().Unix() / int64(windowSeconds)
+		key := "rate_limit:" + name + ":" + c.ClientIP() + ":" + strconv.FormatInt(window, 10)
+		count, err := db.RedisIncrExpire(key, windowSeconds)
+		if err != nil {
+			log.Logger.Error(err.Error())
+			c.Next()
+			return
+		}
+
+		if count > int64(limit) {
+			res := response.Gin{Res: c}
+			c.Header("Retry-After", strconv.FormatInt(int64(windowSeconds)-time.Now().Unix()%int64(windowSeconds), 10))
+			res.Response(c, statecode.TooManyRequests, nil, http.StatusTooManyRequests)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
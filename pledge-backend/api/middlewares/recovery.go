@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/response"
+	"pledge-backend/telemetry"
+)
+
+// Recovery 恢复 handler 中的 panic，带请求信息上报到 Sentry 后返回服务器错误
+// 需要在 otelgin 之后注册，上报时附带请求的 trace_id
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := telemetry.RequestContext(c.Request)
+		c.Request = c.Request.WithContext(ctx)
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				telemetry.ReportPanic(ctx, recovered)
+				res := response.Gin{Res: c}
+				res.Error(c, statecode.New(statecode.CommonErrServerErr))
+				c.Abort()
+			}
+		}()
+		c.Next()
+	}
+}
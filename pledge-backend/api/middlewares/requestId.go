@@ -0,0 +1,39 @@
+package middlewares
+
+import (
+	"pledge-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestIdMaxLen 采信的上游 X-Request-Id 最大长度
+const requestIdMaxLen = 64
+
+// RequestId 为每个请求分配 ID，保存在 gin.Context 的 request_id 中，并通过响应头 X-Request-Id 和响应的 meta.request_id 返回
+// 上游 (网关、客户端) 已带合法的 X-Request-Id 时沿用，便于跨服务排查；否则生成新的 ID
+func RequestId() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader("X-Request-Id")
+		if !validRequestId(id) {
+			id = utils.UniqueId()
+		}
+		c.Set("request_id", id)
+		c.Header("X-Request-Id", id)
+		c.Next()
+	}
+}
+
+// validRequestId 只接受字母、数字和 - _ .，避免把任意内容写入日志和响应头
+func validRequestId(id string) bool {
+	if id == "" || len(id) > requestIdMaxLen {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
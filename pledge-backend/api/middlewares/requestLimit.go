@@ -0,0 +1,84 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/response"
+	"pledge-backend/config"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout 为请求的 ctx 设置 [env] request_timeout 截止时间，handler 通过 ctx.Request.Context() 执行的 MySQL、RPC 到期后被取消
+// handler 没有写入响应时返回 408；已通过 response.Gin 返回的错误也改为 408
+// Redis 调用和不使用 ctx 的 handler 不会被中断。WebSocket、SSE 和 pprof 等长连接请求不受限制
+func Timeout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := time.Duration(config.Config().Env.RequestTimeout) * time.Second
+		if timeout <= 0 || longLived(c) {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		if !c.Writer.Written() && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			res := response.Gin{Res: c}
+			res.Response(c, statecode.RequestTimeout, nil)
+		}
+	}
+}
+
+// longLived 长时间保持的请求: WebSocket 升级、SSE 推送、pprof 采样
+// SSE 按路由判断，不看客户端可以随意设置的 Accept 头
+func longLived(c *gin.Context) bool {
+	return c.IsWebsocket() ||
+		strings.HasSuffix(c.FullPath(), "/price/sse") ||
+		strings.Contains(c.FullPath(), "/debug/pprof/")
+}
+
+// BodyLimit 限制请求体大小，超过 [env] max_body_size 时返回 413，multipart 上传使用 max_upload_size
+// 请求体在 handler 之前读入内存，慢速发送的客户端受 http.Server 的 [env] read_timeout 限制
+func BodyLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := config.Config().Env.MaxBodySize
+		if strings.HasPrefix(c.ContentType(), "multipart/") {
+			limit = config.Config().Env.MaxUploadSize
+		}
+		if limit <= 0 || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		res := response.Gin{Res: c}
+		if c.Request.ContentLength > limit {
+			res.Response(c, statecode.RequestTooLarge, nil)
+			c.Abort()
+			return
+		}
+		body, err := ioutil.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+		_ = c.Request.Body.Close()
+		if err != nil {
+			res.Response(c, statecode.ParameterErr, nil)
+			c.Abort()
+			return
+		}
+		if int64(len(body)) > limit {
+			res.Response(c, statecode.RequestTooLarge, nil)
+			c.Abort()
+			return
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"net/http"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/response"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResponseCache 热点读接口的响应缓存 ([cache] ttl)，key 与维护模式的缓存相同，按语言和请求 URI 区分
+//   - 命中: 直接返回缓存的响应和 Last-Modified，meta 替换为当前请求，响应头 X-Cache: hit
+//   - 未命中: 实时查询，状态码 200 的成功 JSON 响应 (见 response.Succeeded) 写入缓存，响应头 X-Cache: miss
+func ResponseCache() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ttl := config.Config().Cache.Ttl
+		if ttl <= 0 || c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+		key := cacheKey(c)
+		if cached := models.NewResponseCache(); cached.Get(key) {
+			if rsp, err := withMeta(c, cached.Body, nil); err == nil {
+				c.Header("X-Cache", "hit")
+				if cached.LastModified != "" {
+					c.Header("Last-Modified", cached.LastModified)
+				}
+				c.Data(http.StatusOK, "application/json; charset=utf-8", rsp)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Header("X-Cache", "miss")
+		w := &teeWriter{ResponseWriter: c.Writer, limit: int(config.Config().Cache.MaxSize)}
+		c.Writer = w
+		c.Next()
+
+		if w.overflow || w.Status() != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+			return
+		}
+		if !response.Succeeded(w.body.Bytes()) {
+			return
+		}
+		cached := models.ResponseCache{Body: w.body.Bytes(), LastModified: w.Header().Get("Last-Modified")}
+		if err := cached.Set(key, int(ttl)); err != nil {
+			log.Logger.Sugar().Warn("response cache err ", c.Request.RequestURI, " ", err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+)
+
+// Admin 管理员账号，password 为 bcrypt 哈希
+type Admin struct {
+	UserId   int    `json:"user_id" gorm:"column:user_id;primaryKey"`
+	Name     string `json:"name" gorm:"column:name;type:varchar(100);not null"`
+	Password string `json:"-" gorm:"column:password;type:varchar(100);not null"`
+}
+
+func NewAdmin() *Admin {
+	return &Admin{}
+}
+
+func (a *Admin) TableName() string {
+	return "admin"
+}
+
+// Save 按 name 新增或更新管理员
+func (a *Admin) Save(admin *Admin) error {
+	exist := Admin{}
+	err := db.Mysql.Table("admin").Where("name=?", admin.Name).First(&exist).Debug().Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return db.Mysql.Table("admin").Create(admin).Debug().Error
+		}
+		return errors.New("record select err " + err.Error())
+	}
+	return db.Mysql.Table("admin").Where("user_id=?", exist.UserId).Update("password", admin.Password).Debug().Error
+}
+
+// GetByName 按用户名查询管理员
+func (a *Admin) GetByName(name string) error {
+	err := db.Mysql.Table("admin").Where("name=?", name).First(a).Debug().Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New("record select err " + err.Error())
+	}
+	return err
+}
+
+// VerifyToken 校验管理员登录的 JWT (authCode)，令牌有效且未登出时返回用户名
+// HTTP 管理接口 (middlewares.CheckToken) 和 WebSocket 私有主题共用
+// 令牌只在 admin 表的账号登录成功后签发 (UserService.Login)
+func (a *Admin) VerifyToken(token string) (string, bool) {
+	username, err := utils.ParseToken(token, config.Config().Jwt.SecretKey)
+	if err != nil || username == "" {
+		return "", false
+	}
+	// 已登出的令牌在 Redis 中没有 login_ok
+	resByteArr, _ := db.RedisGet(username)
+	if string(resByteArr) != `"login_ok"` {
+		return "", false
+	}
+	return username, true
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"pledge-backend/db"
+)
+
+// ChainHealth RPC 节点最近一次健康检查结果，由 schedule 进程的 UpdateChainHealth 写入
+type ChainHealth struct {
+	Id             int    `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId        string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_url,priority:1"`
+	Url            string `json:"url" gorm:"column:url;type:varchar(255);uniqueIndex:uk_chain_url,priority:2"`
+	Primary        bool   `json:"primary" gorm:"column:is_primary"`              // 是否为 [testnet]/[mainnet] net_url，否则为备用节点
+	Healthy        bool   `json:"healthy" gorm:"column:healthy"`                 // 是否健康
+	LatencyMs      int64  `json:"latency_ms" gorm:"column:latency_ms"`           // eth_blockNumber 延迟, ms
+	BlockNumber    uint64 `json:"block_number" gorm:"column:block_number"`       // 节点区块高度
+	ReferenceBlock uint64 `json:"reference_block" gorm:"column:reference_block"` // 参考节点区块高度，参考节点不可用时为 0
+	BlockLag       uint64 `json:"block_lag" gorm:"column:block_lag"`             // 落后参考节点的区块数
+	Error          string `json:"error" gorm:"column:error;type:text"`           // 请求失败或不健康的原因
+	CheckedAt      string `json:"checked_at" gorm:"column:checked_at"`
+}
+
+func NewChainHealth() *ChainHealth {
+	return &ChainHealth{}
+}
+
+func (c *ChainHealth) TableName() string {
+	return "chain_health"
+}
+
+// List 所有节点的检查结果，每条链主节点在前
+func (c *ChainHealth) List(res *[]ChainHealth) error {
+	return db.Mysql.Table("chain_health").Order("chain_id asc, is_primary desc, url asc").Find(res).Debug().Error
+}
@@ -0,0 +1,34 @@
+package models
+
+import "pledge-backend/db"
+
+// ClaimablePool FINISH / LIQUIDATION 池子计算可提取金额需要的 base 和 data 字段
+type ClaimablePool struct {
+	PoolID                 int    `gorm:"column:pool_id"`
+	State                  string `gorm:"column:state"`
+	SettleTime             string `gorm:"column:settle_time"`
+	EndTime                string `gorm:"column:end_time"`
+	MartgageRate           string `gorm:"column:martgage_rate"`
+	LendToken              string `gorm:"column:lend_token"`
+	LendTokenSymbol        string `gorm:"column:lend_token_symbol"`
+	BorrowToken            string `gorm:"column:borrow_token"`
+	BorrowTokenSymbol      string `gorm:"column:borrow_token_symbol"`
+	SpCoin                 string `gorm:"column:sp_coin"`
+	JpCoin                 string `gorm:"column:jp_coin"`
+	SettleAmountLend       string `gorm:"column:settle_amount_lend"`
+	FinishAmountLend       string `gorm:"column:finish_amount_lend"`
+	FinishAmountBorrow     string `gorm:"column:finish_amount_borrow"`
+	LiquidationAmounLend   string `gorm:"column:liquidation_amoun_lend"`
+	LiquidationAmounBorrow string `gorm:"column:liquidation_amoun_borrow"`
+}
+
+// ClaimablePools 查询指定链上所有 FINISH / LIQUIDATION 状态的池子，已归档的池子仍可提取，软删除的池子不返回
+func (p *PoolBases) ClaimablePools(chainId int, res *[]ClaimablePool) error {
+	return db.Mysql.Table("poolbases b").
+		Select("b.pool_id, b.state, b.settle_time, b.end_time, b.martgage_rate, b.lend_token, b.lend_token_symbol, "+
+			"b.borrow_token, b.borrow_token_symbol, b.sp_coin, b.jp_coin, d.settle_amount_lend, d.finish_amount_lend, "+
+			"d.finish_amount_borrow, d.liquidation_amoun_lend, d.liquidation_amoun_borrow").
+		Joins("left join pooldata d on d.chain_id=b.chain_id and d.pool_id=b.pool_id").
+		Where("b.chain_id=? and b.state in ? and b.deleted_at is null", chainId, []string{PoolStateFinish, PoolStateLiquidation}).
+		Order("b.pool_id asc").Find(res).Debug().Error
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"context"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+	"strings"
+)
+
+// email_subscriptions.events 中的事件
+const (
+	SubscriptionEventSettled    = "settled"    // 池子已结算 (进入执行、完成、清算或未成交)
+	SubscriptionEventFinished   = "finished"   // 池子已完成
+	SubscriptionEventLiquidated = "liquidated" // 池子已清算
+	SubscriptionEventClaimable  = "claimable"  // 池子已完成或已清算，且该钱包在池子中有存入
+)
+
+// EmailSubscription 钱包订阅的池子事件邮件通知，邮箱验证后由 schedule 的 NotifySubscribers 发送
+type EmailSubscription struct {
+	Id            int     `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId       string  `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_address_email_pool,priority:1"`
+	Address       string  `json:"address" gorm:"column:address;type:varchar(42);uniqueIndex:uk_chain_address_email_pool,priority:2"` // checksum 地址
+	Email         string  `json:"email" gorm:"column:email;type:varchar(255);uniqueIndex:uk_chain_address_email_pool,priority:3"`
+	PoolId        int     `json:"pool_id" gorm:"column:pool_id;uniqueIndex:uk_chain_address_email_pool,priority:4"`
+	Events        string  `json:"events" gorm:"column:events;type:varchar(64)"`       // 逗号分隔的事件
+	Token         string  `json:"-" gorm:"column:token;type:varchar(64);uniqueIndex"` // 验证和退订链接中的令牌
+	ExpiresAt     int64   `json:"-" gorm:"column:expires_at"`                         // 验证链接过期时间, Unix 秒
+	VerifiedAt    *string `json:"verified_at" gorm:"column:verified_at;index"`
+	VerifiedState string  `json:"-" gorm:"column:verified_state;type:varchar(8)"` // 验证时池子的状态，此前已发生的事件不通知
+	Language      string  `json:"-" gorm:"column:language;type:varchar(16)"`      // 邮件语言标签，订阅时按 Accept-Language 选择
+	CreatedAt     string  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt     string  `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func NewEmailSubscription() *EmailSubscription {
+	return &EmailSubscription{}
+}
+
+func (s *EmailSubscription) TableName() string {
+	return "email_subscriptions"
+}
+
+// EventList 解析 Events
+func (s *EmailSubscription) EventList() []string {
+	if s.Events == "" {
+		return []string{}
+	}
+	return strings.Split(s.Events, ",")
+}
+
+// Get 钱包在池子上使用该邮箱的订阅，没有时返回 gorm.ErrRecordNotFound
+func (s *EmailSubscription) Get(chainId, address, email string, poolId int) error {
+	return db.Mysql.Table("email_subscriptions").
+		Where("chain_id=? and address=? and email=? and pool_id=?", chainId, address, email, poolId).
+		First(s).Debug().Error
+}
+
+// GetByToken 按验证/退订令牌查询，没有时返回 gorm.ErrRecordNotFound
+func (s *EmailSubscription) GetByToken(token string) error {
+	return db.Mysql.Table("email_subscriptions").Where("token=?", token).First(s).Debug().Error
+}
+
+// List 钱包在指定链上的订阅
+func (s *EmailSubscription) List(ctx context.Context, chainId, address string, res *[]EmailSubscription) error {
+	return db.Mysql.WithContext(ctx).Table("email_subscriptions").
+		Where("chain_id=? and address=?", chainId, address).
+		Order("pool_id asc, id asc").Find(res).Debug().Error
+}
+
+// Count 钱包在指定链上的订阅数
+func (s *EmailSubscription) Count(chainId, address string) (int64, error) {
+	var count int64
+	err := db.Mysql.Table("email_subscriptions").Where("chain_id=? and address=?", chainId, address).Count(&count).Debug().Error
+	return count, err
+}
+
+// Create 新增未验证的订阅
+func (s *EmailSubscription) Create() error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	s.CreatedAt = nowDateTime
+	s.UpdatedAt = nowDateTime
+	return db.Mysql.Table("email_subscriptions").Create(s).Debug().Error
+}
+
+// Update 修改订阅的事件和邮件语言，未验证的订阅同时更换令牌和过期时间
+func (s *EmailSubscription) Update() error {
+	s.UpdatedAt = utils.GetCurDateTimeFormat()
+	return db.Mysql.Table("email_subscriptions").Where("id=?", s.Id).Updates(map[string]interface{}{
+		"events":     s.Events,
+		"language":   s.Language,
+		"token":      s.Token,
+		"expires_at": s.ExpiresAt,
+		"updated_at": s.UpdatedAt,
+	}).Debug().Error
+}
+
+// Verify 标记邮箱已验证，state 为当前池子状态
+func (s *EmailSubscription) Verify(state string) error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	s.VerifiedAt = &nowDateTime
+	s.VerifiedState = state
+	s.UpdatedAt = nowDateTime
+	return db.Mysql.Table("email_subscriptions").Where("id=?", s.Id).Updates(map[string]interface{}{
+		"verified_at":    s.VerifiedAt,
+		"verified_state": s.VerifiedState,
+		"updated_at":     s.UpdatedAt,
+	}).Debug().Error
+}
+
+// DeleteByToken 退订，返回删除的行数
+func (s *EmailSubscription) DeleteByToken(token string) (int64, error) {
+	result := db.Mysql.Table("email_subscriptions").Where("token=?", token).Delete(&EmailSubscription{}).Debug()
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"context"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+)
+
+// FeeRevenue 池子完成或清算时协议收取的 lendFee / borrowFee，每个池子一条，由 schedule 的 AccountFeeRevenue 写入
+// 美元价值按记入时的价格折算, 1e8 精度
+type FeeRevenue struct {
+	Id              int    `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId         string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_pool,priority:1;index:idx_chain_accrued,priority:1"`
+	PoolId          int    `json:"pool_id" gorm:"column:pool_id;uniqueIndex:uk_chain_pool,priority:2"`
+	Event           string `json:"event" gorm:"column:event;type:varchar(16)"` // finish / liquidation
+	LendToken       string `json:"lend_token" gorm:"column:lend_token;type:varchar(42)"`
+	BorrowToken     string `json:"borrow_token" gorm:"column:borrow_token;type:varchar(42)"`
+	LendFeeAmount   string `json:"lend_fee_amount" gorm:"column:lend_fee_amount;type:decimal(65,0)"`     // 出借代币最小单位
+	BorrowFeeAmount string `json:"borrow_fee_amount" gorm:"column:borrow_fee_amount;type:decimal(65,0)"` // 抵押代币最小单位
+	LendFeeUsd      string `json:"lend_fee_usd" gorm:"column:lend_fee_usd;type:decimal(65,0)"`
+	BorrowFeeUsd    string `json:"borrow_fee_usd" gorm:"column:borrow_fee_usd;type:decimal(65,0)"`
+	TotalUsd        string `json:"total_usd" gorm:"column:total_usd;type:decimal(65,0)"`
+	AccruedAt       int64  `json:"accrued_at" gorm:"column:accrued_at;index:idx_chain_accrued,priority:2"` // 池子进入完成 / 清算状态的时间, Unix 秒
+	CreatedAt       string `json:"-" gorm:"column:created_at"`
+}
+
+func NewFeeRevenue() *FeeRevenue {
+	return &FeeRevenue{}
+}
+
+func (f *FeeRevenue) TableName() string {
+	return "fee_revenues"
+}
+
+// Between [from, to) 内记入的手续费收入，按时间升序，chainIds 为空时查询所有链
+func (f *FeeRevenue) Between(ctx context.Context, chainIds []int, from, to int64, res *[]FeeRevenue) error {
+	tx := db.Mysql.WithContext(ctx).Table("fee_revenues").Where("accrued_at>=? and accrued_at<?", from, to)
+	if len(chainIds) > 0 {
+		ids := make([]string, 0, len(chainIds))
+		for _, chainId := range chainIds {
+			ids = append(ids, utils.IntToString(chainId))
+		}
+		tx = tx.Where("chain_id in ?", ids)
+	}
+	return tx.Order("accrued_at asc, id asc").Find(res).Debug().Error
+}
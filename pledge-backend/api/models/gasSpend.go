@@ -0,0 +1,69 @@
+package models
+
+import (
+	"errors"
+	"pledge-backend/db"
+)
+
+const (
+	GasSpendSuccess = "success"
+	GasSpendFailed  = "failed"
+)
+
+// GasSpend 后端发送的交易及其 gas 花费，由 schedule 写入
+type GasSpend struct {
+	Id          int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId     string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);index:idx_chain_created,priority:1"`
+	Purpose     string `json:"purpose" gorm:"column:purpose;type:varchar(32)"` // 交易用途，例如 oracle_set_price
+	TxHash      string `json:"tx_hash" gorm:"column:tx_hash;type:varchar(66);uniqueIndex"`
+	FromAddress string `json:"from_address" gorm:"column:from_address;type:varchar(42)"`
+	ToAddress   string `json:"to_address" gorm:"column:to_address;type:varchar(42)"`
+	Nonce       uint64 `json:"nonce" gorm:"column:nonce"`
+	GasLimit    uint64 `json:"gas_limit" gorm:"column:gas_limit"`
+	GasPrice    string `json:"gas_price" gorm:"column:gas_price;type:decimal(65,0)"` // wei
+	GasUsed     uint64 `json:"gas_used" gorm:"column:gas_used"`
+	FeeWei      string `json:"fee_wei" gorm:"column:fee_wei;type:decimal(65,0)"` // gas_used * gas_price
+	BlockNumber uint64 `json:"block_number" gorm:"column:block_number"`
+	Status      string `json:"status" gorm:"column:status;type:varchar(16);index"`
+	CreatedAt   string `json:"created_at" gorm:"column:created_at;index:idx_chain_created,priority:2"`
+	UpdatedAt   string `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// GasSpendSummary 按链、用途汇总的 gas 花费
+type GasSpendSummary struct {
+	ChainId     string `json:"chain_id" gorm:"column:chain_id"`
+	Purpose     string `json:"purpose" gorm:"column:purpose"`
+	TxCount     int64  `json:"tx_count" gorm:"column:tx_count"`
+	FailedCount int64  `json:"failed_count" gorm:"column:failed_count"` // 执行失败但消耗了 gas 的交易数
+	GasUsed     uint64 `json:"gas_used" gorm:"column:gas_used"`
+	FeeWei      string `json:"fee_wei" gorm:"column:fee_wei"`
+}
+
+func NewGasSpend() *GasSpend {
+	return &GasSpend{}
+}
+
+func (g *GasSpend) TableName() string {
+	return "gas_spend"
+}
+
+// Summary 汇总 [start, end) 之间发送并已上链的交易，chainId 为空时汇总所有链
+func (g *GasSpend) Summary(chainId, start, end string, res *[]GasSpendSummary) error {
+	query := db.Mysql.Table("gas_spend").
+		Select("chain_id, purpose, COUNT(*) AS tx_count, SUM(status = ?) AS failed_count, "+
+			"SUM(gas_used) AS gas_used, CAST(SUM(fee_wei) AS CHAR) AS fee_wei", GasSpendFailed).
+		Where("created_at>=? and created_at<? and status in ?", start, end, []string{GasSpendSuccess, GasSpendFailed})
+	if chainId != "" {
+		query = query.Where("chain_id=?", chainId)
+	}
+	err := query.Group("chain_id, purpose").Order("chain_id asc, purpose asc").Scan(res).Debug().Error
+	if err != nil {
+		return errors.New("record select err " + err.Error())
+	}
+	return nil
+}
+
+// GetByTxHash 按交易哈希查询，没有记录时返回 gorm.ErrRecordNotFound
+func (g *GasSpend) GetByTxHash(txHash string, res *GasSpend) error {
+	return db.Mysql.Table("gas_spend").Where("tx_hash=?", txHash).First(res).Debug().Error
+}
@@ -4,8 +4,27 @@ import "pledge-backend/db"
 
 func InitTable() {
 	db.Mysql.AutoMigrate(&MultiSign{})
+	db.Mysql.AutoMigrate(&MultiSignHistory{})
 	db.Mysql.AutoMigrate(&TokenInfo{})
 	db.Mysql.AutoMigrate(&TokenList{})
 	db.Mysql.AutoMigrate(&PoolData{})
 	db.Mysql.AutoMigrate(&PoolBases{})
+	db.Mysql.AutoMigrate(&PriceQuarantine{})
+	db.Mysql.AutoMigrate(&TokenListVersion{})
+	db.Mysql.AutoMigrate(&PoolSnapshot{})
+	db.Mysql.AutoMigrate(&PoolEvent{})
+	db.Mysql.AutoMigrate(&TokenPriceHistory{})
+	db.Mysql.AutoMigrate(&Admin{})
+	db.Mysql.AutoMigrate(&GasSpend{})
+	db.Mysql.AutoMigrate(&ChainHealth{})
+	db.Mysql.AutoMigrate(&JobRun{})
+	db.Mysql.AutoMigrate(&JobRetry{})
+	db.Mysql.AutoMigrate(&PoolArchive{})
+	db.Mysql.AutoMigrate(&PoolMetadata{})
+	db.Mysql.AutoMigrate(&KeeperTx{})
+	db.Mysql.AutoMigrate(&EmailSubscription{})
+	db.Mysql.AutoMigrate(&FeeRevenue{})
+	db.Mysql.AutoMigrate(&ReferralCode{})
+	db.Mysql.AutoMigrate(&ReferralAttribution{})
+	db.Mysql.AutoMigrate(&PriceBackfill{})
 }
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"pledge-backend/db"
+)
+
+// IntegrityReport 链上数据与 MySQL、Redis 最近一次抽查的结果，由 schedule 进程的 VerifyIntegrity 写入 Redis
+type IntegrityReport struct {
+	ChainId       string           `json:"chain_id"`
+	Block         uint64           `json:"block"`          // 读取链上数据的区块
+	SyncBlock     uint64           `json:"sync_block"`     // UpdateAllPoolInfo 最近一次读取的区块
+	PoolsChecked  int              `json:"pools_checked"`  // 本次抽查的池子数
+	PoolsDrifted  int              `json:"pools_drifted"`  // 本次不一致的池子数
+	TokensChecked int              `json:"tokens_checked"` // 本次抽查的代币数
+	TokensDrifted int              `json:"tokens_drifted"` // 本次不一致的代币数
+	Repaired      int              `json:"repaired"`       // 本次修复的池子、代币数
+	Drifts        []IntegrityDrift `json:"drifts"`
+	TotalChecked  int64            `json:"total_checked"` // 累计抽查的池子、代币数
+	TotalDrifted  int64            `json:"total_drifted"` // 累计不一致的池子、代币数
+	CheckedAt     int64            `json:"checked_at"`
+}
+
+// IntegrityDrift 一个不一致的字段
+type IntegrityDrift struct {
+	Kind     string `json:"kind"`  // pool / token
+	Id       string `json:"id"`    // pool_id 或代币地址
+	Store    string `json:"store"` // poolbases / pooldata / token_info / redis
+	Column   string `json:"column"`
+	Stored   string `json:"stored"`
+	OnChain  string `json:"on_chain"`
+	Repaired bool   `json:"repaired"`
+}
+
+func NewIntegrityReport() *IntegrityReport {
+	return &IntegrityReport{}
+}
+
+// Get 读取链上最近一次抽查的结果，还没有执行过时返回 redis.ErrNil
+func (r *IntegrityReport) Get(chainId string) error {
+	data, err := db.RedisGet("integrity:" + chainId)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, r)
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"pledge-backend/db"
+)
+
+// JobRun 定时任务的一次执行记录，由 schedule 进程写入
+type JobRun struct {
+	Id         int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Name       string `json:"name" gorm:"column:name;type:varchar(64);index:idx_name_started,priority:1"`
+	Status     string `json:"status" gorm:"column:status;type:varchar(16)"` // success / failed / timeout
+	Error      string `json:"error" gorm:"column:error;type:text"`
+	Processed  int64  `json:"processed" gorm:"column:processed"` // 处理成功的条目数
+	Failed     int64  `json:"failed" gorm:"column:failed"`       // 处理失败、放入重试队列的条目数
+	DurationMs int64  `json:"duration_ms" gorm:"column:duration_ms"`
+	StartedAt  string `json:"started_at" gorm:"column:started_at;index:idx_name_started,priority:2;index"`
+	FinishedAt string `json:"finished_at" gorm:"column:finished_at"`
+}
+
+func NewJobRun() *JobRun {
+	return &JobRun{}
+}
+
+func (j *JobRun) TableName() string {
+	return "job_runs"
+}
+
+// List 最近的执行记录，name 为空时查询所有任务
+func (j *JobRun) List(name string, limit int, res *[]JobRun) error {
+	query := db.Mysql.Table("job_runs")
+	if name != "" {
+		query = query.Where("name=?", name)
+	}
+	return query.Order("started_at desc, id desc").Limit(limit).Find(res).Debug().Error
+}
+
+// After id 之后写入的执行记录，按 id 升序，用于推送 jobs 主题
+func (j *JobRun) After(id int, limit int, res *[]JobRun) error {
+	return db.Mysql.Table("job_runs").Where("id>?", id).Order("id asc").Limit(limit).Find(res).Debug().Error
+}
+
+// MaxId 最新一条执行记录的 id，没有记录时为 0
+func (j *JobRun) MaxId() (int, error) {
+	var id int
+	err := db.Mysql.Table("job_runs").Select("coalesce(max(id), 0)").Scan(&id).Debug().Error
+	return id, err
+}
+
+// JobRetry 定时任务处理失败、等待重试或已放弃重试 (dead) 的条目，由 schedule 进程写入
+type JobRetry struct {
+	Id          int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Job         string `json:"job" gorm:"column:job;type:varchar(64);uniqueIndex:uk_job_item,priority:1"`
+	ChainId     string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_job_item,priority:2"`
+	Item        string `json:"item" gorm:"column:item;type:varchar(128);uniqueIndex:uk_job_item,priority:3"`  // 条目标识，例如 pool_id
+	Status      string `json:"status" gorm:"column:status;type:varchar(16);index:idx_status_next,priority:1"` // pending / dead
+	Attempts    int    `json:"attempts" gorm:"column:attempts"`
+	LastError   string `json:"last_error" gorm:"column:last_error;type:text"`
+	NextRetryAt string `json:"next_retry_at" gorm:"column:next_retry_at;index:idx_status_next,priority:2"`
+	CreatedAt   string `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt   string `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func NewJobRetry() *JobRetry {
+	return &JobRetry{}
+}
+
+func (j *JobRetry) TableName() string {
+	return "job_retries"
+}
+
+// List 指定状态的条目，最近更新的在前
+func (j *JobRetry) List(status string, res *[]JobRetry) error {
+	return db.Mysql.Table("job_retries").Where("status=?", status).Order("updated_at desc").Find(res).Debug().Error
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json"
+	"pledge-backend/db"
+	"sort"
+)
+
+// JobStats 定时任务的执行统计，由 schedule 进程写入 Redis job_stats:<name>
+type JobStats struct {
+	Name            string `json:"name"`
+	Runs            int64  `json:"runs"`              // 执行次数，不含跳过
+	Failures        int64  `json:"failures"`          // panic 次数
+	Timeouts        int64  `json:"timeouts"`          // 超时次数
+	Skipped         int64  `json:"skipped"`           // 因上一次未结束而跳过的次数
+	LastStatus      string `json:"last_status"`       // success / failed / timeout / skipped
+	LastError       string `json:"last_error"`        // 最近一次失败或超时的原因
+	LastDurationMs  int64  `json:"last_duration_ms"`  // 最近一次执行耗时, ms
+	MaxDurationMs   int64  `json:"max_duration_ms"`   // 最长耗时, ms
+	TotalDurationMs int64  `json:"total_duration_ms"` // 累计耗时, ms
+	LastRunAt       int64  `json:"last_run_at"`       // 最近一次开始执行的时间, Unix 秒
+	LastSuccessAt   int64  `json:"last_success_at"`   // 最近一次成功的时间, Unix 秒
+}
+
+func NewJobStats() *JobStats {
+	return &JobStats{}
+}
+
+// List 所有定时任务的执行统计，按名称排序
+func (j *JobStats) List(res *[]JobStats) error {
+	names, err := db.RedisSmembers("job_stats")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		stats := JobStats{Name: name}
+		statsBytes, err := db.RedisGet("job_stats:" + name)
+		if err == nil && len(statsBytes) > 0 {
+			_ = json.Unmarshal(statsBytes, &stats)
+		}
+		*res = append(*res, stats)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"pledge-backend/db"
+)
+
+// KeeperTx keeper 为池子发送的 settle / finish / liquidate 交易，由 schedule 写入
+type KeeperTx struct {
+	Id          int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId     string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);index:idx_chain_pool_action,priority:1"`
+	PoolId      int    `json:"pool_id" gorm:"column:pool_id;index:idx_chain_pool_action,priority:2"`
+	Action      string `json:"action" gorm:"column:action;type:varchar(16);index:idx_chain_pool_action,priority:3"` // settle / finish / liquidate
+	TxHash      string `json:"tx_hash" gorm:"column:tx_hash;type:varchar(66);index"`
+	FromAddress string `json:"from_address" gorm:"column:from_address;type:varchar(42)"`
+	Nonce       uint64 `json:"nonce" gorm:"column:nonce"`
+	GasLimit    uint64 `json:"gas_limit" gorm:"column:gas_limit"`
+	GasPrice    string `json:"gas_price" gorm:"column:gas_price;type:decimal(65,0)"` // wei
+	GasUsed     uint64 `json:"gas_used" gorm:"column:gas_used"`
+	BlockNumber uint64 `json:"block_number" gorm:"column:block_number"`
+	Status      string `json:"status" gorm:"column:status;type:varchar(16);index"` // pending / success / failed / dropped / dry_run / skipped / error
+	Error       string `json:"error" gorm:"column:error;type:varchar(512)"`
+	CreatedAt   string `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt   string `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func NewKeeperTx() *KeeperTx {
+	return &KeeperTx{}
+}
+
+func (k *KeeperTx) TableName() string {
+	return "keeper_tx"
+}
+
+// List 最近的 keeper 交易，chainId、status 为空时不过滤
+func (k *KeeperTx) List(chainId, status string, poolId, limit int, res *[]KeeperTx) error {
+	query := db.Mysql.Table("keeper_tx")
+	if chainId != "" {
+		query = query.Where("chain_id=?", chainId)
+	}
+	if poolId != 0 {
+		query = query.Where("pool_id=?", poolId)
+	}
+	if status != "" {
+		query = query.Where("status=?", status)
+	}
+	return query.Order("id desc").Limit(limit).Find(res).Debug().Error
+}
@@ -0,0 +1,106 @@
+package kucoin
+
+import (
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"strings"
+	"sync"
+
+	"github.com/Kucoin/kucoin-go-sdk"
+	"github.com/shopspring/decimal"
+)
+
+// DepthTopicPrefix KuCoin 50 档深度频道，每次变化推送买卖各 50 档的完整快照 (最快 100ms 一次)
+const DepthTopicPrefix = "/spotMarket/level2Depth50:"
+
+// BookLevel 盘口的一档
+type BookLevel struct {
+	Price decimal.Decimal
+	Size  decimal.Decimal // 基础币数量，例如 PLGR-USDT 中的 PLGR
+}
+
+// OrderBook 交易对的本地盘口，Bids 按价格从高到低，Asks 按价格从低到高
+type OrderBook struct {
+	Symbol string
+	Bids   []BookLevel
+	Asks   []BookLevel
+	Time   int64 // 交易所推送的毫秒时间戳
+}
+
+// depthModel 深度频道推送的数据
+type depthModel struct {
+	Bids      [][]string `json:"bids"` // [价格, 数量]
+	Asks      [][]string `json:"asks"`
+	Timestamp int64      `json:"timestamp"`
+}
+
+// books 订阅了深度的交易对的最新盘口，key=交易对，value=*OrderBook，整体替换不修改
+var books sync.Map
+
+// DepthSymbols 订阅深度的交易对，未配置时不订阅
+func DepthSymbols() []string {
+	return config.Config().Exchange.DepthSymbols
+}
+
+// DepthEnabled 交易对是否订阅了深度
+func DepthEnabled(symbol string) bool {
+	for _, s := range DepthSymbols() {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// GetOrderBook 交易对的最新盘口，未订阅深度、尚未收到推送或行情连接已断开时返回 false
+func GetOrderBook(symbol string) (*OrderBook, bool) {
+	book, ok := books.Load(symbol)
+	if !ok {
+		return nil, false
+	}
+	return book.(*OrderBook), true
+}
+
+// saveDepth 用深度推送替换交易对的本地盘口
+func saveDepth(msg *kucoin.WebSocketDownstreamMessage) {
+	d := &depthModel{}
+	if err := msg.ReadData(d); err != nil {
+		log.Logger.Sugar().Error("read depth err ", msg.Topic, err)
+		return
+	}
+	symbol := strings.TrimPrefix(msg.Topic, DepthTopicPrefix)
+	books.Store(symbol, &OrderBook{
+		Symbol: symbol,
+		Bids:   bookLevels(d.Bids),
+		Asks:   bookLevels(d.Asks),
+		Time:   d.Timestamp,
+	})
+}
+
+// resetBooks 行情连接断开后清空盘口，避免用过期的深度估算滑点
+func resetBooks() {
+	books.Range(func(key, value interface{}) bool {
+		books.Delete(key)
+		return true
+	})
+}
+
+// bookLevels 解析 [价格, 数量] 列表，跳过格式错误或数量为 0 的档位
+func bookLevels(levels [][]string) []BookLevel {
+	res := make([]BookLevel, 0, len(levels))
+	for _, level := range levels {
+		if len(level) < 2 {
+			continue
+		}
+		price, err := decimal.NewFromString(level[0])
+		if err != nil {
+			continue
+		}
+		size, err := decimal.NewFromString(level[1])
+		if err != nil || !size.IsPositive() {
+			continue
+		}
+		res = append(res, BookLevel{Price: price, Size: size})
+	}
+	return res
+}
@@ -4,31 +4,45 @@
  * ==================================================================================
  *
  * 【核心功能】
- * 该模块通过 WebSocket 实时监听 KuCoin 交易所上 PLGR/USDT 交易对的价格，
+ * 该模块通过 WebSocket 实时监听 KuCoin 交易所上配置的交易对（[exchange] symbols，默认 PLGR-USDT）的价格，
  * 并将最新价格同步到 Redis 缓存和内存变量中，供系统其他模块使用。
  *
  * 【数据流向】
  * KuCoin 交易所 ---(WebSocket)---> GetExchangePrice()
  *     |
- *     +--> Redis 缓存 (plgr_price)     // 持久化存储，服务重启后可恢复
- *     +--> PlgrPrice 全局变量          // 内存快速访问
- *     +--> PlgrPriceChan 通道          // 用于通知 ws.go 广播给前端
+ *     +--> Prices / PlgrPrice 内存变量  // 内存快速访问
+ *     +--> Redis 缓存 (exchange_price:<symbol>，PLGR 额外写 plgr_price) // 持久化存储，服务重启后可恢复
+ *     +--> PriceChan 通道              // 用于通知 ws.go 广播给前端，非阻塞发送，满时丢弃最旧的更新
+ *
+ * 配置了 [exchange] depth_symbols 时在同一连接上订阅 50 档深度，维护内存中的本地盘口 (见 depth.go)，
+ * 供 /price/:symbol/slippage 估算成交滑点
  *
  * 【调用时机】
- * 在 pledge_api.go 的 main() 函数中以 Goroutine 方式启动:
+ * 在 cmd/api.go 的 runApi() 中以 Goroutine 方式启动:
  *     go kucoin.GetExchangePrice()
  *
  * 【依赖关系】
- * - ws.go: 从 PlgrPriceChan 读取价格并广播给前端用户
- * - tokenPriceService.go: 从 Redis 读取价格并写入链上 Oracle 合约
+ * - ws.go: 从 PriceChan 读取价格并按主题广播给前端用户
+ * - tokenPriceService.go: 从 Redis 读取价格，PLGR 写入链上 Oracle 合约，
+ *   其他配置为交易所定价的代币直接使用交易所价格
  * ==================================================================================
  */
 
 package kucoin
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"pledge-backend/config"
 	"pledge-backend/db"
 	"pledge-backend/log"
+	"pledge-backend/telemetry"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/Kucoin/kucoin-go-sdk"
 )
@@ -42,20 +56,130 @@ const ApiKeyVersionV2 = "2"
 // 其他模块可以直接读取这个变量获取最新价格
 var PlgrPrice = "0.0027"
 
-// PlgrPriceChan 价格更新通道
+// PlgrSymbol PLGR 交易对，价格同时写入 PlgrPrice 和 Redis plgr_price，兼容旧逻辑
+const PlgrSymbol = "PLGR-USDT"
+
+// ExchangePrice 交易对的一次价格更新
+type ExchangePrice struct {
+	Symbol string // 交易对，例如 PLGR-USDT
+	Price  string // 最新成交价
+}
+
+// ExchangeTick 一次成交记录，写入 Redis 有序集合 exchange_ticks:<symbol>，用于计算 TWAP/VWAP
+type ExchangeTick struct {
+	Sequence string `json:"sequence"` // 交易所序号，保证集合成员唯一
+	Price    string `json:"price"`    // 成交价
+	Size     string `json:"size"`     // 成交量
+	Time     int64  `json:"time"`     // 毫秒时间戳
+}
+
+// Prices 所有订阅交易对的最新价格（内存缓存），key=交易对，value=价格字符串
+var Prices sync.Map
+
+// PriceChan 价格更新通道
 // 当收到新价格时，会发送到这个通道
-// ws.go 模块会监听这个通道，并将价格广播给所有前端用户
-// 缓冲区大小为 2，防止短暂的消费延迟导致阻塞
-var PlgrPriceChan = make(chan string, 2)
+// ws.go 模块会监听这个通道，并将价格按主题广播给前端用户
+// 通过 publishPrice 非阻塞发送，广播协程卡住时不会拖住行情接收和 Redis 持久化
+var PriceChan = make(chan ExchangePrice, 16)
+
+// droppedPrices PriceChan 写满后被丢弃的价格更新数
+var droppedPrices int64
+
+// DroppedPrices PriceChan 写满后被丢弃的价格更新数，非零说明 ws 广播跟不上行情
+func DroppedPrices() int64 {
+	return atomic.LoadInt64(&droppedPrices)
+}
+
+// publishPrice 非阻塞地发送价格更新，通道已满时丢弃最旧的一条再发送
+// ws.go 每个广播间隔只发送各交易对的最新价格，丢弃旧的更新不影响前端看到的价格
+func publishPrice(price ExchangePrice) {
+	for i := 0; i < 2; i++ {
+		select {
+		case PriceChan <- price:
+			return
+		default:
+		}
+		select {
+		case old := <-PriceChan:
+			if atomic.AddInt64(&droppedPrices, 1)%1000 == 1 {
+				log.Logger.Sugar().Warn("price channel full, dropping oldest update ", old.Symbol)
+			}
+		default:
+		}
+	}
+	atomic.AddInt64(&droppedPrices, 1)
+}
+
+// Symbols 订阅的交易对，未配置时只订阅 PLGR-USDT
+func Symbols() []string {
+	return config.Config().Exchange.Subscribed()
+}
+
+// PriceRedisKey 交易对价格的 Redis key
+func PriceRedisKey(symbol string) string {
+	return "exchange_price:" + symbol
+}
+
+// PriceTimeRedisKey 交易对最近一次价格更新时间（Unix 秒）的 Redis key
+func PriceTimeRedisKey(symbol string) string {
+	return "exchange_price_time:" + symbol
+}
+
+// TicksRedisKey 交易对成交记录的 Redis key
+func TicksRedisKey(symbol string) string {
+	return "exchange_ticks:" + symbol
+}
 
-// GetExchangePrice 主函数：连接 KuCoin 并实时接收 PLGR 价格
+// tradeBufferSeconds 保存成交记录 ([exchange] trade_retention_months) 时 Redis 中至少保留的时长, s
+// schedule 的 PersistExchangeTrades 从这里读取，任务停止超过这个时间的成交会丢失
+const tradeBufferSeconds = 3600
+
+// SaveTick 记录一次成交，并清理窗口之外的旧记录
+// 窗口长度为 [exchange] average_window 的两倍，保存成交记录时至少一小时；两者都未配置时不记录
+func SaveTick(symbol string, t *kucoin.TickerLevel1Model) {
+	keep := 2 * config.Config().Exchange.AverageWindow
+	if config.Config().Exchange.TradeRetentionMonths > 0 && keep < tradeBufferSeconds {
+		keep = tradeBufferSeconds
+	}
+	if keep <= 0 {
+		return
+	}
+	now := time.Now().UnixMilli()
+	tick, err := json.Marshal(ExchangeTick{
+		Sequence: t.Sequence,
+		Price:    t.Price,
+		Size:     t.Size,
+		Time:     now,
+	})
+	if err != nil {
+		return
+	}
+	key := TicksRedisKey(symbol)
+	if err = db.RedisZAdd(key, now, string(tick)); err != nil {
+		log.Logger.Sugar().Error("save exchange tick err ", symbol, err)
+		return
+	}
+	// 多保留一个窗口，计算 TWAP 时需要窗口起点之前的最后一笔成交
+	_ = db.RedisZRemRangeByScore(key, 0, now-keep*1000)
+}
+
+// GetPrice 获取交易对的最新价格
+func GetPrice(symbol string) (string, bool) {
+	price, ok := Prices.Load(symbol)
+	if !ok {
+		return "", false
+	}
+	return price.(string), true
+}
+
+// GetExchangePrice 主函数：连接 KuCoin 并实时接收配置的交易对价格
 //
 // 【执行流程】
 //  1. 从 Redis 读取上次保存的价格（容灾恢复）
 //  2. 创建 KuCoin API 服务实例
 //  3. 获取 WebSocket 公共令牌（无需真实 API Key）
 //  4. 建立 WebSocket 连接
-//  5. 订阅 PLGR-USDT 交易对
+//  5. 订阅配置的交易对
 //  6. 进入死循环，持续接收价格更新
 //
 // 【注意事项】
@@ -79,6 +203,13 @@ func GetExchangePrice() {
 		// 成功读取，覆盖默认值
 		PlgrPrice = price
 	}
+	Prices.Store(PlgrSymbol, PlgrPrice)
+	symbols := Symbols()
+	for _, symbol := range symbols {
+		if price, err := db.RedisGetString(PriceRedisKey(symbol)); err == nil && price != "" {
+			Prices.Store(symbol, price)
+		}
+	}
 
 	// ============================================================
 	// Step 2: 创建 KuCoin API 服务实例
@@ -101,6 +232,7 @@ func GetExchangePrice() {
 	rsp, err := s.WebSocketPublicToken()
 	if err != nil {
 		log.Logger.Error(err.Error())
+		feedDown(err)
 		return
 	}
 
@@ -108,6 +240,7 @@ func GetExchangePrice() {
 	tk := &kucoin.WebSocketTokenModel{}
 	if err := rsp.ReadData(tk); err != nil {
 		log.Logger.Error(err.Error())
+		feedDown(err)
 		return
 	}
 
@@ -123,24 +256,35 @@ func GetExchangePrice() {
 	mc, ec, err := c.Connect()
 	if err != nil {
 		log.Logger.Sugar().Errorf("Error: %s", err.Error())
+		feedDown(err)
 		return
 	}
 
 	// ============================================================
-	// Step 5: 订阅 PLGR-USDT 交易对
+	// Step 5: 订阅配置的交易对
 	// ============================================================
-	// 创建订阅消息：监听 PLGR-USDT 的 Ticker（最新成交价）
+	// 创建订阅消息：监听所有交易对的 Ticker（最新成交价），多个交易对用逗号拼接
 	// 参数 false 表示非私有频道
-	ch := kucoin.NewSubscribeMessage("/market/ticker:PLGR-USDT", false)
+	topic := "/market/ticker:" + strings.Join(symbols, ",")
+	ch := kucoin.NewSubscribeMessage(topic, false)
 	// 预先创建取消订阅消息，用于异常退出时清理
-	uch := kucoin.NewUnsubscribeMessage("/market/ticker:PLGR-USDT", false)
+	uch := kucoin.NewUnsubscribeMessage(topic, false)
 
 	// 发送订阅请求
 	if err := c.Subscribe(ch); err != nil {
 		log.Logger.Error(err.Error())
+		feedDown(err)
 		return
 	}
 
+	// 订阅深度，只用于估算滑点，失败时不影响价格
+	if depthSymbols := DepthSymbols(); len(depthSymbols) > 0 {
+		dch := kucoin.NewSubscribeMessage(DepthTopicPrefix+strings.Join(depthSymbols, ","), false)
+		if err := c.Subscribe(dch); err != nil {
+			log.Logger.Sugar().Error("subscribe depth err ", err)
+		}
+	}
+
 	// ============================================================
 	// Step 6: 主循环 - 持续接收价格更新
 	// ============================================================
@@ -152,6 +296,7 @@ func GetExchangePrice() {
 			// 停止 WebSocket 客户端
 			c.Stop()
 			log.Logger.Sugar().Errorf("Error: %s", err.Error())
+			feedDown(err)
 			// 尝试取消订阅（可能会失败，忽略错误）
 			_ = c.Unsubscribe(uch)
 			// ⚠️ 直接退出函数，不会自动重连！
@@ -160,25 +305,52 @@ func GetExchangePrice() {
 
 		// 情况 B: 收到新的价格消息
 		case msg := <-mc:
+			// 深度推送只更新本地盘口
+			if strings.HasPrefix(msg.Topic, DepthTopicPrefix) {
+				saveDepth(msg)
+				continue
+			}
+
 			// 解析 Ticker 数据
 			// TickerLevel1Model 包含: Price(最新价), BestBid, BestAsk, Size 等
 			t := &kucoin.TickerLevel1Model{}
 			if err := msg.ReadData(t); err != nil {
 				log.Logger.Sugar().Errorf("Failure to read: %s", err.Error())
+				feedDown(err)
 				return
 			}
 
-			// 动作 1: 发送到通道，通知 ws.go 广播给前端
-			// ⚠️ 如果通道满了（没有人读取），这里会阻塞！
-			PlgrPriceChan <- t.Price
+			// 从消息主题中取出交易对，例如 /market/ticker:PLGR-USDT
+			symbol := strings.TrimPrefix(msg.Topic, "/market/ticker:")
 
-			// 动作 2: 更新内存中的全局变量
-			PlgrPrice = t.Price
+			// 动作 1: 更新内存中的价格
+			Prices.Store(symbol, t.Price)
 
-			// 动作 3: 持久化到 Redis
+			// 动作 2: 持久化到 Redis
 			// 参数 0 表示永不过期
 			// 这样即使服务重启，也能从 Redis 恢复最后的价格
-			_ = db.RedisSetString("plgr_price", PlgrPrice, 0)
+			_ = db.RedisSetString(PriceRedisKey(symbol), t.Price, 0)
+			// 记录更新时间，喂价前据此判断行情是否停滞
+			_ = db.RedisSetString(PriceTimeRedisKey(symbol), strconv.FormatInt(time.Now().Unix(), 10), 0)
+
+			// 动作 3: 记录成交，供喂价前计算 TWAP/VWAP
+			SaveTick(symbol, t)
+
+			// PLGR 保持原有的内存变量和 Redis key
+			if symbol == PlgrSymbol {
+				PlgrPrice = t.Price
+				_ = db.RedisSetString("plgr_price", PlgrPrice, 0)
+			}
+
+			// 动作 4: 发送到通道，通知 ws.go 广播给前端
+			// 非阻塞发送，广播协程卡住时丢弃最旧的更新，不影响上面的持久化
+			publishPrice(ExchangePrice{Symbol: symbol, Price: t.Price})
 		}
 	}
 }
+
+// feedDown 行情连接失败或断开，GetExchangePrice 退出后价格不再更新，上报到 Sentry
+func feedDown(err error) {
+	resetBooks()
+	telemetry.CaptureError(context.Background(), errors.New("kucoin feed down: "+err.Error()), map[string]string{"component": "kucoin"})
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+)
+
+// Leaderboard 按 pool_events 统计的存入排行榜
+type Leaderboard struct {
+	Entries   []LeaderboardEntry `json:"entries"`
+	UpdatedAt int64              `json:"updated_at"` // 计算时间, Unix 秒
+}
+
+type LeaderboardEntry struct {
+	Rank     int    `json:"rank"`
+	Address  string `json:"address"`
+	Amount   string `json:"amount,omitempty"`  // 代币最小单位，只有单个池子的排行榜返回
+	ValueUsd string `json:"value_usd"`         // 按代币当前价格折算，保留两位小数
+	Deposits int64  `json:"deposits"`          // 存入次数
+	Pools    int    `json:"pools"`             // 存入过的池子数
+	Holding  string `json:"holding,omitempty"` // 当前持有的 SP (出借) / JP (抵押) 数量，只有已结算池子的排行榜返回
+}
+
+func NewLeaderboard() *Leaderboard {
+	return &Leaderboard{}
+}
+
+// poolEventsReorgKey schedule 回滚分叉事件的时间 (Unix 秒)，见 schedule/models.PoolEventsReorgKey
+const poolEventsReorgKey = "pool_events_reorg:"
+
+// leaderboardCacheKey 按链、池子、方向、时间窗口和数量缓存计算结果
+func leaderboardCacheKey(chainId, poolId int, side, window string, limit int) string {
+	return "stats_leaderboard:" + utils.IntToString(chainId) + ":" + utils.IntToString(poolId) + ":" + side + ":" + window + ":" + utils.IntToString(limit)
+}
+
+// GetCache 读取缓存的计算结果，没有缓存或缓存在事件索引回滚分叉之前计算时返回 false
+func (l *Leaderboard) GetCache(chainId, poolId int, side, window string, limit int) bool {
+	data, err := db.RedisGet(leaderboardCacheKey(chainId, poolId, side, window, limit))
+	if err != nil || json.Unmarshal(data, l) != nil {
+		return false
+	}
+	reorgAt, err := db.RedisGetInt64(poolEventsReorgKey + utils.IntToString(chainId))
+	return err != nil || l.UpdatedAt > reorgAt
+}
+
+// SetCache 缓存计算结果 aliveSeconds 秒
+func (l *Leaderboard) SetCache(chainId, poolId int, side, window string, limit int, aliveSeconds int) error {
+	return db.RedisSet(leaderboardCacheKey(chainId, poolId, side, window, limit), l, aliveSeconds)
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"encoding/json"
+	"pledge-backend/db"
+	"sync/atomic"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	maintenanceKey         = "maintenance"
+	maintenanceCachePrefix = "maintenance_cache:"
+)
+
+// Maintenance 维护模式状态，保存在 Redis maintenance，所有 API 实例共享
+type Maintenance struct {
+	Enabled   bool   `json:"enabled"`
+	Message   string `json:"message"`    // 展示给用户的维护说明
+	Operator  string `json:"operator"`   // 最近一次修改的管理员
+	UpdatedAt int64  `json:"updated_at"` // 最近一次修改时间, Unix 秒
+}
+
+// currentMaintenance 本进程当前的维护状态，请求路径上只读内存，不访问 Redis
+var currentMaintenance atomic.Value
+
+func NewMaintenance() *Maintenance {
+	return &Maintenance{}
+}
+
+// CurrentMaintenance 本进程当前的维护状态
+func CurrentMaintenance() Maintenance {
+	state, _ := currentMaintenance.Load().(Maintenance)
+	return state
+}
+
+// SetCurrentMaintenance 更新本进程的维护状态，返回状态是否变化
+func SetCurrentMaintenance(state Maintenance) bool {
+	changed := CurrentMaintenance() != state
+	currentMaintenance.Store(state)
+	return changed
+}
+
+// Get 读取 Redis 中的维护状态，不存在时为未维护
+func (m *Maintenance) Get() (Maintenance, error) {
+	state := Maintenance{}
+	stateBytes, err := db.RedisGet(maintenanceKey)
+	if err == redis.ErrNil {
+		return state, nil
+	} else if err != nil {
+		return state, err
+	}
+	err = json.Unmarshal(stateBytes, &state)
+	return state, err
+}
+
+// Set 写入维护状态
+func (m *Maintenance) Set(state Maintenance) error {
+	return db.RedisSet(maintenanceKey, state, 0)
+}
+
+// CacheResponse 保存读接口成功的响应，维护期间直接返回，key 为语言和请求 URI
+// 缓存未过期时不覆盖，每个 key 每 aliveSeconds 最多写入一次
+func (m *Maintenance) CacheResponse(key string, body []byte, aliveSeconds int) error {
+	_, err := db.RedisSetNX(maintenanceCachePrefix+key, string(body), aliveSeconds)
+	return err
+}
+
+// CachedResponse 读取读接口缓存的响应，没有缓存时返回 false
+func (m *Maintenance) CachedResponse(key string) ([]byte, bool) {
+	body, err := db.RedisGet(maintenanceCachePrefix + key)
+	if err != nil || len(body) == 0 {
+		return nil, false
+	}
+	return body, true
+}
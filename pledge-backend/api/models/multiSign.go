@@ -1,11 +1,13 @@
 package models
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"gorm.io/gorm"
 	"pledge-backend/api/models/request"
 	"pledge-backend/db"
+	"pledge-backend/utils"
 )
 
 // MultiSign multi-sign signature
@@ -28,34 +30,48 @@ func NewMultiSign() *MultiSign {
 }
 
 // Set Multi-Sign
-func (m *MultiSign) Set(multiSign *request.SetMultiSign) error {
+// 在同一个事务中替换当前配置并写入版本记录 history，版本号为该链最新版本加一
+// 并发请求写入同一版本号时由唯一索引拦截
+func (m *MultiSign) Set(ctx context.Context, multiSign *request.SetMultiSign, history *MultiSignHistory) error {
 
 	MultiSignAccountByteArr, _ := json.Marshal(multiSign.MultiSignAccount)
-	err := db.Mysql.Table("multi_sign").Where("chain_id", multiSign.ChainId).Delete(&m).Debug().Error
-	if err != nil {
-		return errors.New("record select err " + err.Error())
-	}
-	err = db.Mysql.Table("multi_sign").Where("id=?", m.Id).Create(&MultiSign{
-		ChainId:          multiSign.ChainId,
-		SpName:           multiSign.SpName,
-		SpToken:          multiSign.SpToken,
-		JpName:           multiSign.JpName,
-		JpToken:          multiSign.JpToken,
-		SpAddress:        multiSign.SpAddress,
-		JpAddress:        multiSign.JpAddress,
-		SpHash:           multiSign.SpHash,
-		JpHash:           multiSign.JpHash,
-		MultiSignAccount: string(MultiSignAccountByteArr),
-	}).Debug().Error
-	if err != nil {
-		return err
-	}
-	return nil
+	return db.Mysql.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("multi_sign").Where("chain_id", multiSign.ChainId).Delete(&m).Debug().Error
+		if err != nil {
+			return errors.New("record select err " + err.Error())
+		}
+		err = tx.Table("multi_sign").Where("id=?", m.Id).Create(&MultiSign{
+			ChainId:          multiSign.ChainId,
+			SpName:           multiSign.SpName,
+			SpToken:          multiSign.SpToken,
+			JpName:           multiSign.JpName,
+			JpToken:          multiSign.JpToken,
+			SpAddress:        multiSign.SpAddress,
+			JpAddress:        multiSign.JpAddress,
+			SpHash:           multiSign.SpHash,
+			JpHash:           multiSign.JpHash,
+			MultiSignAccount: string(MultiSignAccountByteArr),
+		}).Debug().Error
+		if err != nil {
+			return err
+		}
+
+		var version int
+		err = tx.Table("multi_sign_history").Where("chain_id=?", multiSign.ChainId).
+			Select("coalesce(max(version), 0)").Scan(&version).Debug().Error
+		if err != nil {
+			return err
+		}
+		history.ChainId = multiSign.ChainId
+		history.Version = version + 1
+		history.CreatedAt = utils.GetCurDateTimeFormat()
+		return tx.Table("multi_sign_history").Create(history).Debug().Error
+	})
 }
 
 // Get Multi-Sign
-func (m *MultiSign) Get(chainId int) error {
-	err := db.Mysql.Table("multi_sign").Where("chain_id", chainId).First(&m).Debug().Error
+func (m *MultiSign) Get(ctx context.Context, chainId int) error {
+	err := db.Mysql.WithContext(ctx).Table("multi_sign").Where("chain_id", chainId).First(&m).Debug().Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"context"
+	"pledge-backend/db"
+)
+
+// MultiSignHistory 多签配置的版本记录，每次 SetMultiSign 版本号加一
+type MultiSignHistory struct {
+	Id        int32  `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId   int    `json:"chain_id" gorm:"column:chain_id;uniqueIndex:uk_chain_version"`
+	Version   int    `json:"version" gorm:"column:version;uniqueIndex:uk_chain_version"`
+	Config    string `json:"-" gorm:"column:config;type:text"` // 该版本的多签配置 (JSON)
+	Diff      string `json:"-" gorm:"column:diff;type:text"`   // 与上一版本的差异 (JSON)
+	Operator  string `json:"operator" gorm:"column:operator;type:varchar(64)"`
+	CreatedAt string `json:"created_at" gorm:"column:created_at"`
+}
+
+func NewMultiSignHistory() *MultiSignHistory {
+	return &MultiSignHistory{}
+}
+
+func (m *MultiSignHistory) TableName() string {
+	return "multi_sign_history"
+}
+
+// Latest 查询指定链的最新版本，没有记录时返回 gorm.ErrRecordNotFound
+func (m *MultiSignHistory) Latest(ctx context.Context, chainId int) error {
+	return db.Mysql.WithContext(ctx).Table("multi_sign_history").Where("chain_id=?", chainId).
+		Order("version desc").First(m).Debug().Error
+}
+
+// List 查询指定链的版本历史，新版本在前
+func (m *MultiSignHistory) List(ctx context.Context, chainId, limit int, res *[]MultiSignHistory) error {
+	return db.Mysql.WithContext(ctx).Table("multi_sign_history").Where("chain_id=?", chainId).
+		Order("version desc").Limit(limit).Find(res).Debug().Error
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"encoding/json"
+	"pledge-backend/config"
+	"pledge-backend/db"
+)
+
+// NetworkStatus 链的最新区块、出块时间和 gas 价格建议，由 schedule 进程的 UpdateChainHealth 写入 Redis
+type NetworkStatus struct {
+	ChainId      string   `json:"chain_id"`
+	BlockNumber  uint64   `json:"block_number"`   // 最新区块高度
+	BlockTime    int64    `json:"block_time"`     // 最新区块时间, Unix 秒
+	AvgBlockTime float64  `json:"avg_block_time"` // 最近区块的平均出块时间, s
+	BaseFee      string   `json:"base_fee"`       // 最新区块的 base fee, wei，不支持 EIP-1559 的链为空
+	GasPrice     GasPrice `json:"gas_price"`
+	UpdatedAt    int64    `json:"updated_at"` // 采集时间, Unix 秒
+
+	ReadLag    uint64               `json:"read_lag"`    // [testnet] / [mainnet] read_lag
+	ReadBlocks map[string]ReadBlock `json:"read_blocks"` // 各同步任务最近一次读取链上状态的区块，key 为任务名称
+}
+
+// ReadBlock 同步任务读取链上状态的区块，由 schedule 进程写入 Redis read_blocks:<chainId>
+type ReadBlock struct {
+	Block  uint64 `json:"block"`   // 读取的区块 = latest - read_lag
+	Latest uint64 `json:"latest"`  // 读取时的最新区块
+	ReadAt int64  `json:"read_at"` // 读取时间, Unix 秒
+}
+
+// GasPrice gas 价格建议, wei
+type GasPrice struct {
+	Slow     string `json:"slow"`
+	Standard string `json:"standard"`
+	Fast     string `json:"fast"`
+}
+
+func NewNetworkStatus() *NetworkStatus {
+	return &NetworkStatus{}
+}
+
+// Get 读取链的网络状态和同步任务读取的区块，网络状态在 Redis 中没有时返回 redis.ErrNil
+func (n *NetworkStatus) Get(chainId string) error {
+	data, err := db.RedisGet("network_status:" + chainId)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, n); err != nil {
+		return err
+	}
+
+	n.ReadLag = config.Config().ReadLag(chainId)
+	n.ReadBlocks = map[string]ReadBlock{}
+	blocks, err := db.RedisGetHash("read_blocks:" + chainId)
+	if err != nil {
+		return err
+	}
+	for job, v := range blocks {
+		block := ReadBlock{}
+		if json.Unmarshal([]byte(v), &block) == nil {
+			n.ReadBlocks[job] = block
+		}
+	}
+	return nil
+}
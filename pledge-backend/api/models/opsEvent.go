@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"pledge-backend/db"
+)
+
+// opsEventsKey schedule 写入的运维事件，score 为事件时间 (Unix 毫秒)，保留一小时
+const opsEventsKey = "ops_events"
+
+// OpsEvent 运维事件，由 schedule PublishOpsEvent 写入，推送到 WebSocket 私有主题 ops
+type OpsEvent struct {
+	Kind    string          `json:"kind"` // job / oracle_tx / chain_health / alert
+	ChainId string          `json:"chain_id,omitempty"`
+	Data    json.RawMessage `json:"data"` // 对应的 job_runs / gas_spend / chain_health / alert_history 记录
+	At      int64           `json:"at"`   // 事件时间, Unix 毫秒
+}
+
+func NewOpsEvent() *OpsEvent {
+	return &OpsEvent{}
+}
+
+// Since 事件时间晚于 after (Unix 毫秒) 的事件，按时间排序
+func (e *OpsEvent) Since(after int64, res *[]OpsEvent) error {
+	members, err := db.RedisZRangeByScore(opsEventsKey, after+1, math.MaxInt64)
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		event := OpsEvent{}
+		if json.Unmarshal([]byte(member), &event) == nil {
+			*res = append(*res, event)
+		}
+	}
+	return nil
+}
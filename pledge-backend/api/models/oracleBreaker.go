@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/json"
+	"pledge-backend/db"
+)
+
+// OracleBreaker 喂价熔断器状态，由 schedule 进程写入 Redis oracle_breaker:<symbol>
+type OracleBreaker struct {
+	State       string `json:"state"`        // closed 正常 / open 熔断
+	Reason      string `json:"reason"`       // 熔断原因
+	Failures    int    `json:"failures"`     // 连续 SetPrice 失败次数
+	LastFailure int64  `json:"last_failure"` // 最近一次失败时间, Unix 秒
+	UpdatedAt   int64  `json:"updated_at"`   // 状态更新时间, Unix 秒
+}
+
+func NewOracleBreaker() *OracleBreaker {
+	return &OracleBreaker{}
+}
+
+// OracleWrite 最近一次喂价的写入决策，由 schedule 进程写入 Redis oracle_write:<chainId>:<symbol>
+type OracleWrite struct {
+	Decision     string `json:"decision"` // write 写入 / skip 跳过
+	Reason       string `json:"reason"`
+	Price        int64  `json:"price"`
+	OnChainPrice int64  `json:"on_chain_price"`
+	DeltaBps     int64  `json:"delta_bps"`
+	Skips        int    `json:"skips"` // 连续跳过次数
+	TotalSkips   int64  `json:"total_skips"`
+	LastWriteAt  int64  `json:"last_write_at"`
+	UpdatedAt    int64  `json:"updated_at"`
+	Result       string `json:"result"` // sent / batched / failed / mismatch
+	TxHash       string `json:"tx_hash"`
+	Error        string `json:"error"`
+}
+
+// GetOracleBreaker 读取熔断器状态，不存在时为 closed
+func (o *OracleBreaker) GetOracleBreaker(symbol string) OracleBreaker {
+	state := OracleBreaker{State: "closed"}
+	stateBytes, err := db.RedisGet("oracle_breaker:" + symbol)
+	if err == nil && len(stateBytes) > 0 {
+		_ = json.Unmarshal(stateBytes, &state)
+	}
+	return state
+}
+
+// GetOracleWrite 读取链 chainId 上最近一次的写入决策，还没有喂价时返回 nil
+func (o *OracleBreaker) GetOracleWrite(chainId, symbol string) *OracleWrite {
+	stateBytes, err := db.RedisGet("oracle_write:" + chainId + ":" + symbol)
+	if err != nil || len(stateBytes) == 0 {
+		return nil
+	}
+	state := OracleWrite{}
+	if json.Unmarshal(stateBytes, &state) != nil {
+		return nil
+	}
+	return &state
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"encoding/json"
 	"pledge-backend/api/models/request"
 	"pledge-backend/db"
@@ -24,6 +25,7 @@ type Pool struct {
 	SpCoin                 string   `json:"spCoin"`
 	JpCoin                 string   `json:"jpCoin"`
 	AutoLiquidateThreshold string   `json:"autoLiquidateThreshold"`
+	Archived               bool     `json:"archived"`
 	Pooldata               PoolData `json:"pooldata"`
 }
 
@@ -31,21 +33,24 @@ func NewPool() *Pool {
 	return &Pool{}
 }
 
-func (p *Pool) Pagination(req *request.Search, whereCondition string) (error, int64, []Pool) {
+func (p *Pool) Pagination(ctx context.Context, req *request.Search, query string, args ...interface{}) (error, int64, []Pool) {
 	var total int64
 	pools := []Pool{}
 	poolBase := []models.PoolBase{}
 
-	db.Mysql.Table("poolbases").Where(whereCondition).Count(&total)
+	err := db.Mysql.WithContext(ctx).Table("poolbases").Where(query, args...).Count(&total).Error
+	if err != nil {
+		return err, 0, nil
+	}
 
-	err := db.Mysql.Table("poolbases").Where(whereCondition).Order("pool_id desc").Limit(req.PageSize).Offset((req.Page - 1) * req.PageSize).Find(&poolBase).Debug().Error
+	err = db.Mysql.WithContext(ctx).Table("poolbases").Where(query, args...).Order("pool_id desc").Limit(req.PageSize).Offset((req.Page - 1) * req.PageSize).Find(&poolBase).Debug().Error
 	if err != nil {
 		return err, 0, nil
 	}
 
 	for _, b := range poolBase {
 		poolData := PoolData{}
-		err = db.Mysql.Table("pooldata").Where("chain_id=?", req.ChainID).First(&poolData).Debug().Error
+		err = db.Mysql.WithContext(ctx).Table("pooldata").Where("chain_id=?", req.ChainID).First(&poolData).Debug().Error
 		if err != nil {
 			return err, 0, nil
 		}
@@ -63,11 +68,14 @@ func (p *Pool) Pagination(req *request.Search, whereCondition string) (error, in
 			BorrowSupply:           b.BorrowSupply,
 			MartgageRate:           b.MartgageRate,
 			LendToken:              lendToken.TokenName,
+			LendTokenSymbol:        b.LendTokenSymbol,
 			BorrowToken:            borrowToken.TokenName,
+			BorrowTokenSymbol:      b.BorrowTokenSymbol,
 			State:                  b.State,
 			SpCoin:                 b.SpCoin,
 			JpCoin:                 b.JpCoin,
 			AutoLiquidateThreshold: b.AutoLiquidateThreshold,
+			Archived:               b.ArchivedAt != nil,
 			Pooldata:               poolData,
 		})
 	}
@@ -0,0 +1,21 @@
+package models
+
+// PoolArchive 归档池子最后一次同步的 PoolBase 和 PoolData，由 schedule 进程的 ArchivePools 写入
+type PoolArchive struct {
+	Id         int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId    string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_pool,priority:1"`
+	PoolId     int    `json:"pool_id" gorm:"column:pool_id;uniqueIndex:uk_chain_pool,priority:2"`
+	State      string `json:"state" gorm:"column:state"`
+	EndTime    string `json:"end_time" gorm:"column:end_time"`
+	PoolBase   string `json:"pool_base" gorm:"column:pool_base;type:text"`
+	PoolData   string `json:"pool_data" gorm:"column:pool_data;type:text"`
+	ArchivedAt string `json:"archived_at" gorm:"column:archived_at"`
+}
+
+func NewPoolArchive() *PoolArchive {
+	return &PoolArchive{}
+}
+
+func (a *PoolArchive) TableName() string {
+	return "pool_archives"
+}
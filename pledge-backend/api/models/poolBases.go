@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"encoding/json"
 	"pledge-backend/db"
 )
@@ -22,26 +23,63 @@ type PoolBaseInfo struct {
 	SettleTime             string          `json:"settleTime"`
 	SpCoin                 string          `json:"spCoin"`
 	State                  string          `json:"state"`
+	Archived               bool            `json:"archived"`
+	Name                   string          `json:"name"`        // 管理员维护的展示名称 (pool_metadata)，未设置时为空
+	Description            string          `json:"description"` // 展示说明
+	Tags                   []string        `json:"tags"`        // 风险标签
+	Featured               bool            `json:"featured"`    // 是否推荐展示
 }
 
 type PoolBases struct {
-	Id                     int    `json:"-" gorm:"column:id;primaryKey"`
-	PoolID                 int    `json:"pool_id" gorm:"column:pool_id;"`
-	AutoLiquidateThreshold string `json:"autoLiquidateThreshold" gorm:"column:auto_liquidata_threshold;"`
-	BorrowSupply           string `json:"borrowSupply" gorm:"column:borrow_supply;"`
-	BorrowToken            string `json:"borrowToken" gorm:"column:pool_id;"`
-	BorrowTokenInfo        string `json:"borrowTokenInfo" gorm:"column:borrow_token_info;"`
-	EndTime                string `json:"endTime" gorm:"end_time;"`
-	InterestRate           string `json:"interestRate" gorm:"column:interest_rate;"`
-	JpCoin                 string `json:"jpCoin" gorm:"column:jp_coin;"`
-	LendSupply             string `json:"lendSupply" gorm:"column:lend_supply;"`
-	LendToken              string `json:"lendToken" gorm:"column:lend_token;"`
-	LendTokenInfo          string `json:"lendTokenInfo" gorm:"column:lend_token_info;"`
-	MartgageRate           string `json:"martgageRate" gorm:"column:martgage_rate;"`
-	MaxSupply              string `json:"maxSupply" gorm:"column:max_supply;"`
-	SettleTime             string `json:"settleTime" gorm:"column:settle_time;"`
-	SpCoin                 string `json:"spCoin" gorm:"column:sp_coin;"`
-	State                  string `json:"state" gorm:"column:state;"`
+	Id                     int     `json:"-" gorm:"column:id;primaryKey"`
+	PoolID                 int     `json:"pool_id" gorm:"column:pool_id;"`
+	AutoLiquidateThreshold string  `json:"autoLiquidateThreshold" gorm:"column:auto_liquidate_threshold;"`
+	BorrowSupply           string  `json:"borrowSupply" gorm:"column:borrow_supply;"`
+	BorrowToken            string  `json:"borrowToken" gorm:"column:borrow_token;"`
+	BorrowTokenInfo        string  `json:"borrowTokenInfo" gorm:"column:borrow_token_info;"`
+	EndTime                string  `json:"endTime" gorm:"end_time;"`
+	InterestRate           string  `json:"interestRate" gorm:"column:interest_rate;"`
+	JpCoin                 string  `json:"jpCoin" gorm:"column:jp_coin;"`
+	LendSupply             string  `json:"lendSupply" gorm:"column:lend_supply;"`
+	LendToken              string  `json:"lendToken" gorm:"column:lend_token;"`
+	LendTokenInfo          string  `json:"lendTokenInfo" gorm:"column:lend_token_info;"`
+	MartgageRate           string  `json:"martgageRate" gorm:"column:martgage_rate;"`
+	MaxSupply              string  `json:"maxSupply" gorm:"column:max_supply;"`
+	SettleTime             string  `json:"settleTime" gorm:"column:settle_time;"`
+	SpCoin                 string  `json:"spCoin" gorm:"column:sp_coin;"`
+	State                  string  `json:"state" gorm:"column:state;"`
+	UpdatedAt              string  `json:"-" gorm:"column:updated_at;"`
+	Version                int64   `json:"-" gorm:"column:version;not null;default:0"`
+	DeletedAt              *string `json:"-" gorm:"column:deleted_at;"`
+	ArchivedAt             *string `json:"-" gorm:"column:archived_at;index"`
+}
+
+// 池子列表接口的 archived 参数
+const (
+	ArchivedExclude = "exclude" // 不返回已归档的池子，默认
+	ArchivedInclude = "include" // 同时返回已归档的池子
+	ArchivedOnly    = "only"    // 只返回已归档的池子
+)
+
+// ArchivedCondition 按 archived 参数筛选池子的条件，软删除的池子始终不返回
+func ArchivedCondition(archived string) string {
+	switch archived {
+	case ArchivedInclude:
+		return "deleted_at is null"
+	case ArchivedOnly:
+		return "deleted_at is null and archived_at is not null"
+	}
+	return "deleted_at is null and archived_at is null"
+}
+
+// PoolContracts 池子的 SP / JP 代币以及出借、抵押代币地址
+type PoolContracts struct {
+	PoolId      int    `json:"pool_id" gorm:"column:pool_id"`
+	SpToken     string `json:"sp_token" gorm:"column:sp_coin"`
+	JpToken     string `json:"jp_token" gorm:"column:jp_coin"`
+	LendToken   string `json:"lend_token" gorm:"column:lend_token"`
+	BorrowToken string `json:"borrow_token" gorm:"column:borrow_token"`
+	Archived    bool   `json:"archived" gorm:"column:archived"`
 }
 
 type BorrowTokenInfo struct {
@@ -71,40 +109,84 @@ func (p *PoolBases) TableName() string {
 	return "poolbases"
 }
 
-func (p *PoolBases) PoolBaseInfo(chainId int, res *[]PoolBaseInfoRes) error {
+// PoolBaseInfo 查询指定链的池子，archived 见 ArchivedCondition
+func (p *PoolBases) PoolBaseInfo(ctx context.Context, chainId int, archived string, res *[]PoolBaseInfoRes) error {
 	var poolBases []PoolBases
 
-	err := db.Mysql.Table("poolbases").Where("chain_id=?", chainId).Order("pool_id asc").Find(&poolBases).Debug().Error
+	err := db.Mysql.WithContext(ctx).Table("poolbases").Where("chain_id=?", chainId).Where(ArchivedCondition(archived)).Order("pool_id asc").Find(&poolBases).Debug().Error
+	if err != nil {
+		return err
+	}
+	metadata, err := NewPoolMetadata().Map(ctx, chainId)
 	if err != nil {
 		return err
 	}
 
 	for _, v := range poolBases {
-		borrowTokenInfo := BorrowTokenInfo{}
-		_ = json.Unmarshal([]byte(v.BorrowTokenInfo), &borrowTokenInfo)
-		lendTokenInfo := LendTokenInfo{}
-		_ = json.Unmarshal([]byte(v.LendTokenInfo), &lendTokenInfo)
-		*res = append(*res, PoolBaseInfoRes{
-			Index: v.PoolID - 1,
-			PoolData: PoolBaseInfo{
-				PoolID:                 v.PoolID,
-				AutoLiquidateThreshold: v.AutoLiquidateThreshold,
-				BorrowSupply:           v.BorrowSupply,
-				BorrowToken:            v.BorrowToken,
-				BorrowTokenInfo:        borrowTokenInfo,
-				EndTime:                v.EndTime,
-				InterestRate:           v.InterestRate,
-				JpCoin:                 v.JpCoin,
-				LendSupply:             v.LendSupply,
-				LendToken:              v.LendToken,
-				LendTokenInfo:          lendTokenInfo,
-				MartgageRate:           v.MartgageRate,
-				MaxSupply:              v.MaxSupply,
-				SettleTime:             v.SettleTime,
-				SpCoin:                 v.SpCoin,
-				State:                  v.State,
-			},
-		})
+		*res = append(*res, v.InfoRes(metadata[v.PoolID]))
 	}
 	return nil
 }
+
+// InfoRes poolBaseInfo 接口返回的格式，合并管理员维护的展示信息 meta
+func (p *PoolBases) InfoRes(meta PoolMetadata) PoolBaseInfoRes {
+	borrowTokenInfo := BorrowTokenInfo{}
+	_ = json.Unmarshal([]byte(p.BorrowTokenInfo), &borrowTokenInfo)
+	lendTokenInfo := LendTokenInfo{}
+	_ = json.Unmarshal([]byte(p.LendTokenInfo), &lendTokenInfo)
+	return PoolBaseInfoRes{
+		Index: p.PoolID - 1,
+		PoolData: PoolBaseInfo{
+			PoolID:                 p.PoolID,
+			AutoLiquidateThreshold: p.AutoLiquidateThreshold,
+			BorrowSupply:           p.BorrowSupply,
+			BorrowToken:            p.BorrowToken,
+			BorrowTokenInfo:        borrowTokenInfo,
+			EndTime:                p.EndTime,
+			InterestRate:           p.InterestRate,
+			JpCoin:                 p.JpCoin,
+			LendSupply:             p.LendSupply,
+			LendToken:              p.LendToken,
+			LendTokenInfo:          lendTokenInfo,
+			MartgageRate:           p.MartgageRate,
+			MaxSupply:              p.MaxSupply,
+			SettleTime:             p.SettleTime,
+			SpCoin:                 p.SpCoin,
+			State:                  p.State,
+			Archived:               p.ArchivedAt != nil,
+			Name:                   meta.Name,
+			Description:            meta.Description,
+			Tags:                   meta.TagList(),
+			Featured:               meta.Featured,
+		},
+	}
+}
+
+// ChangedSince since (datetime) 之后修改过的池子，以及 poolIds 中的池子，包含已归档和软删除的池子
+func (p *PoolBases) ChangedSince(ctx context.Context, chainId int, since string, poolIds []int, res *[]PoolBases) error {
+	return db.Mysql.WithContext(ctx).Table("poolbases").
+		Where("chain_id=?", chainId).Where("updated_at>=? or pool_id in ?", since, poolIds).
+		Order("pool_id asc").Find(res).Debug().Error
+}
+
+// List 查询指定链的池子，state 为空时不过滤状态，archived 见 ArchivedCondition
+func (p *PoolBases) List(chainId int, state, archived string, limit int, res *[]PoolBases) error {
+	tx := db.Mysql.Table("poolbases").Where("chain_id=?", chainId).Where(ArchivedCondition(archived))
+	if state != "" {
+		tx = tx.Where("state=?", state)
+	}
+	return tx.Order("pool_id asc").Limit(limit).Find(res).Debug().Error
+}
+
+// Contracts 指定链上未删除池子的代币地址，包含已归档的池子
+func (p *PoolBases) Contracts(ctx context.Context, chainId int, res *[]PoolContracts) error {
+	return db.Mysql.WithContext(ctx).Table("poolbases").
+		Select("pool_id, sp_coin, jp_coin, lend_token, borrow_token, archived_at is not null as archived").
+		Where("chain_id=?", chainId).Where(ArchivedCondition(ArchivedInclude)).
+		Order("pool_id asc").Find(res).Debug().Error
+}
+
+// Get 查询单个池子
+func (p *PoolBases) Get(chainId, poolId int) error {
+	return db.Mysql.Table("poolbases").Where("chain_id=? and pool_id=?", chainId, poolId).First(p).Debug().Error
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"pledge-backend/db"
 )
 
@@ -16,6 +17,7 @@ type PoolData struct {
 	SettleAmountLend       string `json:"settle_amount_lend" gorm:"column:settle_amount_lend"`
 	CreatedAt              string `json:"created_at" gorm:"column:created_at"`
 	UpdatedAt              string `json:"updated_at" gorm:"column:updated_at"`
+	Version                int64  `json:"-" gorm:"column:version;not null;default:0"`
 }
 
 type PoolDataInfoRes struct {
@@ -31,10 +33,10 @@ func (p *PoolData) TableName() string {
 	return "pooldata"
 }
 
-func (p *PoolData) PoolDataInfo(chainId int, res *[]PoolDataInfoRes) error {
+func (p *PoolData) PoolDataInfo(ctx context.Context, chainId int, res *[]PoolDataInfoRes) error {
 	var poolData []PoolData
 
-	err := db.Mysql.Table("pooldata").Where("chain_id=?", chainId).Order("pool_id asc").Find(&poolData).Debug().Error
+	err := db.Mysql.WithContext(ctx).Table("pooldata").Where("chain_id=?", chainId).Order("pool_id asc").Find(&poolData).Debug().Error
 	if err != nil {
 		return err
 	}
@@ -47,3 +49,9 @@ func (p *PoolData) PoolDataInfo(chainId int, res *[]PoolDataInfoRes) error {
 	}
 	return nil
 }
+
+// ChangedSince since (datetime) 之后修改过的 pooldata
+func (p *PoolData) ChangedSince(ctx context.Context, chainId int, since string, res *[]PoolData) error {
+	return db.Mysql.WithContext(ctx).Table("pooldata").Where("chain_id=? and updated_at>=?", chainId, since).
+		Order("pool_id asc").Find(res).Debug().Error
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"pledge-backend/db"
+)
+
+// poolDeadlineEventsKey schedule 写入的倒计时通知，score 为通知时间 (Unix 毫秒)，保留一天
+const poolDeadlineEventsKey = "pool_deadline_events"
+
+// PoolDeadline 池子 settleTime / endTime 的倒计时通知，由 schedule WatchDeadlines 写入
+type PoolDeadline struct {
+	ChainId    string `json:"chain_id"`
+	PoolId     int    `json:"pool_id"`
+	State      string `json:"state"`
+	Kind       string `json:"kind"`        // settle / end
+	Action     string `json:"action"`      // 截止后需要的链上操作: settle / finish
+	Deadline   int64  `json:"deadline"`    // 截止时间, Unix 秒
+	Lead       string `json:"lead"`        // 命中的提前量，例如 1h
+	Remaining  int64  `json:"remaining"`   // 通知时距截止时间的秒数
+	NotifiedAt int64  `json:"notified_at"` // 通知时间, Unix 毫秒
+}
+
+func NewPoolDeadline() *PoolDeadline {
+	return &PoolDeadline{}
+}
+
+// Since 通知时间晚于 after (Unix 毫秒) 的通知，按通知时间排序，chainId 为空时返回所有链
+func (d *PoolDeadline) Since(chainId string, after int64, res *[]PoolDeadline) error {
+	members, err := db.RedisZRangeByScore(poolDeadlineEventsKey, after+1, math.MaxInt64)
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		event := PoolDeadline{}
+		if json.Unmarshal([]byte(member), &event) != nil {
+			continue
+		}
+		if chainId == "" || event.ChainId == chainId {
+			*res = append(*res, event)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"encoding/json"
+	"pledge-backend/db"
+)
+
+// 池子状态，与 PledgePool 合约的 PoolState 一致
+const (
+	PoolStateMatch       = "0"
+	PoolStateExecution   = "1"
+	PoolStateFinish      = "2"
+	PoolStateLiquidation = "3"
+	PoolStateUndone      = "4"
+)
+
+// PoolDetail 单个池子的 base + data 合并记录，以及按当前价格计算的字段
+type PoolDetail struct {
+	PoolBaseInfo
+	ChainId  int      `json:"chain_id"`
+	PoolData PoolData `json:"pool_data"`
+
+	LendTokenPrice        string `json:"lend_token_price"`       // 出借代币当前价格 (1e8 精度)
+	BorrowTokenPrice      string `json:"borrow_token_price"`     // 抵押代币当前价格 (1e8 精度)
+	FillRatio             string `json:"fill_ratio"`             // lendSupply / maxSupply
+	UtilizationRatio      string `json:"utilization_ratio"`      // 可被借出的出借资金占比，结算后为 settleAmountLend / lendSupply
+	LenderApy             string `json:"lender_apy"`             // 出借人的预期年化: interestRate * utilization * (1 - lendFee)
+	CollateralizationRate string `json:"collateralization_rate"` // 当前价格下 抵押品价值 / 借款价值
+	Phase                 string `json:"phase"`                  // match / execution / finish / liquidation / undone
+	TimeToSettle          int64  `json:"time_to_settle"`         // 距结算时间的秒数，已过为 0
+	TimeToEnd             int64  `json:"time_to_end"`            // 距结束时间的秒数，已过为 0
+	PhaseEndsIn           int64  `json:"phase_ends_in"`          // 当前阶段剩余秒数，没有截止时间的阶段为 0
+}
+
+// PoolToken 计算价值用到的代币价格和精度
+type PoolToken struct {
+	Price    string `gorm:"column:price"`
+	Decimals int    `gorm:"column:decimals"`
+}
+
+// PoolDetail 查询单个池子的 base 和 data
+func (p *PoolBases) PoolDetail(chainId, poolId int, res *PoolDetail) error {
+	base := PoolBases{}
+	err := db.Mysql.Table("poolbases").Where("chain_id=? and pool_id=?", chainId, poolId).First(&base).Debug().Error
+	if err != nil {
+		return err
+	}
+	borrowTokenInfo := BorrowTokenInfo{}
+	_ = json.Unmarshal([]byte(base.BorrowTokenInfo), &borrowTokenInfo)
+	lendTokenInfo := LendTokenInfo{}
+	_ = json.Unmarshal([]byte(base.LendTokenInfo), &lendTokenInfo)
+
+	res.ChainId = chainId
+	res.PoolBaseInfo = PoolBaseInfo{
+		PoolID:                 base.PoolID,
+		AutoLiquidateThreshold: base.AutoLiquidateThreshold,
+		BorrowSupply:           base.BorrowSupply,
+		BorrowToken:            base.BorrowToken,
+		BorrowTokenInfo:        borrowTokenInfo,
+		EndTime:                base.EndTime,
+		InterestRate:           base.InterestRate,
+		JpCoin:                 base.JpCoin,
+		LendSupply:             base.LendSupply,
+		LendToken:              base.LendToken,
+		LendTokenInfo:          lendTokenInfo,
+		MartgageRate:           base.MartgageRate,
+		MaxSupply:              base.MaxSupply,
+		SettleTime:             base.SettleTime,
+		SpCoin:                 base.SpCoin,
+		State:                  base.State,
+	}
+
+	// 池子还没有 data 记录时返回空的 pool_data
+	_ = db.Mysql.Table("pooldata").Where("chain_id=? and pool_id=?", chainId, poolId).First(&res.PoolData).Debug().Error
+	return nil
+}
+
+// GetPoolToken 查询代币价格和精度，代币不存在时返回空价格
+func (p *PoolBases) GetPoolToken(chainId int, token string) (error, PoolToken) {
+	poolToken := PoolToken{}
+	err := db.Mysql.Table("token_info").Where("chain_id=? and token=?", chainId, token).Limit(1).Find(&poolToken).Debug().Error
+	return err, poolToken
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"context"
+	"pledge-backend/db"
+)
+
+// pool_events.event
+const (
+	PoolEventDepositLend   = "deposit_lend"
+	PoolEventDepositBorrow = "deposit_borrow"
+)
+
+// PoolEvent PledgePool 合约的存入事件，由 schedule 索引
+type PoolEvent struct {
+	Id          int    `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId     string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_tx_log,priority:1;index:idx_chain_pool_event,priority:1"`
+	PoolId      int    `json:"pool_id" gorm:"column:pool_id;index:idx_chain_pool_event,priority:2"`
+	Event       string `json:"event" gorm:"column:event;type:varchar(32);index:idx_chain_pool_event,priority:3"`
+	Address     string `json:"address" gorm:"column:address;type:varchar(64)"`
+	Token       string `json:"token" gorm:"column:token;type:varchar(64)"`
+	Amount      string `json:"amount" gorm:"column:amount;type:decimal(65,0)"`
+	BlockNumber uint64 `json:"block_number" gorm:"column:block_number"`
+	BlockTime   int64  `json:"block_time" gorm:"column:block_time;index"` // 区块时间, Unix 秒，新增该列之前索引的事件为 0
+	BlockHash   string `json:"block_hash" gorm:"column:block_hash;type:varchar(80)"`
+	TxHash      string `json:"tx_hash" gorm:"column:tx_hash;type:varchar(80);uniqueIndex:uk_chain_tx_log,priority:2"`
+	LogIndex    uint   `json:"log_index" gorm:"column:log_index;uniqueIndex:uk_chain_tx_log,priority:3"`
+	Pending     bool   `json:"pending" gorm:"column:pending;default:false"` // 区块尚未达到 [indexer] confirmations，分叉时可能被删除
+	CreatedAt   string `json:"created_at" gorm:"column:created_at"`
+}
+
+// PoolEventStats 单个池子某类存入事件的汇总
+type PoolEventStats struct {
+	Participants int64  `gorm:"column:participants"`
+	Deposits     int64  `gorm:"column:deposits"`
+	Total        string `gorm:"column:total"`
+}
+
+// PoolPosition 单个地址在池子中的累计存入
+type PoolPosition struct {
+	Address  string `json:"address" gorm:"column:address"`
+	Amount   string `json:"amount" gorm:"column:amount"`
+	Deposits int64  `json:"deposits" gorm:"column:deposits"`
+}
+
+func NewPoolEvent() *PoolEvent {
+	return &PoolEvent{}
+}
+
+func (e *PoolEvent) TableName() string {
+	return "pool_events"
+}
+
+// Stats 去重地址数、存入次数、存入总额
+func (e *PoolEvent) Stats(chainId, poolId int, event string, res *PoolEventStats) error {
+	return db.Mysql.Table("pool_events").
+		Select("count(distinct address) participants, count(*) deposits, cast(coalesce(sum(amount), 0) as char) total").
+		Where("chain_id=? and pool_id=? and event=?", chainId, poolId, event).
+		Scan(res).Debug().Error
+}
+
+// TopPositions 按累计存入金额排序的前 limit 个地址
+func (e *PoolEvent) TopPositions(chainId, poolId int, event string, limit int, res *[]PoolPosition) error {
+	return db.Mysql.Table("pool_events").
+		Select("address, cast(sum(amount) as char) amount, count(*) deposits").
+		Where("chain_id=? and pool_id=? and event=?", chainId, poolId, event).
+		Group("address").Order("sum(amount) desc").Limit(limit).
+		Scan(res).Debug().Error
+}
+
+// PoolDeposit 地址在某个池子中的累计存入
+type PoolDeposit struct {
+	PoolId   int    `gorm:"column:pool_id"`
+	Address  string `gorm:"column:address"`
+	Amount   string `gorm:"column:amount"`
+	Deposits int64  `gorm:"column:deposits"`
+}
+
+// DepositsByAddress 区块时间在 since 之后每个地址在每个池子中的累计存入
+// poolId 为 0 时查询所有池子，since 为 0 时不限时间 (包括没有区块时间的旧事件)
+func (e *PoolEvent) DepositsByAddress(ctx context.Context, chainId, poolId int, event string, since int64, res *[]PoolDeposit) error {
+	tx := db.Mysql.WithContext(ctx).Table("pool_events").
+		Select("pool_id, address, cast(sum(amount) as char) amount, count(*) deposits").
+		Where("chain_id=? and event=?", chainId, event)
+	if poolId != 0 {
+		tx = tx.Where("pool_id=?", poolId)
+	}
+	if since > 0 {
+		tx = tx.Where("block_time>=?", since)
+	}
+	return tx.Group("pool_id, address").Scan(res).Debug().Error
+}
+
+// FirstByTx 交易中的第一个存入事件，没有时返回 gorm.ErrRecordNotFound
+func (e *PoolEvent) FirstByTx(chainId int, txHash string) error {
+	return db.Mysql.Table("pool_events").Where("chain_id=? and tx_hash=?", chainId, txHash).
+		Order("log_index asc").First(e).Debug().Error
+}
+
+// AddressPosition 单个地址在某个池子中的累计存入
+type AddressPosition struct {
+	PoolId        int    `gorm:"column:pool_id"`
+	LendDeposit   string `gorm:"column:lend_deposit"`
+	BorrowDeposit string `gorm:"column:borrow_deposit"`
+	Deposits      int64  `gorm:"column:deposits"`
+}
+
+// List 池子的存入事件，新的在前，event 为空时返回全部类型
+func (e *PoolEvent) List(chainId, poolId int, event string, limit int, res *[]PoolEvent) error {
+	tx := db.Mysql.Table("pool_events").Where("chain_id=? and pool_id=?", chainId, poolId)
+	if event != "" {
+		tx = tx.Where("event=?", event)
+	}
+	return tx.Order("block_number desc, log_index desc").Limit(limit).Find(res).Debug().Error
+}
+
+// Positions 地址在每个池子中的累计出借和抵押存入
+func (e *PoolEvent) Positions(chainId int, address string, res *[]AddressPosition) error {
+	return db.Mysql.Table("pool_events").
+		Select("pool_id, "+
+			"cast(coalesce(sum(case when event=? then amount end), 0) as char) lend_deposit, "+
+			"cast(coalesce(sum(case when event=? then amount end), 0) as char) borrow_deposit, "+
+			"count(*) deposits", PoolEventDepositLend, PoolEventDepositBorrow).
+		Where("chain_id=? and address=?", chainId, address).
+		Group("pool_id").Order("pool_id asc").
+		Scan(res).Debug().Error
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+
+	"gorm.io/gorm/clause"
+)
+
+// PoolMetadata 管理员维护的池子展示信息，合并到 poolBaseInfo 返回，不影响链上数据
+type PoolMetadata struct {
+	Id          int32  `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId     int    `json:"chain_id" gorm:"column:chain_id;uniqueIndex:uk_chain_pool,priority:1"`
+	PoolId      int    `json:"pool_id" gorm:"column:pool_id;uniqueIndex:uk_chain_pool,priority:2"`
+	Name        string `json:"name" gorm:"column:name;type:varchar(64)"`
+	Description string `json:"description" gorm:"column:description;type:text"`
+	Tags        string `json:"-" gorm:"column:tags;type:varchar(512)"` // 风险标签 (JSON 数组)
+	Featured    bool   `json:"featured" gorm:"column:featured"`
+	UpdatedBy   string `json:"updated_by" gorm:"column:updated_by;type:varchar(64)"`
+	CreatedAt   string `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt   string `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func NewPoolMetadata() *PoolMetadata {
+	return &PoolMetadata{}
+}
+
+func (m *PoolMetadata) TableName() string {
+	return "pool_metadata"
+}
+
+// TagList 解析 Tags
+func (m *PoolMetadata) TagList() []string {
+	tags := make([]string, 0)
+	_ = json.Unmarshal([]byte(m.Tags), &tags)
+	return tags
+}
+
+// List 查询指定链的池子展示信息
+func (m *PoolMetadata) List(ctx context.Context, chainId int, res *[]PoolMetadata) error {
+	return db.Mysql.WithContext(ctx).Table("pool_metadata").Where("chain_id=?", chainId).Order("pool_id asc").Find(res).Debug().Error
+}
+
+// ChangedSince since (datetime) 之后修改过的池子展示信息
+func (m *PoolMetadata) ChangedSince(ctx context.Context, chainId int, since string, res *[]PoolMetadata) error {
+	return db.Mysql.WithContext(ctx).Table("pool_metadata").Where("chain_id=? and updated_at>=?", chainId, since).
+		Order("pool_id asc").Find(res).Debug().Error
+}
+
+// Map 查询指定链的池子展示信息，key 为 pool_id
+func (m *PoolMetadata) Map(ctx context.Context, chainId int) (map[int]PoolMetadata, error) {
+	var list []PoolMetadata
+	if err := m.List(ctx, chainId, &list); err != nil {
+		return nil, err
+	}
+	res := make(map[int]PoolMetadata, len(list))
+	for _, v := range list {
+		res[v.PoolId] = v
+	}
+	return res, nil
+}
+
+// Save 新增或覆盖池子展示信息
+func (m *PoolMetadata) Save() error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	m.CreatedAt = nowDateTime
+	m.UpdatedAt = nowDateTime
+	return db.Mysql.Table("pool_metadata").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain_id"}, {Name: "pool_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "description", "tags", "featured", "updated_by", "updated_at"}),
+	}).Create(m).Debug().Error
+}
+
+// Delete 删除池子展示信息，返回删除的行数
+func (m *PoolMetadata) Delete(chainId, poolId int) (int64, error) {
+	result := db.Mysql.Table("pool_metadata").Where("chain_id=? and pool_id=?", chainId, poolId).Delete(&PoolMetadata{}).Debug()
+	return result.RowsAffected, result.Error
+}
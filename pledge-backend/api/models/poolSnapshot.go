@@ -0,0 +1,59 @@
+package models
+
+import (
+	"context"
+	"pledge-backend/db"
+)
+
+// PoolSnapshot 池子数据的历史快照，由 schedule 在池子数据变化时或每小时写入
+type PoolSnapshot struct {
+	Id                 int    `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId            string `json:"-" gorm:"column:chain_id;type:varchar(16);index:idx_chain_pool_time,priority:1"`
+	PoolId             int    `json:"-" gorm:"column:pool_id;index:idx_chain_pool_time,priority:2"`
+	State              string `json:"state" gorm:"column:state"`
+	LendSupply         string `json:"lend_supply" gorm:"column:lend_supply"`
+	BorrowSupply       string `json:"borrow_supply" gorm:"column:borrow_supply"`
+	SettleAmountLend   string `json:"settle_amount_lend" gorm:"column:settle_amount_lend"`
+	SettleAmountBorrow string `json:"settle_amount_borrow" gorm:"column:settle_amount_borrow"`
+	BlockNumber        uint64 `json:"block_number" gorm:"column:block_number"` // 读取数据的区块，同一轮同步的池子相同
+	SnapshotAt         int64  `json:"snapshot_at" gorm:"column:snapshot_at;index:idx_chain_pool_time,priority:3"`
+	CreatedAt          string `json:"-" gorm:"column:created_at"`
+}
+
+func NewPoolSnapshot() *PoolSnapshot {
+	return &PoolSnapshot{}
+}
+
+func (p *PoolSnapshot) TableName() string {
+	return "pool_snapshots"
+}
+
+// History 查询 [from, to] 内最近的 limit 条快照，按时间升序返回，to 为 0 时不限结束时间
+func (p *PoolSnapshot) History(chainId, poolId int, from, to int64, limit int, res *[]PoolSnapshot) error {
+	tx := db.Mysql.Table("pool_snapshots").Where("chain_id=? and pool_id=? and snapshot_at>=?", chainId, poolId, from)
+	if to > 0 {
+		tx = tx.Where("snapshot_at<=?", to)
+	}
+	err := tx.Order("snapshot_at desc").Limit(limit).Find(res).Debug().Error
+	if err != nil {
+		return err
+	}
+	for i, j := 0, len(*res)-1; i < j; i, j = i+1, j-1 {
+		(*res)[i], (*res)[j] = (*res)[j], (*res)[i]
+	}
+	return nil
+}
+
+// LatestBefore 每个池子在 ts 之前的最后一条快照
+func (p *PoolSnapshot) LatestBefore(ctx context.Context, chainId int, ts int64, res *[]PoolSnapshot) error {
+	latest := db.Mysql.Table("pool_snapshots").Select("pool_id, max(snapshot_at)").
+		Where("chain_id=? and snapshot_at<?", chainId, ts).Group("pool_id")
+	return db.Mysql.WithContext(ctx).Table("pool_snapshots").Where("chain_id=? and (pool_id, snapshot_at) in (?)", chainId, latest).
+		Find(res).Debug().Error
+}
+
+// Between [from, to) 内所有池子的快照，按时间升序
+func (p *PoolSnapshot) Between(ctx context.Context, chainId int, from, to int64, res *[]PoolSnapshot) error {
+	return db.Mysql.WithContext(ctx).Table("pool_snapshots").Where("chain_id=? and snapshot_at>=? and snapshot_at<?", chainId, from, to).
+		Order("snapshot_at asc, id asc").Find(res).Debug().Error
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"pledge-backend/db"
+	"strconv"
+	"strings"
+)
+
+// PoolWhitelist 池子的链上权限状态，由 schedule 进程的 SyncPrivileges 写入 Redis
+type PoolWhitelist struct {
+	ChainId   string           `json:"chain_id"`
+	PoolId    int              `json:"pool_id"`
+	Pool      string           `json:"pool"`    // PledgePool 合约地址
+	Paused    bool             `json:"paused"`  // PledgePool.globalPaused
+	SpCoin    DebtTokenMinters `json:"sp_coin"` // 出借凭证的 minter 白名单
+	JpCoin    DebtTokenMinters `json:"jp_coin"` // 借款凭证的 minter 白名单
+	Block     uint64           `json:"block"`   // 读取的区块
+	UpdatedAt int64            `json:"updated_at"`
+}
+
+// DebtTokenMinters DebtToken 的 minter 白名单，地址为小写
+type DebtTokenMinters struct {
+	Address string   `json:"address"`
+	Minters []string `json:"minters"`
+}
+
+func NewPoolWhitelist() *PoolWhitelist {
+	return &PoolWhitelist{}
+}
+
+// Get 读取池子的权限状态，还没有同步或同步已停止超过有效期时返回 redis.ErrNil
+func (p *PoolWhitelist) Get(chainId, poolId int) error {
+	data, err := db.RedisGet("pool_whitelist:" + strconv.Itoa(chainId) + ":" + strconv.Itoa(poolId))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, p)
+}
+
+// IsMinter address 是否在 minter 白名单中
+func (d DebtTokenMinters) IsMinter(address string) bool {
+	for _, minter := range d.Minters {
+		if strings.EqualFold(minter, address) {
+			return true
+		}
+	}
+	return false
+}
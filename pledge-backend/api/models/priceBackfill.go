@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// price_backfills.status
+const (
+	BackfillPending = "pending" // 已提交，等待 schedule 执行
+	BackfillRunning = "running"
+	BackfillDone    = "done"
+	BackfillFailed  = "failed" // 读取价格失败，例如节点不是归档节点，相同 from_block 重新提交后从检查点继续
+)
+
+// PriceBackfill 代币的历史价格回填，管理员提交，由 schedule 的 BackfillPriceHistory 执行
+type PriceBackfill struct {
+	Id        int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId   string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_token,priority:1"`
+	Token     string `json:"token" gorm:"column:token;type:varchar(64);uniqueIndex:uk_chain_token,priority:2"`
+	FromBlock uint64 `json:"from_block" gorm:"column:from_block"`
+	ToBlock   uint64 `json:"to_block" gorm:"column:to_block"`     // 开始执行后确定，pending 时为 0
+	NextBlock uint64 `json:"next_block" gorm:"column:next_block"` // 检查点，下一个读取价格的区块
+	LastPrice string `json:"last_price" gorm:"column:last_price"`
+	Inserted  int64  `json:"inserted" gorm:"column:inserted"` // 已写入 token_price_history 的记录数
+	Status    string `json:"status" gorm:"column:status;type:varchar(16);index"`
+	Error     string `json:"error" gorm:"column:error;type:varchar(512)"`
+	CreatedAt string `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt string `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func NewPriceBackfill() *PriceBackfill {
+	return &PriceBackfill{}
+}
+
+func (m *PriceBackfill) TableName() string {
+	return "price_backfills"
+}
+
+// List 回填列表，chainId 为空时查询所有链
+func (m *PriceBackfill) List(chainId string, res *[]PriceBackfill) error {
+	query := db.Mysql.Table("price_backfills")
+	if chainId != "" {
+		query = query.Where("chain_id=?", chainId)
+	}
+	return query.Order("id desc").Find(res).Debug().Error
+}
+
+// Submit 提交代币的回填: 未完成的回填保持不变；
+// 失败的回填 from_block 相同时从检查点继续，其它情况从 fromBlock 重新开始
+func (m *PriceBackfill) Submit(chainId, token string, fromBlock uint64) error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	err := db.Mysql.Table("price_backfills").Where("chain_id=? and token=?", chainId, token).First(m).Debug().Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		*m = PriceBackfill{
+			ChainId:   chainId,
+			Token:     token,
+			FromBlock: fromBlock,
+			NextBlock: fromBlock,
+			Status:    BackfillPending,
+			CreatedAt: nowDateTime,
+			UpdatedAt: nowDateTime,
+		}
+		return db.Mysql.Table("price_backfills").Create(m).Debug().Error
+	} else if err != nil {
+		return err
+	}
+
+	switch {
+	case m.Status == BackfillPending || m.Status == BackfillRunning:
+		return nil
+	case m.Status == BackfillFailed && m.FromBlock == fromBlock:
+		m.Status = BackfillRunning
+		if m.ToBlock == 0 {
+			m.Status = BackfillPending
+		}
+	default:
+		m.FromBlock, m.ToBlock, m.NextBlock = fromBlock, 0, fromBlock
+		m.LastPrice, m.Inserted = "", 0
+		m.Status = BackfillPending
+	}
+	m.Error, m.UpdatedAt = "", nowDateTime
+	return db.Mysql.Table("price_backfills").Where("id=?", m.Id).Updates(map[string]interface{}{
+		"from_block": m.FromBlock,
+		"to_block":   m.ToBlock,
+		"next_block": m.NextBlock,
+		"last_price": m.LastPrice,
+		"inserted":   m.Inserted,
+		"status":     m.Status,
+		"error":      m.Error,
+		"updated_at": m.UpdatedAt,
+	}).Debug().Error
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+	"strings"
+	"time"
+)
+
+const (
+	QuarantinePending  = "pending"
+	QuarantineApproved = "approved"
+	QuarantineRejected = "rejected"
+)
+
+// PriceQuarantine 被异常检测拦截的价格，由 schedule 写入，管理员审核
+type PriceQuarantine struct {
+	Id        int32  `json:"id" gorm:"column:id;primaryKey"`
+	Token     string `json:"token" gorm:"column:token"`
+	Symbol    string `json:"symbol" gorm:"column:symbol"`
+	ChainId   string `json:"chain_id" gorm:"column:chain_id"`
+	Price     string `json:"price" gorm:"column:price"`
+	LastPrice string `json:"last_price" gorm:"column:last_price"`
+	Reason    string `json:"reason" gorm:"column:reason"`
+	Status    string `json:"status" gorm:"column:status"`
+	CreatedAt string `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt string `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func NewPriceQuarantine() *PriceQuarantine {
+	return &PriceQuarantine{}
+}
+
+func (m *PriceQuarantine) TableName() string {
+	return "price_quarantine"
+}
+
+// List 按状态查询隔离价格
+func (m *PriceQuarantine) List(status string, res *[]PriceQuarantine) error {
+	err := db.Mysql.Table("price_quarantine").Where("status=?", status).Order("id desc").Find(res).Debug().Error
+	if err != nil {
+		return errors.New("record select err " + err.Error())
+	}
+	return nil
+}
+
+// Get 查询一条待审核记录
+func (m *PriceQuarantine) Get(id int32) error {
+	err := db.Mysql.Table("price_quarantine").Where("id=? and status=?", id, QuarantinePending).First(&m).Debug().Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return errors.New("record select err " + err.Error())
+	}
+	return nil
+}
+
+// Approve 审核通过: 价格写入 token_info，清除代币缓存并记入价格历史
+func (m *PriceQuarantine) Approve() error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	err := db.Mysql.Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("token_info").Where("token=? and chain_id=?", m.Token, m.ChainId).Updates(map[string]interface{}{
+			"price":      m.Price,
+			"updated_at": nowDateTime,
+		}).Debug().Error
+		if err != nil {
+			return err
+		}
+		err = tx.Table("token_price_history").Create(&TokenPriceHistory{
+			ChainId:   m.ChainId,
+			Token:     m.Token,
+			Price:     m.Price,
+			PriceAt:   time.Now().Unix(),
+			CreatedAt: nowDateTime,
+		}).Debug().Error
+		if err != nil {
+			return err
+		}
+		return tx.Table("price_quarantine").Where("id=?", m.Id).Updates(map[string]interface{}{
+			"status":     QuarantineApproved,
+			"updated_at": nowDateTime,
+		}).Debug().Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// 删除缓存后 schedule 和池子数据会从 MySQL 重新读取新价格
+	_, _ = db.RedisDelete("token_info:" + m.ChainId + ":" + m.Token)
+	// 与 schedule 的 PriceAnomaly.Record 一致，只保留最近 [anomaly] history_size 条
+	key := "price_history:" + m.ChainId + ":" + strings.ToLower(m.Token)
+	if err = db.RedisListRpush(key, m.Price); err == nil {
+		_ = db.RedisListTrim(key, -config.Config().Anomaly.HistorySize, -1)
+	}
+	return nil
+}
+
+// Reject 审核拒绝，价格不生效
+func (m *PriceQuarantine) Reject() error {
+	return db.Mysql.Table("price_quarantine").Where("id=?", m.Id).Updates(map[string]interface{}{
+		"status":     QuarantineRejected,
+		"updated_at": utils.GetCurDateTimeFormat(),
+	}).Debug().Error
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"context"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+)
+
+// referral_attributions.status
+const (
+	ReferralPending  = "pending"  // 交易尚未被索引，等待 schedule 的 VerifyReferrals 校验
+	ReferralVerified = "verified" // 交易是 pool_events 中的存入
+	ReferralRejected = "rejected" // 存入地址就是推荐人，或超过 [referral] pending_hours 仍未找到交易
+)
+
+// ReferralCode 钱包的推荐码，每个钱包一个，所有链通用
+type ReferralCode struct {
+	Id        int    `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	Address   string `json:"address" gorm:"column:address;type:varchar(42);uniqueIndex"` // checksum 地址
+	Code      string `json:"code" gorm:"column:code;type:varchar(16);uniqueIndex"`
+	CreatedAt string `json:"created_at" gorm:"column:created_at"`
+}
+
+// ReferralAttribution 前端提交的存入交易与推荐码的归属，每笔交易只能归属一次
+type ReferralAttribution struct {
+	Id         int     `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId    string  `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_tx,priority:1;index:idx_chain_referrer,priority:1"`
+	TxHash     string  `json:"tx_hash" gorm:"column:tx_hash;type:varchar(80);uniqueIndex:uk_chain_tx,priority:2"`
+	Code       string  `json:"code" gorm:"column:code;type:varchar(16)"`
+	Referrer   string  `json:"referrer" gorm:"column:referrer;type:varchar(42);index:idx_chain_referrer,priority:2"` // 推荐码所属钱包
+	Referee    string  `json:"referee" gorm:"column:referee;type:varchar(42)"`                                       // 存入地址，校验后填写
+	PoolId     int     `json:"pool_id" gorm:"column:pool_id"`
+	Event      string  `json:"event" gorm:"column:event;type:varchar(32)"` // deposit_lend / deposit_borrow
+	Token      string  `json:"token" gorm:"column:token;type:varchar(64)"`
+	Amount     string  `json:"amount" gorm:"column:amount;type:decimal(65,0)"`
+	BlockTime  int64   `json:"block_time" gorm:"column:block_time"`
+	Status     string  `json:"status" gorm:"column:status;type:varchar(16);index"`
+	Reason     string  `json:"reason" gorm:"column:reason;type:varchar(32)"` // rejected 的原因: self / not_found
+	VerifiedAt *string `json:"verified_at" gorm:"column:verified_at"`
+	CreatedAt  string  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt  string  `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// ReferralTotal 推荐人通过某个被推荐地址、某个代币带来的已校验存入
+type ReferralTotal struct {
+	Referrer  string `gorm:"column:referrer"`
+	Referee   string `gorm:"column:referee"`
+	Token     string `gorm:"column:token"`
+	Amount    string `gorm:"column:amount"`
+	Referrals int64  `gorm:"column:referrals"`
+}
+
+func NewReferralCode() *ReferralCode {
+	return &ReferralCode{}
+}
+
+func (r *ReferralCode) TableName() string {
+	return "referral_codes"
+}
+
+// GetByAddress 钱包的推荐码，没有时返回 gorm.ErrRecordNotFound
+func (r *ReferralCode) GetByAddress(address string) error {
+	return db.Mysql.Table("referral_codes").Where("address=?", address).First(r).Debug().Error
+}
+
+// GetByCode 按推荐码查询，没有时返回 gorm.ErrRecordNotFound
+func (r *ReferralCode) GetByCode(code string) error {
+	return db.Mysql.Table("referral_codes").Where("code=?", code).First(r).Debug().Error
+}
+
+func (r *ReferralCode) Create() error {
+	r.CreatedAt = utils.GetCurDateTimeFormat()
+	return db.Mysql.Table("referral_codes").Create(r).Debug().Error
+}
+
+func NewReferralAttribution() *ReferralAttribution {
+	return &ReferralAttribution{}
+}
+
+func (r *ReferralAttribution) TableName() string {
+	return "referral_attributions"
+}
+
+// Exists 交易是否已归属推荐码
+func (r *ReferralAttribution) Exists(chainId int, txHash string) (bool, error) {
+	var count int64
+	err := db.Mysql.Table("referral_attributions").Where("chain_id=? and tx_hash=?", chainId, txHash).Count(&count).Debug().Error
+	return count > 0, err
+}
+
+func (r *ReferralAttribution) Create() error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	r.CreatedAt = nowDateTime
+	r.UpdatedAt = nowDateTime
+	if r.Status == ReferralVerified {
+		r.VerifiedAt = &nowDateTime
+	}
+	return db.Mysql.Table("referral_attributions").Create(r).Debug().Error
+}
+
+// Count 推荐人处于 status 的归属数
+func (r *ReferralAttribution) Count(chainId int, referrer, status string) (int64, error) {
+	var count int64
+	err := db.Mysql.Table("referral_attributions").Where("chain_id=? and referrer=? and status=?", chainId, referrer, status).
+		Count(&count).Debug().Error
+	return count, err
+}
+
+// VerifiedTotals 区块时间在 since 之后的已校验存入，按推荐人、被推荐地址、代币合计
+// referrer 为空时查询所有推荐人，since 为 0 时不限时间
+func (r *ReferralAttribution) VerifiedTotals(ctx context.Context, chainId int, referrer string, since int64, res *[]ReferralTotal) error {
+	tx := db.Mysql.WithContext(ctx).Table("referral_attributions").
+		Select("referrer, referee, token, cast(sum(amount) as char) amount, count(*) referrals").
+		Where("chain_id=? and status=? and block_time>=?", chainId, ReferralVerified, since)
+	if referrer != "" {
+		tx = tx.Where("referrer=?", referrer)
+	}
+	return tx.Group("referrer, referee, token").Scan(res).Debug().Error
+}
@@ -11,8 +11,14 @@ type SetMultiSign struct {
 	SpHash           string   `json:"spHash"`
 	JpHash           string   `json:"jpHash"`
 	MultiSignAccount []string `json:"multi_sign_account"`
+	Threshold        int      `json:"threshold"` // 多签门限，至少为 1，必须与链上多签合约一致
 }
 
 type GetMultiSign struct {
 	ChainId int `json:"chain_id"`
 }
+
+type MultiSignHistory struct {
+	ChainId int `form:"chainId" binding:"required"`
+	Limit   int `form:"limit"` // 默认 20，最大 100
+}
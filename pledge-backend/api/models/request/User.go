@@ -4,3 +4,12 @@ type Login struct {
 	Name     string `form:"name" binding:"required"`
 	Password string `form:"password" binding:"required"`
 }
+
+type UserClaimable struct {
+	Address string `uri:"address"` // 路径参数，在 query 之后绑定
+	ChainId int    `form:"chainId" binding:"required"`
+}
+
+type WalletNonce struct {
+	Address string `uri:"address"`
+}
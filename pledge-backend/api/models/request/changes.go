@@ -0,0 +1,8 @@
+package request
+
+type Changes struct {
+	ChainId int    `form:"chainId" binding:"required"`
+	Since   string `form:"since"` // Unix 秒、RFC3339 时间或上次返回的 cursor，为空时返回全部数据
+
+	SinceTs int64 `form:"-"`
+}
@@ -0,0 +1,5 @@
+package request
+
+type Contracts struct {
+	ChainId int `form:"chainId" binding:"required"`
+}
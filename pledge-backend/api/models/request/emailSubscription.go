@@ -0,0 +1,19 @@
+package request
+
+// Subscribe 钱包订阅池子事件，同一钱包、邮箱、池子再次提交时覆盖事件
+type Subscribe struct {
+	Address string   `uri:"address"` // 路径参数，在 JSON 之后绑定
+	ChainId int      `json:"chain_id" binding:"required"`
+	PoolId  int      `json:"pool_id" binding:"required"`
+	Email   string   `json:"email" binding:"required,max=255"`
+	Events  []string `json:"events" binding:"required"` // settled / finished / liquidated / claimable
+}
+
+type SubscriptionToken struct {
+	Token string `form:"token" binding:"required"`
+}
+
+type Subscriptions struct {
+	Address string `uri:"address"` // 路径参数，在 query 之后绑定
+	ChainId int    `form:"chainId" binding:"required"`
+}
@@ -0,0 +1,6 @@
+package request
+
+type GasSummary struct {
+	Month   string `form:"month"`   // 2006-01，默认当月
+	ChainId int    `form:"chainId"` // [testnet] / [mainnet] 的链 ID，默认所有链
+}
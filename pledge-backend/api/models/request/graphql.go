@@ -0,0 +1,7 @@
+package request
+
+type Graphql struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
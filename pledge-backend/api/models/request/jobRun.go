@@ -0,0 +1,10 @@
+package request
+
+type JobRuns struct {
+	Name  string `form:"name"`  // 任务名称，默认所有任务
+	Limit int    `form:"limit"` // 默认 50，最多 500
+}
+
+type JobRetries struct {
+	Status string `form:"status"` // pending / dead，默认 dead
+}
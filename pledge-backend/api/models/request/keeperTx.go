@@ -0,0 +1,8 @@
+package request
+
+type KeeperTxs struct {
+	ChainId int    `form:"chainId"` // 97 / 56，默认所有链
+	PoolId  int    `form:"poolId"`  // 默认所有池子
+	Status  string `form:"status"`  // pending / success / failed / dropped / dry_run / skipped / error，默认所有状态
+	Limit   int    `form:"limit"`   // 默认 50，最多 500
+}
@@ -0,0 +1,5 @@
+package request
+
+type LogLevel struct {
+	Level string `json:"level" binding:"required,oneof=debug info warn error"` // 日志级别
+}
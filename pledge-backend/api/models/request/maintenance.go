@@ -0,0 +1,6 @@
+package request
+
+type Maintenance struct {
+	Enabled *bool  `json:"enabled" binding:"required"`          // 开启 / 关闭维护模式
+	Message string `json:"message" binding:"omitempty,max=500"` // 维护说明，返回给前端展示
+}
@@ -0,0 +1,5 @@
+package request
+
+type Network struct {
+	ChainId int `uri:"chainId" binding:"required"`
+}
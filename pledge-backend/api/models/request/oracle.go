@@ -0,0 +1,8 @@
+package request
+
+// OracleSimulateSetPrice 模拟一次 Oracle setPrice 写入
+type OracleSimulateSetPrice struct {
+	ChainId int    `json:"chain_id" binding:"required"`
+	Token   string `json:"token"` // 为空时为该链的 PLGR
+	Price   string `json:"price"` // 待写入的价格, USD；为空时主网取定价路径的交易所最新价格，测试网取固定价格，与 schedule 一致
+}
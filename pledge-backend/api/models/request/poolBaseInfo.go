@@ -1,5 +1,50 @@
 package request
 
 type PoolBaseInfo struct {
-	ChainId int `form:"chainId" binding:"required"`
+	Chains   string `form:"chainId" binding:"required"` // 链 ID，多条链用逗号分隔或 all
+	Archived string `form:"archived"`                   // exclude (默认) / include / only
+
+	ChainIds []int    `form:"-"`
+	Multi    bool     `form:"-"` // chainId 为列表或 all 时按链分组返回
+	Fields   []string `form:"-"` // ?fields= 指定的字段，为空时返回全部字段
+}
+
+type PoolDetail struct {
+	ChainId int `uri:"chainId" binding:"required"`
+	PoolId  int `uri:"poolId" binding:"required"`
+
+	Fields []string `uri:"-"` // ?fields= 指定的字段，为空时返回全部字段
+}
+
+type PoolHistory struct {
+	ChainId int    `uri:"chainId" binding:"required"`
+	PoolId  int    `uri:"poolId" binding:"required"`
+	From    int64  `form:"from"`
+	To      int64  `form:"to"`
+	Limit   int    `form:"limit"`
+	Format  string `form:"format"` // json / csv，默认 json
+}
+
+type PoolEstimate struct {
+	ChainId int    `uri:"chainId"` // 路径参数，在 query 之后绑定
+	PoolId  int    `uri:"poolId"`
+	Amount  string `form:"amount" binding:"required"` // 代币最小单位
+	Side    string `form:"side" binding:"required"`   // lend / borrow
+}
+
+type PoolStats struct {
+	ChainId int `uri:"chainId" binding:"required"`
+	PoolId  int `uri:"poolId" binding:"required"`
+	Top     int `form:"top"` // 返回的最大持仓数，默认 5，最大 20
+}
+
+type PoolWhitelist struct {
+	ChainId int `uri:"chainId" binding:"required"`
+	PoolId  int `uri:"poolId" binding:"required"`
+}
+
+type PoolWhitelistCheck struct {
+	ChainId int    `uri:"chainId"` // 路径参数，在 query 之后绑定
+	PoolId  int    `uri:"poolId"`
+	Address string `form:"address" binding:"required"` // 借款人钱包地址
 }
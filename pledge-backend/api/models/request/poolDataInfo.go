@@ -1,5 +1,9 @@
 package request
 
 type PoolDataInfo struct {
-	ChainId int `form:"chainId" binding:"required"`
+	Chains string `form:"chainId" binding:"required"` // 链 ID，多条链用逗号分隔或 all
+
+	ChainIds []int    `form:"-"`
+	Multi    bool     `form:"-"` // chainId 为列表或 all 时按链分组返回
+	Fields   []string `form:"-"` // ?fields= 指定的字段，为空时返回全部字段
 }
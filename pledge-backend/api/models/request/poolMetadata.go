@@ -0,0 +1,20 @@
+package request
+
+type PoolMetadataList struct {
+	ChainId int `form:"chainId" binding:"required"`
+}
+
+// SavePoolMetadata 整体覆盖池子的展示信息
+type SavePoolMetadata struct {
+	ChainId     int      `json:"chain_id" binding:"required"`
+	PoolId      int      `json:"pool_id" binding:"required"`
+	Name        string   `json:"name" binding:"max=64"`
+	Description string   `json:"description" binding:"max=1000"`
+	Tags        []string `json:"tags"` // 小写字母、数字、中划线，最多 10 个
+	Featured    bool     `json:"featured"`
+}
+
+type DeletePoolMetadata struct {
+	ChainId int `json:"chain_id" binding:"required"`
+	PoolId  int `json:"pool_id" binding:"required"`
+}
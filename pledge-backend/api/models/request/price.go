@@ -0,0 +1,21 @@
+package request
+
+type Prices struct {
+	ChainId int    `form:"chainId" binding:"required"`
+	Tokens  string `form:"tokens" binding:"required"` // 代币地址，逗号分隔，最多 50 个
+
+	Addresses []string `form:"-"` // 校验后去重的 checksum 地址
+}
+
+type Convert struct {
+	ChainId int    `form:"chainId" binding:"required"`
+	From    string `form:"from" binding:"required"`   // 源代币地址
+	To      string `form:"to" binding:"required"`     // 目标代币地址
+	Amount  string `form:"amount" binding:"required"` // 源代币最小单位
+}
+
+type Slippage struct {
+	Symbol string `uri:"symbol"`                     // 路径参数，在 query 之后绑定；交易对，例如 PLGR-USDT，需在 [exchange] depth_symbols 中
+	Amount string `form:"amount" binding:"required"` // 成交数量，基础币单位 (例如 PLGR)，可以是小数
+	Side   string `form:"side"`                      // buy: 吃卖盘 / sell: 吃买盘 (默认，清算卖出抵押物)
+}
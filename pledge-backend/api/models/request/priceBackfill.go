@@ -0,0 +1,12 @@
+package request
+
+type PriceBackfillList struct {
+	ChainId int `form:"chainId"` // 为空时查询所有链
+}
+
+// PriceBackfill 提交代币的历史价格回填
+type PriceBackfill struct {
+	ChainId   int    `json:"chain_id" binding:"required"`
+	Token     string `json:"token" binding:"required"`
+	FromBlock uint64 `json:"from_block" binding:"required"` // 开始读取价格的区块，例如 Oracle 合约部署区块
+}
@@ -0,0 +1,10 @@
+package request
+
+type PriceHistory struct {
+	ChainId int    `form:"chainId" binding:"required"`
+	Token   string `form:"token" binding:"required"`
+	From    int64  `form:"from"`
+	To      int64  `form:"to"`
+	Limit   int    `form:"limit"`
+	Format  string `form:"format"` // json / csv，默认 json
+}
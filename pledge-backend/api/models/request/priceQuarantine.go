@@ -0,0 +1,10 @@
+package request
+
+type PriceQuarantineList struct {
+	Status string `form:"status"` // pending / approved / rejected，默认 pending
+}
+
+type ReviewPriceQuarantine struct {
+	Id     int32  `json:"id" binding:"required"`
+	Action string `json:"action" binding:"required"` // approve / reject
+}
@@ -0,0 +1,28 @@
+package request
+
+type ReferralCode struct {
+	Address string `uri:"address"`
+}
+
+// ReferralAttribute 把存入交易归属到推荐码
+type ReferralAttribute struct {
+	ChainId int    `json:"chain_id" binding:"required"`
+	Code    string `json:"code" binding:"required"`
+	TxHash  string `json:"tx_hash" binding:"required"`
+}
+
+type Referral struct {
+	Address string `uri:"address"` // 路径参数，在 query 之后绑定
+	ChainId int    `form:"chainId" binding:"required"`
+	Window  string `form:"window"` // 时间窗口，数字加单位 h / d，默认 all 不限时间
+
+	Since int64 `form:"-"`
+}
+
+type ReferralStats struct {
+	ChainId int    `form:"chainId" binding:"required"`
+	Window  string `form:"window"` // 时间窗口，数字加单位 h / d，默认 all 不限时间
+	Limit   int    `form:"limit"`  // 默认 20，最多 100
+
+	Since int64 `form:"-"`
+}
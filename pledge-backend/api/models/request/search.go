@@ -1,9 +1,25 @@
 package request
 
 type Search struct {
-	ChainID         int    `form:"chainID" json:"chainID" binding:"required"`
-	LendTokenSymbol string `form:"lend_token_symbol" json:"lend_token_symbol" binding:"omitempty"`
-	State           string `form:"state" json:"state" binding:"omitempty"`
-	Page            int    `form:"page" json:"page" `
-	PageSize        int    `form:"pageSize" json:"pageSize" `
+	ChainID         int      `form:"chainID" json:"chainID" binding:"required"`
+	LendTokenSymbol string   `form:"lend_token_symbol" json:"lend_token_symbol" binding:"omitempty"`
+	State           string   `form:"state" json:"state" binding:"omitempty"`
+	Keyword         string   `form:"keyword" json:"keyword"`                     // 借出/抵押代币的 symbol、name 或地址，前缀/部分匹配，不区分大小写
+	States          []string `form:"states" json:"states"`                       // state IN (...)
+	InterestRateMin string   `form:"interest_rate_min" json:"interest_rate_min"` // 利率区间，与链上 interestRate 精度一致
+	InterestRateMax string   `form:"interest_rate_max" json:"interest_rate_max"`
+	EndTimeFrom     int64    `form:"end_time_from" json:"end_time_from"` // 结束时间区间，Unix 时间戳
+	EndTimeTo       int64    `form:"end_time_to" json:"end_time_to"`
+	Archived        string   `form:"archived" json:"archived"` // exclude (默认) / include / only
+	Page            int      `form:"page" json:"page" `
+	PageSize        int      `form:"pageSize" json:"pageSize" `
+
+	Fields []string `form:"-" json:"-"` // ?fields= 指定的字段，为空时返回全部字段
+}
+
+type TokenSearch struct {
+	ChainId int    `form:"chainId" binding:"required"`
+	Keyword string `form:"keyword" binding:"required"`
+
+	Fields []string `form:"-"` // ?fields= 指定的字段，为空时返回全部字段
 }
@@ -0,0 +1,33 @@
+package request
+
+type Tvl struct {
+	Chains   string `form:"chainId"`  // 链 ID，多条链用逗号分隔，为空或 all 时返回所有链
+	Interval string `form:"interval"` // 1h / 4h / 1d，默认 1d
+	Range    string `form:"range"`    // 时间范围，数字加单位 h / d，例如 24h、90d，默认 30d，最长 365d
+
+	ChainIds        []int `form:"-"` // 为空时返回所有链
+	IntervalSeconds int64 `form:"-"`
+	RangeSeconds    int64 `form:"-"`
+}
+
+type Fees struct {
+	Chains string `form:"chainId"` // 链 ID，多条链用逗号分隔或 all，为空时合计所有链
+	Group  string `form:"group"`   // month / day，默认 month
+	From   string `form:"from"`    // 开始日期 (UTC) 2006-01-02，默认为结束日期前 12 个月或 30 天
+	To     string `form:"to"`      // 结束日期 (UTC)，包含当天，默认今天
+
+	ChainIds []int `form:"-"` // 为空时合计所有链
+	Multi    bool  `form:"-"` // chainId 为列表或 all 时同时返回每条链的合计
+	FromTs   int64 `form:"-"`
+	ToTs     int64 `form:"-"` // 结束日期的下一天 0 点
+}
+
+type Leaderboard struct {
+	ChainId int    `form:"chainId"`
+	PoolId  int    `form:"poolId"` // 为空时为全协议排行榜
+	Side    string `form:"side"`   // lend / borrow，默认 lend
+	Window  string `form:"window"` // 时间窗口，数字加单位 h / d，例如 7d，默认 all 不限时间
+	Limit   int    `form:"limit"`  // 默认 20，最多 100
+
+	Since int64 `form:"-"` // 窗口开始时间, Unix 秒，all 时为 0
+}
@@ -0,0 +1,31 @@
+package request
+
+type CreateToken struct {
+	ChainId     int    `json:"chain_id" binding:"required"`
+	Token       string `json:"token" binding:"required"`
+	Symbol      string `json:"symbol"`
+	Decimals    int    `json:"decimals"`
+	Logo        string `json:"logo"`
+	PriceSource string `json:"price_source"` // 为空自动选择，oracle / exchange / coingecko
+	CoingeckoId string `json:"coingecko_id"`
+}
+
+// UpdateToken 只更新传入的字段
+type UpdateToken struct {
+	Id          int32   `json:"id" binding:"required"`
+	Symbol      *string `json:"symbol"`
+	Decimals    *int    `json:"decimals"`
+	Logo        *string `json:"logo"`
+	PriceSource *string `json:"price_source"`
+	CoingeckoId *string `json:"coingecko_id"`
+}
+
+type DeleteToken struct {
+	Id int32 `json:"id" binding:"required"`
+}
+
+// UploadTokenLogo 图片通过 multipart 字段 logo 上传
+type UploadTokenLogo struct {
+	Address string `uri:"address"` // 路径参数，在表单之后绑定
+	ChainId int    `form:"chainId" binding:"required"`
+}
@@ -3,3 +3,23 @@ package request
 type TokenList struct {
 	ChainId int `form:"chainId" binding:"required"`
 }
+
+// ChainTokenList /token 的参数，chainId 可以是逗号分隔的列表或 all
+type ChainTokenList struct {
+	Chains string `form:"chainId" binding:"required"`
+
+	ChainIds []int    `form:"-"`
+	Multi    bool     `form:"-"` // chainId 为列表或 all 时按链分组返回
+	Fields   []string `form:"-"` // ?fields= 指定的代币字段，为空时返回全部字段
+}
+
+type TokenListChangelog struct {
+	ChainId int `form:"chainId" binding:"required"`
+	Limit   int `form:"limit"` // 默认 20，最大 100
+}
+
+// TokenLogoUrl 代币当前 logo 的内容哈希地址
+type TokenLogoUrl struct {
+	ChainId int    `form:"chainId" binding:"required"`
+	Address string `form:"address" binding:"required"`
+}
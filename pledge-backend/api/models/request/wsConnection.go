@@ -0,0 +1,35 @@
+package request
+
+import "encoding/json"
+
+type CloseWsConnection struct {
+	Id string `json:"id" binding:"required"`
+}
+
+type WsPrice struct {
+	Encoding string `form:"encoding"` // json (默认) / msgpack，协商了子协议时以子协议为准
+	AuthCode string `form:"authCode"` // jobs / ops 的凭证，也可以放在请求头 authCode / X-Api-Key 中
+	ApiKey   string `form:"apiKey"`
+	Topic    string `form:"topic"`    // 连接时订阅的主题，多个用逗号分隔，默认 price
+	LastSeq  int64  `form:"last_seq"` // 断线重连时最后收到的消息序号，补发之后遗漏的消息
+
+	Address   string `form:"address"`   // claimable 主题的钱包地址
+	Signature string `form:"signature"` // 钱包对 /user/{address}/nonce 返回的 message 的签名 (personal_sign)
+
+	Authorized bool   `form:"-"` // 提供了有效的 authCode / API key
+	Wallet     string `form:"-"` // 签名校验通过的钱包地址 (小写)
+}
+
+type PriceSse struct {
+	Topic       string `form:"topic"`         // 订阅主题，多个用逗号分隔，默认 price
+	LastEventId int64  `form:"last_event_id"` // 断线重连序号，优先使用请求头 Last-Event-ID
+}
+
+// WsRpcParams WebSocket RPC 请求的 params，各方法使用其中的部分字段
+type WsRpcParams struct {
+	ChainId  int             `json:"chainId"`
+	PoolId   int             `json:"poolId"`   // getPoolDetail
+	Archived string          `json:"archived"` // getPoolBaseInfo，同 /poolBaseInfo
+	Since    json.RawMessage `json:"since"`    // getChanges，Unix 秒 (数字或字符串)、RFC3339 时间或上次返回的 cursor
+	Fields   []string        `json:"fields"`   // 只返回这些字段，同 ?fields=
+}
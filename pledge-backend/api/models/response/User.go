@@ -3,3 +3,32 @@ package response
 type Login struct {
 	TokenId string `json:"token_id"`
 }
+
+// WalletNonce 钱包证明地址所有权的 nonce，钱包对 message 签名 (personal_sign) 后在 WebSocket 中提供
+type WalletNonce struct {
+	Address   string `json:"address"`
+	Nonce     string `json:"nonce"`
+	Message   string `json:"message"`
+	ExpiresIn int    `json:"expires_in"` // 有效期 (秒)，只能使用一次
+}
+
+// Claimable 钱包在 FINISH / LIQUIDATION 池子中可提取的金额，均为代币最小单位
+type Claimable struct {
+	Address string          `json:"address"`
+	Pools   []ClaimablePool `json:"pools"`
+}
+
+type ClaimablePool struct {
+	PoolId            int    `json:"pool_id"`
+	State             string `json:"state"`
+	SpCoin            string `json:"sp_coin"`
+	SpBalance         string `json:"sp_balance"`
+	LendToken         string `json:"lend_token"`
+	LendTokenSymbol   string `json:"lend_token_symbol"`
+	LendClaimable     string `json:"lend_claimable"` // withdrawLend 可取回的出借代币
+	JpCoin            string `json:"jp_coin"`
+	JpBalance         string `json:"jp_balance"`
+	BorrowToken       string `json:"borrow_token"`
+	BorrowTokenSymbol string `json:"borrow_token_symbol"`
+	BorrowClaimable   string `json:"borrow_claimable"` // withdrawBorrow 可取回的抵押代币
+}
@@ -0,0 +1,15 @@
+package response
+
+import "pledge-backend/api/models"
+
+// ChainsHealth 各链 RPC 节点的健康状态
+type ChainsHealth struct {
+	Chains []ChainHealth `json:"chains"`
+}
+
+// ChainHealth 单条链的节点，healthy 为至少有一个健康节点
+type ChainHealth struct {
+	ChainId   string               `json:"chain_id"`
+	Healthy   bool                 `json:"healthy"`
+	Endpoints []models.ChainHealth `json:"endpoints"`
+}
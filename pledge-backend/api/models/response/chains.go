@@ -0,0 +1,7 @@
+package response
+
+// ChainData chainId 为逗号分隔的列表或 all 时，按链分组返回的结果，data 与单条链时的返回相同
+type ChainData struct {
+	ChainId int         `json:"chain_id"`
+	Data    interface{} `json:"data"`
+}
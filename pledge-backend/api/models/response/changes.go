@@ -0,0 +1,21 @@
+package response
+
+import "pledge-backend/api/models"
+
+type Changes struct {
+	ChainId       int                      `json:"chain_id"`
+	Since         int64                    `json:"since"`
+	Cursor        string                   `json:"cursor"`         // 下次请求的 since，为本次查询开始时的服务器时间
+	Pools         []models.PoolBaseInfoRes `json:"pools"`          // 基础信息或展示信息有变化的池子，格式同 poolBaseInfo，包含已归档的池子
+	PoolData      []models.PoolDataInfoRes `json:"pool_data"`      // 有变化的池子动态数据，格式同 poolDataInfo
+	RemovedPools  []int                    `json:"removed_pools"`  // 已从链上移除的池子
+	Tokens        []Token                  `json:"tokens"`         // 新增或修改的代币，格式同 token list
+	RemovedTokens []string                 `json:"removed_tokens"` // 已删除的代币地址
+	Prices        []ChangedPrice           `json:"prices"`         // 价格有变化的代币的最新价格
+}
+
+type ChangedPrice struct {
+	Token   string `json:"token"`
+	Price   string `json:"price"` // 1e8 精度
+	PriceAt int64  `json:"price_at"`
+}
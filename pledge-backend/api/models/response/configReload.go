@@ -0,0 +1,5 @@
+package response
+
+type ConfigReload struct {
+	Changed []string `json:"changed"` // 发生变化并已生效的配置项
+}
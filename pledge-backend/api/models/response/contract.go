@@ -0,0 +1,13 @@
+package response
+
+import "pledge-backend/api/models"
+
+// Contracts 链上的合约地址，均为 checksum 地址，未配置的合约为空字符串
+type Contracts struct {
+	ChainId    int                    `json:"chain_id"`
+	PledgePool string                 `json:"pledge_pool"` // PledgePool 合约
+	Oracle     string                 `json:"oracle"`      // BscPledgeOracle 合约
+	Plgr       string                 `json:"plgr"`        // PLGR 代币
+	MultiSign  string                 `json:"multi_sign"`  // 多签合约
+	Pools      []models.PoolContracts `json:"pools"`
+}
@@ -0,0 +1,12 @@
+package response
+
+// EmailSubscription 邮箱只返回打码后的地址，例如 a***@example.com
+type EmailSubscription struct {
+	ChainId   int      `json:"chain_id"`
+	Address   string   `json:"address"`
+	PoolId    int      `json:"pool_id"`
+	Email     string   `json:"email"`
+	Events    []string `json:"events"`
+	Verified  bool     `json:"verified"`
+	CreatedAt string   `json:"created_at"`
+}
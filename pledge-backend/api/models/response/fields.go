@@ -0,0 +1,95 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// fieldSet 请求的字段，值为 nil 时返回整个字段，否则只返回其中的子字段
+type fieldSet map[string]fieldSet
+
+// SelectFields 只保留 fields 中的字段 (JSON:API sparse fieldsets)，fields 为空时原样返回
+//
+// data 为一条记录或记录数组，数组中的每条记录分别筛选；字段名为 JSON 字段名，嵌套字段用 . 连接，
+// 例如 pool_data.state；不存在的字段忽略
+func SelectFields(data interface{}, fields []string) interface{} {
+	if len(fields) == 0 {
+		return data
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	// 保留数字原样，避免大整数丢失精度
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err = decoder.Decode(&v); err != nil {
+		return data
+	}
+	return selectFields(v, newFieldSet(fields))
+}
+
+// NestedFields 记录不在 data 顶层时 (例如 rows、tokens)，为 fields 加上记录所在的字段名 name，
+// 并始终返回顶层的 keep 字段；fields 为空时返回 nil
+func NestedFields(fields []string, name string, keep ...string) []string {
+	if len(fields) == 0 {
+		return nil
+	}
+	res := make([]string, 0, len(fields)+len(keep))
+	for _, field := range fields {
+		res = append(res, name+"."+field)
+	}
+	return append(res, keep...)
+}
+
+func newFieldSet(fields []string) fieldSet {
+	set := fieldSet{}
+	for _, field := range fields {
+		node := set
+		parts := strings.Split(field, ".")
+		for i, part := range parts {
+			if i == len(parts)-1 {
+				node[part] = nil
+				break
+			}
+			child, ok := node[part]
+			if ok && child == nil {
+				// 已选中整个字段
+				break
+			}
+			if !ok {
+				child = fieldSet{}
+				node[part] = child
+			}
+			node = child
+		}
+	}
+	return set
+}
+
+func selectFields(v interface{}, set fieldSet) interface{} {
+	switch value := v.(type) {
+	case []interface{}:
+		for i := range value {
+			value[i] = selectFields(value[i], set)
+		}
+		return value
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(set))
+		for name, sub := range set {
+			field, ok := value[name]
+			if !ok {
+				continue
+			}
+			if sub == nil {
+				res[name] = field
+			} else {
+				res[name] = selectFields(field, sub)
+			}
+		}
+		return res
+	}
+	return v
+}
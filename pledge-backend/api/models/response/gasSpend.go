@@ -0,0 +1,24 @@
+package response
+
+// GasSummary 月度 gas 花费
+type GasSummary struct {
+	Month  string            `json:"month"`
+	Chains []GasSummaryChain `json:"chains"`
+}
+
+// GasSummaryChain 单条链的月度花费，金额单位 BNB
+type GasSummaryChain struct {
+	ChainId  string              `json:"chain_id"`
+	Budget   string              `json:"budget"`   // [gas] 月度预算，"0" 表示未设置
+	FeeBnb   string              `json:"fee_bnb"`  // 当月花费
+	TxCount  int64               `json:"tx_count"` // 当月上链的交易数
+	Purposes []GasSummaryPurpose `json:"purposes"` // 按用途统计
+}
+
+type GasSummaryPurpose struct {
+	Purpose     string `json:"purpose"`
+	TxCount     int64  `json:"tx_count"`
+	FailedCount int64  `json:"failed_count"`
+	GasUsed     uint64 `json:"gas_used"`
+	FeeBnb      string `json:"fee_bnb"`
+}
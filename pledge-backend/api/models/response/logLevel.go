@@ -0,0 +1,5 @@
+package response
+
+type LogLevel struct {
+	Level string `json:"level"` // 当前日志级别
+}
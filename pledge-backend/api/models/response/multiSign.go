@@ -11,4 +11,22 @@ type MultiSign struct {
 	SpHash           string   `json:"spHash"`
 	JpHash           string   `json:"jpHash"`
 	MultiSignAccount []string `json:"multi_sign_account"`
+	Threshold        int      `json:"threshold"`
+	Version          int      `json:"version"` // 当前配置的版本号，从未设置时为 0
+}
+
+// MultiSignVersion 多签配置的一个版本
+type MultiSignVersion struct {
+	Version   int               `json:"version"`
+	Operator  string            `json:"operator"`
+	CreatedAt string            `json:"created_at"`
+	Config    MultiSign         `json:"config"`
+	Diff      []MultiSignChange `json:"diff"` // 与上一版本相比变化的字段
+}
+
+// MultiSignChange 一个字段的变化
+type MultiSignChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
 }
@@ -0,0 +1,23 @@
+package response
+
+// OracleSimulateSetPrice setPrice 模拟结果，价格单位为 USD
+type OracleSimulateSetPrice struct {
+	ChainId      string `json:"chain_id"`
+	Oracle       string `json:"oracle"` // Oracle 合约地址
+	From         string `json:"from"`   // 喂价签名地址
+	Token        string `json:"token"`
+	Symbol       string `json:"symbol"`
+	Price        string `json:"price"`        // 待写入的价格
+	PriceE8      int64  `json:"price_e8"`     // 合约 setPrice 的参数，1e8 精度
+	PriceSource  string `json:"price_source"` // request / exchange / testnet_fixed
+	OnChainPrice string `json:"on_chain_price"`
+	DeltaBps     int64  `json:"delta_bps"`  // 待写入价格相对链上价格变化的基点数，链上没有价格时为 0
+	WouldSkip    bool   `json:"would_skip"` // 变化不足 [oracle] min_change_bps，下一次定时写入会跳过 (max_skip_minutes 到期时仍会写入)
+	Reverted     bool   `json:"reverted"`
+	RevertReason string `json:"revert_reason"` // 合约 revert 的原因，例如签名地址没有权限
+	Error        string `json:"error"`         // RPC 等非 revert 的错误
+	Gas          uint64 `json:"gas"`           // eth_estimateGas 的结果，revert 时为 0
+	GasPrice     string `json:"gas_price"`     // 节点建议的 gas 价格, wei
+	Fee          string `json:"fee"`           // gas * gas_price, wei
+	ResultPrice  string `json:"result_price"`  // 交易上链后 getPrice 返回的价格，revert 时为链上当前价格
+}
@@ -0,0 +1,25 @@
+package response
+
+import "pledge-backend/api/models"
+
+// OracleStatus 主网 Oracle 中各喂价代币 (PLGR 和 [oracle] feeds) 的状态
+type OracleStatus struct {
+	ChainId string             `json:"chain_id"`
+	Feeds   []OracleFeedStatus `json:"feeds"`
+}
+
+// OracleFeedStatus 单个代币的链上价格、交易所价格、熔断器和最近一次写入，价格单位为 USD
+type OracleFeedStatus struct {
+	Token             string               `json:"token"`
+	Symbol            string               `json:"symbol"` // 熔断器和写入决策的名称，例如 PLGR-USDT
+	Route             []string             `json:"route"`  // 定价路径
+	OnChainPrice      string               `json:"on_chain_price"`
+	OnChainErr        string               `json:"on_chain_err"`        // 读取链上价格失败的原因
+	LastUpdateAt      int64                `json:"last_update_at"`      // 最近一次写链成功的时间, Unix 秒，Oracle 合约不记录更新时间
+	ExchangePrice     string               `json:"exchange_price"`      // 定价路径上各交易对最新价格的乘积
+	ExchangeUpdatedAt int64                `json:"exchange_updated_at"` // 定价路径上最久未更新的交易对的更新时间, Unix 秒
+	Deviation         string               `json:"deviation"`           // |链上价格 - 交易所价格| / 交易所价格，与 [oracle] max_divergence 比较；任一价格缺失时为空
+	Breaker           models.OracleBreaker `json:"breaker"`
+	LastWrite         *models.OracleWrite  `json:"last_write"` // 最近一次写入决策和结果，还没有喂价时为 null
+	TxStatus          string               `json:"tx_status"`  // 最近一次写入交易在 gas_spend 中的状态 (pending / success / failed / dropped)，没有记录时为空
+}
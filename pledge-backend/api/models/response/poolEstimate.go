@@ -0,0 +1,20 @@
+package response
+
+// PoolEstimate 按池子固定利率和时间线估算的收益/成本，金额均为代币最小单位
+//
+// lend: principal 为出借金额，maturity_value = principal + interest (出借代币)
+// borrow: principal 为按当前价格和抵押率可借出的金额，到期卖出抵押品偿还 principal + interest + lend_fee，
+// maturity_value 为扣除 borrow_fee 后取回的抵押品 (抵押代币)
+type PoolEstimate struct {
+	Side           string `json:"side"`
+	Amount         string `json:"amount"`
+	Duration       int64  `json:"duration"`        // settleTime 到 endTime 的秒数，计息区间
+	InterestRate   string `json:"interest_rate"`   // 固定年化利率 (1e8 精度)
+	Principal      string `json:"principal"`       // 出借代币
+	Interest       string `json:"interest"`        // 出借代币
+	LendFee        string `json:"lend_fee"`        // 出借代币，由借款人的抵押品支付
+	CollateralSold string `json:"collateral_sold"` // 抵押代币，到期为偿还本息和 lend_fee 卖出的抵押品
+	BorrowFee      string `json:"borrow_fee"`      // 抵押代币，按剩余抵押品收取
+	MaturityValue  string `json:"maturity_value"`
+	MaturityToken  string `json:"maturity_token"`
+}
@@ -0,0 +1,40 @@
+package response
+
+import (
+	"pledge-backend/api/models"
+	"strconv"
+)
+
+// PoolHistory 池子历史快照，points 按时间升序
+type PoolHistory struct {
+	Points       []models.PoolSnapshot `json:"points"`
+	StateChanges []PoolStateChange     `json:"state_changes"`
+}
+
+// PoolStateChange 两条相邻快照之间的状态变化
+type PoolStateChange struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	SnapshotAt int64  `json:"snapshot_at"`
+}
+
+// CsvHeader 快照导出为 CSV 时的表头
+func (p *PoolHistory) CsvHeader() []string {
+	return []string{"snapshot_at", "state", "lend_supply", "borrow_supply", "settle_amount_lend", "settle_amount_borrow"}
+}
+
+// CsvRows 快照导出为 CSV 时的数据行
+func (p *PoolHistory) CsvRows() [][]string {
+	rows := make([][]string, 0, len(p.Points))
+	for _, point := range p.Points {
+		rows = append(rows, []string{
+			strconv.FormatInt(point.SnapshotAt, 10),
+			point.State,
+			point.LendSupply,
+			point.BorrowSupply,
+			point.SettleAmountLend,
+			point.SettleAmountBorrow,
+		})
+	}
+	return rows
+}
@@ -0,0 +1,12 @@
+package response
+
+type PoolMetadata struct {
+	ChainId     int      `json:"chain_id"`
+	PoolId      int      `json:"pool_id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Featured    bool     `json:"featured"`
+	UpdatedBy   string   `json:"updated_by"`
+	UpdatedAt   string   `json:"updated_at"`
+}
@@ -0,0 +1,17 @@
+package response
+
+import "pledge-backend/api/models"
+
+// PoolStats 池子参与者和存入统计，金额为代币最小单位
+type PoolStats struct {
+	Lend   PoolSideStats `json:"lend"`
+	Borrow PoolSideStats `json:"borrow"`
+}
+
+type PoolSideStats struct {
+	Participants   int64                 `json:"participants"`    // 去重地址数
+	Deposits       int64                 `json:"deposits"`        // 存入次数
+	TotalDeposit   string                `json:"total_deposit"`   // 累计存入
+	AverageDeposit string                `json:"average_deposit"` // 单次平均存入
+	TopPositions   []models.PoolPosition `json:"top_positions"`   // 累计存入最多的地址
+}
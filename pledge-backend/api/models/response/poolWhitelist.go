@@ -0,0 +1,20 @@
+package response
+
+// 借款检查不通过的原因
+const (
+	WhitelistReasonPaused      = "paused"        // PledgePool.globalPaused
+	WhitelistReasonNotMatch    = "not_match"     // 池子不在匹配阶段，depositBorrow 只在 MATCH 状态可用
+	WhitelistReasonSettled     = "settle_time"   // 已过 settleTime，不能再存入抵押品
+	WhitelistReasonJpNotMinter = "jp_not_minter" // PledgePool 不在 jpCoin 的 minter 白名单中，claimBorrow 会失败
+	WhitelistReasonSpNotMinter = "sp_not_minter" // PledgePool 不在 spCoin 的 minter 白名单中，出借方 claimLend 会失败，池子无法正常结算
+)
+
+// PoolWhitelistCheck 地址是否可以在池子中借款 (depositBorrow)
+// PledgePool 没有按地址的白名单，结果只取决于池子状态和链上权限，对所有地址相同
+type PoolWhitelistCheck struct {
+	Address   string   `json:"address"`
+	Allowed   bool     `json:"allowed"`
+	Reasons   []string `json:"reasons"`    // 不允许借款的原因，见 WhitelistReason*
+	Block     uint64   `json:"block"`      // 权限状态读取的区块
+	UpdatedAt int64    `json:"updated_at"` // 权限状态同步时间, Unix 秒
+}
@@ -0,0 +1,35 @@
+package response
+
+import "pledge-backend/api/models"
+
+// Prices 批量查询的代币价格，price 为 1e8 精度的美元价格
+type Prices struct {
+	Prices  []models.TokenPrice `json:"prices"`
+	Missing []string            `json:"missing"` // 未收录或已删除的代币地址
+}
+
+// Convert 按 token_info 当前价格换算的金额，金额均为代币最小单位
+type Convert struct {
+	From     models.TokenPrice `json:"from"`
+	To       models.TokenPrice `json:"to"`
+	Amount   string            `json:"amount"`    // 源代币
+	Result   string            `json:"result"`    // 目标代币，向下取整
+	Rate     string            `json:"rate"`      // 1 个源代币可换的目标代币数量 (按代币单位，非最小单位)
+	ValueUsd string            `json:"value_usd"` // amount 的美元价值
+}
+
+// Slippage 按本地盘口估算的市价成交结果，价格为计价币单位 (例如 USDT)
+type Slippage struct {
+	Symbol     string `json:"symbol"`
+	Side       string `json:"side"`
+	Amount     string `json:"amount"`      // 请求的成交数量
+	Filled     string `json:"filled"`      // 盘口深度能成交的数量，深度不足时小于 amount
+	Complete   bool   `json:"complete"`    // 是否能全部成交
+	Cost       string `json:"cost"`        // 成交额 (计价币)
+	BestPrice  string `json:"best_price"`  // 对手方最优价
+	MidPrice   string `json:"mid_price"`   // 买一卖一中间价，单边盘口为空时等于 best_price
+	AvgPrice   string `json:"avg_price"`   // 成交均价
+	WorstPrice string `json:"worst_price"` // 吃到的最后一档价格
+	Slippage   string `json:"slippage"`    // 成交均价相对中间价的不利偏离比例，例如 0.0125 表示 1.25%
+	UpdatedAt  int64  `json:"updated_at"`  // 盘口的交易所时间, ms
+}
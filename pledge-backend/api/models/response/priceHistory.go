@@ -0,0 +1,25 @@
+package response
+
+import (
+	"pledge-backend/api/models"
+	"strconv"
+)
+
+// PriceHistory 代币价格历史，points 按时间升序
+type PriceHistory struct {
+	Points []models.TokenPriceHistory `json:"points"`
+}
+
+// CsvHeader 价格历史导出为 CSV 时的表头
+func (p *PriceHistory) CsvHeader() []string {
+	return []string{"price_at", "price"}
+}
+
+// CsvRows 价格历史导出为 CSV 时的数据行
+func (p *PriceHistory) CsvRows() [][]string {
+	rows := make([][]string, 0, len(p.Points))
+	for _, point := range p.Points {
+		rows = append(rows, []string{strconv.FormatInt(point.PriceAt, 10), point.Price})
+	}
+	return rows
+}
@@ -0,0 +1,17 @@
+package response
+
+type Readyz struct {
+	Mysql         string      `json:"mysql"`
+	Redis         string      `json:"redis"`
+	OracleBreaker interface{} `json:"oracle_breaker"`
+	OracleWrites  interface{} `json:"oracle_writes"` // 各链最近一次喂价的写入决策，key 为 chainId
+	OracleFeeds   interface{} `json:"oracle_feeds"`  // [oracle] feeds 中各代币的熔断器状态和主网最近一次写入，key 为 <基础币>-USDT
+	PriceDropped  int64       `json:"price_dropped"` // ws 广播跟不上行情时丢弃的价格更新数
+}
+
+// OracleFeed [oracle] feeds 中一个代币的喂价状态
+type OracleFeed struct {
+	Token   string      `json:"token"`
+	Breaker interface{} `json:"breaker"`
+	Write   interface{} `json:"write"` // 主网最近一次的写入决策和结果，还没有喂价时为 null
+}
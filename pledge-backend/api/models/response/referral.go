@@ -0,0 +1,39 @@
+package response
+
+type ReferralCode struct {
+	Address   string `json:"address"`
+	Code      string `json:"code"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ReferralAttribution 归属结果，pending 时交易尚未被索引，池子和金额为空
+type ReferralAttribution struct {
+	ChainId int    `json:"chain_id"`
+	TxHash  string `json:"tx_hash"`
+	Code    string `json:"code"`
+	Status  string `json:"status"` // pending / verified
+	Referee string `json:"referee"`
+	PoolId  int    `json:"pool_id"`
+	Event   string `json:"event"`
+	Token   string `json:"token"`
+	Amount  string `json:"amount"`
+}
+
+// Referral 推荐人的推荐码和已校验的推荐数据
+type Referral struct {
+	ChainId   int    `json:"chain_id"`
+	Address   string `json:"address"`
+	Code      string `json:"code"`
+	Window    string `json:"window"`
+	Referrals int64  `json:"referrals"` // 已校验的存入交易数
+	Referees  int    `json:"referees"`  // 去重的被推荐地址数
+	ValueUsd  string `json:"value_usd"` // 存入按代币当前价格折算的美元价值，保留两位小数
+	Pending   int64  `json:"pending"`   // 等待校验的交易数
+}
+
+// ReferralStats 按推荐存入价值排序的推荐人
+type ReferralStats struct {
+	ChainId   int        `json:"chain_id"`
+	Window    string     `json:"window"`
+	Referrers []Referral `json:"referrers"`
+}
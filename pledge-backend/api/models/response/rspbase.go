@@ -1,61 +1,177 @@
 package response
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
 	"pledge-backend/api/common/statecode"
+	"pledge-backend/config"
+	"pledge-backend/i18n"
+	"pledge-backend/log"
+	"time"
 )
 
 type Gin struct {
 	Res *gin.Context
 }
 
-type Page struct {
-	Code  int         `json:"code"`
-	Msg   string      `json:"message"`
-	Total int         `json:"total"`
-	Data  interface{} `json:"data"`
+// Meta 每个响应附带的请求信息
+type Meta struct {
+	RequestId  string      `json:"request_id"` // 与响应头 X-Request-Id 一致，排查问题时提供
+	Timestamp  int64       `json:"timestamp"`  // 服务器时间, Unix 毫秒
+	Version    string      `json:"version"`    // 接口版本，与路径中的 v{version} 一致
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+// Pagination 分页接口的分页信息
+type Pagination struct {
+	Page     int   `json:"page"`
+	PageSize int   `json:"page_size"`
+	Total    int64 `json:"total"`
+	Pages    int64 `json:"pages"` // 总页数
 }
 
-// ResponsePages
-// 响应统一分页格式
-func (g *Gin) ResponsePages(c *gin.Context, code int, totalCount int, data interface{}) {
-	lang := statecode.LangZh
-	langInf, hasLang := c.Get("lang")
-	if hasLang {
-		lang = langInf.(int)
+// NewPagination 按总条数计算总页数
+func NewPagination(page, pageSize int, total int64) *Pagination {
+	p := &Pagination{Page: page, PageSize: pageSize, Total: total}
+	if pageSize > 0 {
+		p.Pages = (total + int64(pageSize) - 1) / int64(pageSize)
 	}
-	rsp := Page{
-		Code:  code,
-		Msg:   statecode.GetMsg(code, lang),
-		Total: totalCount,
-		Data:  data,
+	return p
+}
+
+// NewMeta 当前请求的 Meta，request_id 由 middlewares.RequestId 设置
+func NewMeta(c *gin.Context) *Meta {
+	return &Meta{
+		RequestId: c.GetString("request_id"),
+		Timestamp: time.Now().UnixMilli(),
+		Version:   config.Config().Env.Version,
 	}
-	g.Res.JSON(200, rsp)
-	return
 }
 
-// Response  响应统一格式
-func (g *Gin) Response(c *gin.Context, code int, data interface{}, httpStatus ...int) {
-	lang := statecode.LangEn
-	langInf, hasLang := c.Get("lang")
-	if hasLang {
-		lang = langInf.(int)
+// Page 分页接口的响应
+type Page struct {
+	Response
+	Total int64 `json:"total"` // Deprecated: 旧分页格式的总条数，保留一个版本后移除，改用 meta.pagination.total
+}
+
+// ResponsePage 分页接口的成功响应，data 之外在 meta.pagination 中返回分页信息
+func (g *Gin) ResponsePage(c *gin.Context, data interface{}, pagination *Pagination) {
+	meta := NewMeta(c)
+	meta.Pagination = pagination
+	g.Res.JSON(status(statecode.CommonSuccess), Page{
+		Response: Response{
+			Code: statecode.CommonSuccess,
+			Msg:  statecode.GetMsg(statecode.CommonSuccess, Language(c)),
+			Data: data,
+			Meta: meta,
+		},
+		Total: pagination.Total,
+	})
+}
+
+// LastModified 设置响应头 Last-Modified，modified 为零值时不设置
+// 由 middlewares.HttpCache 处理 If-Modified-Since，middlewares.ResponseCache 与响应一起缓存
+func (g *Gin) LastModified(modified time.Time) {
+	if !modified.IsZero() {
+		g.Res.Header("Last-Modified", modified.UTC().Format(http.TimeFormat))
 	}
+}
+
+// Response  响应统一格式，未指定 httpStatus 时按 statecode.HttpStatus 映射，[env] strict_status 关闭时都为 200
+func (g *Gin) Response(c *gin.Context, code int, data interface{}, httpStatus ...int) {
+	lang := Language(c)
 	rsp := Response{
 		Code: code,
 		Msg:  statecode.GetMsg(code, lang),
 		Data: data,
+		Meta: NewMeta(c),
+	}
+	if code == statecode.CommonErrServerErr && timedOut(c) {
+		code = statecode.RequestTimeout
+		rsp.Code, rsp.Msg = code, statecode.GetMsg(code, lang)
+	}
+	g.Res.JSON(status(code, httpStatus...), rsp)
+}
+
+// Error 错误响应 {code, message, details}，非 *statecode.Error 的错误按服务器错误返回，原始错误只写入日志
+func (g *Gin) Error(c *gin.Context, err error) {
+	e := statecode.FromError(err)
+	if e.Code == statecode.CommonErrServerErr && timedOut(c) {
+		e = statecode.Wrap(statecode.RequestTimeout, e.Cause)
+	}
+	if e.Cause != nil {
+		log.Logger.Error("request failed", zap.String("path", c.FullPath()), zap.String("request_id", c.GetString("request_id")),
+			zap.Int("code", e.Code), zap.Error(e.Cause))
+	}
+	lang := Language(c)
+	g.Res.JSON(status(e.Code), Response{
+		Code:    e.Code,
+		Msg:     statecode.GetMsg(e.Code, lang),
+		Details: e.Details,
+		Meta:    NewMeta(c),
+	})
+}
+
+// Language 由 middlewares.Language 按 Accept-Language 选择的语言，未经过该中间件时为 [i18n] default_language
+func Language(c *gin.Context) int {
+	if lang, ok := c.Get("lang"); ok {
+		return lang.(int)
+	}
+	return i18n.Default()
+}
+
+// status 响应的 HTTP 状态码: 指定了 httpStatus 时使用指定值，否则按 statecode.HttpStatus 映射
+// [env] strict_status 关闭时都返回 200，结果只通过 code 判断，兼容旧客户端
+func status(code int, httpStatus ...int) int {
+	if !config.Config().Env.StrictStatus {
+		return http.StatusOK
 	}
-	HttpStatus := 200
 	if len(httpStatus) > 0 {
-		HttpStatus = httpStatus[0]
+		return httpStatus[0]
 	}
-	g.Res.JSON(HttpStatus, rsp)
-	return
+	return statecode.HttpStatus(code)
 }
 
+// timedOut 请求超过 middlewares.Timeout 设置的处理时限，下游调用因 ctx 取消而失败
+func timedOut(c *gin.Context) bool {
+	return errors.Is(c.Request.Context().Err(), context.DeadlineExceeded)
+}
+
+// Response 统一的响应格式 {code, message, data, details, meta}
 type Response struct {
-	Code int         `json:"code"`
-	Msg  string      `json:"message"`
-	Data interface{} `json:"data"`
+	Code    int         `json:"code"`
+	Msg     string      `json:"message"`
+	Data    interface{} `json:"data"`
+	Details interface{} `json:"details,omitempty"`
+	Meta    *Meta       `json:"meta,omitempty"`
+}
+
+// Succeeded 响应体是否为成功的 JSON 响应: 统一格式中 code 为 0，
+// 或没有 code 的标准格式 (例如 /token 的 Token List) 中没有 error
+func Succeeded(body []byte) bool {
+	rsp := make(map[string]json.RawMessage)
+	if json.Unmarshal(body, &rsp) != nil {
+		return false
+	}
+	if raw, ok := rsp["code"]; ok {
+		var code int
+		return json.Unmarshal(raw, &code) == nil && code == statecode.CommonSuccess
+	}
+	_, failed := rsp["error"]
+	return !failed
+}
+
+// Csv 以 CSV 附件返回，header 为表头，rows 为数据行
+func (g *Gin) Csv(c *gin.Context, filename string, header []string, rows [][]string) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename=\""+filename+"\"")
+	c.Status(200)
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write(header)
+	_ = w.WriteAll(rows)
 }
@@ -6,3 +6,26 @@ type Search struct {
 	Count int64         `json:"count"`
 	Rows  []models.Pool `json:"rows"`
 }
+
+type PublicSearch struct {
+	Count int64        `json:"count"`
+	Rows  []PublicPool `json:"rows"`
+}
+
+// PublicPool 公开搜索返回的字段，不包含 sp/jp 代币和清算阈值等管理信息
+type PublicPool struct {
+	PoolID            int    `json:"pool_id"`
+	State             string `json:"state"`
+	SettleTime        string `json:"settleTime"`
+	EndTime           string `json:"endTime"`
+	InterestRate      string `json:"interestRate"`
+	MaxSupply         string `json:"maxSupply"`
+	LendSupply        string `json:"lendSupply"`
+	BorrowSupply      string `json:"borrowSupply"`
+	MartgageRate      string `json:"martgageRate"`
+	LendToken         string `json:"lendToken"`
+	LendTokenSymbol   string `json:"lend_token_symbol"`
+	BorrowToken       string `json:"borrowToken"`
+	BorrowTokenSymbol string `json:"borrow_token_symbol"`
+	Archived          bool   `json:"archived"`
+}
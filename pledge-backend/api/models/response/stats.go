@@ -0,0 +1,60 @@
+package response
+
+import "pledge-backend/api/models"
+
+// Tvl 各链的 TVL、利用率和各代币锁定价值的时间序列
+type Tvl struct {
+	Interval string            `json:"interval"`
+	Range    string            `json:"range"`
+	Chains   []models.ChainTvl `json:"chains"`
+}
+
+// Fees 协议手续费收入，金额为美元，保留两位小数
+type Fees struct {
+	Group   string      `json:"group"`
+	From    string      `json:"from"`
+	To      string      `json:"to"`
+	Total   FeePeriod   `json:"total"`
+	Periods []FeePeriod `json:"periods"`          // 范围内的每个月 / 每天，没有收入的时间段也返回
+	Pools   []FeePool   `json:"pools"`            // 范围内记入收入的池子，按时间升序
+	Chains  []FeeChain  `json:"chains,omitempty"` // chainId 为列表或 all 时每条链的合计
+}
+
+type FeeChain struct {
+	ChainId int         `json:"chain_id"`
+	Total   FeePeriod   `json:"total"`
+	Periods []FeePeriod `json:"periods"`
+}
+
+type FeePeriod struct {
+	Period       string `json:"period"` // 2006-01 或 2006-01-02，total 为空
+	LendFeeUsd   string `json:"lend_fee_usd"`
+	BorrowFeeUsd string `json:"borrow_fee_usd"`
+	TotalUsd     string `json:"total_usd"`
+	Pools        int    `json:"pools"` // 完成或清算的池子数
+}
+
+type FeePool struct {
+	ChainId         int    `json:"chain_id"`
+	PoolId          int    `json:"pool_id"`
+	Event           string `json:"event"` // finish / liquidation
+	Period          string `json:"period"`
+	LendToken       string `json:"lend_token"`
+	BorrowToken     string `json:"borrow_token"`
+	LendFeeAmount   string `json:"lend_fee_amount"`   // 出借代币最小单位
+	BorrowFeeAmount string `json:"borrow_fee_amount"` // 抵押代币最小单位
+	LendFeeUsd      string `json:"lend_fee_usd"`
+	BorrowFeeUsd    string `json:"borrow_fee_usd"`
+	TotalUsd        string `json:"total_usd"`
+	AccruedAt       int64  `json:"accrued_at"`
+}
+
+// Leaderboard 按出借或抵押存入价值排序的地址
+type Leaderboard struct {
+	ChainId   int                       `json:"chain_id"`
+	PoolId    int                       `json:"pool_id"` // 0 为全协议
+	Side      string                    `json:"side"`
+	Window    string                    `json:"window"`
+	Entries   []models.LeaderboardEntry `json:"entries"`
+	UpdatedAt int64                     `json:"updated_at"`
+}
@@ -3,11 +3,33 @@ package response
 import "time"
 
 type TokenList struct {
-	Name      string    `json:"name"`
-	LogoURI   string    `json:"logoURI"`
-	Tokens    []Token   `json:"tokens"`
-	Version   Version   `json:"version"`
-	Timestamp time.Time `json:"timestamp"`
+	Name      string              `json:"name"`
+	LogoURI   string              `json:"logoURI"`
+	Tokens    []Token             `json:"tokens"`
+	Version   Version             `json:"version"`
+	Timestamp time.Time           `json:"timestamp"`
+	Signature *TokenListSignature `json:"signature,omitempty"`
+}
+
+// TokenLists /token 的 chainId 为多条链时按链分组的代币列表
+type TokenLists struct {
+	Chains []ChainData `json:"chains"`
+}
+
+// TokenListSignature EIP-712 签名，tokensHash 为 tokens 数组 JSON 的 keccak256
+type TokenListSignature struct {
+	Signer     string `json:"signer"`
+	TokensHash string `json:"tokensHash"`
+	Signature  string `json:"signature"`
+}
+
+// TokenListChange 一个版本相对上一版本的变化
+type TokenListChange struct {
+	Version   string   `json:"version"`
+	Added     []string `json:"added"`
+	Removed   []string `json:"removed"`
+	Changed   []string `json:"changed"`
+	Timestamp string   `json:"timestamp"`
 }
 
 type Token struct {
@@ -24,3 +46,16 @@ type Version struct {
 	Minor int `json:"minor"`
 	Patch int `json:"patch"`
 }
+
+type TokenLogo struct {
+	Logo     string            `json:"logo"`
+	Variants map[string]string `json:"variants"` // 尺寸 -> 地址，SVG 所有尺寸为同一文件
+}
+
+// TokenLogoUrl 代币当前 logo 在 /assets 下带内容哈希的地址，logo 更换后地址随之变化
+type TokenLogoUrl struct {
+	ChainId  int               `json:"chain_id"`
+	Address  string            `json:"address"`
+	Logo     string            `json:"logo"`     // 远程图片或文件不存在时为 token_info.logo 原值，没有 logo 时为空
+	Variants map[string]string `json:"variants"` // 上传的 PNG 按 [token] logo_sizes 生成的缩略图，尺寸 -> 地址
+}
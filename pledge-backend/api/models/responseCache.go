@@ -0,0 +1,32 @@
+package models
+
+import (
+	"encoding/json"
+	"pledge-backend/db"
+)
+
+const responseCachePrefix = "response_cache:"
+
+// ResponseCache 热点读接口的响应缓存，保存在 Redis response_cache:<语言>:<请求 URI>，所有 API 实例共享
+type ResponseCache struct {
+	Body         json.RawMessage `json:"body"`
+	LastModified string          `json:"last_modified"` // 响应头 Last-Modified，handler 没有设置时为空
+}
+
+func NewResponseCache() *ResponseCache {
+	return &ResponseCache{}
+}
+
+// Set 保存成功的响应 aliveSeconds 秒
+func (r *ResponseCache) Set(key string, aliveSeconds int) error {
+	return db.RedisSet(responseCachePrefix+key, r, aliveSeconds)
+}
+
+// Get 读取缓存的响应，没有缓存时返回 false
+func (r *ResponseCache) Get(key string) bool {
+	data, err := db.RedisGet(responseCachePrefix + key)
+	if err != nil || len(data) == 0 {
+		return false
+	}
+	return json.Unmarshal(data, r) == nil && len(r.Body) > 0
+}
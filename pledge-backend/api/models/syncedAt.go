@@ -0,0 +1,25 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"pledge-backend/db"
+	"time"
+)
+
+// SyncedAt 链 chainIds 在表 table 中最近一次修改的时间 (updated_at 最大值，包括已删除的记录)，没有记录时为零值
+// schedule 只在链上数据变化时更新 updated_at，用作接口的 Last-Modified
+func SyncedAt(ctx context.Context, table string, chainIds []int) (time.Time, error) {
+	var updatedAt sql.NullTime
+	err := db.Mysql.WithContext(ctx).Table(table).Where("chain_id in ?", chainIds).Select("max(" + syncedColumn(table) + ")").Row().Scan(&updatedAt)
+	return updatedAt.Time, err
+}
+
+// syncedColumn 只新增、不修改记录的表没有 updated_at，按 created_at
+func syncedColumn(table string) string {
+	switch table {
+	case "token_price_history", "token_list_version":
+		return "created_at"
+	}
+	return "updated_at"
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"context"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+)
+
+// token_info.price_source，为空时由 schedule 自动选择价格来源
+const (
+	PriceSourceAuto      = ""
+	PriceSourceOracle    = "oracle"
+	PriceSourceExchange  = "exchange"
+	PriceSourceCoingecko = "coingecko"
+)
+
+// LogoSourceUpload 管理员上传的 logo，schedule 不会自动替换
+const LogoSourceUpload = "upload"
+
+// TokenAdmin 管理后台使用的 token_info 完整字段
+type TokenAdmin struct {
+	Id          int32   `json:"id" gorm:"column:id;primaryKey"`
+	Token       string  `json:"token" gorm:"column:token"`
+	ChainId     string  `json:"chain_id" gorm:"column:chain_id"`
+	Symbol      string  `json:"symbol" gorm:"column:symbol"`
+	Name        string  `json:"name" gorm:"column:name"`
+	Decimals    int     `json:"decimals" gorm:"column:decimals"`
+	Logo        string  `json:"logo" gorm:"column:logo"`
+	LogoSource  string  `json:"logo_source" gorm:"column:logo_source"`
+	Price       string  `json:"price" gorm:"column:price"`
+	PriceSource string  `json:"price_source" gorm:"column:price_source"`
+	CoingeckoId string  `json:"coingecko_id" gorm:"column:coingecko_id"`
+	CreatedAt   string  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt   string  `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt   *string `json:"-" gorm:"column:deleted_at"`
+}
+
+func NewTokenAdmin() *TokenAdmin {
+	return &TokenAdmin{}
+}
+
+// List 查询指定链上未删除的代币
+func (m *TokenAdmin) List(chainId int, res *[]TokenAdmin) error {
+	return db.Mysql.Table("token_info").Where("chain_id=? and deleted_at is null", chainId).Order("id asc").Find(res).Debug().Error
+}
+
+// ChangedSince since (datetime) 之后新增、修改或删除的代币，包含已删除的记录
+func (m *TokenAdmin) ChangedSince(ctx context.Context, chainId int, since string, res *[]TokenAdmin) error {
+	return db.Mysql.WithContext(ctx).Table("token_info").Where("chain_id=? and updated_at>=?", chainId, since).
+		Order("id asc").Find(res).Debug().Error
+}
+
+// Get 按 id 查询未删除的代币
+func (m *TokenAdmin) Get(id int32) error {
+	return db.Mysql.Table("token_info").Where("id=? and deleted_at is null", id).First(m).Debug().Error
+}
+
+// GetActiveByToken 按链和地址查询未删除的代币
+func (m *TokenAdmin) GetActiveByToken(chainId, token string) error {
+	return db.Mysql.Table("token_info").Where("chain_id=? and token=? and deleted_at is null", chainId, token).First(m).Debug().Error
+}
+
+// GetByToken 按链和地址查询代币，包含已删除的记录
+func (m *TokenAdmin) GetByToken(chainId, token string) error {
+	return db.Mysql.Table("token_info").Where("chain_id=? and token=?", chainId, token).First(m).Debug().Error
+}
+
+// Create 新增代币，已软删除的同地址代币直接恢复
+func (m *TokenAdmin) Create() error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	m.UpdatedAt = nowDateTime
+
+	old := TokenAdmin{}
+	err := old.GetByToken(m.ChainId, m.Token)
+	if err == nil {
+		m.Id = old.Id
+		m.CreatedAt = old.CreatedAt
+		err = db.Mysql.Table("token_info").Where("id=?", old.Id).Updates(map[string]interface{}{
+			"token":        m.Token,
+			"symbol":       m.Symbol,
+			"decimals":     m.Decimals,
+			"logo":         m.Logo,
+			"price_source": m.PriceSource,
+			"coingecko_id": m.CoingeckoId,
+			"updated_at":   nowDateTime,
+			"deleted_at":   nil,
+		}).Debug().Error
+	} else {
+		m.CreatedAt = nowDateTime
+		err = db.Mysql.Table("token_info").Create(m).Debug().Error
+	}
+	if err != nil {
+		return err
+	}
+	m.clearCache()
+	return nil
+}
+
+// Update 更新代币字段
+func (m *TokenAdmin) Update(fields map[string]interface{}) error {
+	fields["updated_at"] = utils.GetCurDateTimeFormat()
+	err := db.Mysql.Table("token_info").Where("id=?", m.Id).Updates(fields).Debug().Error
+	if err != nil {
+		return err
+	}
+	m.clearCache()
+	return nil
+}
+
+// Delete 软删除代币，schedule 不再同步该代币，公开接口不再返回
+func (m *TokenAdmin) Delete() error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	err := db.Mysql.Table("token_info").Where("id=?", m.Id).Updates(map[string]interface{}{
+		"updated_at": nowDateTime,
+		"deleted_at": nowDateTime,
+	}).Debug().Error
+	if err != nil {
+		return err
+	}
+	m.clearCache()
+	return nil
+}
+
+// clearCache 删除 token_info 缓存，schedule 下次从 MySQL 重新读取
+func (m *TokenAdmin) clearCache() {
+	_, _ = db.RedisDelete("token_info:" + m.ChainId + ":" + m.Token)
+}
@@ -1,9 +1,12 @@
 package models
 
 import (
+	"context"
 	"errors"
 	"pledge-backend/api/models/request"
 	"pledge-backend/db"
+	"pledge-backend/utils"
+	"strings"
 )
 
 type TokenInfo struct {
@@ -15,6 +18,7 @@ type TokenInfo struct {
 
 type TokenList struct {
 	Id       int32  `json:"-" gorm:"column:id;primaryKey"`
+	Name     string `json:"name" gorm:"column:name"`
 	Symbol   string `json:"symbol" gorm:"column:symbol"`
 	Decimals int    `json:"decimals" gorm:"column:decimals"`
 	Token    string `json:"token" gorm:"column:token"`
@@ -22,13 +26,32 @@ type TokenList struct {
 	ChainId  int    `json:"chain_id" gorm:"column:chain_id"`
 }
 
+// TokenPriceSource 代币在不同价格来源的价格，均为 1e8 精度
+type TokenPriceSource struct {
+	Symbol             string `json:"symbol" gorm:"column:symbol"`
+	Token              string `json:"token" gorm:"column:token"`
+	ChainId            int    `json:"chain_id" gorm:"column:chain_id"`
+	Price              string `json:"oracle_price" gorm:"column:price"`
+	ChainlinkPrice     string `json:"chainlink_price" gorm:"column:chainlink_price"`
+	ChainlinkUpdatedAt int64  `json:"chainlink_updated_at" gorm:"column:chainlink_updated_at"`
+}
+
+// TokenPrice 代币当前价格 (1e8 精度) 和精度，用于批量查询价格和金额换算
+type TokenPrice struct {
+	Symbol    string `json:"symbol" gorm:"column:symbol"`
+	Token     string `json:"token" gorm:"column:token"`
+	Decimals  int    `json:"decimals" gorm:"column:decimals"`
+	Price     string `json:"price" gorm:"column:price"`
+	UpdatedAt string `json:"updated_at" gorm:"column:updated_at"`
+}
+
 func NewTokenInfo() *TokenInfo {
 	return &TokenInfo{}
 }
 
 func (m *TokenInfo) GetTokenInfo(req *request.TokenList) (error, []TokenInfo) {
 	var tokenInfo = make([]TokenInfo, 0)
-	err := db.Mysql.Table("token_info").Where("chain_id", req.ChainId).Find(&tokenInfo).Debug().Error
+	err := db.Mysql.Table("token_info").Where("chain_id=? and deleted_at is null", req.ChainId).Find(&tokenInfo).Debug().Error
 	if err != nil {
 		return errors.New("record select err " + err.Error()), nil
 	}
@@ -37,9 +60,36 @@ func (m *TokenInfo) GetTokenInfo(req *request.TokenList) (error, []TokenInfo) {
 
 func (m *TokenInfo) GetTokenList(req *request.TokenList) (error, []TokenList) {
 	var tokenList = make([]TokenList, 0)
-	err := db.Mysql.Table("token_info").Where("chain_id", req.ChainId).Find(&tokenList).Debug().Error
+	err := db.Mysql.Table("token_info").Where("chain_id=? and deleted_at is null", req.ChainId).Find(&tokenList).Debug().Error
 	if err != nil {
 		return errors.New("record select err " + err.Error()), nil
 	}
 	return nil, tokenList
 }
+
+func (m *TokenInfo) GetTokenPriceSources(req *request.TokenList) (error, []TokenPriceSource) {
+	var sources = make([]TokenPriceSource, 0)
+	err := db.Mysql.Table("token_info").Where("chain_id=? and deleted_at is null", req.ChainId).Find(&sources).Debug().Error
+	if err != nil {
+		return errors.New("record select err " + err.Error()), nil
+	}
+	return nil, sources
+}
+
+// GetTokenPrices 按地址批量查询未删除代币的价格，不存在的代币不返回
+func (m *TokenInfo) GetTokenPrices(chainId int, tokens []string, res *[]TokenPrice) error {
+	return db.Mysql.Table("token_info").Where("chain_id=? and token in ? and deleted_at is null", chainId, tokens).Find(res).Debug().Error
+}
+
+// Search 通过 search_term 索引前缀匹配代币，最多返回 20 条
+func (m *TokenInfo) Search(ctx context.Context, chainId int, keyword string, res *[]TokenList) error {
+	return db.Mysql.WithContext(ctx).Table("token_info").
+		Where("chain_id=? and deleted_at is null", chainId).
+		Where("token in (select token from search_term where chain_id=? and term like ?)", utils.IntToString(chainId), EscapeLike(keyword)+"%").
+		Order("symbol asc").Limit(20).Find(res).Debug().Error
+}
+
+// EscapeLike 转义 LIKE 通配符
+func EscapeLike(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(s)
+}
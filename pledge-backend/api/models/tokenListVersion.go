@@ -0,0 +1,48 @@
+package models
+
+import (
+	"pledge-backend/db"
+	"pledge-backend/utils"
+)
+
+// TokenListVersion Token List 的版本记录，代币列表变化时按 Token List 规范递增版本号:
+// 删除代币 major+1，新增代币 minor+1，修改代币信息 patch+1
+type TokenListVersion struct {
+	Id        int32  `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId   int    `json:"chain_id" gorm:"column:chain_id;uniqueIndex:uk_chain_version"`
+	Major     int    `json:"major" gorm:"column:major;uniqueIndex:uk_chain_version"`
+	Minor     int    `json:"minor" gorm:"column:minor;uniqueIndex:uk_chain_version"`
+	Patch     int    `json:"patch" gorm:"column:patch;uniqueIndex:uk_chain_version"`
+	Hash      string `json:"hash" gorm:"column:hash;type:varchar(64)"`
+	Tokens    string `json:"-" gorm:"column:tokens;type:mediumtext"` // 该版本的代币快照 (JSON)
+	Added     string `json:"-" gorm:"column:added;type:text"`        // 新增的代币地址 (JSON 数组)
+	Removed   string `json:"-" gorm:"column:removed;type:text"`      // 删除的代币地址 (JSON 数组)
+	Changed   string `json:"-" gorm:"column:changed;type:text"`      // 修改的代币地址 (JSON 数组)
+	CreatedAt string `json:"created_at" gorm:"column:created_at"`
+}
+
+func NewTokenListVersion() *TokenListVersion {
+	return &TokenListVersion{}
+}
+
+func (m *TokenListVersion) TableName() string {
+	return "token_list_version"
+}
+
+// Latest 查询指定链的最新版本
+func (m *TokenListVersion) Latest(chainId int) error {
+	return db.Mysql.Table("token_list_version").Where("chain_id=?", chainId).
+		Order("major desc, minor desc, patch desc").First(m).Debug().Error
+}
+
+// List 查询指定链的版本历史，新版本在前
+func (m *TokenListVersion) List(chainId, limit int, res *[]TokenListVersion) error {
+	return db.Mysql.Table("token_list_version").Where("chain_id=?", chainId).
+		Order("major desc, minor desc, patch desc").Limit(limit).Find(res).Debug().Error
+}
+
+// Create 写入新版本，并发请求写入同一版本号时由唯一索引拦截
+func (m *TokenListVersion) Create() error {
+	m.CreatedAt = utils.GetCurDateTimeFormat()
+	return db.Mysql.Table("token_list_version").Create(m).Debug().Error
+}
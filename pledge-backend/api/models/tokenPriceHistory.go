@@ -0,0 +1,62 @@
+package models
+
+import (
+	"context"
+	"pledge-backend/db"
+)
+
+// TokenPriceHistory 代币价格变化记录，由 schedule 在价格变化时写入
+type TokenPriceHistory struct {
+	Id        int    `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId   string `json:"-" gorm:"column:chain_id;type:varchar(16);index:idx_chain_token_time,priority:1"`
+	Token     string `json:"-" gorm:"column:token;type:varchar(64);index:idx_chain_token_time,priority:2"`
+	Price     string `json:"price" gorm:"column:price"` // 1e8 精度
+	PriceAt   int64  `json:"price_at" gorm:"column:price_at;index:idx_chain_token_time,priority:3"`
+	CreatedAt string `json:"-" gorm:"column:created_at"`
+}
+
+func NewTokenPriceHistory() *TokenPriceHistory {
+	return &TokenPriceHistory{}
+}
+
+func (t *TokenPriceHistory) TableName() string {
+	return "token_price_history"
+}
+
+// History 查询 [from, to] 内最近的 limit 条价格，按时间升序返回，to 为 0 时不限结束时间
+func (t *TokenPriceHistory) History(chainId int, token string, from, to int64, limit int, res *[]TokenPriceHistory) error {
+	tx := db.Mysql.Table("token_price_history").Where("chain_id=? and token=? and price_at>=?", chainId, token, from)
+	if to > 0 {
+		tx = tx.Where("price_at<=?", to)
+	}
+	err := tx.Order("price_at desc").Limit(limit).Find(res).Debug().Error
+	if err != nil {
+		return err
+	}
+	for i, j := 0, len(*res)-1; i < j; i, j = i+1, j-1 {
+		(*res)[i], (*res)[j] = (*res)[j], (*res)[i]
+	}
+	return nil
+}
+
+// LatestBefore 每个代币在 ts 之前的最后一次价格
+func (t *TokenPriceHistory) LatestBefore(ctx context.Context, chainId int, ts int64, res *[]TokenPriceHistory) error {
+	latest := db.Mysql.Table("token_price_history").Select("token, max(price_at)").
+		Where("chain_id=? and price_at<?", chainId, ts).Group("token")
+	return db.Mysql.WithContext(ctx).Table("token_price_history").Where("chain_id=? and (token, price_at) in (?)", chainId, latest).
+		Find(res).Debug().Error
+}
+
+// LatestSince ts 及之后价格有变化的代币的最新价格
+func (t *TokenPriceHistory) LatestSince(ctx context.Context, chainId int, ts int64, res *[]TokenPriceHistory) error {
+	latest := db.Mysql.Table("token_price_history").Select("token, max(price_at)").
+		Where("chain_id=? and price_at>=?", chainId, ts).Group("token")
+	return db.Mysql.WithContext(ctx).Table("token_price_history").Where("chain_id=? and (token, price_at) in (?)", chainId, latest).
+		Order("token asc, id asc").Find(res).Debug().Error
+}
+
+// Between [from, to) 内所有代币的价格变化，按时间升序
+func (t *TokenPriceHistory) Between(ctx context.Context, chainId int, from, to int64, res *[]TokenPriceHistory) error {
+	return db.Mysql.WithContext(ctx).Table("token_price_history").Where("chain_id=? and price_at>=? and price_at<?", chainId, from, to).
+		Order("price_at asc, id asc").Find(res).Debug().Error
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/json"
+	"pledge-backend/db"
+)
+
+// ChainTvl 一条链的 TVL 和利用率时间序列，由 pool_snapshots 和 token_price_history 计算
+type ChainTvl struct {
+	ChainId string     `json:"chain_id"`
+	Points  []TvlPoint `json:"points"`
+	Tokens  []TokenTvl `json:"tokens"`
+}
+
+// TvlPoint 一个时间段结束时 (最后一段为当前时间) 匹配和执行中池子锁定的价值，金额为美元，保留两位小数
+type TvlPoint struct {
+	Timestamp   int64  `json:"timestamp"`   // 时间段开始, Unix 秒
+	Tvl         string `json:"tvl"`         // lend + borrow
+	Lend        string `json:"lend"`        // 出借侧 lendSupply 的价值
+	Borrow      string `json:"borrow"`      // 抵押侧 borrowSupply 的价值
+	Utilization string `json:"utilization"` // 这些池子 lendSupply 与 maxSupply 价值之比, 0 ~ 1
+}
+
+// TokenTvl 单个代币锁定的数量和价值，出借和抵押两侧合计
+type TokenTvl struct {
+	Token  string          `json:"token"`
+	Symbol string          `json:"symbol"`
+	Points []TokenTvlPoint `json:"points"`
+}
+
+type TokenTvlPoint struct {
+	Timestamp int64  `json:"timestamp"`
+	Amount    string `json:"amount"` // 代币最小单位
+	Tvl       string `json:"tvl"`    // 美元
+}
+
+func NewChainTvl() *ChainTvl {
+	return &ChainTvl{}
+}
+
+// tvlCacheKey 按链、间隔和范围缓存计算结果
+func tvlCacheKey(chainId, interval, rangeText string) string {
+	return "stats_tvl:" + chainId + ":" + interval + ":" + rangeText
+}
+
+// GetCache 读取缓存的计算结果，没有缓存时返回 false
+func (c *ChainTvl) GetCache(chainId, interval, rangeText string) bool {
+	data, err := db.RedisGet(tvlCacheKey(chainId, interval, rangeText))
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, c) == nil
+}
+
+// SetCache 缓存计算结果 aliveSeconds 秒
+func (c *ChainTvl) SetCache(interval, rangeText string, aliveSeconds int) error {
+	return db.RedisSet(tvlCacheKey(c.ChainId, interval, rangeText), c, aliveSeconds)
+}
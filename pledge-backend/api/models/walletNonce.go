@@ -0,0 +1,50 @@
+package models
+
+import (
+	"pledge-backend/db"
+	"pledge-backend/utils"
+	"strings"
+)
+
+// WalletNonceTtl 钱包签名 nonce 的有效期 (秒)
+const WalletNonceTtl = 300
+
+const walletNoncePrefix = "wallet_nonce:"
+
+// WalletNonce 钱包证明地址所有权的一次性 nonce，存放在 Redis，key 为小写地址
+// 用于 WebSocket 私有主题 claimable:{chainId}:{address}，钱包对 WalletNonceMessage 签名 (personal_sign)
+type WalletNonce struct{}
+
+func NewWalletNonce() *WalletNonce {
+	return &WalletNonce{}
+}
+
+// Create 为地址生成新的 nonce，之前未使用的 nonce 失效
+func (n *WalletNonce) Create(address string) (string, error) {
+	nonce := utils.UniqueId()
+	err := db.RedisSetString(walletNoncePrefix+strings.ToLower(address), nonce, WalletNonceTtl)
+	if err != nil {
+		return "", err
+	}
+	return nonce, nil
+}
+
+// Get 地址当前有效的 nonce，没有或已过期时返回 false
+func (n *WalletNonce) Get(address string) (string, bool) {
+	nonce, err := db.RedisGetString(walletNoncePrefix + strings.ToLower(address))
+	if err != nil || nonce == "" {
+		return "", false
+	}
+	return nonce, true
+}
+
+// Use 签名校验通过后删除 nonce，同一个 nonce 只有第一次使用返回 true
+func (n *WalletNonce) Use(address, nonce string) bool {
+	used, err := db.RedisDeleteIfEqual(walletNoncePrefix+strings.ToLower(address), nonce)
+	return err == nil && used
+}
+
+// WalletNonceMessage 钱包需要签名的消息
+func WalletNonceMessage(address, nonce string) string {
+	return "Sign in to Pledge to receive updates for this wallet.\n\nAddress: " + strings.ToLower(address) + "\nNonce: " + nonce
+}
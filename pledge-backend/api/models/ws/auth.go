@@ -0,0 +1,122 @@
+package ws
+
+import (
+	"crypto/subtle"
+	"pledge-backend/api/models"
+	"pledge-backend/config"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Authenticate 校验 jobs / ops 主题的凭证: 管理员登录的 authCode (JWT) 或 [env] wss_api_keys 中的 API key
+// 两者都为空时返回 false；claimable 主题需要钱包签名，见 VerifyWallet
+func Authenticate(authCode, apiKey string) bool {
+	if apiKey != "" {
+		for _, key := range strings.Split(config.Config().Env.WssApiKeys, ",") {
+			key = strings.TrimSpace(key)
+			if key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+				return true
+			}
+		}
+		return false
+	}
+	if authCode != "" {
+		_, ok := models.NewAdmin().VerifyToken(authCode)
+		return ok
+	}
+	return false
+}
+
+// VerifyWallet 校验钱包对 models.WalletNonceMessage 的签名 (personal_sign)，通过后 nonce 失效
+// 返回小写地址，签名不是该地址签发、nonce 不存在或已使用时返回 false
+func VerifyWallet(address, signature string) (string, bool) {
+	if !common.IsHexAddress(address) {
+		return "", false
+	}
+	nonce, ok := models.NewWalletNonce().Get(address)
+	if !ok {
+		return "", false
+	}
+	sig, err := hexutil.Decode(signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return "", false
+	}
+	// 钱包返回的 v 为 27 / 28
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(models.WalletNonceMessage(address, nonce))), sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != common.HexToAddress(address) {
+		return "", false
+	}
+	if !models.NewWalletNonce().Use(address, nonce) {
+		return "", false
+	}
+	return strings.ToLower(address), true
+}
+
+// authenticate 连接建立后通过 auth 消息或随订阅消息提供凭证，成功后可以订阅 jobs / ops
+func (s *Server) authenticate(authCode, apiKey string) bool {
+	if !Authenticate(authCode, apiKey) {
+		return false
+	}
+	s.authLock.Lock()
+	defer s.authLock.Unlock()
+	s.Authorized = true
+	return true
+}
+
+// authenticateWallet 连接建立后通过 auth 消息或随订阅消息提供钱包签名，成功后可以订阅该地址的 claimable 主题
+func (s *Server) authenticateWallet(address, signature string) bool {
+	wallet, ok := VerifyWallet(address, signature)
+	if !ok {
+		return false
+	}
+	s.authLock.Lock()
+	defer s.authLock.Unlock()
+	for _, w := range s.Wallets {
+		if w == wallet {
+			return true
+		}
+	}
+	s.Wallets = append(s.Wallets, wallet)
+	return true
+}
+
+// authorized 判断连接能否订阅主题，公开主题都可以订阅
+// claimable 只能订阅已证明的钱包地址，authCode / API key 不能订阅 claimable
+func (s *Server) authorized(topic string) bool {
+	if _, address, ok := ParseClaimableTopic(topic); ok {
+		return s.hasWallet(address)
+	}
+	return !PrivateTopic(topic) || s.IsAuthorized()
+}
+
+// IsAuthorized 连接是否已通过 jobs / ops 的凭证校验
+func (s *Server) IsAuthorized() bool {
+	s.authLock.RLock()
+	defer s.authLock.RUnlock()
+	return s.Authorized
+}
+
+// WalletList 已通过签名证明的钱包地址
+func (s *Server) WalletList() []string {
+	s.authLock.RLock()
+	defer s.authLock.RUnlock()
+	return append([]string{}, s.Wallets...)
+}
+
+func (s *Server) hasWallet(address string) bool {
+	s.authLock.RLock()
+	defer s.authLock.RUnlock()
+	for _, w := range s.Wallets {
+		if w == strings.ToLower(address) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package ws
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/ugorji/go/codec"
+)
+
+// 连接的消息编码，升级时通过子协议 (Sec-WebSocket-Protocol) 或 ?encoding= 协商，默认 json
+// msgpack 连接的服务端消息以二进制帧发送，内容与 json 相同，价格等高频主题可以节省移动端流量
+const (
+	EncodingJson    = "json"
+	EncodingMsgpack = "msgpack"
+)
+
+// Subprotocols 升级时支持的子协议，按服务端偏好排序
+var Subprotocols = []string{EncodingMsgpack, EncodingJson}
+
+var msgpackHandle = newMsgpackHandle()
+
+func newMsgpackHandle() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{}
+	h.WriteExt = true // 字符串使用 str8 / bin 类型 (新版 msgpack 规范)
+	h.RawToString = true
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	return h
+}
+
+// ValidEncoding 判断是否为支持的编码
+func ValidEncoding(encoding string) bool {
+	return encoding == EncodingJson || encoding == EncodingMsgpack
+}
+
+// Payload 按连接的编码返回消息内容，msgpack 在第一个需要的连接发送时编码一次，之后所有连接共用
+func (m *TopicMessage) Payload(encoding string) ([]byte, error) {
+	if encoding != EncodingMsgpack {
+		return m.Data, nil
+	}
+	m.binaryOnce.Do(func() {
+		m.binary, m.binaryErr = jsonToMsgpack(m.Data)
+	})
+	return m.binary, m.binaryErr
+}
+
+// jsonToMsgpack 把已编码的 JSON 消息转为 msgpack
+// 经过 JSON 转换而不是直接编码原始数据，保证两种编码的字段名和取值一致 (例如 decimal 仍为字符串)
+func jsonToMsgpack(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	var out []byte
+	err := codec.NewEncoderBytes(&out, msgpackHandle).Encode(jsonNumbers(v))
+	return out, err
+}
+
+// msgpackToJson 把客户端发来的 msgpack 消息转为 JSON，之后与文本消息一样处理
+func msgpackToJson(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := codec.NewDecoderBytes(data, msgpackHandle).Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// jsonNumbers 把 json.Number 转为整数或浮点数，否则 msgpack 中会编码为字符串
+func jsonNumbers(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = jsonNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = jsonNumbers(item)
+		}
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return i
+		}
+		if f, err := value.Float64(); err == nil {
+			return f
+		}
+		return value.String()
+	}
+	return v
+}
+
+// binaryMessage msgpack 的缓存，只由 Payload 读写
+type binaryMessage struct {
+	binaryOnce sync.Once
+	binary     []byte
+	binaryErr  error
+}
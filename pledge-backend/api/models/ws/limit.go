@@ -0,0 +1,64 @@
+package ws
+
+import (
+	"sync"
+)
+
+// ConnLimiter 连接数限制
+// 在协议升级之前占用名额，连接结束后释放，
+// 防止连接洪水拖垮广播循环和内存
+type ConnLimiter struct {
+	lock     sync.Mutex
+	total    int            // 当前连接总数
+	perIp    map[string]int // 每个 IP 的当前连接数
+	maxTotal int            // 最大连接总数，0 不限制
+	maxPerIp int            // 单 IP 最大连接数，0 不限制
+}
+
+// Limiter 全局连接数限制，WebSocket 和 SSE 共用，api 启动时按配置 SetLimit
+var Limiter = NewConnLimiter(0, 0)
+
+func NewConnLimiter(maxTotal, maxPerIp int) *ConnLimiter {
+	return &ConnLimiter{
+		perIp:    make(map[string]int),
+		maxTotal: maxTotal,
+		maxPerIp: maxPerIp,
+	}
+}
+
+// SetLimit 修改连接数限制，已建立的连接不受影响
+func (l *ConnLimiter) SetLimit(maxTotal, maxPerIp int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.maxTotal = maxTotal
+	l.maxPerIp = maxPerIp
+}
+
+// Acquire 占用一个连接名额，超出限制返回 false
+func (l *ConnLimiter) Acquire(ip string) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.maxTotal > 0 && l.total >= l.maxTotal {
+		return false
+	}
+	if l.maxPerIp > 0 && l.perIp[ip] >= l.maxPerIp {
+		return false
+	}
+	l.total++
+	l.perIp[ip]++
+	return true
+}
+
+// Release 释放一个连接名额
+func (l *ConnLimiter) Release(ip string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.perIp[ip] <= 1 {
+		delete(l.perIp, ip)
+	} else {
+		l.perIp[ip]--
+	}
+	if l.total > 0 {
+		l.total--
+	}
+}
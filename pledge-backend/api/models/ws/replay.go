@@ -0,0 +1,107 @@
+package ws
+
+import (
+	"encoding/json"
+	"math"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/log"
+)
+
+// replaySeqKey 广播消息序号的 Redis 计数器，多个 api 实例共用，重启后继续递增
+const replaySeqKey = "ws_seq"
+
+// replayKey 最近广播消息的 Redis 有序集合，score 为序号，保留 [env] wss_replay_size 条
+const replayKey = "ws_replay"
+
+// replayEntry 重放缓冲中的一条消息
+type replayEntry struct {
+	Seq   int64           `json:"seq"`
+	Topic string          `json:"topic"`
+	Data  json.RawMessage `json:"data"` // 已编码的完整 JSON 消息
+}
+
+// nextSeq 分配下一个广播序号，Redis 不可用时在本进程内递增
+// 只能在持有 seqLock 时调用
+func (m *ServerManager) nextSeq() int64 {
+	seq, err := db.RedisIncrExpire(replaySeqKey, 0)
+	if err != nil {
+		log.Logger.Sugar().Error("ws seq err ", err)
+	}
+	if err != nil || seq <= m.lastSeq {
+		seq = m.lastSeq + 1
+	}
+	m.lastSeq = seq
+	return seq
+}
+
+// storeReplay 把广播消息写入 Redis 重放缓冲，并删除超出 wss_replay_size 的旧消息
+func storeReplay(message *TopicMessage) {
+	size := int64(config.Config().Env.WssReplaySize)
+	if size <= 0 {
+		return
+	}
+	member, err := json.Marshal(replayEntry{Seq: message.Id, Topic: message.Topic, Data: message.Data})
+	if err == nil {
+		err = db.RedisZAdd(replayKey, message.Id, string(member))
+	}
+	if err == nil {
+		err = db.RedisZRemRangeByScore(replayKey, 0, message.Id-size)
+	}
+	if err != nil {
+		log.Logger.Sugar().Error("ws replay store err ", err)
+	}
+}
+
+// restoreReplay 断线重连时从 Redis 补发 LastEventId 之后的已订阅消息，放入 Send 通道，需在注册到 Hub 之前调用
+// LastEventId 随之前移，之后的消息由 Hub 从内存中补发
+//
+// 返回 false 表示需要推送当前快照: 新连接，或遗漏的消息已超出缓冲范围 (此时 LastEventId 清零，不做部分补发)
+// 重放缓冲关闭或 Redis 不可用时只使用 Hub 内存中的最近 HistorySize 条消息
+func (s *Server) restoreReplay() bool {
+	if s.LastEventId <= 0 {
+		return false
+	}
+	size := int64(config.Config().Env.WssReplaySize)
+	if size <= 0 {
+		return true
+	}
+
+	current, err := db.RedisGetInt64(replaySeqKey)
+	if err != nil {
+		log.Logger.Sugar().Error(s.Id+" ws replay err ", err)
+		return true
+	}
+	if s.LastEventId > current || s.LastEventId < current-size {
+		s.LastEventId = 0
+		return false
+	}
+
+	members, err := db.RedisZRangeByScore(replayKey, s.LastEventId+1, math.MaxInt64)
+	if err != nil {
+		log.Logger.Sugar().Error(s.Id+" ws replay err ", err)
+		return true
+	}
+	messages := make([]*TopicMessage, 0)
+	last := s.LastEventId
+	for _, member := range members {
+		entry := replayEntry{}
+		if json.Unmarshal([]byte(member), &entry) != nil {
+			continue
+		}
+		last = entry.Seq
+		if s.Subscribed(entry.Topic) {
+			messages = append(messages, &TopicMessage{Id: entry.Seq, Topic: entry.Topic, Data: entry.Data})
+		}
+	}
+	// 至少留一半缓冲给之后的广播
+	if len(messages) > cap(s.Send)/2 {
+		s.LastEventId = 0
+		return false
+	}
+	for _, message := range messages {
+		s.Send <- message
+	}
+	s.LastEventId = last
+	return true
+}
@@ -0,0 +1,131 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"sync"
+	"time"
+)
+
+// RpcMaxInFlight 单个连接同时处理的 RPC 请求数，超出时直接返回限流错误
+const RpcMaxInFlight = 8
+
+// RpcHandler RPC 方法的处理函数，返回值作为响应的 result
+// 出错时返回 statecode.New/Wrap 的错误，code 和 details 原样返回给客户端
+type RpcHandler func(ctx context.Context, params json.RawMessage) (interface{}, error)
+
+// RpcResponse RPC 请求的响应，id 与请求相同，result 和 error 只有一个
+// 例如: {"id":1,"result":[...]} 或 {"id":1,"error":{"code":1404,"message":"method not found"}}
+type RpcResponse struct {
+	Id     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result,omitempty"`
+	Error  *RpcError       `json:"error,omitempty"`
+}
+
+// RpcError RPC 错误，code 使用 statecode 中的错误码
+type RpcError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+var (
+	rpcMethods    = make(map[string]RpcHandler)
+	rpcMethodLock sync.RWMutex
+)
+
+// RegisterMethod 注册 RPC 方法，重复注册时覆盖
+// ws 包不能依赖 services，具体的方法在 routes 中注册
+func RegisterMethod(method string, handler RpcHandler) {
+	rpcMethodLock.Lock()
+	defer rpcMethodLock.Unlock()
+	rpcMethods[method] = handler
+}
+
+func lookupMethod(method string) (RpcHandler, bool) {
+	rpcMethodLock.RLock()
+	defer rpcMethodLock.RUnlock()
+	handler, ok := rpcMethods[method]
+	return handler, ok
+}
+
+// handleRpc 在独立协程中执行 RPC 请求并回复，不阻塞读协程
+// 每个连接最多 RpcMaxInFlight 个请求同时执行，处理时限与 HTTP 接口相同 ([env] request_timeout)
+func (s *Server) handleRpc(message *ClientMessage) {
+	select {
+	case s.rpcSlots() <- struct{}{}:
+	default:
+		s.sendRpcError(message.Id, statecode.New(statecode.TooManyRequests))
+		return
+	}
+
+	go func() {
+		defer func() {
+			<-s.rpcSlots()
+			if recoverRes := recover(); recoverRes != nil {
+				log.Logger.Sugar().Error(s.Id+" rpc recover ", message.Method, recoverRes)
+				s.sendRpcError(message.Id, statecode.New(statecode.CommonErrServerErr))
+			}
+		}()
+
+		handler, ok := lookupMethod(message.Method)
+		if !ok {
+			s.sendRpcError(message.Id, statecode.New(statecode.WsMethodErr))
+			return
+		}
+
+		ctx := context.Background()
+		if timeout := config.Config().Env.RequestTimeout; timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+			defer cancel()
+		}
+		result, err := handler(ctx, message.Params)
+		if err != nil {
+			if e := statecode.FromError(err); e.Code == statecode.CommonErrServerErr && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = statecode.Wrap(statecode.RequestTimeout, e.Cause)
+			}
+			s.sendRpcError(message.Id, err)
+			return
+		}
+		s.sendRpcResponse(RpcResponse{Id: message.Id, Result: result})
+	}()
+}
+
+// rpcSlots 连接的 RPC 并发名额，首次使用时创建
+func (s *Server) rpcSlots() chan struct{} {
+	s.rpcOnce.Do(func() {
+		s.rpcInFlight = make(chan struct{}, RpcMaxInFlight)
+	})
+	return s.rpcInFlight
+}
+
+// sendRpcError 按连接的语言回复错误
+func (s *Server) sendRpcError(id json.RawMessage, err error) {
+	e := statecode.FromError(err)
+	if e.Cause != nil {
+		log.Logger.Sugar().Error(s.Id+" rpc err ", e.Code, e.Cause)
+	}
+	s.sendRpcResponse(RpcResponse{
+		Id:    id,
+		Error: &RpcError{Code: e.Code, Message: statecode.GetMsg(e.Code, s.Lang), Details: e.Details},
+	})
+}
+
+func (s *Server) sendRpcResponse(res RpcResponse) {
+	if res.Id == nil {
+		res.Id = json.RawMessage("null")
+	}
+	dataBytes, err := json.Marshal(res)
+	if err != nil {
+		log.Logger.Sugar().Error(s.Id+" rpc marshal err ", err)
+		return
+	}
+	if err = s.write(dataBytes); err != nil {
+		log.Logger.Sugar().Error(s.Id+" rpc write err ", err)
+	}
+}
@@ -0,0 +1,82 @@
+package ws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"pledge-backend/api/models"
+	"pledge-backend/log"
+	"time"
+)
+
+// ServeSSE 以 Server-Sent Events 方式向客户端推送已订阅主题的消息
+//
+// 与 WebSocket 连接共用同一个 Hub，区别只在于写出方式:
+//   - 每条广播以 "id: {序号}\nevent: {主题}\ndata: {消息}\n\n" 写出
+//   - 未携带 Last-Event-ID 或遗漏的消息已超出重放缓冲的连接先推送各主题的当前快照（无 id）
+//   - 定时写出注释行保活，防止代理断开空闲连接
+//
+// 阻塞直到客户端断开或连接被 Hub 注销
+func (s *Server) ServeSSE(w http.ResponseWriter, done <-chan struct{}) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("streaming unsupported")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	replayed := s.restoreReplay()
+	Manager.Register(s)
+	defer Manager.Unregister(s)
+
+	// 新连接推送当前快照，断线重连的补发遗漏的消息 (Redis 重放缓冲和 Hub 内存)
+	if !replayed {
+		topics := s.TopicList()
+		if models.CurrentMaintenance().Enabled {
+			topics = append(topics, TopicMaintenance)
+		}
+		for _, topic := range topics {
+			data, err := Snapshot(topic)
+			if err != nil {
+				log.Logger.Sugar().Error(s.Id+" sse snapshot err ", topic, err)
+				continue
+			}
+			dataBytes, err := json.Marshal(Message{Code: SuccessCode, Topic: topic, Data: data})
+			if err != nil {
+				continue
+			}
+			if _, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", topic, dataBytes); err != nil {
+				return err
+			}
+		}
+		flusher.Flush()
+	}
+
+	ticker := time.NewTicker(pingInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return nil
+		case message, ok := <-s.Send:
+			if !ok {
+				return nil
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", message.Id, message.Topic, message.Data); err != nil {
+				return err
+			}
+			flusher.Flush()
+			s.touch()
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return err
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/kucoin"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TopicPrice PLGR 价格主题，连接建立后默认订阅
+const TopicPrice = "price"
+
+// TopicPricePrefix 交易对价格主题前缀，格式: price:{symbol}，例如 price:PLGR-USDT
+const TopicPricePrefix = "price:"
+
+// TopicPoolPrefix 池子主题前缀，格式: pool:{chainId}，例如 pool:97
+const TopicPoolPrefix = "pool:"
+
+// TopicDeadlinePrefix 池子结算倒计时主题前缀，格式: deadline:{chainId}，例如 deadline:97
+const TopicDeadlinePrefix = "deadline:"
+
+// TopicMaintenance 维护模式通知，所有连接都会收到，无需订阅
+const TopicMaintenance = "maintenance"
+
+// TopicJobs 定时任务执行记录，私有主题，需要 authCode 或 API key
+const TopicJobs = "jobs"
+
+// TopicOps 运维事件: 任务结果、喂价交易状态、节点健康变化和告警，私有主题，需要 authCode 或 API key
+const TopicOps = "ops"
+
+// TopicClaimablePrefix 钱包可提取金额主题前缀，私有主题，格式: claimable:{chainId}:{address}，地址为小写
+// 只有对 address 签名证明了所有权的连接可以订阅，见 VerifyWallet
+const TopicClaimablePrefix = "claimable:"
+
+// PrivateTopic 判断是否为私有主题，jobs / ops 需要 authCode 或 API key，claimable 需要钱包签名，其他主题公开
+func PrivateTopic(topic string) bool {
+	return topic == TopicJobs || topic == TopicOps || strings.HasPrefix(topic, TopicClaimablePrefix)
+}
+
+// NormalizeTopic 统一主题的写法，claimable 主题的地址转为小写，保证与广播的主题一致
+func NormalizeTopic(topic string) string {
+	if strings.HasPrefix(topic, TopicClaimablePrefix) {
+		return strings.ToLower(topic)
+	}
+	return topic
+}
+
+// ClaimableTopic 钱包可提取金额主题
+func ClaimableTopic(chainId int, address string) string {
+	return TopicClaimablePrefix + strconv.Itoa(chainId) + ":" + strings.ToLower(address)
+}
+
+// ParseClaimableTopic 解析 claimable:{chainId}:{address}
+func ParseClaimableTopic(topic string) (int, string, bool) {
+	parts := strings.Split(strings.TrimPrefix(topic, TopicClaimablePrefix), ":")
+	if !strings.HasPrefix(topic, TopicClaimablePrefix) || len(parts) != 2 {
+		return 0, "", false
+	}
+	chainId, err := strconv.Atoi(parts[0])
+	if err != nil || !common.IsHexAddress(parts[1]) {
+		return 0, "", false
+	}
+	return chainId, parts[1], true
+}
+
+// ValidTopic 判断主题是否合法
+func ValidTopic(topic string) bool {
+	if topic == TopicPrice {
+		return true
+	}
+	if strings.HasPrefix(topic, TopicPricePrefix) {
+		symbol := strings.TrimPrefix(topic, TopicPricePrefix)
+		for _, s := range kucoin.Symbols() {
+			if s == symbol {
+				return true
+			}
+		}
+		return false
+	}
+	if strings.HasPrefix(topic, TopicPoolPrefix) {
+		_, err := strconv.Atoi(strings.TrimPrefix(topic, TopicPoolPrefix))
+		return err == nil
+	}
+	if strings.HasPrefix(topic, TopicDeadlinePrefix) {
+		_, err := strconv.Atoi(strings.TrimPrefix(topic, TopicDeadlinePrefix))
+		return err == nil
+	}
+	if topic == TopicJobs || topic == TopicOps {
+		return true
+	}
+	if strings.HasPrefix(topic, TopicClaimablePrefix) {
+		_, _, ok := ParseClaimableTopic(topic)
+		return ok
+	}
+	return false
+}
+
+// Snapshot 获取主题的当前数据，用于连接建立或订阅时立即推送
+func Snapshot(topic string) (interface{}, error) {
+	if topic == TopicPrice {
+		return kucoin.PlgrPrice, nil
+	}
+	if topic == TopicMaintenance {
+		return models.CurrentMaintenance(), nil
+	}
+	if strings.HasPrefix(topic, TopicPricePrefix) {
+		price, _ := kucoin.GetPrice(strings.TrimPrefix(topic, TopicPricePrefix))
+		return price, nil
+	}
+	if strings.HasPrefix(topic, TopicPoolPrefix) {
+		chainId, err := strconv.Atoi(strings.TrimPrefix(topic, TopicPoolPrefix))
+		if err != nil {
+			return nil, err
+		}
+		var result []models.PoolBaseInfoRes
+		err = models.NewPoolBases().PoolBaseInfo(context.Background(), chainId, models.ArchivedExclude, &result)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	if strings.HasPrefix(topic, TopicDeadlinePrefix) {
+		// 最近一天的倒计时通知
+		result := make([]models.PoolDeadline, 0)
+		err := models.NewPoolDeadline().Since(strings.TrimPrefix(topic, TopicDeadlinePrefix), 0, &result)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	if topic == TopicJobs {
+		// 最近的执行记录
+		result := make([]models.JobRun, 0)
+		if err := models.NewJobRun().List("", jobsSnapshotSize, &result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	if topic == TopicOps {
+		// 最近一段时间的事件，最多 opsSnapshotSize 条
+		result := make([]models.OpsEvent, 0)
+		after := time.Now().Add(-opsSnapshotWindow).UnixMilli()
+		if err := models.NewOpsEvent().Since(after, &result); err != nil {
+			return nil, err
+		}
+		if len(result) > opsSnapshotSize {
+			result = result[len(result)-opsSnapshotSize:]
+		}
+		return result, nil
+	}
+	for prefix, snapshot := range snapshots {
+		if strings.HasPrefix(topic, prefix) {
+			return snapshot(topic)
+		}
+	}
+	return nil, errors.New("unknown topic " + topic)
+}
+
+// jobsSnapshotSize 订阅 jobs 时推送的最近执行记录条数
+const jobsSnapshotSize = 20
+
+// 订阅 ops 时推送的最近事件的时间范围和条数
+const (
+	opsSnapshotWindow = 10 * time.Minute
+	opsSnapshotSize   = 100
+)
+
+// snapshots 由 ws 包之外提供的主题快照，key 为主题前缀
+var snapshots = map[string]func(topic string) (interface{}, error){}
+
+// RegisterSnapshot 注册主题快照，需在 StartServer 之前调用
+// 用于依赖 services 的主题 (例如 claimable)，ws 包不能引用 services
+func RegisterSnapshot(prefix string, snapshot func(topic string) (interface{}, error)) {
+	snapshots[prefix] = snapshot
+}
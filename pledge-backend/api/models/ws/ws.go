@@ -8,15 +8,22 @@
  * 它是"交易所 -> 后端 -> 前端"实时数据链路的最后一环。
  *
  * 【数据流向】
- * kucoin.go (PlgrPriceChan) ---> StartServer() ---> 所有前端 WebSocket 客户端
+ * kucoin.go (PriceChan) ---> StartServer() ---> 订阅了对应主题的前端 WebSocket/SSE 客户端
  *
  * 【主要职责】
- * 1. 连接管理: 维护所有在线用户的 WebSocket 连接池 (ServerManager)
- * 2. 心跳保活: 实现 Ping/Pong 机制，自动断开超时连接
- * 3. 消息广播: 从 PlgrPriceChan 读取价格，广播给所有客户端
+ * 1. 连接管理: 由 Hub (ServerManager.Run) 通过 Register/Unregister 通道维护连接池
+ * 2. 心跳保活: 服务端定时发送 WebSocket ping 控制帧，收到 pong 帧（或旧客户端的文本 "ping"）
+ *    即刷新读超时，超时未收到任何心跳则断开连接；每次写入都设置写超时
+ * 3. 消息广播: 从 PriceChan 读取价格，编码一次后投递到 Broadcast 通道，
+ *    Hub 再分发到每个连接的 Send 通道，由连接自己的写协程发送
+ * 4. 慢客户端剔除: Send 缓冲区写满的连接会被 Hub 直接断开，不会拖慢整体广播
+ * 5. SSE 兜底: 无法升级 WebSocket 的客户端通过 sse.go 接入同一个 Hub，
+ *    Hub 保留最近 HistorySize 条广播，供 Last-Event-ID 断线补发
+ * 6. 断线重放: 每条广播带递增序号 seq，并写入 Redis 重放缓冲 (见 replay.go)，
+ *    WebSocket 客户端重连时携带 ?last_seq= 即可补收断线期间的消息，不必重新拉取快照
  *
  * 【调用时机】
- * 在 pledge_api.go 的 main() 函数中以 Goroutine 方式启动:
+ * 在 cmd/api.go 的 runApi() 中以 Goroutine 方式启动:
  *     go ws.StartServer()
  *
  * 【WebSocket 消息格式】
@@ -24,6 +31,16 @@
  *   "code": 0,      // 0=成功, 1=Pong 响应, -1=错误
  *   "data": "..."   // 价格字符串或错误信息
  * }
+ *
+ * 【消息编码】
+ * 默认 JSON 文本帧；升级时协商子协议 msgpack (或 ?encoding=msgpack) 后服务端消息改为 msgpack 二进制帧，
+ * 内容与 JSON 相同。广播消息的 msgpack 编码由第一个需要的连接生成一次，之后所有连接共用 (见 encoding.go)
+ *
+ * 【RPC 请求】
+ * 客户端可以在同一个连接上发送请求，不必再混用 REST 轮询 (见 rpc.go):
+ *     {"id":1,"method":"getPoolBaseInfo","params":{"chainId":97}}
+ * 响应带相同的 id，与推送消息通过是否有 id 区分:
+ *     {"id":1,"result":[...]} 或 {"id":1,"error":{"code":1404,"message":"method not found"}}
  * ==================================================================================
  */
 
@@ -32,10 +49,13 @@ package ws
 import (
 	"encoding/json"
 	"errors"
+	"pledge-backend/api/models"
 	"pledge-backend/api/models/kucoin"
 	"pledge-backend/config"
 	"pledge-backend/log"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -64,78 +84,481 @@ const ErrorCode = -1
 // Server 单个 WebSocket 连接的封装
 // 每个连接的前端用户对应一个 Server 实例
 type Server struct {
-	sync.Mutex                 // 互斥锁，保证并发安全（发送消息时需要加锁）
-	Id         string          // 连接唯一标识符（通常是用户 ID 或随机生成的 UUID）
-	Socket     *websocket.Conn // 底层 WebSocket 连接对象
-	Send       chan []byte     // 发送消息的缓冲通道（用于异步发送）
-	LastTime   int64           // 最后一次收到心跳的 Unix 时间戳
+	sync.Mutex                     // 互斥锁，保证同一时刻只有一个协程写 Socket
+	Id          string             // 连接唯一标识符（通常是用户 ID 或随机生成的 UUID）
+	Socket      *websocket.Conn    // 底层 WebSocket 连接对象，SSE 连接为 nil
+	Send        chan *TopicMessage // 待发送的消息缓冲通道（已编码的完整消息），只由 Hub 关闭
+	LastEventId int64              // 断线重连时客户端最后收到的序号 (SSE Last-Event-ID / WebSocket ?last_seq=)，注册前后补发之后的消息
+	LastTime    int64              // 最后一次收到心跳的 Unix 时间戳，连接建立后通过 touch / lastActive 原子读写
+	Ip          string             // 客户端 IP
+	ConnectAt   int64              // 建立连接的 Unix 时间戳
+	Topics      []string           // 已订阅的主题，连接建立后只能通过 Subscribe/Unsubscribe 修改
+	topicLock   sync.RWMutex       // 保护 Topics
+	Lang        int                // RPC 错误消息的语言，升级时按 Accept-Language 选择
+	Encoding    string             // 消息编码 json / msgpack，升级时协商，SSE 只支持 json
+	Authorized  bool               // 是否可以订阅 jobs / ops，升级时或通过 auth 消息校验 authCode / API key，见 auth.go
+	Wallets     []string           // 已通过签名证明的钱包地址 (小写)，可以订阅这些地址的 claimable 主题
+	authLock    sync.RWMutex       // 保护 Authorized 和 Wallets
+	rpcOnce     sync.Once          // 创建 rpcInFlight
+	rpcInFlight chan struct{}      // 正在执行的 RPC 请求名额，见 rpc.go
+}
+
+// ConnInfo 连接元信息，用于管理端查看在线连接
+type ConnInfo struct {
+	Id         string   `json:"id"`
+	Ip         string   `json:"ip"`
+	ConnectAt  int64    `json:"connect_at"`
+	LastTime   int64    `json:"last_time"`
+	Topics     []string `json:"topics"`
+	Encoding   string   `json:"encoding"`
+	Authorized bool     `json:"authorized"`
+	Wallets    []string `json:"wallets"`
 }
 
-// ServerManager WebSocket 连接池管理器
-// 维护所有在线用户的连接，支持并发安全的读写操作
+// ServerManager WebSocket 连接池管理器（Hub）
+// Servers 只由 Run() 所在的协程写入，其他地方只读
 type ServerManager struct {
-	Servers    sync.Map     // 连接池，key=连接ID，value=*Server（使用 sync.Map 保证并发安全）
-	Broadcast  chan []byte  // 广播通道（当前未使用，预留给未来扩展）
-	Register   chan *Server // 注册通道（当前未使用，预留给未来扩展）
-	Unregister chan *Server // 注销通道（当前未使用，预留给未来扩展）
+	Servers   sync.Map           // 连接池，key=连接ID，value=*Server
+	Broadcast chan *TopicMessage // 广播通道，写入已编码的消息，Hub 分发给订阅了该主题的连接
+	registry  chan registryEvent // 注册 / 注销事件，同一个通道保证同一连接的注册先于注销处理
+
+	history []*TopicMessage // 最近的广播消息，用于断线补发，只在 Run() 协程中读写
+
+	seqLock sync.Mutex // 保证序号分配和投递 Broadcast 的顺序一致
+	lastSeq int64      // 最近一条广播消息的序号，持有 seqLock 时读写
 }
 
 // Message WebSocket 消息格式
 // 所有发送给前端的消息都会被序列化为这个 JSON 结构
 type Message struct {
-	Code int    `json:"code"` // 状态码: 0=成功, 1=Pong, -1=错误
-	Data string `json:"data"` // 消息内容: 价格字符串 或 "pong" 或 错误信息
+	Code  int         `json:"code"`            // 状态码: 0=成功, 1=Pong, -1=错误
+	Topic string      `json:"topic,omitempty"` // 消息所属主题，心跳和错误消息为空
+	Data  interface{} `json:"data"`            // 消息内容: 价格字符串、池子快照、"pong" 或 错误信息
+	Seq   int64       `json:"seq,omitempty"`   // 广播消息的序号，断线重连时作为 last_seq 携带；快照和控制消息为空
+}
+
+// registryEvent 连接的注册或注销
+type registryEvent struct {
+	server   *Server
+	register bool
+}
+
+// TopicMessage 投递给 Hub 的广播消息
+type TopicMessage struct {
+	Id    int64  // 消息序号，与消息中的 seq 相同，SSE 作为事件 id 下发
+	Topic string // 主题，只发送给订阅了该主题的连接
+	Data  []byte // 已编码的完整 JSON 消息
+
+	binaryMessage // msgpack 编码，见 Payload
+}
+
+// ClientMessage 客户端发来的控制消息或 RPC 请求
+// 例如: {"op":"subscribe","topic":"pool:97"}、{"op":"auth","apiKey":"..."}、{"op":"auth","address":"0x...","signature":"0x..."}
+// 或: {"id":1,"method":"getPoolBaseInfo","params":{"chainId":97}}
+type ClientMessage struct {
+	Op    string `json:"op"`    // subscribe / unsubscribe / auth
+	Topic string `json:"topic"` // 主题
+
+	AuthCode string `json:"authCode"` // jobs / ops 的凭证，auth 消息或订阅消息中携带，二选一
+	ApiKey   string `json:"apiKey"`
+
+	Address   string `json:"address"`   // claimable 主题的钱包地址，与 signature 一起在 auth 消息或订阅消息中携带
+	Signature string `json:"signature"` // 钱包对 /user/{address}/nonce 返回的 message 的签名 (personal_sign)
+
+	Id     json.RawMessage `json:"id"`     // RPC 请求 ID，原样放入响应，客户端据此对应请求
+	Method string          `json:"method"` // RPC 方法，不为空时按 RPC 请求处理
+	Params json.RawMessage `json:"params"` // RPC 参数
 }
 
 // ============================================================
 // 全局变量
 // ============================================================
 
+// HistorySize Hub 保留的最近广播消息条数，超出这个范围的断线消息无法补发
+const HistorySize = 256
+
 // Manager 全局连接池管理器
-// 整个应用只有一个 Manager 实例，管理所有 WebSocket 连接
-var Manager = ServerManager{}
+// 整个应用只有一个 Manager 实例，管理所有 WebSocket 和 SSE 连接
+var Manager = ServerManager{
+	Broadcast: make(chan *TopicMessage, 16),
+	registry:  make(chan registryEvent, 32),
+}
 
-// UserPingPongDurTime 心跳超时时间（秒）
+// userPingPongDurTime 心跳超时时间（秒）
 // 如果超过这个时间没有收到客户端的 Ping，服务器会主动断开连接
-// 从配置文件读取: config.Config.Env.WssTimeoutDuration
-var UserPingPongDurTime = config.Config.Env.WssTimeoutDuration
+// 从配置文件读取: config.Config().Env.WssTimeoutDuration
+func userPingPongDurTime() int64 {
+	return config.Config().Env.WssTimeoutDuration
+}
+
+// pingInterval 服务端发送 ping 控制帧的间隔，需小于心跳超时时间
+func pingInterval() time.Duration {
+	return time.Duration(config.Config().Env.WssPingInterval) * time.Second
+}
+
+// writeTimeout 单次写入的超时时间，防止写阻塞在无响应的连接上
+func writeTimeout() time.Duration {
+	return time.Duration(config.Config().Env.WssWriteTimeout) * time.Second
+}
+
+// broadcastInterval 价格广播的最小间隔，间隔内的多次价格变动只广播最后一个
+func broadcastInterval() time.Duration {
+	return time.Duration(config.Config().Env.WssBroadcastInterval) * time.Millisecond
+}
+
+// ============================================================
+// ServerManager 方法
+// ============================================================
+
+// Run Hub 主循环
+//
+// 连接池统一以 Server.Id 作为 key 存取，
+// 所有连接的注册、注销、广播分发都在这一个协程里完成，
+// 因此关闭 Send 通道的动作不会出现并发重复关闭。
+func (m *ServerManager) Run() {
+	for {
+		select {
+		case event := <-m.registry:
+			if event.register {
+				m.Servers.Store(event.server.Id, event.server)
+				m.replay(event.server)
+			} else {
+				m.remove(event.server)
+			}
+
+		case message := <-m.Broadcast:
+			m.history = append(m.history, message)
+			if len(m.history) > HistorySize {
+				m.history = m.history[len(m.history)-HistorySize:]
+			}
+
+			m.Servers.Range(func(key, value interface{}) bool {
+				s := value.(*Server)
+				// 注册前已从 Redis 补发过的消息不再重复发送
+				if !s.Subscribed(message.Topic) || message.Id <= s.LastEventId {
+					return true
+				}
+				select {
+				case s.Send <- message:
+				default:
+					// Send 缓冲区已满，说明客户端消费太慢，直接剔除
+					log.Logger.Sugar().Error(s.Id, " send buffer full, evicted")
+					m.remove(s)
+				}
+				return true
+			})
+		}
+	}
+}
+
+// replay 向重连的客户端补发 LastEventId 之后的已订阅消息
+// 只能在 Run() 协程中调用
+func (m *ServerManager) replay(s *Server) {
+	if s.LastEventId <= 0 {
+		return
+	}
+	for _, message := range m.history {
+		if message.Id <= s.LastEventId || !s.Subscribed(message.Topic) {
+			continue
+		}
+		select {
+		case s.Send <- message:
+		default:
+			log.Logger.Sugar().Error(s.Id, " send buffer full, evicted")
+			m.remove(s)
+			return
+		}
+	}
+}
+
+// remove 从连接池移除连接并关闭其 Send 通道
+// 只能在 Run() 协程中调用
+func (m *ServerManager) remove(s *Server) {
+	if _, ok := m.Servers.LoadAndDelete(s.Id); ok {
+		close(s.Send)
+	}
+}
+
+// SubscribedTopics 当前有连接订阅的、以 prefix 开头的主题，用于只为有订阅者的主题查询和推送数据
+func (m *ServerManager) SubscribedTopics(prefix string) []string {
+	seen := make(map[string]bool)
+	topics := make([]string, 0)
+	m.Servers.Range(func(key, value interface{}) bool {
+		for _, topic := range value.(*Server).TopicList() {
+			if strings.HasPrefix(topic, prefix) && !seen[topic] {
+				seen[topic] = true
+				topics = append(topics, topic)
+			}
+		}
+		return true
+	})
+	return topics
+}
+
+// Connections 返回当前所有在线连接的元信息
+func (m *ServerManager) Connections() []ConnInfo {
+	conns := make([]ConnInfo, 0)
+	m.Servers.Range(func(key, value interface{}) bool {
+		s := value.(*Server)
+		conns = append(conns, ConnInfo{
+			Id:         s.Id,
+			Ip:         s.Ip,
+			ConnectAt:  s.ConnectAt,
+			LastTime:   s.lastActive(),
+			Topics:     s.TopicList(),
+			Encoding:   s.Encoding,
+			Authorized: s.IsAuthorized(),
+			Wallets:    s.WalletList(),
+		})
+		return true
+	})
+	return conns
+}
+
+// CloseConnection 通知客户端后强制断开指定连接
+// 返回 false 表示连接不存在
+func (m *ServerManager) CloseConnection(id string) bool {
+	value, ok := m.Servers.Load(id)
+	if !ok {
+		return false
+	}
+	s := value.(*Server)
+	if s.Socket != nil {
+		s.SendToClient("connection closed by server", ErrorCode)
+	}
+	m.Unregister(s)
+	return true
+}
+
+// Register 新连接建立后交给 Hub 注册
+func (m *ServerManager) Register(s *Server) {
+	m.registry <- registryEvent{server: s, register: true}
+}
+
+// Unregister 连接断开后通知 Hub 注销，与 Register 经过同一个通道，不会先于注册被处理
+func (m *ServerManager) Unregister(s *Server) {
+	m.registry <- registryEvent{server: s}
+}
+
+// BroadcastMessage 分配序号、编码一次消息并投递给 Hub，广播给订阅了 topic 的连接，同时写入重放缓冲
+func (m *ServerManager) BroadcastMessage(topic string, data interface{}, code int) {
+	m.seqLock.Lock()
+	defer m.seqLock.Unlock()
+
+	seq := m.nextSeq()
+	dataBytes, err := json.Marshal(Message{
+		Code:  code,
+		Topic: topic,
+		Data:  data,
+		Seq:   seq,
+	})
+	if err != nil {
+		log.Logger.Sugar().Error("BroadcastMessage marshal err ", err)
+		return
+	}
+	message := &TopicMessage{Id: seq, Topic: topic, Data: dataBytes}
+	m.Broadcast <- message
+	storeReplay(message)
+}
 
 // ============================================================
 // Server 方法
 // ============================================================
 
-// SendToClient 向客户端发送消息
+// SendToClient 向客户端直接发送一条消息
 //
 // 参数:
-//   - data: 消息内容（价格字符串、"pong"、错误信息等）
+//   - data: 消息内容（"pong"、错误信息等）
 //   - code: 状态码（SuccessCode/PongCode/ErrorCode）
 //
-// 线程安全: 此方法使用互斥锁保证并发安全
-func (s *Server) SendToClient(data string, code int) {
-	// 加锁，防止多个 Goroutine 同时写入 Socket
-	s.Lock()
-	defer s.Unlock()
-
-	// 构造消息结构并序列化为 JSON
-	dataBytes, err := json.Marshal(Message{
+// 广播消息走 Send 通道，只有连接自身的控制类消息才直接调用此方法
+func (s *Server) SendToClient(data interface{}, code int) {
+	s.sendMessage(Message{
 		Code: code,
 		Data: data,
 	})
+}
+
+// SendSnapshot 向客户端推送指定主题的当前快照
+func (s *Server) SendSnapshot(topic string) {
+	data, err := Snapshot(topic)
+	if err != nil {
+		log.Logger.Sugar().Error(s.Id+" SendSnapshot err ", topic, err)
+		return
+	}
+	s.sendMessage(Message{
+		Code:  SuccessCode,
+		Topic: topic,
+		Data:  data,
+	})
+}
 
-	// 通过 WebSocket 发送文本消息
-	err = s.Socket.WriteMessage(websocket.TextMessage, dataBytes)
+// sendMessage 编码并直接写入一条消息
+func (s *Server) sendMessage(message Message) {
+	dataBytes, err := json.Marshal(message)
+	if err != nil {
+		log.Logger.Sugar().Error(s.Id+" SendToClient marshal err ", err)
+		return
+	}
+
+	err = s.write(dataBytes)
 	if err != nil {
 		// 发送失败（通常是连接已断开）
 		log.Logger.Sugar().Error(s.Id+" SendToClient err ", err)
 	}
 }
 
+// write 写入一条已编码的 JSON 消息，msgpack 连接先转换编码
+func (s *Server) write(dataBytes []byte) error {
+	if s.Encoding == EncodingMsgpack {
+		binary, err := jsonToMsgpack(dataBytes)
+		if err != nil {
+			return err
+		}
+		return s.writeFrame(websocket.BinaryMessage, binary)
+	}
+	return s.writeFrame(websocket.TextMessage, dataBytes)
+}
+
+// writeTopicMessage 写入一条广播消息，按连接的编码使用 Hub 中已编码的内容
+func (s *Server) writeTopicMessage(message *TopicMessage) error {
+	payload, err := message.Payload(s.Encoding)
+	if err != nil {
+		return err
+	}
+	if s.Encoding == EncodingMsgpack {
+		return s.writeFrame(websocket.BinaryMessage, payload)
+	}
+	return s.writeFrame(websocket.TextMessage, payload)
+}
+
+// writeFrame 加锁后写入一帧，每次写入都设置写超时
+func (s *Server) writeFrame(messageType int, dataBytes []byte) error {
+	s.Lock()
+	defer s.Unlock()
+	_ = s.Socket.SetWriteDeadline(time.Now().Add(writeTimeout()))
+	return s.Socket.WriteMessage(messageType, dataBytes)
+}
+
+// Subscribed 判断连接是否订阅了指定主题
+// 维护模式通知发送给所有连接
+func (s *Server) Subscribed(topic string) bool {
+	if topic == TopicMaintenance {
+		return true
+	}
+	s.topicLock.RLock()
+	defer s.topicLock.RUnlock()
+	for _, t := range s.Topics {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// TopicList 返回已订阅主题的副本
+func (s *Server) TopicList() []string {
+	s.topicLock.RLock()
+	defer s.topicLock.RUnlock()
+	return append([]string{}, s.Topics...)
+}
+
+// Subscribe 订阅主题，重复订阅忽略
+func (s *Server) Subscribe(topic string) {
+	if s.Subscribed(topic) {
+		return
+	}
+	s.topicLock.Lock()
+	defer s.topicLock.Unlock()
+	s.Topics = append(s.Topics, topic)
+}
+
+// Unsubscribe 取消订阅主题
+func (s *Server) Unsubscribe(topic string) {
+	s.topicLock.Lock()
+	defer s.topicLock.Unlock()
+	for i, t := range s.Topics {
+		if t == topic {
+			s.Topics = append(s.Topics[:i], s.Topics[i+1:]...)
+			return
+		}
+	}
+}
+
+// handleClientMessage 处理客户端的订阅/取消订阅消息和 RPC 请求
+// 订阅成功后立即推送该主题的当前快照
+func (s *Server) handleClientMessage(message []byte) {
+	clientMessage := ClientMessage{}
+	if err := json.Unmarshal(message, &clientMessage); err != nil {
+		return
+	}
+	if clientMessage.Method != "" {
+		s.handleRpc(&clientMessage)
+		return
+	}
+	if clientMessage.AuthCode != "" || clientMessage.ApiKey != "" || clientMessage.Signature != "" {
+		var ok bool
+		if clientMessage.Signature != "" {
+			ok = s.authenticateWallet(clientMessage.Address, clientMessage.Signature)
+		} else {
+			ok = s.authenticate(clientMessage.AuthCode, clientMessage.ApiKey)
+		}
+		if !ok {
+			s.SendToClient("unauthorized", ErrorCode)
+			return
+		}
+		if clientMessage.Op == "auth" {
+			s.SendToClient("authorized", SuccessCode)
+			return
+		}
+	}
+	topic := NormalizeTopic(clientMessage.Topic)
+	if !ValidTopic(topic) {
+		s.SendToClient("invalid topic "+clientMessage.Topic, ErrorCode)
+		return
+	}
+	switch clientMessage.Op {
+	case "subscribe":
+		if !s.authorized(topic) {
+			s.SendToClient("unauthorized topic "+clientMessage.Topic, ErrorCode)
+			return
+		}
+		s.Subscribe(topic)
+		s.SendSnapshot(topic)
+	case "unsubscribe":
+		s.Unsubscribe(topic)
+	}
+}
+
+// ping 发送 WebSocket ping 控制帧
+func (s *Server) ping() error {
+	s.Lock()
+	defer s.Unlock()
+	return s.Socket.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout()))
+}
+
+// touch 记录最后一次心跳的时间，读协程、SSE 写入和 Hub 之外的读取并发进行
+func (s *Server) touch() {
+	atomic.StoreInt64(&s.LastTime, time.Now().Unix())
+}
+
+// lastActive 最后一次心跳的 Unix 时间戳
+func (s *Server) lastActive() int64 {
+	return atomic.LoadInt64(&s.LastTime)
+}
+
+// heartbeat 记录一次心跳并延长读超时
+func (s *Server) heartbeat() {
+	s.touch()
+	_ = s.Socket.SetReadDeadline(time.Now().Add(time.Duration(userPingPongDurTime()) * time.Second))
+}
+
 // ReadAndWrite 处理单个连接的读写和心跳检测
 //
 // 这是每个连接的主循环函数，负责：
-// 1. 将连接注册到全局连接池
-// 2. 启动读取 Goroutine（接收客户端消息）
-// 3. 启动写入 Goroutine（从 Send 通道发送消息）
+// 1. 通过 Register 通道把连接交给 Hub
+// 2. 启动写入 Goroutine（从 Send 通道取消息发送给客户端）
+// 3. 启动读取 Goroutine（接收客户端消息）
 // 4. 主循环检测心跳超时
 //
 // 【生命周期】
@@ -143,39 +566,56 @@ func (s *Server) SendToClient(data string, code int) {
 // - 客户端断开连接
 // - 心跳超时
 // - 读写发生错误
+// - 被 Hub 当作慢客户端剔除（Send 通道被关闭）
 func (s *Server) ReadAndWrite() {
 
-	// 错误通道，用于在读/写 Goroutine 中传递错误到主循环
-	errChan := make(chan error)
+	// 错误通道，读/写协程各最多写入一次，带缓冲避免主循环退出后协程阻塞
+	errChan := make(chan error, 2)
+
+	replayed := s.restoreReplay()
+	Manager.Register(s)
 
-	// 将当前连接注册到全局连接池
-	// 这样 StartServer() 就能遍历到这个连接并推送消息
-	Manager.Servers.Store(s.Id, s)
+	// 新连接立即推送已订阅主题的当前快照，前端无需等待下一次价格变动；断线重连的补发遗漏的消息
+	if !replayed {
+		for _, topic := range s.TopicList() {
+			s.SendSnapshot(topic)
+		}
+		if models.CurrentMaintenance().Enabled {
+			s.SendSnapshot(TopicMaintenance)
+		}
+	}
 
-	// 延迟清理：函数退出时执行
+	// 延迟清理：通知 Hub 注销并关闭底层连接
 	defer func() {
-		// 从连接池中移除
-		Manager.Servers.Delete(s)
-		// 关闭 WebSocket 连接
+		Manager.Unregister(s)
 		_ = s.Socket.Close()
-		// 关闭发送通道
-		close(s.Send)
+		// 释放升级前占用的连接名额
+		Limiter.Release(s.Ip)
 	}()
 
 	// ============================================================
-	// 写入 Goroutine: 从 Send 通道读取消息并发送给客户端
+	// 写入 Goroutine: 从 Send 通道读取消息并发送给客户端，定时发送 ping 帧
 	// ============================================================
 	go func() {
+		ticker := time.NewTicker(pingInterval())
+		defer ticker.Stop()
 		for {
 			select {
 			case message, ok := <-s.Send:
 				if !ok {
-					// 通道已关闭，发送错误到主循环
-					errChan <- errors.New("write message error")
+					// Send 被 Hub 关闭（已注销或被剔除）
+					errChan <- errors.New("send channel closed")
+					return
+				}
+				if err := s.writeTopicMessage(message); err != nil {
+					errChan <- err
+					return
+				}
+			case <-ticker.C:
+				if err := s.ping(); err != nil {
+					errChan <- err
 					return
 				}
-				// 发送消息给客户端
-				s.SendToClient(string(message), SuccessCode)
 			}
 		}
 	}()
@@ -183,10 +623,15 @@ func (s *Server) ReadAndWrite() {
 	// ============================================================
 	// 读取 Goroutine: 接收客户端发来的消息
 	// ============================================================
+	s.heartbeat()
+	s.Socket.SetPongHandler(func(string) error {
+		s.heartbeat()
+		return nil
+	})
 	go func() {
 		for {
-			// 阻塞读取客户端消息
-			_, message, err := s.Socket.ReadMessage()
+			// 阻塞读取客户端消息，超过读超时未收到任何数据（含 pong 帧）会返回错误
+			messageType, message, err := s.Socket.ReadMessage()
 			if err != nil {
 				// 读取失败（通常是客户端断开连接）
 				log.Logger.Sugar().Error(s.Id+" ReadMessage err ", err)
@@ -194,16 +639,25 @@ func (s *Server) ReadAndWrite() {
 				return
 			}
 
-			// 处理心跳请求
+			// msgpack 客户端可以发送二进制帧，转为 JSON 后同样处理
+			if messageType == websocket.BinaryMessage {
+				if message, err = msgpackToJson(message); err != nil {
+					s.SendToClient("invalid msgpack message", ErrorCode)
+					continue
+				}
+			}
+
+			// 兼容旧客户端的文本心跳
 			// 兼容多种 Ping 格式: ping, "ping", 'ping'
 			if string(message) == "ping" || string(message) == `"ping"` || string(message) == "'ping'" {
 				// 更新最后心跳时间
-				s.LastTime = time.Now().Unix()
+				s.heartbeat()
 				// 回复 Pong
 				s.SendToClient("pong", PongCode)
+				continue
 			}
-			// 继续读取下一条消息
-			continue
+
+			s.handleClientMessage(message)
 		}
 	}()
 
@@ -215,7 +669,7 @@ func (s *Server) ReadAndWrite() {
 		// 每秒检查一次心跳状态
 		case <-time.After(time.Second):
 			// 计算距离上次心跳的时间差
-			if time.Now().Unix()-s.LastTime >= UserPingPongDurTime {
+			if time.Now().Unix()-s.lastActive() >= userPingPongDurTime() {
 				// 超时！通知客户端并断开连接
 				s.SendToClient("heartbeat timeout", ErrorCode)
 				return // 退出函数，触发 defer 清理
@@ -237,33 +691,38 @@ func (s *Server) ReadAndWrite() {
 //
 // 【核心功能】
 // 这是一个后台守护协程，负责:
-// 1. 监听 kucoin.PlgrPriceChan 通道（从 KuCoin 接收价格更新）
-// 2. 将新价格广播给所有在线的 WebSocket 客户端
+//  1. 启动 Hub 主循环 (Manager.Run)
+//  2. 监听 kucoin.PriceChan 通道（从 KuCoin 接收各交易对价格更新）
+//  3. 合并价格更新: 每个 broadcastInterval 最多广播一次，每个交易对只发送最新价格，
+//     消息编码一次后交给 Hub 分发给订阅了对应主题的客户端
+//     PLGR-USDT 同时发送到旧的 "price" 主题，兼容现有前端
 //
 // 【调用方式】
 // 必须以 Goroutine 方式启动: go ws.StartServer()
-//
-// 【注意事项】
-// - 此函数会阻塞运行（无限循环）
-// - 如果没有在线用户，消息不会被发送
 func StartServer() {
 	log.Logger.Info("WsServer start")
 
-	// 无限循环，持续监听价格通道
+	go Manager.Run()
+
+	ticker := time.NewTicker(broadcastInterval())
+	defer ticker.Stop()
+
+	latestPrices := make(map[string]string) // 间隔内各交易对的最新价格
 	for {
 		select {
-		// 从 kucoin.PlgrPriceChan 接收新价格
-		// 这个通道由 kucoin.GetExchangePrice() 写入
-		case price, ok := <-kucoin.PlgrPriceChan:
-			if ok {
-				// 遍历所有在线连接，逐个推送价格
-				// Range 方法是并发安全的
-				Manager.Servers.Range(func(key, value interface{}) bool {
-					// 类型断言获取 Server 指针
-					value.(*Server).SendToClient(price, SuccessCode)
-					// 返回 true 继续遍历下一个连接
-					return true
-				})
+		case price, ok := <-kucoin.PriceChan:
+			if !ok {
+				return
+			}
+			latestPrices[price.Symbol] = price.Price
+
+		case <-ticker.C:
+			for symbol, price := range latestPrices {
+				if symbol == kucoin.PlgrSymbol {
+					Manager.BroadcastMessage(TopicPrice, price, SuccessCode)
+				}
+				Manager.BroadcastMessage(TopicPricePrefix+symbol, price, SuccessCode)
+				delete(latestPrices, symbol)
 			}
 		}
 	}
@@ -9,16 +9,36 @@
  *
  * 【路由分组】
  * 所有路由统一使用版本前缀: /api/v{version}
- * 版本号从配置文件读取: config.Config.Env.Version
+ * 版本号从配置文件读取: config.Config().Env.Version
  *
  * 【接口分类】
  * 1. 质押池信息（Pool） - 公开接口，无需登录
- * 2. 价格推送（Price） - WebSocket 接口，用于实时价格
+ * 2. 价格推送（Price） - WebSocket 接口，用于实时价格，同一连接上支持 RPC 请求 (getPoolBaseInfo 等)；
+ *    私有主题 jobs、ops 需要 authCode 或 API key，claimable 需要钱包签名
  * 3. 多签管理（MultiSign） - 管理接口，需要 Token 验证
  * 4. 用户认证（User） - 登录/登出
+ * 5. 代币管理（Token） - 管理接口，需要 Token 验证
+ * 6. 配置与调试（Config / Debug） - 热加载、日志级别、pprof，需要 Token 验证
+ * 7. 邮件订阅（Subscription） - 钱包订阅池子事件邮件，公开接口，按 IP 限流
+ * 8. 网络状态与合约地址（Network / Contracts） - 公开接口
+ * 9. 推荐计划（Referral） - 推荐码和存入交易归属，公开接口，按 IP 限流
  *
  * 【中间件】
  * - middlewares.CheckToken(): 验证 JWT Token，限制管理员访问
+ * - middlewares.RateLimit(): 按 IP 限流，用于公开接口
+ * - middlewares.Cors(): 按 [cors] 配置允许的跨域来源、方法和请求头
+ * - middlewares.RequestId(): 全局注册，沿用或生成 X-Request-Id，响应头、meta.request_id 和错误日志使用同一个 ID
+ * - middlewares.AdminGuard(): 全局注册，/admin/*、setMultiSign、getMultiSign 按 [admin] allow_cidrs / require_mtls 限制来源
+ * - middlewares.Language(): 全局注册，按 Accept-Language 选择 message 的语言 (en / zh / zh-TW 及 [i18n] catalog_dir 中的语言)
+ * - middlewares.Maintenance(): 全局注册，维护模式下读接口返回缓存，写接口和管理接口返回 503
+ * - middlewares.BodyLimit() / Timeout(): 全局请求体大小 ([env] max_body_size) 和处理时限 ([env] request_timeout)
+ *
+ * 【错误响应】
+ * 所有接口返回 {code, message, data, meta}，出错时可能带 details；
+ * 池子和代币的读接口支持 ?fields= 只返回指定字段 (sparse fieldsets)，嵌套字段用 . 连接；
+ * meta 包含 request_id、timestamp (服务器时间, Unix 毫秒)、version，分页接口 (pool/search) 另有 pagination {page, page_size, total, pages}
+ * [env] strict_status = true 时 HTTP 状态码由 statecode.HttpStatus 按 code 映射 (参数错误 400、未登录 401、不存在 404、禁止访问 403、超时 408、请求体过大 413、限流 429、服务器错误 500、维护中 503)；
+ * false 时都返回 200，只通过 code 判断
  * ==================================================================================
  */
 
@@ -27,6 +47,7 @@ package routes
 import (
 	"pledge-backend/api/controllers"
 	"pledge-backend/api/middlewares"
+	"pledge-backend/api/models/ws"
 	"pledge-backend/config"
 
 	"github.com/gin-gonic/gin"
@@ -41,7 +62,7 @@ import (
 //   - *gin.Engine: 配置好路由的 Gin 引擎
 //
 // 【调用时机】
-// 在 pledge_api.go 的 main() 函数中调用:
+// 在 cmd/api.go 的 runApi() 中调用:
 //
 //	routes.InitRoute(app)
 func InitRoute(e *gin.Engine) *gin.Engine {
@@ -51,7 +72,22 @@ func InitRoute(e *gin.Engine) *gin.Engine {
 	// ============================================================
 	// 所有 API 路由的前缀: /api/v{version}
 	// 例如: /api/v2/poolBaseInfo
-	v2Group := e.Group("/api/v" + config.Config.Env.Version)
+	v2Group := e.Group("/api/v" + config.Config().Env.Version)
+
+	// ============================================================
+	// 健康检查 (Health) - 不带版本前缀，供负载均衡/容器编排探测
+	// ============================================================
+	healthController := controllers.HealthController{}
+
+	// GET /readyz
+	// 就绪检查: MySQL、Redis 连接状态，以及喂价熔断器状态
+	e.GET("/readyz", healthController.Readyz)
+
+	// GET /assets/{path}.{hash}.{ext}
+	// static 目录下的代币 logo、项目 logo，路径带内容哈希，内容变化后地址随之变化，响应可以长期缓存 (immutable)
+	// 原地覆盖的 /storage 地址仍可访问，但每次都需要重新验证
+	assetController := controllers.AssetController{}
+	e.GET("/assets/*filepath", assetController.Asset)
 
 	// ============================================================
 	// 质押池相关接口 (Pool)
@@ -59,20 +95,92 @@ func InitRoute(e *gin.Engine) *gin.Engine {
 	// 这些接口用于查询质押池的基本信息和数据
 	poolController := controllers.PoolController{}
 
-	// GET /api/v{version}/poolBaseInfo
+	// GET /api/v{version}/poolBaseInfo?chainId=56
 	// 获取质押池基础信息（池名称、币种、利率等静态配置）
+	// chainId 为逗号分隔的列表 (97,56) 或 all 时按链分组返回
+	// 响应缓存 [cache] ttl 秒，附带 ETag / Last-Modified，条件请求未变化时返回 304
 	// 公开接口，无需登录
-	v2Group.GET("/poolBaseInfo", poolController.PoolBaseInfo)
+	v2Group.GET("/poolBaseInfo", middlewares.HttpCache(), middlewares.ResponseCache(), poolController.PoolBaseInfo)
 
-	// GET /api/v{version}/poolDataInfo
+	// GET /api/v{version}/poolDataInfo?chainId=56
 	// 获取质押池动态数据（TVL、借贷量、用户数等实时数据）
+	// chainId 为逗号分隔的列表或 all 时按链分组返回
+	// 响应缓存 [cache] ttl 秒，附带 ETag / Last-Modified，条件请求未变化时返回 304
+	// 公开接口，无需登录
+	v2Group.GET("/poolDataInfo", middlewares.HttpCache(), middlewares.ResponseCache(), poolController.PoolDataInfo)
+
+	// GET /api/v{version}/pool/{chainId}/{poolId}
+	// 单个质押池详情，附带利用率、出借年化、抵押率、阶段倒计时等计算字段
+	// 附带 ETag / Last-Modified，条件请求未变化时返回 304
+	// 公开接口，无需登录
+	v2Group.GET("/pool/:chainId/:poolId", middlewares.HttpCache(), poolController.PoolDetail)
+
+	// GET /api/v{version}/pool/{chainId}/{poolId}/history?from=&to=&limit=&format=csv
+	// 质押池历史快照（出借供给、抵押供给、状态变化），用于前端绘图
+	// format=csv 时以附件返回快照
+	// 公开接口，无需登录
+	v2Group.GET("/pool/:chainId/:poolId/history", poolController.PoolHistory)
+
+	// GET /api/v{version}/pool/{chainId}/{poolId}/estimate?amount=&side=lend|borrow
+	// 按固定利率和池子时间线估算利息、手续费和到期价值
+	// 公开接口，无需登录
+	v2Group.GET("/pool/:chainId/:poolId/estimate", poolController.PoolEstimate)
+
+	// GET /api/v{version}/pool/{chainId}/{poolId}/stats?top=5
+	// 质押池参与人数、存入次数、平均存入和最大持仓
+	// 公开接口，无需登录
+	v2Group.GET("/pool/:chainId/:poolId/stats", poolController.PoolStats)
+
+	// GET /api/v{version}/pool/{chainId}/{poolId}/whitelist
+	// 池子 spCoin / jpCoin 的 minter 白名单和 PledgePool 的暂停状态，由 schedule 定时同步
+	// 公开接口，无需登录
+	v2Group.GET("/pool/:chainId/:poolId/whitelist", poolController.PoolWhitelist)
+
+	// GET /api/v{version}/pool/{chainId}/{poolId}/whitelist/check?address=
+	// 地址现在能否在池子中借款，不能时返回原因，前端在发送交易前检查
+	// 公开接口，无需登录
+	v2Group.GET("/pool/:chainId/:poolId/whitelist/check", poolController.PoolWhitelistCheck)
+
+	// GET /api/v{version}/stats/tvl?chainId=&interval=1d&range=90d
+	// 各链 TVL、利用率和各代币锁定价值的时间序列，由池子快照和价格历史计算，结果缓存 5 分钟
+	// chainId 可以是逗号分隔的列表，为空或 all 时返回所有链
+	// 公开接口，无需登录
+	statsController := controllers.StatsController{}
+	v2Group.GET("/stats/tvl", statsController.Tvl)
+
+	// GET /api/v{version}/stats/fees?chainId=&group=month&from=&to=
+	// 协议在池子完成、清算时收取的手续费收入，按月或按天合计，并列出每个池子
+	// chainId 为逗号分隔的列表或 all 时同时返回每条链的合计
+	// 公开接口，无需登录
+	v2Group.GET("/stats/fees", statsController.Fees)
+
+	// GET /api/v{version}/stats/leaderboard?chainId=97&side=lend&poolId=&window=30d&limit=20
+	// 单个池子或全协议的出借、抵押存入排行榜，由 pool_events 统计，结果缓存 5 分钟
 	// 公开接口，无需登录
-	v2Group.GET("/poolDataInfo", poolController.PoolDataInfo)
+	v2Group.GET("/stats/leaderboard", statsController.Leaderboard)
 
-	// GET /api/v{version}/token
+	// GET /api/v{version}/token?chainId=56
 	// 获取支持的代币列表（代币地址、符号、精度等）
+	// chainId 为逗号分隔的列表或 all 时返回 {"chains": [...]}，每条链一个代币列表
+	// 响应缓存 [cache] ttl 秒，附带 ETag / Last-Modified，条件请求未变化时返回 304
 	// 公开接口，无需登录
-	v2Group.GET("/token", poolController.TokenList)
+	v2Group.GET("/token", middlewares.HttpCache(), middlewares.ResponseCache(), poolController.TokenList)
+
+	// GET /api/v{version}/token/changelog?chainId=56
+	// 代币列表版本变更记录（新增、删除、修改的代币）
+	// 附带 ETag / Last-Modified，条件请求未变化时返回 304
+	// 公开接口，无需登录
+	v2Group.GET("/token/changelog", middlewares.HttpCache(), poolController.TokenListChangelog)
+
+	// GET /api/v{version}/token/logo?chainId=56&address=
+	// 代币当前 logo 和缩略图在 /assets 下带内容哈希的地址
+	// 公开接口，无需登录
+	v2Group.GET("/token/logo", poolController.TokenLogo)
+
+	// GET /api/v{version}/token/search?chainId=56&keyword=bu
+	// 按 symbol、name、地址前缀/部分匹配搜索代币
+	// 公开接口，按 IP 限流
+	v2Group.GET("/token/search", middlewares.RateLimit("token_search", searchRateLimit), poolController.TokenSearch)
 
 	// POST /api/v{version}/pool/debtTokenList
 	// 获取债务代币列表
@@ -80,10 +188,31 @@ func InitRoute(e *gin.Engine) *gin.Engine {
 	v2Group.POST("/pool/debtTokenList", middlewares.CheckToken(), poolController.DebtTokenList)
 
 	// POST /api/v{version}/pool/search
-	// 搜索/筛选质押池
+	// 搜索/筛选质押池，支持 keyword 模糊匹配代币，states、利率区间、结束时间区间组合筛选
 	// 需要管理员 Token 验证
 	v2Group.POST("/pool/search", middlewares.CheckToken(), poolController.Search)
 
+	// GET /api/v{version}/pool/search?chainID=97&state=0&page=1&pageSize=10
+	// 公开的质押池搜索，只返回公开字段，[search] public_enabled 关闭时不可用
+	// 公开接口，按 IP 限流
+	v2Group.GET("/pool/search", middlewares.RateLimit("pool_search", searchRateLimit), poolController.PublicSearch)
+
+	// GET /api/v{version}/changes?chainId=56&since=1700000000
+	// 增量同步: since 之后变化的池子、代币和价格，返回下次请求使用的 cursor
+	// 公开接口，无需登录
+	changesController := controllers.ChangesController{}
+	v2Group.GET("/changes", changesController.Changes)
+
+	// ============================================================
+	// GraphQL 接口
+	// ============================================================
+	// 池子、代币、价格、存入事件和钱包持仓的只读查询，支持字段选择和嵌套查询
+	graphqlController := controllers.GraphqlController{}
+
+	// POST /api/v{version}/graphql
+	// 公开接口，按 IP 限流，[graphql] enabled 关闭时不可用
+	v2Group.POST("/graphql", middlewares.RateLimit("graphql", graphqlRateLimit), graphqlController.Query)
+
 	// ============================================================
 	// 价格推送接口 (Price) - WebSocket
 	// ============================================================
@@ -94,9 +223,268 @@ func InitRoute(e *gin.Engine) *gin.Engine {
 	// WebSocket 升级端点
 	// 客户端连接后会自动接收价格推送
 	// 连接示例: ws://localhost:8081/api/v2/price
+	// 同一连接上可以发送 RPC 请求 {"id":1,"method":"getPoolBaseInfo","params":{"chainId":97}}，响应带相同的 id
+	// 子协议 msgpack 或 ?encoding=msgpack 时以 msgpack 二进制帧推送
+	// 私有主题 jobs、ops 需要 authCode 或 API key ([env] wss_api_keys)；claimable:<chainId>:<address> 需要该钱包对 /user/:address/nonce 的签名
+	// 凭证可在连接时或通过 auth 消息提供
+	// 广播消息带序号 seq，重连时携带 ?last_seq= 和 ?topic= 补发断线期间遗漏的消息 ([env] wss_replay_size)
 	// 公开接口，无需登录
 	v2Group.GET("/price", priceController.NewPrice)
 
+	// WebSocket RPC 方法，返回数据与对应的 HTTP 接口相同
+	wsRpcController := controllers.WsRpcController{}
+	ws.RegisterMethod("getPoolBaseInfo", wsRpcController.PoolBaseInfo) // 同 /poolBaseInfo，params: chainId, archived, fields
+	ws.RegisterMethod("getPoolDataInfo", wsRpcController.PoolDataInfo) // 同 /poolDataInfo，params: chainId, fields
+	ws.RegisterMethod("getPoolDetail", wsRpcController.PoolDetail)     // 同 /pool/{chainId}/{poolId}，params: chainId, poolId, fields
+	ws.RegisterMethod("getChanges", wsRpcController.Changes)           // 同 /changes，params: chainId, since
+
+	// GET /api/v{version}/price/sse
+	// SSE 价格推送，WebSocket 被代理拦截时使用，支持 Last-Event-ID 断线补发
+	// 可选参数 topic，多个主题逗号分隔，例如 ?topic=price,pool:97,deadline:97
+	// 公开接口，无需登录
+	v2Group.GET("/price/sse", priceController.PriceSse)
+
+	// GET /api/v{version}/price/sources?chainId=56
+	// 代币的 BscPledgeOracle 价格与 Chainlink 价格
+	// 响应缓存 [cache] ttl 秒，附带 ETag / Last-Modified，条件请求未变化时返回 304
+	// 公开接口，无需登录
+	v2Group.GET("/price/sources", middlewares.HttpCache(), middlewares.ResponseCache(), priceController.PriceSources)
+
+	// GET /api/v{version}/price/{symbol}/slippage?amount=&side=sell
+	// 按 KuCoin 50 档盘口估算市价成交均价和滑点，交易对需在 [exchange] depth_symbols 中
+	// 公开接口，无需登录
+	v2Group.GET("/price/:symbol/slippage", priceController.Slippage)
+
+	// GET /api/v{version}/prices?chainId=56&tokens=a,b,c
+	// 批量查询代币价格，最多 50 个，未收录的代币在 missing 中返回
+	// 附带 ETag / Last-Modified，条件请求未变化时返回 304
+	// 公开接口，无需登录
+	v2Group.GET("/prices", middlewares.HttpCache(), priceController.Prices)
+
+	// GET /api/v{version}/convert?chainId=56&from=&to=&amount=
+	// 按当前价格和精度换算金额，amount 和 result 均为最小单位
+	// 公开接口，无需登录
+	v2Group.GET("/convert", priceController.Convert)
+
+	// GET /api/v{version}/price/history?chainId=97&token=&from=&to=&limit=&format=csv
+	// 代币价格变化记录，format=csv 时以附件返回
+	// JSON 响应附带 ETag / Last-Modified，条件请求未变化时返回 304
+	// 公开接口，无需登录
+	v2Group.GET("/price/history", middlewares.HttpCache(), priceController.PriceHistory)
+
+	// GET /api/v{version}/admin/ws/connections
+	// 查看在线 WebSocket 连接（IP、连接时间、订阅主题）
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/ws/connections", middlewares.CheckToken(), priceController.Connections)
+
+	// POST /api/v{version}/admin/ws/connections/close
+	// 强制断开指定 WebSocket 连接
+	// 需要管理员 Token 验证
+	v2Group.POST("/admin/ws/connections/close", middlewares.CheckToken(), priceController.CloseConnection)
+
+	// GET /api/v{version}/admin/price/quarantine
+	// 查看被异常检测隔离的价格，可选参数 status=pending/approved/rejected
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/price/quarantine", middlewares.CheckToken(), priceController.PriceQuarantine)
+
+	// POST /api/v{version}/admin/price/quarantine/review
+	// 审核隔离价格: approve 写入代币价格，reject 丢弃
+	// 需要管理员 Token 验证
+	v2Group.POST("/admin/price/quarantine/review", middlewares.CheckToken(), priceController.ReviewPriceQuarantine)
+
+	// GET /api/v{version}/admin/price/backfill
+	// 查看历史价格回填的进度，可选参数 chainId
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/price/backfill", middlewares.CheckToken(), priceController.PriceBackfills)
+
+	// POST /api/v{version}/admin/price/backfill
+	// 提交代币的历史价格回填，由 schedule 的 BackfillPriceHistory 每隔 [backfill] step_blocks 个区块读取 Oracle 价格
+	// 需要管理员 Token 验证
+	v2Group.POST("/admin/price/backfill", middlewares.CheckToken(), priceController.SubmitPriceBackfill)
+
+	// ============================================================
+	// 配置管理接口 (Config) - 管理员专用
+	// ============================================================
+	configController := controllers.ConfigController{}
+
+	// POST /api/v{version}/admin/config/reload
+	// 重新读取配置文件，热加载定时任务间隔、RPC 地址、告警阈值、限流、日志级别等配置项
+	// 只作用于当前 api 进程，task 进程监听配置文件自动加载
+	// 需要管理员 Token 验证
+	v2Group.POST("/admin/config/reload", middlewares.CheckToken(), configController.Reload)
+
+	// GET /api/v{version}/admin/log/level
+	// 查询当前日志级别
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/log/level", middlewares.CheckToken(), configController.LogLevel)
+
+	// POST /api/v{version}/admin/log/level
+	// 运行时修改日志级别 (debug / info / warn / error)，不修改配置文件，只作用于当前 api 进程
+	// 需要管理员 Token 验证
+	v2Group.POST("/admin/log/level", middlewares.CheckToken(), configController.SetLogLevel)
+
+	// ============================================================
+	// 维护模式接口 (Maintenance) - 管理员专用
+	// ============================================================
+	maintenanceController := controllers.MaintenanceController{}
+
+	// GET /api/v{version}/admin/maintenance
+	// 查询维护模式状态
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/maintenance", middlewares.CheckToken(), maintenanceController.Maintenance)
+
+	// POST /api/v{version}/admin/maintenance
+	// 开启或关闭维护模式 {enabled, message}，状态保存在 Redis，所有 api 实例生效
+	// 维护期间写接口和管理接口返回 503，读接口返回缓存数据并附带 maintenance 字段
+	// 需要管理员 Token 验证
+	v2Group.POST("/admin/maintenance", middlewares.CheckToken(), maintenanceController.SetMaintenance)
+
+	// ============================================================
+	// Gas 花费接口 (Gas) - 管理员专用
+	// ============================================================
+	gasController := controllers.GasController{}
+
+	// GET /api/v{version}/admin/gas/summary?month=2006-01&chainId=56
+	// 后端发送的交易 (Oracle 喂价等) 的月度 gas 花费，按链、用途汇总，附带 [gas] 预算
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/gas/summary", middlewares.CheckToken(), gasController.Summary)
+
+	// ============================================================
+	// 清算执行机器人 (Keeper) - 管理员专用
+	// ============================================================
+	keeperController := controllers.KeeperController{}
+
+	// GET /api/v{version}/admin/keeper/txs?chainId=97&poolId=&status=&limit=50
+	// schedule 的 LiquidatePools ([keeper] enabled) 发送的 settle / finish / liquidate 交易及每次尝试的结果
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/keeper/txs", middlewares.CheckToken(), keeperController.Txs)
+
+	// ============================================================
+	// RPC 节点健康 (Chains) - 管理员专用
+	// ============================================================
+
+	// GET /api/v{version}/admin/chains/health
+	// 各链 RPC 节点最近一次健康检查结果: 延迟、区块高度、落后参考节点的区块数
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/chains/health", middlewares.CheckToken(), healthController.ChainsHealth)
+
+	// GET /api/v{version}/admin/oracle/status
+	// 主网 Oracle 中 PLGR 和 [oracle] feeds 各代币: 链上价格、最近一次写链时间、交易所价格、偏离、熔断器状态、最近一次写入交易的状态
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/oracle/status", middlewares.CheckToken(), healthController.OracleStatus)
+
+	// POST /api/v{version}/admin/oracle/simulateSetPrice
+	// 以喂价签名地址对 setPrice 执行 eth_call 和 eth_estimateGas，返回预计 gas、revert 原因和写入后的价格，不发送交易
+	// 修改喂价配置后在下一次定时写入前验证，需要管理员 Token 验证
+	v2Group.POST("/admin/oracle/simulateSetPrice", middlewares.CheckToken(), healthController.SimulateSetPrice)
+
+	// GET /api/v{version}/admin/integrity
+	// schedule 的 VerifyIntegrity 最近一次抽查池子、代币的链上数据与 MySQL、Redis 的结果和不一致的字段
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/integrity", middlewares.CheckToken(), healthController.Integrity)
+
+	// ============================================================
+	// 网络状态与合约地址 (Network / Contracts) - 公开接口
+	// ============================================================
+	// 前端和集成方按环境读取，无需自己访问 RPC 节点或硬编码合约地址
+	contractController := controllers.ContractController{}
+
+	// GET /api/v{version}/contracts?chainId=97
+	// PledgePool、Oracle、PLGR、多签合约和每个池子 SP / JP 代币的地址
+	// 公开接口，无需登录
+	v2Group.GET("/contracts", contractController.Contracts)
+
+	// GET /api/v{version}/network/{chainId}
+	// 最新区块、平均出块时间和 gas 价格建议，由 schedule 的 UpdateChainHealth 采集
+	// 以及 read_lag 和各同步任务最近一次读取链上状态的区块 (read_blocks)
+	// 公开接口，无需登录
+	v2Group.GET("/network/:chainId", healthController.Network)
+
+	// ============================================================
+	// 定时任务 (Jobs) - 管理员专用
+	// ============================================================
+
+	// GET /api/v{version}/admin/jobs
+	// schedule 进程各定时任务的执行次数、失败 (panic)、超时、跳过次数和耗时，task 重启后重新统计
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/jobs", middlewares.CheckToken(), healthController.Jobs)
+
+	// GET /api/v{version}/admin/jobs/runs?name=&limit=50
+	// 定时任务最近的执行记录 (job_runs 表): 开始、结束时间、结果、处理成功和失败的条目数
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/jobs/runs", middlewares.CheckToken(), healthController.JobRuns)
+
+	// GET /api/v{version}/admin/jobs/retries?status=dead
+	// 执行中处理失败的条目 (job_retries 表): pending 等待重试，dead 已放弃重试
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/jobs/retries", middlewares.CheckToken(), healthController.JobRetries)
+
+	// ============================================================
+	// 调试接口 (Debug) - 管理员专用
+	// ============================================================
+	debugController := controllers.DebugController{}
+
+	// GET /api/v{version}/admin/debug/pprof/:name
+	// net/http/pprof 性能分析，例如:
+	//   curl -H "authCode: <token>" .../admin/debug/pprof/goroutine?debug=2
+	//   curl -H "authCode: <token>" -o cpu.out .../admin/debug/pprof/profile?seconds=30 && go tool pprof cpu.out
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/debug/pprof/", middlewares.CheckToken(), debugController.Pprof)
+	v2Group.GET("/admin/debug/pprof/:name", middlewares.CheckToken(), debugController.Pprof)
+	v2Group.POST("/admin/debug/pprof/:name", middlewares.CheckToken(), debugController.Pprof)
+
+	// ============================================================
+	// 代币管理接口 (Token) - 管理员专用
+	// ============================================================
+	// 维护 token_info，新增代币时校验地址 checksum 和链上合约
+	tokenController := controllers.TokenController{}
+
+	// GET /api/v{version}/admin/token?chainId=56
+	// 查看代币列表（含价格来源、logo 来源）
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/token", middlewares.CheckToken(), tokenController.List)
+
+	// POST /api/v{version}/admin/token/create
+	// 新增代币，已删除的同地址代币会被恢复
+	// 需要管理员 Token 验证
+	v2Group.POST("/admin/token/create", middlewares.CheckToken(), tokenController.Create)
+
+	// POST /api/v{version}/admin/token/update
+	// 修改 logo、symbol、decimals、price_source、coingecko_id
+	// 需要管理员 Token 验证
+	v2Group.POST("/admin/token/update", middlewares.CheckToken(), tokenController.Update)
+
+	// POST /api/v{version}/admin/token/delete
+	// 软删除代币，schedule 停止同步，公开接口不再返回
+	// 需要管理员 Token 验证
+	v2Group.POST("/admin/token/delete", middlewares.CheckToken(), tokenController.Delete)
+
+	// POST /api/v{version}/admin/token/:address/logo?chainId=56
+	// 上传代币 logo（multipart 字段 logo，PNG/SVG），PNG 会生成多尺寸缩略图
+	// 需要管理员 Token 验证
+	v2Group.POST("/admin/token/:address/logo", middlewares.CheckToken(), tokenController.UploadLogo)
+
+	// ============================================================
+	// 池子展示信息 (Pool Metadata) - 管理员专用
+	// ============================================================
+	// 名称、说明、风险标签和推荐标记，合并到 poolBaseInfo 返回，不需要修改合约
+	poolMetadataController := controllers.PoolMetadataController{}
+
+	// GET /api/v{version}/admin/pool/metadata?chainId=56
+	// 查看池子展示信息
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/pool/metadata", middlewares.CheckToken(), poolMetadataController.List)
+
+	// POST /api/v{version}/admin/pool/metadata/save
+	// 新增或覆盖池子展示信息 {chain_id, pool_id, name, description, tags, featured}，池子必须已同步
+	// 需要管理员 Token 验证
+	v2Group.POST("/admin/pool/metadata/save", middlewares.CheckToken(), poolMetadataController.Save)
+
+	// POST /api/v{version}/admin/pool/metadata/delete
+	// 删除池子展示信息
+	// 需要管理员 Token 验证
+	v2Group.POST("/admin/pool/metadata/delete", middlewares.CheckToken(), poolMetadataController.Delete)
+
 	// ============================================================
 	// 多签管理接口 (MultiSign) - 管理员专用
 	// ============================================================
@@ -104,7 +492,8 @@ func InitRoute(e *gin.Engine) *gin.Engine {
 	multiSignPoolController := controllers.MultiSignPoolController{}
 
 	// POST /api/v{version}/pool/setMultiSign
-	// 设置/更新多签配置
+	// 设置/更新多签配置，每次保存记录一个版本 (修改人、与上一版本的差异)
+	// 配置了 [testnet] / [mainnet] multi_sign_address 时，签名人和门限必须与链上多签合约一致
 	// 需要管理员 Token 验证
 	v2Group.POST("/pool/setMultiSign", middlewares.CheckToken(), multiSignPoolController.SetMultiSign)
 
@@ -113,6 +502,11 @@ func InitRoute(e *gin.Engine) *gin.Engine {
 	// 需要管理员 Token 验证
 	v2Group.POST("/pool/getMultiSign", middlewares.CheckToken(), multiSignPoolController.GetMultiSign)
 
+	// GET /api/v{version}/admin/multiSign/history?chainId=97&limit=20
+	// 多签配置的版本历史，新版本在前
+	// 需要管理员 Token 验证
+	v2Group.GET("/admin/multiSign/history", middlewares.CheckToken(), multiSignPoolController.MultiSignHistory)
+
 	// ============================================================
 	// 用户认证接口 (User)
 	// ============================================================
@@ -130,9 +524,93 @@ func InitRoute(e *gin.Engine) *gin.Engine {
 	// 需要 Token 验证
 	v2Group.POST("/user/logout", middlewares.CheckToken(), userController.Logout)
 
+	// GET /api/v{version}/user/{address}/claimable?chainId=97
+	// 钱包在 FINISH / LIQUIDATION 池子中可提取的金额（按 SP/JP 余额计算）
+	// 公开接口，无需登录
+	v2Group.GET("/user/:address/claimable", userController.Claimable)
+
+	// GET /api/v{version}/user/{address}/nonce
+	// 钱包签名用的一次性 nonce，签名后可以订阅 WebSocket 主题 claimable:<chainId>:<address>
+	// 公开接口，按 IP 限流 ([search] public_rate_limit)
+	v2Group.GET("/user/:address/nonce", middlewares.RateLimit("wallet_nonce", searchRateLimit), userController.WalletNonce)
+
+	// ============================================================
+	// 邮件订阅 (Subscription)
+	// ============================================================
+	// 钱包订阅池子的已结算、已完成、已清算、可提取事件，由 schedule 的 NotifySubscribers 发送邮件
+	// [subscription] enabled 关闭时不可用
+	subscriptionController := controllers.SubscriptionController{}
+
+	// POST /api/v{version}/user/{address}/subscribe
+	// 订阅池子事件 {chain_id, pool_id, email, events}，新的邮箱发送验证邮件
+	// 公开接口，按 IP 限流
+	v2Group.POST("/user/:address/subscribe", middlewares.RateLimit("subscription", subscriptionRateLimit), subscriptionController.Subscribe)
+
+	// GET /api/v{version}/user/{address}/subscriptions?chainId=97
+	// 钱包的订阅列表，邮箱打码返回
+	// 公开接口，按 IP 限流
+	v2Group.GET("/user/:address/subscriptions", middlewares.RateLimit("subscription", subscriptionRateLimit), subscriptionController.List)
+
+	// GET /api/v{version}/subscription/verify?token=
+	// 验证邮件中的确认链接
+	// 公开接口，按 IP 限流
+	v2Group.GET("/subscription/verify", middlewares.RateLimit("subscription", subscriptionRateLimit), subscriptionController.Verify)
+
+	// GET /api/v{version}/subscription/unsubscribe?token=
+	// 邮件中的退订链接
+	// 公开接口，按 IP 限流
+	v2Group.GET("/subscription/unsubscribe", middlewares.RateLimit("subscription", subscriptionRateLimit), subscriptionController.Unsubscribe)
+
+	// ============================================================
+	// 推荐计划 (Referral)
+	// ============================================================
+	// 钱包的推荐码和存入交易的推荐归属，交易按 pool_events 校验，不需要修改合约
+	// [referral] enabled 关闭时不可用
+	referralController := controllers.ReferralController{}
+
+	// POST /api/v{version}/user/{address}/referral/code
+	// 获取或生成钱包的推荐码
+	// 公开接口，按 IP 限流
+	v2Group.POST("/user/:address/referral/code", middlewares.RateLimit("referral", referralRateLimit), referralController.Code)
+
+	// POST /api/v{version}/referral/attribute
+	// 把存入交易归属到推荐码 {chain_id, code, tx_hash}，未索引的交易由 schedule 的 VerifyReferrals 校验
+	// 公开接口，按 IP 限流
+	v2Group.POST("/referral/attribute", middlewares.RateLimit("referral", referralRateLimit), referralController.Attribute)
+
+	// GET /api/v{version}/user/{address}/referral?chainId=97&window=30d
+	// 推荐人的推荐码、推荐存入交易数、被推荐地址数和存入价值
+	// 公开接口，按 IP 限流
+	v2Group.GET("/user/:address/referral", middlewares.RateLimit("referral", referralRateLimit), referralController.Referral)
+
+	// GET /api/v{version}/stats/referrals?chainId=97&window=30d&limit=20
+	// 推荐人排行
+	// 公开接口，按 IP 限流
+	v2Group.GET("/stats/referrals", middlewares.RateLimit("referral", referralRateLimit), referralController.Stats)
+
 	return e
 }
 
+// searchRateLimit 公开搜索接口的限流配置，每次请求读取，支持热加载
+func searchRateLimit() (int, int) {
+	return config.Config().Search.PublicRateLimit, config.Config().Search.PublicRateWindow
+}
+
+// graphqlRateLimit GraphQL 接口的限流配置，每次请求读取，支持热加载
+func graphqlRateLimit() (int, int) {
+	return config.Config().Graphql.RateLimit, config.Config().Graphql.RateWindow
+}
+
+// subscriptionRateLimit 邮件订阅接口的限流配置，每次请求读取，支持热加载
+func subscriptionRateLimit() (int, int) {
+	return config.Config().Subscription.RateLimit, config.Config().Subscription.RateWindow
+}
+
+// referralRateLimit 推荐接口的限流配置，每次请求读取，支持热加载
+func referralRateLimit() (int, int) {
+	return config.Config().Referral.RateLimit, config.Config().Referral.RateWindow
+}
+
 /*
  * ==================================================================================
  * API 接口汇总表
@@ -140,16 +618,81 @@ func InitRoute(e *gin.Engine) *gin.Engine {
  *
  * | 方法   | 路径                          | 说明                 | 认证要求 |
  * |--------|-------------------------------|----------------------|----------|
+ * | GET    | /readyz                       | 就绪检查             | 无       |
+ * | GET    | /assets/*filepath             | 带内容哈希的静态文件 | 无       |
  * | GET    | /api/v{ver}/poolBaseInfo      | 质押池基础信息       | 无       |
  * | GET    | /api/v{ver}/poolDataInfo      | 质押池动态数据       | 无       |
+ * | GET    | /api/v{ver}/pool/:chainId/:poolId | 质押池详情       | 无       |
+ * | GET    | /api/v{ver}/pool/:chainId/:poolId/history | 质押池历史快照 | 无   |
+ * | GET    | /api/v{ver}/pool/:chainId/:poolId/estimate | 收益/成本估算 | 无   |
+ * | GET    | /api/v{ver}/pool/:chainId/:poolId/stats | 参与者和存入统计 | 无     |
+ * | GET    | /api/v{ver}/pool/:chainId/:poolId/whitelist | 池子链上权限状态 | 无 |
+ * | GET    | /api/v{ver}/pool/:chainId/:poolId/whitelist/check | 地址能否借款 | 无 |
+ * | GET    | /api/v{ver}/stats/tvl         | TVL 和利用率序列     | 无       |
+ * | GET    | /api/v{ver}/stats/fees        | 协议手续费收入       | 无       |
+ * | GET    | /api/v{ver}/stats/leaderboard | 出借/抵押存入排行榜  | 无       |
  * | GET    | /api/v{ver}/token             | 代币列表             | 无       |
+ * | GET    | /api/v{ver}/token/changelog   | 代币列表变更记录     | 无       |
+ * | GET    | /api/v{ver}/token/logo        | 代币 logo 哈希地址   | 无       |
+ * | GET    | /api/v{ver}/token/search      | 模糊搜索代币         | 无(限流) |
  * | POST   | /api/v{ver}/pool/debtTokenList| 债务代币列表         | 需要     |
  * | POST   | /api/v{ver}/pool/search       | 搜索质押池           | 需要     |
- * | GET    | /api/v{ver}/price             | WebSocket 价格推送   | 无       |
+ * | GET    | /api/v{ver}/pool/search       | 公开搜索质押池       | 无(限流) |
+ * | GET    | /api/v{ver}/changes           | 增量同步             | 无       |
+ * | POST   | /api/v{ver}/graphql           | GraphQL 查询         | 无(限流) |
+ * | GET    | /api/v{ver}/price             | WebSocket 价格推送和 RPC | 无   |
+ * | GET    | /api/v{ver}/price/sse         | SSE 价格推送         | 无       |
+ * | GET    | /api/v{ver}/price/sources     | 多来源代币价格       | 无       |
+ * | GET    | /api/v{ver}/price/history     | 代币价格历史         | 无       |
+ * | GET    | /api/v{ver}/price/:symbol/slippage | 盘口滑点估算    | 无       |
+ * | GET    | /api/v{ver}/prices            | 批量查询代币价格     | 无       |
+ * | GET    | /api/v{ver}/convert           | 代币金额换算         | 无       |
+ * | GET    | /api/v{ver}/admin/ws/connections | 在线连接列表      | 需要     |
+ * | POST   | /api/v{ver}/admin/ws/connections/close | 强制断开连接 | 需要     |
+ * | GET    | /api/v{ver}/admin/price/quarantine | 隔离价格列表     | 需要     |
+ * | POST   | /api/v{ver}/admin/price/quarantine/review | 审核隔离价格 | 需要  |
+ * | GET    | /api/v{ver}/admin/price/backfill | 历史价格回填进度 | 需要     |
+ * | POST   | /api/v{ver}/admin/price/backfill | 提交历史价格回填 | 需要     |
+ * | POST   | /api/v{ver}/admin/config/reload | 热加载配置         | 需要     |
+ * | GET    | /api/v{ver}/admin/log/level   | 查询日志级别         | 需要     |
+ * | POST   | /api/v{ver}/admin/log/level   | 修改日志级别         | 需要     |
+ * | GET    | /api/v{ver}/admin/maintenance | 查询维护模式         | 需要     |
+ * | POST   | /api/v{ver}/admin/maintenance | 开启/关闭维护模式    | 需要     |
+ * | GET    | /api/v{ver}/admin/gas/summary | 月度 gas 花费        | 需要     |
+ * | GET    | /api/v{ver}/admin/keeper/txs  | keeper 交易记录      | 需要     |
+ * | GET    | /api/v{ver}/admin/chains/health | RPC 节点健康状态   | 需要     |
+ * | GET    | /api/v{ver}/admin/oracle/status | 喂价状态汇总       | 需要     |
+ * | POST   | /api/v{ver}/admin/oracle/simulateSetPrice | 模拟喂价交易 | 需要   |
+ * | GET    | /api/v{ver}/admin/integrity   | 数据一致性抽查结果   | 需要     |
+ * | GET    | /api/v{ver}/network/:chainId  | 区块和 gas 价格建议  | 无       |
+ * | GET    | /api/v{ver}/contracts         | 合约地址             | 无       |
+ * | GET    | /api/v{ver}/admin/jobs        | 定时任务执行统计     | 需要     |
+ * | GET    | /api/v{ver}/admin/jobs/runs   | 定时任务执行记录     | 需要     |
+ * | GET    | /api/v{ver}/admin/jobs/retries | 失败条目重试队列    | 需要     |
+ * | GET    | /api/v{ver}/admin/debug/pprof/:name | pprof 性能分析 | 需要     |
+ * | GET    | /api/v{ver}/admin/token       | 代币列表（管理）     | 需要     |
+ * | POST   | /api/v{ver}/admin/token/create | 新增代币            | 需要     |
+ * | POST   | /api/v{ver}/admin/token/update | 修改代币            | 需要     |
+ * | POST   | /api/v{ver}/admin/token/delete | 删除代币            | 需要     |
+ * | POST   | /api/v{ver}/admin/token/:address/logo | 上传代币 logo | 需要     |
+ * | GET    | /api/v{ver}/admin/pool/metadata | 池子展示信息       | 需要     |
+ * | POST   | /api/v{ver}/admin/pool/metadata/save | 保存池子展示信息 | 需要   |
+ * | POST   | /api/v{ver}/admin/pool/metadata/delete | 删除池子展示信息 | 需要 |
  * | POST   | /api/v{ver}/pool/setMultiSign | 设置多签配置         | 需要     |
  * | POST   | /api/v{ver}/pool/getMultiSign | 获取多签配置         | 需要     |
+ * | GET    | /api/v{ver}/admin/multiSign/history | 多签配置版本历史 | 需要     |
  * | POST   | /api/v{ver}/user/login        | 管理员登录           | 无       |
  * | POST   | /api/v{ver}/user/logout       | 管理员登出           | 需要     |
+ * | GET    | /api/v{ver}/user/:address/claimable | 钱包可提取金额 | 无       |
+ * | GET    | /api/v{ver}/user/:address/nonce | 钱包签名 nonce   | 无(限流) |
+ * | POST   | /api/v{ver}/user/:address/subscribe | 订阅池子事件邮件 | 无(限流) |
+ * | GET    | /api/v{ver}/user/:address/subscriptions | 钱包的订阅 | 无(限流) |
+ * | GET    | /api/v{ver}/subscription/verify | 确认订阅邮箱  | 无(限流) |
+ * | GET    | /api/v{ver}/subscription/unsubscribe | 退订     | 无(限流) |
+ * | POST   | /api/v{ver}/user/:address/referral/code | 钱包的推荐码 | 无(限流) |
+ * | POST   | /api/v{ver}/referral/attribute | 存入交易归属推荐码 | 无(限流) |
+ * | GET    | /api/v{ver}/user/:address/referral | 推荐人数据 | 无(限流) |
+ * | GET    | /api/v{ver}/stats/referrals   | 推荐人排行           | 无(限流) |
  *
  * ==================================================================================
  */
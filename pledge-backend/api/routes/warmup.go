@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"pledge-backend/api/models/response"
+	"pledge-backend/config"
+	"pledge-backend/i18n"
+	"pledge-backend/log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// warmupPaths 启动时预热的接口，与 InitRoute 中使用 middlewares.ResponseCache 的路由一致，chainId 参数由 WarmUp 追加
+var warmupPaths = []string{"/poolBaseInfo", "/poolDataInfo", "/token", "/price/sources"}
+
+// WarmUp 在开始监听前，为 [testnet] / [mainnet] 中 enabled 的链按各语言请求一次 warmupPaths，
+// 响应经过完整的中间件写入响应缓存，部署后的第一批请求不再同时查询 MySQL
+// 超过 [cache] warmup_timeout 秒时停止，未预热的接口在第一次请求时缓存
+func WarmUp(e *gin.Engine) {
+	if !config.Config().Cache.Warmup || config.Config().Cache.Ttl <= 0 {
+		return
+	}
+	chainIds := make([]string, 0, 2)
+	if config.Config().TestNet.Enabled {
+		chainIds = append(chainIds, config.Config().TestNet.ChainId)
+	}
+	if config.Config().MainNet.Enabled {
+		chainIds = append(chainIds, config.Config().MainNet.ChainId)
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.Config().Cache.WarmupTimeout)*time.Second)
+	defer cancel()
+	primed, failed := 0, 0
+	for _, chainId := range chainIds {
+		for _, path := range warmupPaths {
+			for _, lang := range i18n.Languages() {
+				if ctx.Err() != nil {
+					log.Logger.Sugar().Warn("cache warmup timed out after ", primed, " responses")
+					return
+				}
+				uri := "/api/v" + config.Config().Env.Version + path + "?chainId=" + chainId
+				req := httptest.NewRequest(http.MethodGet, uri, nil).WithContext(ctx)
+				req.Header.Set("Accept-Language", i18n.Tag(lang))
+				w := httptest.NewRecorder()
+				e.ServeHTTP(w, req)
+				// [env] strict_status 关闭时失败也是 200，按响应体判断
+				if w.Code != http.StatusOK || !response.Succeeded(w.Body.Bytes()) {
+					failed++
+					log.Logger.Sugar().Warn("cache warmup ", uri, " ", i18n.Tag(lang), " status ", w.Code, " ", w.Body.String())
+					continue
+				}
+				primed++
+			}
+		}
+	}
+	log.Logger.Sugar().Info("cache warmup primed ", primed, " responses, ", failed, " failed in ", time.Since(start))
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/static"
+	"pledge-backend/config"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Asset static 目录下的图片 (代币 logo、项目 logo) 的内容哈希地址
+// /storage/<path> 的文件会被原地覆盖，/assets/<path 带哈希> 内容不变，可以设置很长的 CDN 缓存时间
+type Asset struct{}
+
+func NewAsset() *Asset {
+	return &Asset{}
+}
+
+// Url 把 /storage 下文件的地址转为 /assets 下带内容哈希的地址，协议和域名不变
+// 远程图片和不存在的文件原样返回
+func (s *Asset) Url(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || !strings.HasPrefix(u.Path, "/storage/") {
+		return raw
+	}
+	hashed, err := static.HashedPath(strings.TrimPrefix(u.Path, "/storage/"))
+	if err != nil {
+		return raw
+	}
+	u.Path = "/assets/" + hashed
+	return u.String()
+}
+
+// TokenLogo 代币当前 logo 及缩略图的内容哈希地址
+func (s *Asset) TokenLogo(req *request.TokenLogoUrl, res *response.TokenLogoUrl) error {
+	token := models.NewTokenAdmin()
+	err := token.GetActiveByToken(strconv.Itoa(req.ChainId), req.Address)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return statecode.New(statecode.TokenNotFound)
+	} else if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	res.ChainId = req.ChainId
+	res.Address = token.Token
+	res.Logo = s.Url(token.Logo)
+	res.Variants = map[string]string{}
+	// 缩略图与原图同名加 _<尺寸>，见 TokenLogo.Upload
+	if strings.HasSuffix(token.Logo, ".png") {
+		for _, size := range config.Config().Token.LogoSizes {
+			variant := strings.TrimSuffix(token.Logo, ".png") + "_" + strconv.Itoa(size) + ".png"
+			if hashed := s.Url(variant); hashed != variant {
+				res.Variants[strconv.Itoa(size)] = hashed
+			}
+		}
+	}
+	return nil
+}
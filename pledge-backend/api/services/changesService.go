@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"strconv"
+	"time"
+)
+
+// Changes 轮询客户端的增量同步
+//
+// 池子、pooldata、池子展示信息和代币按 updated_at 查询，价格按 token_price_history 的 price_at 查询；
+// since 包含边界，同一秒内的修改可能在相邻两次同步中重复返回，客户端按 pool_id / 代币地址覆盖即可
+type Changes struct{}
+
+func NewChanges() *Changes {
+	return &Changes{}
+}
+
+func (s *Changes) Changes(ctx context.Context, req *request.Changes, res *response.Changes) error {
+	// cursor 取查询开始前的时间，查询期间的修改在下次同步中返回
+	now := time.Now().Unix()
+	since := time.Unix(req.SinceTs, 0).Format("2006-01-02 15:04:05")
+	res.ChainId = req.ChainId
+	res.Since = req.SinceTs
+	res.Cursor = strconv.FormatInt(now, 10)
+	res.Pools = make([]models.PoolBaseInfoRes, 0)
+	res.PoolData = make([]models.PoolDataInfoRes, 0)
+	res.RemovedPools = make([]int, 0)
+	res.Tokens = make([]response.Token, 0)
+	res.RemovedTokens = make([]string, 0)
+	res.Prices = make([]response.ChangedPrice, 0)
+
+	// 只修改了展示信息的池子也需要返回
+	var changedMetadata []models.PoolMetadata
+	if err := models.NewPoolMetadata().ChangedSince(ctx, req.ChainId, since, &changedMetadata); err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	poolIds := make([]int, 0, len(changedMetadata))
+	for _, v := range changedMetadata {
+		poolIds = append(poolIds, v.PoolId)
+	}
+	metadata, err := models.NewPoolMetadata().Map(ctx, req.ChainId)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	var pools []models.PoolBases
+	if err = models.NewPoolBases().ChangedSince(ctx, req.ChainId, since, poolIds, &pools); err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	for i := range pools {
+		if pools[i].DeletedAt != nil {
+			res.RemovedPools = append(res.RemovedPools, pools[i].PoolID)
+			continue
+		}
+		res.Pools = append(res.Pools, pools[i].InfoRes(metadata[pools[i].PoolID]))
+	}
+
+	var poolData []models.PoolData
+	if err = models.NewPoolData().ChangedSince(ctx, req.ChainId, since, &poolData); err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	for _, v := range poolData {
+		res.PoolData = append(res.PoolData, models.PoolDataInfoRes{Index: v.PoolID - 1, PoolData: v})
+	}
+
+	var tokens []models.TokenAdmin
+	if err = models.NewTokenAdmin().ChangedSince(ctx, req.ChainId, since, &tokens); err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	for _, v := range tokens {
+		if v.DeletedAt != nil {
+			res.RemovedTokens = append(res.RemovedTokens, v.Token)
+			continue
+		}
+		res.Tokens = append(res.Tokens, response.Token{
+			Name:     v.Symbol,
+			Symbol:   v.Symbol,
+			Decimals: v.Decimals,
+			Address:  v.Token,
+			ChainID:  req.ChainId,
+			LogoURI:  v.Logo,
+		})
+	}
+
+	var prices []models.TokenPriceHistory
+	if err = models.NewTokenPriceHistory().LatestSince(ctx, req.ChainId, req.SinceTs, &prices); err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	// 同一秒内多次变化时取最后一条
+	for _, v := range prices {
+		price := response.ChangedPrice{Token: v.Token, Price: v.Price, PriceAt: v.PriceAt}
+		if n := len(res.Prices); n > 0 && res.Prices[n-1].Token == v.Token {
+			res.Prices[n-1] = price
+			continue
+		}
+		res.Prices = append(res.Prices, price)
+	}
+	return nil
+}
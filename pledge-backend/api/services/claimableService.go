@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/models/ws"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	scheduleModels "pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+)
+
+// balanceOfSelector ERC20 balanceOf(address) 的函数选择器
+var balanceOfSelector = common.FromHex("0x70a08231")
+
+type Claimable struct{}
+
+func NewClaimable() *Claimable {
+	return &Claimable{}
+}
+
+// Claimable 按钱包的 SP/JP 余额和 pooldata 的结束/清算金额，计算每个 FINISH / LIQUIDATION 池子可提取的金额
+//
+// 与 PledgePool.sol 一致:
+// withdrawLend   = finishAmountLend(liquidationAmounLend) * spBalance / settleAmountLend
+// withdrawBorrow = finishAmountBorrow(liquidationAmounBorrow) * jpBalance / (settleAmountLend * martgageRate / 1e8)
+func (s *Claimable) Claimable(req *request.UserClaimable, res *response.Claimable) error {
+	var pools []models.ClaimablePool
+	err := models.NewPoolBases().ClaimablePools(req.ChainId, &pools)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	owner := common.HexToAddress(req.Address)
+	res.Address = owner.Hex()
+	res.Pools = []response.ClaimablePool{}
+	if len(pools) == 0 {
+		return nil
+	}
+
+	netUrl := scheduleModels.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId)
+	if utils.IntToString(req.ChainId) == config.Config().TestNet.ChainId {
+		netUrl = scheduleModels.NewChainHealth().RpcUrl(config.Config().TestNet.ChainId)
+	}
+	ethereumConn, err := ethclient.Dial(netUrl)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	defer ethereumConn.Close()
+
+	// 多个池子可能共用 SP/JP 代币，同一次请求内只查询一次
+	balances := map[string]decimal.Decimal{}
+	balanceOf := func(coin string) (decimal.Decimal, error) {
+		if v, ok := balances[coin]; ok {
+			return v, nil
+		}
+		balance, err := s.BalanceOf(ethereumConn, coin, owner)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		balances[coin] = decimal.NewFromBigInt(balance, 0)
+		return balances[coin], nil
+	}
+
+	precision := decimal.NewFromInt(100000000)
+	now := time.Now().Unix()
+	for _, pool := range pools {
+		spBalance, err := balanceOf(pool.SpCoin)
+		if err != nil {
+			return statecode.Wrap(statecode.CommonErrServerErr, err)
+		}
+		jpBalance, err := balanceOf(pool.JpCoin)
+		if err != nil {
+			return statecode.Wrap(statecode.CommonErrServerErr, err)
+		}
+		if !spBalance.IsPositive() && !jpBalance.IsPositive() {
+			continue
+		}
+
+		// FINISH 在 endTime 之后、LIQUIDATION 在 settleTime 之后才能提取
+		lendAmount, borrowAmount := pool.FinishAmountLend, pool.FinishAmountBorrow
+		unlockTime := utils.StringToInt64(pool.EndTime)
+		if pool.State == models.PoolStateLiquidation {
+			lendAmount, borrowAmount = pool.LiquidationAmounLend, pool.LiquidationAmounBorrow
+			unlockTime = utils.StringToInt64(pool.SettleTime)
+		}
+
+		lendClaimable, borrowClaimable := decimal.Zero, decimal.Zero
+		if now > unlockTime {
+			totalSp := toDecimal(pool.SettleAmountLend)
+			if totalSp.IsPositive() {
+				lendClaimable = toDecimal(lendAmount).Mul(spBalance).Div(totalSp).Floor()
+			}
+			totalJp := totalSp.Mul(toDecimal(pool.MartgageRate)).Div(precision).Floor()
+			if totalJp.IsPositive() {
+				borrowClaimable = toDecimal(borrowAmount).Mul(jpBalance).Div(totalJp).Floor()
+			}
+		}
+
+		res.Pools = append(res.Pools, response.ClaimablePool{
+			PoolId:            pool.PoolID,
+			State:             pool.State,
+			SpCoin:            pool.SpCoin,
+			SpBalance:         spBalance.String(),
+			LendToken:         pool.LendToken,
+			LendTokenSymbol:   pool.LendTokenSymbol,
+			LendClaimable:     lendClaimable.String(),
+			JpCoin:            pool.JpCoin,
+			JpBalance:         jpBalance.String(),
+			BorrowToken:       pool.BorrowToken,
+			BorrowTokenSymbol: pool.BorrowTokenSymbol,
+			BorrowClaimable:   borrowClaimable.String(),
+		})
+	}
+	return nil
+}
+
+// claimablePollInterval 重新计算已订阅钱包可提取金额的间隔
+const claimablePollInterval = time.Minute
+
+// WatchClaimable 定时重新计算有连接订阅的 claimable:{chainId}:{address} 私有主题，金额变化时推送，必须以 Goroutine 方式启动
+// 订阅时已通过主题快照推送当前金额，之后只推送变化
+func WatchClaimable() {
+	last := map[string]string{} // 主题上次计算的结果
+	for {
+		time.Sleep(claimablePollInterval)
+
+		topics := ws.Manager.SubscribedTopics(ws.TopicClaimablePrefix)
+		current := make(map[string]string, len(topics))
+		for _, topic := range topics {
+			data, err := ClaimableSnapshot(topic)
+			if err != nil {
+				log.Logger.Sugar().Error("WatchClaimable err ", topic, err)
+				if prev, ok := last[topic]; ok {
+					current[topic] = prev
+				}
+				continue
+			}
+			encoded, _ := json.Marshal(data)
+			current[topic] = string(encoded)
+			if prev, ok := last[topic]; ok && prev != current[topic] {
+				ws.Manager.BroadcastMessage(topic, data, ws.SuccessCode)
+			}
+		}
+		last = current
+	}
+}
+
+// ClaimableSnapshot claimable 主题的当前数据，在 cmd/api.go 中通过 ws.RegisterSnapshot 注册
+func ClaimableSnapshot(topic string) (interface{}, error) {
+	chainId, address, ok := ws.ParseClaimableTopic(topic)
+	if !ok {
+		return nil, statecode.New(statecode.WsTopicErr)
+	}
+	if chainId != 97 && chainId != 56 {
+		return nil, statecode.New(statecode.ChainIdErr)
+	}
+	res := response.Claimable{}
+	err := NewClaimable().Claimable(&request.UserClaimable{ChainId: chainId, Address: address}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// BalanceOf 读取 ERC20 余额
+func (s *Claimable) BalanceOf(conn *ethclient.Client, token string, owner common.Address) (*big.Int, error) {
+	contract := common.HexToAddress(token)
+	if contract == (common.Address{}) {
+		return big.NewInt(0), nil
+	}
+	data := append(append([]byte{}, balanceOfSelector...), common.LeftPadBytes(owner.Bytes(), 32)...)
+	out, err := conn.CallContract(context.Background(), ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(out), nil
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+)
+
+// hotReads 合并热点读接口 (poolBaseInfo、poolDataInfo、token 及其 Last-Modified) 并发的相同查询，
+// 响应缓存未命中时同一时刻只有一个查询到达 MySQL，其余请求等待并共享结果
+var hotReads singleflight.Group
+
+// coalesce 按 key 合并并发的相同查询 fn，结果由等待的请求共享，调用方不能修改
+// fn 使用不随请求取消的 ctx (保留链路追踪的 span)，一个请求断开或超时不会让其他等待的请求失败；
+// 请求自己的 ctx 结束时不再等待，返回 ctx.Err()
+func coalesce(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	shared := trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+	ch := hotReads.DoChan(key, func() (interface{}, error) {
+		return fn(shared)
+	})
+	select {
+	case r := <-ch:
+		return r.Val, r.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
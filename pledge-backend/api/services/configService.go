@@ -0,0 +1,47 @@
+package services
+
+import (
+	"errors"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/response"
+	"pledge-backend/config"
+	"pledge-backend/log"
+)
+
+type Config struct{}
+
+func NewConfig() *Config {
+	return &Config{}
+}
+
+// Reload 重新加载配置文件中支持热加载的配置项
+// 配置文件无法解析或校验失败时保留当前配置，details 返回具体问题
+func (s *Config) Reload(res *response.ConfigReload) error {
+	changed, err := config.Reload()
+	if err != nil {
+		var problems config.ValidationError
+		if errors.As(err, &problems) {
+			return statecode.Wrap(statecode.ConfigInvalid, err).WithDetails(problems)
+		}
+		return statecode.Wrap(statecode.ConfigInvalid, err).WithDetails(err.Error())
+	}
+	res.Changed = changed
+	return nil
+}
+
+// LogLevel 当前日志级别
+func (s *Config) LogLevel(res *response.LogLevel) {
+	res.Level = log.Level()
+}
+
+// SetLogLevel 修改当前进程的日志级别，立即生效
+// 不修改配置文件，重启或配置文件中的 [log] level 变化后以配置为准
+func (s *Config) SetLogLevel(level string, res *response.LogLevel) error {
+	err := log.SetLevel(level)
+	if err != nil {
+		return statecode.Wrap(statecode.ParameterErr, err)
+	}
+	log.Logger.Sugar().Warn("log level changed to ", level)
+	res.Level = log.Level()
+	return nil
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/config"
+	"pledge-backend/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Contract struct{}
+
+func NewContract() *Contract {
+	return &Contract{}
+}
+
+// Contracts 链的合约地址: PledgePool、Oracle、PLGR、多签合约取自 [testnet] / [mainnet] 配置 ([devnet] enabled 时为本地部署的合约)，
+// 每个池子的 SP / JP 代币取自 poolbases，包含已归档的池子
+func (s *Contract) Contracts(ctx context.Context, req *request.Contracts, res *response.Contracts) error {
+	mainnet := config.Config().MainNet
+	pledgePool, oracle, plgr, multiSign := mainnet.PledgePoolToken, mainnet.BscPledgeOracleToken, mainnet.PlgrAddress, mainnet.MultiSignAddress
+	if utils.IntToString(req.ChainId) == config.Config().TestNet.ChainId {
+		testnet := config.Config().TestNet
+		pledgePool, oracle, plgr, multiSign = testnet.PledgePoolToken, testnet.BscPledgeOracleToken, testnet.PlgrAddress, testnet.MultiSignAddress
+	}
+
+	res.ChainId = req.ChainId
+	res.PledgePool = checksumOrEmpty(pledgePool)
+	res.Oracle = checksumOrEmpty(oracle)
+	res.Plgr = checksumOrEmpty(plgr)
+	res.MultiSign = checksumOrEmpty(multiSign)
+	res.Pools = make([]models.PoolContracts, 0)
+	if err := models.NewPoolBases().Contracts(ctx, req.ChainId, &res.Pools); err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	return nil
+}
+
+// checksumOrEmpty 配置中的地址大小写不统一，统一转为 checksum 地址，未配置时返回空字符串
+func checksumOrEmpty(address string) string {
+	if !common.IsHexAddress(address) {
+		return ""
+	}
+	return common.HexToAddress(address).Hex()
+}
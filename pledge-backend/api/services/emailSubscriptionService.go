@@ -0,0 +1,205 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/config"
+	"pledge-backend/i18n"
+	"pledge-backend/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+)
+
+// verifyEmailTemplate 订阅验证邮件，文案见 i18n/notification.go
+var verifyEmailTemplate = template.Must(template.New("verify").Parse(`<p>{{call .T "subscription.verify.intro" .PoolId .ChainId}}</p>
+<p>{{call .T "subscription.wallet"}}: {{.Address}}<br>{{call .T "subscription.events"}}: {{.Events}}</p>
+<p><a href="{{.VerifyUrl}}">{{call .T "subscription.verify.confirm"}}</a> ({{call .T "subscription.verify.expires" .ExpiresAt}})</p>
+<p>{{call .T "subscription.verify.ignore"}} <a href="{{.UnsubscribeUrl}}">{{call .T "subscription.verify.cancel"}}</a>.</p>`))
+
+type EmailSubscription struct{}
+
+func NewEmailSubscription() *EmailSubscription {
+	return &EmailSubscription{}
+}
+
+// Subscribe 新的订阅发送验证邮件；已有订阅覆盖事件，未验证的订阅更换令牌并重新发送验证邮件
+// lang 为请求的语言，验证邮件和之后的通知邮件都使用该语言
+func (s *EmailSubscription) Subscribe(req *request.Subscribe, lang int, res *response.EmailSubscription) error {
+	conf := config.Config().Subscription
+	err := models.NewPoolBases().Get(req.ChainId, req.PoolId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return statecode.New(statecode.PoolNotFound)
+		}
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	chainId := strconv.Itoa(req.ChainId)
+	address := common.HexToAddress(req.Address).Hex()
+	events := subscriptionEventsString(req.Events)
+
+	subscription := models.NewEmailSubscription()
+	err = subscription.Get(chainId, address, req.Email, req.PoolId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	exists := err == nil
+
+	if !exists {
+		count, err := subscription.Count(chainId, address)
+		if err != nil {
+			return statecode.Wrap(statecode.CommonErrServerErr, err)
+		}
+		if count >= int64(conf.MaxPerAddress) {
+			return statecode.New(statecode.SubscriptionLimit)
+		}
+		subscription.ChainId, subscription.Address, subscription.Email, subscription.PoolId = chainId, address, req.Email, req.PoolId
+	}
+	subscription.Events = events
+	subscription.Language = i18n.Tag(lang)
+	if subscription.VerifiedAt == nil {
+		subscription.Token = utils.UniqueId()
+		subscription.ExpiresAt = time.Now().Add(time.Duration(conf.VerifyTtlHours) * time.Hour).Unix()
+	}
+
+	if exists {
+		err = subscription.Update()
+	} else {
+		err = subscription.Create()
+	}
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	if subscription.VerifiedAt == nil {
+		if err = s.sendVerifyEmail(subscription, lang); err != nil {
+			return statecode.Wrap(statecode.CommonErrServerErr, err)
+		}
+	}
+	*res = emailSubscriptionResponse(subscription)
+	return nil
+}
+
+// Verify 确认邮箱，记录当前池子状态，此前已发生的事件不再通知
+func (s *EmailSubscription) Verify(req *request.SubscriptionToken) error {
+	subscription := models.NewEmailSubscription()
+	err := subscription.GetByToken(req.Token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return statecode.New(statecode.SubscriptionNotFound)
+		}
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	if subscription.VerifiedAt != nil {
+		return nil
+	}
+	if time.Now().Unix() > subscription.ExpiresAt {
+		return statecode.New(statecode.SubscriptionExpired)
+	}
+
+	pool := models.NewPoolBases()
+	err = pool.Get(utils.StringToInt(subscription.ChainId), subscription.PoolId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return statecode.New(statecode.PoolNotFound)
+		}
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	if err = subscription.Verify(pool.State); err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	return nil
+}
+
+// Unsubscribe 删除订阅，验证前后都可以退订
+func (s *EmailSubscription) Unsubscribe(req *request.SubscriptionToken) error {
+	rows, err := models.NewEmailSubscription().DeleteByToken(req.Token)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	if rows == 0 {
+		return statecode.New(statecode.SubscriptionNotFound)
+	}
+	return nil
+}
+
+func (s *EmailSubscription) List(ctx context.Context, req *request.Subscriptions, res *[]response.EmailSubscription) error {
+	var list []models.EmailSubscription
+	err := models.NewEmailSubscription().List(ctx, strconv.Itoa(req.ChainId), common.HexToAddress(req.Address).Hex(), &list)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	for i := range list {
+		*res = append(*res, emailSubscriptionResponse(&list[i]))
+	}
+	return nil
+}
+
+func (s *EmailSubscription) sendVerifyEmail(subscription *models.EmailSubscription, lang int) error {
+	base := strings.TrimSuffix(config.Config().Subscription.LinkBaseUrl, "/")
+	events := subscription.EventList()
+	for i, event := range events {
+		events[i] = i18n.T(lang, "subscription.event."+event)
+	}
+	var body bytes.Buffer
+	err := verifyEmailTemplate.Execute(&body, map[string]interface{}{
+		"T":              func(key string, args ...interface{}) string { return i18n.T(lang, key, args...) },
+		"ChainId":        subscription.ChainId,
+		"PoolId":         subscription.PoolId,
+		"Address":        subscription.Address,
+		"Events":         strings.Join(events, ", "),
+		"VerifyUrl":      base + "/subscription/verify?token=" + subscription.Token,
+		"UnsubscribeUrl": base + "/subscription/unsubscribe?token=" + subscription.Token,
+		"ExpiresAt":      time.Unix(subscription.ExpiresAt, 0).UTC().Format("2006-01-02 15:04"),
+	})
+	if err != nil {
+		return err
+	}
+	return utils.SendEmailTo([]string{subscription.Email}, i18n.T(lang, "subscription.verify.subject"), body.Bytes())
+}
+
+// subscriptionEventsString 去重并排序，保存为逗号分隔的字符串
+func subscriptionEventsString(events []string) string {
+	seen := make(map[string]bool, len(events))
+	list := make([]string, 0, len(events))
+	for _, event := range events {
+		if !seen[event] {
+			seen[event] = true
+			list = append(list, event)
+		}
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
+
+func emailSubscriptionResponse(m *models.EmailSubscription) response.EmailSubscription {
+	return response.EmailSubscription{
+		ChainId:   utils.StringToInt(m.ChainId),
+		Address:   m.Address,
+		PoolId:    m.PoolId,
+		Email:     maskEmail(m.Email),
+		Events:    m.EventList(),
+		Verified:  m.VerifiedAt != nil,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// maskEmail 只保留用户名的第一个字符和域名，例如 alice@example.com -> a***@example.com
+func maskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return "***"
+	}
+	return email[:1] + "***" + email[at:]
+}
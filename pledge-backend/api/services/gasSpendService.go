@@ -0,0 +1,94 @@
+package services
+
+import (
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/config"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type GasSpend struct{}
+
+func NewGasSpend() *GasSpend {
+	return &GasSpend{}
+}
+
+// Summary 月度 gas 花费，按链汇总并附带 [gas] 预算，月份按服务器时区划分
+// enabled 的链当月没有交易时也返回，花费为 0，可以直接对照预算
+func (s *GasSpend) Summary(req *request.GasSummary, res *response.GasSummary) error {
+	start, err := time.ParseInLocation("2006-01", req.Month, time.Local)
+	if err != nil {
+		return statecode.Wrap(statecode.ParameterErr, err)
+	}
+	chainId := ""
+	if req.ChainId != 0 {
+		chainId = strconv.Itoa(req.ChainId)
+	}
+
+	var list []models.GasSpendSummary
+	err = models.NewGasSpend().Summary(chainId, start.Format("2006-01-02 15:04:05"), start.AddDate(0, 1, 0).Format("2006-01-02 15:04:05"), &list)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	res.Month = req.Month
+	res.Chains = make([]response.GasSummaryChain, 0)
+	index := make(map[string]int)
+	addChain := func(chainId string) *response.GasSummaryChain {
+		if i, ok := index[chainId]; ok {
+			return &res.Chains[i]
+		}
+		index[chainId] = len(res.Chains)
+		res.Chains = append(res.Chains, response.GasSummaryChain{
+			ChainId:  chainId,
+			Budget:   gasBudget(chainId),
+			Purposes: make([]response.GasSummaryPurpose, 0),
+		})
+		return &res.Chains[len(res.Chains)-1]
+	}
+	for _, v := range config.Config().EnabledChainIds() {
+		if chainId == "" || chainId == v {
+			addChain(v)
+		}
+	}
+
+	fees := make(map[string]decimal.Decimal)
+	for _, v := range list {
+		fee, _ := decimal.NewFromString(v.FeeWei)
+		chain := addChain(v.ChainId)
+		chain.TxCount += v.TxCount
+		chain.Purposes = append(chain.Purposes, response.GasSummaryPurpose{
+			Purpose:     v.Purpose,
+			TxCount:     v.TxCount,
+			FailedCount: v.FailedCount,
+			GasUsed:     v.GasUsed,
+			FeeBnb:      fee.Shift(-18).String(),
+		})
+		fees[v.ChainId] = fees[v.ChainId].Add(fee)
+	}
+	for i := range res.Chains {
+		res.Chains[i].FeeBnb = fees[res.Chains[i].ChainId].Shift(-18).String()
+	}
+	sort.Slice(res.Chains, func(i, j int) bool {
+		return res.Chains[i].ChainId < res.Chains[j].ChainId
+	})
+	return nil
+}
+
+// gasBudget 链的月度 gas 预算 (BNB)
+func gasBudget(chainId string) string {
+	switch chainId {
+	case config.Config().TestNet.ChainId:
+		return config.Config().Gas.TestnetMonthlyBudget
+	case config.Config().MainNet.ChainId:
+		return config.Config().Gas.MainnetMonthlyBudget
+	default:
+		return "0"
+	}
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/kucoin"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/models/ws"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	"strconv"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+type Health struct{}
+
+func NewHealth() *Health {
+	return &Health{}
+}
+
+// Readyz 检查 MySQL、Redis 连接，并附带喂价熔断器状态、各链最近一次喂价的写入决策和 [oracle] feeds 中各代币的喂价状态
+// 熔断器只影响 schedule 进程的链上写入，不影响 api 是否可用
+// MySQL 或 Redis 不可用时返回第一个错误，res 中仍然填写所有检查结果
+func (h *Health) Readyz(res *response.Readyz) error {
+	var failed error
+
+	res.Mysql = "ok"
+	sqlDB, err := db.Mysql.DB()
+	if err == nil {
+		err = sqlDB.Ping()
+	}
+	if err != nil {
+		res.Mysql = err.Error()
+		failed = statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	res.Redis = "ok"
+	if err = db.RedisPing(); err != nil {
+		res.Redis = err.Error()
+		if failed == nil {
+			failed = statecode.Wrap(statecode.CommonErrServerErr, err)
+		}
+	}
+
+	res.OracleBreaker = models.NewOracleBreaker().GetOracleBreaker("PLGR-USDT")
+	writes := map[string]*models.OracleWrite{}
+	for _, chainId := range []string{config.Config().TestNet.ChainId, config.Config().MainNet.ChainId} {
+		if write := models.NewOracleBreaker().GetOracleWrite(chainId, "PLGR-USDT"); write != nil {
+			writes[chainId] = write
+		}
+	}
+	res.OracleWrites = writes
+	feeds := map[string]response.OracleFeed{}
+	for _, token := range config.Config().Oracle.Feeds {
+		symbol := config.Config().Exchange.FeedSymbol(token)
+		feeds[symbol] = response.OracleFeed{
+			Token:   token,
+			Breaker: models.NewOracleBreaker().GetOracleBreaker(symbol),
+			Write:   models.NewOracleBreaker().GetOracleWrite(config.Config().MainNet.ChainId, symbol),
+		}
+	}
+	res.OracleFeeds = feeds
+	res.PriceDropped = kucoin.DroppedPrices()
+	return failed
+}
+
+// ChainsHealth 各链 RPC 节点最近一次的健康检查结果，按链分组
+func (h *Health) ChainsHealth(res *response.ChainsHealth) error {
+	var list []models.ChainHealth
+	if err := models.NewChainHealth().List(&list); err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	res.Chains = make([]response.ChainHealth, 0)
+	for _, v := range list {
+		if len(res.Chains) == 0 || res.Chains[len(res.Chains)-1].ChainId != v.ChainId {
+			res.Chains = append(res.Chains, response.ChainHealth{
+				ChainId:   v.ChainId,
+				Endpoints: make([]models.ChainHealth, 0),
+			})
+		}
+		chain := &res.Chains[len(res.Chains)-1]
+		chain.Healthy = chain.Healthy || v.Healthy
+		chain.Endpoints = append(chain.Endpoints, v)
+	}
+	return nil
+}
+
+// Integrity 各链最近一次抽查链上数据与 MySQL、Redis 的结果，还没有执行过的链不返回
+func (h *Health) Integrity(res *[]models.IntegrityReport) error {
+	*res = make([]models.IntegrityReport, 0)
+	for _, chainId := range []string{config.Config().TestNet.ChainId, config.Config().MainNet.ChainId} {
+		report := models.IntegrityReport{}
+		err := report.Get(chainId)
+		if err == redis.ErrNil {
+			continue
+		}
+		if err != nil {
+			return statecode.Wrap(statecode.CommonErrServerErr, err)
+		}
+		*res = append(*res, report)
+	}
+	return nil
+}
+
+// Network 链的最新区块、平均出块时间和 gas 价格建议，由 schedule 的 UpdateChainHealth 每次检查时更新
+// 尚未采集或采集已停止超过有效期时返回 NetworkStatusUnavailable
+func (h *Health) Network(req *request.Network, res *models.NetworkStatus) error {
+	err := res.Get(strconv.Itoa(req.ChainId))
+	if err == redis.ErrNil {
+		return statecode.New(statecode.NetworkStatusUnavailable)
+	}
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	return nil
+}
+
+// Jobs schedule 进程各定时任务的执行统计
+func (h *Health) Jobs(res *[]models.JobStats) error {
+	*res = make([]models.JobStats, 0)
+	if err := models.NewJobStats().List(res); err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	return nil
+}
+
+// JobRuns 定时任务最近的执行记录
+func (h *Health) JobRuns(req *request.JobRuns, res *[]models.JobRun) error {
+	*res = make([]models.JobRun, 0)
+	if err := models.NewJobRun().List(req.Name, req.Limit, res); err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	return nil
+}
+
+// jobRunPollInterval 读取 schedule 执行记录的间隔
+const jobRunPollInterval = 5 * time.Second
+
+// WatchJobRuns 定时读取 schedule 写入的执行记录，推送到私有主题 jobs，必须以 Goroutine 方式启动
+// 没有连接订阅时不查询；有订阅后只推送之后的记录，之前的记录在订阅时通过主题快照获取
+func WatchJobRuns() {
+	last := -1 // 为 -1 时从当前最新的记录开始
+	for {
+		time.Sleep(jobRunPollInterval)
+		if len(ws.Manager.SubscribedTopics(ws.TopicJobs)) == 0 {
+			last = -1
+			continue
+		}
+
+		if last < 0 {
+			id, err := models.NewJobRun().MaxId()
+			if err != nil {
+				log.Logger.Sugar().Error("WatchJobRuns err ", err)
+				continue
+			}
+			last = id
+		}
+		runs := make([]models.JobRun, 0)
+		if err := models.NewJobRun().After(last, 100, &runs); err != nil {
+			log.Logger.Sugar().Error("WatchJobRuns err ", err)
+			continue
+		}
+		for _, run := range runs {
+			ws.Manager.BroadcastMessage(ws.TopicJobs, run, ws.SuccessCode)
+			last = run.Id
+		}
+	}
+}
+
+// JobRetries 重试队列中的条目
+func (h *Health) JobRetries(req *request.JobRetries, res *[]models.JobRetry) error {
+	*res = make([]models.JobRetry, 0)
+	if err := models.NewJobRetry().List(req.Status, res); err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	return nil
+}
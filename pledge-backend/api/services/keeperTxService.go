@@ -0,0 +1,27 @@
+package services
+
+import (
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"strconv"
+)
+
+type KeeperTx struct{}
+
+func NewKeeperTx() *KeeperTx {
+	return &KeeperTx{}
+}
+
+// List keeper 最近的交易和尝试记录，新记录在前
+func (s *KeeperTx) List(req *request.KeeperTxs, res *[]models.KeeperTx) error {
+	chainId := ""
+	if req.ChainId != 0 {
+		chainId = strconv.Itoa(req.ChainId)
+	}
+	err := models.NewKeeperTx().List(chainId, req.Status, req.PoolId, req.Limit, res)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	return nil
+}
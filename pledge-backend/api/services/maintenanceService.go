@@ -0,0 +1,60 @@
+package services
+
+import (
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/ws"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"time"
+)
+
+type MaintenanceService struct{}
+
+func NewMaintenance() *MaintenanceService {
+	return &MaintenanceService{}
+}
+
+// Get 当前维护状态
+func (s *MaintenanceService) Get(res *models.Maintenance) {
+	*res = models.CurrentMaintenance()
+}
+
+// Set 开启或关闭维护模式，写入 Redis 后本实例立即生效并通知 WebSocket / SSE 客户端
+// 其他 API 实例由 WatchMaintenance 在 [maintenance] poll_interval 内同步
+func (s *MaintenanceService) Set(req *request.Maintenance, operator string, res *models.Maintenance) error {
+	state := models.Maintenance{
+		Enabled:   *req.Enabled,
+		Message:   req.Message,
+		Operator:  operator,
+		UpdatedAt: time.Now().Unix(),
+	}
+	if err := models.NewMaintenance().Set(state); err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	log.Logger.Sugar().Warn("maintenance mode ", state.Enabled, " by ", operator, ": ", state.Message)
+	applyMaintenance(state)
+	*res = state
+	return nil
+}
+
+// WatchMaintenance 定时从 Redis 同步维护状态，必须以 Goroutine 方式启动
+func WatchMaintenance() {
+	for {
+		state, err := models.NewMaintenance().Get()
+		if err != nil {
+			log.Logger.Sugar().Error("WatchMaintenance err ", err)
+		} else {
+			applyMaintenance(state)
+		}
+		time.Sleep(time.Duration(config.Config().Maintenance.PollInterval) * time.Second)
+	}
+}
+
+// applyMaintenance 更新本进程的维护状态，状态变化时广播给所有连接
+func applyMaintenance(state models.Maintenance) {
+	if models.SetCurrentMaintenance(state) {
+		ws.Manager.BroadcastMessage(ws.TopicMaintenance, state, ws.SuccessCode)
+	}
+}
@@ -1,13 +1,32 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"math/big"
 	"pledge-backend/api/common/statecode"
 	"pledge-backend/api/models"
 	"pledge-backend/api/models/request"
 	"pledge-backend/api/models/response"
+	"pledge-backend/config"
+	"pledge-backend/contract/bindings"
+	scheduleModels "pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"gorm.io/gorm"
 )
 
+// maxMultiSignOwners 读取链上 signatureOwners 的最大数量，合约没有提供数组长度，越界时 revert
+const maxMultiSignOwners = 64
+
 type MutiSignService struct{}
 
 func NewMutiSign() *MutiSignService {
@@ -15,22 +34,64 @@ func NewMutiSign() *MutiSignService {
 }
 
 // SetMultiSign Set Multi-Sign
-func (c *MutiSignService) SetMultiSign(mutiSign *request.SetMultiSign) (int, error) {
+// 配置了链上多签合约时先校验签名人和门限，保存后写入新版本，返回版本号和与上一版本的差异
+// ctx 为请求的 ctx，[env] request_timeout 到期后取消链上读取和数据库写入
+func (c *MutiSignService) SetMultiSign(ctx context.Context, mutiSign *request.SetMultiSign, operator string, res *response.MultiSignVersion) error {
+	err := c.checkOnChain(ctx, mutiSign)
+	if err != nil {
+		return err
+	}
+
+	previous := response.MultiSign{}
+	err = c.GetMultiSign(ctx, &previous, mutiSign.ChainId)
+	if err != nil {
+		return err
+	}
+	current := response.MultiSign{
+		SpName:           mutiSign.SpName,
+		SpToken:          mutiSign.SpToken,
+		JpName:           mutiSign.JpName,
+		JpToken:          mutiSign.JpToken,
+		SpAddress:        mutiSign.SpAddress,
+		JpAddress:        mutiSign.JpAddress,
+		SpHash:           mutiSign.SpHash,
+		JpHash:           mutiSign.JpHash,
+		MultiSignAccount: mutiSign.MultiSignAccount,
+		Threshold:        mutiSign.Threshold,
+	}
+	diff := multiSignDiff(previous, current)
+	configJson, _ := json.Marshal(current)
+	diffJson, _ := json.Marshal(diff)
+
 	//db set
-	err := models.NewMultiSign().Set(mutiSign)
+	history := models.MultiSignHistory{
+		Config:   string(configJson),
+		Diff:     string(diffJson),
+		Operator: operator,
+	}
+	err = models.NewMultiSign().Set(ctx, mutiSign, &history)
 	if err != nil {
-		return statecode.CommonErrServerErr, err
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	current.Version = history.Version
+	*res = response.MultiSignVersion{
+		Version:   history.Version,
+		Operator:  history.Operator,
+		CreatedAt: history.CreatedAt,
+		Config:    current,
+		Diff:      diff,
 	}
-	return statecode.CommonSuccess, nil
+	return nil
 }
 
 // GetMultiSign Get Multi-Sign
-func (c *MutiSignService) GetMultiSign(mutiSign *response.MultiSign, chainId int) (int, error) {
+func (c *MutiSignService) GetMultiSign(ctx context.Context, mutiSign *response.MultiSign, chainId int) error {
 	//db get
 	multiSignModel := models.NewMultiSign()
-	err := multiSignModel.Get(chainId)
+	err := multiSignModel.Get(ctx, chainId)
 	if err != nil {
-		return statecode.CommonErrServerErr, err
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
 	}
 	var multiSignAccount []string
 	_ = json.Unmarshal([]byte(multiSignModel.MultiSignAccount), &multiSignAccount)
@@ -44,5 +105,138 @@ func (c *MutiSignService) GetMultiSign(mutiSign *response.MultiSign, chainId int
 	mutiSign.SpHash = multiSignModel.SpHash
 	mutiSign.JpHash = multiSignModel.JpHash
 	mutiSign.MultiSignAccount = multiSignAccount
-	return statecode.CommonSuccess, nil
+
+	// 门限和版本号只记录在版本历史中，引入版本历史之前保存的配置版本为 0
+	history := models.NewMultiSignHistory()
+	err = history.Latest(ctx, chainId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	} else if err == nil {
+		latest := response.MultiSign{}
+		_ = json.Unmarshal([]byte(history.Config), &latest)
+		mutiSign.Threshold = latest.Threshold
+		mutiSign.Version = history.Version
+	}
+	return nil
+}
+
+// History 多签配置的版本历史，新版本在前
+func (c *MutiSignService) History(ctx context.Context, req *request.MultiSignHistory, res *[]response.MultiSignVersion) error {
+	var versions []models.MultiSignHistory
+	err := models.NewMultiSignHistory().List(ctx, req.ChainId, req.Limit, &versions)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	for _, v := range versions {
+		version := response.MultiSignVersion{
+			Version:   v.Version,
+			Operator:  v.Operator,
+			CreatedAt: v.CreatedAt,
+			Diff:      []response.MultiSignChange{},
+		}
+		_ = json.Unmarshal([]byte(v.Config), &version.Config)
+		_ = json.Unmarshal([]byte(v.Diff), &version.Diff)
+		version.Config.Version = v.Version
+		*res = append(*res, version)
+	}
+	return nil
+}
+
+// checkOnChain 提交的签名人 (不区分大小写和顺序) 和门限必须与链上多签合约 multi_sign_address 一致
+// 不一致时在 details 中返回链上的值；链不是 [testnet] / [mainnet] 中 enabled 的链或没有配置多签合约时拒绝，不跳过校验
+func (c *MutiSignService) checkOnChain(ctx context.Context, mutiSign *request.SetMultiSign) error {
+	chainId := utils.IntToString(mutiSign.ChainId)
+	var address string
+	switch {
+	case chainId == config.Config().TestNet.ChainId && config.Config().TestNet.Enabled:
+		address = config.Config().TestNet.MultiSignAddress
+	case chainId == config.Config().MainNet.ChainId && config.Config().MainNet.Enabled:
+		address = config.Config().MainNet.MultiSignAddress
+	default:
+		return statecode.New(statecode.ChainIdErr)
+	}
+	if address == "" {
+		return statecode.Wrap(statecode.CommonErrServerErr, errors.New("multi_sign_address is not configured for chain "+chainId))
+	}
+	netUrl := scheduleModels.NewChainHealth().RpcUrl(chainId)
+
+	ethereumConn, err := ethclient.DialContext(ctx, netUrl)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	defer ethereumConn.Close()
+	multiSign, err := bindings.NewMultiSignature(common.HexToAddress(address), ethereumConn)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	opts := &bind.CallOpts{Context: ctx}
+	threshold, err := multiSign.Threshold(opts)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	owners := make([]string, 0)
+	for i := 0; i < maxMultiSignOwners; i++ {
+		owner, err := multiSign.SignatureOwners(opts, big.NewInt(int64(i)))
+		if err != nil {
+			if strings.Contains(err.Error(), "execution reverted") {
+				break
+			}
+			return statecode.Wrap(statecode.CommonErrServerErr, err)
+		}
+		owners = append(owners, strings.ToLower(owner.Hex()))
+	}
+
+	if !reflect.DeepEqual(normalizeSigners(mutiSign.MultiSignAccount), normalizeSigners(owners)) {
+		return statecode.New(statecode.MultiSignSignerErr).WithDetails(map[string]interface{}{"on_chain_signers": owners})
+	}
+	if int64(mutiSign.Threshold) != threshold.Int64() {
+		return statecode.New(statecode.MultiSignThresholdErr).WithDetails(map[string]interface{}{"on_chain_threshold": threshold.Int64()})
+	}
+	return nil
+}
+
+// normalizeSigners 转小写、去重、排序后的签名人地址
+func normalizeSigners(signers []string) []string {
+	set := make(map[string]bool, len(signers))
+	for _, signer := range signers {
+		set[strings.ToLower(signer)] = true
+	}
+	res := make([]string, 0, len(set))
+	for signer := range set {
+		res = append(res, signer)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// multiSignDiff 两个版本之间变化的字段，签名人列表不区分大小写和顺序
+func multiSignDiff(previous, current response.MultiSign) []response.MultiSignChange {
+	diff := make([]response.MultiSignChange, 0)
+	fields := []struct {
+		name     string
+		old, new interface{}
+	}{
+		{"sp_name", previous.SpName, current.SpName},
+		{"_spToken", previous.SpToken, current.SpToken},
+		{"jp_name", previous.JpName, current.JpName},
+		{"_jpToken", previous.JpToken, current.JpToken},
+		{"sp_address", previous.SpAddress, current.SpAddress},
+		{"jp_address", previous.JpAddress, current.JpAddress},
+		{"spHash", previous.SpHash, current.SpHash},
+		{"jpHash", previous.JpHash, current.JpHash},
+		{"threshold", previous.Threshold, current.Threshold},
+	}
+	for _, f := range fields {
+		if f.old != f.new {
+			diff = append(diff, response.MultiSignChange{Field: f.name, Old: f.old, New: f.new})
+		}
+	}
+	if !reflect.DeepEqual(normalizeSigners(previous.MultiSignAccount), normalizeSigners(current.MultiSignAccount)) {
+		diff = append(diff, response.MultiSignChange{Field: "multi_sign_account", Old: previous.MultiSignAccount, New: current.MultiSignAccount})
+	}
+	return diff
 }
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/ws"
+	"pledge-backend/log"
+	"time"
+)
+
+// opsEventPollInterval 读取 schedule 运维事件的间隔
+const opsEventPollInterval = 2 * time.Second
+
+// WatchOpsEvents 定时读取 schedule 发布的运维事件 (任务结果、喂价交易、节点健康变化、告警)，推送到私有主题 ops，必须以 Goroutine 方式启动
+// 没有连接订阅时不读取；订阅时通过主题快照获取最近的事件，之后只推送新的事件
+func WatchOpsEvents() {
+	last := time.Now().UnixMilli()
+	for {
+		time.Sleep(opsEventPollInterval)
+		if len(ws.Manager.SubscribedTopics(ws.TopicOps)) == 0 {
+			last = time.Now().UnixMilli()
+			continue
+		}
+
+		events := make([]models.OpsEvent, 0)
+		if err := models.NewOpsEvent().Since(last, &events); err != nil {
+			log.Logger.Sugar().Error("WatchOpsEvents err ", err)
+			continue
+		}
+		for _, event := range events {
+			ws.Manager.BroadcastMessage(ws.TopicOps, event, ws.SuccessCode)
+			if event.At > last {
+				last = event.At
+			}
+		}
+	}
+}
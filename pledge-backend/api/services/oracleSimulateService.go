@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/config"
+	"pledge-backend/contract/bindings"
+	"pledge-backend/log"
+	scheduleModels "pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/shopspring/decimal"
+)
+
+// oracleTestNetPrice 测试网固定喂价，与 schedule 的 SavePlgrPriceTestNet 一致, 1e8 精度
+const oracleTestNetPrice = 22222
+
+type OracleSimulate struct{}
+
+func NewOracleSimulate() *OracleSimulate {
+	return &OracleSimulate{}
+}
+
+// SimulateSetPrice 以喂价签名地址对 Oracle 的 setPrice 执行 eth_call 和 eth_estimateGas，不发送交易
+//
+// 调用数据与 schedule 写链时相同，运维修改 [oracle]、[exchange.tokens] 或更换签名私钥后，
+// 可以在下一次定时写入前确认交易能否成功、需要多少 gas 以及写入后的价格
+func (s *OracleSimulate) SimulateSetPrice(req *request.OracleSimulateSetPrice, res *response.OracleSimulateSetPrice) error {
+	netUrl, oracleAddress, plgr := scheduleModels.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId), config.Config().MainNet.BscPledgeOracleToken, config.Config().MainNet.PlgrAddress
+	tokens := NewOracleStatus().tokens()
+	chainId := utils.IntToString(req.ChainId)
+	testNet := chainId == config.Config().TestNet.ChainId
+	if testNet {
+		netUrl, oracleAddress, plgr = scheduleModels.NewChainHealth().RpcUrl(config.Config().TestNet.ChainId), config.Config().TestNet.BscPledgeOracleToken, config.Config().TestNet.PlgrAddress
+		tokens = []string{plgr}
+	}
+	if req.Token == "" {
+		req.Token = plgr
+	}
+	res.ChainId = chainId
+	res.Oracle = oracleAddress
+	res.Token = req.Token
+
+	// 只模拟 schedule 会写入的代币
+	found := false
+	for _, token := range tokens {
+		found = found || strings.EqualFold(token, req.Token)
+	}
+	if !found {
+		return statecode.New(statecode.OracleFeedErr)
+	}
+	res.Symbol = "PLGR-USDT"
+	if !testNet {
+		if _, ok := config.Config().Exchange.TokenRoute(req.Token); ok {
+			res.Symbol = config.Config().Exchange.FeedSymbol(req.Token)
+		}
+	}
+
+	// 待写入的价格
+	switch {
+	case req.Price != "":
+		price, _ := decimal.NewFromString(req.Price)
+		res.PriceE8 = price.Shift(8).IntPart()
+		res.PriceSource = "request"
+	case testNet:
+		res.PriceE8 = oracleTestNetPrice
+		res.PriceSource = "testnet_fixed"
+	default:
+		feed := response.OracleFeedStatus{Route: []string{"PLGR-USDT"}}
+		if route, ok := config.Config().Exchange.TokenRoute(req.Token); ok {
+			feed.Route = route
+		}
+		res.PriceE8 = NewOracleStatus().exchangePrice(&feed).Shift(8).IntPart()
+		res.PriceSource = "exchange"
+	}
+	if res.PriceE8 <= 0 {
+		return statecode.New(statecode.OraclePriceUnavailable)
+	}
+	res.Price = decimal.New(res.PriceE8, -8).String()
+
+	from, err := s.signer()
+	if err != nil {
+		return statecode.Wrap(statecode.OracleSignerUnavailable, err)
+	}
+	res.From = from.Hex()
+
+	conn, err := ethclient.Dial(netUrl)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		res.Error = err.Error()
+		return nil
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// 链上当前价格和下一次定时写入是否会跳过，与 schedule 的 OracleWrite.ShouldWrite 一致
+	oracle, err := bindings.NewBscPledgeOracleMainnetToken(common.HexToAddress(oracleAddress), conn)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	onChain, err := oracle.GetPrice(&bind.CallOpts{Context: ctx}, common.HexToAddress(req.Token))
+	if err != nil {
+		res.Error = err.Error()
+		return nil
+	}
+	res.OnChainPrice = decimal.NewFromBigInt(onChain, -8).String()
+	if onChain.Sign() > 0 {
+		res.DeltaBps = decimal.NewFromInt(res.PriceE8).Sub(decimal.NewFromBigInt(onChain, 0)).Abs().
+			Shift(4).Div(decimal.NewFromBigInt(onChain, 0)).IntPart()
+		res.WouldSkip = res.DeltaBps < config.Config().Oracle.MinChangeBps
+	}
+
+	oracleAbi, err := bindings.BscPledgeOracleMainnetTokenMetaData.GetAbi()
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	data, err := oracleAbi.Pack("setPrice", common.HexToAddress(req.Token), big.NewInt(res.PriceE8))
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	to := common.HexToAddress(oracleAddress)
+	msg := ethereum.CallMsg{From: from, To: &to, Data: data}
+
+	// eth_call 取得 revert 原因，eth_estimateGas 在 revert 时只返回笼统的错误
+	res.ResultPrice = res.OnChainPrice
+	if _, err = conn.CallContract(ctx, msg, nil); err != nil {
+		if reason, ok := revertReason(err); ok {
+			res.Reverted = true
+			res.RevertReason = reason
+		} else {
+			res.Error = err.Error()
+		}
+		return nil
+	}
+	res.ResultPrice = res.Price
+
+	res.Gas, err = conn.EstimateGas(ctx, msg)
+	if err != nil {
+		res.Error = err.Error()
+		return nil
+	}
+	gasPrice, err := conn.SuggestGasPrice(ctx)
+	if err != nil {
+		res.Error = err.Error()
+		return nil
+	}
+	res.GasPrice = gasPrice.String()
+	res.Fee = new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(res.Gas)).String()
+	return nil
+}
+
+// signer 喂价签名地址，私钥的读取与 schedule 的 common.GetEnv 一致
+func (s *OracleSimulate) signer() (common.Address, error) {
+	key := config.Config().Oracle.SignerKey
+	if key == "" && config.Config().Devnet.Enabled {
+		key = config.Config().Devnet.PrivateKey
+	}
+	if key == "" {
+		return common.Address{}, errors.New("plgr_admin_private_key is not set")
+	}
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(key, "0x"))
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(privateKey.PublicKey), nil
+}
+
+// revertReason 从 eth_call 的错误中取出 revert 原因，不是 revert 的错误 (RPC 不可用等) 返回 false
+func revertReason(err error) (string, bool) {
+	if dataErr, ok := err.(rpc.DataError); ok {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if raw, decodeErr := hexutil.Decode(data); decodeErr == nil {
+				if reason, unpackErr := abi.UnpackRevert(raw); unpackErr == nil {
+					return reason, true
+				}
+			}
+		}
+	}
+	if strings.Contains(err.Error(), "execution reverted") {
+		return err.Error(), true
+	}
+	return "", false
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/kucoin"
+	"pledge-backend/api/models/response"
+	"pledge-backend/config"
+	"pledge-backend/contract/bindings"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	scheduleModels "pledge-backend/schedule/models"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+)
+
+type OracleStatus struct{}
+
+func NewOracleStatus() *OracleStatus {
+	return &OracleStatus{}
+}
+
+// Status 汇总主网 Oracle 中 PLGR 和 [oracle] feeds 各代币的状态，供值班排查喂价问题
+//
+// 链上价格每次请求实时读取，RPC 不可用时记录在 on_chain_err，其余字段照常返回；
+// 交易所价格、熔断器和写入决策来自 Redis，写入交易的状态来自 gas_spend
+func (s *OracleStatus) Status(res *response.OracleStatus) {
+	res.ChainId = config.Config().MainNet.ChainId
+	res.Feeds = make([]response.OracleFeedStatus, 0)
+
+	var oracle *bindings.BscPledgeOracleMainnetToken
+	conn, err := ethclient.Dial(scheduleModels.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId))
+	if err == nil {
+		defer conn.Close()
+		oracle, err = bindings.NewBscPledgeOracleMainnetToken(common.HexToAddress(config.Config().MainNet.BscPledgeOracleToken), conn)
+	}
+	if err != nil {
+		log.Logger.Error(err.Error())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, token := range s.tokens() {
+		feed := response.OracleFeedStatus{Token: token, Symbol: "PLGR-USDT", Route: []string{"PLGR-USDT"}}
+		if route, ok := config.Config().Exchange.TokenRoute(token); ok {
+			feed.Route = route
+			feed.Symbol = config.Config().Exchange.FeedSymbol(token)
+		}
+
+		onChain := decimal.Zero
+		if oracle == nil {
+			feed.OnChainErr = err.Error()
+		} else if price, readErr := oracle.GetPrice(&bind.CallOpts{Context: ctx}, common.HexToAddress(token)); readErr != nil {
+			feed.OnChainErr = readErr.Error()
+		} else {
+			onChain = decimal.NewFromBigInt(price, -8)
+			feed.OnChainPrice = onChain.String()
+		}
+
+		exchange := s.exchangePrice(&feed)
+		if onChain.IsPositive() && exchange.IsPositive() {
+			feed.Deviation = onChain.Sub(exchange).Abs().Div(exchange).StringFixed(6)
+		}
+
+		feed.Breaker = models.NewOracleBreaker().GetOracleBreaker(feed.Symbol)
+		feed.LastWrite = models.NewOracleBreaker().GetOracleWrite(res.ChainId, feed.Symbol)
+		if feed.LastWrite != nil {
+			feed.LastUpdateAt = feed.LastWrite.LastWriteAt
+			spend := models.GasSpend{}
+			if feed.LastWrite.TxHash != "" && models.NewGasSpend().GetByTxHash(feed.LastWrite.TxHash, &spend) == nil {
+				feed.TxStatus = spend.Status
+			}
+		}
+		res.Feeds = append(res.Feeds, feed)
+	}
+}
+
+// tokens 写入主网 Oracle 的代币，与 schedule 的 SavePlgrPrice 一致: PLGR 以及 [oracle] feeds
+func (s *OracleStatus) tokens() []string {
+	tokens := []string{config.Config().MainNet.PlgrAddress}
+	for _, token := range config.Config().Oracle.Feeds {
+		if !strings.EqualFold(token, config.Config().MainNet.PlgrAddress) {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// exchangePrice 定价路径上各交易对最新价格的乘积，任一交易对没有价格时返回 0
+func (s *OracleStatus) exchangePrice(feed *response.OracleFeedStatus) decimal.Decimal {
+	price := decimal.NewFromInt(1)
+	for _, symbol := range feed.Route {
+		priceStr, err := db.RedisGetString(kucoin.PriceRedisKey(symbol))
+		if err != nil {
+			return decimal.Zero
+		}
+		legPrice, err := decimal.NewFromString(priceStr)
+		if err != nil {
+			return decimal.Zero
+		}
+		price = price.Mul(legPrice)
+
+		updatedAt, err := db.RedisGetInt64(kucoin.PriceTimeRedisKey(symbol))
+		if err == nil && (feed.ExchangeUpdatedAt == 0 || updatedAt < feed.ExchangeUpdatedAt) {
+			feed.ExchangeUpdatedAt = updatedAt
+		}
+	}
+	feed.ExchangePrice = price.String()
+	return price
+}
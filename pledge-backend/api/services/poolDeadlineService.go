@@ -0,0 +1,32 @@
+package services
+
+import (
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/ws"
+	"pledge-backend/log"
+	"time"
+)
+
+// poolDeadlinePollInterval 读取 schedule 倒计时通知的间隔
+const poolDeadlinePollInterval = 5 * time.Second
+
+// WatchPoolDeadlines 定时读取 schedule 写入的倒计时通知，推送到 deadline:<chainId> 主题，必须以 Goroutine 方式启动
+// 只推送本进程启动之后的通知，之前的通知在订阅时通过主题快照获取
+func WatchPoolDeadlines() {
+	last := time.Now().UnixMilli()
+	for {
+		time.Sleep(poolDeadlinePollInterval)
+
+		events := make([]models.PoolDeadline, 0)
+		if err := models.NewPoolDeadline().Since("", last, &events); err != nil {
+			log.Logger.Sugar().Error("WatchPoolDeadlines err ", err)
+			continue
+		}
+		for _, event := range events {
+			ws.Manager.BroadcastMessage(ws.TopicDeadlinePrefix+event.ChainId, event, ws.SuccessCode)
+			if event.NotifiedAt > last {
+				last = event.NotifiedAt
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gorm.io/gorm"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+)
+
+type PoolMetadata struct{}
+
+func NewPoolMetadata() *PoolMetadata {
+	return &PoolMetadata{}
+}
+
+func (s *PoolMetadata) List(ctx context.Context, req *request.PoolMetadataList, res *[]response.PoolMetadata) error {
+	var list []models.PoolMetadata
+	err := models.NewPoolMetadata().List(ctx, req.ChainId, &list)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	for i := range list {
+		*res = append(*res, poolMetadataResponse(&list[i]))
+	}
+	return nil
+}
+
+// Save 池子必须已由 schedule 同步到 poolbases，重复的标签只保留一个
+func (s *PoolMetadata) Save(req *request.SavePoolMetadata, operator string, res *response.PoolMetadata) error {
+	err := models.NewPoolBases().Get(req.ChainId, req.PoolId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return statecode.New(statecode.PoolNotFound)
+		}
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	tags := make([]string, 0, len(req.Tags))
+	seen := make(map[string]bool, len(req.Tags))
+	for _, tag := range req.Tags {
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	tagsJson, _ := json.Marshal(tags)
+
+	metadata := models.PoolMetadata{
+		ChainId:     req.ChainId,
+		PoolId:      req.PoolId,
+		Name:        req.Name,
+		Description: req.Description,
+		Tags:        string(tagsJson),
+		Featured:    req.Featured,
+		UpdatedBy:   operator,
+	}
+	err = metadata.Save()
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	*res = poolMetadataResponse(&metadata)
+	return nil
+}
+
+func (s *PoolMetadata) Delete(req *request.DeletePoolMetadata) error {
+	rows, err := models.NewPoolMetadata().Delete(req.ChainId, req.PoolId)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	if rows == 0 {
+		return statecode.New(statecode.PoolMetadataNotFound)
+	}
+	return nil
+}
+
+func poolMetadataResponse(m *models.PoolMetadata) response.PoolMetadata {
+	return response.PoolMetadata{
+		ChainId:     m.ChainId,
+		PoolId:      m.PoolId,
+		Name:        m.Name,
+		Description: m.Description,
+		Tags:        m.TagList(),
+		Featured:    m.Featured,
+		UpdatedBy:   m.UpdatedBy,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
@@ -1,9 +1,19 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
 	"pledge-backend/api/common/statecode"
 	"pledge-backend/api/models"
-	"pledge-backend/log"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/utils"
+	"strconv"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/shopspring/decimal"
 )
 
 type poolService struct{}
@@ -12,22 +22,336 @@ func NewPool() *poolService {
 	return &poolService{}
 }
 
-func (s *poolService) PoolBaseInfo(chainId int, result *[]models.PoolBaseInfoRes) int {
+// PoolBaseInfo ctx 为请求的 ctx，用于链路追踪，archived 见 models.ArchivedCondition
+// 并发的相同查询合并为一次，见 coalesce
+func (s *poolService) PoolBaseInfo(ctx context.Context, chainId int, archived string, result *[]models.PoolBaseInfoRes) error {
+
+	rows, err := coalesce(ctx, "poolBaseInfo:"+strconv.Itoa(chainId)+":"+archived, func(ctx context.Context) (interface{}, error) {
+		var rows []models.PoolBaseInfoRes
+		err := models.NewPoolBases().PoolBaseInfo(ctx, chainId, archived, &rows)
+		return rows, err
+	})
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	*result = append(*result, rows.([]models.PoolBaseInfoRes)...)
+	return nil
+}
+
+// PoolDataInfo ctx 为请求的 ctx，用于链路追踪
+// 并发的相同查询合并为一次，见 coalesce
+func (s *poolService) PoolDataInfo(ctx context.Context, chainId int, result *[]models.PoolDataInfoRes) error {
+
+	rows, err := coalesce(ctx, "poolDataInfo:"+strconv.Itoa(chainId), func(ctx context.Context) (interface{}, error) {
+		var rows []models.PoolDataInfoRes
+		err := models.NewPoolData().PoolDataInfo(ctx, chainId, &rows)
+		return rows, err
+	})
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	*result = append(*result, rows.([]models.PoolDataInfoRes)...)
+	return nil
+}
+
+// PoolDetail 单个池子详情，并按 token_info 中的当前价格计算利用率、年化、抵押率和阶段倒计时
+//
+// 金额均为代币最小单位，价格为 1e8 精度，interestRate、martgageRate、lendFee 为 1e8 精度
+func (s *poolService) PoolDetail(req *request.PoolDetail, res *models.PoolDetail) error {
+	pool := models.NewPoolBases()
+	err := pool.PoolDetail(req.ChainId, req.PoolId, res)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return statecode.New(statecode.PoolNotFound)
+		}
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	err, lendToken := pool.GetPoolToken(req.ChainId, res.LendToken)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	err, borrowToken := pool.GetPoolToken(req.ChainId, res.BorrowToken)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	res.LendTokenPrice = lendToken.Price
+	res.BorrowTokenPrice = borrowToken.Price
+
+	precision := decimal.NewFromInt(100000000)
+	lendSupply := toDecimal(res.LendSupply)
+	maxSupply := toDecimal(res.MaxSupply)
+	martgageRate := toDecimal(res.MartgageRate)
+
+	// 出借和抵押的美元价值
+	lendValue := tokenValue(lendSupply, lendToken)
+	borrowValue := tokenValue(toDecimal(res.BorrowSupply), borrowToken)
+
+	fillRatio := decimal.Zero
+	if maxSupply.IsPositive() {
+		fillRatio = lendSupply.Div(maxSupply)
+	}
+
+	// 结算前: 抵押品按抵押率最多能借出的价值 / 出借价值；结算后以链上结算金额为准
+	utilization := decimal.Zero
+	settleAmountLend := toDecimal(res.PoolData.SettleAmountLend)
+	if res.State != models.PoolStateMatch && settleAmountLend.IsPositive() && lendSupply.IsPositive() {
+		utilization = settleAmountLend.Div(lendSupply)
+	} else if lendValue.IsPositive() && martgageRate.IsPositive() {
+		utilization = borrowValue.Mul(precision).Div(martgageRate).Div(lendValue)
+	}
+	if utilization.GreaterThan(decimal.NewFromInt(1)) {
+		utilization = decimal.NewFromInt(1)
+	}
+
+	lendFee := toDecimal(res.LendTokenInfo.LendFee).Div(precision)
+	lenderApy := toDecimal(res.InterestRate).Div(precision).Mul(utilization).Mul(decimal.NewFromInt(1).Sub(lendFee))
+
+	// 抵押率: 抵押品价值 / 实际借出价值
+	collateralization := decimal.Zero
+	borrowedValue := lendValue.Mul(utilization)
+	if borrowedValue.IsPositive() {
+		collateralization = borrowValue.Div(borrowedValue)
+	}
+
+	res.FillRatio = fillRatio.StringFixed(4)
+	res.UtilizationRatio = utilization.StringFixed(4)
+	res.LenderApy = lenderApy.StringFixed(4)
+	res.CollateralizationRate = collateralization.StringFixed(4)
+
+	now := time.Now().Unix()
+	res.TimeToSettle = countdown(res.SettleTime, now)
+	res.TimeToEnd = countdown(res.EndTime, now)
+	switch res.State {
+	case models.PoolStateMatch:
+		res.Phase = "match"
+		res.PhaseEndsIn = res.TimeToSettle
+	case models.PoolStateExecution:
+		res.Phase = "execution"
+		res.PhaseEndsIn = res.TimeToEnd
+	case models.PoolStateFinish:
+		res.Phase = "finish"
+	case models.PoolStateLiquidation:
+		res.Phase = "liquidation"
+	case models.PoolStateUndone:
+		res.Phase = "undone"
+	}
+	return nil
+}
+
+// PoolHistory 池子历史快照，并从相邻快照中提取状态变化
+func (s *poolService) PoolHistory(req *request.PoolHistory, res *response.PoolHistory) error {
+	res.Points = []models.PoolSnapshot{}
+	res.StateChanges = []response.PoolStateChange{}
+	err := models.NewPoolSnapshot().History(req.ChainId, req.PoolId, req.From, req.To, req.Limit, &res.Points)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	for i := 1; i < len(res.Points); i++ {
+		if res.Points[i].State != res.Points[i-1].State {
+			res.StateChanges = append(res.StateChanges, response.PoolStateChange{
+				From:       res.Points[i-1].State,
+				To:         res.Points[i].State,
+				SnapshotAt: res.Points[i].SnapshotAt,
+			})
+		}
+	}
+	return nil
+}
+
+// PoolEstimate 按合约 finish() 的计算方式估算到期利息、手续费和到期价值
+//
+// 利息 = 本金 * interestRate * (endTime - settleTime) / 365 天，与合约一样按整数截断；
+// 借款方向按 token_info 当前价格估算可借金额，实际以结算时的价格为准
+func (s *poolService) PoolEstimate(req *request.PoolEstimate, res *response.PoolEstimate) error {
+	pool := models.NewPoolBases()
+	detail := models.PoolDetail{}
+	err := pool.PoolDetail(req.ChainId, req.PoolId, &detail)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return statecode.New(statecode.PoolNotFound)
+		}
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	lendToken := models.PoolToken{}
+	borrowToken := models.PoolToken{}
+	if req.Side != "lend" {
+		err, lendToken = pool.GetPoolToken(req.ChainId, detail.LendToken)
+		if err != nil {
+			return statecode.Wrap(statecode.CommonErrServerErr, err)
+		}
+		err, borrowToken = pool.GetPoolToken(req.ChainId, detail.BorrowToken)
+		if err != nil {
+			return statecode.Wrap(statecode.CommonErrServerErr, err)
+		}
+	}
+	return estimate(req, detail, lendToken, borrowToken, res)
+}
+
+// estimate PoolEstimate 的计算部分，lend 方向不使用 lendToken / borrowToken
+func estimate(req *request.PoolEstimate, detail models.PoolDetail, lendToken, borrowToken models.PoolToken, res *response.PoolEstimate) error {
+	precision := decimal.NewFromInt(100000000)
+	amount := toDecimal(req.Amount)
+	interestRate := toDecimal(detail.InterestRate)
+	lendFee := toDecimal(detail.LendTokenInfo.LendFee)
+	borrowFee := toDecimal(detail.BorrowTokenInfo.BorrowFee)
+	duration := utils.StringToInt64(detail.EndTime) - utils.StringToInt64(detail.SettleTime)
+	if duration < 0 {
+		duration = 0
+	}
+	// timeRatio = duration * 1e8 / baseYear，interest = timeRatio * interestRate * principal / 1e16
+	timeRatio := decimal.NewFromInt(duration).Mul(precision).Div(decimal.NewFromInt(365 * 24 * 3600)).Floor()
+	interest := func(principal decimal.Decimal) decimal.Decimal {
+		return timeRatio.Mul(interestRate).Mul(principal).Shift(-16).Floor()
+	}
+
+	res.Side = req.Side
+	res.Amount = req.Amount
+	res.Duration = duration
+	res.InterestRate = detail.InterestRate
+	res.LendFee = "0"
+	res.CollateralSold = "0"
+	res.BorrowFee = "0"
+
+	if req.Side == "lend" {
+		lendInterest := interest(amount)
+		res.Principal = amount.String()
+		res.Interest = lendInterest.String()
+		res.MaturityValue = amount.Add(lendInterest).String()
+		res.MaturityToken = detail.LendToken
+		return nil
+	}
+
+	martgageRate := toDecimal(detail.MartgageRate)
+	if !toDecimal(lendToken.Price).IsPositive() || !toDecimal(borrowToken.Price).IsPositive() || !martgageRate.IsPositive() {
+		return statecode.New(statecode.PoolTokenPriceErr)
+	}
+
+	// 抵押品价值 / 抵押率 = 可借出金额 (出借代币最小单位)
+	principal := tokenValue(amount, borrowToken).Mul(precision).Div(martgageRate).
+		Shift(8).Div(toDecimal(lendToken.Price)).Shift(int32(lendToken.Decimals)).Floor()
+	borrowInterest := interest(principal)
+	lendAmount := principal.Add(borrowInterest)
+	sellAmount := lendAmount.Mul(lendFee.Add(precision)).Div(precision).Floor()
+
+	// 卖出抵押品换回 sellAmount 出借代币，剩余抵押品扣除 borrowFee 后返还
+	sold := tokenValue(sellAmount, lendToken).Shift(8).Div(toDecimal(borrowToken.Price)).Shift(int32(borrowToken.Decimals)).Ceil()
+	if sold.GreaterThan(amount) {
+		sold = amount
+	}
+	remain := amount.Sub(sold)
+	fee := remain.Mul(borrowFee).Div(precision).Floor()
+
+	res.Principal = principal.String()
+	res.Interest = borrowInterest.String()
+	res.LendFee = sellAmount.Sub(lendAmount).String()
+	res.CollateralSold = sold.String()
+	res.BorrowFee = fee.String()
+	res.MaturityValue = remain.Sub(fee).String()
+	res.MaturityToken = detail.BorrowToken
+	return nil
+}
+
+// PoolStats 根据 pool_events 统计出借/抵押两侧的参与人数、存入次数、平均存入和最大持仓
+func (s *poolService) PoolStats(req *request.PoolStats, res *response.PoolStats) error {
+	sides := map[string]*response.PoolSideStats{
+		models.PoolEventDepositLend:   &res.Lend,
+		models.PoolEventDepositBorrow: &res.Borrow,
+	}
+	for event, side := range sides {
+		stats := models.PoolEventStats{}
+		err := models.NewPoolEvent().Stats(req.ChainId, req.PoolId, event, &stats)
+		if err != nil {
+			return statecode.Wrap(statecode.CommonErrServerErr, err)
+		}
+		side.TopPositions = []models.PoolPosition{}
+		err = models.NewPoolEvent().TopPositions(req.ChainId, req.PoolId, event, req.Top, &side.TopPositions)
+		if err != nil {
+			return statecode.Wrap(statecode.CommonErrServerErr, err)
+		}
 
-	err := models.NewPoolBases().PoolBaseInfo(chainId, result)
+		total := toDecimal(stats.Total)
+		average := decimal.Zero
+		if stats.Deposits > 0 {
+			average = total.Div(decimal.NewFromInt(stats.Deposits)).Floor()
+		}
+		side.Participants = stats.Participants
+		side.Deposits = stats.Deposits
+		side.TotalDeposit = total.String()
+		side.AverageDeposit = average.String()
+	}
+	return nil
+}
+
+func toDecimal(s string) decimal.Decimal {
+	d, err := decimal.NewFromString(s)
 	if err != nil {
-		log.Logger.Error(err.Error())
-		return statecode.CommonErrServerErr
+		return decimal.Zero
 	}
-	return statecode.CommonSuccess
+	return d
+}
+
+// tokenValue 代币数量 (最小单位) 的美元价值
+func tokenValue(amount decimal.Decimal, token models.PoolToken) decimal.Decimal {
+	return amount.Shift(int32(-token.Decimals)).Mul(toDecimal(token.Price)).Shift(-8)
 }
 
-func (s *poolService) PoolDataInfo(chainId int, result *[]models.PoolDataInfoRes) int {
+// countdown 距离 Unix 时间戳的秒数，已过为 0
+func countdown(timestamp string, now int64) int64 {
+	left := utils.StringToInt64(timestamp) - now
+	if left < 0 {
+		return 0
+	}
+	return left
+}
 
-	err := models.NewPoolData().PoolDataInfo(chainId, result)
+// PoolWhitelist 池子 spCoin / jpCoin 的 minter 白名单和 PledgePool 的暂停状态，由 schedule 的 SyncPrivileges 同步
+func (s *poolService) PoolWhitelist(req *request.PoolWhitelist, res *models.PoolWhitelist) error {
+	err := res.Get(req.ChainId, req.PoolId)
+	if err == redis.ErrNil {
+		return statecode.New(statecode.PoolWhitelistUnavailable)
+	}
 	if err != nil {
-		log.Logger.Error(err.Error())
-		return statecode.CommonErrServerErr
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	return nil
+}
+
+// PoolWhitelistCheck 按 depositBorrow 的条件和池子的链上权限判断地址现在能否借款，不能时列出原因，
+// 前端据此提示用户，而不是等交易在链上失败
+func (s *poolService) PoolWhitelistCheck(req *request.PoolWhitelistCheck, res *response.PoolWhitelistCheck) error {
+	detail := models.PoolDetail{}
+	err := models.NewPoolBases().PoolDetail(req.ChainId, req.PoolId, &detail)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return statecode.New(statecode.PoolNotFound)
+		}
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	whitelist := models.PoolWhitelist{}
+	if err = s.PoolWhitelist(&request.PoolWhitelist{ChainId: req.ChainId, PoolId: req.PoolId}, &whitelist); err != nil {
+		return err
+	}
+
+	res.Address = req.Address
+	res.Block = whitelist.Block
+	res.UpdatedAt = whitelist.UpdatedAt
+	res.Reasons = make([]string, 0)
+	if whitelist.Paused {
+		res.Reasons = append(res.Reasons, response.WhitelistReasonPaused)
+	}
+	if detail.State != models.PoolStateMatch {
+		res.Reasons = append(res.Reasons, response.WhitelistReasonNotMatch)
+	} else if time.Now().Unix() >= utils.StringToInt64(detail.SettleTime) {
+		res.Reasons = append(res.Reasons, response.WhitelistReasonSettled)
+	}
+	if !whitelist.JpCoin.IsMinter(whitelist.Pool) {
+		res.Reasons = append(res.Reasons, response.WhitelistReasonJpNotMinter)
+	}
+	if !whitelist.SpCoin.IsMinter(whitelist.Pool) {
+		res.Reasons = append(res.Reasons, response.WhitelistReasonSpNotMinter)
 	}
-	return statecode.CommonSuccess
+	res.Allowed = len(res.Reasons) == 0
+	return nil
 }
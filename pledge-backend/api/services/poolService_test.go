@@ -0,0 +1,179 @@
+package services
+
+import (
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"testing"
+)
+
+func TestEstimate(t *testing.T) {
+	const year = "31536000"
+	pool := func(interestRate, martgageRate, endTime, lendFee, borrowFee string) models.PoolDetail {
+		detail := models.PoolDetail{}
+		detail.InterestRate = interestRate
+		detail.MartgageRate = martgageRate
+		detail.SettleTime = "0"
+		detail.EndTime = endTime
+		detail.LendToken = "lend"
+		detail.BorrowToken = "borrow"
+		detail.LendTokenInfo.LendFee = lendFee
+		detail.BorrowTokenInfo.BorrowFee = borrowFee
+		return detail
+	}
+	usd := models.PoolToken{Price: "100000000", Decimals: 18}
+	two := models.PoolToken{Price: "200000000", Decimals: 18}
+
+	tests := []struct {
+		name        string
+		side        string
+		amount      string
+		detail      models.PoolDetail
+		lendToken   models.PoolToken
+		borrowToken models.PoolToken
+		want        response.PoolEstimate
+		wantCode    int
+	}{
+		{
+			name:   "lend 一年 5%",
+			side:   "lend",
+			amount: "1000000",
+			detail: pool("5000000", "0", year, "0", "0"),
+			want:   response.PoolEstimate{Duration: 31536000, Principal: "1000000", Interest: "50000", MaturityValue: "1050000", MaturityToken: "lend"},
+		},
+		{
+			name:   "lend 金额为 0",
+			side:   "lend",
+			amount: "0",
+			detail: pool("5000000", "0", year, "0", "0"),
+			want:   response.PoolEstimate{Duration: 31536000, Principal: "0", Interest: "0", MaturityValue: "0", MaturityToken: "lend"},
+		},
+		{
+			name:   "lend endTime 早于 settleTime 时不计息",
+			side:   "lend",
+			amount: "1000000",
+			detail: pool("5000000", "0", "-100", "0", "0"),
+			want:   response.PoolEstimate{Duration: 0, Principal: "1000000", Interest: "0", MaturityValue: "1000000", MaturityToken: "lend"},
+		},
+		{
+			// timeRatio = floor(1e8 / 31536000) = 3，interest = 3 * 5e6 * amount / 1e16
+			name:   "lend 利息在 1e8 精度边界向下取整为 1",
+			side:   "lend",
+			amount: "666666667",
+			detail: pool("5000000", "0", "1", "0", "0"),
+			want:   response.PoolEstimate{Duration: 1, Principal: "666666667", Interest: "1", MaturityValue: "666666668", MaturityToken: "lend"},
+		},
+		{
+			name:   "lend 利息在 1e8 精度边界向下取整为 0",
+			side:   "lend",
+			amount: "666666666",
+			detail: pool("5000000", "0", "1", "0", "0"),
+			want:   response.PoolEstimate{Duration: 1, Principal: "666666666", Interest: "0", MaturityValue: "666666666", MaturityToken: "lend"},
+		},
+		{
+			name:        "borrow 抵押率 200%，手续费各 1%",
+			side:        "borrow",
+			amount:      "1000000000000000000",
+			detail:      pool("5000000", "200000000", year, "1000000", "1000000"),
+			lendToken:   usd,
+			borrowToken: two,
+			want: response.PoolEstimate{
+				Duration:       31536000,
+				Principal:      "1000000000000000000",
+				Interest:       "50000000000000000",
+				LendFee:        "10500000000000000",
+				CollateralSold: "530250000000000000",
+				BorrowFee:      "4697500000000000",
+				MaturityValue:  "465052500000000000",
+				MaturityToken:  "borrow",
+			},
+		},
+		{
+			name:        "borrow 卖出的抵押品超过抵押数量时按抵押数量计",
+			side:        "borrow",
+			amount:      "1000000000000000000",
+			detail:      pool("100000000", "100000000", year, "0", "1000000"),
+			lendToken:   usd,
+			borrowToken: two,
+			want: response.PoolEstimate{
+				Duration:       31536000,
+				Principal:      "2000000000000000000",
+				Interest:       "2000000000000000000",
+				LendFee:        "0",
+				CollateralSold: "1000000000000000000",
+				BorrowFee:      "0",
+				MaturityValue:  "0",
+				MaturityToken:  "borrow",
+			},
+		},
+		{
+			// principal = floor(10 * 3 / 2 / 2) = 7，sold = ceil(7 * 2 / 3) = 5，fee = floor(5 * 10%) = 0
+			name:        "borrow 可借金额向下取整，卖出抵押品向上取整",
+			side:        "borrow",
+			amount:      "10",
+			detail:      pool("0", "200000000", "0", "0", "10000000"),
+			lendToken:   models.PoolToken{Price: "200000000"},
+			borrowToken: models.PoolToken{Price: "300000000"},
+			want: response.PoolEstimate{
+				Principal:      "7",
+				Interest:       "0",
+				LendFee:        "0",
+				CollateralSold: "5",
+				BorrowFee:      "0",
+				MaturityValue:  "5",
+				MaturityToken:  "borrow",
+			},
+		},
+		{
+			name:        "borrow 代币没有价格",
+			side:        "borrow",
+			amount:      "1000000000000000000",
+			detail:      pool("5000000", "200000000", year, "0", "0"),
+			lendToken:   models.PoolToken{Price: "0", Decimals: 18},
+			borrowToken: two,
+			wantCode:    statecode.PoolTokenPriceErr,
+		},
+		{
+			name:        "borrow 抵押率为 0",
+			side:        "borrow",
+			amount:      "1000000000000000000",
+			detail:      pool("5000000", "0", year, "0", "0"),
+			lendToken:   usd,
+			borrowToken: two,
+			wantCode:    statecode.PoolTokenPriceErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &request.PoolEstimate{Side: tt.side, Amount: tt.amount}
+			res := response.PoolEstimate{}
+			err := estimate(req, tt.detail, tt.lendToken, tt.borrowToken, &res)
+			if tt.wantCode != 0 {
+				if err == nil || statecode.FromError(err).Code != tt.wantCode {
+					t.Fatalf("estimate() err = %v, want code %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("estimate() err = %v", err)
+			}
+			want := tt.want
+			want.Side = tt.side
+			want.Amount = tt.amount
+			want.InterestRate = tt.detail.InterestRate
+			if want.LendFee == "" {
+				want.LendFee = "0"
+			}
+			if want.CollateralSold == "" {
+				want.CollateralSold = "0"
+			}
+			if want.BorrowFee == "" {
+				want.BorrowFee = "0"
+			}
+			if res != want {
+				t.Errorf("estimate() = %+v\nwant %+v", res, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/utils"
+)
+
+type PriceBackfill struct{}
+
+func NewPriceBackfill() *PriceBackfill {
+	return &PriceBackfill{}
+}
+
+func (s *PriceBackfill) List(req *request.PriceBackfillList, res *[]models.PriceBackfill) error {
+	chainId := ""
+	if req.ChainId != 0 {
+		chainId = utils.IntToString(req.ChainId)
+	}
+	*res = []models.PriceBackfill{}
+	err := models.NewPriceBackfill().List(chainId, res)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	return nil
+}
+
+// Submit 提交已登记代币的回填，由 schedule 的 BackfillPriceHistory 执行
+func (s *PriceBackfill) Submit(req *request.PriceBackfill, res *models.PriceBackfill) error {
+	chainId := utils.IntToString(req.ChainId)
+	token := models.NewTokenAdmin()
+	err := token.GetActiveByToken(chainId, req.Token)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return statecode.New(statecode.TokenNotFound)
+	} else if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	err = res.Submit(chainId, token.Token, req.FromBlock)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	return nil
+}
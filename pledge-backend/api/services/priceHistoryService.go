@@ -0,0 +1,24 @@
+package services
+
+import (
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+)
+
+type PriceHistory struct{}
+
+func NewPriceHistory() *PriceHistory {
+	return &PriceHistory{}
+}
+
+// PriceHistory 代币价格变化记录
+func (s *PriceHistory) PriceHistory(req *request.PriceHistory, res *response.PriceHistory) error {
+	res.Points = []models.TokenPriceHistory{}
+	err := models.NewTokenPriceHistory().History(req.ChainId, req.Token, req.From, req.To, req.Limit, &res.Points)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+)
+
+type PriceQuarantine struct{}
+
+func NewPriceQuarantine() *PriceQuarantine {
+	return &PriceQuarantine{}
+}
+
+func (s *PriceQuarantine) List(req *request.PriceQuarantineList, res *[]models.PriceQuarantine) error {
+	err := models.NewPriceQuarantine().List(req.Status, res)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	return nil
+}
+
+func (s *PriceQuarantine) Review(req *request.ReviewPriceQuarantine) error {
+	quarantine := models.NewPriceQuarantine()
+	err := quarantine.Get(req.Id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return statecode.New(statecode.QuarantineNotFound)
+		}
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	if req.Action == "approve" {
+		err = quarantine.Approve()
+	} else {
+		err = quarantine.Reject()
+	}
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/kucoin"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+
+	"github.com/shopspring/decimal"
+)
+
+// rateDecimals 换算比例保留的小数位数
+const rateDecimals = 18
+
+type Price struct{}
+
+func NewPrice() *Price {
+	return &Price{}
+}
+
+// Prices 批量查询代币价格，按请求中的顺序返回，未收录的代币放在 missing 中
+func (s *Price) Prices(req *request.Prices, res *response.Prices) error {
+	var list []models.TokenPrice
+	err := models.NewTokenInfo().GetTokenPrices(req.ChainId, req.Addresses, &list)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	prices := make(map[string]models.TokenPrice, len(list))
+	for _, price := range list {
+		prices[price.Token] = price
+	}
+
+	res.Prices = make([]models.TokenPrice, 0, len(req.Addresses))
+	res.Missing = []string{}
+	for _, token := range req.Addresses {
+		if price, ok := prices[token]; ok {
+			res.Prices = append(res.Prices, price)
+		} else {
+			res.Missing = append(res.Missing, token)
+		}
+	}
+	return nil
+}
+
+// Convert 按两个代币的当前价格换算金额
+//
+// result = amount * fromPrice * 10^toDecimals / (toPrice * 10^fromDecimals)，
+// 全部按整数计算后向下取整，不经过浮点数，最小单位的金额不会因为精度丢失而多给
+func (s *Price) Convert(req *request.Convert, res *response.Convert) error {
+	from, err := tokenPrice(req.ChainId, req.From)
+	if err != nil {
+		return err
+	}
+	to, err := tokenPrice(req.ChainId, req.To)
+	if err != nil {
+		return err
+	}
+
+	amount := toDecimal(req.Amount)
+	fromPrice := toDecimal(from.Price)
+	toPrice := toDecimal(to.Price)
+	numerator := amount.Mul(fromPrice).Shift(int32(to.Decimals))
+	denominator := toPrice.Shift(int32(from.Decimals))
+	result, _ := numerator.QuoRem(denominator, 0)
+
+	res.From = from
+	res.To = to
+	res.Amount = amount.String()
+	res.Result = result.String()
+	res.Rate = fromPrice.DivRound(toPrice, rateDecimals).String()
+	res.ValueUsd = amount.Shift(int32(-from.Decimals)).Mul(fromPrice).Shift(-8).String()
+	return nil
+}
+
+// Slippage 按本地盘口估算市价成交 amount 的均价和滑点
+// buy 从卖一开始逐档吃卖盘，sell 从买一开始逐档吃买盘；深度只有 50 档，不足时按能成交的部分计算并返回 complete=false
+func (s *Price) Slippage(req *request.Slippage, res *response.Slippage) error {
+	book, ok := kucoin.GetOrderBook(req.Symbol)
+	if !ok {
+		return statecode.New(statecode.OrderBookUnavailable)
+	}
+	levels := book.Bids
+	if req.Side == "buy" {
+		levels = book.Asks
+	}
+	if len(levels) == 0 {
+		return statecode.New(statecode.OrderBookUnavailable)
+	}
+
+	amount := toDecimal(req.Amount)
+	filled, cost := decimal.Zero, decimal.Zero
+	worst := levels[0].Price
+	for _, level := range levels {
+		if !filled.LessThan(amount) {
+			break
+		}
+		size := decimal.Min(level.Size, amount.Sub(filled))
+		filled = filled.Add(size)
+		cost = cost.Add(size.Mul(level.Price))
+		worst = level.Price
+	}
+
+	best := levels[0].Price
+	mid := best
+	if len(book.Bids) > 0 && len(book.Asks) > 0 {
+		mid = book.Bids[0].Price.Add(book.Asks[0].Price).Div(decimal.NewFromInt(2))
+	}
+	avg := cost.DivRound(filled, rateDecimals)
+	slippage := mid.Sub(avg)
+	if req.Side == "buy" {
+		slippage = avg.Sub(mid)
+	}
+
+	res.Symbol = req.Symbol
+	res.Side = req.Side
+	res.Amount = amount.String()
+	res.Filled = filled.String()
+	res.Complete = filled.Equal(amount)
+	res.Cost = cost.String()
+	res.BestPrice = best.String()
+	res.MidPrice = mid.String()
+	res.AvgPrice = avg.String()
+	res.WorstPrice = worst.String()
+	res.Slippage = slippage.DivRound(mid, 8).String()
+	res.UpdatedAt = book.Time
+	return nil
+}
+
+// tokenPrice 未收录的代币返回 TokenNotFound，价格为空或不为正时返回 PoolTokenPriceErr
+func tokenPrice(chainId int, token string) (models.TokenPrice, error) {
+	var list []models.TokenPrice
+	err := models.NewTokenInfo().GetTokenPrices(chainId, []string{token}, &list)
+	if err != nil {
+		return models.TokenPrice{}, statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	if len(list) == 0 {
+		return models.TokenPrice{}, statecode.New(statecode.TokenNotFound)
+	}
+	if !toDecimal(list[0].Price).IsPositive() {
+		return models.TokenPrice{}, statecode.New(statecode.PoolTokenPriceErr)
+	}
+	return list[0], nil
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/utils"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// referralCodeAlphabet 推荐码字符，去掉容易混淆的 0 O 1 I
+const referralCodeAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// referralCodeLength 推荐码长度，与 validate 中的格式一致
+const referralCodeLength = 8
+
+type Referral struct{}
+
+func NewReferral() *Referral {
+	return &Referral{}
+}
+
+// Code 钱包的推荐码，没有时生成，重复调用返回同一个推荐码
+func (s *Referral) Code(req *request.ReferralCode, res *response.ReferralCode) error {
+	address := common.HexToAddress(req.Address).Hex()
+	code := models.NewReferralCode()
+	err := code.GetByAddress(address)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	if err != nil {
+		// 推荐码或地址的唯一索引冲突时重新读取或重新生成
+		for i := 0; i < 3; i++ {
+			code = &models.ReferralCode{Address: address, Code: randomReferralCode()}
+			if err = code.Create(); err == nil {
+				break
+			}
+			if err = code.GetByAddress(address); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return statecode.Wrap(statecode.CommonErrServerErr, err)
+		}
+	}
+	*res = response.ReferralCode{Address: code.Address, Code: code.Code, CreatedAt: code.CreatedAt}
+	return nil
+}
+
+// Attribute 把存入交易归属到推荐码
+//
+// 交易已在 pool_events 中时立即校验，存入地址就是推荐人时拒绝；
+// 尚未索引时记为 pending，由 schedule 的 VerifyReferrals 在 [referral] pending_hours 内继续校验
+func (s *Referral) Attribute(req *request.ReferralAttribute, res *response.ReferralAttribution) error {
+	code := models.NewReferralCode()
+	if err := code.GetByCode(req.Code); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return statecode.New(statecode.ReferralCodeNotFound)
+		}
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	exists, err := models.NewReferralAttribution().Exists(req.ChainId, req.TxHash)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	if exists {
+		return statecode.New(statecode.ReferralTxAttributed)
+	}
+
+	attribution := &models.ReferralAttribution{
+		ChainId:  utils.IntToString(req.ChainId),
+		TxHash:   req.TxHash,
+		Code:     code.Code,
+		Referrer: code.Address,
+		Amount:   "0",
+		Status:   models.ReferralPending,
+	}
+	event := models.NewPoolEvent()
+	err = event.FirstByTx(req.ChainId, req.TxHash)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	if err == nil {
+		if event.Address == code.Address {
+			return statecode.New(statecode.ReferralSelf)
+		}
+		attribution.Status = models.ReferralVerified
+		attribution.Referee = event.Address
+		attribution.PoolId = event.PoolId
+		attribution.Event = event.Event
+		attribution.Token = event.Token
+		attribution.Amount = event.Amount
+		attribution.BlockTime = event.BlockTime
+	}
+	if err = attribution.Create(); err != nil {
+		// 并发提交同一交易时唯一索引冲突
+		if exists, _ = models.NewReferralAttribution().Exists(req.ChainId, req.TxHash); exists {
+			return statecode.New(statecode.ReferralTxAttributed)
+		}
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	*res = response.ReferralAttribution{
+		ChainId: req.ChainId,
+		TxHash:  attribution.TxHash,
+		Code:    attribution.Code,
+		Status:  attribution.Status,
+		Referee: attribution.Referee,
+		PoolId:  attribution.PoolId,
+		Event:   attribution.Event,
+		Token:   attribution.Token,
+		Amount:  attribution.Amount,
+	}
+	return nil
+}
+
+// Referral 推荐人的推荐码和时间窗口内已校验的推荐数据
+func (s *Referral) Referral(ctx context.Context, req *request.Referral, res *response.Referral) error {
+	address := common.HexToAddress(req.Address).Hex()
+	code := models.NewReferralCode()
+	if err := code.GetByAddress(address); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return statecode.New(statecode.ReferralCodeNotFound)
+		}
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	referrers, err := s.referrers(ctx, req.ChainId, address, req.Since)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	pending, err := models.NewReferralAttribution().Count(req.ChainId, address, models.ReferralPending)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	*res = response.Referral{ChainId: req.ChainId, Address: address, ValueUsd: "0.00"}
+	if len(referrers) > 0 {
+		*res = referrers[0]
+	}
+	res.Code = code.Code
+	res.Window = req.Window
+	res.Pending = pending
+	return nil
+}
+
+// Stats 时间窗口内按推荐存入价值排序的前 limit 个推荐人
+func (s *Referral) Stats(ctx context.Context, req *request.ReferralStats, res *response.ReferralStats) error {
+	referrers, err := s.referrers(ctx, req.ChainId, "", req.Since)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	if len(referrers) > req.Limit {
+		referrers = referrers[:req.Limit]
+	}
+	for i := range referrers {
+		referrers[i].Window = req.Window
+	}
+	res.ChainId = req.ChainId
+	res.Window = req.Window
+	res.Referrers = referrers
+	return s.fillCodes(res.Referrers)
+}
+
+// referrers 按推荐人合计已校验的存入，价值按 token_info 的当前价格折算，按价值降序
+func (s *Referral) referrers(ctx context.Context, chainId int, referrer string, since int64) ([]response.Referral, error) {
+	var totals []models.ReferralTotal
+	if err := models.NewReferralAttribution().VerifiedTotals(ctx, chainId, referrer, since, &totals); err != nil {
+		return nil, err
+	}
+	var tokens []models.TokenAdmin
+	if err := models.NewTokenAdmin().List(chainId, &tokens); err != nil {
+		return nil, err
+	}
+	tokenMap := make(map[string]models.TokenAdmin, len(tokens))
+	for _, token := range tokens {
+		tokenMap[strings.ToLower(token.Token)] = token
+	}
+
+	type sum struct {
+		referrals int64
+		referees  map[string]bool
+		value     decimal.Decimal
+	}
+	sums := map[string]*sum{}
+	for _, v := range totals {
+		t := sums[v.Referrer]
+		if t == nil {
+			t = &sum{referees: map[string]bool{}}
+			sums[v.Referrer] = t
+		}
+		info := tokenMap[strings.ToLower(v.Token)]
+		t.referrals += v.Referrals
+		t.referees[v.Referee] = true
+		t.value = t.value.Add(usdAmount(toDecimal(v.Amount), info.Decimals, toDecimal(info.Price)))
+	}
+
+	res := make([]response.Referral, 0, len(sums))
+	for address, t := range sums {
+		res = append(res, response.Referral{
+			ChainId:   chainId,
+			Address:   address,
+			Referrals: t.referrals,
+			Referees:  len(t.referees),
+			ValueUsd:  t.value.StringFixed(2),
+		})
+	}
+	values := make(map[string]decimal.Decimal, len(sums))
+	for address, t := range sums {
+		values[address] = t.value
+	}
+	sort.Slice(res, func(i, j int) bool {
+		a, b := values[res[i].Address], values[res[j].Address]
+		if !a.Equal(b) {
+			return a.GreaterThan(b)
+		}
+		return res[i].Address < res[j].Address
+	})
+	return res, nil
+}
+
+// fillCodes 填写推荐人的推荐码
+func (s *Referral) fillCodes(referrers []response.Referral) error {
+	for i := range referrers {
+		code := models.NewReferralCode()
+		if err := code.GetByAddress(referrers[i].Address); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return statecode.Wrap(statecode.CommonErrServerErr, err)
+		}
+		referrers[i].Code = code.Code
+	}
+	return nil
+}
+
+// randomReferralCode 随机生成推荐码
+func randomReferralCode() string {
+	max := big.NewInt(int64(len(referralCodeAlphabet)))
+	code := make([]byte, referralCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			n = big.NewInt(int64(i))
+		}
+		code[i] = referralCodeAlphabet[n.Int64()]
+	}
+	return string(code)
+}
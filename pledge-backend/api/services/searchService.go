@@ -1,11 +1,13 @@
 package services
 
 import (
-	"fmt"
+	"context"
 	"pledge-backend/api/common/statecode"
 	"pledge-backend/api/models"
 	"pledge-backend/api/models/request"
-	"pledge-backend/log"
+	"pledge-backend/api/models/response"
+	"pledge-backend/utils"
+	"strings"
 )
 
 type SearchService struct{}
@@ -14,19 +16,94 @@ func NewSearch() *SearchService {
 	return &SearchService{}
 }
 
-func (c *SearchService) Search(req *request.Search) (int, int64, []models.Pool) {
+// Search ctx 为请求的 ctx，[env] request_timeout 到期后取消查询
+func (c *SearchService) Search(ctx context.Context, req *request.Search) (int64, []models.Pool, error) {
 
-	whereCondition := fmt.Sprintf(`chain_id='%v'`, req.ChainID)
+	query, args := c.condition(req)
+	err, total, data := models.NewPool().Pagination(ctx, req, query, args...)
+	if err != nil {
+		return 0, nil, statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	return total, data, nil
+}
+
+// PublicSearch 公开搜索，只返回 response.PublicPool 中的字段
+func (c *SearchService) PublicSearch(ctx context.Context, req *request.Search) (int64, []response.PublicPool, error) {
+	total, data, err := c.Search(ctx, req)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	pools := make([]response.PublicPool, 0, len(data))
+	for _, p := range data {
+		pools = append(pools, response.PublicPool{
+			PoolID:            p.PoolID,
+			State:             p.State,
+			SettleTime:        p.SettleTime,
+			EndTime:           p.EndTime,
+			InterestRate:      p.InterestRate,
+			MaxSupply:         p.MaxSupply,
+			LendSupply:        p.LendSupply,
+			BorrowSupply:      p.BorrowSupply,
+			MartgageRate:      p.MartgageRate,
+			LendToken:         p.LendToken,
+			LendTokenSymbol:   p.LendTokenSymbol,
+			BorrowToken:       p.BorrowToken,
+			BorrowTokenSymbol: p.BorrowTokenSymbol,
+			Archived:          p.Archived,
+		})
+	}
+	return total, pools, nil
+}
+
+// condition 构造查询条件，参数化传入避免 SQL 注入
+// 关键字通过 search_term 索引做前缀匹配，找出 symbol/name/地址匹配的代币，再按借出或抵押代币筛选池子
+func (c *SearchService) condition(req *request.Search) (string, []interface{}) {
+	query := "chain_id=? and " + models.ArchivedCondition(req.Archived)
+	args := []interface{}{req.ChainID}
 	if req.LendTokenSymbol != "" {
-		whereCondition += fmt.Sprintf(` and lend_token_symbol='%v'`, req.LendTokenSymbol)
+		query += " and lend_token_symbol=?"
+		args = append(args, req.LendTokenSymbol)
 	}
 	if req.State != "" {
-		whereCondition += fmt.Sprintf(` and state='%v'`, req.State)
+		query += " and state=?"
+		args = append(args, req.State)
+	}
+	if len(req.States) > 0 {
+		query += " and state in ?"
+		args = append(args, req.States)
+	}
+	if req.Keyword != "" {
+		term := "select token from search_term where chain_id=? and term like ?"
+		query += " and (lend_token in (" + term + ") or borrow_token in (" + term + "))"
+		pattern := models.EscapeLike(strings.ToLower(req.Keyword)) + "%"
+		chainId := utils.IntToString(req.ChainID)
+		args = append(args, chainId, pattern, chainId, pattern)
+	}
+	if req.InterestRateMin != "" {
+		query += " and cast(interest_rate as decimal(65,0)) >= ?"
+		args = append(args, req.InterestRateMin)
+	}
+	if req.InterestRateMax != "" {
+		query += " and cast(interest_rate as decimal(65,0)) <= ?"
+		args = append(args, req.InterestRateMax)
 	}
-	err, total, data := models.NewPool().Pagination(req, whereCondition)
+	if req.EndTimeFrom > 0 {
+		query += " and cast(end_time as unsigned) >= ?"
+		args = append(args, req.EndTimeFrom)
+	}
+	if req.EndTimeTo > 0 {
+		query += " and cast(end_time as unsigned) <= ?"
+		args = append(args, req.EndTimeTo)
+	}
+	return query, args
+}
+
+// TokenSearch 按 symbol、name、地址模糊搜索代币
+func (c *SearchService) TokenSearch(ctx context.Context, req *request.TokenSearch, res *[]models.TokenList) error {
+	err := models.NewTokenInfo().Search(ctx, req.ChainId, strings.ToLower(req.Keyword), res)
 	if err != nil {
-		log.Logger.Error(err.Error())
-		return statecode.CommonErrServerErr, 0, nil
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
 	}
-	return 0, total, data
+	return nil
 }
@@ -0,0 +1,477 @@
+package services
+
+import (
+	"context"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	scheduleModels "pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+)
+
+// tvlCacheTtl TVL 序列的缓存时间, s
+const tvlCacheTtl = 300
+
+// leaderboardCacheTtl 排行榜的缓存时间, s
+const leaderboardCacheTtl = 300
+
+type Stats struct{}
+
+func NewStats() *Stats {
+	return &Stats{}
+}
+
+// Tvl 各链按 interval 分段的 TVL、利用率和各代币锁定价值，计算结果缓存 tvlCacheTtl 秒
+func (s *Stats) Tvl(ctx context.Context, req *request.Tvl, res *response.Tvl) error {
+	res.Interval = req.Interval
+	res.Range = req.Range
+	res.Chains = make([]models.ChainTvl, 0)
+
+	now := time.Now().Unix()
+	current := now - now%req.IntervalSeconds
+	count := (req.RangeSeconds + req.IntervalSeconds - 1) / req.IntervalSeconds
+	starts := make([]int64, 0, count)
+	for i := count - 1; i >= 0; i-- {
+		starts = append(starts, current-i*req.IntervalSeconds)
+	}
+
+	for _, chainId := range []string{config.Config().TestNet.ChainId, config.Config().MainNet.ChainId} {
+		if len(req.ChainIds) > 0 && !containsChainId(req.ChainIds, utils.StringToInt(chainId)) {
+			continue
+		}
+		chain := models.NewChainTvl()
+		if !chain.GetCache(chainId, req.Interval, req.Range) {
+			err := s.chainTvl(ctx, utils.StringToInt(chainId), starts, req.IntervalSeconds, now, chain)
+			if err != nil {
+				return statecode.Wrap(statecode.CommonErrServerErr, err)
+			}
+			if err = chain.SetCache(req.Interval, req.Range, tvlCacheTtl); err != nil {
+				log.Logger.Sugar().Warn("tvl cache err ", err)
+			}
+		}
+		res.Chains = append(res.Chains, *chain)
+	}
+	return nil
+}
+
+func containsChainId(chainIds []int, chainId int) bool {
+	for _, v := range chainIds {
+		if v == chainId {
+			return true
+		}
+	}
+	return false
+}
+
+// chainTvl 从第一个时间段开始前的快照和价格出发，按时间顺序回放之后的快照和价格变化，
+// 在每个时间段结束时 (最后一段为当前时间) 统计匹配和执行中池子的出借、抵押价值
+//
+// 价格取当时最近一次的 token_price_history，没有记录时使用 token_info 的当前价格
+func (s *Stats) chainTvl(ctx context.Context, chainId int, starts []int64, interval, now int64, res *models.ChainTvl) error {
+	first := starts[0]
+
+	var pools []models.PoolBases
+	if err := models.NewPoolBases().List(chainId, "", models.ArchivedInclude, -1, &pools); err != nil {
+		return err
+	}
+	var tokens []models.TokenAdmin
+	if err := models.NewTokenAdmin().List(chainId, &tokens); err != nil {
+		return err
+	}
+	var snapshots, changes []models.PoolSnapshot
+	if err := models.NewPoolSnapshot().LatestBefore(ctx, chainId, first, &snapshots); err != nil {
+		return err
+	}
+	if err := models.NewPoolSnapshot().Between(ctx, chainId, first, now+1, &changes); err != nil {
+		return err
+	}
+	var history, priceChanges []models.TokenPriceHistory
+	if err := models.NewTokenPriceHistory().LatestBefore(ctx, chainId, first, &history); err != nil {
+		return err
+	}
+	if err := models.NewTokenPriceHistory().Between(ctx, chainId, first, now+1, &priceChanges); err != nil {
+		return err
+	}
+
+	poolMap := make(map[int]models.PoolBases, len(pools))
+	for _, pool := range pools {
+		poolMap[pool.PoolID] = pool
+	}
+	tokenMap := make(map[string]models.TokenAdmin, len(tokens))
+	prices := make(map[string]decimal.Decimal, len(tokens))
+	for _, token := range tokens {
+		tokenMap[strings.ToLower(token.Token)] = token
+		prices[strings.ToLower(token.Token)] = toDecimal(token.Price)
+	}
+	for _, v := range history {
+		prices[strings.ToLower(v.Token)] = toDecimal(v.Price)
+	}
+	state := make(map[int]models.PoolSnapshot, len(snapshots))
+	for _, v := range snapshots {
+		state[v.PoolId] = v
+	}
+
+	// 出现在池子中的代币，按 symbol 排序输出
+	tokenKeys := make([]string, 0)
+	seen := map[string]bool{}
+	for _, pool := range pools {
+		for _, token := range []string{pool.LendToken, pool.BorrowToken} {
+			key := strings.ToLower(token)
+			if !seen[key] {
+				seen[key] = true
+				tokenKeys = append(tokenKeys, key)
+			}
+		}
+	}
+	sort.Slice(tokenKeys, func(i, j int) bool {
+		return tokenMap[tokenKeys[i]].Symbol+tokenKeys[i] < tokenMap[tokenKeys[j]].Symbol+tokenKeys[j]
+	})
+	tokenSeries := make(map[string]*models.TokenTvl, len(tokenKeys))
+	res.ChainId = utils.IntToString(chainId)
+	res.Points = make([]models.TvlPoint, 0, len(starts))
+	res.Tokens = make([]models.TokenTvl, len(tokenKeys))
+	for i, key := range tokenKeys {
+		token := tokenMap[key]
+		res.Tokens[i] = models.TokenTvl{Token: token.Token, Symbol: token.Symbol, Points: make([]models.TokenTvlPoint, 0, len(starts))}
+		if token.Token == "" {
+			res.Tokens[i].Token = key
+		}
+		tokenSeries[key] = &res.Tokens[i]
+	}
+
+	next, nextPrice := 0, 0
+	for _, start := range starts {
+		end := start + interval
+		if end > now {
+			end = now + 1
+		}
+		for ; next < len(changes) && changes[next].SnapshotAt < end; next++ {
+			state[changes[next].PoolId] = changes[next]
+		}
+		for ; nextPrice < len(priceChanges) && priceChanges[nextPrice].PriceAt < end; nextPrice++ {
+			prices[strings.ToLower(priceChanges[nextPrice].Token)] = toDecimal(priceChanges[nextPrice].Price)
+		}
+
+		lend, borrow, maxSupply := decimal.Zero, decimal.Zero, decimal.Zero
+		amounts := make(map[string]decimal.Decimal, len(tokenKeys))
+		for poolId, snapshot := range state {
+			pool, ok := poolMap[poolId]
+			if !ok || (snapshot.State != models.PoolStateMatch && snapshot.State != models.PoolStateExecution) {
+				continue
+			}
+			lendKey, borrowKey := strings.ToLower(pool.LendToken), strings.ToLower(pool.BorrowToken)
+			lendAmount, borrowAmount := toDecimal(snapshot.LendSupply), toDecimal(snapshot.BorrowSupply)
+			amounts[lendKey] = amounts[lendKey].Add(lendAmount)
+			amounts[borrowKey] = amounts[borrowKey].Add(borrowAmount)
+			lend = lend.Add(usdAmount(lendAmount, tokenMap[lendKey].Decimals, prices[lendKey]))
+			borrow = borrow.Add(usdAmount(borrowAmount, tokenMap[borrowKey].Decimals, prices[borrowKey]))
+			maxSupply = maxSupply.Add(usdAmount(toDecimal(pool.MaxSupply), tokenMap[lendKey].Decimals, prices[lendKey]))
+		}
+
+		utilization := decimal.Zero
+		if maxSupply.IsPositive() {
+			utilization = lend.Div(maxSupply)
+		}
+		res.Points = append(res.Points, models.TvlPoint{
+			Timestamp:   start,
+			Tvl:         lend.Add(borrow).StringFixed(2),
+			Lend:        lend.StringFixed(2),
+			Borrow:      borrow.StringFixed(2),
+			Utilization: utilization.StringFixed(4),
+		})
+		for _, key := range tokenKeys {
+			amount := amounts[key]
+			series := tokenSeries[key]
+			series.Points = append(series.Points, models.TokenTvlPoint{
+				Timestamp: start,
+				Amount:    amount.String(),
+				Tvl:       usdAmount(amount, tokenMap[key].Decimals, prices[key]).StringFixed(2),
+			})
+		}
+	}
+	return nil
+}
+
+// feePeriodLayouts 手续费按月 / 按天分组时时间段的格式
+var feePeriodLayouts = map[string]string{"month": "2006-01", "day": "2006-01-02"}
+
+// Fees 按月或按天合计 fee_revenues 中记入的手续费收入，时间段按 UTC 划分
+// chainId 为列表或 all 时，chains 中同时返回每条链的合计
+func (s *Stats) Fees(ctx context.Context, req *request.Fees, res *response.Fees) error {
+	var revenues []models.FeeRevenue
+	if err := models.NewFeeRevenue().Between(ctx, req.ChainIds, req.FromTs, req.ToTs, &revenues); err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	layout := feePeriodLayouts[req.Group]
+	res.Group = req.Group
+	res.From = req.From
+	res.To = req.To
+	res.Pools = make([]response.FeePool, 0, len(revenues))
+
+	periods := make([]string, 0)
+	for t := time.Unix(req.FromTs, 0).UTC(); t.Unix() < req.ToTs; {
+		period := t.Format(layout)
+		if len(periods) == 0 || periods[len(periods)-1] != period {
+			periods = append(periods, period)
+		}
+		if req.Group == "day" {
+			t = t.AddDate(0, 0, 1)
+		} else {
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+		}
+	}
+
+	all := newFeeSums(periods)
+	chains := make(map[int]*feeSums, len(req.ChainIds))
+	if req.Multi {
+		for _, chainId := range req.ChainIds {
+			chains[chainId] = newFeeSums(periods)
+		}
+	}
+	for _, v := range revenues {
+		chainId := utils.StringToInt(v.ChainId)
+		period := time.Unix(v.AccruedAt, 0).UTC().Format(layout)
+		lend, borrow := toDecimal(v.LendFeeUsd).Shift(-8), toDecimal(v.BorrowFeeUsd).Shift(-8)
+		all.add(period, lend, borrow)
+		if sums := chains[chainId]; sums != nil {
+			sums.add(period, lend, borrow)
+		}
+		res.Pools = append(res.Pools, response.FeePool{
+			ChainId:         chainId,
+			PoolId:          v.PoolId,
+			Event:           v.Event,
+			Period:          period,
+			LendToken:       v.LendToken,
+			BorrowToken:     v.BorrowToken,
+			LendFeeAmount:   v.LendFeeAmount,
+			BorrowFeeAmount: v.BorrowFeeAmount,
+			LendFeeUsd:      lend.StringFixed(2),
+			BorrowFeeUsd:    borrow.StringFixed(2),
+			TotalUsd:        lend.Add(borrow).StringFixed(2),
+			AccruedAt:       v.AccruedAt,
+		})
+	}
+
+	res.Total, res.Periods = all.result(periods)
+	if req.Multi {
+		res.Chains = make([]response.FeeChain, 0, len(req.ChainIds))
+		for _, chainId := range req.ChainIds {
+			chain := response.FeeChain{ChainId: chainId}
+			chain.Total, chain.Periods = chains[chainId].result(periods)
+			res.Chains = append(res.Chains, chain)
+		}
+	}
+	return nil
+}
+
+type feeSum struct {
+	lend, borrow decimal.Decimal
+	pools        int
+}
+
+// feeSums 合计和每个时间段的手续费收入
+type feeSums struct {
+	total   feeSum
+	periods map[string]*feeSum
+}
+
+func newFeeSums(periods []string) *feeSums {
+	sums := &feeSums{periods: make(map[string]*feeSum, len(periods))}
+	for _, period := range periods {
+		sums.periods[period] = &feeSum{}
+	}
+	return sums
+}
+
+func (f *feeSums) add(period string, lend, borrow decimal.Decimal) {
+	if sum := f.periods[period]; sum != nil {
+		sum.lend, sum.borrow, sum.pools = sum.lend.Add(lend), sum.borrow.Add(borrow), sum.pools+1
+	}
+	f.total.lend, f.total.borrow, f.total.pools = f.total.lend.Add(lend), f.total.borrow.Add(borrow), f.total.pools+1
+}
+
+// result 合计和按 periods 顺序的每个时间段，没有收入的时间段也返回
+func (f *feeSums) result(periods []string) (response.FeePeriod, []response.FeePeriod) {
+	feePeriod := func(period string, sum feeSum) response.FeePeriod {
+		return response.FeePeriod{
+			Period:       period,
+			LendFeeUsd:   sum.lend.StringFixed(2),
+			BorrowFeeUsd: sum.borrow.StringFixed(2),
+			TotalUsd:     sum.lend.Add(sum.borrow).StringFixed(2),
+			Pools:        sum.pools,
+		}
+	}
+	list := make([]response.FeePeriod, 0, len(periods))
+	for _, period := range periods {
+		list = append(list, feePeriod(period, *f.periods[period]))
+	}
+	return feePeriod("", f.total), list
+}
+
+// Leaderboard 按 pool_events 中的存入统计地址排行，计算结果缓存 leaderboardCacheTtl 秒
+//
+// 单个池子按存入数量排序，并返回地址当前持有的 SP / JP 数量 (池子结算后才有)；
+// 全协议按各池子存入的美元价值合计排序，价格为 token_info 的当前价格
+func (s *Stats) Leaderboard(ctx context.Context, req *request.Leaderboard, res *response.Leaderboard) error {
+	res.ChainId = req.ChainId
+	res.PoolId = req.PoolId
+	res.Side = req.Side
+	res.Window = req.Window
+
+	board := models.NewLeaderboard()
+	if !board.GetCache(req.ChainId, req.PoolId, req.Side, req.Window, req.Limit) {
+		if err := s.leaderboard(ctx, req, board); err != nil {
+			return err
+		}
+		if err := board.SetCache(req.ChainId, req.PoolId, req.Side, req.Window, req.Limit, leaderboardCacheTtl); err != nil {
+			log.Logger.Sugar().Warn("leaderboard cache err ", err)
+		}
+	}
+	res.Entries = board.Entries
+	res.UpdatedAt = board.UpdatedAt
+	return nil
+}
+
+func (s *Stats) leaderboard(ctx context.Context, req *request.Leaderboard, res *models.Leaderboard) error {
+	var pools []models.PoolBases
+	if err := models.NewPoolBases().List(req.ChainId, "", models.ArchivedInclude, -1, &pools); err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	poolMap := make(map[int]models.PoolBases, len(pools))
+	for _, pool := range pools {
+		poolMap[pool.PoolID] = pool
+	}
+	if _, ok := poolMap[req.PoolId]; req.PoolId != 0 && !ok {
+		return statecode.New(statecode.PoolNotFound)
+	}
+	var tokens []models.TokenAdmin
+	if err := models.NewTokenAdmin().List(req.ChainId, &tokens); err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	tokenMap := make(map[string]models.TokenAdmin, len(tokens))
+	for _, token := range tokens {
+		tokenMap[strings.ToLower(token.Token)] = token
+	}
+
+	event := models.PoolEventDepositLend
+	if req.Side == "borrow" {
+		event = models.PoolEventDepositBorrow
+	}
+	var deposits []models.PoolDeposit
+	if err := models.NewPoolEvent().DepositsByAddress(ctx, req.ChainId, req.PoolId, event, req.Since, &deposits); err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	type total struct {
+		amount, value decimal.Decimal
+		deposits      int64
+		pools         int
+	}
+	totals := map[string]*total{}
+	for _, v := range deposits {
+		token := poolMap[v.PoolId].LendToken
+		if event == models.PoolEventDepositBorrow {
+			token = poolMap[v.PoolId].BorrowToken
+		}
+		info := tokenMap[strings.ToLower(token)]
+		amount := toDecimal(v.Amount)
+		t := totals[v.Address]
+		if t == nil {
+			t = &total{}
+			totals[v.Address] = t
+		}
+		t.amount = t.amount.Add(amount)
+		t.value = t.value.Add(usdAmount(amount, info.Decimals, toDecimal(info.Price)))
+		t.deposits += v.Deposits
+		t.pools++
+	}
+
+	addresses := make([]string, 0, len(totals))
+	for address := range totals {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		a, b := totals[addresses[i]], totals[addresses[j]]
+		if req.PoolId != 0 && !a.amount.Equal(b.amount) {
+			return a.amount.GreaterThan(b.amount)
+		}
+		if !a.value.Equal(b.value) {
+			return a.value.GreaterThan(b.value)
+		}
+		return addresses[i] < addresses[j]
+	})
+	if len(addresses) > req.Limit {
+		addresses = addresses[:req.Limit]
+	}
+
+	res.UpdatedAt = time.Now().Unix()
+	res.Entries = make([]models.LeaderboardEntry, 0, len(addresses))
+	for i, address := range addresses {
+		t := totals[address]
+		entry := models.LeaderboardEntry{
+			Rank:     i + 1,
+			Address:  address,
+			ValueUsd: t.value.StringFixed(2),
+			Deposits: t.deposits,
+			Pools:    t.pools,
+		}
+		if req.PoolId != 0 {
+			entry.Amount = t.amount.String()
+		}
+		res.Entries = append(res.Entries, entry)
+	}
+	if req.PoolId != 0 && poolMap[req.PoolId].State != models.PoolStateMatch {
+		s.leaderboardHoldings(req, poolMap[req.PoolId], res.Entries)
+	}
+	return nil
+}
+
+// leaderboardHoldings 读取地址当前持有的 SP (出借) / JP (抵押) 数量，读取失败时不返回 holding
+func (s *Stats) leaderboardHoldings(req *request.Leaderboard, pool models.PoolBases, entries []models.LeaderboardEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	coin := pool.SpCoin
+	if req.Side == "borrow" {
+		coin = pool.JpCoin
+	}
+	netUrl := scheduleModels.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId)
+	if utils.IntToString(req.ChainId) == config.Config().TestNet.ChainId {
+		netUrl = scheduleModels.NewChainHealth().RpcUrl(config.Config().TestNet.ChainId)
+	}
+	ethereumConn, err := ethclient.Dial(netUrl)
+	if err != nil {
+		log.Logger.Sugar().Warn("leaderboard holdings err ", err)
+		return
+	}
+	defer ethereumConn.Close()
+
+	holdings := make([]string, len(entries))
+	for i := range entries {
+		balance, err := NewClaimable().BalanceOf(ethereumConn, coin, common.HexToAddress(entries[i].Address))
+		if err != nil {
+			log.Logger.Sugar().Warn("leaderboard holdings err ", err)
+			return
+		}
+		holdings[i] = balance.String()
+	}
+	for i := range entries {
+		entries[i].Holding = holdings[i]
+	}
+}
+
+// usdAmount 代币数量 (最小单位) 按 1e8 精度价格折算的美元价值
+func usdAmount(amount decimal.Decimal, decimals int, price decimal.Decimal) decimal.Decimal {
+	return amount.Shift(int32(-decimals)).Mul(price).Shift(-8)
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"pledge-backend/api/models"
+	"pledge-backend/log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Synced struct{}
+
+func NewSynced() *Synced {
+	return &Synced{}
+}
+
+// LastModified 链 chainIds 在 tables 中最近一次同步或修改的时间，作为接口的 Last-Modified
+// 查询失败时记录日志并返回零值，响应不带 Last-Modified，只用 ETag 验证；并发的相同查询合并为一次，见 coalesce
+func (s *Synced) LastModified(ctx context.Context, chainIds []int, tables ...string) time.Time {
+	ids := make([]string, 0, len(chainIds))
+	for _, chainId := range chainIds {
+		ids = append(ids, strconv.Itoa(chainId))
+	}
+	key := "syncedAt:" + strings.Join(tables, ",") + ":" + strings.Join(ids, ",")
+	latest, err := coalesce(ctx, key, func(ctx context.Context) (interface{}, error) {
+		var latest time.Time
+		for _, table := range tables {
+			syncedAt, err := models.SyncedAt(ctx, table, chainIds)
+			if err != nil {
+				return nil, err
+			}
+			if syncedAt.After(latest) {
+				latest = syncedAt
+			}
+		}
+		return latest, nil
+	})
+	if err != nil {
+		log.Logger.Sugar().Warn("LastModified ", tables, " err ", err)
+		return time.Time{}
+	}
+	return latest.(time.Time)
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"gorm.io/gorm"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	scheduleModels "pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"time"
+)
+
+type TokenAdmin struct{}
+
+func NewTokenAdmin() *TokenAdmin {
+	return &TokenAdmin{}
+}
+
+func (s *TokenAdmin) List(req *request.TokenList, res *[]models.TokenAdmin) error {
+	err := models.NewTokenAdmin().List(req.ChainId, res)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	return nil
+}
+
+// Create 校验链上合约存在后新增代币，symbol/decimals 为空时由 schedule 元信息同步任务补全
+func (s *TokenAdmin) Create(req *request.CreateToken, res *models.TokenAdmin) error {
+	chainId := utils.IntToString(req.ChainId)
+	token := common.HexToAddress(req.Token).Hex()
+
+	old := models.NewTokenAdmin()
+	err := old.GetByToken(chainId, token)
+	if err == nil && old.DeletedAt == nil {
+		return statecode.New(statecode.TokenExists)
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	err = s.CheckContract(chainId, token)
+	if err != nil {
+		return err
+	}
+
+	*res = models.TokenAdmin{
+		Token:       token,
+		ChainId:     chainId,
+		Symbol:      req.Symbol,
+		Decimals:    req.Decimals,
+		Logo:        req.Logo,
+		PriceSource: req.PriceSource,
+		CoingeckoId: req.CoingeckoId,
+	}
+	err = res.Create()
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	// 代币已写入，版本生成失败只记录日志，下次写入时补上
+	if err = NewTokenList().Refresh(req.ChainId); err != nil {
+		log.Logger.Error(err.Error())
+	}
+	return nil
+}
+
+func (s *TokenAdmin) Update(req *request.UpdateToken) error {
+	token := models.NewTokenAdmin()
+	err := token.Get(req.Id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return statecode.New(statecode.TokenNotFound)
+		}
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	fields := map[string]interface{}{}
+	if req.Symbol != nil {
+		fields["symbol"] = *req.Symbol
+	}
+	if req.Decimals != nil {
+		fields["decimals"] = *req.Decimals
+	}
+	if req.Logo != nil {
+		fields["logo"] = *req.Logo
+	}
+	if req.CoingeckoId != nil {
+		fields["coingecko_id"] = *req.CoingeckoId
+		token.CoingeckoId = *req.CoingeckoId
+	}
+	if req.PriceSource != nil {
+		fields["price_source"] = *req.PriceSource
+		token.PriceSource = *req.PriceSource
+	}
+	if token.PriceSource == models.PriceSourceCoingecko && token.CoingeckoId == "" {
+		return statecode.New(statecode.TokenPriceSourceErr)
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	err = token.Update(fields)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	if err = NewTokenList().Refresh(utils.StringToInt(token.ChainId)); err != nil {
+		log.Logger.Error(err.Error())
+	}
+	return nil
+}
+
+func (s *TokenAdmin) Delete(req *request.DeleteToken) error {
+	token := models.NewTokenAdmin()
+	err := token.Get(req.Id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return statecode.New(statecode.TokenNotFound)
+		}
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	err = token.Delete()
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	if err = NewTokenList().Refresh(utils.StringToInt(token.ChainId)); err != nil {
+		log.Logger.Error(err.Error())
+	}
+	return nil
+}
+
+// CheckContract 检查代币合约已部署，零地址表示链原生币（BNB）
+func (s *TokenAdmin) CheckContract(chainId, token string) error {
+	address := common.HexToAddress(token)
+	if address == (common.Address{}) {
+		return nil
+	}
+
+	netUrl := scheduleModels.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId)
+	if chainId == config.Config().TestNet.ChainId {
+		netUrl = scheduleModels.NewChainHealth().RpcUrl(config.Config().TestNet.ChainId)
+	}
+	ethereumConn, err := ethclient.Dial(netUrl)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	defer ethereumConn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	code, err := ethereumConn.CodeAt(ctx, address, nil)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	if len(code) == 0 {
+		return statecode.New(statecode.TokenContractNotFound)
+	}
+	return nil
+}
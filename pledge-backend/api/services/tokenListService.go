@@ -1,9 +1,11 @@
 package services
 
 import (
+	"context"
 	"pledge-backend/api/common/statecode"
 	"pledge-backend/api/models"
 	"pledge-backend/api/models/request"
+	"strconv"
 )
 
 type TokenList struct{}
@@ -12,20 +14,33 @@ func NewTokenList() *TokenList {
 	return &TokenList{}
 }
 
-func (c *TokenList) DebtTokenList(req *request.TokenList) (int, []models.TokenInfo) {
+func (c *TokenList) DebtTokenList(req *request.TokenList) ([]models.TokenInfo, error) {
 	err, res := models.NewTokenInfo().GetTokenInfo(req)
 	if err != nil {
-		return statecode.CommonErrServerErr, nil
+		return nil, statecode.Wrap(statecode.CommonErrServerErr, err)
 	}
-	return statecode.CommonSuccess, res
+	return res, nil
 
 }
 
-func (c *TokenList) GetTokenList(req *request.TokenList) (int, []models.TokenList) {
-	err, tokenList := models.NewTokenInfo().GetTokenList(req)
+// GetTokenList 链上的代币，并发的相同查询合并为一次，见 coalesce
+func (c *TokenList) GetTokenList(req *request.TokenList) ([]models.TokenList, error) {
+	rows, err := coalesce(context.Background(), "token:"+strconv.Itoa(req.ChainId), func(ctx context.Context) (interface{}, error) {
+		err, tokenList := models.NewTokenInfo().GetTokenList(req)
+		return tokenList, err
+	})
 	if err != nil {
-		return statecode.CommonErrServerErr, nil
+		return nil, statecode.Wrap(statecode.CommonErrServerErr, err)
 	}
-	return statecode.CommonSuccess, tokenList
+	return append([]models.TokenList{}, rows.([]models.TokenList)...), nil
+
+}
+
+func (c *TokenList) GetTokenPriceSources(req *request.TokenList) ([]models.TokenPriceSource, error) {
+	err, sources := models.NewTokenInfo().GetTokenPriceSources(req)
+	if err != nil {
+		return nil, statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	return sources, nil
 
 }
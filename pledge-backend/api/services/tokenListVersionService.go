@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"gorm.io/gorm"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/config"
+	"pledge-backend/utils"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// 首个版本沿用之前写死的 2.16.12，避免客户端看到版本号回退
+var initialTokenListVersion = response.Version{Major: 2, Minor: 16, Patch: 12}
+
+// Tokens 链上代币的 Token List 条目，logo 使用 /assets 下带内容哈希的地址
+func (c *TokenList) Tokens(chainId int) ([]response.Token, error) {
+	data, err := c.GetTokenList(&request.TokenList{ChainId: chainId})
+	if err != nil {
+		return nil, err
+	}
+	return listTokens(data), nil
+}
+
+// Refresh 代币写入后生成新版本，代币列表与最新版本相同时不写入
+// 只在写路径调用 (代币管理接口、schedule 的代币同步任务)，GET /token 只读取已有版本
+func (c *TokenList) Refresh(chainId int) error {
+	// 不经过 GetTokenList 的 coalesce，避免拿到写入之前开始的查询结果
+	err, data := models.NewTokenInfo().GetTokenList(&request.TokenList{ChainId: chainId})
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	version := models.TokenListVersion{}
+	return c.Version(chainId, listTokens(data), &version)
+}
+
+// Current 最新版本，还没有版本记录时返回初始版本，时间为代币最后一次写入的时间，不写入版本
+func (c *TokenList) Current(chainId int, res *models.TokenListVersion) error {
+	err := res.Latest(chainId)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	*res = models.TokenListVersion{
+		ChainId: chainId,
+		Major:   initialTokenListVersion.Major,
+		Minor:   initialTokenListVersion.Minor,
+		Patch:   initialTokenListVersion.Patch,
+	}
+	if synced := NewSynced().LastModified(context.Background(), []int{chainId}, "token_info"); !synced.IsZero() {
+		res.CreatedAt = synced.In(time.Local).Format("2006-01-02 15:04:05")
+	}
+	return nil
+}
+
+// Version 对比最新版本的代币快照，代币列表变化时生成新版本
+func (c *TokenList) Version(chainId int, tokens []response.Token, res *models.TokenListVersion) error {
+	snapshot := map[string]response.Token{}
+	for _, t := range tokens {
+		snapshot[strings.ToLower(t.Address)] = t
+	}
+	snapshotBytes, err := json.Marshal(sortTokens(tokens))
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	sum := sha256.Sum256(snapshotBytes)
+	hash := hex.EncodeToString(sum[:])
+
+	latest := models.NewTokenListVersion()
+	err = latest.Latest(chainId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	if err == nil && latest.Hash == hash {
+		*res = *latest
+		return nil
+	}
+
+	next := models.TokenListVersion{
+		ChainId: chainId,
+		Major:   initialTokenListVersion.Major,
+		Minor:   initialTokenListVersion.Minor,
+		Patch:   initialTokenListVersion.Patch,
+		Hash:    hash,
+		Tokens:  string(snapshotBytes),
+		Added:   "[]",
+		Removed: "[]",
+		Changed: "[]",
+	}
+	if err == nil {
+		var previous []response.Token
+		_ = json.Unmarshal([]byte(latest.Tokens), &previous)
+		added, removed, changed := diffTokens(previous, snapshot)
+		next.Major, next.Minor, next.Patch = latest.Major, latest.Minor, latest.Patch
+		if len(removed) > 0 {
+			next.Major, next.Minor, next.Patch = next.Major+1, 0, 0
+		} else if len(added) > 0 {
+			next.Minor, next.Patch = next.Minor+1, 0
+		} else {
+			next.Patch++
+		}
+		addedBytes, _ := json.Marshal(added)
+		removedBytes, _ := json.Marshal(removed)
+		changedBytes, _ := json.Marshal(changed)
+		next.Added, next.Removed, next.Changed = string(addedBytes), string(removedBytes), string(changedBytes)
+	}
+
+	err = next.Create()
+	if err != nil {
+		// 并发请求已写入同一版本号时直接使用已写入的版本
+		if latestErr := latest.Latest(chainId); latestErr == nil && latest.Hash == hash {
+			*res = *latest
+			return nil
+		}
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	*res = next
+	return nil
+}
+
+// Changelog 查询版本历史
+func (c *TokenList) Changelog(req *request.TokenListChangelog, res *[]response.TokenListChange) error {
+	var versions []models.TokenListVersion
+	err := models.NewTokenListVersion().List(req.ChainId, req.Limit, &versions)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	for _, v := range versions {
+		change := response.TokenListChange{
+			Version:   utils.IntToString(v.Major) + "." + utils.IntToString(v.Minor) + "." + utils.IntToString(v.Patch),
+			Added:     []string{},
+			Removed:   []string{},
+			Changed:   []string{},
+			Timestamp: v.CreatedAt,
+		}
+		_ = json.Unmarshal([]byte(v.Added), &change.Added)
+		_ = json.Unmarshal([]byte(v.Removed), &change.Removed)
+		_ = json.Unmarshal([]byte(v.Changed), &change.Changed)
+		*res = append(*res, change)
+	}
+	return nil
+}
+
+// Sign 配置了 [token] list_sign_key 时对 Token List 做 EIP-712 签名
+//
+// 签名结构:
+//
+//	EIP712Domain(string name,string version,uint256 chainId)
+//	TokenList(string name,uint256 major,uint256 minor,uint256 patch,uint256 timestamp,bytes32 tokensHash)
+func (c *TokenList) Sign(chainId int, list *response.TokenList) error {
+	if config.Config().Token.ListSignKey == "" {
+		return nil
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(config.Config().Token.ListSignKey, "0x"))
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	tokensBytes, err := json.Marshal(list.Tokens)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	tokensHash := crypto.Keccak256(tokensBytes)
+
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+			},
+			"TokenList": {
+				{Name: "name", Type: "string"},
+				{Name: "major", Type: "uint256"},
+				{Name: "minor", Type: "uint256"},
+				{Name: "patch", Type: "uint256"},
+				{Name: "timestamp", Type: "uint256"},
+				{Name: "tokensHash", Type: "bytes32"},
+			},
+		},
+		PrimaryType: "TokenList",
+		Domain: apitypes.TypedDataDomain{
+			Name:    list.Name,
+			Version: "1",
+			ChainId: math.NewHexOrDecimal256(int64(chainId)),
+		},
+		Message: apitypes.TypedDataMessage{
+			"name":       list.Name,
+			"major":      utils.IntToString(list.Version.Major),
+			"minor":      utils.IntToString(list.Version.Minor),
+			"patch":      utils.IntToString(list.Version.Patch),
+			"timestamp":  utils.Int64ToString(list.Timestamp.Unix()),
+			"tokensHash": tokensHash,
+		},
+	}
+	domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	messageHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	digest := crypto.Keccak256([]byte("\x19\x01"), domainSeparator, messageHash)
+	signature, err := crypto.Sign(digest, key)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	signature[64] += 27
+
+	list.Signature = &response.TokenListSignature{
+		Signer:     crypto.PubkeyToAddress(key.PublicKey).Hex(),
+		TokensHash: hexutil.Encode(tokensHash),
+		Signature:  hexutil.Encode(signature),
+	}
+	return nil
+}
+
+// VersionTime 版本的生成时间，作为 Token List 的 timestamp
+func VersionTime(v *models.TokenListVersion) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", v.CreatedAt, time.Local)
+	if err != nil {
+		return time.Now()
+	}
+	return t
+}
+
+// listTokens token_info 转为 Token List 条目，元信息同步之前 name 为空时使用 symbol
+func listTokens(data []models.TokenList) []response.Token {
+	tokens := make([]response.Token, 0, len(data))
+	for _, v := range data {
+		name := v.Name
+		if name == "" {
+			name = v.Symbol
+		}
+		tokens = append(tokens, response.Token{
+			Name:     name,
+			Symbol:   v.Symbol,
+			Decimals: v.Decimals,
+			Address:  v.Token,
+			ChainID:  v.ChainId,
+			LogoURI:  NewAsset().Url(v.Logo),
+		})
+	}
+	return tokens
+}
+
+// sortTokens 按地址排序，保证同样的代币列表得到同样的快照
+func sortTokens(tokens []response.Token) []response.Token {
+	sorted := make([]response.Token, len(tokens))
+	copy(sorted, tokens)
+	sort.Slice(sorted, func(i, j int) bool {
+		return strings.ToLower(sorted[i].Address) < strings.ToLower(sorted[j].Address)
+	})
+	return sorted
+}
+
+func diffTokens(previous []response.Token, current map[string]response.Token) (added, removed, changed []string) {
+	added, removed, changed = []string{}, []string{}, []string{}
+	seen := map[string]bool{}
+	for _, p := range previous {
+		address := strings.ToLower(p.Address)
+		seen[address] = true
+		t, ok := current[address]
+		if !ok {
+			removed = append(removed, p.Address)
+		} else if t != p {
+			changed = append(changed, t.Address)
+		}
+	}
+	for address, t := range current {
+		if !seen[address] {
+			added = append(added, t.Address)
+		}
+	}
+	sort.Strings(added)
+	return added, removed, changed
+}
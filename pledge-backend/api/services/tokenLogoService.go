@@ -0,0 +1,193 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"gorm.io/gorm"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/response"
+	"pledge-backend/api/static"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"pledge-backend/utils"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type TokenLogo struct{}
+
+func NewTokenLogo() *TokenLogo {
+	return &TokenLogo{}
+}
+
+// Upload 保存上传的 logo 并更新 token_info.logo
+// PNG 会按 [token] logo_sizes 生成缩略图，SVG 原样保存
+func (s *TokenLogo) Upload(req *request.UploadTokenLogo, file *multipart.FileHeader, baseUrl string, res *response.TokenLogo) error {
+	chainId := utils.IntToString(req.ChainId)
+	token := models.NewTokenAdmin()
+	err := token.GetActiveByToken(chainId, common.HexToAddress(req.Address).Hex())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return statecode.New(statecode.TokenNotFound)
+		}
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+
+	if file.Size <= 0 || file.Size > config.Config().Token.LogoMaxSize {
+		return statecode.New(statecode.TokenLogoSizeErr)
+	}
+	f, err := file.Open()
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	defer f.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(f, config.Config().Token.LogoMaxSize+1))
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	if int64(len(body)) > config.Config().Token.LogoMaxSize {
+		return statecode.New(statecode.TokenLogoSizeErr)
+	}
+
+	// 文件名与 schedule 下载的 logo 一致，同一代币只保留一份
+	name := chainId + "_" + strings.ToLower(token.Token)
+	files := map[string][]byte{}
+	res.Variants = map[string]string{}
+	if http.DetectContentType(body) == "image/png" {
+		img, err := png.Decode(bytes.NewReader(body))
+		if err != nil {
+			return statecode.New(statecode.TokenLogoFormatErr)
+		}
+		size := img.Bounds().Dx()
+		if size != img.Bounds().Dy() || size < config.Config().Token.LogoMinDimension || size > config.Config().Token.LogoMaxDimension {
+			return statecode.New(statecode.TokenLogoSizeErr)
+		}
+		files[name+".png"] = body
+		res.Logo = baseUrl + "storage/img/tokens/" + name + ".png"
+		for _, v := range config.Config().Token.LogoSizes {
+			if v <= 0 || v > size {
+				continue
+			}
+			buf := bytes.Buffer{}
+			err = png.Encode(&buf, resizeImage(img, v))
+			if err != nil {
+				return statecode.Wrap(statecode.CommonErrServerErr, err)
+			}
+			variant := name + "_" + utils.IntToString(v) + ".png"
+			files[variant] = buf.Bytes()
+			res.Variants[utils.IntToString(v)] = baseUrl + "storage/img/tokens/" + variant
+		}
+	} else {
+		if !checkSvg(body) {
+			return statecode.New(statecode.TokenLogoFormatErr)
+		}
+		files[name+".svg"] = body
+		res.Logo = baseUrl + "storage/img/tokens/" + name + ".svg"
+		for _, v := range config.Config().Token.LogoSizes {
+			res.Variants[utils.IntToString(v)] = res.Logo
+		}
+	}
+
+	dir := static.TokenLogoPath()
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	for fileName, data := range files {
+		err = ioutil.WriteFile(path.Join(dir, fileName), data, 0644)
+		if err != nil {
+			return statecode.Wrap(statecode.CommonErrServerErr, err)
+		}
+	}
+
+	err = token.Update(map[string]interface{}{
+		"logo":            res.Logo,
+		"logo_source":     models.LogoSourceUpload,
+		"logo_origin_url": file.Filename,
+	})
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	// 同一地址覆盖上传时 logoURI 的内容哈希变化，同样生成新版本
+	if err = NewTokenList().Refresh(req.ChainId); err != nil {
+		log.Logger.Error(err.Error())
+	}
+	return nil
+}
+
+// resizeImage 按区域平均缩放为 size*size
+func resizeImage(src image.Image, size int) *image.RGBA64 {
+	b := src.Bounds()
+	dst := image.NewRGBA64(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0 := b.Min.Y + y*b.Dy()/size
+		y1 := b.Min.Y + (y+1)*b.Dy()/size
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < size; x++ {
+			x0 := b.Min.X + x*b.Dx()/size
+			x1 := b.Min.X + (x+1)*b.Dx()/size
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
+
+// checkSvg 根元素必须是 svg，且不能包含脚本和事件属性
+func checkSvg(body []byte) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	root := true
+	for {
+		t, err := decoder.Token()
+		if err == io.EOF {
+			return !root
+		}
+		if err != nil {
+			return false
+		}
+		el, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if root && el.Name.Local != "svg" {
+			return false
+		}
+		root = false
+		if strings.EqualFold(el.Name.Local, "script") || strings.EqualFold(el.Name.Local, "foreignObject") {
+			return false
+		}
+		for _, attr := range el.Attr {
+			if strings.HasPrefix(strings.ToLower(attr.Name.Local), "on") {
+				return false
+			}
+			if attr.Name.Local == "href" && !strings.HasPrefix(attr.Value, "#") {
+				return false
+			}
+		}
+	}
+}
@@ -1,13 +1,16 @@
 package services
 
 import (
+	"errors"
+	"gorm.io/gorm"
 	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
 	"pledge-backend/api/models/request"
 	"pledge-backend/api/models/response"
 	"pledge-backend/config"
 	"pledge-backend/db"
-	"pledge-backend/log"
 	"pledge-backend/utils"
+	"strings"
 )
 
 type UserService struct{}
@@ -16,19 +19,39 @@ func NewUser() *UserService {
 	return &UserService{}
 }
 
-func (s *UserService) Login(req *request.Login, result *response.Login) int {
-	log.Logger.Sugar().Info("contractService", req)
-	if req.Name == "admin" && req.Password == "password" {
-		token, err := utils.CreateToken(req.Name)
-		if err != nil {
-			log.Logger.Error("CreateToken" + err.Error())
-			return statecode.CommonErrServerErr
+// Login 用户名和密码与 admin 表中的 bcrypt 哈希比较，账号由 pledge seed 的 admin.yaml 创建
+func (s *UserService) Login(req *request.Login, result *response.Login) error {
+	admin := models.NewAdmin()
+	err := admin.GetByName(req.Name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return statecode.New(statecode.NameOrPasswordErr)
 		}
-		result.TokenId = token
-		//save to redis
-		_ = db.RedisSet(req.Name, "login_ok", config.Config.Jwt.ExpireTime)
-		return statecode.CommonSuccess
-	} else {
-		return statecode.NameOrPasswordErr
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
 	}
+	if !utils.CheckPasswordHash(req.Password, admin.Password) {
+		return statecode.New(statecode.NameOrPasswordErr)
+	}
+
+	token, err := utils.CreateToken(admin.Name)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	result.TokenId = token
+	//save to redis
+	_ = db.RedisSet(admin.Name, "login_ok", config.Config().Jwt.ExpireTime)
+	return nil
+}
+
+// WalletNonce 为钱包生成一次性 nonce 和需要签名的消息，签名后用于订阅 WebSocket 主题 claimable:{chainId}:{address}
+func (s *UserService) WalletNonce(req *request.WalletNonce, result *response.WalletNonce) error {
+	nonce, err := models.NewWalletNonce().Create(req.Address)
+	if err != nil {
+		return statecode.Wrap(statecode.CommonErrServerErr, err)
+	}
+	result.Address = strings.ToLower(req.Address)
+	result.Nonce = nonce
+	result.Message = models.WalletNonceMessage(req.Address, nonce)
+	result.ExpiresIn = models.WalletNonceTtl
+	return nil
 }
@@ -0,0 +1,102 @@
+package static
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// assetHashLen 内容哈希在文件名中的长度 (十六进制)
+const assetHashLen = 12
+
+// ErrAssetNotFound 文件不存在、是目录或路径不在 static 目录下
+var ErrAssetNotFound = errors.New("asset not found")
+
+// assetHash 文件内容的哈希，文件的修改时间或大小变化后重新计算
+type assetHash struct {
+	modTime time.Time
+	size    int64
+	hash    string
+}
+
+var (
+	assetMu     sync.Mutex
+	assetHashes = map[string]assetHash{}
+)
+
+// HashedPath static 目录下文件 rel 带内容哈希的路径，通过 /assets/ 访问
+// 例如 img/BTC.png -> img/BTC.3f2a9c1d5e7b.png，文件内容变化后路径随之变化，可以长期缓存
+func HashedPath(rel string) (string, error) {
+	rel, file, err := assetFile(rel)
+	if err != nil {
+		return "", err
+	}
+	hash, err := contentHash(rel, file)
+	if err != nil {
+		return "", err
+	}
+	ext := path.Ext(rel)
+	return strings.TrimSuffix(rel, ext) + "." + hash + ext, nil
+}
+
+// ResolveHashed 带内容哈希的路径对应的文件，返回文件的绝对路径和文件当前的哈希路径
+// 文件在 hashed 生成之后被替换时 current 与 hashed 不同，旧内容已不存在
+func ResolveHashed(hashed string) (file, current string, err error) {
+	ext := path.Ext(hashed)
+	name := strings.TrimSuffix(hashed, ext)
+	i := strings.LastIndex(name, ".")
+	if i < 0 || len(name)-i-1 != assetHashLen {
+		return "", "", ErrAssetNotFound
+	}
+	rel := name[:i] + ext
+	if _, file, err = assetFile(rel); err != nil {
+		return "", "", err
+	}
+	current, err = HashedPath(rel)
+	return file, current, err
+}
+
+// assetFile 清理 rel 并返回 static 目录下的普通文件，不允许 .. 跳出 static 目录
+func assetFile(rel string) (string, string, error) {
+	rel = strings.TrimPrefix(path.Clean("/"+rel), "/")
+	if rel == "" || strings.HasSuffix(rel, ".go") {
+		return "", "", ErrAssetNotFound
+	}
+	file := path.Join(GetCurrentAbPathByCaller(), rel)
+	info, err := os.Stat(file)
+	if err != nil || !info.Mode().IsRegular() {
+		return "", "", ErrAssetNotFound
+	}
+	return rel, file, nil
+}
+
+// contentHash 文件内容 SHA-256 的前 assetHashLen 位，按修改时间和大小缓存
+func contentHash(rel, file string) (string, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return "", ErrAssetNotFound
+	}
+	assetMu.Lock()
+	cached, ok := assetHashes[rel]
+	assetMu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.hash, nil
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])[:assetHashLen]
+	assetMu.Lock()
+	assetHashes[rel] = assetHash{modTime: info.ModTime(), size: info.Size(), hash: hash}
+	assetMu.Unlock()
+	return hash, nil
+}
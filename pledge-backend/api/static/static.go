@@ -13,3 +13,8 @@ func GetCurrentAbPathByCaller() string {
 	}
 	return abPath
 }
+
+// TokenLogoPath 代币 logo 的存放目录，通过 /storage/img/tokens 访问
+func TokenLogoPath() string {
+	return path.Join(GetCurrentAbPathByCaller(), "img", "tokens")
+}
@@ -0,0 +1,35 @@
+package validate
+
+import (
+	"pledge-backend/api/common/statecode"
+	"strconv"
+	"strings"
+)
+
+// chainIdsAll chainId=all 时查询的链
+var chainIdsAll = []int{97, 56}
+
+// parseChainIds 解析 chainId 参数: 单个链 ID、逗号分隔的多个链 ID 或 all，去重后按参数顺序返回
+// multi 为 true (列表或 all) 时结果按链分组返回
+func parseChainIds(s string) (chainIds []int, multi bool, errCode int) {
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, "all") {
+		return append([]int{}, chainIdsAll...), true, statecode.CommonSuccess
+	}
+	parts := strings.Split(s, ",")
+	seen := make(map[int]bool, len(parts))
+	for _, part := range parts {
+		chainId, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, false, statecode.ParameterErr
+		}
+		if chainId != 97 && chainId != 56 {
+			return nil, false, statecode.ChainIdErr
+		}
+		if !seen[chainId] {
+			seen[chainId] = true
+			chainIds = append(chainIds, chainId)
+		}
+	}
+	return chainIds, len(parts) > 1, statecode.CommonSuccess
+}
@@ -0,0 +1,50 @@
+package validate
+
+import (
+	"github.com/gin-gonic/gin"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+	"strconv"
+	"time"
+)
+
+type Changes struct{}
+
+func NewChanges() *Changes {
+	return &Changes{}
+}
+
+func (v *Changes) Changes(c *gin.Context, req *request.Changes) int {
+	if c.ShouldBindQuery(req) != nil {
+		return statecode.ChainIdEmpty
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	var errCode int
+	if req.SinceTs, errCode = parseSince(req.Since); errCode != statecode.CommonSuccess {
+		return errCode
+	}
+
+	return statecode.CommonSuccess
+}
+
+// parseSince Unix 秒或 RFC3339 时间，为空时返回 0，不能晚于当前时间
+func parseSince(s string) (int64, int) {
+	if s == "" {
+		return 0, statecode.CommonSuccess
+	}
+	since, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return 0, statecode.ParameterErr
+		}
+		since = t.Unix()
+	}
+	if since < 0 || since > time.Now().Unix() {
+		return 0, statecode.ParameterErr
+	}
+	return since, statecode.CommonSuccess
+}
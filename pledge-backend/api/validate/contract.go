@@ -0,0 +1,25 @@
+package validate
+
+import (
+	"github.com/gin-gonic/gin"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+)
+
+type Contract struct{}
+
+func NewContract() *Contract {
+	return &Contract{}
+}
+
+func (v *Contract) Contracts(c *gin.Context, req *request.Contracts) int {
+	if c.ShouldBindQuery(req) != nil {
+		return statecode.ChainIdEmpty
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+
+	return statecode.CommonSuccess
+}
@@ -0,0 +1,93 @@
+package validate
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"net/mail"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"pledge-backend/config"
+)
+
+// subscriptionEvents 可订阅的池子事件
+var subscriptionEvents = map[string]bool{
+	models.SubscriptionEventSettled:    true,
+	models.SubscriptionEventFinished:   true,
+	models.SubscriptionEventLiquidated: true,
+	models.SubscriptionEventClaimable:  true,
+}
+
+type EmailSubscription struct{}
+
+func NewEmailSubscription() *EmailSubscription {
+	return &EmailSubscription{}
+}
+
+func (v *EmailSubscription) Subscribe(c *gin.Context, req *request.Subscribe) int {
+	if !config.Config().Subscription.Enabled {
+		return statecode.ApiDisabled
+	}
+
+	errCode := bindJSON(c, req)
+	if errCode != statecode.CommonSuccess {
+		return errCode
+	}
+	if c.ShouldBindUri(req) != nil || !common.IsHexAddress(req.Address) {
+		return statecode.ParameterErr
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if req.PoolId <= 0 {
+		return statecode.ParameterEmptyErr
+	}
+	// 只接受纯地址，不接受 "Name <a@b.com>" 形式
+	addr, err := mail.ParseAddress(req.Email)
+	if err != nil || addr.Address != req.Email {
+		return statecode.SubscriptionEmailErr
+	}
+	if len(req.Events) == 0 {
+		return statecode.ParameterEmptyErr
+	}
+	for _, event := range req.Events {
+		if !subscriptionEvents[event] {
+			return statecode.SubscriptionEventErr
+		}
+	}
+
+	return statecode.CommonSuccess
+}
+
+// Token 验证和退订链接
+func (v *EmailSubscription) Token(c *gin.Context, req *request.SubscriptionToken) int {
+	if !config.Config().Subscription.Enabled {
+		return statecode.ApiDisabled
+	}
+
+	if c.ShouldBindQuery(req) != nil || len(req.Token) > 64 {
+		return statecode.ParameterErr
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *EmailSubscription) List(c *gin.Context, req *request.Subscriptions) int {
+	if !config.Config().Subscription.Enabled {
+		return statecode.ApiDisabled
+	}
+
+	if c.ShouldBindQuery(req) != nil {
+		return statecode.ChainIdEmpty
+	}
+	if c.ShouldBindUri(req) != nil || !common.IsHexAddress(req.Address) {
+		return statecode.ParameterErr
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+
+	return statecode.CommonSuccess
+}
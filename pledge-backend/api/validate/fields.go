@@ -0,0 +1,43 @@
+package validate
+
+import (
+	"pledge-backend/api/common/statecode"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fieldsMax ?fields= 最多的字段数
+const fieldsMax = 50
+
+// fieldNameRegexp JSON 字段名，嵌套字段用 . 连接，例如 pool_data.state
+var fieldNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// parseFields 解析 query 中逗号分隔的 fields (JSON:API sparse fieldsets)，未传时返回 nil 表示返回全部字段
+// POST 接口的 fields 同样放在 query 中
+func parseFields(c *gin.Context) ([]string, int) {
+	s := strings.TrimSpace(c.Query("fields"))
+	if s == "" {
+		return nil, statecode.CommonSuccess
+	}
+	parts := strings.Split(s, ",")
+	fields := make([]string, 0, len(parts))
+	for _, part := range parts {
+		fields = append(fields, strings.TrimSpace(part))
+	}
+	return fields, checkFields(fields)
+}
+
+// checkFields 字段数和字段名检查，WebSocket RPC 的 fields 参数为数组，同样使用
+func checkFields(fields []string) int {
+	if len(fields) > fieldsMax {
+		return statecode.ParameterErr
+	}
+	for _, field := range fields {
+		if !fieldNameRegexp.MatchString(field) {
+			return statecode.ParameterErr
+		}
+	}
+	return statecode.CommonSuccess
+}
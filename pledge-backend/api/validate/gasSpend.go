@@ -0,0 +1,38 @@
+package validate
+
+import (
+	"github.com/gin-gonic/gin"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+	"pledge-backend/config"
+	"strconv"
+	"time"
+)
+
+type GasSpend struct{}
+
+func NewGasSpend() *GasSpend {
+	return &GasSpend{}
+}
+
+func (v *GasSpend) Summary(c *gin.Context, req *request.GasSummary) int {
+
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.Month == "" {
+		req.Month = time.Now().Format("2006-01")
+	}
+	if _, err = time.Parse("2006-01", req.Month); err != nil {
+		return statecode.ParameterErr
+	}
+	// [testnet] / [mainnet] 中配置的链，[devnet] enabled 时为本地链
+	chainId := strconv.Itoa(req.ChainId)
+	if req.ChainId != 0 && chainId != config.Config().TestNet.ChainId && chainId != config.Config().MainNet.ChainId {
+		return statecode.ChainIdErr
+	}
+
+	return statecode.CommonSuccess
+}
@@ -0,0 +1,24 @@
+package validate
+
+import (
+	"github.com/gin-gonic/gin"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+	"pledge-backend/config"
+)
+
+type Graphql struct{}
+
+func NewGraphql() *Graphql {
+	return &Graphql{}
+}
+
+func (v *Graphql) Query(c *gin.Context, req *request.Graphql) int {
+	if !config.Config().Graphql.Enabled {
+		return statecode.ApiDisabled
+	}
+	if c.ShouldBindJSON(req) != nil {
+		return statecode.ParameterErr
+	}
+	return statecode.CommonSuccess
+}
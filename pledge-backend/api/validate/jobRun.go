@@ -0,0 +1,47 @@
+package validate
+
+import (
+	"github.com/gin-gonic/gin"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+)
+
+type JobRun struct{}
+
+func NewJobRun() *JobRun {
+	return &JobRun{}
+}
+
+func (v *JobRun) Runs(c *gin.Context, req *request.JobRuns) int {
+
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.Limit == 0 {
+		req.Limit = 50
+	}
+	if req.Limit < 0 || req.Limit > 500 {
+		return statecode.ParameterErr
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *JobRun) Retries(c *gin.Context, req *request.JobRetries) int {
+
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.Status == "" {
+		req.Status = "dead"
+	}
+	if req.Status != "pending" && req.Status != "dead" {
+		return statecode.ParameterErr
+	}
+
+	return statecode.CommonSuccess
+}
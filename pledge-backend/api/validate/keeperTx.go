@@ -0,0 +1,38 @@
+package validate
+
+import (
+	"github.com/gin-gonic/gin"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+)
+
+type KeeperTx struct{}
+
+func NewKeeperTx() *KeeperTx {
+	return &KeeperTx{}
+}
+
+func (v *KeeperTx) List(c *gin.Context, req *request.KeeperTxs) int {
+
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.ChainId != 0 && req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	switch req.Status {
+	case "", "pending", "success", "failed", "dropped", "dry_run", "skipped", "error":
+	default:
+		return statecode.ParameterErr
+	}
+	if req.Limit == 0 {
+		req.Limit = 50
+	}
+	if req.Limit < 0 || req.Limit > 500 || req.PoolId < 0 {
+		return statecode.ParameterErr
+	}
+
+	return statecode.CommonSuccess
+}
@@ -0,0 +1,36 @@
+package validate
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"io"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+)
+
+type Maintenance struct{}
+
+func NewMaintenance() *Maintenance {
+	return &Maintenance{}
+}
+
+func (v *Maintenance) SetMaintenance(c *gin.Context, req *request.Maintenance) int {
+
+	err := c.ShouldBindJSON(req)
+	if err == io.EOF {
+		return statecode.ParameterEmptyErr
+	} else if err != nil {
+		errs, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return statecode.ParameterErr
+		}
+		for _, e := range errs {
+			if e.Field() == "Enabled" && e.Tag() == "required" {
+				return statecode.ParameterEmptyErr
+			}
+		}
+		return statecode.ParameterErr
+	}
+
+	return statecode.CommonSuccess
+}
@@ -6,6 +6,8 @@ import (
 	"io"
 	"pledge-backend/api/common/statecode"
 	"pledge-backend/api/models/request"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 type MutiSign struct{}
@@ -32,6 +34,15 @@ func (v *MutiSign) SetMultiSign(c *gin.Context, req *request.SetMultiSign) int {
 		return statecode.CommonErrServerErr
 	}
 
+	for _, account := range req.MultiSignAccount {
+		if !common.IsHexAddress(account) {
+			return statecode.MultiSignAddressErr
+		}
+	}
+	if req.Threshold < 1 || req.Threshold > len(req.MultiSignAccount) {
+		return statecode.MultiSignThresholdErr
+	}
+
 	return statecode.CommonSuccess
 }
 
@@ -55,3 +66,20 @@ func (v *MutiSign) GetMultiSign(c *gin.Context, req *request.GetMultiSign) int {
 
 	return statecode.CommonSuccess
 }
+
+func (v *MutiSign) History(c *gin.Context, req *request.MultiSignHistory) int {
+
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		return statecode.ChainIdEmpty
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+
+	return statecode.CommonSuccess
+}
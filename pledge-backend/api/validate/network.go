@@ -0,0 +1,25 @@
+package validate
+
+import (
+	"github.com/gin-gonic/gin"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+)
+
+type Network struct{}
+
+func NewNetwork() *Network {
+	return &Network{}
+}
+
+func (v *Network) Network(c *gin.Context, req *request.Network) int {
+	if c.ShouldBindUri(req) != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+
+	return statecode.CommonSuccess
+}
@@ -0,0 +1,37 @@
+package validate
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+)
+
+type Oracle struct{}
+
+func NewOracle() *Oracle {
+	return &Oracle{}
+}
+
+func (v *Oracle) SimulateSetPrice(c *gin.Context, req *request.OracleSimulateSetPrice) int {
+
+	errCode := bindJSON(c, req)
+	if errCode != statecode.CommonSuccess {
+		return errCode
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if req.Token != "" && !checksumAddress(req.Token) {
+		return statecode.TokenAddressErr
+	}
+	if req.Price != "" {
+		price, err := decimal.NewFromString(req.Price)
+		if err != nil || !price.IsPositive() {
+			return statecode.ParameterErr
+		}
+	}
+
+	return statecode.CommonSuccess
+}
@@ -5,7 +5,11 @@ import (
 	"github.com/go-playground/validator/v10"
 	"io"
 	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
 	"pledge-backend/api/models/request"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
 )
 
 type PoolBaseInfo struct{}
@@ -21,16 +25,144 @@ func (v *PoolBaseInfo) PoolBaseInfo(c *gin.Context, req *request.PoolBaseInfo) i
 	} else if err != nil {
 		errs := err.(validator.ValidationErrors)
 		for _, e := range errs {
-			if e.Field() == "ChainId" && e.Tag() == "required" {
+			if e.Field() == "Chains" && e.Tag() == "required" {
 				return statecode.ChainIdEmpty
 			}
 		}
 		return statecode.CommonErrServerErr
 	}
 
+	var errCode int
+	if req.ChainIds, req.Multi, errCode = parseChainIds(req.Chains); errCode != statecode.CommonSuccess {
+		return errCode
+	}
+	if !validArchived(req.Archived) {
+		return statecode.ParameterErr
+	}
+	if req.Fields, errCode = parseFields(c); errCode != statecode.CommonSuccess {
+		return errCode
+	}
+
+	return statecode.CommonSuccess
+}
+
+// validArchived 池子列表的 archived 参数，为空时不返回已归档的池子
+func validArchived(archived string) bool {
+	switch archived {
+	case "", models.ArchivedExclude, models.ArchivedInclude, models.ArchivedOnly:
+		return true
+	}
+	return false
+}
+
+func (v *PoolBaseInfo) PoolDetail(c *gin.Context, req *request.PoolDetail) int {
+	err := c.ShouldBindUri(req)
+	if err != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if req.PoolId <= 0 {
+		return statecode.ParameterErr
+	}
+
+	var errCode int
+	req.Fields, errCode = parseFields(c)
+	return errCode
+}
+
+func (v *PoolBaseInfo) PoolHistory(c *gin.Context, req *request.PoolHistory) int {
+	if c.ShouldBindUri(req) != nil || c.ShouldBindQuery(req) != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if req.PoolId <= 0 || req.From < 0 || req.To < 0 || req.Limit < 0 {
+		return statecode.ParameterErr
+	}
+	if req.To > 0 && req.To < req.From {
+		return statecode.ParameterErr
+	}
+	if req.Limit == 0 || req.Limit > 1000 {
+		req.Limit = 1000
+	}
+	if req.Format != "" && req.Format != "json" && req.Format != "csv" {
+		return statecode.ParameterErr
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *PoolBaseInfo) PoolEstimate(c *gin.Context, req *request.PoolEstimate) int {
+	if c.ShouldBindQuery(req) != nil || c.ShouldBindUri(req) != nil {
+		return statecode.ParameterErr
+	}
+
 	if req.ChainId != 97 && req.ChainId != 56 {
 		return statecode.ChainIdErr
 	}
+	if req.PoolId <= 0 || (req.Side != "lend" && req.Side != "borrow") {
+		return statecode.ParameterErr
+	}
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil || !amount.IsPositive() || !amount.IsInteger() {
+		return statecode.ParameterErr
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *PoolBaseInfo) PoolStats(c *gin.Context, req *request.PoolStats) int {
+	if c.ShouldBindUri(req) != nil || c.ShouldBindQuery(req) != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if req.PoolId <= 0 || req.Top < 0 {
+		return statecode.ParameterErr
+	}
+	if req.Top == 0 {
+		req.Top = 5
+	}
+	if req.Top > 20 {
+		req.Top = 20
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *PoolBaseInfo) PoolWhitelist(c *gin.Context, req *request.PoolWhitelist) int {
+	if c.ShouldBindUri(req) != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if req.PoolId <= 0 {
+		return statecode.ParameterErr
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *PoolBaseInfo) PoolWhitelistCheck(c *gin.Context, req *request.PoolWhitelistCheck) int {
+	if c.ShouldBindQuery(req) != nil || c.ShouldBindUri(req) != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if req.PoolId <= 0 || !common.IsHexAddress(req.Address) {
+		return statecode.ParameterErr
+	}
 
 	return statecode.CommonSuccess
 }
@@ -21,15 +21,19 @@ func (v *PoolDataInfo) PoolDataInfo(c *gin.Context, req *request.PoolDataInfo) i
 	} else if err != nil {
 		errs := err.(validator.ValidationErrors)
 		for _, e := range errs {
-			if e.Field() == "ChainId" && e.Tag() == "required" {
+			if e.Field() == "Chains" && e.Tag() == "required" {
 				return statecode.ChainIdEmpty
 			}
 		}
 		return statecode.CommonErrServerErr
 	}
 
-	if req.ChainId != 97 && req.ChainId != 56 {
-		return statecode.ChainIdErr
+	var errCode int
+	if req.ChainIds, req.Multi, errCode = parseChainIds(req.Chains); errCode != statecode.CommonSuccess {
+		return errCode
+	}
+	if req.Fields, errCode = parseFields(c); errCode != statecode.CommonSuccess {
+		return errCode
 	}
 
 	return statecode.CommonSuccess
@@ -0,0 +1,72 @@
+package validate
+
+import (
+	"github.com/gin-gonic/gin"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+	"regexp"
+)
+
+// poolTagRegexp 风险标签格式，例如 high-risk、stable
+var poolTagRegexp = regexp.MustCompile(`^[a-z0-9-]{1,32}$`)
+
+const maxPoolTags = 10
+
+type PoolMetadata struct{}
+
+func NewPoolMetadata() *PoolMetadata {
+	return &PoolMetadata{}
+}
+
+func (v *PoolMetadata) List(c *gin.Context, req *request.PoolMetadataList) int {
+
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		return statecode.ChainIdEmpty
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *PoolMetadata) Save(c *gin.Context, req *request.SavePoolMetadata) int {
+
+	errCode := bindJSON(c, req)
+	if errCode != statecode.CommonSuccess {
+		return errCode
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if req.PoolId <= 0 {
+		return statecode.ParameterEmptyErr
+	}
+	if len(req.Tags) > maxPoolTags {
+		return statecode.PoolMetadataTagErr
+	}
+	for _, tag := range req.Tags {
+		if !poolTagRegexp.MatchString(tag) {
+			return statecode.PoolMetadataTagErr
+		}
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *PoolMetadata) Delete(c *gin.Context, req *request.DeletePoolMetadata) int {
+
+	errCode := bindJSON(c, req)
+	if errCode != statecode.CommonSuccess {
+		return errCode
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+
+	return statecode.CommonSuccess
+}
@@ -0,0 +1,94 @@
+package validate
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/kucoin"
+	"pledge-backend/api/models/request"
+	"strings"
+)
+
+// pricesMaxTokens 批量查询价格一次最多的代币数
+const pricesMaxTokens = 50
+
+type Price struct{}
+
+func NewPrice() *Price {
+	return &Price{}
+}
+
+func (v *Price) Prices(c *gin.Context, req *request.Prices) int {
+	if c.ShouldBindQuery(req) != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	seen := make(map[string]bool)
+	for _, token := range strings.Split(req.Tokens, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		if !common.IsHexAddress(token) {
+			return statecode.TokenAddressErr
+		}
+		address := common.HexToAddress(token).Hex()
+		if !seen[address] {
+			seen[address] = true
+			req.Addresses = append(req.Addresses, address)
+		}
+	}
+	if len(req.Addresses) == 0 || len(req.Addresses) > pricesMaxTokens {
+		return statecode.ParameterErr
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *Price) Convert(c *gin.Context, req *request.Convert) int {
+	if c.ShouldBindQuery(req) != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if !common.IsHexAddress(req.From) || !common.IsHexAddress(req.To) {
+		return statecode.TokenAddressErr
+	}
+	req.From = common.HexToAddress(req.From).Hex()
+	req.To = common.HexToAddress(req.To).Hex()
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil || amount.IsNegative() || !amount.IsInteger() {
+		return statecode.ParameterErr
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *Price) Slippage(c *gin.Context, req *request.Slippage) int {
+	if c.ShouldBindQuery(req) != nil || c.ShouldBindUri(req) != nil {
+		return statecode.ParameterErr
+	}
+
+	req.Symbol = strings.ToUpper(req.Symbol)
+	if !kucoin.DepthEnabled(req.Symbol) {
+		return statecode.DepthSymbolErr
+	}
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil || !amount.IsPositive() {
+		return statecode.ParameterErr
+	}
+	if req.Side == "" {
+		req.Side = "sell"
+	}
+	if req.Side != "buy" && req.Side != "sell" {
+		return statecode.ParameterErr
+	}
+
+	return statecode.CommonSuccess
+}
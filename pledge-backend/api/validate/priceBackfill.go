@@ -0,0 +1,44 @@
+package validate
+
+import (
+	"github.com/gin-gonic/gin"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+)
+
+type PriceBackfill struct{}
+
+func NewPriceBackfill() *PriceBackfill {
+	return &PriceBackfill{}
+}
+
+func (v *PriceBackfill) List(c *gin.Context, req *request.PriceBackfillList) int {
+
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		return statecode.ParameterEmptyErr
+	}
+
+	if req.ChainId != 0 && req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *PriceBackfill) Submit(c *gin.Context, req *request.PriceBackfill) int {
+
+	errCode := bindJSON(c, req)
+	if errCode != statecode.CommonSuccess {
+		return errCode
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if !checksumAddress(req.Token) {
+		return statecode.TokenAddressErr
+	}
+
+	return statecode.CommonSuccess
+}
@@ -0,0 +1,41 @@
+package validate
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+)
+
+type PriceHistory struct{}
+
+func NewPriceHistory() *PriceHistory {
+	return &PriceHistory{}
+}
+
+func (v *PriceHistory) PriceHistory(c *gin.Context, req *request.PriceHistory) int {
+	if c.ShouldBindQuery(req) != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if !common.IsHexAddress(req.Token) {
+		return statecode.TokenAddressErr
+	}
+	if req.From < 0 || req.To < 0 || req.Limit < 0 {
+		return statecode.ParameterErr
+	}
+	if req.To > 0 && req.To < req.From {
+		return statecode.ParameterErr
+	}
+	if req.Limit == 0 || req.Limit > 1000 {
+		req.Limit = 1000
+	}
+	if req.Format != "" && req.Format != "json" && req.Format != "csv" {
+		return statecode.ParameterErr
+	}
+
+	return statecode.CommonSuccess
+}
@@ -0,0 +1,57 @@
+package validate
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"io"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+)
+
+type PriceQuarantine struct{}
+
+func NewPriceQuarantine() *PriceQuarantine {
+	return &PriceQuarantine{}
+}
+
+func (v *PriceQuarantine) List(c *gin.Context, req *request.PriceQuarantineList) int {
+
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		return statecode.ParameterEmptyErr
+	}
+
+	if req.Status == "" {
+		req.Status = "pending"
+	}
+	if req.Status != "pending" && req.Status != "approved" && req.Status != "rejected" {
+		return statecode.ParameterEmptyErr
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *PriceQuarantine) Review(c *gin.Context, req *request.ReviewPriceQuarantine) int {
+
+	err := c.ShouldBindJSON(req)
+	if err == io.EOF {
+		return statecode.ParameterEmptyErr
+	} else if err != nil {
+		errs, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return statecode.CommonErrServerErr
+		}
+		for _, e := range errs {
+			if e.Tag() == "required" {
+				return statecode.ParameterEmptyErr
+			}
+		}
+		return statecode.CommonErrServerErr
+	}
+
+	if req.Action != "approve" && req.Action != "reject" {
+		return statecode.QuarantineActionErr
+	}
+
+	return statecode.CommonSuccess
+}
@@ -0,0 +1,122 @@
+package validate
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+	"pledge-backend/config"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	referralCodeRegexp = regexp.MustCompile(`^[0-9A-Z]{8}$`)
+	txHashRegexp       = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+)
+
+type Referral struct{}
+
+func NewReferral() *Referral {
+	return &Referral{}
+}
+
+func (v *Referral) Code(c *gin.Context, req *request.ReferralCode) int {
+	if !config.Config().Referral.Enabled {
+		return statecode.ApiDisabled
+	}
+
+	if c.ShouldBindUri(req) != nil || !common.IsHexAddress(req.Address) {
+		return statecode.ParameterErr
+	}
+
+	return statecode.CommonSuccess
+}
+
+// Attribute 推荐码不区分大小写，交易哈希统一为小写
+func (v *Referral) Attribute(c *gin.Context, req *request.ReferralAttribute) int {
+	if !config.Config().Referral.Enabled {
+		return statecode.ApiDisabled
+	}
+
+	errCode := bindJSON(c, req)
+	if errCode != statecode.CommonSuccess {
+		return errCode
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	req.Code = strings.ToUpper(req.Code)
+	if !referralCodeRegexp.MatchString(req.Code) || !txHashRegexp.MatchString(req.TxHash) {
+		return statecode.ParameterErr
+	}
+	req.TxHash = strings.ToLower(req.TxHash)
+
+	return statecode.CommonSuccess
+}
+
+func (v *Referral) Referral(c *gin.Context, req *request.Referral) int {
+	if !config.Config().Referral.Enabled {
+		return statecode.ApiDisabled
+	}
+
+	if c.ShouldBindQuery(req) != nil {
+		return statecode.ChainIdEmpty
+	}
+	if c.ShouldBindUri(req) != nil || !common.IsHexAddress(req.Address) {
+		return statecode.ParameterErr
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	since, ok := referralWindow(&req.Window)
+	if !ok {
+		return statecode.ParameterErr
+	}
+	req.Since = since
+
+	return statecode.CommonSuccess
+}
+
+func (v *Referral) Stats(c *gin.Context, req *request.ReferralStats) int {
+	if !config.Config().Referral.Enabled {
+		return statecode.ApiDisabled
+	}
+
+	if c.ShouldBindQuery(req) != nil {
+		return statecode.ChainIdEmpty
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if req.Limit == 0 {
+		req.Limit = leaderboardDefaultLimit
+	}
+	if req.Limit < 0 || req.Limit > leaderboardMaxLimit {
+		return statecode.ParameterErr
+	}
+	since, ok := referralWindow(&req.Window)
+	if !ok {
+		return statecode.ParameterErr
+	}
+	req.Since = since
+
+	return statecode.CommonSuccess
+}
+
+// referralWindow 与排行榜相同的时间窗口，为空时设为 all，返回窗口开始时间，all 时为 0
+func referralWindow(window *string) (int64, bool) {
+	if *window == "" || *window == leaderboardWindowAll {
+		*window = leaderboardWindowAll
+		return 0, true
+	}
+	d := rangeDuration(*window)
+	if d == 0 || d > tvlMaxRange {
+		return 0, false
+	}
+	return time.Now().Unix() - d, true
+}
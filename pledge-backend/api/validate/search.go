@@ -6,6 +6,10 @@ import (
 	"io"
 	"pledge-backend/api/common/statecode"
 	"pledge-backend/api/models/request"
+	"pledge-backend/config"
+	"strings"
+
+	"github.com/shopspring/decimal"
 )
 
 type Search struct{}
@@ -33,5 +37,80 @@ func (s *Search) Search(c *gin.Context, req *request.Search) int {
 		return statecode.ChainIdErr
 	}
 
+	return s.filter(c, req)
+}
+
+// PublicSearch 公开搜索使用 query 参数，分页参数有默认值和上限
+func (s *Search) PublicSearch(c *gin.Context, req *request.Search) int {
+
+	if !config.Config().Search.PublicEnabled {
+		return statecode.ApiDisabled
+	}
+
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		return statecode.ChainIdEmpty
+	}
+
+	if req.ChainID != 97 && req.ChainID != 56 {
+		return statecode.ChainIdErr
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if config.Config().Search.PublicMaxPageSize > 0 && req.PageSize > config.Config().Search.PublicMaxPageSize {
+		req.PageSize = config.Config().Search.PublicMaxPageSize
+	}
+
+	return s.filter(c, req)
+}
+
+func (s *Search) TokenSearch(c *gin.Context, req *request.TokenSearch) int {
+
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		return statecode.ParameterEmptyErr
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	req.Keyword = strings.TrimSpace(req.Keyword)
+	if req.Keyword == "" || len(req.Keyword) > 64 {
+		return statecode.ParameterErr
+	}
+
+	var errCode int
+	req.Fields, errCode = parseFields(c)
+	return errCode
+}
+
+// filter 校验组合筛选条件和 fields
+func (s *Search) filter(c *gin.Context, req *request.Search) int {
+	var errCode int
+	if req.Fields, errCode = parseFields(c); errCode != statecode.CommonSuccess {
+		return errCode
+	}
+	req.Keyword = strings.TrimSpace(req.Keyword)
+	if len(req.Keyword) > 64 || len(req.States) > 10 {
+		return statecode.ParameterErr
+	}
+	for _, rate := range []string{req.InterestRateMin, req.InterestRateMax} {
+		if rate == "" {
+			continue
+		}
+		if _, err := decimal.NewFromString(rate); err != nil {
+			return statecode.ParameterErr
+		}
+	}
+	if req.EndTimeFrom < 0 || req.EndTimeTo < 0 || (req.EndTimeTo > 0 && req.EndTimeFrom > req.EndTimeTo) {
+		return statecode.ParameterErr
+	}
+	if !validArchived(req.Archived) {
+		return statecode.ParameterErr
+	}
 	return statecode.CommonSuccess
 }
@@ -0,0 +1,182 @@
+package validate
+
+import (
+	"github.com/gin-gonic/gin"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tvl 序列的时间间隔、最长范围和最多点数
+var tvlIntervals = map[string]int64{"1h": 3600, "4h": 4 * 3600, "1d": 24 * 3600}
+
+const (
+	tvlMaxRange  = 365 * 24 * 3600
+	tvlMaxPoints = 1000
+)
+
+// 手续费统计的分组和最长范围
+const (
+	feeGroupMonth = "month"
+	feeGroupDay   = "day"
+	feeMaxDays    = 366
+	feeMaxMonths  = 120
+	feeDateLayout = "2006-01-02"
+)
+
+// 排行榜的方向和数量
+const (
+	leaderboardSideLend     = "lend"
+	leaderboardSideBorrow   = "borrow"
+	leaderboardWindowAll    = "all"
+	leaderboardDefaultLimit = 20
+	leaderboardMaxLimit     = 100
+)
+
+type Stats struct{}
+
+func NewStats() *Stats {
+	return &Stats{}
+}
+
+func (v *Stats) Tvl(c *gin.Context, req *request.Tvl) int {
+	if c.ShouldBindQuery(req) != nil {
+		return statecode.ParameterErr
+	}
+
+	// 为空或 all 时返回所有已配置的链
+	if req.Chains != "" && !strings.EqualFold(req.Chains, "all") {
+		var errCode int
+		if req.ChainIds, _, errCode = parseChainIds(req.Chains); errCode != statecode.CommonSuccess {
+			return errCode
+		}
+	}
+	if req.Interval == "" {
+		req.Interval = "1d"
+	}
+	if req.Range == "" {
+		req.Range = "30d"
+	}
+	interval, ok := tvlIntervals[req.Interval]
+	if !ok {
+		return statecode.ParameterErr
+	}
+	rangeSeconds := rangeDuration(req.Range)
+	if rangeSeconds < interval || rangeSeconds > tvlMaxRange || rangeSeconds/interval > tvlMaxPoints {
+		return statecode.ParameterErr
+	}
+	req.IntervalSeconds = interval
+	req.RangeSeconds = rangeSeconds
+
+	return statecode.CommonSuccess
+}
+
+func (v *Stats) Fees(c *gin.Context, req *request.Fees) int {
+	if c.ShouldBindQuery(req) != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.Chains != "" {
+		var errCode int
+		if req.ChainIds, req.Multi, errCode = parseChainIds(req.Chains); errCode != statecode.CommonSuccess {
+			return errCode
+		}
+	}
+	if req.Group == "" {
+		req.Group = feeGroupMonth
+	}
+	if req.Group != feeGroupMonth && req.Group != feeGroupDay {
+		return statecode.ParameterErr
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.To != "" {
+		t, err := time.Parse(feeDateLayout, req.To)
+		if err != nil {
+			return statecode.ParameterErr
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -29)
+	if req.Group == feeGroupMonth {
+		from = time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -11, 0)
+	}
+	if req.From != "" {
+		t, err := time.Parse(feeDateLayout, req.From)
+		if err != nil {
+			return statecode.ParameterErr
+		}
+		from = t
+	}
+	if from.After(to) {
+		return statecode.ParameterErr
+	}
+	if req.Group == feeGroupDay && to.Sub(from) >= feeMaxDays*24*time.Hour {
+		return statecode.ParameterErr
+	}
+	if req.Group == feeGroupMonth && from.AddDate(0, feeMaxMonths, 0).Before(to) {
+		return statecode.ParameterErr
+	}
+	req.From = from.Format(feeDateLayout)
+	req.To = to.Format(feeDateLayout)
+	req.FromTs = from.Unix()
+	req.ToTs = to.AddDate(0, 0, 1).Unix()
+
+	return statecode.CommonSuccess
+}
+
+func (v *Stats) Leaderboard(c *gin.Context, req *request.Leaderboard) int {
+	if c.ShouldBindQuery(req) != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if req.PoolId < 0 {
+		return statecode.ParameterErr
+	}
+	if req.Side == "" {
+		req.Side = leaderboardSideLend
+	}
+	if req.Side != leaderboardSideLend && req.Side != leaderboardSideBorrow {
+		return statecode.ParameterErr
+	}
+	if req.Limit == 0 {
+		req.Limit = leaderboardDefaultLimit
+	}
+	if req.Limit < 0 || req.Limit > leaderboardMaxLimit {
+		return statecode.ParameterErr
+	}
+	if req.Window == "" {
+		req.Window = leaderboardWindowAll
+	}
+	if req.Window != leaderboardWindowAll {
+		window := rangeDuration(req.Window)
+		if window == 0 || window > tvlMaxRange {
+			return statecode.ParameterErr
+		}
+		req.Since = time.Now().Unix() - window
+	}
+
+	return statecode.CommonSuccess
+}
+
+// rangeDuration 解析 24h、90d 形式的时间范围，返回秒数，格式错误时返回 0
+func rangeDuration(s string) int64 {
+	units := map[string]int64{"h": 3600, "d": 24 * 3600}
+	if len(s) < 2 {
+		return 0
+	}
+	unit, ok := units[strings.ToLower(s[len(s)-1:])]
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseInt(s[:len(s)-1], 10, 64)
+	if err != nil || n <= 0 || n > tvlMaxRange {
+		return 0
+	}
+	return n * unit
+}
@@ -0,0 +1,122 @@
+package validate
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"io"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models"
+	"pledge-backend/api/models/request"
+	"strings"
+)
+
+type TokenAdmin struct{}
+
+func NewTokenAdmin() *TokenAdmin {
+	return &TokenAdmin{}
+}
+
+func (v *TokenAdmin) Create(c *gin.Context, req *request.CreateToken) int {
+
+	errCode := bindJSON(c, req)
+	if errCode != statecode.CommonSuccess {
+		return errCode
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if !checksumAddress(req.Token) {
+		return statecode.TokenAddressErr
+	}
+	if req.Decimals < 0 || req.Decimals > 36 {
+		return statecode.TokenDecimalsErr
+	}
+	if !validPriceSource(req.PriceSource) || (req.PriceSource == models.PriceSourceCoingecko && req.CoingeckoId == "") {
+		return statecode.TokenPriceSourceErr
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *TokenAdmin) Update(c *gin.Context, req *request.UpdateToken) int {
+
+	errCode := bindJSON(c, req)
+	if errCode != statecode.CommonSuccess {
+		return errCode
+	}
+
+	if req.Decimals != nil && (*req.Decimals < 0 || *req.Decimals > 36) {
+		return statecode.TokenDecimalsErr
+	}
+	if req.PriceSource != nil && !validPriceSource(*req.PriceSource) {
+		return statecode.TokenPriceSourceErr
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *TokenAdmin) Delete(c *gin.Context, req *request.DeleteToken) int {
+	return bindJSON(c, req)
+}
+
+func (v *TokenAdmin) UploadLogo(c *gin.Context, req *request.UploadTokenLogo) int {
+
+	// chainId 可以放在 query 或 multipart 字段中，binding.Form 会同时读取两者
+	if c.ShouldBindWith(req, binding.Form) != nil {
+		return statecode.ChainIdEmpty
+	}
+	if c.ShouldBindUri(req) != nil {
+		return statecode.ParameterEmptyErr
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if !checksumAddress(req.Address) {
+		return statecode.TokenAddressErr
+	}
+
+	return statecode.CommonSuccess
+}
+
+func bindJSON(c *gin.Context, req interface{}) int {
+	err := c.ShouldBindJSON(req)
+	if err == io.EOF {
+		return statecode.ParameterEmptyErr
+	} else if err != nil {
+		errs, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return statecode.CommonErrServerErr
+		}
+		for _, e := range errs {
+			if e.Tag() == "required" {
+				return statecode.ParameterEmptyErr
+			}
+		}
+		return statecode.CommonErrServerErr
+	}
+	return statecode.CommonSuccess
+}
+
+// checksumAddress 地址格式正确，且大小写混合时必须符合 EIP-55 校验
+func checksumAddress(address string) bool {
+	if !common.IsHexAddress(address) || !strings.HasPrefix(address, "0x") {
+		return false
+	}
+	hex := address[2:]
+	if hex == strings.ToLower(hex) || hex == strings.ToUpper(hex) {
+		return true
+	}
+	return common.HexToAddress(address).Hex() == address
+}
+
+func validPriceSource(source string) bool {
+	switch source {
+	case models.PriceSourceAuto, models.PriceSourceOracle, models.PriceSourceExchange, models.PriceSourceCoingecko:
+		return true
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package validate
 
 import (
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"io"
@@ -35,3 +36,59 @@ func (v *TokenList) TokenList(c *gin.Context, req *request.TokenList) int {
 
 	return statecode.CommonSuccess
 }
+
+// ChainTokenList /token 的参数，chainId 可以是逗号分隔的列表或 all
+func (v *TokenList) ChainTokenList(c *gin.Context, req *request.ChainTokenList) int {
+
+	err := c.ShouldBind(req)
+	if err == io.EOF {
+		return statecode.ParameterEmptyErr
+	} else if err != nil {
+		errs := err.(validator.ValidationErrors)
+		for _, e := range errs {
+			if e.Field() == "Chains" && e.Tag() == "required" {
+				return statecode.ChainIdEmpty
+			}
+		}
+		return statecode.CommonErrServerErr
+	}
+
+	var errCode int
+	if req.ChainIds, req.Multi, errCode = parseChainIds(req.Chains); errCode != statecode.CommonSuccess {
+		return errCode
+	}
+	req.Fields, errCode = parseFields(c)
+	return errCode
+}
+
+func (v *TokenList) Changelog(c *gin.Context, req *request.TokenListChangelog) int {
+
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		return statecode.ChainIdEmpty
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *TokenList) LogoUrl(c *gin.Context, req *request.TokenLogoUrl) int {
+	if c.ShouldBindQuery(req) != nil {
+		return statecode.ParameterEmptyErr
+	}
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if !checksumAddress(req.Address) {
+		return statecode.TokenAddressErr
+	}
+	req.Address = common.HexToAddress(req.Address).Hex()
+
+	return statecode.CommonSuccess
+}
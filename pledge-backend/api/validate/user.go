@@ -6,6 +6,8 @@ import (
 	"io"
 	"pledge-backend/api/common/statecode"
 	"pledge-backend/api/models/request"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 type User struct{}
@@ -34,3 +36,28 @@ func (v *User) Login(c *gin.Context, req *request.Login) int {
 
 	return statecode.CommonSuccess
 }
+
+func (v *User) Claimable(c *gin.Context, req *request.UserClaimable) int {
+	if c.ShouldBindQuery(req) != nil {
+		return statecode.ChainIdEmpty
+	}
+	if c.ShouldBindUri(req) != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.ChainId != 97 && req.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	if !common.IsHexAddress(req.Address) {
+		return statecode.ParameterErr
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *User) WalletNonce(c *gin.Context, req *request.WalletNonce) int {
+	if c.ShouldBindUri(req) != nil || !common.IsHexAddress(req.Address) {
+		return statecode.ParameterErr
+	}
+	return statecode.CommonSuccess
+}
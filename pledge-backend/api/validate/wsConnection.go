@@ -0,0 +1,201 @@
+package validate
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"io"
+	"pledge-backend/api/common/statecode"
+	"pledge-backend/api/models/request"
+	"pledge-backend/api/models/ws"
+	"strconv"
+	"strings"
+)
+
+type WsConnection struct{}
+
+func NewWsConnection() *WsConnection {
+	return &WsConnection{}
+}
+
+func (v *WsConnection) CloseWsConnection(c *gin.Context, req *request.CloseWsConnection) int {
+
+	err := c.ShouldBindJSON(req)
+	if err == io.EOF {
+		return statecode.ParameterEmptyErr
+	} else if err != nil {
+		errs, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return statecode.CommonErrServerErr
+		}
+		for _, e := range errs {
+			if e.Field() == "Id" && e.Tag() == "required" {
+				return statecode.ParameterEmptyErr
+			}
+		}
+		return statecode.CommonErrServerErr
+	}
+
+	return statecode.CommonSuccess
+}
+
+func (v *WsConnection) WsPrice(c *gin.Context, req *request.WsPrice) int {
+
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		return statecode.ParameterErr
+	}
+
+	if req.Encoding == "" {
+		req.Encoding = ws.EncodingJson
+	}
+	if !ws.ValidEncoding(req.Encoding) {
+		return statecode.ParameterErr
+	}
+
+	// 浏览器无法为 WebSocket 设置请求头，凭证可以放在 query 中，或者连接后发送 auth 消息
+	if req.AuthCode == "" {
+		req.AuthCode = c.GetHeader("authCode")
+	}
+	if req.ApiKey == "" {
+		req.ApiKey = c.GetHeader("X-Api-Key")
+	}
+	if req.AuthCode != "" || req.ApiKey != "" {
+		if !ws.Authenticate(req.AuthCode, req.ApiKey) {
+			return statecode.TokenErr
+		}
+		req.Authorized = true
+	}
+	if req.Signature != "" {
+		wallet, ok := ws.VerifyWallet(req.Address, req.Signature)
+		if !ok {
+			return statecode.TokenErr
+		}
+		req.Wallet = wallet
+	}
+
+	if req.LastSeq < 0 {
+		return statecode.ParameterErr
+	}
+	if req.Topic == "" {
+		req.Topic = ws.TopicPrice
+	}
+	topics := strings.Split(req.Topic, ",")
+	for i, topic := range topics {
+		topics[i] = ws.NormalizeTopic(topic)
+		if !ws.ValidTopic(topics[i]) {
+			return statecode.WsTopicErr
+		}
+		// claimable 只能订阅签名证明过的地址，其他私有主题需要 authCode / API key
+		if _, address, ok := ws.ParseClaimableTopic(topics[i]); ok {
+			if address != req.Wallet {
+				return statecode.TokenErr
+			}
+		} else if ws.PrivateTopic(topics[i]) && !req.Authorized {
+			return statecode.TokenErr
+		}
+	}
+	req.Topic = strings.Join(topics, ",")
+
+	return statecode.CommonSuccess
+}
+
+func (v *WsConnection) PriceSse(c *gin.Context, req *request.PriceSse) int {
+
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		return statecode.ParameterEmptyErr
+	}
+
+	if lastEventId := c.GetHeader("Last-Event-ID"); lastEventId != "" {
+		req.LastEventId, err = strconv.ParseInt(lastEventId, 10, 64)
+		if err != nil {
+			return statecode.ParameterEmptyErr
+		}
+	}
+
+	if req.Topic == "" {
+		req.Topic = ws.TopicPrice
+	}
+	// SSE 只支持公开主题
+	topics := strings.Split(req.Topic, ",")
+	for i, topic := range topics {
+		topics[i] = ws.NormalizeTopic(topic)
+		if !ws.ValidTopic(topics[i]) || ws.PrivateTopic(topics[i]) {
+			return statecode.WsTopicErr
+		}
+	}
+	req.Topic = strings.Join(topics, ",")
+
+	return statecode.CommonSuccess
+}
+
+// WsPoolBaseInfo getPoolBaseInfo 的参数，只支持单条链
+func (v *WsConnection) WsPoolBaseInfo(params json.RawMessage, req *request.PoolBaseInfo) int {
+	p := request.WsRpcParams{}
+	if errCode := v.wsRpcParams(params, &p); errCode != statecode.CommonSuccess {
+		return errCode
+	}
+	if !validArchived(p.Archived) {
+		return statecode.ParameterErr
+	}
+	req.ChainIds, req.Archived, req.Fields = []int{p.ChainId}, p.Archived, p.Fields
+	return statecode.CommonSuccess
+}
+
+// WsPoolDataInfo getPoolDataInfo 的参数，只支持单条链
+func (v *WsConnection) WsPoolDataInfo(params json.RawMessage, req *request.PoolDataInfo) int {
+	p := request.WsRpcParams{}
+	if errCode := v.wsRpcParams(params, &p); errCode != statecode.CommonSuccess {
+		return errCode
+	}
+	req.ChainIds, req.Fields = []int{p.ChainId}, p.Fields
+	return statecode.CommonSuccess
+}
+
+// WsPoolDetail getPoolDetail 的参数
+func (v *WsConnection) WsPoolDetail(params json.RawMessage, req *request.PoolDetail) int {
+	p := request.WsRpcParams{}
+	if errCode := v.wsRpcParams(params, &p); errCode != statecode.CommonSuccess {
+		return errCode
+	}
+	if p.PoolId <= 0 {
+		return statecode.ParameterErr
+	}
+	req.ChainId, req.PoolId, req.Fields = p.ChainId, p.PoolId, p.Fields
+	return statecode.CommonSuccess
+}
+
+// WsChanges getChanges 的参数
+func (v *WsConnection) WsChanges(params json.RawMessage, req *request.Changes) int {
+	p := request.WsRpcParams{}
+	if errCode := v.wsRpcParams(params, &p); errCode != statecode.CommonSuccess {
+		return errCode
+	}
+	req.ChainId = p.ChainId
+	if len(p.Since) > 0 && string(p.Since) != "null" {
+		req.Since = strings.Trim(string(p.Since), `"`)
+	}
+	var errCode int
+	if req.SinceTs, errCode = parseSince(req.Since); errCode != statecode.CommonSuccess {
+		return errCode
+	}
+	return statecode.CommonSuccess
+}
+
+// wsRpcParams 解析 params 并检查各方法共用的 chainId 和 fields
+func (v *WsConnection) wsRpcParams(params json.RawMessage, p *request.WsRpcParams) int {
+	if len(params) == 0 || string(params) == "null" {
+		return statecode.ChainIdEmpty
+	}
+	if json.Unmarshal(params, p) != nil {
+		return statecode.ParameterErr
+	}
+	if p.ChainId == 0 {
+		return statecode.ChainIdEmpty
+	}
+	if p.ChainId != 97 && p.ChainId != 56 {
+		return statecode.ChainIdErr
+	}
+	return checkFields(p.Fields)
+}
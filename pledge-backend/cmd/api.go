@@ -0,0 +1,248 @@
+/*
+ * ==================================================================================
+ * api.go - Pledge API 服务 (pledge api)
+ * ==================================================================================
+ *
+ * 【核心功能】
+ * 1. 初始化数据库连接 (MySQL, Redis)
+ * 2. 启动 WebSocket 服务 (用于实时价格推送)
+ * 3. 启动 KuCoin 价格获取协程 (获取 PLGR 交易所价格)
+ * 4. 配置并启动 Gin Web 服务器
+ * 5. [telemetry] enabled 时导出 HTTP、MySQL、Redis 链路追踪
+ * 6. [sentry] enabled 时上报 panic 和严重错误
+ * 7. [admin] tls_port 配置时启动要求客户端证书的管理端监听
+ * 8. [cache] warmup 开启时，开始监听前预热热点读接口的响应缓存
+ *
+ * 【服务架构】
+ * Pledge 后端由两个独立的服务组成 (可分开部署):
+ * - pledge api: HTTP API 服务 (本文件)
+ * - pledge task: 定时任务服务 (schedule 模块)
+ *
+ * 【默认端口】
+ * HTTP API: 由 config.Config().Env.Port 配置 (默认 8081)
+ * WebSocket: ws.StartServer() 内部配置
+ * ==================================================================================
+ */
+
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"pledge-backend/api/middlewares"
+	apiModels "pledge-backend/api/models"
+	"pledge-backend/api/models/kucoin"
+	"pledge-backend/api/models/ws"
+	"pledge-backend/api/routes"
+	"pledge-backend/api/services"
+	"pledge-backend/api/static"
+	"pledge-backend/api/validate"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"pledge-backend/telemetry"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+var apiCmd = &cobra.Command{
+	Use:   "api",
+	Short: "Start the HTTP API service",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runApi()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(apiCmd)
+}
+
+func runApi() error {
+
+	// ============================================================
+	// Step 1: 初始化数据库连接
+	// ============================================================
+
+	// 初始化链路追踪 ([telemetry] enabled = false 时为空操作)，退出前发送缓冲中的 span
+	shutdown, err := telemetry.Init("pledge-api")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdown(context.Background()); err != nil {
+			log.Logger.Sugar().Error("telemetry shutdown err ", err)
+		}
+	}()
+
+	// 初始化 Sentry ([sentry] enabled = false 时为空操作)
+	flush, err := telemetry.InitSentry("pledge-api")
+	if err != nil {
+		return err
+	}
+	defer flush()
+
+	// 初始化 MySQL (持久化存储) 和 Redis (缓存和实时数据)
+	if err = initStorage(); err != nil {
+		return err
+	}
+
+	// 附加翻译，失败时不启动，避免接口返回缺失的文案
+	if err = loadTranslations(); err != nil {
+		return err
+	}
+
+	// WebSocket / SSE 连接数限制
+	ws.Limiter.SetLimit(config.Config().Env.WssMaxConnections, config.Config().Env.WssMaxConnectionsPerIp)
+
+	// 监听配置文件，限流、日志级别等配置修改后无需重启
+	watchConfig()
+	config.OnReload(func(changed []string) {
+		ws.Limiter.SetLimit(config.Config().Env.WssMaxConnections, config.Config().Env.WssMaxConnectionsPerIp)
+	})
+
+	// 创建数据库表 (如果不存在)
+	apiModels.InitTable()
+
+	// ============================================================
+	// Step 2: 初始化验证器
+	// ============================================================
+
+	// 将 go-playground-validator 绑定到 Gin
+	validate.BindingValidator()
+
+	// ============================================================
+	// Step 3: 启动后台协程 (Goroutines)
+	// ============================================================
+
+	// 启动 WebSocket 服务器 (用于实时价格推送等)
+	// claimable 主题的快照依赖 services，在启动前注册
+	ws.RegisterSnapshot(ws.TopicClaimablePrefix, services.ClaimableSnapshot)
+	go ws.StartServer()
+
+	// 从 Redis 同步维护模式状态，变化时通知 WebSocket / SSE 客户端
+	go services.WatchMaintenance()
+
+	// 读取 schedule 写入的池子结算倒计时通知，推送给订阅了 deadline:<chainId> 的 WebSocket / SSE 客户端
+	go services.WatchPoolDeadlines()
+
+	// 推送私有主题: jobs (schedule 的执行记录) 和 claimable:<chainId>:<address> (钱包可提取金额变化)，只处理有订阅的主题
+	go services.WatchJobRuns()
+	go services.WatchClaimable()
+
+	// 读取 schedule 发布的运维事件，推送给订阅了私有主题 ops 的运维面板
+	go services.WatchOpsEvents()
+
+	// 启动 KuCoin 价格获取服务
+	// 该服务定期从 KuCoin 交易所获取 PLGR 价格并存入 Redis
+	// 然后由 tokenPriceService.SavePlgrPrice() 写入链上 Oracle
+	go kucoin.GetExchangePrice()
+
+	// ============================================================
+	// Step 4: 配置并启动 Gin Web 服务器
+	// ============================================================
+
+	// 设置 Gin 为发布模式 (关闭调试日志)
+	gin.SetMode(gin.ReleaseMode)
+
+	// 创建 Gin 实例
+	app := gin.Default()
+	if err = app.SetTrustedProxies(config.Config().Env.TrustedProxies); err != nil {
+		return err
+	}
+
+	// 配置静态文件服务 (代币 Logo 等资源)
+	// /storage 下的文件会被原地覆盖，每次都需要重新验证；可以长期缓存的内容哈希地址见 /assets
+	staticPath := static.GetCurrentAbPathByCaller()
+	app.Group("/storage", middlewares.NoCache()).Static("/", staticPath)
+
+	// 配置 CORS 中间件 (允许跨域请求)
+	app.Use(middlewares.Cors())
+
+	// 链路追踪: 每个请求一个 span，上游带 traceparent 时加入同一个 trace
+	app.Use(otelgin.Middleware("pledge-api"))
+
+	// 请求 ID: 响应头 X-Request-Id、响应的 meta.request_id 和错误日志使用同一个 ID
+	app.Use(middlewares.RequestId())
+
+	// panic 上报到 Sentry 后返回服务器错误
+	app.Use(middlewares.Recovery())
+
+	// 请求体大小和处理时限，慢速或过大的请求返回 413 / 408，不长时间占用 handler
+	app.Use(middlewares.BodyLimit())
+	app.Use(middlewares.Timeout())
+
+	// 管理接口按 [admin] allow_cidrs / require_mtls 限制访问来源
+	app.Use(middlewares.AdminGuard())
+
+	// 按 Accept-Language 选择接口消息的语言，维护模式的缓存按语言区分
+	app.Use(middlewares.Language())
+
+	// 维护模式: 读接口返回缓存并附带维护信息，写接口和管理接口返回 503
+	app.Use(middlewares.Maintenance())
+
+	// 注册所有 API 路由
+	routes.InitRoute(app)
+
+	// 开始监听前预热池子列表、代币列表和价格的响应缓存，部署后的第一批请求不会同时查询 MySQL
+	routes.WarmUp(app)
+
+	// [admin] tls_port 配置时同时启动 mTLS 管理端监听，任一监听退出即返回
+	errCh := make(chan error, 2)
+	if config.Config().Admin.MtlsEnabled() {
+		server, err := adminTlsServer(app)
+		if err != nil {
+			return err
+		}
+		go func() {
+			errCh <- server.ListenAndServeTLS(config.Config().Admin.TlsCertFile, config.Config().Admin.TlsKeyFile)
+		}()
+		log.Logger.Sugar().Info("admin mTLS listener on :", config.Config().Admin.TlsPort)
+	}
+
+	// 启动 HTTP 服务器
+	// 监听端口由 config.Config().Env.Port 配置
+	server := &http.Server{Addr: ":" + config.Config().Env.Port, Handler: app}
+	setReadTimeouts(server)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	return <-errCh
+}
+
+// adminTlsServer 管理端 HTTPS 监听，要求客户端证书由 [admin] client_ca_file 签发
+// 与 HTTP 监听共用同一套路由，require_mtls 时管理接口只能从这里访问
+func adminTlsServer(handler http.Handler) (*http.Server, error) {
+	caPem, err := ioutil.ReadFile(config.Config().Admin.ClientCaFile)
+	if err != nil {
+		return nil, err
+	}
+	clientCas := x509.NewCertPool()
+	if !clientCas.AppendCertsFromPEM(caPem) {
+		return nil, errors.New("no certificate found in [admin] client_ca_file " + config.Config().Admin.ClientCaFile)
+	}
+	server := &http.Server{
+		Addr:    ":" + config.Config().Admin.TlsPort,
+		Handler: handler,
+		TLSConfig: &tls.Config{
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			ClientCAs:  clientCas,
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+	setReadTimeouts(server)
+	return server, nil
+}
+
+// setReadTimeouts [env] read_header_timeout / read_timeout，慢速发送请求头或请求体的连接到期后被关闭
+// 只限制读取请求，SSE 等长时间写入的响应不受影响；WebSocket 升级后由 gorilla/websocket 清除连接的截止时间
+func setReadTimeouts(server *http.Server) {
+	server.ReadHeaderTimeout = time.Duration(config.Config().Env.ReadHeaderTimeout) * time.Second
+	server.ReadTimeout = time.Duration(config.Config().Env.ReadTimeout) * time.Second
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"pledge-backend/config"
+	"pledge-backend/schedule/models"
+	"pledge-backend/schedule/services"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var (
+	backfillChain     string
+	backfillToken     string
+	backfillFromBlock uint64
+)
+
+var backfillPricesCmd = &cobra.Command{
+	Use:   "backfill-prices",
+	Short: "Backfill token_price_history from historical oracle prices",
+	Long: "Read the BscPledgeOracle price of a token every [backfill] step_blocks blocks from --from-block up to its first " +
+		"recorded price (or the latest block) and write the price changes to token_price_history. Requires an archive node. " +
+		"Progress is checkpointed in price_backfills: an unfinished backfill resumes from its checkpoint, Ctrl+C stops after " +
+		"saving it. The same backfill can be submitted through POST /admin/price/backfill and run by the task service.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if backfillChain != config.Config().TestNet.ChainId && backfillChain != config.Config().MainNet.ChainId {
+			return errors.New("unknown chain " + backfillChain)
+		}
+		if !common.IsHexAddress(backfillToken) {
+			return errors.New("invalid token address " + backfillToken)
+		}
+		if backfillFromBlock == 0 {
+			return errors.New("--from-block is required")
+		}
+
+		if err := initStorage(); err != nil {
+			return err
+		}
+		backfill := models.NewPriceBackfill()
+		err := backfill.Submit(backfillChain, common.HexToAddress(backfillToken).Hex(), backfillFromBlock)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if err = services.NewPriceBackfill().Run(ctx, backfill); err != nil {
+			return err
+		}
+		fmt.Printf("%s %s: status %s, next block %d of %d, %d prices inserted\n",
+			backfill.ChainId, backfill.Token, backfill.Status, backfill.NextBlock, backfill.ToBlock, backfill.Inserted)
+		return nil
+	},
+}
+
+func init() {
+	backfillPricesCmd.Flags().StringVar(&backfillChain, "chain", "56", "chain id (97=testnet, 56=mainnet)")
+	backfillPricesCmd.Flags().StringVar(&backfillToken, "token", "", "token address")
+	backfillPricesCmd.Flags().Uint64Var(&backfillFromBlock, "from-block", 0, "first block to read, e.g. the oracle deployment block")
+	rootCmd.AddCommand(backfillPricesCmd)
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"errors"
+	"pledge-backend/config"
+	"pledge-backend/db/seed"
+	"pledge-backend/schedule/services"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+var devnetCmd = &cobra.Command{
+	Use:   "devnet",
+	Short: "Deploy the oracle and pool contracts to a local anvil chain",
+	Long: "Deploy BscPledgeOracle, multiSignature and PledgePool on the [devnet] chain (start it with `anvil --chain-id 97`), " +
+		"set oracle prices for the tokens in the seed fixtures and create the fixture pools on chain. " +
+		"With [devnet] enabled = true the task service syncs from this chain instead of BSC testnet.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !config.Config().Devnet.Enabled {
+			return errors.New("[devnet] enabled is false")
+		}
+
+		fixtures, err := seed.Dir(seedDir)
+		if err != nil {
+			return err
+		}
+		tokens, err := seed.Tokens(fixtures)
+		if err != nil {
+			return err
+		}
+		prices := map[string]int64{}
+		for _, t := range tokens {
+			if t.ChainId != config.Config().Devnet.ChainId {
+				continue
+			}
+			price, err := strconv.ParseInt(t.Price, 10, 64)
+			if err != nil {
+				return errors.New("invalid price of " + t.Token + " in fixture")
+			}
+			prices[t.Token] = price
+		}
+
+		pools, err := seed.Pools(fixtures)
+		if err != nil {
+			return err
+		}
+		return services.NewDevnet().Setup(prices, pools)
+	},
+}
+
+func init() {
+	devnetCmd.Flags().StringVar(&seedDir, "dir", "", "fixture directory providing token prices and pools, built-in fixtures when empty")
+	rootCmd.AddCommand(devnetCmd)
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	apiModels "pledge-backend/api/models"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	scheduleModels "pledge-backend/schedule/models"
+
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Create or update the api and task tables",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := db.WaitDependencies(db.Dependency{Name: "mysql", Connect: db.InitMysql}); err != nil {
+			return err
+		}
+		apiModels.InitTable()
+		scheduleModels.InitTable()
+		log.Logger.Info("migrate done")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/schedule/services"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	restoreList   bool
+	restoreBackup string
+	restoreTables []string
+	restoreYes    bool
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "List database backups or restore tables from one",
+	Long: "Backups are written by the BackupDatabase task to the [export] storage under [backup] prefix. " +
+		"--list prints the complete backups, newest first. --backup restores every table in the backup, or only those " +
+		"given with --table: each table is emptied and reloaded in its own transaction, so a failed table is left unchanged. " +
+		"Stop the api and task services first, otherwise they may write to the tables while they are restored.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if config.Config().Backup.Prefix == "" {
+			return errors.New("[backup] prefix is not set")
+		}
+		backup := services.NewBackup()
+		if restoreList {
+			manifests, err := backup.List()
+			if err != nil {
+				return err
+			}
+			for _, manifest := range manifests {
+				fmt.Printf("%s  %s  %s\n", manifest.Id, time.Unix(manifest.CreatedAt, 0).Format("2006-01-02 15:04:05"), restoreRows(manifest.Tables))
+			}
+			return nil
+		}
+		if restoreBackup == "" {
+			return errors.New("--backup or --list is required")
+		}
+
+		manifest, err := backup.Manifest(restoreBackup)
+		if err != nil {
+			return errors.New("backup " + restoreBackup + " not found or incomplete: " + err.Error())
+		}
+		tables := manifest.Tables
+		if len(restoreTables) > 0 {
+			tables = map[string]int64{}
+			for _, table := range restoreTables {
+				rows, ok := manifest.Tables[table]
+				if !ok {
+					return errors.New("table " + table + " is not in backup " + restoreBackup)
+				}
+				tables[table] = rows
+			}
+		}
+		if !restoreYes {
+			return errors.New("this replaces all rows of " + restoreRows(tables) + " with backup " + restoreBackup + ", add --yes to continue")
+		}
+
+		if err = db.WaitDependencies(db.Dependency{Name: "mysql", Connect: db.InitMysql}); err != nil {
+			return err
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		restored, err := backup.Restore(ctx, restoreBackup, restoreTables)
+		if len(restored) > 0 {
+			fmt.Println("restored " + restoreRows(restored))
+		}
+		return err
+	},
+}
+
+// restoreRows 表名和行数，按表名排序
+func restoreRows(tables map[string]int64) string {
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	rows := make([]string, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, fmt.Sprintf("%s (%d rows)", name, tables[name]))
+	}
+	return strings.Join(rows, ", ")
+}
+
+func init() {
+	restoreCmd.Flags().BoolVar(&restoreList, "list", false, "list complete backups, newest first")
+	restoreCmd.Flags().StringVar(&restoreBackup, "backup", "", "backup id to restore, e.g. 20261016T020000Z")
+	restoreCmd.Flags().StringSliceVar(&restoreTables, "table", nil, "restore only these tables (repeatable), default all tables in the backup")
+	restoreCmd.Flags().BoolVar(&restoreYes, "yes", false, "confirm replacing the table contents")
+	rootCmd.AddCommand(restoreCmd)
+}
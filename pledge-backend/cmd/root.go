@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/i18n"
+	"pledge-backend/log"
+	"pledge-backend/telemetry"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "pledge",
+	Short: "Pledge backend: API service, scheduled tasks and operational commands",
+	// 参数错误时只输出错误信息，不输出整段用法
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	// 所有子命令在连接数据库和节点之前先读取并校验配置，一次列出全部问题
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.Load(); err != nil {
+			return err
+		}
+		if err := config.Validate(); err != nil {
+			return err
+		}
+		return log.SetLevel(config.Config().Log.Level)
+	},
+}
+
+// Execute 解析命令行并执行子命令
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// initStorage 各子命令共用的 MySQL、Redis 初始化，extra 为同时等待的其它依赖
+// 依赖未就绪时按 [startup] 重试，超时后返回所有未就绪依赖的错误
+func initStorage(extra ...db.Dependency) error {
+	deps := []db.Dependency{
+		{Name: "mysql", Connect: db.InitMysql},
+		{Name: "redis", Connect: db.InitRedis},
+	}
+	return db.WaitDependencies(append(deps, extra...)...)
+}
+
+// rpcDependencies 已启用链的 RPC 节点，查询到 chainId 即视为就绪
+func rpcDependencies() []db.Dependency {
+	deps := make([]db.Dependency, 0)
+	chains := []struct {
+		enabled      bool
+		chainId, url string
+	}{
+		{config.Config().TestNet.Enabled, config.Config().TestNet.ChainId, config.Config().TestNet.NetUrl},
+		{config.Config().MainNet.Enabled, config.Config().MainNet.ChainId, config.Config().MainNet.NetUrl},
+	}
+	for _, chain := range chains {
+		if !chain.enabled {
+			continue
+		}
+		url := chain.url
+		deps = append(deps, db.Dependency{Name: "rpc " + chain.chainId, Connect: func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			client, err := telemetry.DialEth(ctx, url)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			_, err = client.ChainID(ctx)
+			return err
+		}})
+	}
+	return deps
+}
+
+// loadTranslations 读取 [i18n] catalog_dir 中的翻译文件，增加语言或覆盖内置的接口消息和通知文案
+func loadTranslations() error {
+	return i18n.LoadDir(config.Config().I18n.CatalogPath())
+}
+
+// watchConfig 常驻服务 (api、task) 监听配置文件，热加载支持运行时修改的配置项
+func watchConfig() {
+	config.OnReload(func(changed []string) {
+		// 只在配置文件中的级别变化时应用，不覆盖通过 /admin/log/level 临时修改的级别
+		for _, key := range changed {
+			if key == "log.level" {
+				if err := log.SetLevel(config.Config().Log.Level); err != nil {
+					log.Logger.Error(err.Error())
+				}
+			}
+		}
+	})
+	if err := config.Watch(); err != nil {
+		log.Logger.Sugar().Error("config watch err ", err)
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	apiModels "pledge-backend/api/models"
+	"pledge-backend/db/seed"
+	scheduleModels "pledge-backend/schedule/models"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	seedDir      string
+	seedFixtures []string
+)
+
+var seedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Load token_info, sample pools and an admin user from YAML fixtures",
+	Long: "Load token_info.yaml, pools.yaml and admin.yaml for local development and integration tests. " +
+		"The fixtures in db/seed/fixtures are built into the binary; --dir reads them from a directory instead, " +
+		"and --fixtures limits which ones are loaded (e.g. --fixtures admin). A selected fixture that is missing is an error. " +
+		"Rows are upserted by business key, so the command can be run repeatedly.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fixtures, err := seed.Dir(seedDir)
+		if err != nil {
+			return err
+		}
+		if err = initStorage(); err != nil {
+			return err
+		}
+		apiModels.InitTable()
+		scheduleModels.InitTable()
+		return seed.Load(fixtures, seedFixtures)
+	},
+}
+
+func init() {
+	seedCmd.Flags().StringVar(&seedDir, "dir", "", "fixture directory, built-in fixtures when empty")
+	seedCmd.Flags().StringSliceVar(&seedFixtures, "fixtures", seed.Fixtures, "fixtures to load")
+	rootCmd.AddCommand(seedCmd)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"errors"
+	"pledge-backend/config"
+	"pledge-backend/schedule/common"
+	"pledge-backend/schedule/services"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	setPriceChain  string
+	setPriceDryRun bool
+)
+
+var setPriceCmd = &cobra.Command{
+	Use:   "set-price",
+	Short: "Write the PLGR price to the on-chain oracle",
+	Long: "Write the PLGR price to the BscPledgeOracle contract. Mainnet uses the KuCoin price cached in redis, " +
+		"testnet uses the fixed test price. With --dry-run the transaction is signed and gas estimated but not sent.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if setPriceChain != config.Config().TestNet.ChainId && setPriceChain != config.Config().MainNet.ChainId {
+			return errors.New("unknown chain " + setPriceChain)
+		}
+
+		// 读取 plgr_admin_private_key
+		common.GetEnv()
+		if err := initStorage(); err != nil {
+			return err
+		}
+
+		tokenPrice := services.NewTokenPrice()
+		tokenPrice.DryRun = setPriceDryRun
+		if setPriceChain == config.Config().MainNet.ChainId {
+			tokenPrice.SavePlgrPrice()
+		} else {
+			tokenPrice.SavePlgrPriceTestNet()
+		}
+		return nil
+	},
+}
+
+func init() {
+	setPriceCmd.Flags().StringVar(&setPriceChain, "chain", "97", "chain id (97=testnet, 56=mainnet)")
+	setPriceCmd.Flags().BoolVar(&setPriceDryRun, "dry-run", false, "sign the transaction without sending it")
+	rootCmd.AddCommand(setPriceCmd)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"errors"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/schedule/services"
+
+	"github.com/spf13/cobra"
+)
+
+var syncPoolsChain string
+
+var syncPoolsCmd = &cobra.Command{
+	Use:   "sync-pools",
+	Short: "Sync pool base and data info from chain once",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var contractAddress, network string
+		switch syncPoolsChain {
+		case config.Config().TestNet.ChainId:
+			contractAddress, network = config.Config().TestNet.PledgePoolToken, config.Config().TestNet.NetUrl
+		case config.Config().MainNet.ChainId:
+			contractAddress, network = config.Config().MainNet.PledgePoolToken, config.Config().MainNet.NetUrl
+		default:
+			return errors.New("unknown chain " + syncPoolsChain)
+		}
+
+		if err := initStorage(); err != nil {
+			return err
+		}
+		db.InitMqtt()
+		services.NewPool().UpdatePoolInfo(cmd.Context(), contractAddress, network, syncPoolsChain)
+		return nil
+	},
+}
+
+func init() {
+	syncPoolsCmd.Flags().StringVar(&syncPoolsChain, "chain", "97", "chain id (97=testnet, 56=mainnet)")
+	rootCmd.AddCommand(syncPoolsCmd)
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/pprof"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	scheduleModels "pledge-backend/schedule/models"
+	"pledge-backend/schedule/tasks"
+	"pledge-backend/telemetry"
+
+	"github.com/spf13/cobra"
+)
+
+var taskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Start the scheduled task service",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// init telemetry, noop when [telemetry] enabled = false
+		shutdown, err := telemetry.Init("pledge-task")
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = shutdown(context.Background())
+		}()
+
+		// init sentry, noop when [sentry] enabled = false
+		flush, err := telemetry.InitSentry("pledge-task")
+		if err != nil {
+			return err
+		}
+		defer flush()
+
+		// 启动时等待 MySQL、Redis 和已启用链的 RPC 节点就绪
+		if err = initStorage(rpcDependencies()...); err != nil {
+			return err
+		}
+		if err = loadTranslations(); err != nil {
+			return err
+		}
+		watchConfig()
+
+		// pprof on loopback for diagnosing blocked sync loops, disabled when [debug] task_pprof_addr is empty
+		if addr := config.Config().Debug.TaskPprofAddr; addr != "" {
+			go servePprof(addr)
+		}
+
+		// init mqtt
+		db.InitMqtt()
+
+		// create table
+		scheduleModels.InitTable()
+
+		// pool task
+		tasks.Task()
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(taskCmd)
+}
+
+// servePprof serves net/http/pprof on addr, validated to be a loopback address
+func servePprof(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	log.Logger.Sugar().Info("pprof listening on ", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Logger.Sugar().Error("pprof listen err ", err)
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"net"
+	"strings"
+)
+
+// IpAllowed 客户端 IP 是否在 [admin] allow_cidrs 中，allow_cidrs 为空时不限制
+// 单个 IP (不带掩码) 视为 /32 或 /128
+func (c AdminConfig) IpAllowed(ip string) bool {
+	if len(c.AllowCidrs) == 0 {
+		return true
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, cidr := range c.AllowCidrs {
+		network, err := parseCidr(cidr)
+		if err == nil && network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// MtlsEnabled 是否启动需要客户端证书的管理端监听
+func (c AdminConfig) MtlsEnabled() bool {
+	return c.TlsPort != ""
+}
+
+func parseCidr(cidr string) (*net.IPNet, error) {
+	if !strings.Contains(cidr, "/") {
+		if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+			cidr += "/32"
+		} else {
+			cidr += "/128"
+		}
+	}
+	_, network, err := net.ParseCIDR(cidr)
+	return network, err
+}
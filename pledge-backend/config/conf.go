@@ -1,28 +1,373 @@
 package config
 
-var Config *Conf
+import "sync/atomic"
+
+// current 当前配置 (*Conf)，热加载时整体替换为新的 *Conf，已发布的 *Conf 不再修改
+var current atomic.Value
+
+// Config 当前配置，与热加载的替换之间没有数据竞争
+// 需要多个配置项保持一致时，读取一次后使用同一个 *Conf；Load 之前调用会 panic
+func Config() *Conf {
+	return current.Load().(*Conf)
+}
 
 type Conf struct {
 	Mysql        MysqlConfig
 	Redis        RedisConfig
+	Startup      StartupConfig
 	TestNet      TestNetConfig
 	MainNet      MainNetConfig
 	Token        TokenConfig
 	Email        EmailConfig
-	DefaultAdmin DefaultAdminConfig
 	Threshold    ThresholdConfig
 	Jwt          JwtConfig
 	Env          EnvConfig
+	Cors         CorsConfig
+	Admin        AdminConfig
+	Maintenance  MaintenanceConfig
+	Cache        CacheConfig
+	Exchange     ExchangeConfig
+	Oracle       OracleConfig
+	Chainlink    ChainlinkConfig
+	Coingecko    CoingeckoConfig
+	Anomaly      AnomalyConfig
+	Search       SearchConfig
+	Report       ReportConfig
+	Indexer      IndexerConfig
+	Backfill     BackfillConfig
+	Graphql      GraphqlConfig
+	Mqtt         MqttConfig
+	Export       ExportConfig
+	Backup       BackupConfig
+	Integrity    IntegrityConfig
+	Devnet       DevnetConfig
+	Schedule     ScheduleConfig
+	Jobs         map[string]JobConfig
+	Log          LogConfig
+	Telemetry    TelemetryConfig
+	Sentry       SentryConfig
+	Debug        DebugConfig
+	Alert        AlertConfig
+	Gas          GasConfig
+	Keeper       KeeperConfig
+	Deadline     DeadlineConfig
+	Subscription SubscriptionConfig
+	Referral     ReferralConfig
+	I18n         I18nConfig
+	ChainHealth  ChainHealthConfig `toml:"chain_health"`
+	Cluster      ClusterConfig
 }
 
 type EnvConfig struct {
-	Port               string `toml:"port"`
-	Version            string `toml:"version"`
-	Protocol           string `toml:"protocol"`
-	DomainName         string `toml:"domain_name"`
-	TaskDuration       int64  `toml:"task_duration"`
-	WssTimeoutDuration int64  `toml:"wss_timeout_duration"`
-	TaskExtendDuration int64  `toml:"task_extend_duration"`
+	Port                   string   `toml:"port"`
+	Version                string   `toml:"version"`
+	Protocol               string   `toml:"protocol"`
+	DomainName             string   `toml:"domain_name"`
+	TaskDuration           int64    `toml:"task_duration"`
+	WssTimeoutDuration     int64    `toml:"wss_timeout_duration"`
+	WssPingInterval        int64    `toml:"wss_ping_interval"`          // 服务端发送 ping 控制帧的间隔, s
+	WssWriteTimeout        int64    `toml:"wss_write_timeout"`          // 单次写入超时, s
+	WssBroadcastInterval   int64    `toml:"wss_broadcast_interval"`     // 价格广播最小间隔, ms, 必须大于 0
+	WssMaxConnections      int      `toml:"wss_max_connections"`        // 最大并发连接数（WS+SSE），0 不限制
+	WssMaxConnectionsPerIp int      `toml:"wss_max_connections_per_ip"` // 单 IP 最大并发连接数，0 不限制
+	WssApiKeys             string   `toml:"wss_api_keys"`               // 可订阅私有主题的 API key，逗号分隔，从密钥服务读取
+	WssReplaySize          int      `toml:"wss_replay_size"`            // Redis 重放缓冲保留的最近广播条数，0 关闭
+	TaskExtendDuration     int64    `toml:"task_extend_duration"`
+	RequestTimeout         int64    `toml:"request_timeout"`     // 单个 HTTP 请求的处理时限, s, 0 不限制，超时返回 408
+	ReadHeaderTimeout      int64    `toml:"read_header_timeout"` // 读取请求头的时限, s, 0 不限制
+	ReadTimeout            int64    `toml:"read_timeout"`        // 读取整个请求 (含请求体) 的时限, s, 0 不限制
+	MaxBodySize            int64    `toml:"max_body_size"`       // 请求体最大字节数, 0 不限制，超过返回 413
+	MaxUploadSize          int64    `toml:"max_upload_size"`     // multipart 上传请求体最大字节数, 0 不限制
+	TrustedProxies         []string `toml:"trusted_proxies"`     // 可信代理 CIDR，只采信来自这些地址的 X-Forwarded-For / X-Real-IP
+	StrictStatus           bool     `toml:"strict_status"`       // true 按错误码返回对应的 HTTP 状态码，false 都返回 200、只通过 code 判断
+}
+
+type CorsConfig struct {
+	AllowOrigins     []string `toml:"allow_origins"`     // 允许的跨域来源, "*" 所有来源, 支持 https://*.example.com 子域名通配
+	AllowMethods     []string `toml:"allow_methods"`     // Access-Control-Allow-Methods
+	AllowHeaders     []string `toml:"allow_headers"`     // Access-Control-Allow-Headers
+	ExposeHeaders    []string `toml:"expose_headers"`    // Access-Control-Expose-Headers
+	AllowCredentials bool     `toml:"allow_credentials"` // 是否允许携带 Cookie 等凭证, 不能与 "*" 同时使用
+	MaxAge           int64    `toml:"max_age"`           // 预检结果缓存时间, s, 0 不缓存
+}
+
+type AdminConfig struct {
+	AllowCidrs   []string `toml:"allow_cidrs"`    // 允许访问管理接口的客户端 IP / CIDR, 为空不限制
+	TlsPort      string   `toml:"tls_port"`       // 管理端 mTLS 监听端口, 为空不启动
+	TlsCertFile  string   `toml:"tls_cert_file"`  // 服务端证书
+	TlsKeyFile   string   `toml:"tls_key_file"`   // 服务端私钥
+	ClientCaFile string   `toml:"client_ca_file"` // 校验客户端证书的 CA
+	RequireMtls  bool     `toml:"require_mtls"`   // 管理接口只接受 mTLS 监听上通过证书校验的请求
+}
+
+type MaintenanceConfig struct {
+	PollInterval int64 `toml:"poll_interval"`  // 从 Redis 同步维护状态的间隔, s
+	CacheTtl     int64 `toml:"cache_ttl"`      // 读接口响应缓存时间, s, 0 不缓存, 维护期间读接口没有缓存时实时查询
+	CacheMaxSize int64 `toml:"cache_max_size"` // 单个响应最大缓存字节数
+}
+
+// CacheConfig 热点读接口 (池子列表、代币列表、价格) 的响应缓存
+type CacheConfig struct {
+	Ttl           int64 `toml:"ttl"`            // 成功响应在 Redis 中的缓存时间, s, 0 不缓存
+	MaxSize       int64 `toml:"max_size"`       // 单个响应最大缓存字节数
+	MaxAge        int64 `toml:"max_age"`        // 响应头 Cache-Control 的 max-age, s, 浏览器和 CDN 在此期间不重新请求, 0 每次都用 ETag 重新验证
+	Warmup        bool  `toml:"warmup"`         // 启动时先为 enabled 的链预热缓存再开始监听
+	WarmupTimeout int64 `toml:"warmup_timeout"` // 预热的最长时间, s, 超时后不再等待，直接开始监听
+}
+
+type ExchangeConfig struct {
+	Symbols       []string          `toml:"symbols"`        // KuCoin 订阅的交易对
+	Tokens        map[string]string `toml:"tokens"`         // 使用交易所价格的代币, key: 代币地址(小写), value: 交易对，或用 * 连接的换算路径 (见 TokenRoute)
+	AverageWindow int64             `toml:"average_window"` // 喂价均价窗口, s, 0 使用最新成交价
+	AverageMode   string            `toml:"average_mode"`   // 均价算法: twap / vwap
+	DepthSymbols  []string          `toml:"depth_symbols"`  // 订阅 50 档深度的交易对，用于估算滑点，为空不订阅
+
+	TradeRetentionMonths int `toml:"trade_retention_months"` // 成交记录按月分表 exchange_trades_YYYYMM 保留的月数 (含本月)，0 不保存
+}
+
+type OracleConfig struct {
+	StaleMinutes     int64    `toml:"stale_minutes"`     // 交易所价格超过该时间未更新则拒绝喂价, min
+	MaxFailures      int      `toml:"max_failures"`      // 连续 SetPrice 失败次数达到该值则熔断
+	CooldownMinutes  int64    `toml:"cooldown_minutes"`  // 失败熔断后的冷却时间，冷却后允许一次试探写入, min
+	FreshnessMinutes int64    `toml:"freshness_minutes"` // 主网 Oracle 中的 PLGR 价格超过该时间未变化则告警, min, 0 不检测
+	MaxDivergence    float64  `toml:"max_divergence"`    // 主网 Oracle 中的 PLGR 价格相对交易所价格的最大偏离比例, 0 不检测
+	MinChangeBps     int64    `toml:"min_change_bps"`    // 待写入价格与链上价格相差不足该基点数时跳过 SetPrice, 0 每次都写入
+	MaxSkipMinutes   int64    `toml:"max_skip_minutes"`  // 距上次写链成功超过该时间时不再跳过, min, 0 不限制
+	Feeds            []string `toml:"feeds"`             // 除 PLGR 外写入主网 Oracle 的交易所定价代币地址，需配置在 [exchange.tokens] 中
+	BatchMode        string   `toml:"batch_mode"`        // 多个代币需要写入时: set_prices 一笔 setPrices 交易 / single 每个代币一笔 setPrice 交易
+	SignerKey        string   `toml:"-"`                 // 喂价签名私钥，只从密钥服务读取 (plgr_admin_private_key)
+}
+
+type ChainlinkConfig struct {
+	Feeds map[string]string `toml:"feeds"` // BSC 主网 Chainlink 喂价合约, key: 代币地址(小写), value: aggregator 地址
+}
+
+type CoingeckoConfig struct {
+	ApiUrl       string `toml:"api_url"`
+	ApiKey       string `toml:"api_key"`
+	ApiKeyHeader string `toml:"api_key_header"` // x-cg-demo-api-key / x-cg-pro-api-key
+	MinInterval  int64  `toml:"min_interval"`   // 两次请求的最小间隔, ms
+	CacheSeconds int    `toml:"cache_seconds"`  // 价格缓存时间, s
+}
+
+type AnomalyConfig struct {
+	HistorySize        int     `toml:"history_size"`         // 每个代币保留的最近价格条数
+	MinSamples         int     `toml:"min_samples"`          // 历史价格少于该条数时不做 z-score 检测
+	ZScore             float64 `toml:"z_score"`              // 偏离历史均值超过该 z-score 视为异常, 0 不检测
+	MaxDeviation       float64 `toml:"max_deviation"`        // 相对上一次价格的最大变动比例, 0 不检测
+	MaxSourceDeviation float64 `toml:"max_source_deviation"` // 相对 Chainlink 价格的最大偏离比例, 0 不检测
+}
+
+type SearchConfig struct {
+	PublicEnabled     bool `toml:"public_enabled"`       // 是否开放无需登录的质押池搜索
+	PublicRateLimit   int  `toml:"public_rate_limit"`    // 单 IP 在 public_rate_window 内的最大请求数, 0 不限制
+	PublicRateWindow  int  `toml:"public_rate_window"`   // 限流窗口, s
+	PublicMaxPageSize int  `toml:"public_max_page_size"` // 公开搜索单页最大条数
+}
+
+type ReportConfig struct {
+	EmailEnabled bool   `toml:"email_enabled"` // 是否将报表摘要发送到 [email] 收件人
+	EmailSubject string `toml:"email_subject"`
+}
+
+type IndexerConfig struct {
+	StartBlock    uint64 `toml:"start_block"`   // 没有游标时从该区块开始索引，0 表示从当前区块开始
+	BatchBlocks   uint64 `toml:"batch_blocks"`  // 单次 eth_getLogs 的区块范围
+	Confirmations uint64 `toml:"confirmations"` // 距最新区块超过该数量的区块中的事件才确认，之前的事件分叉时回滚
+}
+
+// BackfillConfig 历史价格回填，按间隔在历史区块上读取 Oracle getPrice，需要支持历史状态查询的归档节点
+type BackfillConfig struct {
+	StepBlocks uint64 `toml:"step_blocks"` // 采样间隔的区块数
+	RateLimit  int    `toml:"rate_limit"`  // 每秒最多的 RPC 请求数
+}
+
+type GraphqlConfig struct {
+	Enabled    bool `toml:"enabled"`
+	MaxDepth   int  `toml:"max_depth"`   // 查询最大嵌套深度
+	MaxFirst   int  `toml:"max_first"`   // 列表参数 first 的最大值
+	RateLimit  int  `toml:"rate_limit"`  // 单 IP 在 rate_window 内的最大请求数, 0 不限制
+	RateWindow int  `toml:"rate_window"` // 限流窗口, s
+}
+
+type MqttConfig struct {
+	Enabled     bool   `toml:"enabled"`
+	Broker      string `toml:"broker"` // tcp://host:1883 / ssl://host:8883
+	ClientId    string `toml:"client_id"`
+	Username    string `toml:"username"`
+	Password    string `toml:"password"`
+	TopicPrefix string `toml:"topic_prefix"`
+	Qos         byte   `toml:"qos"`
+	Retain      bool   `toml:"retain"` // 保留最后一条消息，新订阅的客户端立即收到当前值
+}
+
+type ExportConfig struct {
+	Enabled   bool   `toml:"enabled"`
+	Endpoint  string `toml:"endpoint"` // S3 兼容存储地址，例如 https://s3.ap-southeast-1.amazonaws.com
+	Region    string `toml:"region"`
+	Bucket    string `toml:"bucket"`
+	AccessKey string `toml:"access_key"`
+	SecretKey string `toml:"secret_key"`
+	Prefix    string `toml:"prefix"` // 对象 key 前缀，{prefix}/{table}/dt={YYYY-MM-DD}/{table}.parquet
+}
+
+// BackupConfig 数据库备份，上传到 [export] 配置的 S3 兼容存储
+// 对象 key: {prefix}/{备份 ID}/{table}.jsonl.gz，全部表上传后写入 {prefix}/{备份 ID}/manifest.json
+type BackupConfig struct {
+	Enabled       bool     `toml:"enabled"`
+	Prefix        string   `toml:"prefix"`
+	Tables        []string `toml:"tables"`         // 备份的表，链上无法重新同步的数据 (代币 logo、池子展示信息、多签配置等)
+	RetentionDays int      `toml:"retention_days"` // 删除超过该天数的备份, 0 不删除
+}
+
+// IntegrityConfig VerifyIntegrity 抽样核对链上数据与 MySQL、Redis 中的数据
+type IntegrityConfig struct {
+	SamplePools  int  `toml:"sample_pools"`  // 每条链每次抽查的池子数
+	SampleTokens int  `toml:"sample_tokens"` // 每条链每次抽查的代币数
+	Repair       bool `toml:"repair"`        // 发现不一致时清除缓存并重新同步该池子、代币 (代币精度变化只告警，需要人工确认)
+}
+
+// DevnetConfig 本地开发链 (anvil)，enabled 时 [testnet] 的节点和合约地址被替换为本地链
+type DevnetConfig struct {
+	Enabled              bool   `toml:"enabled"`
+	NetUrl               string `toml:"net_url"`
+	ChainId              string `toml:"chain_id"`    // anvil --chain-id 97，与测试网一致，接口的 chainId 校验无需修改
+	PrivateKey           string `toml:"private_key"` // 部署合约和写入价格的账户，未设置 plgr_admin_private_key 时也用于 schedule
+	PledgePoolToken      string `toml:"pledge_pool_token"`
+	BscPledgeOracleToken string `toml:"bsc_pledge_oracle_token"`
+	MultiSignAddress     string `toml:"multi_sign_address"` // PledgePool 的 multiSignature 合约，门限为 1，唯一签名人是 private_key 的账户
+}
+
+// ScheduleConfig schedule 定时任务的公共配置，支持热加载
+type ScheduleConfig struct {
+	JobTimeout          uint64 `toml:"job_timeout"`            // 单次任务的最长执行时间, min，超时后不再等待，结束前跳过后续执行
+	RetryMaxAttempts    int    `toml:"retry_max_attempts"`     // 重试队列中的条目最多重试次数，之后标记为 dead
+	JobRunRetentionDays int    `toml:"job_run_retention_days"` // job_runs 执行记录保留天数
+	ArchiveGraceDays    int    `toml:"archive_grace_days"`     // 池子 endTime 之后保留的天数，之后由 ArchivePools 归档
+	HeadInterval        int64  `toml:"head_interval"`          // 同一任务由新区块触发的最小间隔, s
+}
+
+// 多实例部署时定时任务的执行方式 [jobs.<任务名称>] run
+const (
+	JobRunLeader = "leader" // 只在 leader 上执行，默认
+	JobRunShard  = "shard"  // 所有实例都执行，按 [cluster] shard_by 分配各自处理的链或池子，只用于只读的同步任务
+)
+
+// JobConfig 单个定时任务的执行计划，配置文件中为 [jobs.<任务名称>]，支持热加载
+type JobConfig struct {
+	Cron    string   `toml:"cron"`    // cron 表达式 (UTC)，5 段 "分 时 日 月 周" 或带秒的 6 段，也支持 @every 2m、@daily 等
+	Enabled bool     `toml:"enabled"` // false 时不调度，task 启动时也不执行
+	Chains  []string `toml:"chains"`  // 只处理这些链 ID，为空时处理 [testnet] / [mainnet] 中所有 enabled 的链；只对按链同步的任务生效
+	Run     string   `toml:"run"`     // leader / shard，[cluster] enabled 时生效，为空时为 leader
+	OnHead  bool     `toml:"on_head"` // 配置了 ws_url 的链收到新区块时执行；所有处理的链订阅正常时不按 cron 执行，订阅断开时回退到 cron
+}
+
+type LogConfig struct {
+	Level string `toml:"level"` // debug / info / warn / error，支持热加载
+}
+
+// TelemetryConfig OpenTelemetry 链路追踪，span 以 OTLP/HTTP 发送到 collector
+type TelemetryConfig struct {
+	Enabled     bool    `toml:"enabled"`
+	Endpoint    string  `toml:"endpoint"`     // collector 地址 host:port，例如 127.0.0.1:4318
+	Insecure    bool    `toml:"insecure"`     // 使用 http 而不是 https
+	SampleRatio float64 `toml:"sample_ratio"` // 采样比例 0-1，上游请求已采样时跟随上游
+}
+
+// SentryConfig panic 和严重错误 (喂价写链失败、行情中断) 上报到 Sentry
+type SentryConfig struct {
+	Enabled     bool   `toml:"enabled"`
+	Dsn         string `toml:"dsn"`         // 由密钥服务读取 sentry_dsn
+	Environment string `toml:"environment"` // 例如 testnet、mainnet
+}
+
+// DebugConfig 线上问题排查
+// api 进程的 pprof 通过 /admin/debug/pprof 访问，需要管理员 Token；task 进程没有 HTTP 服务，单独监听本机地址
+type DebugConfig struct {
+	TaskPprofAddr string `toml:"task_pprof_addr"` // pledge task 的 pprof 监听地址，只允许本机地址，例如 127.0.0.1:6060，为空时不开启
+}
+
+// AlertConfig 余额告警的冷却和升级
+// 余额持续低于阈值时每次检查累计一次，达到 telegram_after / pagerduty_after 次后升级到对应渠道，低级别渠道继续发送
+type AlertConfig struct {
+	CooldownMinutes     int64  `toml:"cooldown_minutes"`      // 同一告警在冷却时间内不重复发送，升级时立即发送
+	TelegramAfter       int    `toml:"telegram_after"`        // 连续低于阈值的检查次数达到后发送 Telegram，0 表示不升级
+	PagerdutyAfter      int    `toml:"pagerduty_after"`       // 连续低于阈值的检查次数达到后触发 PagerDuty，0 表示不升级
+	TelegramBotToken    string `toml:"telegram_bot_token"`    // 由密钥服务读取 telegram_bot_token
+	TelegramChatId      string `toml:"telegram_chat_id"`      // 接收告警的群组或用户
+	PagerdutyUrl        string `toml:"pagerduty_url"`         // Events API v2 地址
+	PagerdutyRoutingKey string `toml:"pagerduty_routing_key"` // 由密钥服务读取 pagerduty_routing_key
+}
+
+// GasConfig 后端发送的交易 (Oracle 喂价等) 的 gas 预算，按链、按自然月 (服务器时区，与 created_at 一致) 统计
+type GasConfig struct {
+	TestnetMonthlyBudget string `toml:"testnet_monthly_budget"` // BNB，当月花费超出后告警，"0" 表示不告警
+	MainnetMonthlyBudget string `toml:"mainnet_monthly_budget"` // BNB
+}
+
+// KeeperConfig 清算执行机器人，默认关闭，只在同时承担 keeper 职责的部署中开启
+// LiquidatePools 检测到可结算、可完成或可清算的池子后由 keeper 账户发送 settle / finish / liquidate 交易
+type KeeperConfig struct {
+	Enabled         bool   `toml:"enabled"`
+	DryRun          bool   `toml:"dry_run"`            // 只签名和估算 gas，不广播交易，记录为 dry_run
+	MaxGasPriceGwei string `toml:"max_gas_price_gwei"` // 节点建议的 gas price 超过该值时不发送, gwei, "0" 不限制
+	MaxGasLimit     uint64 `toml:"max_gas_limit"`      // 估算的 gas limit 超过该值时不发送
+	RetryMinutes    int64  `toml:"retry_minutes"`      // 同一池子的同一操作两次尝试的最小间隔, min，交易待上链时不会重复发送
+	SignerKey       string `toml:"-"`                  // keeper 签名私钥，只从密钥服务读取 (keeper_private_key)，为空时使用喂价私钥
+}
+
+// DeadlineConfig 池子结算时间 (settleTime) 和结束时间 (endTime) 临近时的倒计时通知，由 WatchDeadlines 检查
+type DeadlineConfig struct {
+	LeadTimes     []string `toml:"lead_times"`   // 提前通知的时间，例如 ["24h", "1h", "5m"]，同一截止时间每个提前量只通知一次
+	AlertLevel    int      `toml:"alert_level"`  // 管理员告警渠道: 0 不告警, 1 邮件, 2 邮件 + Telegram
+	WebhookUrls   []string `toml:"webhook_urls"` // 以 POST JSON 通知的地址，为空不发送
+	WebhookSecret string   `toml:"-"`            // 由密钥服务读取 deadline_webhook_secret，非空时以 X-Pledge-Signature 头携带 HMAC-SHA256 签名
+}
+
+// SubscriptionConfig 钱包订阅池子事件的邮件通知，验证邮件由 api 发送，事件通知由 NotifySubscribers 发送
+type SubscriptionConfig struct {
+	Enabled        bool   `toml:"enabled"`          // false 时订阅接口返回 1003，NotifySubscribers 不发送
+	LinkBaseUrl    string `toml:"link_base_url"`    // 邮件中验证、退订链接的前缀，例如 https://api.example.com/api/v21
+	VerifyTtlHours int64  `toml:"verify_ttl_hours"` // 验证链接有效期, h，过期后需重新订阅
+	MaxPerAddress  int    `toml:"max_per_address"`  // 单个钱包在一条链上的最大订阅数
+	MaxAttempts    int    `toml:"max_attempts"`     // 单个通知发送失败后的最多尝试次数
+	RateLimit      int    `toml:"rate_limit"`       // 单 IP 在 rate_window 内的最大请求数, 0 不限制
+	RateWindow     int    `toml:"rate_window"`      // 限流窗口, s
+}
+
+// ReferralConfig 推荐码和存入交易的推荐归属，归属由 api 记录，等待索引的交易由 VerifyReferrals 校验
+type ReferralConfig struct {
+	Enabled      bool  `toml:"enabled"`       // false 时推荐接口返回 1003，VerifyReferrals 不校验
+	PendingHours int64 `toml:"pending_hours"` // 提交后超过该时间仍未在 pool_events 中找到的交易标记为无效, h
+	RateLimit    int   `toml:"rate_limit"`    // 单 IP 在 rate_window 内的最大请求数, 0 不限制
+	RateWindow   int   `toml:"rate_window"`   // 限流窗口, s
+}
+
+// I18nConfig 接口错误消息和用户通知邮件的语言，接口按请求头 Accept-Language 选择
+type I18nConfig struct {
+	DefaultLanguage string `toml:"default_language"` // 请求没有 Accept-Language 或其中的语言都不支持时使用，例如 en、zh、zh-TW
+	CatalogDir      string `toml:"catalog_dir"`      // 附加翻译目录，每个语言一个 <语言标签>.toml，相对路径相对于配置文件所在目录，为空不加载
+}
+
+// ChainHealthConfig RPC 节点健康检查
+// 每条链检查 net_url 和 endpoints 中的备用节点，区块高度与公共参考节点比较，落后或延迟超出阈值的节点标记为不健康
+type ChainHealthConfig struct {
+	TestnetEndpoints    []string `toml:"testnet_endpoints"`     // 测试网备用 RPC 节点
+	MainnetEndpoints    []string `toml:"mainnet_endpoints"`     // 主网备用 RPC 节点
+	TestnetReferenceUrl string   `toml:"testnet_reference_url"` // 测试网公共参考节点，为空时不计算落后区块数
+	MainnetReferenceUrl string   `toml:"mainnet_reference_url"` // 主网公共参考节点
+	MaxBlockLag         uint64   `toml:"max_block_lag"`         // 落后参考节点超过该区块数视为不健康
+	MaxLatencyMs        int64    `toml:"max_latency_ms"`        // eth_blockNumber 延迟超过该值视为不健康, ms
+}
+
+// ClusterConfig 多个 task 实例部署时的选主和分片
+// 实例通过 Redis 租约选出一个 leader，链上写入 (SavePlgrPrice)、告警、报表等任务只在 leader 上执行，
+// run = "shard" 的只读同步任务由所有存活的实例分担
+type ClusterConfig struct {
+	Enabled      bool   `toml:"enabled"`       // false 时单实例运行，所有任务在本实例执行
+	InstanceId   string `toml:"instance_id"`   // 实例标识，为空时使用 hostname-pid
+	LeaseSeconds int    `toml:"lease_seconds"` // leader 租约和实例心跳的有效期，每 1/3 租约续期一次
+	ShardBy      string `toml:"shard_by"`      // chain: 每条链由一个实例同步；pool: 每个实例同步所有链上 pool_id 取模分到的池子
 }
 
 type ThresholdConfig struct {
@@ -40,18 +385,19 @@ type EmailConfig struct {
 	Cc       []string `toml:"cc"`
 }
 
-type DefaultAdminConfig struct {
-	Username string `toml:"username"`
-	Password string `toml:"password"`
-}
-
 type JwtConfig struct {
 	SecretKey  string `toml:"secret_key"`
 	ExpireTime int    `toml:"expire_time"` // duration, s
 }
 
 type TokenConfig struct {
-	LogoUrl string `toml:"logo_url"`
+	LogoUrl          string `toml:"logo_url"`
+	TrustwalletUrl   string `toml:"trustwallet_url"`
+	LogoMaxSize      int64  `toml:"logo_max_size"`
+	LogoMinDimension int    `toml:"logo_min_dimension"`
+	LogoMaxDimension int    `toml:"logo_max_dimension"`
+	LogoSizes        []int  `toml:"logo_sizes"`
+	ListSignKey      string `toml:"list_sign_key"`
 }
 
 type MysqlConfig struct {
@@ -66,19 +412,33 @@ type MysqlConfig struct {
 }
 
 type TestNetConfig struct {
+	Enabled              bool   `toml:"enabled"` // schedule 是否同步该链的池子、事件和 Oracle 价格
 	ChainId              string `toml:"chain_id"`
 	NetUrl               string `toml:"net_url"`
+	WsUrl                string `toml:"ws_url"`   // WebSocket RPC 地址，订阅 newHeads 在新区块时触发 on_head 的任务，为空时只按 cron 执行
+	ReadLag              uint64 `toml:"read_lag"` // 同步池子和价格时读取最新区块之前第几个区块的状态，避免 API 返回可能被分叉回滚的数据
 	PlgrAddress          string `toml:"plgr_address"`
 	PledgePoolToken      string `toml:"pledge_pool_token"`
 	BscPledgeOracleToken string `toml:"bsc_pledge_oracle_token"`
+	MultiSignAddress     string `toml:"multi_sign_address"` // 多签合约地址，设置多签配置时校验签名人和门限，链 enabled 时必填
 }
 
 type MainNetConfig struct {
+	Enabled              bool   `toml:"enabled"` // schedule 是否同步该链的池子、事件和 Oracle 价格，并写入 PLGR 价格
 	ChainId              string `toml:"chain_id"`
 	NetUrl               string `toml:"net_url"`
+	WsUrl                string `toml:"ws_url"`   // WebSocket RPC 地址，订阅 newHeads 在新区块时触发 on_head 的任务，为空时只按 cron 执行
+	ReadLag              uint64 `toml:"read_lag"` // 同步池子和价格时读取最新区块之前第几个区块的状态，避免 API 返回可能被分叉回滚的数据
 	PlgrAddress          string `toml:"plgr_address"`
 	PledgePoolToken      string `toml:"pledge_pool_token"`
 	BscPledgeOracleToken string `toml:"bsc_pledge_oracle_token"`
+	MultiSignAddress     string `toml:"multi_sign_address"` // 多签合约地址，设置多签配置时校验签名人和门限，链 enabled 时必填
+}
+
+// StartupConfig 启动时等待 MySQL、Redis 和 RPC 节点就绪
+type StartupConfig struct {
+	WaitTimeout int64 `toml:"wait_timeout"` // 等待依赖就绪的最长时间, s, 0 只尝试一次
+	MaxBackoff  int64 `toml:"max_backoff"`  // 两次重试之间的最长间隔, s
 }
 
 type RedisConfig struct {
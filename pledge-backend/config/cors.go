@@ -0,0 +1,56 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+)
+
+// OriginAllowed 跨域请求的 Origin 是否在 [cors] allow_origins 中
+// "*" 允许所有来源；"https://*.pledge.finance" 允许该域名的任意子域名 (不含 pledge.finance 本身)；其余按 scheme://host[:port] 精确匹配
+func (c CorsConfig) OriginAllowed(origin string) bool {
+	origin = strings.ToLower(strings.TrimSuffix(origin, "/"))
+	for _, allowed := range c.AllowOrigins {
+		allowed = strings.ToLower(strings.TrimSuffix(allowed, "/"))
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+		if wildcardOriginMatch(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowAll allow_origins 包含 "*"
+func (c CorsConfig) AllowAll() bool {
+	for _, allowed := range c.AllowOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardOriginMatch pattern 形如 scheme://*.domain[:port]，origin 的 scheme、端口相同且主机名是 domain 的子域名
+func wildcardOriginMatch(pattern, origin string) bool {
+	i := strings.Index(pattern, "://*.")
+	if i < 0 {
+		return false
+	}
+	scheme, suffix := pattern[:i+3], pattern[i+4:]
+	if !strings.HasPrefix(origin, scheme) {
+		return false
+	}
+	host := origin[len(scheme):]
+	return strings.HasSuffix(host, suffix) && len(host) > len(suffix) && !strings.Contains(host[:len(host)-len(suffix)], "/")
+}
+
+// validOrigin allow_origins 中的一项: "*" 或 scheme://host[:port]，host 可以以 "*." 开头
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	u, err := url.Parse(strings.Replace(origin, "://*.", "://wildcard.", 1))
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" &&
+		(u.Path == "" || u.Path == "/") && u.RawQuery == "" && u.User == nil
+}
@@ -0,0 +1,19 @@
+package config
+
+import (
+	"sort"
+	"time"
+)
+
+// Leads [deadline] lead_times 中合法的提前量，从小到大排序
+func (c DeadlineConfig) Leads() []time.Duration {
+	leads := make([]time.Duration, 0, len(c.LeadTimes))
+	for _, lead := range c.LeadTimes {
+		d, err := time.ParseDuration(lead)
+		if err == nil && d > 0 {
+			leads = append(leads, d)
+		}
+	}
+	sort.Slice(leads, func(i, j int) bool { return leads[i] < leads[j] })
+	return leads
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"strings"
+)
+
+// ExchangeQuoteCurrency 定价路径最终的计价币，按 1 USDT = 1 USD 写入 Oracle
+const ExchangeQuoteCurrency = "USDT"
+
+// [oracle] batch_mode
+const (
+	OracleBatchSetPrices = "set_prices" // 一笔 setPrices 交易写入所有代币
+	OracleBatchSingle    = "single"     // 每个代币一笔 setPrice 交易
+)
+
+// Subscribed KuCoin 订阅的交易对，未配置时只订阅 PLGR-USDT
+func (c ExchangeConfig) Subscribed() []string {
+	if len(c.Symbols) == 0 {
+		return []string{"PLGR-USDT"}
+	}
+	return c.Symbols
+}
+
+// TokenRoute 代币在 [exchange.tokens] 中的定价路径，未配置时返回 false
+// 值为一个交易对，或用 * 连接的多个交易对，例如 "PLGR-BTC*BTC-USDT"，价格为各交易对价格的乘积
+func (c ExchangeConfig) TokenRoute(token string) ([]string, bool) {
+	value, ok := c.Tokens[strings.ToLower(token)]
+	if !ok {
+		return nil, false
+	}
+	return splitRoute(value), true
+}
+
+// FeedSymbol 代币在 Oracle 喂价熔断和写入决策中的名称: 定价路径第一个交易对的基础币以 USDT 计价，例如 PLGR-USDT
+// 未配置在 [exchange.tokens] 中时返回空字符串
+func (c ExchangeConfig) FeedSymbol(token string) string {
+	route, ok := c.TokenRoute(token)
+	if !ok {
+		return ""
+	}
+	return strings.Split(route[0], "-")[0] + "-" + ExchangeQuoteCurrency
+}
+
+func splitRoute(value string) []string {
+	route := strings.Split(value, "*")
+	for i := range route {
+		route[i] = strings.TrimSpace(route[i])
+	}
+	return route
+}
+
+// routeProblem 定价路径的问题: 交易对须已订阅，前一个交易对的计价币是后一个的基础币，最后以 USDT 计价
+// 路径合法时返回空字符串
+func (c ExchangeConfig) routeProblem(route []string) string {
+	subscribed := make(map[string]bool)
+	for _, symbol := range c.Subscribed() {
+		subscribed[symbol] = true
+	}
+	quote := ""
+	for i, symbol := range route {
+		pair := strings.Split(symbol, "-")
+		if len(pair) != 2 || pair[0] == "" || pair[1] == "" {
+			return symbol + " is not a BASE-QUOTE pair"
+		}
+		if !subscribed[symbol] {
+			return symbol + " is not in [exchange] symbols"
+		}
+		if i > 0 && pair[0] != quote {
+			return symbol + " does not convert from " + quote
+		}
+		quote = pair[1]
+	}
+	if quote != ExchangeQuoteCurrency {
+		return "must end with a pair quoted in " + ExchangeQuoteCurrency
+	}
+	return ""
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// builtinLanguages 内置翻译的语言标签 (小写)，其他语言需要 catalog_dir 中的翻译文件
+var builtinLanguages = []string{"en", "zh", "zh-tw"}
+
+// CatalogPath catalog_dir 的绝对路径，相对路径相对于配置文件所在目录，未配置时为空
+func (c I18nConfig) CatalogPath() string {
+	if c.CatalogDir == "" || filepath.IsAbs(c.CatalogDir) {
+		return c.CatalogDir
+	}
+	return filepath.Join(filepath.Dir(configFile), c.CatalogDir)
+}
+
+// languageAvailable 内置语言或 catalog_dir 中有 <tag>.toml
+func (c I18nConfig) languageAvailable(tag string) bool {
+	for _, builtin := range builtinLanguages {
+		if strings.EqualFold(tag, builtin) {
+			return true
+		}
+	}
+	if c.CatalogDir == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(c.CatalogPath(), tag+".toml"))
+	return err == nil
+}
@@ -1,24 +1,63 @@
 package config
 
 import (
+	"errors"
 	"github.com/BurntSushi/toml"
 	"path"
 	"path/filepath"
 	"runtime"
 )
 
-func init() {
+// configFile 当前使用的配置文件，热加载时重新读取
+var configFile string
+
+// Load 读取配置文件和密钥，由 cmd 在执行子命令之前调用，之后才能使用 Config()
+// 密钥服务 (Vault / SSM) 需要网络请求，不在 import 时读取
+func Load() error {
 	currentAbPath := getCurrentAbPathByCaller()
 	tomlFile, err := filepath.Abs(currentAbPath + "/configV21.toml")
 	//tomlFile, err := filepath.Abs(currentAbPath + "/configV22.toml")
 	if err != nil {
-		panic("read toml file err: " + err.Error())
-		return
+		return errors.New("config file path: " + err.Error())
+	}
+	configFile = tomlFile
+	conf, err := load()
+	if err != nil {
+		return errors.New("load config " + configFile + ": " + err.Error())
+	}
+	current.Store(conf)
+	return nil
+}
+
+// load 读取并解析配置文件，再从密钥服务读取密码、私钥等配置项
+func load() (*Conf, error) {
+	conf := &Conf{}
+	if _, err := toml.DecodeFile(configFile, conf); err != nil {
+		return nil, err
+	}
+	if err := loadSecrets(conf); err != nil {
+		return nil, err
 	}
-	if _, err := toml.DecodeFile(tomlFile, &Config); err != nil {
-		panic("read toml file err: " + err.Error())
+	applyDevnet(conf)
+	return conf, nil
+}
+
+// applyDevnet [devnet] enabled 时 schedule 同步的 [testnet] 改为本地开发链
+func applyDevnet(conf *Conf) {
+	if !conf.Devnet.Enabled {
 		return
 	}
+	conf.TestNet.Enabled = true
+	conf.TestNet.NetUrl = conf.Devnet.NetUrl
+	conf.TestNet.ChainId = conf.Devnet.ChainId
+	conf.TestNet.PledgePoolToken = conf.Devnet.PledgePoolToken
+	conf.TestNet.BscPledgeOracleToken = conf.Devnet.BscPledgeOracleToken
+	conf.TestNet.MultiSignAddress = conf.Devnet.MultiSignAddress
+	// anvil 只在有交易时出块，不会分叉，直接读取最新区块
+	conf.TestNet.ReadLag = 0
+	// 测试网的备用节点和参考节点不是本地链，不参与健康检查和节点切换
+	conf.ChainHealth.TestnetEndpoints = nil
+	conf.ChainHealth.TestnetReferenceUrl = ""
 }
 
 func getCurrentAbPathByCaller() string {
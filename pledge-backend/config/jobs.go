@@ -0,0 +1,59 @@
+package config
+
+import (
+	"github.com/robfig/cron/v3"
+)
+
+// CronParser 解析 [jobs] cron 表达式，秒可省略
+var CronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ChainEnabled 定时任务 job 是否处理链 chainId:
+// 链在 [testnet] / [mainnet] 中 enabled，且 [jobs.<job>] chains 为空或包含该链
+func (c *Conf) ChainEnabled(job, chainId string) bool {
+	switch chainId {
+	case c.TestNet.ChainId:
+		if !c.TestNet.Enabled {
+			return false
+		}
+	case c.MainNet.ChainId:
+		if !c.MainNet.Enabled {
+			return false
+		}
+	default:
+		return false
+	}
+
+	chains := c.Jobs[job].Chains
+	if len(chains) == 0 {
+		return true
+	}
+	for _, chain := range chains {
+		if chain == chainId {
+			return true
+		}
+	}
+	return false
+}
+
+// EnabledChainIds [testnet] / [mainnet] 中 enabled 的链，testnet 在前
+func (c *Conf) EnabledChainIds() []string {
+	chainIds := make([]string, 0, 2)
+	if c.TestNet.Enabled {
+		chainIds = append(chainIds, c.TestNet.ChainId)
+	}
+	if c.MainNet.Enabled {
+		chainIds = append(chainIds, c.MainNet.ChainId)
+	}
+	return chainIds
+}
+
+// ReadLag 链的 read_lag，同步任务读取最新区块之前第 read_lag 个区块的状态
+func (c *Conf) ReadLag(chainId string) uint64 {
+	switch chainId {
+	case c.TestNet.ChainId:
+		return c.TestNet.ReadLag
+	case c.MainNet.ChainId:
+		return c.MainNet.ReadLag
+	}
+	return 0
+}
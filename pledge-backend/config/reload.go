@@ -0,0 +1,155 @@
+package config
+
+import (
+	"path/filepath"
+	"pledge-backend/log"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadable 支持热加载的配置项，key 为配置文件中的名称
+// 这些配置在使用时读取 config.Config()，或通过 OnReload 重新应用；其它配置项的修改需要重启服务
+var reloadable = map[string]func(c *Conf) interface{}{
+	"schedule":                        func(c *Conf) interface{} { return &c.Schedule },
+	"jobs":                            func(c *Conf) interface{} { return &c.Jobs },
+	"testnet.enabled":                 func(c *Conf) interface{} { return &c.TestNet.Enabled },
+	"testnet.net_url":                 func(c *Conf) interface{} { return &c.TestNet.NetUrl },
+	"testnet.read_lag":                func(c *Conf) interface{} { return &c.TestNet.ReadLag },
+	"mainnet.enabled":                 func(c *Conf) interface{} { return &c.MainNet.Enabled },
+	"mainnet.net_url":                 func(c *Conf) interface{} { return &c.MainNet.NetUrl },
+	"mainnet.read_lag":                func(c *Conf) interface{} { return &c.MainNet.ReadLag },
+	"threshold":                       func(c *Conf) interface{} { return &c.Threshold },
+	"alert":                           func(c *Conf) interface{} { return &c.Alert },
+	"gas":                             func(c *Conf) interface{} { return &c.Gas },
+	"keeper":                          func(c *Conf) interface{} { return &c.Keeper },
+	"deadline":                        func(c *Conf) interface{} { return &c.Deadline },
+	"subscription":                    func(c *Conf) interface{} { return &c.Subscription },
+	"referral":                        func(c *Conf) interface{} { return &c.Referral },
+	"backfill":                        func(c *Conf) interface{} { return &c.Backfill },
+	"i18n.default_language":           func(c *Conf) interface{} { return &c.I18n.DefaultLanguage },
+	"chain_health":                    func(c *Conf) interface{} { return &c.ChainHealth },
+	"oracle":                          func(c *Conf) interface{} { return &c.Oracle },
+	"anomaly":                         func(c *Conf) interface{} { return &c.Anomaly },
+	"search.public_rate_limit":        func(c *Conf) interface{} { return &c.Search.PublicRateLimit },
+	"search.public_rate_window":       func(c *Conf) interface{} { return &c.Search.PublicRateWindow },
+	"graphql.rate_limit":              func(c *Conf) interface{} { return &c.Graphql.RateLimit },
+	"graphql.rate_window":             func(c *Conf) interface{} { return &c.Graphql.RateWindow },
+	"env.wss_max_connections":         func(c *Conf) interface{} { return &c.Env.WssMaxConnections },
+	"env.wss_max_connections_per_ip":  func(c *Conf) interface{} { return &c.Env.WssMaxConnectionsPerIp },
+	"env.wss_replay_size":             func(c *Conf) interface{} { return &c.Env.WssReplaySize },
+	"exchange.trade_retention_months": func(c *Conf) interface{} { return &c.Exchange.TradeRetentionMonths },
+	"env.request_timeout":             func(c *Conf) interface{} { return &c.Env.RequestTimeout },
+	"env.max_body_size":               func(c *Conf) interface{} { return &c.Env.MaxBodySize },
+	"env.strict_status":               func(c *Conf) interface{} { return &c.Env.StrictStatus },
+	"env.max_upload_size":             func(c *Conf) interface{} { return &c.Env.MaxUploadSize },
+	"admin.allow_cidrs":               func(c *Conf) interface{} { return &c.Admin.AllowCidrs },
+	"maintenance.cache_ttl":           func(c *Conf) interface{} { return &c.Maintenance.CacheTtl },
+	"maintenance.cache_max_size":      func(c *Conf) interface{} { return &c.Maintenance.CacheMaxSize },
+	"cache.ttl":                       func(c *Conf) interface{} { return &c.Cache.Ttl },
+	"cache.max_size":                  func(c *Conf) interface{} { return &c.Cache.MaxSize },
+	"cache.max_age":                   func(c *Conf) interface{} { return &c.Cache.MaxAge },
+	"cors":                            func(c *Conf) interface{} { return &c.Cors },
+	"log.level":                       func(c *Conf) interface{} { return &c.Log.Level },
+}
+
+var (
+	reloadLock  sync.Mutex
+	reloadHooks []func(changed []string)
+)
+
+// OnReload 注册热加载回调，changed 为发生变化的配置项
+// 用于启动时按配置创建、之后不再读取 config.Config() 的对象，例如定时任务、连接数限制
+func OnReload(hook func(changed []string)) {
+	reloadLock.Lock()
+	defer reloadLock.Unlock()
+	reloadHooks = append(reloadHooks, hook)
+}
+
+// Reload 重新读取配置文件，校验通过后替换 reloadable 中的配置项，返回发生变化的配置项
+// 校验失败时保留当前配置
+func Reload() ([]string, error) {
+	reloadLock.Lock()
+	defer reloadLock.Unlock()
+
+	loaded, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if err = validate(loaded); err != nil {
+		return nil, err
+	}
+
+	// 在副本上修改后通过 atomic.Value 整体替换，读取方不会看到只更新了一半的配置
+	next := *Config()
+	changed := make([]string, 0)
+	for key, field := range reloadable {
+		dst := reflect.ValueOf(field(&next)).Elem()
+		src := reflect.ValueOf(field(loaded)).Elem()
+		if !reflect.DeepEqual(dst.Interface(), src.Interface()) {
+			dst.Set(src)
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	if !reflect.DeepEqual(next, *loaded) {
+		log.Logger.Sugar().Warn("config reload: settings other than hot-reloadable ones changed, restart to apply them")
+	}
+	if len(changed) == 0 {
+		return changed, nil
+	}
+
+	current.Store(&next)
+	for _, hook := range reloadHooks {
+		hook(changed)
+	}
+	return changed, nil
+}
+
+// Watch 监听配置文件，修改后自动 Reload
+// 监听所在目录而不是文件本身，编辑器保存时先写临时文件再重命名也能收到事件
+func Watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err = watcher.Add(filepath.Dir(configFile)); err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+		// 保存文件通常触发多个事件，合并 500ms 内的事件后只加载一次
+		var debounce <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != configFile || event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				debounce = time.After(500 * time.Millisecond)
+			case <-debounce:
+				debounce = nil
+				changed, err := Reload()
+				if err != nil {
+					log.Logger.Sugar().Error("config reload err ", err)
+					continue
+				}
+				log.Logger.Sugar().Info("config reloaded, changed: ", changed)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Logger.Sugar().Error("config watch err ", err)
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"sort"
+)
+
+// 【密钥】
+// 数据库密码、JWT 密钥、喂价签名私钥等不写在配置文件中，启动时由环境变量 SECRETS_PROVIDER 选择的密钥服务读取:
+//   - env (默认): 读取与密钥同名的环境变量，例如 mysql_password、plgr_admin_private_key
+//   - vault: HashiCorp Vault KV v2，VAULT_ADDR、VAULT_TOKEN，密钥路径 VAULT_SECRET_PATH (默认 secret/data/pledge)
+//   - ssm: AWS SSM Parameter Store，AWS_REGION、AWS_ACCESS_KEY_ID、AWS_SECRET_ACCESS_KEY (可选 AWS_SESSION_TOKEN)，
+//     参数名为 SSM_PARAMETER_PREFIX (默认 /pledge/) + 密钥名称，SecureString 自动解密
+//
+// 密钥服务中不存在的密钥保留配置文件中的值 (一般为空)。
+
+// secretFields 从密钥服务读取的配置项，key 为密钥名称 (环境变量名 / Vault 字段名 / SSM 参数名后缀)
+var secretFields = map[string]func(c *Conf) *string{
+	"mysql_password":          func(c *Conf) *string { return &c.Mysql.Password },
+	"redis_password":          func(c *Conf) *string { return &c.Redis.Password },
+	"jwt_secret_key":          func(c *Conf) *string { return &c.Jwt.SecretKey },
+	"plgr_admin_private_key":  func(c *Conf) *string { return &c.Oracle.SignerKey },
+	"keeper_private_key":      func(c *Conf) *string { return &c.Keeper.SignerKey },
+	"deadline_webhook_secret": func(c *Conf) *string { return &c.Deadline.WebhookSecret },
+	"token_list_sign_key":     func(c *Conf) *string { return &c.Token.ListSignKey },
+	"email_pwd":               func(c *Conf) *string { return &c.Email.Pwd },
+	"mqtt_password":           func(c *Conf) *string { return &c.Mqtt.Password },
+	"export_access_key":       func(c *Conf) *string { return &c.Export.AccessKey },
+	"export_secret_key":       func(c *Conf) *string { return &c.Export.SecretKey },
+	"sentry_dsn":              func(c *Conf) *string { return &c.Sentry.Dsn },
+	"telegram_bot_token":      func(c *Conf) *string { return &c.Alert.TelegramBotToken },
+	"pagerduty_routing_key":   func(c *Conf) *string { return &c.Alert.PagerdutyRoutingKey },
+	"wss_api_keys":            func(c *Conf) *string { return &c.Env.WssApiKeys },
+}
+
+// secretsProvider 密钥服务
+type secretsProvider interface {
+	// Get 读取 names 中的密钥，结果只包含存在的密钥
+	Get(names []string) (map[string]string, error)
+}
+
+func newSecretsProvider() (secretsProvider, error) {
+	switch provider := os.Getenv("SECRETS_PROVIDER"); provider {
+	case "", "env":
+		return envSecrets{}, nil
+	case "vault":
+		return newVaultSecrets()
+	case "ssm":
+		return newSsmSecrets()
+	default:
+		return nil, errors.New("unknown SECRETS_PROVIDER " + provider + ", expected env, vault or ssm")
+	}
+}
+
+// loadSecrets 从密钥服务读取 secretFields 中的配置项
+func loadSecrets(conf *Conf) error {
+	provider, err := newSecretsProvider()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(secretFields))
+	for name := range secretFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	secrets, err := provider.Get(names)
+	if err != nil {
+		return errors.New("load secrets: " + err.Error())
+	}
+	for name, value := range secrets {
+		field, ok := secretFields[name]
+		if ok && value != "" {
+			*field(conf) = value
+		}
+	}
+	return nil
+}
+
+// envSecrets 从环境变量读取密钥
+type envSecrets struct{}
+
+func (envSecrets) Get(names []string) (map[string]string, error) {
+	secrets := make(map[string]string)
+	for _, name := range names {
+		value, ok := os.LookupEnv(name)
+		if ok {
+			secrets[name] = value
+		}
+	}
+	return secrets, nil
+}
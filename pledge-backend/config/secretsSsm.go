@@ -0,0 +1,110 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"pledge-backend/utils/sigv4"
+	"strings"
+	"time"
+)
+
+// ssmGetParametersLimit GetParameters 单次最多查询的参数个数
+const ssmGetParametersLimit = 10
+
+// ssmSecrets 从 AWS SSM Parameter Store 读取密钥，参数名为 prefix + 密钥名称
+type ssmSecrets struct {
+	endpoint string
+	region   string
+	prefix   string
+	cred     sigv4.Credentials
+}
+
+func newSsmSecrets() (*ssmSecrets, error) {
+	s := &ssmSecrets{
+		endpoint: strings.TrimRight(os.Getenv("SSM_ENDPOINT"), "/"),
+		region:   os.Getenv("AWS_REGION"),
+		prefix:   os.Getenv("SSM_PARAMETER_PREFIX"),
+		cred: sigv4.Credentials{
+			AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		},
+	}
+	if s.region == "" || s.cred.AccessKey == "" || s.cred.SecretKey == "" {
+		return nil, errors.New("SECRETS_PROVIDER=ssm requires AWS_REGION, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	if s.endpoint == "" {
+		s.endpoint = "https://ssm." + s.region + ".amazonaws.com"
+	}
+	if s.prefix == "" {
+		s.prefix = "/pledge/"
+	}
+	return s, nil
+}
+
+func (s *ssmSecrets) Get(names []string) (map[string]string, error) {
+	secrets := make(map[string]string)
+	for start := 0; start < len(names); start += ssmGetParametersLimit {
+		end := start + ssmGetParametersLimit
+		if end > len(names) {
+			end = len(names)
+		}
+		err := s.getParameters(names[start:end], secrets)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return secrets, nil
+}
+
+// getParameters 调用 AmazonSSM.GetParameters，不存在的参数出现在 InvalidParameters 中，不视为错误
+func (s *ssmSecrets) getParameters(names []string, secrets map[string]string) error {
+	params := make([]string, 0, len(names))
+	for _, name := range names {
+		params = append(params, s.prefix+name)
+	}
+	body, err := json.Marshal(map[string]interface{}{"Names": params, "WithDecryption": true})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "AmazonSSM.GetParameters")
+	sigv4.Sign(req, body, s.region, "ssm", s.cred)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	rsp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	rspBody, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return err
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return errors.New("ssm GetParameters " + rsp.Status + " " + string(rspBody))
+	}
+
+	result := struct {
+		Parameters []struct {
+			Name  string `json:"Name"`
+			Value string `json:"Value"`
+		} `json:"Parameters"`
+	}{}
+	if err = json.Unmarshal(rspBody, &result); err != nil {
+		return errors.New("ssm GetParameters: " + err.Error())
+	}
+	for _, param := range result.Parameters {
+		secrets[strings.TrimPrefix(param.Name, s.prefix)] = param.Value
+	}
+	return nil
+}
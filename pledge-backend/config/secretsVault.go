@@ -0,0 +1,74 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// vaultSecrets 从 HashiCorp Vault KV v2 读取密钥，所有密钥保存在同一个路径的不同字段中
+type vaultSecrets struct {
+	addr  string
+	token string
+	path  string // 例如 secret/data/pledge
+}
+
+func newVaultSecrets() (*vaultSecrets, error) {
+	s := &vaultSecrets{
+		addr:  strings.TrimRight(os.Getenv("VAULT_ADDR"), "/"),
+		token: os.Getenv("VAULT_TOKEN"),
+		path:  strings.Trim(os.Getenv("VAULT_SECRET_PATH"), "/"),
+	}
+	if s.addr == "" || s.token == "" {
+		return nil, errors.New("SECRETS_PROVIDER=vault requires VAULT_ADDR and VAULT_TOKEN")
+	}
+	if s.path == "" {
+		s.path = "secret/data/pledge"
+	}
+	return s, nil
+}
+
+func (s *vaultSecrets) Get(names []string) (map[string]string, error) {
+	req, err := http.NewRequest(http.MethodGet, s.addr+"/v1/"+s.path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", s.token)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	rsp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	body, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, errors.New("vault " + s.path + " " + rsp.Status + " " + string(body))
+	}
+
+	// KV v2 响应: {"data": {"data": {...}, "metadata": {...}}}
+	result := struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}{}
+	if err = json.Unmarshal(body, &result); err != nil {
+		return nil, errors.New("vault " + s.path + ": " + err.Error())
+	}
+
+	secrets := make(map[string]string)
+	for _, name := range names {
+		value, ok := result.Data.Data[name]
+		if ok {
+			secrets[name] = value
+		}
+	}
+	return secrets, nil
+}
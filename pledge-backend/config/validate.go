@@ -0,0 +1,515 @@
+package config
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	hexAddressRegexp = regexp.MustCompile(`^0[xX][0-9a-fA-F]{40}$`)
+	privateKeyRegexp = regexp.MustCompile(`^(0[xX])?[0-9a-fA-F]{64}$`)
+	tableNameRegexp  = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	decimalRegexp    = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+	rpcSchemes       = []string{"http", "https", "ws", "wss"}
+)
+
+// ValidationError 配置校验失败的全部问题，每一项形如 "[section] key: 原因"
+type ValidationError []string
+
+func (e ValidationError) Error() string {
+	return "invalid config:\n  " + strings.Join(e, "\n  ")
+}
+
+// validator 收集校验问题，所有配置项检查完后一次性返回，而不是遇到第一个错误就退出
+type validator struct {
+	problems ValidationError
+}
+
+func (v *validator) addf(section, key, reason string) {
+	v.problems = append(v.problems, "["+section+"] "+key+": "+reason)
+}
+
+func (v *validator) notEmpty(section, key, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.addf(section, key, "must not be empty")
+		return false
+	}
+	return true
+}
+
+func (v *validator) url(section, key, value string, schemes ...string) {
+	if !v.notEmpty(section, key, value) {
+		return
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		v.addf(section, key, "invalid url "+strconv.Quote(value))
+		return
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return
+		}
+	}
+	v.addf(section, key, "unsupported scheme "+strconv.Quote(u.Scheme)+", expected one of "+strings.Join(schemes, ", "))
+}
+
+func (v *validator) hexAddress(section, key, value string) {
+	if !v.notEmpty(section, key, value) {
+		return
+	}
+	if !hexAddressRegexp.MatchString(value) {
+		v.addf(section, key, strconv.Quote(value)+" is not a 0x-prefixed 20-byte hex address")
+	}
+}
+
+func (v *validator) chainId(section, key, value string) {
+	if !v.notEmpty(section, key, value) {
+		return
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || id == 0 {
+		v.addf(section, key, strconv.Quote(value)+" is not a positive integer")
+	}
+}
+
+func (v *validator) port(section, key, value string) {
+	if !v.notEmpty(section, key, value) {
+		return
+	}
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		v.addf(section, key, strconv.Quote(value)+" is not a port in range 1-65535")
+	}
+}
+
+func (v *validator) positive(section, key string, value int64) {
+	if value <= 0 {
+		v.addf(section, key, "must be greater than 0, got "+strconv.FormatInt(value, 10))
+	}
+}
+
+func (v *validator) nonNegative(section, key string, value int64) {
+	if value < 0 {
+		v.addf(section, key, "must not be negative, got "+strconv.FormatInt(value, 10))
+	}
+}
+
+func (v *validator) decimal(section, key, value string) {
+	if !decimalRegexp.MatchString(value) {
+		v.addf(section, key, strconv.Quote(value)+" is not a non-negative decimal number")
+	}
+}
+
+// Validate 启动时检查配置，返回汇总了所有问题的 ValidationError
+//
+// 在连接数据库、节点之前调用，避免错误的 RPC 地址或合约地址直到同步任务中才以 RPC / hex 解码错误的形式暴露
+func Validate() error {
+	return validate(Config())
+}
+
+func validate(c *Conf) error {
+	v := &validator{}
+
+	v.notEmpty("mysql", "address", c.Mysql.Address)
+	v.port("mysql", "port", c.Mysql.Port)
+	v.notEmpty("mysql", "db_name", c.Mysql.DbName)
+	v.notEmpty("mysql", "user_name", c.Mysql.UserName)
+	v.nonNegative("mysql", "max_open_conns", int64(c.Mysql.MaxOpenConns))
+	v.nonNegative("mysql", "max_idle_conns", int64(c.Mysql.MaxIdleConns))
+	v.nonNegative("mysql", "max_life_time", int64(c.Mysql.MaxLifeTime))
+
+	v.notEmpty("redis", "address", c.Redis.Address)
+	v.port("redis", "port", c.Redis.Port)
+	if c.Redis.Db < 0 || c.Redis.Db > 15 {
+		v.addf("redis", "db", "must be in range 0-15, got "+strconv.Itoa(c.Redis.Db))
+	}
+	v.nonNegative("redis", "idle_timeout", int64(c.Redis.IdleTimeout))
+
+	v.nonNegative("startup", "wait_timeout", c.Startup.WaitTimeout)
+	v.positive("startup", "max_backoff", c.Startup.MaxBackoff)
+
+	v.chainId("testnet", "chain_id", c.TestNet.ChainId)
+	v.url("testnet", "net_url", c.TestNet.NetUrl, rpcSchemes...)
+	if c.TestNet.WsUrl != "" {
+		v.url("testnet", "ws_url", c.TestNet.WsUrl, "ws", "wss")
+	}
+	v.hexAddress("testnet", "plgr_address", c.TestNet.PlgrAddress)
+	v.hexAddress("testnet", "pledge_pool_token", c.TestNet.PledgePoolToken)
+	v.hexAddress("testnet", "bsc_pledge_oracle_token", c.TestNet.BscPledgeOracleToken)
+	// 设置多签配置时必须与链上多签合约核对，enabled 的链不能跳过
+	if c.TestNet.Enabled || c.TestNet.MultiSignAddress != "" {
+		v.hexAddress("testnet", "multi_sign_address", c.TestNet.MultiSignAddress)
+	}
+
+	v.chainId("mainnet", "chain_id", c.MainNet.ChainId)
+	v.url("mainnet", "net_url", c.MainNet.NetUrl, rpcSchemes...)
+	if c.MainNet.WsUrl != "" {
+		v.url("mainnet", "ws_url", c.MainNet.WsUrl, "ws", "wss")
+	}
+	v.hexAddress("mainnet", "plgr_address", c.MainNet.PlgrAddress)
+	v.hexAddress("mainnet", "pledge_pool_token", c.MainNet.PledgePoolToken)
+	v.hexAddress("mainnet", "bsc_pledge_oracle_token", c.MainNet.BscPledgeOracleToken)
+	if c.MainNet.Enabled || c.MainNet.MultiSignAddress != "" {
+		v.hexAddress("mainnet", "multi_sign_address", c.MainNet.MultiSignAddress)
+	}
+
+	if c.Token.ListSignKey != "" && !privateKeyRegexp.MatchString(c.Token.ListSignKey) {
+		v.addf("token", "list_sign_key", "must be a 32-byte hex private key")
+	}
+	v.positive("token", "logo_max_size", c.Token.LogoMaxSize)
+	if c.Token.LogoMinDimension <= 0 || c.Token.LogoMinDimension > c.Token.LogoMaxDimension {
+		v.addf("token", "logo_min_dimension", "must be greater than 0 and not greater than logo_max_dimension")
+	}
+
+	if c.Jwt.SecretKey == "" {
+		v.addf("jwt", "secret_key", "must not be empty, set jwt_secret_key in the secrets provider")
+	}
+	v.positive("jwt", "expire_time", int64(c.Jwt.ExpireTime))
+
+	v.port("env", "port", c.Env.Port)
+	v.notEmpty("env", "version", c.Env.Version)
+	v.positive("env", "task_duration", c.Env.TaskDuration)
+	v.positive("env", "task_extend_duration", c.Env.TaskExtendDuration)
+	v.positive("env", "wss_timeout_duration", c.Env.WssTimeoutDuration)
+	v.positive("env", "wss_ping_interval", c.Env.WssPingInterval)
+	v.positive("env", "wss_write_timeout", c.Env.WssWriteTimeout)
+	v.positive("env", "wss_broadcast_interval", c.Env.WssBroadcastInterval)
+	v.nonNegative("env", "wss_max_connections", int64(c.Env.WssMaxConnections))
+	v.nonNegative("env", "wss_max_connections_per_ip", int64(c.Env.WssMaxConnectionsPerIp))
+	v.nonNegative("env", "wss_replay_size", int64(c.Env.WssReplaySize))
+	v.nonNegative("env", "request_timeout", c.Env.RequestTimeout)
+	v.nonNegative("env", "read_header_timeout", c.Env.ReadHeaderTimeout)
+	v.nonNegative("env", "read_timeout", c.Env.ReadTimeout)
+	v.nonNegative("env", "max_body_size", c.Env.MaxBodySize)
+	v.nonNegative("env", "max_upload_size", c.Env.MaxUploadSize)
+	if c.Env.WssPingInterval >= c.Env.WssTimeoutDuration {
+		v.addf("env", "wss_ping_interval", "must be less than wss_timeout_duration, otherwise idle connections are closed before the next ping")
+	}
+
+	if len(c.Cors.AllowOrigins) == 0 {
+		v.addf("cors", "allow_origins", "must not be empty, use [\"*\"] to allow every origin")
+	}
+	for _, origin := range c.Cors.AllowOrigins {
+		if !validOrigin(origin) {
+			v.addf("cors", "allow_origins", "invalid origin "+strconv.Quote(origin)+", expected \"*\" or scheme://host[:port] with an optional *. subdomain wildcard")
+		}
+	}
+	if c.Cors.AllowCredentials && c.Cors.AllowAll() {
+		v.addf("cors", "allow_credentials", "must be false when allow_origins contains \"*\", browsers reject credentialed requests to a wildcard origin")
+	}
+	if len(c.Cors.AllowMethods) == 0 {
+		v.addf("cors", "allow_methods", "must not be empty")
+	}
+	v.nonNegative("cors", "max_age", c.Cors.MaxAge)
+
+	for _, cidr := range c.Env.TrustedProxies {
+		if _, err := parseCidr(cidr); err != nil {
+			v.addf("env", "trusted_proxies", "invalid cidr "+strconv.Quote(cidr))
+		}
+	}
+	for _, cidr := range c.Admin.AllowCidrs {
+		if _, err := parseCidr(cidr); err != nil {
+			v.addf("admin", "allow_cidrs", "invalid cidr "+strconv.Quote(cidr))
+		}
+	}
+	if len(c.Admin.AllowCidrs) > 0 {
+		for _, cidr := range c.Env.TrustedProxies {
+			if network, err := parseCidr(cidr); err == nil {
+				if ones, _ := network.Mask.Size(); ones == 0 {
+					v.addf("env", "trusted_proxies", strconv.Quote(cidr)+" trusts every address, clients could forge X-Forwarded-For to pass [admin] allow_cidrs")
+				}
+			}
+		}
+	}
+	if c.Admin.MtlsEnabled() {
+		v.port("admin", "tls_port", c.Admin.TlsPort)
+		if c.Admin.TlsPort == c.Env.Port {
+			v.addf("admin", "tls_port", "must differ from [env] port")
+		}
+		v.notEmpty("admin", "tls_cert_file", c.Admin.TlsCertFile)
+		v.notEmpty("admin", "tls_key_file", c.Admin.TlsKeyFile)
+		v.notEmpty("admin", "client_ca_file", c.Admin.ClientCaFile)
+	} else if c.Admin.RequireMtls {
+		v.addf("admin", "require_mtls", "requires tls_port, otherwise admin routes are unreachable")
+	}
+
+	v.positive("maintenance", "poll_interval", c.Maintenance.PollInterval)
+	v.nonNegative("maintenance", "cache_ttl", c.Maintenance.CacheTtl)
+	v.positive("maintenance", "cache_max_size", c.Maintenance.CacheMaxSize)
+
+	v.nonNegative("cache", "ttl", c.Cache.Ttl)
+	v.positive("cache", "max_size", c.Cache.MaxSize)
+	v.nonNegative("cache", "max_age", c.Cache.MaxAge)
+	if c.Cache.Warmup {
+		v.positive("cache", "warmup_timeout", c.Cache.WarmupTimeout)
+	}
+
+	v.nonNegative("exchange", "average_window", c.Exchange.AverageWindow)
+	v.nonNegative("exchange", "trade_retention_months", int64(c.Exchange.TradeRetentionMonths))
+	if c.Exchange.AverageMode != "twap" && c.Exchange.AverageMode != "vwap" {
+		v.addf("exchange", "average_mode", strconv.Quote(c.Exchange.AverageMode)+" is not one of twap, vwap")
+	}
+	for token, value := range c.Exchange.Tokens {
+		v.hexAddress("exchange.tokens", "key", token)
+		if problem := c.Exchange.routeProblem(splitRoute(value)); problem != "" {
+			v.addf("exchange.tokens", token, problem)
+		}
+	}
+	for _, symbol := range c.Exchange.DepthSymbols {
+		v.notEmpty("exchange", "depth_symbols", symbol)
+	}
+
+	v.positive("oracle", "stale_minutes", c.Oracle.StaleMinutes)
+	v.positive("oracle", "max_failures", int64(c.Oracle.MaxFailures))
+	v.nonNegative("oracle", "cooldown_minutes", c.Oracle.CooldownMinutes)
+	v.nonNegative("oracle", "freshness_minutes", c.Oracle.FreshnessMinutes)
+	v.nonNegative("oracle", "min_change_bps", c.Oracle.MinChangeBps)
+	v.nonNegative("oracle", "max_skip_minutes", c.Oracle.MaxSkipMinutes)
+	if c.Oracle.FreshnessMinutes > 0 && c.Oracle.MinChangeBps > 0 &&
+		(c.Oracle.MaxSkipMinutes == 0 || c.Oracle.MaxSkipMinutes >= c.Oracle.FreshnessMinutes) {
+		v.addf("oracle", "max_skip_minutes", "must be between 1 and freshness_minutes - 1 when min_change_bps is set, otherwise skipped writes trip the freshness alert")
+	}
+	for _, token := range c.Oracle.Feeds {
+		v.hexAddress("oracle", "feeds", token)
+		if _, ok := c.Exchange.TokenRoute(token); !ok {
+			v.addf("oracle", "feeds", token+" is not in [exchange.tokens]")
+		}
+	}
+	if c.Oracle.BatchMode != OracleBatchSetPrices && c.Oracle.BatchMode != OracleBatchSingle {
+		v.addf("oracle", "batch_mode", strconv.Quote(c.Oracle.BatchMode)+" is not one of set_prices, single")
+	}
+	if c.Oracle.MaxDivergence < 0 {
+		v.addf("oracle", "max_divergence", "must not be negative, got "+strconv.FormatFloat(c.Oracle.MaxDivergence, 'f', -1, 64))
+	}
+
+	for token, feed := range c.Chainlink.Feeds {
+		v.hexAddress("chainlink.feeds", "key", token)
+		v.hexAddress("chainlink.feeds", token, feed)
+	}
+
+	v.url("coingecko", "api_url", c.Coingecko.ApiUrl, "http", "https")
+	v.nonNegative("coingecko", "min_interval", c.Coingecko.MinInterval)
+	v.nonNegative("coingecko", "cache_seconds", int64(c.Coingecko.CacheSeconds))
+
+	v.nonNegative("search", "public_rate_limit", int64(c.Search.PublicRateLimit))
+	if c.Search.PublicRateLimit > 0 {
+		v.positive("search", "public_rate_window", int64(c.Search.PublicRateWindow))
+	}
+	v.positive("search", "public_max_page_size", int64(c.Search.PublicMaxPageSize))
+
+	v.positive("indexer", "batch_blocks", int64(c.Indexer.BatchBlocks))
+	v.positive("backfill", "step_blocks", int64(c.Backfill.StepBlocks))
+	v.positive("backfill", "rate_limit", int64(c.Backfill.RateLimit))
+
+	if c.Graphql.Enabled {
+		v.positive("graphql", "max_depth", int64(c.Graphql.MaxDepth))
+		v.positive("graphql", "max_first", int64(c.Graphql.MaxFirst))
+		if c.Graphql.RateLimit > 0 {
+			v.positive("graphql", "rate_window", int64(c.Graphql.RateWindow))
+		}
+	}
+
+	if c.Mqtt.Enabled {
+		v.url("mqtt", "broker", c.Mqtt.Broker, "tcp", "ssl", "tls", "ws", "wss")
+		if c.Mqtt.Qos > 2 {
+			v.addf("mqtt", "qos", "must be 0, 1 or 2, got "+strconv.Itoa(int(c.Mqtt.Qos)))
+		}
+	}
+
+	// [backup] 使用 [export] 的存储
+	if c.Export.Enabled || c.Backup.Enabled {
+		v.url("export", "endpoint", c.Export.Endpoint, "http", "https")
+		v.notEmpty("export", "region", c.Export.Region)
+		v.notEmpty("export", "bucket", c.Export.Bucket)
+		v.notEmpty("export", "access_key", c.Export.AccessKey)
+		v.notEmpty("export", "secret_key", c.Export.SecretKey)
+	}
+
+	if c.Backup.Enabled {
+		v.notEmpty("backup", "prefix", strings.Trim(c.Backup.Prefix, "/"))
+		if len(c.Backup.Tables) == 0 {
+			v.addf("backup", "tables", "must not be empty")
+		}
+		for _, table := range c.Backup.Tables {
+			if !tableNameRegexp.MatchString(table) {
+				v.addf("backup", "tables", "invalid table name "+strconv.Quote(table))
+			}
+		}
+		if c.Backup.RetentionDays < 0 {
+			v.addf("backup", "retention_days", "must not be negative")
+		}
+	}
+
+	if c.Integrity.SamplePools < 0 {
+		v.addf("integrity", "sample_pools", "must not be negative")
+	}
+	if c.Integrity.SampleTokens < 0 {
+		v.addf("integrity", "sample_tokens", "must not be negative")
+	}
+
+	if c.Devnet.Enabled {
+		v.url("devnet", "net_url", c.Devnet.NetUrl, rpcSchemes...)
+		v.chainId("devnet", "chain_id", c.Devnet.ChainId)
+		if !privateKeyRegexp.MatchString(c.Devnet.PrivateKey) {
+			v.addf("devnet", "private_key", "must be a 32-byte hex private key")
+		}
+		v.hexAddress("devnet", "pledge_pool_token", c.Devnet.PledgePoolToken)
+		v.hexAddress("devnet", "bsc_pledge_oracle_token", c.Devnet.BscPledgeOracleToken)
+		v.hexAddress("devnet", "multi_sign_address", c.Devnet.MultiSignAddress)
+	}
+
+	names := make([]string, 0, len(c.Jobs))
+	for name := range c.Jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names) // 错误按任务名称排序输出
+	for _, name := range names {
+		job := c.Jobs[name]
+		if _, err := CronParser.Parse(job.Cron); err != nil {
+			v.addf("jobs."+name, "cron", strconv.Quote(job.Cron)+" is not a valid cron expression: "+err.Error())
+		}
+		for _, chain := range job.Chains {
+			v.chainId("jobs."+name, "chains", chain)
+		}
+		if job.Run != "" && job.Run != JobRunLeader && job.Run != JobRunShard {
+			v.addf("jobs."+name, "run", strconv.Quote(job.Run)+" is not leader or shard")
+		}
+	}
+	v.positive("schedule", "job_timeout", int64(c.Schedule.JobTimeout))
+	v.positive("schedule", "retry_max_attempts", int64(c.Schedule.RetryMaxAttempts))
+	v.positive("schedule", "job_run_retention_days", int64(c.Schedule.JobRunRetentionDays))
+	v.positive("schedule", "archive_grace_days", int64(c.Schedule.ArchiveGraceDays))
+	v.nonNegative("schedule", "head_interval", c.Schedule.HeadInterval)
+	v.decimal("gas", "testnet_monthly_budget", c.Gas.TestnetMonthlyBudget)
+	v.decimal("gas", "mainnet_monthly_budget", c.Gas.MainnetMonthlyBudget)
+	leads := make(map[time.Duration]bool)
+	for _, lead := range c.Deadline.LeadTimes {
+		d, err := time.ParseDuration(lead)
+		if err != nil || d <= 0 {
+			v.addf("deadline", "lead_times", strconv.Quote(lead)+" is not a positive duration such as 24h, 1h or 5m")
+		} else if leads[d] {
+			v.addf("deadline", "lead_times", strconv.Quote(lead)+" is duplicated")
+		}
+		leads[d] = true
+	}
+	if c.Deadline.AlertLevel < 0 || c.Deadline.AlertLevel > 2 {
+		v.addf("deadline", "alert_level", "must be 0, 1 or 2, got "+strconv.Itoa(c.Deadline.AlertLevel))
+	}
+	if c.Deadline.AlertLevel >= 2 && (c.Alert.TelegramBotToken == "" || c.Alert.TelegramChatId == "") {
+		v.addf("deadline", "alert_level", "2 requires [alert] telegram_chat_id and telegram_bot_token in the secrets provider")
+	}
+	for _, webhook := range c.Deadline.WebhookUrls {
+		v.url("deadline", "webhook_urls", webhook, "http", "https")
+	}
+	if c.Subscription.Enabled {
+		v.url("subscription", "link_base_url", c.Subscription.LinkBaseUrl, "http", "https")
+		v.positive("subscription", "verify_ttl_hours", c.Subscription.VerifyTtlHours)
+		v.positive("subscription", "max_per_address", int64(c.Subscription.MaxPerAddress))
+		v.positive("subscription", "max_attempts", int64(c.Subscription.MaxAttempts))
+		v.nonNegative("subscription", "rate_limit", int64(c.Subscription.RateLimit))
+		if c.Subscription.RateLimit > 0 {
+			v.positive("subscription", "rate_window", int64(c.Subscription.RateWindow))
+		}
+	}
+	if c.Referral.Enabled {
+		v.positive("referral", "pending_hours", c.Referral.PendingHours)
+		v.nonNegative("referral", "rate_limit", int64(c.Referral.RateLimit))
+		if c.Referral.RateLimit > 0 {
+			v.positive("referral", "rate_window", int64(c.Referral.RateWindow))
+		}
+	}
+	if c.I18n.CatalogDir != "" {
+		if info, err := os.Stat(c.I18n.CatalogPath()); err != nil || !info.IsDir() {
+			v.addf("i18n", "catalog_dir", strconv.Quote(c.I18n.CatalogDir)+" is not a directory")
+		}
+	}
+	if c.I18n.DefaultLanguage != "" && !c.I18n.languageAvailable(c.I18n.DefaultLanguage) {
+		v.addf("i18n", "default_language", strconv.Quote(c.I18n.DefaultLanguage)+" is not en, zh, zh-TW or a <tag>.toml in catalog_dir")
+	}
+	if c.Keeper.Enabled {
+		v.decimal("keeper", "max_gas_price_gwei", c.Keeper.MaxGasPriceGwei)
+		v.positive("keeper", "max_gas_limit", int64(c.Keeper.MaxGasLimit))
+		v.positive("keeper", "retry_minutes", c.Keeper.RetryMinutes)
+		if c.Keeper.SignerKey != "" && !privateKeyRegexp.MatchString(c.Keeper.SignerKey) {
+			v.addf("keeper", "keeper_private_key", "must be a 32-byte hex private key")
+		}
+	}
+
+	for _, endpoint := range c.ChainHealth.TestnetEndpoints {
+		v.url("chain_health", "testnet_endpoints", endpoint, rpcSchemes...)
+	}
+	for _, endpoint := range c.ChainHealth.MainnetEndpoints {
+		v.url("chain_health", "mainnet_endpoints", endpoint, rpcSchemes...)
+	}
+	if c.ChainHealth.TestnetReferenceUrl != "" {
+		v.url("chain_health", "testnet_reference_url", c.ChainHealth.TestnetReferenceUrl, rpcSchemes...)
+	}
+	if c.ChainHealth.MainnetReferenceUrl != "" {
+		v.url("chain_health", "mainnet_reference_url", c.ChainHealth.MainnetReferenceUrl, rpcSchemes...)
+	}
+	v.positive("chain_health", "max_block_lag", int64(c.ChainHealth.MaxBlockLag))
+	v.positive("chain_health", "max_latency_ms", c.ChainHealth.MaxLatencyMs)
+
+	if c.Cluster.Enabled {
+		if c.Cluster.LeaseSeconds < 3 {
+			v.addf("cluster", "lease_seconds", "must be at least 3")
+		}
+		if c.Cluster.ShardBy != "chain" && c.Cluster.ShardBy != "pool" {
+			v.addf("cluster", "shard_by", strconv.Quote(c.Cluster.ShardBy)+" is not chain or pool")
+		}
+	}
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		v.addf("log", "level", strconv.Quote(c.Log.Level)+" is not one of debug, info, warn, error")
+	}
+
+	if c.Telemetry.Enabled {
+		v.notEmpty("telemetry", "endpoint", c.Telemetry.Endpoint)
+		if c.Telemetry.SampleRatio < 0 || c.Telemetry.SampleRatio > 1 {
+			v.addf("telemetry", "sample_ratio", "must be in range 0-1, got "+strconv.FormatFloat(c.Telemetry.SampleRatio, 'f', -1, 64))
+		}
+	}
+
+	if c.Sentry.Enabled {
+		v.url("sentry", "dsn", c.Sentry.Dsn, "http", "https")
+	}
+
+	v.nonNegative("alert", "cooldown_minutes", c.Alert.CooldownMinutes)
+	v.nonNegative("alert", "telegram_after", int64(c.Alert.TelegramAfter))
+	v.nonNegative("alert", "pagerduty_after", int64(c.Alert.PagerdutyAfter))
+	if c.Alert.TelegramAfter > 0 {
+		if c.Alert.TelegramBotToken == "" {
+			v.addf("alert", "telegram_bot_token", "must not be empty when telegram_after > 0, set telegram_bot_token in the secrets provider")
+		}
+		v.notEmpty("alert", "telegram_chat_id", c.Alert.TelegramChatId)
+	}
+	if c.Alert.PagerdutyAfter > 0 {
+		v.url("alert", "pagerduty_url", c.Alert.PagerdutyUrl, "https", "http")
+		if c.Alert.PagerdutyRoutingKey == "" {
+			v.addf("alert", "pagerduty_routing_key", "must not be empty when pagerduty_after > 0, set pagerduty_routing_key in the secrets provider")
+		}
+	}
+
+	if c.Debug.TaskPprofAddr != "" {
+		host, _, err := net.SplitHostPort(c.Debug.TaskPprofAddr)
+		if err != nil {
+			v.addf("debug", "task_pprof_addr", err.Error())
+		} else if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			v.addf("debug", "task_pprof_addr", "pprof has no authentication, listen on a loopback address such as 127.0.0.1:6060")
+		}
+	}
+
+	if len(v.problems) > 0 {
+		return v.problems
+	}
+	return nil
+}
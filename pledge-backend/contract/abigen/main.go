@@ -0,0 +1,58 @@
+// abigen 根据 contract/abi 中的 ABI 生成 contract/bindings 中的 Go 绑定
+//
+// 由 contract/bindings/generate.go 调用: cd contract/bindings && go generate
+// ABI 来自 pledgev2 的 hardhat-abi-exporter 输出 (pledgev2/abi/contracts)，合约升级后先覆盖 contract/abi 中对应的 .abi 再生成
+// 只生成 contracts 中列出的合约；PledgePool 和 Oracle 等早期的绑定是手工生成的，不在此列
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// contract 一个生成的绑定
+type contract struct {
+	Type string // 绑定的 Go 类型名
+	Abi  string // contract/abi 中的文件
+	Out  string // contract/bindings 中的文件
+}
+
+var contracts = []contract{
+	// spCoin / jpCoin，读取 mint / burn (Transfer 事件) 和 minter 列表
+	{Type: "DebtToken", Abi: "debt_token.abi", Out: "debtToken.go"},
+	// 权限合约，读取 minter 白名单
+	{Type: "AddressPrivileges", Abi: "address_privileges.abi", Out: "addressPrivileges.go"},
+	// PledgePool.swapRouter 指向的 UniswapV2Router02，读取清算时的兑换报价
+	{Type: "SwapRouter", Abi: "swap_router.abi", Out: "swapRouter.go"},
+}
+
+func main() {
+	abiDir := flag.String("abi", "../abi", "directory of the .abi files")
+	outDir := flag.String("out", ".", "directory of the generated bindings")
+	pkg := flag.String("pkg", "bindings", "package name of the generated bindings")
+	flag.Parse()
+
+	for _, c := range contracts {
+		if err := generate(c, *abiDir, *outDir, *pkg); err != nil {
+			fmt.Fprintln(os.Stderr, "abigen", c.Type, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func generate(c contract, abiDir, outDir, pkg string) error {
+	abiBytes, err := os.ReadFile(filepath.Join(abiDir, c.Abi))
+	if err != nil {
+		return err
+	}
+	// 只生成调用和事件绑定，不需要部署用的 bytecode
+	code, err := bind.Bind([]string{c.Type}, []string{string(abiBytes)}, []string{""}, nil, pkg, bind.LangGo, nil, nil)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outDir, c.Out), []byte(code), 0644)
+}
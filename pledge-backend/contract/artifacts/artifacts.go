@@ -0,0 +1,50 @@
+// Package artifacts 本地开发链 (pledge devnet) 部署用的合约 ABI 和创建字节码
+//
+// 取自 pledgev2 在 Sepolia 部署时的 hardhat 编译结果 (pledgev2/ignition/deployments/chain-11155111/build-info)，
+// 与 contract/bindings 中 PledgePool 的调用和事件一致，只多了 multiSignature 构造参数；合约升级后重新导出对应的 .json
+package artifacts
+
+import (
+	"embed"
+	"encoding/json"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+//go:embed *.json
+var files embed.FS
+
+const (
+	PledgePool     = "pledge_pool"
+	MultiSignature = "multi_signature"
+	DebtToken      = "debt_token"
+)
+
+// Contract 一个合约的 ABI 和创建字节码
+type Contract struct {
+	Abi      abi.ABI
+	Bytecode []byte
+}
+
+// Load 读取 name 对应的 <name>.json
+func Load(name string) (*Contract, error) {
+	content, err := files.ReadFile(name + ".json")
+	if err != nil {
+		return nil, err
+	}
+	var artifact struct {
+		Abi      json.RawMessage `json:"abi"`
+		Bytecode string          `json:"bytecode"`
+	}
+	err = json.Unmarshal(content, &artifact)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(artifact.Abi)))
+	if err != nil {
+		return nil, err
+	}
+	return &Contract{Abi: parsed, Bytecode: common.FromHex(artifact.Bytecode)}, nil
+}
@@ -0,0 +1,346 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package bindings
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+)
+
+// AddressPrivilegesMetaData contains all meta data concerning the AddressPrivileges contract.
+var AddressPrivilegesMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"multiSignature\",\"type\":\"address\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_addMinter\",\"type\":\"address\"}],\"name\":\"addMinter\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_delMinter\",\"type\":\"address\"}],\"name\":\"delMinter\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"_index\",\"type\":\"uint256\"}],\"name\":\"getMinter\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getMinterLength\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getMultiSignatureAddress\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"isMinter\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// AddressPrivilegesABI is the input ABI used to generate the binding from.
+// Deprecated: Use AddressPrivilegesMetaData.ABI instead.
+var AddressPrivilegesABI = AddressPrivilegesMetaData.ABI
+
+// AddressPrivileges is an auto generated Go binding around an Ethereum contract.
+type AddressPrivileges struct {
+	AddressPrivilegesCaller     // Read-only binding to the contract
+	AddressPrivilegesTransactor // Write-only binding to the contract
+	AddressPrivilegesFilterer   // Log filterer for contract events
+}
+
+// AddressPrivilegesCaller is an auto generated read-only Go binding around an Ethereum contract.
+type AddressPrivilegesCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AddressPrivilegesTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type AddressPrivilegesTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AddressPrivilegesFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type AddressPrivilegesFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AddressPrivilegesSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type AddressPrivilegesSession struct {
+	Contract     *AddressPrivileges // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts      // Call options to use throughout this session
+	TransactOpts bind.TransactOpts  // Transaction auth options to use throughout this session
+}
+
+// AddressPrivilegesCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type AddressPrivilegesCallerSession struct {
+	Contract *AddressPrivilegesCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts            // Call options to use throughout this session
+}
+
+// AddressPrivilegesTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type AddressPrivilegesTransactorSession struct {
+	Contract     *AddressPrivilegesTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts            // Transaction auth options to use throughout this session
+}
+
+// AddressPrivilegesRaw is an auto generated low-level Go binding around an Ethereum contract.
+type AddressPrivilegesRaw struct {
+	Contract *AddressPrivileges // Generic contract binding to access the raw methods on
+}
+
+// AddressPrivilegesCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type AddressPrivilegesCallerRaw struct {
+	Contract *AddressPrivilegesCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// AddressPrivilegesTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type AddressPrivilegesTransactorRaw struct {
+	Contract *AddressPrivilegesTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewAddressPrivileges creates a new instance of AddressPrivileges, bound to a specific deployed contract.
+func NewAddressPrivileges(address common.Address, backend bind.ContractBackend) (*AddressPrivileges, error) {
+	contract, err := bindAddressPrivileges(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &AddressPrivileges{AddressPrivilegesCaller: AddressPrivilegesCaller{contract: contract}, AddressPrivilegesTransactor: AddressPrivilegesTransactor{contract: contract}, AddressPrivilegesFilterer: AddressPrivilegesFilterer{contract: contract}}, nil
+}
+
+// NewAddressPrivilegesCaller creates a new read-only instance of AddressPrivileges, bound to a specific deployed contract.
+func NewAddressPrivilegesCaller(address common.Address, caller bind.ContractCaller) (*AddressPrivilegesCaller, error) {
+	contract, err := bindAddressPrivileges(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AddressPrivilegesCaller{contract: contract}, nil
+}
+
+// NewAddressPrivilegesTransactor creates a new write-only instance of AddressPrivileges, bound to a specific deployed contract.
+func NewAddressPrivilegesTransactor(address common.Address, transactor bind.ContractTransactor) (*AddressPrivilegesTransactor, error) {
+	contract, err := bindAddressPrivileges(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AddressPrivilegesTransactor{contract: contract}, nil
+}
+
+// NewAddressPrivilegesFilterer creates a new log filterer instance of AddressPrivileges, bound to a specific deployed contract.
+func NewAddressPrivilegesFilterer(address common.Address, filterer bind.ContractFilterer) (*AddressPrivilegesFilterer, error) {
+	contract, err := bindAddressPrivileges(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &AddressPrivilegesFilterer{contract: contract}, nil
+}
+
+// bindAddressPrivileges binds a generic wrapper to an already deployed contract.
+func bindAddressPrivileges(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(AddressPrivilegesABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_AddressPrivileges *AddressPrivilegesRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _AddressPrivileges.Contract.AddressPrivilegesCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_AddressPrivileges *AddressPrivilegesRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AddressPrivileges.Contract.AddressPrivilegesTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_AddressPrivileges *AddressPrivilegesRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _AddressPrivileges.Contract.AddressPrivilegesTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_AddressPrivileges *AddressPrivilegesCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _AddressPrivileges.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_AddressPrivileges *AddressPrivilegesTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AddressPrivileges.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_AddressPrivileges *AddressPrivilegesTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _AddressPrivileges.Contract.contract.Transact(opts, method, params...)
+}
+
+// GetMinter is a free data retrieval call binding the contract method 0x5b7121f8.
+//
+// Solidity: function getMinter(uint256 _index) view returns(address)
+func (_AddressPrivileges *AddressPrivilegesCaller) GetMinter(opts *bind.CallOpts, _index *big.Int) (common.Address, error) {
+	var out []interface{}
+	err := _AddressPrivileges.contract.Call(opts, &out, "getMinter", _index)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// GetMinter is a free data retrieval call binding the contract method 0x5b7121f8.
+//
+// Solidity: function getMinter(uint256 _index) view returns(address)
+func (_AddressPrivileges *AddressPrivilegesSession) GetMinter(_index *big.Int) (common.Address, error) {
+	return _AddressPrivileges.Contract.GetMinter(&_AddressPrivileges.CallOpts, _index)
+}
+
+// GetMinter is a free data retrieval call binding the contract method 0x5b7121f8.
+//
+// Solidity: function getMinter(uint256 _index) view returns(address)
+func (_AddressPrivileges *AddressPrivilegesCallerSession) GetMinter(_index *big.Int) (common.Address, error) {
+	return _AddressPrivileges.Contract.GetMinter(&_AddressPrivileges.CallOpts, _index)
+}
+
+// GetMinterLength is a free data retrieval call binding the contract method 0x0323aac7.
+//
+// Solidity: function getMinterLength() view returns(uint256)
+func (_AddressPrivileges *AddressPrivilegesCaller) GetMinterLength(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _AddressPrivileges.contract.Call(opts, &out, "getMinterLength")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetMinterLength is a free data retrieval call binding the contract method 0x0323aac7.
+//
+// Solidity: function getMinterLength() view returns(uint256)
+func (_AddressPrivileges *AddressPrivilegesSession) GetMinterLength() (*big.Int, error) {
+	return _AddressPrivileges.Contract.GetMinterLength(&_AddressPrivileges.CallOpts)
+}
+
+// GetMinterLength is a free data retrieval call binding the contract method 0x0323aac7.
+//
+// Solidity: function getMinterLength() view returns(uint256)
+func (_AddressPrivileges *AddressPrivilegesCallerSession) GetMinterLength() (*big.Int, error) {
+	return _AddressPrivileges.Contract.GetMinterLength(&_AddressPrivileges.CallOpts)
+}
+
+// GetMultiSignatureAddress is a free data retrieval call binding the contract method 0x638c7e17.
+//
+// Solidity: function getMultiSignatureAddress() view returns(address)
+func (_AddressPrivileges *AddressPrivilegesCaller) GetMultiSignatureAddress(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _AddressPrivileges.contract.Call(opts, &out, "getMultiSignatureAddress")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// GetMultiSignatureAddress is a free data retrieval call binding the contract method 0x638c7e17.
+//
+// Solidity: function getMultiSignatureAddress() view returns(address)
+func (_AddressPrivileges *AddressPrivilegesSession) GetMultiSignatureAddress() (common.Address, error) {
+	return _AddressPrivileges.Contract.GetMultiSignatureAddress(&_AddressPrivileges.CallOpts)
+}
+
+// GetMultiSignatureAddress is a free data retrieval call binding the contract method 0x638c7e17.
+//
+// Solidity: function getMultiSignatureAddress() view returns(address)
+func (_AddressPrivileges *AddressPrivilegesCallerSession) GetMultiSignatureAddress() (common.Address, error) {
+	return _AddressPrivileges.Contract.GetMultiSignatureAddress(&_AddressPrivileges.CallOpts)
+}
+
+// IsMinter is a free data retrieval call binding the contract method 0xaa271e1a.
+//
+// Solidity: function isMinter(address account) view returns(bool)
+func (_AddressPrivileges *AddressPrivilegesCaller) IsMinter(opts *bind.CallOpts, account common.Address) (bool, error) {
+	var out []interface{}
+	err := _AddressPrivileges.contract.Call(opts, &out, "isMinter", account)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// IsMinter is a free data retrieval call binding the contract method 0xaa271e1a.
+//
+// Solidity: function isMinter(address account) view returns(bool)
+func (_AddressPrivileges *AddressPrivilegesSession) IsMinter(account common.Address) (bool, error) {
+	return _AddressPrivileges.Contract.IsMinter(&_AddressPrivileges.CallOpts, account)
+}
+
+// IsMinter is a free data retrieval call binding the contract method 0xaa271e1a.
+//
+// Solidity: function isMinter(address account) view returns(bool)
+func (_AddressPrivileges *AddressPrivilegesCallerSession) IsMinter(account common.Address) (bool, error) {
+	return _AddressPrivileges.Contract.IsMinter(&_AddressPrivileges.CallOpts, account)
+}
+
+// AddMinter is a paid mutator transaction binding the contract method 0x983b2d56.
+//
+// Solidity: function addMinter(address _addMinter) returns(bool)
+func (_AddressPrivileges *AddressPrivilegesTransactor) AddMinter(opts *bind.TransactOpts, _addMinter common.Address) (*types.Transaction, error) {
+	return _AddressPrivileges.contract.Transact(opts, "addMinter", _addMinter)
+}
+
+// AddMinter is a paid mutator transaction binding the contract method 0x983b2d56.
+//
+// Solidity: function addMinter(address _addMinter) returns(bool)
+func (_AddressPrivileges *AddressPrivilegesSession) AddMinter(_addMinter common.Address) (*types.Transaction, error) {
+	return _AddressPrivileges.Contract.AddMinter(&_AddressPrivileges.TransactOpts, _addMinter)
+}
+
+// AddMinter is a paid mutator transaction binding the contract method 0x983b2d56.
+//
+// Solidity: function addMinter(address _addMinter) returns(bool)
+func (_AddressPrivileges *AddressPrivilegesTransactorSession) AddMinter(_addMinter common.Address) (*types.Transaction, error) {
+	return _AddressPrivileges.Contract.AddMinter(&_AddressPrivileges.TransactOpts, _addMinter)
+}
+
+// DelMinter is a paid mutator transaction binding the contract method 0x23338b88.
+//
+// Solidity: function delMinter(address _delMinter) returns(bool)
+func (_AddressPrivileges *AddressPrivilegesTransactor) DelMinter(opts *bind.TransactOpts, _delMinter common.Address) (*types.Transaction, error) {
+	return _AddressPrivileges.contract.Transact(opts, "delMinter", _delMinter)
+}
+
+// DelMinter is a paid mutator transaction binding the contract method 0x23338b88.
+//
+// Solidity: function delMinter(address _delMinter) returns(bool)
+func (_AddressPrivileges *AddressPrivilegesSession) DelMinter(_delMinter common.Address) (*types.Transaction, error) {
+	return _AddressPrivileges.Contract.DelMinter(&_AddressPrivileges.TransactOpts, _delMinter)
+}
+
+// DelMinter is a paid mutator transaction binding the contract method 0x23338b88.
+//
+// Solidity: function delMinter(address _delMinter) returns(bool)
+func (_AddressPrivileges *AddressPrivilegesTransactorSession) DelMinter(_delMinter common.Address) (*types.Transaction, error) {
+	return _AddressPrivileges.Contract.DelMinter(&_AddressPrivileges.TransactOpts, _delMinter)
+}
@@ -0,0 +1,4 @@
+package bindings
+
+// 重新生成 DebtToken、AddressPrivileges、SwapRouter 的绑定，见 contract/abigen
+//go:generate go run ../abigen -abi ../abi -out .
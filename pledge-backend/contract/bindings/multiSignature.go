@@ -0,0 +1,242 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package bindings
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+)
+
+// MultiSignatureMetaData contains all meta data concerning the MultiSignature contract.
+var MultiSignatureMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"signatureOwners\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"threshold\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// MultiSignatureABI is the input ABI used to generate the binding from.
+// Deprecated: Use MultiSignatureMetaData.ABI instead.
+var MultiSignatureABI = MultiSignatureMetaData.ABI
+
+// MultiSignature is an auto generated Go binding around an Ethereum contract.
+type MultiSignature struct {
+	MultiSignatureCaller     // Read-only binding to the contract
+	MultiSignatureTransactor // Write-only binding to the contract
+	MultiSignatureFilterer   // Log filterer for contract events
+}
+
+// MultiSignatureCaller is an auto generated read-only Go binding around an Ethereum contract.
+type MultiSignatureCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MultiSignatureTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type MultiSignatureTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MultiSignatureFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type MultiSignatureFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MultiSignatureSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type MultiSignatureSession struct {
+	Contract     *MultiSignature   // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// MultiSignatureCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type MultiSignatureCallerSession struct {
+	Contract *MultiSignatureCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts         // Call options to use throughout this session
+}
+
+// MultiSignatureTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type MultiSignatureTransactorSession struct {
+	Contract     *MultiSignatureTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts         // Transaction auth options to use throughout this session
+}
+
+// MultiSignatureRaw is an auto generated low-level Go binding around an Ethereum contract.
+type MultiSignatureRaw struct {
+	Contract *MultiSignature // Generic contract binding to access the raw methods on
+}
+
+// MultiSignatureCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type MultiSignatureCallerRaw struct {
+	Contract *MultiSignatureCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// MultiSignatureTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type MultiSignatureTransactorRaw struct {
+	Contract *MultiSignatureTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewMultiSignature creates a new instance of MultiSignature, bound to a specific deployed contract.
+func NewMultiSignature(address common.Address, backend bind.ContractBackend) (*MultiSignature, error) {
+	contract, err := bindMultiSignature(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSignature{MultiSignatureCaller: MultiSignatureCaller{contract: contract}, MultiSignatureTransactor: MultiSignatureTransactor{contract: contract}, MultiSignatureFilterer: MultiSignatureFilterer{contract: contract}}, nil
+}
+
+// NewMultiSignatureCaller creates a new read-only instance of MultiSignature, bound to a specific deployed contract.
+func NewMultiSignatureCaller(address common.Address, caller bind.ContractCaller) (*MultiSignatureCaller, error) {
+	contract, err := bindMultiSignature(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSignatureCaller{contract: contract}, nil
+}
+
+// NewMultiSignatureTransactor creates a new write-only instance of MultiSignature, bound to a specific deployed contract.
+func NewMultiSignatureTransactor(address common.Address, transactor bind.ContractTransactor) (*MultiSignatureTransactor, error) {
+	contract, err := bindMultiSignature(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSignatureTransactor{contract: contract}, nil
+}
+
+// NewMultiSignatureFilterer creates a new log filterer instance of MultiSignature, bound to a specific deployed contract.
+func NewMultiSignatureFilterer(address common.Address, filterer bind.ContractFilterer) (*MultiSignatureFilterer, error) {
+	contract, err := bindMultiSignature(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSignatureFilterer{contract: contract}, nil
+}
+
+// bindMultiSignature binds a generic wrapper to an already deployed contract.
+func bindMultiSignature(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(MultiSignatureABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_MultiSignature *MultiSignatureRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _MultiSignature.Contract.MultiSignatureCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_MultiSignature *MultiSignatureRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _MultiSignature.Contract.MultiSignatureTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_MultiSignature *MultiSignatureRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _MultiSignature.Contract.MultiSignatureTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_MultiSignature *MultiSignatureCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _MultiSignature.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_MultiSignature *MultiSignatureTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _MultiSignature.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_MultiSignature *MultiSignatureTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _MultiSignature.Contract.contract.Transact(opts, method, params...)
+}
+
+// SignatureOwners is a free data retrieval call binding the contract method 0x5e63ff3f.
+//
+// Solidity: function signatureOwners(uint256 ) view returns(address)
+func (_MultiSignature *MultiSignatureCaller) SignatureOwners(opts *bind.CallOpts, arg0 *big.Int) (common.Address, error) {
+	var out []interface{}
+	err := _MultiSignature.contract.Call(opts, &out, "signatureOwners", arg0)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// SignatureOwners is a free data retrieval call binding the contract method 0x5e63ff3f.
+//
+// Solidity: function signatureOwners(uint256 ) view returns(address)
+func (_MultiSignature *MultiSignatureSession) SignatureOwners(arg0 *big.Int) (common.Address, error) {
+	return _MultiSignature.Contract.SignatureOwners(&_MultiSignature.CallOpts, arg0)
+}
+
+// SignatureOwners is a free data retrieval call binding the contract method 0x5e63ff3f.
+//
+// Solidity: function signatureOwners(uint256 ) view returns(address)
+func (_MultiSignature *MultiSignatureCallerSession) SignatureOwners(arg0 *big.Int) (common.Address, error) {
+	return _MultiSignature.Contract.SignatureOwners(&_MultiSignature.CallOpts, arg0)
+}
+
+// Threshold is a free data retrieval call binding the contract method 0x42cde4e8.
+//
+// Solidity: function threshold() view returns(uint256)
+func (_MultiSignature *MultiSignatureCaller) Threshold(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _MultiSignature.contract.Call(opts, &out, "threshold")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Threshold is a free data retrieval call binding the contract method 0x42cde4e8.
+//
+// Solidity: function threshold() view returns(uint256)
+func (_MultiSignature *MultiSignatureSession) Threshold() (*big.Int, error) {
+	return _MultiSignature.Contract.Threshold(&_MultiSignature.CallOpts)
+}
+
+// Threshold is a free data retrieval call binding the contract method 0x42cde4e8.
+//
+// Solidity: function threshold() view returns(uint256)
+func (_MultiSignature *MultiSignatureCallerSession) Threshold() (*big.Int, error) {
+	return _MultiSignature.Contract.Threshold(&_MultiSignature.CallOpts)
+}
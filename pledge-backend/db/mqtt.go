@@ -0,0 +1,72 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+var MqttClient mqtt.Client
+
+// mqttConnectRetryInterval 首次连接失败后的重试间隔
+const mqttConnectRetryInterval = 10 * time.Second
+
+// InitMqtt 初始化 MQTT 连接，[mqtt] enabled 关闭时不连接
+// MQTT 只用于向轻量客户端推送，连接失败不影响主流程:
+// 首次连接失败时每 mqttConnectRetryInterval 在后台重试 (SetConnectRetry)，连接建立后断开由 AutoReconnect 重连
+func InitMqtt() mqtt.Client {
+	mqttConf := config.Config().Mqtt
+	if !mqttConf.Enabled {
+		return nil
+	}
+	log.Logger.Info("Init Mqtt")
+	opts := mqtt.NewClientOptions().
+		AddBroker(mqttConf.Broker).
+		SetClientID(mqttConf.ClientId).
+		SetUsername(mqttConf.Username).
+		SetPassword(mqttConf.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(mqttConnectRetryInterval).
+		SetConnectTimeout(10 * time.Second).
+		SetConnectionLostHandler(func(client mqtt.Client, err error) {
+			log.Logger.Sugar().Error("mqtt connection lost ", err)
+		})
+	MqttClient = mqtt.NewClient(opts)
+	token := MqttClient.Connect()
+	if !token.WaitTimeout(10 * time.Second) {
+		log.Logger.Sugar().Warn("mqtt not connected to ", mqttConf.Broker, ", retrying every ", mqttConnectRetryInterval)
+	} else if token.Error() != nil {
+		log.Logger.Sugar().Error("mqtt connect err ", token.Error())
+	}
+	return MqttClient
+}
+
+// MqttTopic 拼接主题，例如 MqttTopic("97", "price", "BUSD") -> pledge/97/price/BUSD
+func MqttTopic(levels ...string) string {
+	return strings.Join(append([]string{config.Config().Mqtt.TopicPrefix}, levels...), "/")
+}
+
+// MqttPublish 以 JSON 发布消息，未启用 MQTT 时直接返回
+func MqttPublish(topic string, data interface{}) error {
+	if MqttClient == nil {
+		return nil
+	}
+	if !MqttClient.IsConnected() {
+		return errors.New("mqtt not connected")
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	token := MqttClient.Publish(topic, config.Config().Mqtt.Qos, config.Config().Mqtt.Retain, payload)
+	if !token.WaitTimeout(5 * time.Second) {
+		return errors.New("mqtt publish timeout " + topic)
+	}
+	return token.Error()
+}
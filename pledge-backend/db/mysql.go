@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"pledge-backend/config"
 	"pledge-backend/log"
+	"pledge-backend/telemetry"
 	"time"
 
 	"gorm.io/driver/mysql"
@@ -11,8 +12,9 @@ import (
 	"gorm.io/gorm/schema"
 )
 
-func InitMysql() {
-	mysqlConf := config.Config.Mysql
+// InitMysql 连接 MySQL，失败时返回错误，由 WaitDependencies 重试
+func InitMysql() error {
+	mysqlConf := config.Config().Mysql
 	log.Logger.Info("Init Mysql")
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
 		mysqlConf.UserName,
@@ -34,7 +36,7 @@ func InitMysql() {
 		SkipDefaultTransaction: true,
 	})
 	if err != nil {
-		log.Logger.Panic(fmt.Sprintf("mysql connention error ==>  %+v", err))
+		return fmt.Errorf("mysql connection error: %w", err)
 	}
 	_ = db.Callback().Create().After("gorm:after_create").Register("after_create", After)
 	_ = db.Callback().Query().After("gorm:after_query").Register("after_query", After)
@@ -43,12 +45,21 @@ func InitMysql() {
 	_ = db.Callback().Row().After("gorm:row").Register("after_row", After)
 	_ = db.Callback().Raw().After("gorm:raw").Register("after_raw", After)
 
+	// 链路追踪: 通过 WithContext(ctx) 传入请求或定时任务的 ctx 时，每条 SQL 记录一个 span
+	if err = db.Use(telemetry.GormPlugin()); err != nil {
+		log.Logger.Error("gorm telemetry plugin err:" + err.Error())
+	}
+
 	//自动迁移为给定模型运行自动迁移，只会添加缺失的字段，不会删除/更改当前数据
 	//db.AutoMigrate(&TestTable{})
 
 	sqlDB, err := db.DB()
 	if err != nil {
-		log.Logger.Error("db.DB() err:" + err.Error())
+		return err
+	}
+	if err = sqlDB.Ping(); err != nil {
+		_ = sqlDB.Close()
+		return fmt.Errorf("mysql ping error: %w", err)
 	}
 	//下列三项设置可参考技术文档或查看源代码
 	//https://colobu.com/2019/05/27/configuring-sql-DB-for-better-performance/
@@ -56,6 +67,7 @@ func InitMysql() {
 	sqlDB.SetMaxOpenConns(mysqlConf.MaxOpenConns) // 最大连接数   默认0是无限制的  使用默认值即可
 	sqlDB.SetConnMaxLifetime(time.Duration(mysqlConf.MaxLifeTime) * time.Second)
 	Mysql = db
+	return nil
 }
 
 func After(db *gorm.DB) {
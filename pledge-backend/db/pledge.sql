@@ -98,7 +98,10 @@ CREATE TABLE `poolbases` (
   `lend_token_info` json DEFAULT NULL,
   `chain_id` varchar(20) DEFAULT '56',
   `lend_token_symbol` varchar(100) DEFAULT NULL,
-  `borrow_token_symbol` varchar(100) DEFAULT NULL
+  `borrow_token_symbol` varchar(100) DEFAULT NULL,
+  `version` bigint(20) NOT NULL DEFAULT '0',
+  `deleted_at` datetime DEFAULT NULL,
+  `archived_at` datetime DEFAULT NULL
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='poolbase';
 
 --
@@ -175,7 +178,8 @@ CREATE TABLE `pooldata` (
   `created_at` datetime DEFAULT NULL,
   `id` int(11) NOT NULL,
   `chain_id` varchar(20) DEFAULT '56',
-  `pool_id` varchar(50) DEFAULT NULL
+  `pool_id` varchar(50) DEFAULT NULL,
+  `version` bigint(20) NOT NULL DEFAULT '0'
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='pooldata';
 
 --
@@ -244,6 +248,7 @@ INSERT INTO `pooldata` (`settle_amount_lend`, `settle_amount_borrow`, `finish_am
 CREATE TABLE `token_info` (
   `id` int(10) UNSIGNED NOT NULL,
   `symbol` varchar(100) DEFAULT NULL,
+  `name` varchar(100) DEFAULT NULL,
   `logo` varchar(150) DEFAULT NULL,
   `price` varchar(50) DEFAULT NULL,
   `token` varchar(100) DEFAULT NULL,
@@ -251,7 +256,14 @@ CREATE TABLE `token_info` (
   `abi_file_exist` int(2) UNSIGNED DEFAULT '0',
   `created_at` datetime DEFAULT NULL,
   `updated_at` datetime DEFAULT NULL,
-  `decimals` int(11) NOT NULL
+  `decimals` int(11) NOT NULL,
+  `coingecko_id` varchar(100) DEFAULT NULL,
+  `chainlink_price` varchar(50) DEFAULT NULL,
+  `chainlink_updated_at` bigint(20) DEFAULT NULL,
+  `logo_source` varchar(20) DEFAULT NULL,
+  `logo_origin_url` varchar(255) DEFAULT NULL,
+  `price_source` varchar(20) NOT NULL DEFAULT '',
+  `deleted_at` datetime DEFAULT NULL
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
 --
@@ -371,6 +383,502 @@ INSERT INTO `token_info` (`id`, `symbol`, `logo`, `price`, `token`, `chain_id`,
 (1004, 'BNB', 'https://dev-v2-backend.pledger.finance/storage/img/BNB.png', '39318136274', '0x0000000000000000000000000000000000000000', '56', 0, '2022-03-09 07:27:27', '2022-03-09 08:31:35', 18),
 (1005, 'PLGR', 'https://dev-v2-backend.pledger.finance/storage/img/PLGR.png', '0', '0x6Aa91CbfE045f9D154050226fCc830ddbA886CED', '56', 0, '2022-03-09 07:27:27', '2022-03-09 07:28:36', 18);
 
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `token_logo_override`
+--
+
+CREATE TABLE `token_logo_override` (
+  `id` int(10) UNSIGNED NOT NULL,
+  `token` varchar(64) NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `symbol` varchar(100) DEFAULT NULL,
+  `decimals` int(11) NOT NULL DEFAULT '18',
+  `logo` varchar(255) NOT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+--
+-- 转存表中的数据 `token_logo_override`
+--
+
+INSERT INTO `token_logo_override` (`id`, `token`, `chain_id`, `symbol`, `decimals`, `logo`, `created_at`, `updated_at`) VALUES
+(1, '0x0000000000000000000000000000000000000000', '97', 'BNB', 18, 'storage/img/BNB.png', '2022-03-09 07:27:27', '2022-03-09 07:27:27'),
+(2, '0x0000000000000000000000000000000000000000', '56', 'BNB', 18, 'storage/img/BNB.png', '2022-03-09 07:27:27', '2022-03-09 07:27:27'),
+(3, '0xB5514a4FA9dDBb48C3DE215Bc9e52d9fCe2D8658', '97', 'BTC', 8, 'storage/img/BTC.png', '2022-03-09 07:27:27', '2022-03-09 07:27:27'),
+(4, '0x7130d2A12B9BCbFAe4f2634d864A1Ee1Ce3Ead9c', '56', 'BTC', 8, 'storage/img/BTC.png', '2022-03-09 07:27:27', '2022-03-09 07:27:27'),
+(5, '0xE676Dcd74f44023b95E0E2C6436C97991A7497DA', '97', 'BUSD', 18, 'storage/img/BUSD.png', '2022-03-09 07:27:27', '2022-03-09 07:27:27'),
+(6, '0xe9e7CEA3DedcA5984780Bafc599bD69ADd087D56', '56', 'BUSD', 18, 'storage/img/BUSD.png', '2022-03-09 07:27:27', '2022-03-09 07:27:27'),
+(7, '0x490BC3FCc845d37C1686044Cd2d6589585DE9B8B', '97', 'DAI', 18, 'storage/img/DAI.png', '2022-03-09 07:27:27', '2022-03-09 07:27:27'),
+(8, '0x1AF3F329e8BE154074D8769D1FFa4eE058B1DBc3', '56', 'DAI', 18, 'storage/img/DAI.png', '2022-03-09 07:27:27', '2022-03-09 07:27:27'),
+(9, '0x2170ed0880ac9a755fd29b2688956bd959f933f8', '56', 'ETH', 18, 'storage/img/ETH.png', '2022-03-09 07:27:27', '2022-03-09 07:27:27'),
+(10, '0x55d398326f99059ff775485246999027b3197955', '56', 'USDT', 18, 'storage/img/USDT.png', '2022-03-09 07:27:27', '2022-03-09 07:27:27'),
+(11, '0xEAEd08168a2D34Ae2B9ea1c1f920E0BC00F9fA67', '97', 'CAKE', 18, 'storage/img/CAKE.png', '2022-03-09 07:27:27', '2022-03-09 07:27:27'),
+(12, '0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82', '56', 'CAKE', 18, 'storage/img/CAKE.png', '2022-03-09 07:27:27', '2022-03-09 07:27:27'),
+(13, '0x6Aa91CbfE045f9D154050226fCc830ddbA886CED', '56', 'PLGR', 18, 'storage/img/PLGR.png', '2022-03-09 07:27:27', '2022-03-09 07:27:27');
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `token_list_version`
+--
+
+CREATE TABLE `token_list_version` (
+  `id` int(10) UNSIGNED NOT NULL,
+  `chain_id` int(11) NOT NULL,
+  `major` int(11) NOT NULL,
+  `minor` int(11) NOT NULL,
+  `patch` int(11) NOT NULL,
+  `hash` varchar(64) NOT NULL,
+  `tokens` mediumtext,
+  `added` text,
+  `removed` text,
+  `changed` text,
+  `created_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `search_term`
+--
+
+CREATE TABLE `search_term` (
+  `id` int(10) UNSIGNED NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `term` varchar(64) NOT NULL,
+  `token` varchar(64) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `pool_snapshots`
+--
+
+CREATE TABLE `pool_snapshots` (
+  `id` int(10) UNSIGNED NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `pool_id` int(11) NOT NULL,
+  `state` varchar(16) NOT NULL DEFAULT '',
+  `lend_supply` varchar(80) NOT NULL DEFAULT '',
+  `borrow_supply` varchar(80) NOT NULL DEFAULT '',
+  `settle_amount_lend` varchar(80) NOT NULL DEFAULT '',
+  `settle_amount_borrow` varchar(80) NOT NULL DEFAULT '',
+  `snapshot_at` bigint(20) NOT NULL,
+  `block_number` bigint(20) UNSIGNED NOT NULL DEFAULT '0',
+  `created_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `daily_reports`
+--
+
+CREATE TABLE `daily_reports` (
+  `id` int(10) UNSIGNED NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `report_date` varchar(10) NOT NULL,
+  `new_lend_deposit` varchar(80) NOT NULL DEFAULT '0',
+  `new_borrow_deposit` varchar(80) NOT NULL DEFAULT '0',
+  `settled_pools` int(11) NOT NULL DEFAULT '0',
+  `undone_pools` int(11) NOT NULL DEFAULT '0',
+  `liquidated_pools` int(11) NOT NULL DEFAULT '0',
+  `lend_fee_revenue` varchar(80) NOT NULL DEFAULT '0',
+  `borrow_fee_revenue` varchar(80) NOT NULL DEFAULT '0',
+  `tvl` varchar(80) NOT NULL DEFAULT '0',
+  `tvl_change` varchar(80) NOT NULL DEFAULT '0',
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `pool_events`
+--
+
+CREATE TABLE `pool_events` (
+  `id` int(10) UNSIGNED NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `pool_id` int(11) NOT NULL,
+  `event` varchar(32) NOT NULL,
+  `address` varchar(64) NOT NULL,
+  `token` varchar(64) NOT NULL,
+  `amount` decimal(65,0) NOT NULL,
+  `block_number` bigint(20) UNSIGNED NOT NULL,
+  `block_time` bigint(20) NOT NULL DEFAULT '0',
+  `block_hash` varchar(80) DEFAULT NULL,
+  `tx_hash` varchar(80) NOT NULL,
+  `log_index` int(10) UNSIGNED NOT NULL,
+  `pending` tinyint(1) NOT NULL DEFAULT '0',
+  `created_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `event_cursor`
+--
+
+CREATE TABLE `event_cursor` (
+  `id` int(10) UNSIGNED NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `contract` varchar(64) NOT NULL,
+  `block_number` bigint(20) UNSIGNED NOT NULL,
+  `block_time` bigint(20) NOT NULL DEFAULT '0',
+  `updated_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `token_price_history`
+--
+
+CREATE TABLE `token_price_history` (
+  `id` int(10) UNSIGNED NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `token` varchar(64) NOT NULL,
+  `price` varchar(255) NOT NULL DEFAULT '0',
+  `price_at` bigint(20) NOT NULL,
+  `created_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `multi_sign_history`
+--
+
+CREATE TABLE `multi_sign_history` (
+  `id` int(11) NOT NULL,
+  `chain_id` int(11) NOT NULL,
+  `version` int(11) NOT NULL,
+  `config` text,
+  `diff` text,
+  `operator` varchar(64) DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `price_quarantine`
+--
+
+CREATE TABLE `price_quarantine` (
+  `id` int(11) NOT NULL,
+  `token` varchar(64) NOT NULL,
+  `symbol` varchar(100) DEFAULT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `price` varchar(80) NOT NULL DEFAULT '0',
+  `last_price` varchar(80) NOT NULL DEFAULT '0',
+  `reason` varchar(255) DEFAULT NULL,
+  `status` varchar(16) NOT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `indexed_blocks`
+--
+
+CREATE TABLE `indexed_blocks` (
+  `id` bigint(20) NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `contract` varchar(64) NOT NULL,
+  `block_number` bigint(20) UNSIGNED NOT NULL,
+  `block_hash` varchar(80) NOT NULL,
+  `created_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `alert_history`
+--
+
+CREATE TABLE `alert_history` (
+  `id` bigint(20) NOT NULL,
+  `kind` varchar(32) NOT NULL,
+  `chain_id` varchar(16) DEFAULT NULL,
+  `target` varchar(64) NOT NULL,
+  `level` bigint(20) NOT NULL DEFAULT '0',
+  `consecutive` bigint(20) NOT NULL DEFAULT '0',
+  `message` text,
+  `error` text,
+  `created_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `gas_spend`
+--
+
+CREATE TABLE `gas_spend` (
+  `id` bigint(20) NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `purpose` varchar(32) NOT NULL,
+  `tx_hash` varchar(66) NOT NULL,
+  `from_address` varchar(42) NOT NULL,
+  `to_address` varchar(42) DEFAULT NULL,
+  `nonce` bigint(20) UNSIGNED NOT NULL DEFAULT '0',
+  `gas_limit` bigint(20) UNSIGNED NOT NULL DEFAULT '0',
+  `gas_price` decimal(65,0) NOT NULL DEFAULT '0',
+  `gas_used` bigint(20) UNSIGNED NOT NULL DEFAULT '0',
+  `fee_wei` decimal(65,0) NOT NULL DEFAULT '0',
+  `block_number` bigint(20) UNSIGNED NOT NULL DEFAULT '0',
+  `status` varchar(16) NOT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `chain_health`
+--
+
+CREATE TABLE `chain_health` (
+  `id` bigint(20) NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `url` varchar(255) NOT NULL,
+  `is_primary` tinyint(1) NOT NULL DEFAULT '0',
+  `healthy` tinyint(1) NOT NULL DEFAULT '0',
+  `latency_ms` bigint(20) NOT NULL DEFAULT '0',
+  `block_number` bigint(20) UNSIGNED NOT NULL DEFAULT '0',
+  `reference_block` bigint(20) UNSIGNED NOT NULL DEFAULT '0',
+  `block_lag` bigint(20) UNSIGNED NOT NULL DEFAULT '0',
+  `error` text,
+  `checked_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `job_runs`
+--
+
+CREATE TABLE `job_runs` (
+  `id` bigint(20) NOT NULL,
+  `name` varchar(64) NOT NULL,
+  `status` varchar(16) NOT NULL,
+  `error` text,
+  `processed` bigint(20) NOT NULL DEFAULT '0',
+  `failed` bigint(20) NOT NULL DEFAULT '0',
+  `duration_ms` bigint(20) NOT NULL DEFAULT '0',
+  `started_at` datetime DEFAULT NULL,
+  `finished_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `job_retries`
+--
+
+CREATE TABLE `job_retries` (
+  `id` bigint(20) NOT NULL,
+  `job` varchar(64) NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `item` varchar(128) NOT NULL,
+  `status` varchar(16) NOT NULL,
+  `attempts` bigint(20) NOT NULL DEFAULT '0',
+  `last_error` text,
+  `next_retry_at` datetime DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `pool_archives`
+--
+
+CREATE TABLE `pool_archives` (
+  `id` bigint(20) NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `pool_id` bigint(20) NOT NULL,
+  `state` varchar(100) DEFAULT NULL,
+  `end_time` varchar(100) DEFAULT NULL,
+  `pool_base` text,
+  `pool_data` text,
+  `archived_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `pool_metadata`
+--
+
+CREATE TABLE `pool_metadata` (
+  `id` int(11) NOT NULL,
+  `chain_id` int(11) NOT NULL,
+  `pool_id` int(11) NOT NULL,
+  `name` varchar(64) DEFAULT NULL,
+  `description` text,
+  `tags` varchar(512) DEFAULT NULL,
+  `featured` tinyint(1) NOT NULL DEFAULT '0',
+  `updated_by` varchar(64) DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `keeper_tx`
+--
+
+CREATE TABLE `keeper_tx` (
+  `id` bigint(20) NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `pool_id` bigint(20) NOT NULL,
+  `action` varchar(16) NOT NULL,
+  `tx_hash` varchar(66) DEFAULT NULL,
+  `from_address` varchar(42) DEFAULT NULL,
+  `nonce` bigint(20) UNSIGNED NOT NULL DEFAULT '0',
+  `gas_limit` bigint(20) UNSIGNED NOT NULL DEFAULT '0',
+  `gas_price` decimal(65,0) NOT NULL DEFAULT '0',
+  `gas_used` bigint(20) UNSIGNED NOT NULL DEFAULT '0',
+  `block_number` bigint(20) UNSIGNED NOT NULL DEFAULT '0',
+  `status` varchar(16) NOT NULL,
+  `error` varchar(512) DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `email_subscriptions`
+--
+
+CREATE TABLE `email_subscriptions` (
+  `id` bigint(20) NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `address` varchar(42) NOT NULL,
+  `email` varchar(255) NOT NULL,
+  `pool_id` bigint(20) NOT NULL DEFAULT '0',
+  `events` varchar(64) DEFAULT NULL,
+  `token` varchar(64) NOT NULL,
+  `expires_at` bigint(20) NOT NULL DEFAULT '0',
+  `verified_at` datetime DEFAULT NULL,
+  `verified_state` varchar(8) DEFAULT NULL,
+  `language` varchar(16) DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `email_notifications`
+--
+
+CREATE TABLE `email_notifications` (
+  `id` bigint(20) NOT NULL,
+  `subscription_id` bigint(20) NOT NULL,
+  `event` varchar(16) NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `pool_id` bigint(20) NOT NULL,
+  `status` varchar(16) NOT NULL,
+  `attempts` bigint(20) NOT NULL DEFAULT '0',
+  `error` varchar(512) DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `fee_revenues`
+--
+
+CREATE TABLE `fee_revenues` (
+  `id` bigint(20) NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `pool_id` bigint(20) NOT NULL,
+  `event` varchar(16) NOT NULL,
+  `lend_token` varchar(42) DEFAULT NULL,
+  `borrow_token` varchar(42) DEFAULT NULL,
+  `lend_fee_amount` decimal(65,0) NOT NULL DEFAULT '0',
+  `borrow_fee_amount` decimal(65,0) NOT NULL DEFAULT '0',
+  `lend_fee_usd` decimal(65,0) NOT NULL DEFAULT '0',
+  `borrow_fee_usd` decimal(65,0) NOT NULL DEFAULT '0',
+  `total_usd` decimal(65,0) NOT NULL DEFAULT '0',
+  `accrued_at` bigint(20) NOT NULL,
+  `created_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `referral_codes`
+--
+
+CREATE TABLE `referral_codes` (
+  `id` bigint(20) NOT NULL,
+  `address` varchar(42) NOT NULL,
+  `code` varchar(16) NOT NULL,
+  `created_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `referral_attributions`
+--
+
+CREATE TABLE `referral_attributions` (
+  `id` bigint(20) NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `tx_hash` varchar(80) NOT NULL,
+  `code` varchar(16) NOT NULL,
+  `referrer` varchar(42) NOT NULL,
+  `referee` varchar(42) NOT NULL,
+  `pool_id` bigint(20) NOT NULL,
+  `event` varchar(32) NOT NULL,
+  `token` varchar(64) NOT NULL,
+  `amount` decimal(65,0) NOT NULL DEFAULT '0',
+  `block_time` bigint(20) NOT NULL DEFAULT '0',
+  `status` varchar(16) NOT NULL,
+  `reason` varchar(32) DEFAULT NULL,
+  `verified_at` datetime DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- --------------------------------------------------------
+
+--
+-- 表的结构 `price_backfills`
+--
+
+CREATE TABLE `price_backfills` (
+  `id` bigint(20) NOT NULL,
+  `chain_id` varchar(16) NOT NULL,
+  `token` varchar(64) NOT NULL,
+  `from_block` bigint(20) UNSIGNED NOT NULL,
+  `to_block` bigint(20) UNSIGNED NOT NULL,
+  `next_block` bigint(20) UNSIGNED NOT NULL,
+  `last_price` varchar(80) DEFAULT NULL,
+  `inserted` bigint(20) NOT NULL DEFAULT '0',
+  `status` varchar(16) NOT NULL,
+  `error` varchar(512) DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
 --
 -- 转储表的索引
 --
@@ -391,20 +899,218 @@ ALTER TABLE `multi_sign`
 -- 表的索引 `poolbases`
 --
 ALTER TABLE `poolbases`
-  ADD PRIMARY KEY (`id`) USING BTREE;
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD KEY `idx_chain_state` (`chain_id`,`state`),
+  ADD KEY `idx_chain_lend_token` (`chain_id`,`lend_token`),
+  ADD KEY `idx_chain_borrow_token` (`chain_id`,`borrow_token`),
+  ADD KEY `idx_chain_updated` (`chain_id`,`updated_at`),
+  ADD KEY `idx_poolbases_archived_at` (`archived_at`);
 
 --
 -- 表的索引 `pooldata`
 --
 ALTER TABLE `pooldata`
-  ADD PRIMARY KEY (`id`) USING BTREE;
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD KEY `idx_chain_updated` (`chain_id`,`updated_at`);
 
 --
 -- 表的索引 `token_info`
 --
 ALTER TABLE `token_info`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD KEY `idx_chain_updated` (`chain_id`,`updated_at`);
+
+--
+-- 表的索引 `token_logo_override`
+--
+ALTER TABLE `token_logo_override`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `uk_token_chain` (`token`,`chain_id`);
+
+--
+-- 表的索引 `search_term`
+--
+ALTER TABLE `search_term`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD KEY `idx_chain_term` (`chain_id`,`term`);
+
+--
+-- 表的索引 `token_list_version`
+--
+ALTER TABLE `token_list_version`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `uk_chain_version` (`chain_id`,`major`,`minor`,`patch`);
+
+--
+-- 表的索引 `pool_snapshots`
+--
+ALTER TABLE `pool_snapshots`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD KEY `idx_chain_pool_time` (`chain_id`,`pool_id`,`snapshot_at`);
+
+--
+-- 表的索引 `daily_reports`
+--
+ALTER TABLE `daily_reports`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `uk_chain_date` (`chain_id`,`report_date`);
+
+--
+-- 表的索引 `pool_events`
+--
+ALTER TABLE `pool_events`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `uk_chain_tx_log` (`chain_id`,`tx_hash`,`log_index`),
+  ADD KEY `idx_chain_pool_event` (`chain_id`,`pool_id`,`event`),
+  ADD KEY `idx_pool_events_block_time` (`block_time`);
+
+--
+-- 表的索引 `event_cursor`
+--
+ALTER TABLE `event_cursor`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `uk_chain_contract` (`chain_id`,`contract`),
+  ADD KEY `idx_event_cursor_block_time` (`block_time`);
+
+--
+-- 表的索引 `token_price_history`
+--
+ALTER TABLE `token_price_history`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD KEY `idx_chain_token_time` (`chain_id`,`token`,`price_at`),
+  ADD KEY `idx_chain_time` (`chain_id`,`price_at`);
+
+--
+-- 表的索引 `multi_sign_history`
+--
+ALTER TABLE `multi_sign_history`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `uk_chain_version` (`chain_id`,`version`);
+
+--
+-- 表的索引 `price_quarantine`
+--
+ALTER TABLE `price_quarantine`
   ADD PRIMARY KEY (`id`) USING BTREE;
 
+--
+-- 表的索引 `indexed_blocks`
+--
+ALTER TABLE `indexed_blocks`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `uk_chain_contract_block` (`chain_id`,`contract`,`block_number`);
+
+--
+-- 表的索引 `alert_history`
+--
+ALTER TABLE `alert_history`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD KEY `idx_kind_target` (`kind`,`target`);
+
+--
+-- 表的索引 `gas_spend`
+--
+ALTER TABLE `gas_spend`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `idx_gas_spend_tx_hash` (`tx_hash`),
+  ADD KEY `idx_chain_created` (`chain_id`,`created_at`),
+  ADD KEY `idx_gas_spend_status` (`status`);
+
+--
+-- 表的索引 `chain_health`
+--
+ALTER TABLE `chain_health`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `uk_chain_url` (`chain_id`,`url`);
+
+--
+-- 表的索引 `job_runs`
+--
+ALTER TABLE `job_runs`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD KEY `idx_name_started` (`name`,`started_at`),
+  ADD KEY `idx_job_runs_started_at` (`started_at`);
+
+--
+-- 表的索引 `job_retries`
+--
+ALTER TABLE `job_retries`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `uk_job_item` (`job`,`chain_id`,`item`),
+  ADD KEY `idx_status_next` (`status`,`next_retry_at`);
+
+--
+-- 表的索引 `pool_archives`
+--
+ALTER TABLE `pool_archives`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `uk_chain_pool` (`chain_id`,`pool_id`);
+
+--
+-- 表的索引 `pool_metadata`
+--
+ALTER TABLE `pool_metadata`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `uk_chain_pool` (`chain_id`,`pool_id`);
+
+--
+-- 表的索引 `keeper_tx`
+--
+ALTER TABLE `keeper_tx`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD KEY `idx_chain_pool_action` (`chain_id`,`pool_id`,`action`),
+  ADD KEY `idx_keeper_tx_tx_hash` (`tx_hash`),
+  ADD KEY `idx_keeper_tx_status` (`status`);
+
+--
+-- 表的索引 `email_subscriptions`
+--
+ALTER TABLE `email_subscriptions`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `uk_chain_address_email_pool` (`chain_id`,`address`,`email`,`pool_id`),
+  ADD UNIQUE KEY `idx_email_subscriptions_token` (`token`),
+  ADD KEY `idx_email_subscriptions_verified_at` (`verified_at`);
+
+--
+-- 表的索引 `email_notifications`
+--
+ALTER TABLE `email_notifications`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `uk_subscription_event` (`subscription_id`,`event`);
+
+--
+-- 表的索引 `fee_revenues`
+--
+ALTER TABLE `fee_revenues`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `uk_chain_pool` (`chain_id`,`pool_id`),
+  ADD KEY `idx_chain_accrued` (`chain_id`,`accrued_at`);
+
+--
+-- 表的索引 `referral_codes`
+--
+ALTER TABLE `referral_codes`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `idx_referral_codes_address` (`address`),
+  ADD UNIQUE KEY `idx_referral_codes_code` (`code`);
+
+--
+-- 表的索引 `referral_attributions`
+--
+ALTER TABLE `referral_attributions`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `uk_chain_tx` (`chain_id`,`tx_hash`),
+  ADD KEY `idx_chain_referrer` (`chain_id`,`referrer`),
+  ADD KEY `idx_referral_attributions_status` (`status`);
+
+--
+-- 表的索引 `price_backfills`
+--
+ALTER TABLE `price_backfills`
+  ADD PRIMARY KEY (`id`) USING BTREE,
+  ADD UNIQUE KEY `uk_chain_token` (`chain_id`,`token`),
+  ADD KEY `idx_price_backfills_status` (`status`);
+
 --
 -- 在导出的表使用AUTO_INCREMENT
 --
@@ -438,6 +1144,156 @@ ALTER TABLE `pooldata`
 --
 ALTER TABLE `token_info`
   MODIFY `id` int(10) UNSIGNED NOT NULL AUTO_INCREMENT, AUTO_INCREMENT=1006;
+
+--
+-- 使用表AUTO_INCREMENT `token_logo_override`
+--
+ALTER TABLE `token_logo_override`
+  MODIFY `id` int(10) UNSIGNED NOT NULL AUTO_INCREMENT, AUTO_INCREMENT=14;
+
+--
+-- 使用表AUTO_INCREMENT `search_term`
+--
+ALTER TABLE `search_term`
+  MODIFY `id` int(10) UNSIGNED NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `token_list_version`
+--
+ALTER TABLE `token_list_version`
+  MODIFY `id` int(10) UNSIGNED NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `pool_snapshots`
+--
+ALTER TABLE `pool_snapshots`
+  MODIFY `id` int(10) UNSIGNED NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `daily_reports`
+--
+ALTER TABLE `daily_reports`
+  MODIFY `id` int(10) UNSIGNED NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `pool_events`
+--
+ALTER TABLE `pool_events`
+  MODIFY `id` int(10) UNSIGNED NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `event_cursor`
+--
+ALTER TABLE `event_cursor`
+  MODIFY `id` int(10) UNSIGNED NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `token_price_history`
+--
+ALTER TABLE `token_price_history`
+  MODIFY `id` int(10) UNSIGNED NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `multi_sign_history`
+--
+ALTER TABLE `multi_sign_history`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `price_quarantine`
+--
+ALTER TABLE `price_quarantine`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `indexed_blocks`
+--
+ALTER TABLE `indexed_blocks`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `alert_history`
+--
+ALTER TABLE `alert_history`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `gas_spend`
+--
+ALTER TABLE `gas_spend`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `chain_health`
+--
+ALTER TABLE `chain_health`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `job_runs`
+--
+ALTER TABLE `job_runs`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `job_retries`
+--
+ALTER TABLE `job_retries`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `pool_archives`
+--
+ALTER TABLE `pool_archives`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `pool_metadata`
+--
+ALTER TABLE `pool_metadata`
+  MODIFY `id` int(11) NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `keeper_tx`
+--
+ALTER TABLE `keeper_tx`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `email_subscriptions`
+--
+ALTER TABLE `email_subscriptions`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `email_notifications`
+--
+ALTER TABLE `email_notifications`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `fee_revenues`
+--
+ALTER TABLE `fee_revenues`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `referral_codes`
+--
+ALTER TABLE `referral_codes`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `referral_attributions`
+--
+ALTER TABLE `referral_attributions`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT;
+
+--
+-- 使用表AUTO_INCREMENT `price_backfills`
+--
+ALTER TABLE `price_backfills`
+  MODIFY `id` bigint(20) NOT NULL AUTO_INCREMENT;
 COMMIT;
 
 /*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
@@ -1,21 +1,23 @@
 package db
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/gomodule/redigo/redis"
 	"pledge-backend/config"
 	"pledge-backend/log"
+	"pledge-backend/telemetry"
 	"time"
 )
 
-// InitRedis 初始化Redis
-func InitRedis() *redis.Pool {
+// InitRedis 初始化Redis，连接失败时返回错误，由 WaitDependencies 重试
+func InitRedis() error {
 	log.Logger.Info("Init Redis")
-	redisConf := config.Config.Redis
+	redisConf := config.Config().Redis
 	// 建立连接池
-	RedisConn = &redis.Pool{
+	pool := &redis.Pool{
 		MaxIdle:     10,   // 最大的空闲连接数，表示即使没有redis连接时依然可以保持N个空闲的连接，而不被清除，随时处于待命状态。
 		MaxActive:   0,    // 最大的激活连接数，表示同时最多有N个连接   0 表示无穷大
 		Wait:        true, // 如果连接数不足则阻塞等待
@@ -28,25 +30,38 @@ func InitRedis() *redis.Pool {
 			// 验证密码
 			_, err = c.Do("auth", redisConf.Password)
 			if err != nil {
-				panic("redis auth err " + err.Error())
+				_ = c.Close()
+				return nil, errors.New("redis auth err " + err.Error())
 			}
 			// 选择db
 			_, err = c.Do("select", redisConf.Db)
 			if err != nil {
-				panic("redis select db err " + err.Error())
+				_ = c.Close()
+				return nil, errors.New("redis select db err " + err.Error())
 			}
-			return c, nil
+			// 链路追踪: 通过 redis.DoContext 调用时记录 span
+			return telemetry.RedisConn(c), nil
 		},
 	}
-	err := RedisConn.Get().Err()
-	if err != nil {
-		panic("redis init err " + err.Error())
+	conn := pool.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+	if _, err := conn.Do("ping"); err != nil {
+		_ = pool.Close()
+		return errors.New("redis init err " + err.Error())
 	}
-	return RedisConn
+	RedisConn = pool
+	return nil
 }
 
 // RedisSet 设置key、value、time
 func RedisSet(key string, data interface{}, aliveSeconds int) error {
+	return RedisSetContext(context.Background(), key, data, aliveSeconds)
+}
+
+// RedisSetContext 同 RedisSet，ctx 用于链路追踪
+func RedisSetContext(ctx context.Context, key string, data interface{}, aliveSeconds int) error {
 	conn := RedisConn.Get()
 	defer func() {
 		_ = conn.Close()
@@ -56,9 +71,9 @@ func RedisSet(key string, data interface{}, aliveSeconds int) error {
 		return err
 	}
 	if aliveSeconds > 0 {
-		_, err = conn.Do("set", key, value, "EX", aliveSeconds)
+		_, err = redis.DoContext(conn, ctx, "set", key, value, "EX", aliveSeconds)
 	} else {
-		_, err = conn.Do("set", key, value)
+		_, err = redis.DoContext(conn, ctx, "set", key, value)
 	}
 	if err != nil {
 		return err
@@ -86,11 +101,16 @@ func RedisSetString(key string, data string, aliveSeconds int) error {
 
 // RedisGet 获取Key
 func RedisGet(key string) ([]byte, error) {
+	return RedisGetContext(context.Background(), key)
+}
+
+// RedisGetContext 同 RedisGet，ctx 用于链路追踪
+func RedisGetContext(ctx context.Context, key string) ([]byte, error) {
 	conn := RedisConn.Get()
 	defer func() {
 		_ = conn.Close()
 	}()
-	reply, err := redis.Bytes(conn.Do("get", key))
+	reply, err := redis.Bytes(redis.DoContext(conn, ctx, "get", key))
 	if err != nil {
 		return nil, err
 	}
@@ -346,3 +366,113 @@ func RedisDelList(setName string) error {
 	_, err := conn.Do("del", setName)
 	return err
 }
+
+// RedisZAdd 有序集合添加元素
+func RedisZAdd(key string, score int64, member string) error {
+	conn := RedisConn.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+	_, err := conn.Do("zadd", key, score, member)
+	return err
+}
+
+// RedisZRangeByScore 按分数区间取出有序集合元素
+func RedisZRangeByScore(key string, min, max int64) ([]string, error) {
+	conn := RedisConn.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+	return redis.Strings(conn.Do("zrangebyscore", key, min, max))
+}
+
+// RedisZRemRangeByScore 按分数区间删除有序集合元素
+func RedisZRemRangeByScore(key string, min, max int64) error {
+	conn := RedisConn.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+	_, err := conn.Do("zremrangebyscore", key, min, max)
+	return err
+}
+
+// RedisPing 检查 Redis 连接
+func RedisPing() error {
+	conn := RedisConn.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+	_, err := conn.Do("ping")
+	return err
+}
+
+// RedisListTrim 只保留列表中指定区间的元素
+func RedisListTrim(listName string, start, stop int) error {
+	conn := RedisConn.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+	_, err := conn.Do("ltrim", listName, start, stop)
+	return err
+}
+
+// RedisIncrExpire 计数加一，首次计数时设置过期时间，返回当前计数
+func RedisIncrExpire(key string, aliveSeconds int) (int64, error) {
+	conn := RedisConn.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+	count, err := redis.Int64(conn.Do("incr", key))
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 && aliveSeconds > 0 {
+		_, err = conn.Do("expire", key, aliveSeconds)
+	}
+	return count, err
+}
+
+// RedisSetNX key 不存在时设置并返回 true，用于分布式锁和选主
+func RedisSetNX(key, value string, aliveSeconds int) (bool, error) {
+	conn := RedisConn.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+	reply, err := redis.String(conn.Do("set", key, value, "ex", aliveSeconds, "nx"))
+	if err == redis.ErrNil {
+		return false, nil
+	}
+	return reply == "OK", err
+}
+
+// expireIfEqualScript 值等于 ARGV[1] 时续期，避免续期别人持有的锁
+var expireIfEqualScript = redis.NewScript(1, `
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("expire", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// RedisExpireIfEqual key 的值等于 value 时重新设置过期时间并返回 true
+func RedisExpireIfEqual(key, value string, aliveSeconds int) (bool, error) {
+	conn := RedisConn.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+	return redis.Bool(expireIfEqualScript.Do(conn, key, value, aliveSeconds))
+}
+
+// deleteIfEqualScript 值等于 ARGV[1] 时删除，避免删除已被替换的值
+var deleteIfEqualScript = redis.NewScript(1, `
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0`)
+
+// RedisDeleteIfEqual key 的值等于 value 时删除并返回 true，用于一次性的令牌
+func RedisDeleteIfEqual(key, value string) (bool, error) {
+	conn := RedisConn.Get()
+	defer func() {
+		_ = conn.Close()
+	}()
+	return redis.Bool(deleteIfEqualScript.Do(conn, key, value))
+}
@@ -0,0 +1,277 @@
+/*
+ * ==================================================================================
+ * seed.go - 本地开发和集成测试的数据填充
+ * ==================================================================================
+ *
+ * 【核心功能】
+ * 从 YAML fixture 载入代币、示例池子和管理员账号，代替生产库的 dump:
+ * - token_info.yaml: 代币 (地址、符号、精度、价格)
+ * - pools.yaml: 池子基础信息 (poolbases) 和动态数据 (pooldata)
+ * - admin.yaml: 管理员账号，密码以 bcrypt 哈希保存
+ *
+ * fixture 编译进二进制，部署后不依赖源码目录；--dir 指定目录时从该目录读取
+ * 所有写入都按业务主键新增或更新，可重复执行
+ *
+ * 【调用关系】
+ * pledge seed (cmd/seed.go) --> Load() --> schedule/models、api/models
+ * ==================================================================================
+ */
+
+package seed
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	apiModels "pledge-backend/api/models"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Token token_info.yaml 中的一个代币
+type Token struct {
+	ChainId     string `yaml:"chain_id"`
+	Token       string `yaml:"token"`
+	Symbol      string `yaml:"symbol"`
+	Name        string `yaml:"name"`
+	Decimals    int    `yaml:"decimals"`
+	Price       string `yaml:"price"` // 1e8 精度
+	Logo        string `yaml:"logo"`
+	CoingeckoId string `yaml:"coingecko_id"`
+	PriceSource string `yaml:"price_source"`
+}
+
+// Pool pools.yaml 中的一个池子，代币详情 JSON 按 token_info 生成，与 schedule 同步时的格式一致
+type Pool struct {
+	ChainId                string   `yaml:"chain_id"`
+	PoolId                 int      `yaml:"pool_id"`
+	State                  string   `yaml:"state"`
+	SettleTime             string   `yaml:"settle_time"`
+	EndTime                string   `yaml:"end_time"`
+	InterestRate           string   `yaml:"interest_rate"`
+	MaxSupply              string   `yaml:"max_supply"`
+	LendSupply             string   `yaml:"lend_supply"`
+	BorrowSupply           string   `yaml:"borrow_supply"`
+	MartgageRate           string   `yaml:"martgage_rate"`
+	LendToken              string   `yaml:"lend_token"`
+	BorrowToken            string   `yaml:"borrow_token"`
+	LendFee                string   `yaml:"lend_fee"`
+	BorrowFee              string   `yaml:"borrow_fee"`
+	SpCoin                 string   `yaml:"sp_coin"`
+	JpCoin                 string   `yaml:"jp_coin"`
+	AutoLiquidateThreshold string   `yaml:"auto_liquidate_threshold"`
+	Data                   PoolData `yaml:"data"`
+}
+
+// PoolData 池子的结算、完成、清算金额
+type PoolData struct {
+	SettleAmountLend       string `yaml:"settle_amount_lend"`
+	SettleAmountBorrow     string `yaml:"settle_amount_borrow"`
+	FinishAmountLend       string `yaml:"finish_amount_lend"`
+	FinishAmountBorrow     string `yaml:"finish_amount_borrow"`
+	LiquidationAmounLend   string `yaml:"liquidation_amoun_lend"`
+	LiquidationAmounBorrow string `yaml:"liquidation_amoun_borrow"`
+}
+
+// Admin admin.yaml 中的管理员账号，password 为明文，写入前哈希
+type Admin struct {
+	Name     string `yaml:"name"`
+	Password string `yaml:"password"`
+}
+
+//go:embed fixtures/*.yaml
+var embedded embed.FS
+
+// Fixtures 可载入的 fixture 名称 (<name>.yaml)，按载入顺序: 池子引用 token_info 中的代币
+var Fixtures = []string{"token_info", "pools", "admin"}
+
+// Dir fixture 目录，dir 为空时使用编译进二进制的 db/seed/fixtures
+func Dir(dir string) (fs.FS, error) {
+	if dir == "" {
+		return fs.Sub(embedded, "fixtures")
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, errors.New("seed dir " + dir + " is not a directory")
+	}
+	return os.DirFS(dir), nil
+}
+
+// Load 按 Fixtures 的顺序载入 names 中的 fixture，fixture 文件不存在时返回错误
+// 例如生产环境只创建管理员: names 为 [admin]，目录中只需要 admin.yaml
+func Load(fsys fs.FS, names []string) error {
+	selected := map[string]bool{}
+	for _, name := range names {
+		if !known(name) {
+			return errors.New("unknown fixture " + name + ", expected " + strings.Join(Fixtures, ", "))
+		}
+		selected[name] = true
+	}
+
+	if selected["token_info"] {
+		tokens, err := Tokens(fsys)
+		if err != nil {
+			return err
+		}
+		if err = SaveTokens(tokens); err != nil {
+			return err
+		}
+	}
+	if selected["pools"] {
+		var pools []Pool
+		if err := readFixture(fsys, "pools", &pools); err != nil {
+			return err
+		}
+		if err := SavePools(pools); err != nil {
+			return err
+		}
+	}
+	if selected["admin"] {
+		var admins []Admin
+		if err := readFixture(fsys, "admin", &admins); err != nil {
+			return err
+		}
+		if err := SaveAdmins(admins); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func known(name string) bool {
+	for _, f := range Fixtures {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+func readFixture(fsys fs.FS, name string, out interface{}) error {
+	content, err := fs.ReadFile(fsys, name+".yaml")
+	if err != nil {
+		return errors.New("seed fixture " + name + ".yaml: " + err.Error())
+	}
+	if err = yaml.UnmarshalStrict(content, out); err != nil {
+		return errors.New("seed fixture " + name + ".yaml: " + err.Error())
+	}
+	return nil
+}
+
+// SaveTokens 写入 token_info
+func SaveTokens(tokens []Token) error {
+	for _, t := range tokens {
+		err := models.NewTokenInfo().SaveTokenInfo(&models.TokenInfo{
+			ChainId:     t.ChainId,
+			Token:       t.Token,
+			Symbol:      t.Symbol,
+			Name:        t.Name,
+			Decimals:    t.Decimals,
+			Price:       t.Price,
+			Logo:        t.Logo,
+			CoingeckoId: t.CoingeckoId,
+			PriceSource: t.PriceSource,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	log.Logger.Sugar().Info("seed token_info ", len(tokens))
+	return nil
+}
+
+// SavePools 写入 poolbases 和 pooldata，池子引用的代币需先在 token_info 中存在才能带上符号和价格
+func SavePools(pools []Pool) error {
+	for _, p := range pools {
+		_, lendToken := models.NewTokenInfo().GetTokenInfo(p.LendToken, p.ChainId)
+		_, borrowToken := models.NewTokenInfo().GetTokenInfo(p.BorrowToken, p.ChainId)
+		lendTokenJson, _ := json.Marshal(models.LendToken{
+			LendFee:    p.LendFee,
+			TokenLogo:  lendToken.Logo,
+			TokenName:  lendToken.Symbol,
+			TokenPrice: lendToken.Price,
+		})
+		borrowTokenJson, _ := json.Marshal(models.BorrowToken{
+			BorrowFee:  p.BorrowFee,
+			TokenLogo:  borrowToken.Logo,
+			TokenName:  borrowToken.Symbol,
+			TokenPrice: borrowToken.Price,
+		})
+
+		poolId := strconv.Itoa(p.PoolId)
+		_, err := models.NewPoolBase().SavePoolWithData(context.Background(), p.ChainId, poolId, &models.PoolBase{
+			PoolId:                 p.PoolId,
+			ChainId:                p.ChainId,
+			SettleTime:             p.SettleTime,
+			EndTime:                p.EndTime,
+			InterestRate:           p.InterestRate,
+			MaxSupply:              p.MaxSupply,
+			LendSupply:             p.LendSupply,
+			BorrowSupply:           p.BorrowSupply,
+			MartgageRate:           p.MartgageRate,
+			LendToken:              p.LendToken,
+			LendTokenInfo:          string(lendTokenJson),
+			BorrowToken:            p.BorrowToken,
+			BorrowTokenInfo:        string(borrowTokenJson),
+			State:                  p.State,
+			SpCoin:                 p.SpCoin,
+			JpCoin:                 p.JpCoin,
+			AutoLiquidateThreshold: p.AutoLiquidateThreshold,
+		}, &models.PoolData{
+			PoolId:                 poolId,
+			ChainId:                p.ChainId,
+			SettleAmountLend:       p.Data.SettleAmountLend,
+			SettleAmountBorrow:     p.Data.SettleAmountBorrow,
+			FinishAmountLend:       p.Data.FinishAmountLend,
+			FinishAmountBorrow:     p.Data.FinishAmountBorrow,
+			LiquidationAmounLend:   p.Data.LiquidationAmounLend,
+			LiquidationAmounBorrow: p.Data.LiquidationAmounBorrow,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	log.Logger.Sugar().Info("seed pools ", len(pools))
+	return nil
+}
+
+// SaveAdmins 写入 admin
+func SaveAdmins(admins []Admin) error {
+	for _, a := range admins {
+		hash, err := utils.HashPassword(a.Password)
+		if err != nil {
+			return err
+		}
+		err = apiModels.NewAdmin().Save(&apiModels.Admin{Name: a.Name, Password: hash})
+		if err != nil {
+			return err
+		}
+	}
+	log.Logger.Sugar().Info("seed admin ", len(admins))
+	return nil
+}
+
+// Tokens 读取 token_info.yaml
+func Tokens(fsys fs.FS) ([]Token, error) {
+	var tokens []Token
+	err := readFixture(fsys, "token_info", &tokens)
+	return tokens, err
+}
+
+// Pools 读取 pools.yaml
+func Pools(fsys fs.FS) ([]Pool, error) {
+	var pools []Pool
+	err := readFixture(fsys, "pools", &pools)
+	return pools, err
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Dependency 启动时需要等待就绪的外部依赖，Connect 为一次连接尝试
+type Dependency struct {
+	Name    string
+	Connect func() error
+}
+
+// WaitDependencies 并行连接所有依赖，失败时按 1, 2, 4 ... 秒 (最长 [startup] max_backoff) 退避重试，
+// 超过 [startup] wait_timeout 仍未就绪时返回包含所有未就绪依赖最后一次错误的汇总错误
+// 容器编排中 MySQL、Redis 或 RPC 节点晚于服务启动时不必依赖启动顺序
+func WaitDependencies(deps ...Dependency) error {
+	timeout := time.Duration(config.Config().Startup.WaitTimeout) * time.Second
+	deadline := time.Now().Add(timeout)
+
+	errs := make([]error, len(deps))
+	var wg sync.WaitGroup
+	for i, dep := range deps {
+		wg.Add(1)
+		go func(i int, dep Dependency) {
+			defer wg.Done()
+			errs[i] = waitFor(dep, deadline)
+		}(i, dep)
+	}
+	wg.Wait()
+
+	failed := make([]string, 0)
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	if len(failed) > 0 {
+		return errors.New("dependencies not ready after " + timeout.String() + ":\n  " + strings.Join(failed, "\n  "))
+	}
+	return nil
+}
+
+// waitFor 重试一个依赖直到成功或到达 deadline
+func waitFor(dep Dependency, deadline time.Time) error {
+	backoff := time.Second
+	maxBackoff := time.Duration(config.Config().Startup.MaxBackoff) * time.Second
+	for attempt := 1; ; attempt++ {
+		err := dep.Connect()
+		if err == nil {
+			if attempt > 1 {
+				log.Logger.Sugar().Info(dep.Name, " ready after ", attempt, " attempts")
+			}
+			return nil
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("%s: %v (%d attempts)", dep.Name, err, attempt)
+		}
+		if backoff > remaining {
+			backoff = remaining
+		}
+		log.Logger.Sugar().Warn(dep.Name, " not ready, retry in ", backoff, ": ", err)
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
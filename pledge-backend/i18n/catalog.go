@@ -0,0 +1,85 @@
+package i18n
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Messages 文案目录，key -> 语言 -> 文本
+// 接口错误消息的 key 为 code.<状态码>，通知模板使用各自的前缀，例如 subscription.verify.subject
+type Messages map[string]map[int]string
+
+var messages = Messages{}
+
+// Add 加入文案，同一 key 和语言已存在时覆盖
+func Add(m Messages) {
+	mu.Lock()
+	defer mu.Unlock()
+	for key, texts := range m {
+		if messages[key] == nil {
+			messages[key] = make(map[int]string, len(texts))
+		}
+		for lang, text := range texts {
+			messages[key][lang] = text
+		}
+	}
+}
+
+// Has 是否有该 key 的文案
+func Has(key string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := messages[key]
+	return ok
+}
+
+// T 指定语言的文案，没有该语言的翻译时使用英文，仍没有时返回 key
+// args 不为空时按 fmt.Sprintf 格式化，翻译中的占位符顺序与英文文案一致
+func T(lang int, key string, args ...interface{}) string {
+	mu.RLock()
+	texts := messages[key]
+	text, ok := texts[lang]
+	if !ok {
+		text, ok = texts[En]
+	}
+	mu.RUnlock()
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// LoadDir 读取目录中的翻译文件，文件名为语言标签，例如 ja.toml、en.toml
+// 文件内容为 "key" = "文本"，例如 "code.1004" = "パラメータが正しくありません"；新的语言标签自动注册，已有的翻译被覆盖
+func LoadDir(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".toml" {
+			continue
+		}
+		texts := make(map[string]string)
+		if _, err = toml.DecodeFile(filepath.Join(dir, file.Name()), &texts); err != nil {
+			return fmt.Errorf("%s: %w", file.Name(), err)
+		}
+		lang := Register(strings.TrimSuffix(file.Name(), ".toml"))
+		m := make(Messages, len(texts))
+		for key, text := range texts {
+			m[key] = map[int]string{lang: text}
+		}
+		Add(m)
+	}
+	return nil
+}
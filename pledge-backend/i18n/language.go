@@ -0,0 +1,138 @@
+package i18n
+
+import (
+	"pledge-backend/config"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 内置语言，值与 statecode.LangZh / LangEn / LangZhTw 一致
+const (
+	Zh   = 111
+	En   = 112
+	ZhTw = 113
+)
+
+var (
+	mu sync.RWMutex
+
+	// tags 语言对应的标签，用于 Content-Language 和翻译文件名
+	tags = map[int]string{Zh: "zh", En: "en", ZhTw: "zh-TW"}
+
+	// ids 标签 (小写) 对应的语言，包括地区和书写系统的别名
+	ids = map[string]int{
+		"en":      En,
+		"zh":      Zh,
+		"zh-cn":   Zh,
+		"zh-sg":   Zh,
+		"zh-hans": Zh,
+		"zh-tw":   ZhTw,
+		"zh-hk":   ZhTw,
+		"zh-mo":   ZhTw,
+		"zh-hant": ZhTw,
+	}
+
+	// nextId 下一个由 Register 分配的语言
+	nextId = 200
+)
+
+// Register 注册语言标签，返回分配的语言；已注册的标签返回原有的语言
+func Register(tag string) int {
+	key := strings.ToLower(tag)
+	mu.Lock()
+	defer mu.Unlock()
+	if id, ok := ids[key]; ok {
+		return id
+	}
+	id := nextId
+	nextId++
+	ids[key] = id
+	tags[id] = tag
+	return id
+}
+
+// Lookup 标签对应的语言，依次去掉末尾的子标签再查找，例如 zh-Hant-TW -> zh-Hant，en-US -> en
+func Lookup(tag string) (int, bool) {
+	key := strings.ToLower(strings.TrimSpace(tag))
+	mu.RLock()
+	defer mu.RUnlock()
+	for key != "" {
+		if id, ok := ids[key]; ok {
+			return id, true
+		}
+		i := strings.LastIndex(key, "-")
+		if i < 0 {
+			break
+		}
+		key = key[:i]
+	}
+	return 0, false
+}
+
+// Tag 语言的标签，未知的语言返回 en
+func Tag(lang int) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if tag, ok := tags[lang]; ok {
+		return tag
+	}
+	return tags[En]
+}
+
+// Languages 内置和已注册的全部语言，按 ID 排序
+func Languages() []int {
+	mu.RLock()
+	defer mu.RUnlock()
+	langs := make([]int, 0, len(tags))
+	for lang := range tags {
+		langs = append(langs, lang)
+	}
+	sort.Ints(langs)
+	return langs
+}
+
+// Default [i18n] default_language 对应的语言，未配置或不支持时为英文
+func Default() int {
+	if id, ok := Lookup(config.Config().I18n.DefaultLanguage); ok {
+		return id
+	}
+	return En
+}
+
+// Parse 按 Accept-Language 的 q 值从高到低选择第一个支持的语言，都不支持时返回 Default()
+// 例如 "zh-TW,zh;q=0.9,en;q=0.8" -> ZhTw，"fr-FR,en;q=0.5" -> En
+func Parse(acceptLanguage string) int {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+	candidates := make([]candidate, 0, 4)
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	for _, c := range candidates {
+		if id, ok := Lookup(c.tag); ok {
+			return id
+		}
+	}
+	return Default()
+}
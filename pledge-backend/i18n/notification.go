@@ -0,0 +1,100 @@
+package i18n
+
+// 用户通知邮件的文案，模板中以 {{call .T "key" 参数...}} 引用
+func init() {
+	Add(Messages{
+		// 订阅验证邮件
+		"subscription.verify.subject": {
+			En:   "Confirm your Pledge pool notifications",
+			Zh:   "确认 Pledge 池子通知订阅",
+			ZhTw: "確認 Pledge 池子通知訂閱",
+		},
+		"subscription.verify.intro": { // 池子 ID, 链 ID
+			En:   "Please confirm that you want to receive Pledge notifications for pool %d on chain %s.",
+			Zh:   "请确认订阅链 %[2]s 上池子 %[1]d 的 Pledge 通知。",
+			ZhTw: "請確認訂閱鏈 %[2]s 上池子 %[1]d 的 Pledge 通知。",
+		},
+		"subscription.verify.confirm": {
+			En:   "Confirm subscription",
+			Zh:   "确认订阅",
+			ZhTw: "確認訂閱",
+		},
+		"subscription.verify.expires": { // 过期时间
+			En:   "expires at %s UTC",
+			Zh:   "%s UTC 前有效",
+			ZhTw: "%s UTC 前有效",
+		},
+		"subscription.verify.ignore": {
+			En:   "If you did not request this, ignore this email or",
+			Zh:   "如果不是您本人操作，请忽略此邮件，或",
+			ZhTw: "如果不是您本人操作，請忽略此郵件，或",
+		},
+		"subscription.verify.cancel": {
+			En:   "cancel the request",
+			Zh:   "取消订阅请求",
+			ZhTw: "取消訂閱請求",
+		},
+
+		// 事件通知邮件
+		"subscription.settled.title": { // 池子 ID
+			En:   "Pledge pool %d has been settled",
+			Zh:   "Pledge 池子 %d 已结算",
+			ZhTw: "Pledge 池子 %d 已結算",
+		},
+		"subscription.settled.detail": {
+			En:   "The pool has left the matching phase. Check the pool page for your matched amounts.",
+			Zh:   "池子已结束匹配阶段，请在池子页面查看您的成交金额。",
+			ZhTw: "池子已結束匹配階段，請在池子頁面查看您的成交金額。",
+		},
+		"subscription.finished.title": {
+			En:   "Pledge pool %d has finished",
+			Zh:   "Pledge 池子 %d 已完成",
+			ZhTw: "Pledge 池子 %d 已完成",
+		},
+		"subscription.finished.detail": {
+			En:   "The pool reached its end time and has been finished on chain.",
+			Zh:   "池子已到期，并已在链上完成。",
+			ZhTw: "池子已到期，並已在鏈上完成。",
+		},
+		"subscription.liquidated.title": {
+			En:   "Pledge pool %d has been liquidated",
+			Zh:   "Pledge 池子 %d 已清算",
+			ZhTw: "Pledge 池子 %d 已清算",
+		},
+		"subscription.liquidated.detail": {
+			En:   "The collateral value fell below the liquidation threshold and the pool has been liquidated on chain.",
+			Zh:   "抵押品价值低于清算阈值，池子已在链上清算。",
+			ZhTw: "抵押品價值低於清算閾值，池子已在鏈上清算。",
+		},
+		"subscription.claimable.title": {
+			En:   "Your funds in Pledge pool %d are ready to claim",
+			Zh:   "您在 Pledge 池子 %d 中的资金已可提取",
+			ZhTw: "您在 Pledge 池子 %d 中的資金已可提取",
+		},
+		"subscription.claimable.detail": {
+			En:   "You can now withdraw your lend or borrow side funds from the pool.",
+			Zh:   "现在可以从池子中提取您的出借或借款资金。",
+			ZhTw: "現在可以從池子中提取您的出借或借款資金。",
+		},
+		"subscription.footer": { // 事件名称
+			En:   "You receive this email because this address subscribed to %s notifications for the pool.",
+			Zh:   "您收到此邮件是因为该地址订阅了池子的「%s」通知。",
+			ZhTw: "您收到此郵件是因為該地址訂閱了池子的「%s」通知。",
+		},
+		"subscription.unsubscribe": {
+			En:   "Unsubscribe",
+			Zh:   "退订",
+			ZhTw: "退訂",
+		},
+
+		// 通用字段和事件名称
+		"subscription.chain":            {En: "Chain", Zh: "链", ZhTw: "鏈"},
+		"subscription.pool":             {En: "Pool", Zh: "池子", ZhTw: "池子"},
+		"subscription.wallet":           {En: "Wallet", Zh: "钱包", ZhTw: "錢包"},
+		"subscription.events":           {En: "Events", Zh: "事件", ZhTw: "事件"},
+		"subscription.event.settled":    {En: "settled", Zh: "已结算", ZhTw: "已結算"},
+		"subscription.event.finished":   {En: "finished", Zh: "已完成", ZhTw: "已完成"},
+		"subscription.event.liquidated": {En: "liquidated", Zh: "已清算", ZhTw: "已清算"},
+		"subscription.event.claimable":  {En: "claimable", Zh: "可提取", ZhTw: "可提取"},
+	})
+}
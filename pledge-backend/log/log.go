@@ -11,6 +11,9 @@ import (
 
 var Logger *zap.Logger
 
+// atomicLevel 日志级别，可在运行时通过 SetLevel 修改
+var atomicLevel = zap.NewAtomicLevel()
+
 func init() {
 
 	//zap 不支持文件归档，如果要支持文件按大小或者时间归档，需要使用lumberjack，lumberjack也是zap官方推荐的。
@@ -38,8 +41,7 @@ func init() {
 		EncodeName:     zapcore.FullNameEncoder,
 	}
 
-	// 设置日志级别
-	atomicLevel := zap.NewAtomicLevel()
+	// 设置日志级别，启动后按 [log] level 修改
 	atomicLevel.SetLevel(zap.InfoLevel)
 
 	core := zapcore.NewCore(
@@ -58,6 +60,16 @@ func init() {
 	Logger = zap.New(core, caller, development, filed)
 }
 
+// SetLevel 修改日志级别: debug / info / warn / error
+func SetLevel(level string) error {
+	return atomicLevel.UnmarshalText([]byte(level))
+}
+
+// Level 当前日志级别
+func Level() string {
+	return atomicLevel.String()
+}
+
 // 获取当前执行文件绝对路径（go run）
 func getCurrentAbPathByCaller() string {
 	var abPath string
@@ -0,0 +1,24 @@
+/*
+ * ==================================================================================
+ * main.go - Pledge 后端统一入口
+ * ==================================================================================
+ *
+ * 【启动命令】
+ * go build -o pledge .
+ * ./pledge api                       启动 HTTP API 服务
+ * ./pledge task                      启动定时任务服务
+ * ./pledge migrate                   创建/更新数据库表
+ * ./pledge sync-pools --chain 97     立即同步一次指定链的借贷池
+ * ./pledge set-price --dry-run       写入 PLGR 价格到链上 Oracle (--dry-run 只模拟)
+ *
+ * 子命令定义见 cmd 包
+ * ==================================================================================
+ */
+
+package main
+
+import "pledge-backend/cmd"
+
+func main() {
+	cmd.Execute()
+}
@@ -0,0 +1,167 @@
+package cluster
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	leaderKey  = "schedule_leader"  // 当前 leader 的实例标识，带租约
+	membersKey = "schedule_members" // 有序集合，成员为实例标识，分数为最近一次心跳时间
+)
+
+var (
+	instanceId string
+	leader     int32 // 1 表示本实例持有 leader 租约
+
+	membersLock sync.RWMutex
+	members     []string // 存活的实例，按标识排序
+)
+
+// Start 加入集群并参与选主，[cluster] enabled 为 false 时不做任何事，本实例视为 leader
+// 第一轮选主同步完成，之后每 1/3 租约续期一次
+func Start() {
+	conf := config.Config().Cluster
+	if !conf.Enabled {
+		return
+	}
+
+	instanceId = conf.InstanceId
+	if instanceId == "" {
+		host, _ := os.Hostname()
+		instanceId = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	log.Logger.Sugar().Info("cluster instance ", instanceId)
+
+	campaign()
+	go func() {
+		ticker := time.NewTicker(time.Duration(conf.LeaseSeconds) * time.Second / 3)
+		defer ticker.Stop()
+		for range ticker.C {
+			campaign()
+		}
+	}()
+}
+
+// campaign 发送心跳、刷新存活实例列表，并获取或续期 leader 租约
+// Redis 不可用时放弃 leader 身份，避免与新 leader 同时写链
+func campaign() {
+	lease := config.Config().Cluster.LeaseSeconds
+	now := time.Now().Unix()
+
+	err := db.RedisZAdd(membersKey, now, instanceId)
+	if err == nil {
+		err = db.RedisZRemRangeByScore(membersKey, 0, now-int64(lease))
+	}
+	if err == nil {
+		var alive []string
+		alive, err = db.RedisZRangeByScore(membersKey, now-int64(lease), now+int64(lease))
+		if err == nil {
+			sort.Strings(alive)
+			membersLock.Lock()
+			members = alive
+			membersLock.Unlock()
+		}
+	}
+	if err != nil {
+		log.Logger.Sugar().Error("cluster heartbeat err ", err)
+	}
+
+	acquired, err := db.RedisSetNX(leaderKey, instanceId, lease)
+	if err == nil && !acquired {
+		acquired, err = db.RedisExpireIfEqual(leaderKey, instanceId, lease)
+	}
+	if err != nil {
+		log.Logger.Sugar().Error("cluster leader lease err ", err)
+		acquired = false
+	}
+
+	was := atomic.SwapInt32(&leader, boolToInt32(acquired)) == 1
+	if acquired && !was {
+		log.Logger.Sugar().Info("cluster instance ", instanceId, " became leader")
+	} else if !acquired && was {
+		log.Logger.Sugar().Warn("cluster instance ", instanceId, " lost leadership")
+	}
+}
+
+// IsLeader 本实例是否为 leader，未开启 [cluster] 时总是 true
+func IsLeader() bool {
+	if !config.Config().Cluster.Enabled {
+		return true
+	}
+	return atomic.LoadInt32(&leader) == 1
+}
+
+// OwnsChain 按链分片的任务 job 是否由本实例处理 chainId
+// 任务不是 run = "shard" 或存活实例未知时返回 true
+func OwnsChain(job, chainId string) bool {
+	index, count, ok := shard(job)
+	if !ok {
+		return true
+	}
+	return int(chainIndex(chainId)%uint32(count)) == index
+}
+
+// ServesPools 按池子分片的任务 job 是否需要同步 chainId 上的池子
+// shard_by = "pool" 时每个实例都同步所有链上的一部分池子
+func ServesPools(job, chainId string) bool {
+	if config.Config().Cluster.ShardBy == "pool" {
+		return true
+	}
+	return OwnsChain(job, chainId)
+}
+
+// OwnsPool 按池子分片的任务 job 是否由本实例同步池子 poolId
+func OwnsPool(job, chainId string, poolId int) bool {
+	if config.Config().Cluster.ShardBy != "pool" {
+		return OwnsChain(job, chainId)
+	}
+	index, count, ok := shard(job)
+	if !ok {
+		return true
+	}
+	return int((chainIndex(chainId)+uint32(poolId))%uint32(count)) == index
+}
+
+// shard 本实例在存活实例中的位置，任务不分片或位置未知时 ok 为 false
+func shard(job string) (index, count int, ok bool) {
+	if !config.Config().Cluster.Enabled || config.Config().Jobs[job].Run != config.JobRunShard {
+		return 0, 0, false
+	}
+	membersLock.RLock()
+	defer membersLock.RUnlock()
+	for i, member := range members {
+		if member == instanceId {
+			return i, len(members), true
+		}
+	}
+	return 0, 0, false
+}
+
+// chainIndex 测试网为 0，主网为 1，两个以上实例时两条链分配到不同的实例；其它链按哈希分配
+func chainIndex(chainId string) uint32 {
+	switch chainId {
+	case config.Config().TestNet.ChainId:
+		return 0
+	case config.Config().MainNet.ChainId:
+		return 1
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(chainId))
+	return h.Sum32()
+}
+
+func boolToInt32(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -1,7 +1,7 @@
 package common
 
 import (
-	"os"
+	"pledge-backend/config"
 	"pledge-backend/log"
 )
 
@@ -9,12 +9,15 @@ var PlgrAdminPrivateKey string
 
 func GetEnv() {
 
-	var ok bool
-
-	PlgrAdminPrivateKey, ok = os.LookupEnv("plgr_admin_private_key")
-	if !ok {
-		log.Logger.Error("environment variable is not set")
-		panic("environment variable is not set")
+	// 由密钥服务读取 (SECRETS_PROVIDER，默认为环境变量 plgr_admin_private_key)
+	PlgrAdminPrivateKey = config.Config().Oracle.SignerKey
+	if PlgrAdminPrivateKey == "" && config.Config().Devnet.Enabled {
+		// 本地开发链使用 [devnet] 的测试账户
+		PlgrAdminPrivateKey = config.Config().Devnet.PrivateKey
+	}
+	if PlgrAdminPrivateKey == "" {
+		log.Logger.Error("plgr_admin_private_key is not set")
+		panic("plgr_admin_private_key is not set")
 	}
 
 }
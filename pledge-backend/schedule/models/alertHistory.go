@@ -0,0 +1,68 @@
+package models
+
+import (
+	"errors"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+)
+
+// 告警级别，依次升级
+const (
+	AlertLevelNone      = 0
+	AlertLevelEmail     = 1
+	AlertLevelTelegram  = 2
+	AlertLevelPagerduty = 3
+)
+
+// BalanceAlert 余额告警状态，存放在 Redis balance_alert:<chainId>:<address>
+type BalanceAlert struct {
+	Consecutive int   `json:"consecutive"`   // 连续低于阈值的检查次数
+	Level       int   `json:"level"`         // 已发送的最高告警级别
+	LastAlertAt int64 `json:"last_alert_at"` // 最近一次发送告警的时间, Unix 秒
+}
+
+// AlertHistory 已发送的告警
+type AlertHistory struct {
+	Id          int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Kind        string `json:"kind" gorm:"column:kind;type:varchar(32);index:idx_kind_target,priority:1"` // 告警类型，例如 balance
+	ChainId     string `json:"chain_id" gorm:"column:chain_id;type:varchar(16)"`
+	Target      string `json:"target" gorm:"column:target;type:varchar(64);index:idx_kind_target,priority:2"` // 告警对象，例如合约地址
+	Level       int    `json:"level" gorm:"column:level"`                                                     // 1 邮件 2 Telegram 3 PagerDuty，0 为恢复或只发送了通知
+	Consecutive int    `json:"consecutive" gorm:"column:consecutive"`                                         // 连续低于阈值的检查次数
+	Message     string `json:"message" gorm:"column:message;type:text"`
+	Error       string `json:"error" gorm:"column:error;type:text"` // 发送失败的渠道和错误，全部成功时为空
+	CreatedAt   string `json:"created_at" gorm:"column:created_at"`
+}
+
+func NewAlertHistory() *AlertHistory {
+	return &AlertHistory{}
+}
+
+func (a *AlertHistory) TableName() string {
+	return "alert_history"
+}
+
+// Exists 是否已有同一类型、同一对象的告警，用于只发送一次的告警
+func (a *AlertHistory) Exists(kind, chainId, target string) (bool, error) {
+	var count int64
+	err := db.Mysql.Table("alert_history").Where("kind=? and chain_id=? and target=?", kind, chainId, target).Count(&count).Debug().Error
+	if err != nil {
+		return false, errors.New("record select err " + err.Error())
+	}
+	return count > 0, nil
+}
+
+// Save 记录一次告警
+func (a *AlertHistory) Save(history *AlertHistory) error {
+	history.CreatedAt = utils.GetCurDateTimeFormat()
+	return db.Mysql.Table("alert_history").Create(history).Debug().Error
+}
+
+// OracleFreshness 链上 Oracle 价格监控状态，存放在 Redis oracle_freshness:<chainId>:<asset>
+type OracleFreshness struct {
+	Price       int64 `json:"price"`         // 最近一次读取的链上价格, 1e8 精度
+	WrittenAt   int64 `json:"written_at"`    // 最近一次写链成功的时间, Unix 秒
+	Consecutive int   `json:"consecutive"`   // 连续发现问题的检查次数
+	Level       int   `json:"level"`         // 已发送的最高告警级别
+	LastAlertAt int64 `json:"last_alert_at"` // 最近一次发送告警的时间, Unix 秒
+}
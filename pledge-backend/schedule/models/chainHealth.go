@@ -0,0 +1,93 @@
+package models
+
+import (
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+
+	"gorm.io/gorm/clause"
+)
+
+// ChainHealth RPC 节点最近一次健康检查结果，每个节点一行
+type ChainHealth struct {
+	Id             int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId        string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_url,priority:1"`
+	Url            string `json:"url" gorm:"column:url;type:varchar(255);uniqueIndex:uk_chain_url,priority:2"`
+	Primary        bool   `json:"primary" gorm:"column:is_primary"`              // 是否为 [testnet]/[mainnet] net_url，否则为备用节点
+	Healthy        bool   `json:"healthy" gorm:"column:healthy"`                 // 是否健康
+	LatencyMs      int64  `json:"latency_ms" gorm:"column:latency_ms"`           // eth_blockNumber 延迟, ms
+	BlockNumber    uint64 `json:"block_number" gorm:"column:block_number"`       // 节点区块高度
+	ReferenceBlock uint64 `json:"reference_block" gorm:"column:reference_block"` // 参考节点区块高度，参考节点不可用时为 0
+	BlockLag       uint64 `json:"block_lag" gorm:"column:block_lag"`             // 落后参考节点的区块数
+	Error          string `json:"error" gorm:"column:error;type:text"`           // 请求失败或不健康的原因
+	CheckedAt      string `json:"checked_at" gorm:"column:checked_at"`
+}
+
+func NewChainHealth() *ChainHealth {
+	return &ChainHealth{}
+}
+
+func (c *ChainHealth) TableName() string {
+	return "chain_health"
+}
+
+// Get 节点上一次的检查结果，没有记录时 found 为 false
+func (c *ChainHealth) Get(chainId, url string, res *ChainHealth) (found bool, err error) {
+	result := db.Mysql.Table("chain_health").Where("chain_id=? and url=?", chainId, url).Limit(1).Find(res).Debug()
+	return result.RowsAffected > 0, result.Error
+}
+
+// Save 保存节点的检查结果，已有记录时覆盖
+func (c *ChainHealth) Save(health *ChainHealth) error {
+	health.CheckedAt = utils.GetCurDateTimeFormat()
+	return db.Mysql.Table("chain_health").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain_id"}, {Name: "url"}},
+		DoUpdates: clause.AssignmentColumns([]string{"is_primary", "healthy", "latency_ms", "block_number", "reference_block", "block_lag", "error", "checked_at"}),
+	}).Create(health).Debug().Error
+}
+
+// UnhealthyRedisKey 不健康节点的标记，存在时节点切换应跳过该节点
+func UnhealthyRedisKey(chainId, url string) string {
+	return "rpc_unhealthy:" + chainId + ":" + url
+}
+
+// MarkUnhealthy 标记节点不健康，aliveSeconds 后自动失效，健康检查停止时不会一直保留旧的标记
+func (c *ChainHealth) MarkUnhealthy(chainId, url, reason string, aliveSeconds int) error {
+	return db.RedisSetString(UnhealthyRedisKey(chainId, url), reason, aliveSeconds)
+}
+
+// MarkHealthy 清除节点的不健康标记
+func (c *ChainHealth) MarkHealthy(chainId, url string) error {
+	_, err := db.RedisDelete(UnhealthyRedisKey(chainId, url))
+	return err
+}
+
+// IsHealthy 节点是否没有被标记为不健康
+func (c *ChainHealth) IsHealthy(chainId, url string) bool {
+	return !db.RedisExists(UnhealthyRedisKey(chainId, url))
+}
+
+// RpcUrl 链当前使用的 RPC 节点: [testnet] / [mainnet] net_url 被标记为不健康时，
+// 切换到 [chain_health] endpoints 中第一个没有标记的备用节点；都被标记或 Redis 不可用时仍使用 net_url
+// 标记由 schedule 的 UpdateChainHealth 写入，unhealthyTtl 后失效，节点恢复后自动切回 net_url
+func (c *ChainHealth) RpcUrl(chainId string) string {
+	conf := config.Config()
+	netUrl, endpoints := "", []string(nil)
+	switch chainId {
+	case conf.TestNet.ChainId:
+		netUrl, endpoints = conf.TestNet.NetUrl, conf.ChainHealth.TestnetEndpoints
+	case conf.MainNet.ChainId:
+		netUrl, endpoints = conf.MainNet.NetUrl, conf.ChainHealth.MainnetEndpoints
+	default:
+		return ""
+	}
+	if c.IsHealthy(chainId, netUrl) {
+		return netUrl
+	}
+	for _, url := range endpoints {
+		if url != netUrl && c.IsHealthy(chainId, url) {
+			return url
+		}
+	}
+	return netUrl
+}
@@ -0,0 +1,10 @@
+package models
+
+// ColumnChanges 与上次保存的数据相比发生变化的列及新值，用于只更新变化的列
+type ColumnChanges map[string]interface{}
+
+func (c ColumnChanges) compare(column, old, new string) {
+	if old != new {
+		c[column] = new
+	}
+}
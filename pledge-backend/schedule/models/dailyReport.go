@@ -0,0 +1,52 @@
+package models
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+)
+
+// DailyReport 协议每日统计 (UTC 日)，金额均为按 token_info 当前价格折算的美元价值, 1e8 精度
+type DailyReport struct {
+	Id               int    `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId          string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_date,priority:1"`
+	ReportDate       string `json:"report_date" gorm:"column:report_date;type:varchar(10);uniqueIndex:uk_chain_date,priority:2"` // 2006-01-02
+	NewLendDeposit   string `json:"new_lend_deposit" gorm:"column:new_lend_deposit"`                                             // 当日新增出借
+	NewBorrowDeposit string `json:"new_borrow_deposit" gorm:"column:new_borrow_deposit"`                                         // 当日新增抵押
+	SettledPools     int    `json:"settled_pools" gorm:"column:settled_pools"`                                                   // 当日结算 (进入 EXECUTION) 的池子数
+	UndonePools      int    `json:"undone_pools" gorm:"column:undone_pools"`                                                     // 当日结算失败 (进入 UNDONE) 的池子数
+	LiquidatedPools  int    `json:"liquidated_pools" gorm:"column:liquidated_pools"`                                             // 当日清算的池子数
+	LendFeeRevenue   string `json:"lend_fee_revenue" gorm:"column:lend_fee_revenue"`                                             // 当日结算池子的 lendFee 收入
+	BorrowFeeRevenue string `json:"borrow_fee_revenue" gorm:"column:borrow_fee_revenue"`                                         // 当日结算池子的 borrowFee 收入
+	Tvl              string `json:"tvl" gorm:"column:tvl"`                                                                       // 日终 TVL
+	TvlChange        string `json:"tvl_change" gorm:"column:tvl_change"`                                                         // 日终 TVL - 日初 TVL
+	CreatedAt        string `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt        string `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func NewDailyReport() *DailyReport {
+	return &DailyReport{}
+}
+
+func (r *DailyReport) TableName() string {
+	return "daily_reports"
+}
+
+// SaveDailyReport 同一条链同一天重复生成时覆盖
+func (r *DailyReport) SaveDailyReport(report *DailyReport) error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	report.UpdatedAt = nowDateTime
+	old := DailyReport{}
+	err := db.Mysql.Table("daily_reports").Where("chain_id=? and report_date=?", report.ChainId, report.ReportDate).First(&old).Debug().Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			report.CreatedAt = nowDateTime
+			return db.Mysql.Table("daily_reports").Create(report).Debug().Error
+		}
+		return errors.New("record select err " + err.Error())
+	}
+	report.Id = old.Id
+	report.CreatedAt = old.CreatedAt
+	return db.Mysql.Table("daily_reports").Where("id=?", old.Id).Save(report).Debug().Error
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// email_subscriptions.events 中的事件
+const (
+	SubscriptionEventSettled    = "settled"
+	SubscriptionEventFinished   = "finished"
+	SubscriptionEventLiquidated = "liquidated"
+	SubscriptionEventClaimable  = "claimable"
+)
+
+// email_notifications.status
+const (
+	EmailNotificationSent   = "sent"
+	EmailNotificationFailed = "failed" // 发送失败，未达到 [subscription] max_attempts 时下次执行重试
+)
+
+// EmailSubscription 钱包订阅的池子事件邮件通知，由 api 写入和验证
+type EmailSubscription struct {
+	Id            int     `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId       string  `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_address_email_pool,priority:1"`
+	Address       string  `json:"address" gorm:"column:address;type:varchar(42);uniqueIndex:uk_chain_address_email_pool,priority:2"` // checksum 地址
+	Email         string  `json:"email" gorm:"column:email;type:varchar(255);uniqueIndex:uk_chain_address_email_pool,priority:3"`
+	PoolId        int     `json:"pool_id" gorm:"column:pool_id;uniqueIndex:uk_chain_address_email_pool,priority:4"`
+	Events        string  `json:"events" gorm:"column:events;type:varchar(64)"`       // 逗号分隔的事件
+	Token         string  `json:"-" gorm:"column:token;type:varchar(64);uniqueIndex"` // 验证和退订链接中的令牌
+	ExpiresAt     int64   `json:"-" gorm:"column:expires_at"`                         // 验证链接过期时间, Unix 秒
+	VerifiedAt    *string `json:"verified_at" gorm:"column:verified_at;index"`
+	VerifiedState string  `json:"-" gorm:"column:verified_state;type:varchar(8)"` // 验证时池子的状态，此前已发生的事件不通知
+	Language      string  `json:"-" gorm:"column:language;type:varchar(16)"`      // 邮件语言标签，订阅时按 Accept-Language 选择
+	CreatedAt     string  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt     string  `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// EmailNotification 订阅的每个事件发送一次，记录发送结果
+type EmailNotification struct {
+	Id             int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	SubscriptionId int    `json:"subscription_id" gorm:"column:subscription_id;uniqueIndex:uk_subscription_event,priority:1"`
+	Event          string `json:"event" gorm:"column:event;type:varchar(16);uniqueIndex:uk_subscription_event,priority:2"`
+	ChainId        string `json:"chain_id" gorm:"column:chain_id;type:varchar(16)"`
+	PoolId         int    `json:"pool_id" gorm:"column:pool_id"`
+	Status         string `json:"status" gorm:"column:status;type:varchar(16)"`
+	Attempts       int    `json:"attempts" gorm:"column:attempts"`
+	Error          string `json:"error" gorm:"column:error;type:varchar(512)"`
+	CreatedAt      string `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt      string `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func NewEmailSubscription() *EmailSubscription {
+	return &EmailSubscription{}
+}
+
+func (s *EmailSubscription) TableName() string {
+	return "email_subscriptions"
+}
+
+func NewEmailNotification() *EmailNotification {
+	return &EmailNotification{}
+}
+
+func (n *EmailNotification) TableName() string {
+	return "email_notifications"
+}
+
+// EventList 解析 Events
+func (s *EmailSubscription) EventList() []string {
+	if s.Events == "" {
+		return []string{}
+	}
+	return strings.Split(s.Events, ",")
+}
+
+// Verified 指定链上已验证的订阅
+func (s *EmailSubscription) Verified(ctx context.Context, chainId string, res *[]EmailSubscription) error {
+	err := db.Mysql.WithContext(ctx).Table("email_subscriptions").
+		Where("chain_id=? and verified_at is not null", chainId).
+		Order("id asc").Find(res).Debug().Error
+	if err != nil {
+		return errors.New("record select err " + err.Error())
+	}
+	return nil
+}
+
+// Get 订阅某个事件的通知记录，没有发送过时 found 为 false
+func (n *EmailNotification) Get(subscriptionId int, event string) (found bool, err error) {
+	err = db.Mysql.Table("email_notifications").Where("subscription_id=? and event=?", subscriptionId, event).First(n).Debug().Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, errors.New("record select err " + err.Error())
+	}
+	return true, nil
+}
+
+// Save 记录一次发送，重试时覆盖状态、次数和错误
+func (n *EmailNotification) Save() error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	if len(n.Error) > 512 {
+		n.Error = n.Error[:512]
+	}
+	n.CreatedAt = nowDateTime
+	n.UpdatedAt = nowDateTime
+	return db.Mysql.Table("email_notifications").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "subscription_id"}, {Name: "event"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "attempts", "error", "updated_at"}),
+	}).Create(n).Debug().Error
+}
@@ -0,0 +1,9 @@
+package models
+
+// ExchangeTick 交易所成交记录，由 api 进程的 kucoin.SaveTick 写入 Redis 有序集合 exchange_ticks:<symbol>
+type ExchangeTick struct {
+	Sequence string `json:"sequence"`
+	Price    string `json:"price"`
+	Size     string `json:"size"`
+	Time     int64  `json:"time"` // 毫秒时间戳
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"pledge-backend/db"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// exchangeTradeTablePrefix 成交记录按月分表，表名为 exchange_trades_YYYYMM (UTC)
+const exchangeTradeTablePrefix = "exchange_trades_"
+
+// ExchangeTrade 交易所成交记录，由 PersistExchangeTrades 从 Redis exchange_ticks:<symbol> 写入
+// 按 tick_time 所在月份分表，超过保留期的整张表删除，用于核对喂价时的 TWAP/VWAP 和事后分析
+type ExchangeTrade struct {
+	Id        int64  `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	Symbol    string `json:"symbol" gorm:"column:symbol;type:varchar(32);uniqueIndex:uk_symbol_sequence,priority:1;index:idx_symbol_time,priority:1"`
+	Sequence  string `json:"sequence" gorm:"column:sequence;type:varchar(32);uniqueIndex:uk_symbol_sequence,priority:2"` // 交易所序号
+	Price     string `json:"price" gorm:"column:price;type:varchar(64)"`
+	Size      string `json:"size" gorm:"column:size;type:varchar(64)"`
+	TickTime  int64  `json:"tick_time" gorm:"column:tick_time;index:idx_symbol_time,priority:2"` // api 收到成交的毫秒时间戳，与计算均价时使用的时间相同
+	CreatedAt string `json:"created_at" gorm:"column:created_at"`
+}
+
+// exchangeTradeTables 本进程已建好的分表
+var exchangeTradeTables sync.Map
+
+func NewExchangeTrade() *ExchangeTrade {
+	return &ExchangeTrade{}
+}
+
+// ExchangeTradeTable 成交时间所在月份的分表
+func ExchangeTradeTable(ms int64) string {
+	return exchangeTradeTablePrefix + time.UnixMilli(ms).UTC().Format("200601")
+}
+
+// Save 按月份写入对应的分表，分表不存在时创建；同一笔成交 (symbol, sequence) 重复写入时忽略
+func (e *ExchangeTrade) Save(trades []ExchangeTrade) error {
+	tables := make(map[string][]ExchangeTrade)
+	for _, trade := range trades {
+		table := ExchangeTradeTable(trade.TickTime)
+		tables[table] = append(tables[table], trade)
+	}
+	for table, list := range tables {
+		if err := ensureExchangeTradeTable(table); err != nil {
+			return err
+		}
+		err := db.Mysql.Table(table).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&list, 500).Debug().Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LastTickTime 交易对已保存的最后一笔成交时间，查找本月和上月的分表，没有记录时返回 0
+func (e *ExchangeTrade) LastTickTime(symbol string) (int64, error) {
+	now := time.Now().UTC()
+	for _, month := range []time.Time{now, now.AddDate(0, 0, -now.Day())} {
+		table := ExchangeTradeTable(month.UnixMilli())
+		if !db.Mysql.Migrator().HasTable(table) {
+			continue
+		}
+		var last []int64
+		err := db.Mysql.Table(table).Where("symbol=?", symbol).Order("tick_time desc").Limit(1).Pluck("tick_time", &last).Error
+		if err != nil {
+			return 0, err
+		}
+		if len(last) > 0 {
+			return last[0], nil
+		}
+	}
+	return 0, nil
+}
+
+// Prune 删除早于 keepMonths 个月 (含本月) 的分表，返回删除的表名
+func (e *ExchangeTrade) Prune(keepMonths int) ([]string, error) {
+	tables, err := db.Mysql.Migrator().GetTables()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	oldest := ExchangeTradeTable(time.Date(now.Year(), now.Month()-time.Month(keepMonths-1), 1, 0, 0, 0, 0, time.UTC).UnixMilli())
+	sort.Strings(tables)
+	var dropped []string
+	for _, table := range tables {
+		if !strings.HasPrefix(table, exchangeTradeTablePrefix) || len(table) != len(oldest) || table >= oldest {
+			continue
+		}
+		if err = db.Mysql.Migrator().DropTable(table); err != nil {
+			return dropped, err
+		}
+		exchangeTradeTables.Delete(table)
+		dropped = append(dropped, table)
+	}
+	return dropped, nil
+}
+
+func ensureExchangeTradeTable(table string) error {
+	if _, ok := exchangeTradeTables.Load(table); ok {
+		return nil
+	}
+	if err := db.Mysql.Table(table).AutoMigrate(&ExchangeTrade{}); err != nil {
+		return err
+	}
+	exchangeTradeTables.Store(table, true)
+	return nil
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"context"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+)
+
+// fee_revenues.event
+const (
+	FeeEventFinish      = "finish"      // 池子到期完成
+	FeeEventLiquidation = "liquidation" // 池子被清算
+)
+
+// FeeRevenue 池子完成或清算时协议收取的 lendFee / borrowFee，每个池子一条，由 AccountFeeRevenue 写入
+// 美元价值按 accrued_at 时的 token_price_history 价格折算，没有记录时使用 token_info 的当前价格, 1e8 精度
+type FeeRevenue struct {
+	Id              int    `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId         string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_pool,priority:1;index:idx_chain_accrued,priority:1"`
+	PoolId          int    `json:"pool_id" gorm:"column:pool_id;uniqueIndex:uk_chain_pool,priority:2"`
+	Event           string `json:"event" gorm:"column:event;type:varchar(16)"`
+	LendToken       string `json:"lend_token" gorm:"column:lend_token;type:varchar(42)"`
+	BorrowToken     string `json:"borrow_token" gorm:"column:borrow_token;type:varchar(42)"`
+	LendFeeAmount   string `json:"lend_fee_amount" gorm:"column:lend_fee_amount;type:decimal(65,0)"`     // 出借代币最小单位
+	BorrowFeeAmount string `json:"borrow_fee_amount" gorm:"column:borrow_fee_amount;type:decimal(65,0)"` // 抵押代币最小单位
+	LendFeeUsd      string `json:"lend_fee_usd" gorm:"column:lend_fee_usd;type:decimal(65,0)"`
+	BorrowFeeUsd    string `json:"borrow_fee_usd" gorm:"column:borrow_fee_usd;type:decimal(65,0)"`
+	TotalUsd        string `json:"total_usd" gorm:"column:total_usd;type:decimal(65,0)"`
+	AccruedAt       int64  `json:"accrued_at" gorm:"column:accrued_at;index:idx_chain_accrued,priority:2"` // 池子进入完成 / 清算状态的时间, Unix 秒
+	CreatedAt       string `json:"created_at" gorm:"column:created_at"`
+}
+
+// FeeRevenueSum 一段时间内的手续费收入合计, 1e8 精度
+type FeeRevenueSum struct {
+	LendFeeUsd   string `gorm:"column:lend_fee_usd"`
+	BorrowFeeUsd string `gorm:"column:borrow_fee_usd"`
+}
+
+func NewFeeRevenue() *FeeRevenue {
+	return &FeeRevenue{}
+}
+
+func (f *FeeRevenue) TableName() string {
+	return "fee_revenues"
+}
+
+// Create 记录一个池子的手续费收入
+func (f *FeeRevenue) Create(revenue *FeeRevenue) error {
+	revenue.CreatedAt = utils.GetCurDateTimeFormat()
+	return db.Mysql.Table("fee_revenues").Create(revenue).Debug().Error
+}
+
+// Unaccounted 处于 states 且还没有手续费记录的池子，包括已归档的池子
+func (f *FeeRevenue) Unaccounted(ctx context.Context, chainId string, states []string, res *[]PoolBase) error {
+	accounted := db.Mysql.Table("fee_revenues").Select("pool_id").Where("chain_id=?", chainId)
+	return db.Mysql.WithContext(ctx).Table("poolbases").
+		Where("chain_id=? and state in ? and deleted_at is null and pool_id not in (?)",
+			chainId, states, accounted).
+		Order("pool_id asc").Find(res).Debug().Error
+}
+
+// Sum [start, end) 内记入的手续费收入
+func (f *FeeRevenue) Sum(chainId string, start, end int64) (FeeRevenueSum, error) {
+	sum := FeeRevenueSum{}
+	err := db.Mysql.Table("fee_revenues").
+		Select("CAST(COALESCE(SUM(lend_fee_usd), 0) AS CHAR) AS lend_fee_usd, CAST(COALESCE(SUM(borrow_fee_usd), 0) AS CHAR) AS borrow_fee_usd").
+		Where("chain_id=? and accrued_at>=? and accrued_at<?", chainId, start, end).
+		Scan(&sum).Debug().Error
+	return sum, err
+}
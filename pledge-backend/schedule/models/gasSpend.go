@@ -0,0 +1,90 @@
+package models
+
+import (
+	"errors"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+)
+
+const (
+	GasSpendPending = "pending" // 已发送，等待上链
+	GasSpendSuccess = "success"
+	GasSpendFailed  = "failed"  // 上链但执行失败，gas 同样被消耗
+	GasSpendDropped = "dropped" // 长时间未上链，视为被丢弃，不计入花费
+)
+
+// GasSpend 后端发送的交易及其 gas 花费
+type GasSpend struct {
+	Id          int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId     string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);index:idx_chain_created,priority:1"`
+	Purpose     string `json:"purpose" gorm:"column:purpose;type:varchar(32)"` // 交易用途，例如 oracle_set_price
+	TxHash      string `json:"tx_hash" gorm:"column:tx_hash;type:varchar(66);uniqueIndex"`
+	FromAddress string `json:"from_address" gorm:"column:from_address;type:varchar(42)"`
+	ToAddress   string `json:"to_address" gorm:"column:to_address;type:varchar(42)"`
+	Nonce       uint64 `json:"nonce" gorm:"column:nonce"`
+	GasLimit    uint64 `json:"gas_limit" gorm:"column:gas_limit"`
+	GasPrice    string `json:"gas_price" gorm:"column:gas_price;type:decimal(65,0)"` // wei
+	GasUsed     uint64 `json:"gas_used" gorm:"column:gas_used"`
+	FeeWei      string `json:"fee_wei" gorm:"column:fee_wei;type:decimal(65,0)"` // gas_used * gas_price
+	BlockNumber uint64 `json:"block_number" gorm:"column:block_number"`
+	Status      string `json:"status" gorm:"column:status;type:varchar(16);index"`
+	CreatedAt   string `json:"created_at" gorm:"column:created_at;index:idx_chain_created,priority:2"`
+	UpdatedAt   string `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func NewGasSpend() *GasSpend {
+	return &GasSpend{}
+}
+
+func (g *GasSpend) TableName() string {
+	return "gas_spend"
+}
+
+// Save 记录一笔待上链的交易
+func (g *GasSpend) Save(spend *GasSpend) error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	spend.Status = GasSpendPending
+	spend.FeeWei = "0"
+	spend.CreatedAt = nowDateTime
+	spend.UpdatedAt = nowDateTime
+	return db.Mysql.Table("gas_spend").Create(spend).Debug().Error
+}
+
+// Pending 等待上链的交易
+func (g *GasSpend) Pending(res *[]GasSpend) error {
+	err := db.Mysql.Table("gas_spend").Where("status=?", GasSpendPending).Order("id asc").Find(res).Debug().Error
+	if err != nil {
+		return errors.New("record select err " + err.Error())
+	}
+	return nil
+}
+
+// UpdateStatus 交易上链或被丢弃后更新状态和花费
+func (g *GasSpend) UpdateStatus(id int, status string, gasUsed uint64, feeWei string, blockNumber uint64) error {
+	return db.Mysql.Table("gas_spend").Where("id=?", id).Updates(map[string]interface{}{
+		"status":       status,
+		"gas_used":     gasUsed,
+		"fee_wei":      feeWei,
+		"block_number": blockNumber,
+		"updated_at":   utils.GetCurDateTimeFormat(),
+	}).Debug().Error
+}
+
+// SumFee 统计链上 [start, end) 之间发送的交易花费 (wei)
+func (g *GasSpend) SumFee(chainId, start, end string) (string, error) {
+	var fee string
+	err := db.Mysql.Table("gas_spend").
+		Select("CAST(COALESCE(SUM(fee_wei), 0) AS CHAR)").
+		Where("chain_id=? and created_at>=? and created_at<? and status in ?", chainId, start, end, []string{GasSpendSuccess, GasSpendFailed}).
+		Scan(&fee).Debug().Error
+	if err != nil {
+		return "", errors.New("record select err " + err.Error())
+	}
+	return fee, nil
+}
+
+// LastSuccess 链上最近一笔执行成功的指定用途交易，没有时返回 gorm.ErrRecordNotFound
+func (g *GasSpend) LastSuccess(chainId, purpose string, res *GasSpend) error {
+	return db.Mysql.Table("gas_spend").Where("chain_id=? and purpose=? and status=?", chainId, purpose, GasSpendSuccess).
+		Order("id desc").First(res).Debug().Error
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"pledge-backend/db"
+)
+
+// IntegrityReport VerifyIntegrity 最近一次核对的结果，写入 Redis integrity:<chainId>，供 api 的 /admin/integrity 读取
+type IntegrityReport struct {
+	ChainId       string           `json:"chain_id"`
+	Block         uint64           `json:"block"`          // 读取链上数据的区块
+	SyncBlock     uint64           `json:"sync_block"`     // UpdateAllPoolInfo 最近一次读取的区块，链上在两个区块之间的变化视为同步延迟，不计为不一致
+	PoolsChecked  int              `json:"pools_checked"`  // 本次抽查的池子数
+	PoolsDrifted  int              `json:"pools_drifted"`  // 本次不一致的池子数
+	TokensChecked int              `json:"tokens_checked"` // 本次抽查的代币数
+	TokensDrifted int              `json:"tokens_drifted"` // 本次不一致的代币数
+	Repaired      int              `json:"repaired"`       // 本次修复的池子、代币数
+	Drifts        []IntegrityDrift `json:"drifts"`
+	TotalChecked  int64            `json:"total_checked"` // 累计抽查的池子、代币数
+	TotalDrifted  int64            `json:"total_drifted"` // 累计不一致的池子、代币数
+	CheckedAt     int64            `json:"checked_at"`
+}
+
+// IntegrityDrift 一个不一致的字段
+type IntegrityDrift struct {
+	Kind     string `json:"kind"`     // pool / token
+	Id       string `json:"id"`       // pool_id 或代币地址
+	Store    string `json:"store"`    // 不一致的存储: poolbases / pooldata / token_info 表，或 redis 缓存
+	Column   string `json:"column"`   // 列名
+	Stored   string `json:"stored"`   // 存储中的值，记录不存在时为空
+	OnChain  string `json:"on_chain"` // 链上的值
+	Repaired bool   `json:"repaired"`
+}
+
+// 不一致的对象
+const (
+	IntegrityKindPool  = "pool"
+	IntegrityKindToken = "token"
+)
+
+// 不一致的存储，表名之外的 Redis 缓存
+const IntegrityStoreRedis = "redis"
+
+func NewIntegrityReport() *IntegrityReport {
+	return &IntegrityReport{}
+}
+
+// IntegrityReportRedisKey 链上最近一次核对的结果
+func IntegrityReportRedisKey(chainId string) string {
+	return "integrity:" + chainId
+}
+
+// Get 读取链上最近一次核对的结果，没有记录时返回 redis.ErrNil
+func (r *IntegrityReport) Get(chainId string) error {
+	data, err := db.RedisGet(IntegrityReportRedisKey(chainId))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, r)
+}
+
+// Save 保存核对结果，不过期
+func (r *IntegrityReport) Save() error {
+	return db.RedisSet(IntegrityReportRedisKey(r.ChainId), r, 0)
+}
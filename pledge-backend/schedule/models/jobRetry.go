@@ -0,0 +1,83 @@
+package models
+
+import (
+	"errors"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+const (
+	JobRetryPending = "pending" // 等待重试
+	JobRetryDead    = "dead"    // 超过 [schedule] retry_max_attempts 仍然失败，不再重试
+)
+
+// JobRetry 定时任务执行中处理失败的条目 (例如保存失败的池子)，由 ProcessRetryQueue 单独重试
+// 重试成功或下一轮全量同步成功后删除；放弃重试的条目保留为 dead，通过 GET /admin/jobs/retries 查看
+type JobRetry struct {
+	Id          int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Job         string `json:"job" gorm:"column:job;type:varchar(64);uniqueIndex:uk_job_item,priority:1"`
+	ChainId     string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_job_item,priority:2"`
+	Item        string `json:"item" gorm:"column:item;type:varchar(128);uniqueIndex:uk_job_item,priority:3"` // 条目标识，例如 pool_id
+	Status      string `json:"status" gorm:"column:status;type:varchar(16);index:idx_status_next,priority:1"`
+	Attempts    int    `json:"attempts" gorm:"column:attempts"` // 已重试次数，不含最初的失败
+	LastError   string `json:"last_error" gorm:"column:last_error;type:text"`
+	NextRetryAt string `json:"next_retry_at" gorm:"column:next_retry_at;index:idx_status_next,priority:2"`
+	CreatedAt   string `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt   string `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func NewJobRetry() *JobRetry {
+	return &JobRetry{}
+}
+
+func (j *JobRetry) TableName() string {
+	return "job_retries"
+}
+
+// Enqueue 放入重试队列，已在队列中时只更新错误信息，不重置重试次数和状态
+func (j *JobRetry) Enqueue(job, chainId, item, lastError string) error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	return db.Mysql.Table("job_retries").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "job"}, {Name: "chain_id"}, {Name: "item"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_error", "updated_at"}),
+	}).Create(&JobRetry{
+		Job:         job,
+		ChainId:     chainId,
+		Item:        item,
+		Status:      JobRetryPending,
+		LastError:   lastError,
+		NextRetryAt: nowDateTime,
+		CreatedAt:   nowDateTime,
+		UpdatedAt:   nowDateTime,
+	}).Debug().Error
+}
+
+// Due 到达重试时间的条目
+func (j *JobRetry) Due(limit int, res *[]JobRetry) error {
+	err := db.Mysql.Table("job_retries").
+		Where("status=? and next_retry_at<=?", JobRetryPending, utils.GetCurDateTimeFormat()).
+		Order("next_retry_at asc").Limit(limit).Find(res).Debug().Error
+	if err != nil {
+		return errors.New("record select err " + err.Error())
+	}
+	return nil
+}
+
+// Retried 记录一次失败的重试，status 为 dead 时不再重试
+func (j *JobRetry) Retried(id, attempts int, status, lastError string, nextRetryAt time.Time) error {
+	return db.Mysql.Table("job_retries").Where("id=?", id).Updates(map[string]interface{}{
+		"status":        status,
+		"attempts":      attempts,
+		"last_error":    lastError,
+		"next_retry_at": nextRetryAt.Format("2006-01-02 15:04:05"),
+		"updated_at":    utils.GetCurDateTimeFormat(),
+	}).Debug().Error
+}
+
+// Remove 条目已处理成功，移出重试队列
+func (j *JobRetry) Remove(job, chainId, item string) error {
+	return db.Mysql.Table("job_retries").Where("job=? and chain_id=? and item=?", job, chainId, item).Delete(&JobRetry{}).Debug().Error
+}
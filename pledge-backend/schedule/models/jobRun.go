@@ -0,0 +1,37 @@
+package models
+
+import (
+	"pledge-backend/db"
+	"time"
+)
+
+// JobRun 定时任务的一次执行记录，跳过的执行只计入 job_stats
+type JobRun struct {
+	Id         int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Name       string `json:"name" gorm:"column:name;type:varchar(64);index:idx_name_started,priority:1"`
+	Status     string `json:"status" gorm:"column:status;type:varchar(16)"` // success / failed / timeout
+	Error      string `json:"error" gorm:"column:error;type:text"`          // panic 或超时的原因
+	Processed  int64  `json:"processed" gorm:"column:processed"`            // 处理成功的条目数，例如同步成功的池子
+	Failed     int64  `json:"failed" gorm:"column:failed"`                  // 处理失败、放入重试队列的条目数
+	DurationMs int64  `json:"duration_ms" gorm:"column:duration_ms"`
+	StartedAt  string `json:"started_at" gorm:"column:started_at;index:idx_name_started,priority:2;index"`
+	FinishedAt string `json:"finished_at" gorm:"column:finished_at"` // 超时时为放弃等待的时间
+}
+
+func NewJobRun() *JobRun {
+	return &JobRun{}
+}
+
+func (j *JobRun) TableName() string {
+	return "job_runs"
+}
+
+// Save 记录一次执行
+func (j *JobRun) Save(run *JobRun) error {
+	return db.Mysql.Table("job_runs").Create(run).Debug().Error
+}
+
+// Prune 删除 before 之前开始的执行记录
+func (j *JobRun) Prune(before time.Time) error {
+	return db.Mysql.Table("job_runs").Where("started_at<?", before.Format("2006-01-02 15:04:05")).Delete(&JobRun{}).Debug().Error
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json"
+	"pledge-backend/db"
+)
+
+// 定时任务一次执行的结果
+const (
+	JobSuccess = "success"
+	JobFailed  = "failed"  // panic
+	JobTimeout = "timeout" // 超过 [schedule] job_timeout 仍未结束
+	JobSkipped = "skipped" // 上一次执行仍未结束，跳过本次
+)
+
+// JobStatsSetKey 所有定时任务名称的集合
+const JobStatsSetKey = "job_stats"
+
+// JobStats 定时任务的执行统计，存放在 Redis job_stats:<name>，task 启动时清空
+type JobStats struct {
+	Name            string `json:"name"`
+	Runs            int64  `json:"runs"`              // 执行次数，不含跳过
+	Failures        int64  `json:"failures"`          // panic 次数
+	Timeouts        int64  `json:"timeouts"`          // 超时次数
+	Skipped         int64  `json:"skipped"`           // 因上一次未结束而跳过的次数
+	LastStatus      string `json:"last_status"`       // 最近一次的结果
+	LastError       string `json:"last_error"`        // 最近一次失败或超时的原因
+	LastDurationMs  int64  `json:"last_duration_ms"`  // 最近一次执行耗时, ms，超时时为超时时间
+	MaxDurationMs   int64  `json:"max_duration_ms"`   // 最长耗时, ms
+	TotalDurationMs int64  `json:"total_duration_ms"` // 累计耗时, ms，除以 runs 为平均耗时
+	LastRunAt       int64  `json:"last_run_at"`       // 最近一次开始执行的时间, Unix 秒
+	LastSuccessAt   int64  `json:"last_success_at"`   // 最近一次成功的时间, Unix 秒
+}
+
+func NewJobStats() *JobStats {
+	return &JobStats{}
+}
+
+func jobStatsKey(name string) string {
+	return "job_stats:" + name
+}
+
+// Get 读取定时任务的执行统计，不存在时返回空统计
+func (j *JobStats) Get(name string) JobStats {
+	stats := JobStats{Name: name}
+	statsBytes, err := db.RedisGet(jobStatsKey(name))
+	if err == nil && len(statsBytes) > 0 {
+		_ = json.Unmarshal(statsBytes, &stats)
+	}
+	return stats
+}
+
+// Save 保存定时任务的执行统计
+func (j *JobStats) Save(stats JobStats) error {
+	db.RedisSAdd(JobStatsSetKey, stats.Name)
+	return db.RedisSet(jobStatsKey(stats.Name), stats, 0)
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"errors"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+)
+
+const (
+	KeeperTxPending = "pending" // 已发送，等待上链
+	KeeperTxSuccess = "success"
+	KeeperTxFailed  = "failed"  // 上链但执行失败
+	KeeperTxDropped = "dropped" // 长时间未上链，视为被丢弃
+	KeeperTxDryRun  = "dry_run" // [keeper] dry_run，已签名未广播
+	KeeperTxSkipped = "skipped" // gas price 或 gas limit 超出 [keeper] 上限，未发送
+	KeeperTxError   = "error"   // 估算 gas、签名或发送失败，例如合约 revert
+)
+
+// KeeperTx keeper 为池子发送的 settle / finish / liquidate 交易及每次尝试的结果
+type KeeperTx struct {
+	Id          int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId     string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);index:idx_chain_pool_action,priority:1"`
+	PoolId      int    `json:"pool_id" gorm:"column:pool_id;index:idx_chain_pool_action,priority:2"`
+	Action      string `json:"action" gorm:"column:action;type:varchar(16);index:idx_chain_pool_action,priority:3"` // settle / finish / liquidate
+	TxHash      string `json:"tx_hash" gorm:"column:tx_hash;type:varchar(66);index"`
+	FromAddress string `json:"from_address" gorm:"column:from_address;type:varchar(42)"`
+	Nonce       uint64 `json:"nonce" gorm:"column:nonce"`
+	GasLimit    uint64 `json:"gas_limit" gorm:"column:gas_limit"`
+	GasPrice    string `json:"gas_price" gorm:"column:gas_price;type:decimal(65,0)"` // wei
+	GasUsed     uint64 `json:"gas_used" gorm:"column:gas_used"`
+	BlockNumber uint64 `json:"block_number" gorm:"column:block_number"`
+	Status      string `json:"status" gorm:"column:status;type:varchar(16);index"`
+	Error       string `json:"error" gorm:"column:error;type:varchar(512)"`
+	CreatedAt   string `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt   string `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func NewKeeperTx() *KeeperTx {
+	return &KeeperTx{}
+}
+
+func (k *KeeperTx) TableName() string {
+	return "keeper_tx"
+}
+
+// Save 记录一次尝试，status 由调用方设置
+func (k *KeeperTx) Save(tx *KeeperTx) error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	if tx.GasPrice == "" {
+		tx.GasPrice = "0"
+	}
+	if len(tx.Error) > 512 {
+		tx.Error = tx.Error[:512]
+	}
+	tx.CreatedAt = nowDateTime
+	tx.UpdatedAt = nowDateTime
+	return db.Mysql.Table("keeper_tx").Create(tx).Debug().Error
+}
+
+// Pending 等待上链的交易
+func (k *KeeperTx) Pending(res *[]KeeperTx) error {
+	err := db.Mysql.Table("keeper_tx").Where("status=?", KeeperTxPending).Order("id asc").Find(res).Debug().Error
+	if err != nil {
+		return errors.New("record select err " + err.Error())
+	}
+	return nil
+}
+
+// Last 池子指定操作的最近一次尝试，没有时返回 gorm.ErrRecordNotFound
+func (k *KeeperTx) Last(chainId string, poolId int, action string, res *KeeperTx) error {
+	return db.Mysql.Table("keeper_tx").Where("chain_id=? and pool_id=? and action=?", chainId, poolId, action).
+		Order("id desc").First(res).Debug().Error
+}
+
+// UpdateStatus 交易上链或被丢弃后更新状态
+func (k *KeeperTx) UpdateStatus(id int, status string, gasUsed, blockNumber uint64) error {
+	return db.Mysql.Table("keeper_tx").Where("id=?", id).Updates(map[string]interface{}{
+		"status":       status,
+		"gas_used":     gasUsed,
+		"block_number": blockNumber,
+		"updated_at":   utils.GetCurDateTimeFormat(),
+	}).Debug().Error
+}
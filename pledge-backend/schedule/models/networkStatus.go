@@ -0,0 +1,38 @@
+package models
+
+import (
+	"pledge-backend/db"
+)
+
+// NetworkStatus 链的最新区块、出块时间和 gas 价格建议，由 UpdateChainHealth 写入 Redis network_status:<chainId>
+// 供 api 的 /network/:chainId 读取，前端不需要自己访问 RPC 节点
+type NetworkStatus struct {
+	ChainId      string   `json:"chain_id"`
+	BlockNumber  uint64   `json:"block_number"`   // 最新区块高度
+	BlockTime    int64    `json:"block_time"`     // 最新区块时间, Unix 秒
+	AvgBlockTime float64  `json:"avg_block_time"` // 最近区块的平均出块时间, s
+	BaseFee      string   `json:"base_fee"`       // 最新区块的 base fee, wei，不支持 EIP-1559 的链为空
+	GasPrice     GasPrice `json:"gas_price"`
+	UpdatedAt    int64    `json:"updated_at"` // 采集时间, Unix 秒
+}
+
+// GasPrice gas 价格建议, wei
+type GasPrice struct {
+	Slow     string `json:"slow"`
+	Standard string `json:"standard"`
+	Fast     string `json:"fast"`
+}
+
+func NewNetworkStatus() *NetworkStatus {
+	return &NetworkStatus{}
+}
+
+// NetworkStatusRedisKey 链的网络状态
+func NetworkStatusRedisKey(chainId string) string {
+	return "network_status:" + chainId
+}
+
+// Save 保存网络状态，aliveSeconds 后过期，采集停止时 api 不会一直返回旧的数据
+func (n *NetworkStatus) Save(aliveSeconds int) error {
+	return db.RedisSet(NetworkStatusRedisKey(n.ChainId), n, aliveSeconds)
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"pledge-backend/db"
+	"time"
+)
+
+// OpsEventsKey 运维事件的 Redis 有序集合，score 为事件时间 (Unix 毫秒)，api 进程读取后推送到私有主题 ops
+const OpsEventsKey = "ops_events"
+
+// opsEventRetention 运维事件在 Redis 中保留的时间，运维面板断线超过这个时间需要通过管理接口补齐
+const opsEventRetention = time.Hour
+
+// 运维事件类型
+const (
+	OpsEventJob         = "job"          // 定时任务执行结束，data 为 job_runs 记录
+	OpsEventOracleTx    = "oracle_tx"    // 喂价交易发送 (pending) 和上链 (success / failed / dropped)，data 为 gas_spend 记录
+	OpsEventChainHealth = "chain_health" // RPC 节点健康状态变化，data 为 chain_health 记录
+	OpsEventAlert       = "alert"        // 发送了告警或恢复通知，data 为 alert_history 记录
+	OpsEventReorg       = "reorg"        // 事件索引发现分叉并回滚了未确认的事件，data 为合约、分叉区块、深度和删除的事件数
+)
+
+// OpsEvent 一条运维事件，同时作为 WebSocket 消息
+type OpsEvent struct {
+	Kind    string      `json:"kind"`
+	ChainId string      `json:"chain_id,omitempty"`
+	Data    interface{} `json:"data"`
+	At      int64       `json:"at"` // 事件时间, Unix 毫秒
+}
+
+func NewOpsEvent() *OpsEvent {
+	return &OpsEvent{}
+}
+
+// Publish 写入运维事件，并删除超过保留时间的事件
+func (e *OpsEvent) Publish(event *OpsEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err = db.RedisZAdd(OpsEventsKey, event.At, string(data)); err != nil {
+		return err
+	}
+	return db.RedisZRemRangeByScore(OpsEventsKey, 0, time.Now().Add(-opsEventRetention).UnixMilli())
+}
@@ -0,0 +1,34 @@
+package models
+
+// OracleBreaker 喂价熔断器状态，存放在 Redis oracle_breaker:<symbol>，api 进程的 /readyz 读取展示
+type OracleBreaker struct {
+	State       string `json:"state"`        // closed 正常 / open 熔断
+	Reason      string `json:"reason"`       // 熔断原因
+	Failures    int    `json:"failures"`     // 连续 SetPrice 失败次数
+	LastFailure int64  `json:"last_failure"` // 最近一次失败时间, Unix 秒
+	UpdatedAt   int64  `json:"updated_at"`   // 状态更新时间, Unix 秒
+}
+
+// OracleWrite 最近一次喂价的写入决策，存放在 Redis oracle_write:<chainId>:<symbol>，api 进程的 /readyz 读取展示
+type OracleWrite struct {
+	Decision     string `json:"decision"`       // write 写入 / skip 跳过
+	Reason       string `json:"reason"`         // 决策原因
+	Price        int64  `json:"price"`          // 待写入的价格, 1e8 精度
+	OnChainPrice int64  `json:"on_chain_price"` // 写入前读取的链上价格, 读取失败时为 0
+	DeltaBps     int64  `json:"delta_bps"`      // 两者相差的基点数
+	Skips        int    `json:"skips"`          // 连续跳过次数
+	TotalSkips   int64  `json:"total_skips"`    // 累计跳过次数，task 启动清空 Redis 后重新计数
+	LastWriteAt  int64  `json:"last_write_at"`  // 最近一次写链成功的时间, Unix 秒
+	UpdatedAt    int64  `json:"updated_at"`     // 决策时间, Unix 秒
+	Result       string `json:"result"`         // 最近一次写入的结果，见 OracleWriteResult*
+	TxHash       string `json:"tx_hash"`        // 最近一次写入的交易，批量写入时多个代币相同
+	Error        string `json:"error"`          // 最近一次写入失败的原因
+}
+
+// OracleWrite.Result
+const (
+	OracleWriteResultSent     = "sent"     // setPrice 已广播，回执由 UpdateGasSpend 查询
+	OracleWriteResultBatched  = "batched"  // setPrices 已上链，读回的链上价格与写入的一致
+	OracleWriteResultFailed   = "failed"   // 交易发送失败或执行失败
+	OracleWriteResultMismatch = "mismatch" // setPrices 失败或读回的价格不一致，已改用 setPrice 单独写入，TxHash 为 setPrice 交易
+)
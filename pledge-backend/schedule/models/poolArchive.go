@@ -0,0 +1,103 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PoolArchive 归档池子最后一次同步的 PoolBase 和 PoolData
+// poolbases / pooldata 中的记录保留并标记 archived_at，接口默认不返回，archived=include / only 时可查询
+type PoolArchive struct {
+	Id         int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId    string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_pool,priority:1"`
+	PoolId     int    `json:"pool_id" gorm:"column:pool_id;uniqueIndex:uk_chain_pool,priority:2"`
+	State      string `json:"state" gorm:"column:state"`
+	EndTime    string `json:"end_time" gorm:"column:end_time"`
+	PoolBase   string `json:"pool_base" gorm:"column:pool_base;type:text"` // 归档时的 poolbases 记录 (JSON)
+	PoolData   string `json:"pool_data" gorm:"column:pool_data;type:text"` // 归档时的 pooldata 记录 (JSON)
+	ArchivedAt string `json:"archived_at" gorm:"column:archived_at"`
+}
+
+func NewPoolArchive() *PoolArchive {
+	return &PoolArchive{}
+}
+
+func (a *PoolArchive) TableName() string {
+	return "pool_archives"
+}
+
+// Candidates 查询可以归档的池子: 处于 states 中，endTime 早于 before (Unix 时间戳)，且未归档、未软删除
+func (a *PoolArchive) Candidates(ctx context.Context, chainId string, states []string, before int64, res *[]PoolBase) error {
+	return a.candidates(ctx, chainId, states, before).Order("pool_id asc").Find(res).Debug().Error
+}
+
+// Candidate 查询单个可以归档的池子，不满足条件时返回 gorm.ErrRecordNotFound
+func (a *PoolArchive) Candidate(ctx context.Context, chainId string, poolId int, states []string, before int64, res *PoolBase) error {
+	return a.candidates(ctx, chainId, states, before).Where("pool_id=?", poolId).First(res).Debug().Error
+}
+
+func (a *PoolArchive) candidates(ctx context.Context, chainId string, states []string, before int64) *gorm.DB {
+	return db.Mysql.WithContext(ctx).Table("poolbases").
+		Where("chain_id=? and state in ? and cast(end_time as unsigned)<? and archived_at is null and deleted_at is null", chainId, states, before)
+}
+
+// ArchivedPoolIds 已归档的池子，同步时跳过
+func (a *PoolArchive) ArchivedPoolIds(ctx context.Context, chainId string) (map[int]bool, error) {
+	var poolIds []int
+	err := db.Mysql.WithContext(ctx).Table("poolbases").Where("chain_id=? and archived_at is not null", chainId).Pluck("pool_id", &poolIds).Debug().Error
+	if err != nil {
+		return nil, err
+	}
+	archived := make(map[int]bool, len(poolIds))
+	for _, poolId := range poolIds {
+		archived[poolId] = true
+	}
+	return archived, nil
+}
+
+// Archive 在同一个事务中复制池子到 pool_archives 并标记 poolbases.archived_at
+// 读取后池子被同步修改时返回 ErrConcurrentUpdate，下一次执行再归档
+func (a *PoolArchive) Archive(ctx context.Context, base *PoolBase) error {
+	return db.Mysql.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		poolData := PoolData{}
+		err := tx.Table("pooldata").Where("chain_id=? and pool_id=?", base.ChainId, base.PoolId).First(&poolData).Debug().Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		baseJson, _ := json.Marshal(base)
+		dataJson, _ := json.Marshal(poolData)
+
+		nowDateTime := utils.GetCurDateTimeFormat()
+		err = tx.Table("pool_archives").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "chain_id"}, {Name: "pool_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"state", "end_time", "pool_base", "pool_data", "archived_at"}),
+		}).Create(&PoolArchive{
+			ChainId:    base.ChainId,
+			PoolId:     base.PoolId,
+			State:      base.State,
+			EndTime:    base.EndTime,
+			PoolBase:   string(baseJson),
+			PoolData:   string(dataJson),
+			ArchivedAt: nowDateTime,
+		}).Debug().Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Table("poolbases").Where("id=? and version=? and archived_at is null", base.Id, base.Version).
+			Updates(map[string]interface{}{"archived_at": nowDateTime, "updated_at": nowDateTime, "version": gorm.Expr("version + 1")}).Debug()
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrConcurrentUpdate
+		}
+		return nil
+	})
+}
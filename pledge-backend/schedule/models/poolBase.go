@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"errors"
 	"pledge-backend/db"
 	"pledge-backend/log"
@@ -10,28 +11,31 @@ import (
 )
 
 type PoolBase struct {
-	Id                     int    `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
-	PoolId                 int    `json:"pool_id" gorm:"column:pool_id"`
-	ChainId                string `json:"chain_id" gorm:"column:chain_id"`
-	SettleTime             string `json:"settle_time" gorm:"column:settle_time"`
-	EndTime                string `json:"end_time" gorm:"column:end_time"`
-	InterestRate           string `json:"interest_rate" gorm:"column:interest_rate"`
-	MaxSupply              string `json:"max_supply" gorm:"max_supply:"`
-	LendSupply             string `json:"lend_supply" gorm:"column:lend_supply"`
-	BorrowSupply           string `json:"borrow_supply" gorm:"column:borrow_supply"`
-	MartgageRate           string `json:"martgage_rate" gorm:"column:martgage_rate"`
-	LendToken              string `json:"lend_token" gorm:"column:lend_token"`
-	LendTokenInfo          string `json:"lend_token_info" gorm:"column:lend_token_info"`
-	BorrowToken            string `json:"borrow_token" gorm:"column:borrow_token"`
-	BorrowTokenInfo        string `json:"borrow_token_info" gorm:"column:borrow_token_info"`
-	State                  string `json:"state" gorm:"column:state"`
-	SpCoin                 string `json:"sp_coin" gorm:"column:sp_coin"`
-	JpCoin                 string `json:"jp_coin" gorm:"column:jp_coin"`
-	LendTokenSymbol        string `json:"lend_token_symbol" gorm:"column:lend_token_symbol"`
-	BorrowTokenSymbol      string `json:"borrow_token_symbol" gorm:"column:borrow_token_symbol"`
-	AutoLiquidateThreshold string `json:"auto_liquidate_threshold" gorm:"column:auto_liquidate_threshold"`
-	CreatedAt              string `json:"created_at" gorm:"column:created_at"`
-	UpdatedAt              string `json:"updated_at" gorm:"column:updated_at"`
+	Id                     int     `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	PoolId                 int     `json:"pool_id" gorm:"column:pool_id"`
+	ChainId                string  `json:"chain_id" gorm:"column:chain_id"`
+	SettleTime             string  `json:"settle_time" gorm:"column:settle_time"`
+	EndTime                string  `json:"end_time" gorm:"column:end_time"`
+	InterestRate           string  `json:"interest_rate" gorm:"column:interest_rate"`
+	MaxSupply              string  `json:"max_supply" gorm:"max_supply:"`
+	LendSupply             string  `json:"lend_supply" gorm:"column:lend_supply"`
+	BorrowSupply           string  `json:"borrow_supply" gorm:"column:borrow_supply"`
+	MartgageRate           string  `json:"martgage_rate" gorm:"column:martgage_rate"`
+	LendToken              string  `json:"lend_token" gorm:"column:lend_token"`
+	LendTokenInfo          string  `json:"lend_token_info" gorm:"column:lend_token_info"`
+	BorrowToken            string  `json:"borrow_token" gorm:"column:borrow_token"`
+	BorrowTokenInfo        string  `json:"borrow_token_info" gorm:"column:borrow_token_info"`
+	State                  string  `json:"state" gorm:"column:state"`
+	SpCoin                 string  `json:"sp_coin" gorm:"column:sp_coin"`
+	JpCoin                 string  `json:"jp_coin" gorm:"column:jp_coin"`
+	LendTokenSymbol        string  `json:"lend_token_symbol" gorm:"column:lend_token_symbol"`
+	BorrowTokenSymbol      string  `json:"borrow_token_symbol" gorm:"column:borrow_token_symbol"`
+	AutoLiquidateThreshold string  `json:"auto_liquidate_threshold" gorm:"column:auto_liquidate_threshold"`
+	CreatedAt              string  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt              string  `json:"updated_at" gorm:"column:updated_at"`
+	Version                int64   `json:"-" gorm:"column:version;not null;default:0"`  // 每次更新加 1，并发写入以此检查记录是否已被修改
+	DeletedAt              *string `json:"deleted_at" gorm:"column:deleted_at"`         // 池子超出链上 poolLength 的时间，例如合约重新部署
+	ArchivedAt             *string `json:"archived_at" gorm:"column:archived_at;index"` // 结束超过 [schedule] archive_grace_days 后归档的时间，归档后不再同步
 }
 
 type BorrowToken struct {
@@ -56,44 +60,94 @@ func (p *PoolBase) TableName() string {
 	return "poolbases"
 }
 
-// SavePoolBase Save poolBase information
-func (p *PoolBase) SavePoolBase(chainId, poolId string, poolBase *PoolBase) error {
+// Changes 与 old 相比发生变化的列，不比较 id、created_at、updated_at、version
+func (p *PoolBase) Changes(old *PoolBase) ColumnChanges {
+	changes := ColumnChanges{}
+	changes.compare("settle_time", old.SettleTime, p.SettleTime)
+	changes.compare("end_time", old.EndTime, p.EndTime)
+	changes.compare("interest_rate", old.InterestRate, p.InterestRate)
+	changes.compare("max_supply", old.MaxSupply, p.MaxSupply)
+	changes.compare("lend_supply", old.LendSupply, p.LendSupply)
+	changes.compare("borrow_supply", old.BorrowSupply, p.BorrowSupply)
+	changes.compare("martgage_rate", old.MartgageRate, p.MartgageRate)
+	changes.compare("lend_token", old.LendToken, p.LendToken)
+	changes.compare("lend_token_info", old.LendTokenInfo, p.LendTokenInfo)
+	changes.compare("borrow_token", old.BorrowToken, p.BorrowToken)
+	changes.compare("borrow_token_info", old.BorrowTokenInfo, p.BorrowTokenInfo)
+	changes.compare("state", old.State, p.State)
+	changes.compare("sp_coin", old.SpCoin, p.SpCoin)
+	changes.compare("jp_coin", old.JpCoin, p.JpCoin)
+	changes.compare("lend_token_symbol", old.LendTokenSymbol, p.LendTokenSymbol)
+	changes.compare("borrow_token_symbol", old.BorrowTokenSymbol, p.BorrowTokenSymbol)
+	changes.compare("auto_liquidate_threshold", old.AutoLiquidateThreshold, p.AutoLiquidateThreshold)
+	return changes
+}
+
+// ErrConcurrentUpdate 读取后记录已被其它实例或进程修改，本次写入放弃，事务回滚
+var ErrConcurrentUpdate = errors.New("record was updated concurrently")
+
+// SavePoolWithData 在同一个事务中保存池子的 PoolBase 和 PoolData，任一写入失败时都不保存，ctx 用于链路追踪
+// 已有记录时只更新与数据库中不同的列，返回 PoolBase 是否新增或有列变化
+func (p *PoolBase) SavePoolWithData(ctx context.Context, chainId, poolId string, poolBase *PoolBase, poolData *PoolData) (bool, error) {
+	var changed bool
+	err := db.Mysql.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		changed, err = p.save(tx, chainId, poolId, poolBase)
+		if err != nil {
+			return err
+		}
+		return NewPoolData().save(tx, chainId, poolId, poolData)
+	})
+	return changed, err
+}
+
+// save 在事务 tx 中保存 poolBase
+// 更新时检查读取到的 version 并加 1，期间记录被修改则返回 ErrConcurrentUpdate
+func (p *PoolBase) save(tx *gorm.DB, chainId, poolId string, poolBase *PoolBase) (bool, error) {
 
 	nowDateTime := utils.GetCurDateTimeFormat()
 
 	//save token info
-	err, symbol := p.SaveTokenInfo(poolBase)
+	err, symbol := p.SaveTokenInfo(tx, poolBase)
 	if err != nil {
 		log.Logger.Error(err.Error())
-		return err
+		return false, err
 	}
 	poolBase.BorrowTokenSymbol = symbol[0]
 	poolBase.LendTokenSymbol = symbol[1]
 
 	// save pool info
-	err = db.Mysql.Table("poolbases").Where("chain_id=? and pool_id=?", chainId, poolId).First(&p).Debug().Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			poolBase.CreatedAt = nowDateTime
-			poolBase.UpdatedAt = nowDateTime
-			err = db.Mysql.Table("poolbases").Create(poolBase).Debug().Error
-			if err != nil {
-				log.Logger.Error(err.Error())
-				return err
-			}
-		} else {
-			return errors.New("record select err " + err.Error())
+	existing := PoolBase{}
+	err = tx.Table("poolbases").Where("chain_id=? and pool_id=?", chainId, poolId).First(&existing).Debug().Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		poolBase.CreatedAt = nowDateTime
+		poolBase.UpdatedAt = nowDateTime
+		err = tx.Table("poolbases").Create(poolBase).Debug().Error
+		if err != nil {
+			log.Logger.Error(err.Error())
+			return false, err
 		}
+		return true, nil
+	} else if err != nil {
+		return false, errors.New("record select err " + err.Error())
 	}
 
-	poolBase.UpdatedAt = nowDateTime
-	err = db.Mysql.Table("poolbases").Where("chain_id=? and pool_id=?", chainId, poolId).Updates(poolBase).Debug().Error
-	if err != nil {
-		log.Logger.Error(err.Error())
-		return err
+	changes := poolBase.Changes(&existing)
+	if len(changes) == 0 {
+		return false, nil
+	}
+	changes["updated_at"] = nowDateTime
+	changes["version"] = gorm.Expr("version + 1")
+	result := tx.Table("poolbases").Where("id=? and version=?", existing.Id, existing.Version).Updates(map[string]interface{}(changes)).Debug()
+	if result.Error != nil {
+		log.Logger.Error(result.Error.Error())
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, ErrConcurrentUpdate
 	}
 
-	return nil
+	return true, nil
 }
 
 func (p *PoolBase) PoolBaseInfo(res *PoolBase) error {
@@ -105,17 +159,43 @@ func (p *PoolBase) PoolBaseInfo(res *PoolBase) error {
 	return nil
 }
 
-func (p *PoolBase) SaveTokenInfo(base *PoolBase) (error, []string) {
+// ListByStates 查询 states 中未归档、未软删除的池子
+func (p *PoolBase) ListByStates(ctx context.Context, chainId string, states []string, res *[]PoolBase) error {
+	return db.Mysql.WithContext(ctx).Table("poolbases").
+		Where("chain_id=? and state in ? and archived_at is null and deleted_at is null", chainId, states).
+		Order("pool_id asc").Find(res).Debug().Error
+}
+
+// MarkRemoved 软删除 pool_id 超出链上池子总数 length 的池子，池子重新出现时恢复
+// 同时更新 updated_at 和 version，增量同步接口 (/changes) 据此返回移除或恢复的池子
+// 返回本次软删除的池子数
+func (p *PoolBase) MarkRemoved(ctx context.Context, chainId string, length int) (int64, error) {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	err := db.Mysql.WithContext(ctx).Table("poolbases").
+		Where("chain_id=? and pool_id<=? and deleted_at is not null", chainId, length).
+		Updates(map[string]interface{}{"deleted_at": nil, "updated_at": nowDateTime, "version": gorm.Expr("version + 1")}).Debug().Error
+	if err != nil {
+		return 0, err
+	}
+	result := db.Mysql.WithContext(ctx).Table("poolbases").
+		Where("chain_id=? and pool_id>? and deleted_at is null", chainId, length).
+		Updates(map[string]interface{}{"deleted_at": nowDateTime, "updated_at": nowDateTime, "version": gorm.Expr("version + 1")}).Debug()
+	return result.RowsAffected, result.Error
+}
+
+// SaveTokenInfo 池子的借出、抵押代币不在 token_info 中时新增，返回代币符号
+// tx 为 SavePoolBase 的事务
+func (p *PoolBase) SaveTokenInfo(tx *gorm.DB, base *PoolBase) (error, []string) {
 	tokenInfo := TokenInfo{}
 	tokenSymbol := []string{"", ""}
 	nowDateTime := utils.GetCurDateTimeFormat()
 
 	// borrowToken
-	err := db.Mysql.Table("token_info").Where("chain_id=? and token=?", base.ChainId, base.BorrowToken).First(&tokenInfo).Debug().Error
+	err := tx.Table("token_info").Where("chain_id=? and token=?", base.ChainId, base.BorrowToken).First(&tokenInfo).Debug().Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			tokenInfo.Token = base.BorrowToken
-			err = db.Mysql.Table("token_info").Create(&TokenInfo{
+			err = tx.Table("token_info").Create(&TokenInfo{
 				Token:     base.BorrowToken,
 				ChainId:   base.ChainId,
 				CreatedAt: nowDateTime,
@@ -125,6 +205,7 @@ func (p *PoolBase) SaveTokenInfo(base *PoolBase) (error, []string) {
 				log.Logger.Error(err.Error())
 				return err, tokenSymbol
 			}
+			InvalidateTokenInfoCache(base.ChainId)
 		} else {
 			return errors.New("token_info record select err " + err.Error()), tokenSymbol
 		}
@@ -133,11 +214,11 @@ func (p *PoolBase) SaveTokenInfo(base *PoolBase) (error, []string) {
 
 	//lendToken
 	tokenInfo = TokenInfo{}
-	err = db.Mysql.Table("token_info").Where("chain_id=? and token=?", base.ChainId, base.LendToken).First(&tokenInfo).Debug().Error
+	err = tx.Table("token_info").Where("chain_id=? and token=?", base.ChainId, base.LendToken).First(&tokenInfo).Debug().Error
 
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			err = db.Mysql.Table("token_info").Create(&TokenInfo{
+			err = tx.Table("token_info").Create(&TokenInfo{
 				Token:     base.LendToken,
 				ChainId:   base.ChainId,
 				CreatedAt: nowDateTime,
@@ -147,6 +228,7 @@ func (p *PoolBase) SaveTokenInfo(base *PoolBase) (error, []string) {
 				log.Logger.Error(err.Error())
 				return err, tokenSymbol
 			}
+			InvalidateTokenInfoCache(base.ChainId)
 		} else {
 			return errors.New("token_info record select err " + err.Error()), tokenSymbol
 		}
@@ -19,33 +19,57 @@ type PoolData struct {
 	SettleAmountLend       string `json:"settle_amount_lend" gorm:"column:settle_amount_lend"`
 	CreatedAt              string `json:"created_at" gorm:"column:created_at"`
 	UpdatedAt              string `json:"updated_at" gorm:"column:updated_at"`
+	Version                int64  `json:"-" gorm:"column:version;not null;default:0"` // 每次更新加 1，并发写入以此检查记录是否已被修改
 }
 
 func NewPoolData() *PoolData {
 	return &PoolData{}
 }
 
-// SavePoolData Save poolData information
-func (t *PoolData) SavePoolData(chainId, poolId string, poolData *PoolData) error {
+// Get 查询池子的 pooldata，没有时返回 gorm.ErrRecordNotFound
+func (t *PoolData) Get(chainId string, poolId int) error {
+	return db.Mysql.Table("pooldata").Where("chain_id=? and pool_id=?", chainId, utils.IntToString(poolId)).First(t).Debug().Error
+}
+
+// Changes 与 old 相比发生变化的列，不比较 id、created_at、updated_at、version
+func (t *PoolData) Changes(old *PoolData) ColumnChanges {
+	changes := ColumnChanges{}
+	changes.compare("finish_amount_borrow", old.FinishAmountBorrow, t.FinishAmountBorrow)
+	changes.compare("finish_amount_lend", old.FinishAmountLend, t.FinishAmountLend)
+	changes.compare("liquidation_amoun_borrow", old.LiquidationAmounBorrow, t.LiquidationAmounBorrow)
+	changes.compare("liquidation_amoun_lend", old.LiquidationAmounLend, t.LiquidationAmounLend)
+	changes.compare("settle_amount_borrow", old.SettleAmountBorrow, t.SettleAmountBorrow)
+	changes.compare("settle_amount_lend", old.SettleAmountLend, t.SettleAmountLend)
+	return changes
+}
+
+// save 在 SavePoolWithData 的事务 tx 中保存 poolData
+// 更新时检查读取到的 version 并加 1，期间记录被修改则返回 ErrConcurrentUpdate
+func (t *PoolData) save(tx *gorm.DB, chainId, poolId string, poolData *PoolData) error {
 
 	nowDateTime := utils.GetCurDateTimeFormat()
-	poolData.UpdatedAt = nowDateTime
-	err := db.Mysql.Table("pooldata").Where("chain_id=? and pool_id=?", chainId, poolId).First(&t).Debug().Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			poolData.CreatedAt = nowDateTime
-			err = db.Mysql.Table("pooldata").Create(poolData).Debug().Error
-			if err != nil {
-				return err
-			}
-		} else {
-			return errors.New("record select err " + err.Error())
-		}
+	existing := PoolData{}
+	err := tx.Table("pooldata").Where("chain_id=? and pool_id=?", chainId, poolId).First(&existing).Debug().Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		poolData.CreatedAt = nowDateTime
+		poolData.UpdatedAt = nowDateTime
+		return tx.Table("pooldata").Create(poolData).Debug().Error
+	} else if err != nil {
+		return errors.New("record select err " + err.Error())
 	}
 
-	err = db.Mysql.Table("pooldata").Where("chain_id=? and pool_id=?", chainId, poolId).Updates(poolData).Debug().Error
-	if err != nil {
-		return err
+	changes := poolData.Changes(&existing)
+	if len(changes) == 0 {
+		return nil
+	}
+	changes["updated_at"] = nowDateTime
+	changes["version"] = gorm.Expr("version + 1")
+	result := tx.Table("pooldata").Where("id=? and version=?", existing.Id, existing.Version).Updates(map[string]interface{}(changes)).Debug()
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrConcurrentUpdate
 	}
 	return nil
 }
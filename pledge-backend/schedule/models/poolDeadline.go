@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"pledge-backend/db"
+	"time"
+)
+
+// PoolDeadlineEventsKey 倒计时通知的 Redis 有序集合，score 为通知时间 (Unix 毫秒)，api 进程读取后推送到 deadline:<chainId> 主题
+const PoolDeadlineEventsKey = "pool_deadline_events"
+
+// poolDeadlineRetention 倒计时通知在 Redis 中保留的时间
+const poolDeadlineRetention = 24 * time.Hour
+
+// 倒计时的截止类型
+const (
+	PoolDeadlineSettle = "settle" // 匹配中池子的 settleTime，之后需要链上 settle
+	PoolDeadlineEnd    = "end"    // 执行中池子的 endTime，之后需要链上 finish
+)
+
+// PoolDeadline 一次倒计时通知，同时作为 WebSocket / SSE 消息和 webhook 请求体
+type PoolDeadline struct {
+	ChainId    string `json:"chain_id"`
+	PoolId     int    `json:"pool_id"`
+	State      string `json:"state"`
+	Kind       string `json:"kind"`        // settle / end
+	Action     string `json:"action"`      // 截止后需要的链上操作: settle / finish
+	Deadline   int64  `json:"deadline"`    // 截止时间, Unix 秒
+	Lead       string `json:"lead"`        // 命中的提前量，例如 1h
+	Remaining  int64  `json:"remaining"`   // 通知时距截止时间的秒数
+	NotifiedAt int64  `json:"notified_at"` // 通知时间, Unix 毫秒
+}
+
+func NewPoolDeadline() *PoolDeadline {
+	return &PoolDeadline{}
+}
+
+// Publish 写入倒计时通知，并删除超过保留时间的通知
+func (d *PoolDeadline) Publish(event *PoolDeadline) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err = db.RedisZAdd(PoolDeadlineEventsKey, event.NotifiedAt, string(data)); err != nil {
+		return err
+	}
+	return db.RedisZRemRangeByScore(PoolDeadlineEventsKey, 0, time.Now().Add(-poolDeadlineRetention).UnixMilli())
+}
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+)
+
+// pool_events.event
+const (
+	PoolEventDepositLend   = "deposit_lend"
+	PoolEventDepositBorrow = "deposit_borrow"
+)
+
+// PoolEventsReorgKey 链上最近一次回滚分叉事件的时间 (Unix 秒)，之前计算并缓存的统计需要重新计算
+const PoolEventsReorgKey = "pool_events_reorg:"
+
+// PoolEvent PledgePool 合约的存入事件，pool_id 从交易 input 中解析
+// 区块距最新区块不足 [indexer] confirmations 时 pending 为 true，分叉时删除后重新索引
+type PoolEvent struct {
+	Id          int    `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId     string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_tx_log,priority:1;index:idx_chain_pool_event,priority:1"`
+	PoolId      int    `json:"pool_id" gorm:"column:pool_id;index:idx_chain_pool_event,priority:2"`
+	Event       string `json:"event" gorm:"column:event;type:varchar(32);index:idx_chain_pool_event,priority:3"`
+	Address     string `json:"address" gorm:"column:address;type:varchar(64)"`
+	Token       string `json:"token" gorm:"column:token;type:varchar(64)"`
+	Amount      string `json:"amount" gorm:"column:amount;type:decimal(65,0)"`
+	BlockNumber uint64 `json:"block_number" gorm:"column:block_number"`
+	BlockTime   int64  `json:"block_time" gorm:"column:block_time;index"` // 区块时间, Unix 秒，新增该列之前索引的事件为 0
+	BlockHash   string `json:"block_hash" gorm:"column:block_hash;type:varchar(80)"`
+	TxHash      string `json:"tx_hash" gorm:"column:tx_hash;type:varchar(80);uniqueIndex:uk_chain_tx_log,priority:2"`
+	LogIndex    uint   `json:"log_index" gorm:"column:log_index;uniqueIndex:uk_chain_tx_log,priority:3"`
+	Pending     bool   `json:"pending" gorm:"column:pending;default:false"` // 尚未达到确认数
+	CreatedAt   string `json:"created_at" gorm:"column:created_at"`
+}
+
+// EventCursor 每个合约已索引到的区块
+type EventCursor struct {
+	Id          int    `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId     string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_contract,priority:1"`
+	Contract    string `json:"contract" gorm:"column:contract;type:varchar(64);uniqueIndex:uk_chain_contract,priority:2"`
+	BlockNumber uint64 `json:"block_number" gorm:"column:block_number"`
+	BlockTime   int64  `json:"block_time" gorm:"column:block_time;index"` // 区块时间, Unix 秒，新增该列之前索引的事件为 0
+	UpdatedAt   string `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// IndexedBlock 已扫描但尚未达到确认数的区块哈希，用于发现分叉: 有事件的区块和每次扫描的最后一个区块
+// 达到确认数后删除
+type IndexedBlock struct {
+	Id          int    `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId     string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_contract_block,priority:1"`
+	Contract    string `json:"contract" gorm:"column:contract;type:varchar(64);uniqueIndex:uk_chain_contract_block,priority:2"`
+	BlockNumber uint64 `json:"block_number" gorm:"column:block_number;uniqueIndex:uk_chain_contract_block,priority:3"`
+	BlockHash   string `json:"block_hash" gorm:"column:block_hash;type:varchar(80)"`
+	CreatedAt   string `json:"created_at" gorm:"column:created_at"`
+}
+
+func (b *IndexedBlock) TableName() string {
+	return "indexed_blocks"
+}
+
+func NewPoolEvent() *PoolEvent {
+	return &PoolEvent{}
+}
+
+func (e *PoolEvent) TableName() string {
+	return "pool_events"
+}
+
+func (c *EventCursor) TableName() string {
+	return "event_cursor"
+}
+
+// GetCursor 查询合约已索引到的区块，没有记录时返回 0
+func (e *PoolEvent) GetCursor(chainId, contract string) (error, uint64) {
+	cursor := EventCursor{}
+	err := db.Mysql.Table("event_cursor").Where("chain_id=? and contract=?", chainId, contract).First(&cursor).Debug().Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0
+		}
+		return err, 0
+	}
+	return nil, cursor.BlockNumber
+}
+
+// SaveEvents 写入一批事件和未确认区块的哈希，把 finalized 及之前的事件标记为已确认并推进游标
+// 重复的事件 (chain_id, tx_hash, log_index) 忽略
+func (e *PoolEvent) SaveEvents(chainId, contract string, events []PoolEvent, blocks []IndexedBlock, finalized uint64) error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	return db.Mysql.Transaction(func(tx *gorm.DB) error {
+		if len(events) > 0 {
+			for i := range events {
+				events[i].CreatedAt = nowDateTime
+			}
+			err := tx.Table("pool_events").Clauses(clause.OnConflict{DoNothing: true}).Create(&events).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(blocks) > 0 {
+			for i := range blocks {
+				blocks[i].ChainId, blocks[i].Contract, blocks[i].CreatedAt = chainId, contract, nowDateTime
+			}
+			err := tx.Table("indexed_blocks").Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "chain_id"}, {Name: "contract"}, {Name: "block_number"}},
+				DoUpdates: clause.AssignmentColumns([]string{"block_hash"}),
+			}).Create(&blocks).Error
+			if err != nil {
+				return err
+			}
+		}
+		err := tx.Table("pool_events").Where("chain_id=? and pending=? and block_number<=?", chainId, true, finalized).
+			Update("pending", false).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Table("indexed_blocks").Where("chain_id=? and contract=? and block_number<=?", chainId, contract, finalized).
+			Delete(&IndexedBlock{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Table("event_cursor").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "chain_id"}, {Name: "contract"}},
+			DoUpdates: clause.AssignmentColumns([]string{"block_number", "updated_at"}),
+		}).Create(&EventCursor{
+			ChainId:     chainId,
+			Contract:    contract,
+			BlockNumber: finalized,
+			UpdatedAt:   nowDateTime,
+		}).Error
+	})
+}
+
+// IndexedBlocks 合约已扫描但尚未确认的区块，从高到低
+func (e *PoolEvent) IndexedBlocks(chainId, contract string, res *[]IndexedBlock) error {
+	return db.Mysql.Table("indexed_blocks").Where("chain_id=? and contract=?", chainId, contract).
+		Order("block_number desc").Find(res).Debug().Error
+}
+
+// Rollback 分叉后删除 fromBlock 及之后的未确认事件和区块哈希，
+// 依赖这些事件校验的推荐归属恢复为 pending，由 VerifyReferrals 按重新索引的事件再次校验；返回删除的事件
+func (e *PoolEvent) Rollback(chainId, contract string, fromBlock uint64) ([]PoolEvent, error) {
+	var removed []PoolEvent
+	err := db.Mysql.Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("pool_events").Where("chain_id=? and pending=? and block_number>=?", chainId, true, fromBlock).
+			Find(&removed).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Table("indexed_blocks").Where("chain_id=? and contract=? and block_number>=?", chainId, contract, fromBlock).
+			Delete(&IndexedBlock{}).Error
+		if err != nil || len(removed) == 0 {
+			return err
+		}
+		err = tx.Table("pool_events").Where("chain_id=? and pending=? and block_number>=?", chainId, true, fromBlock).
+			Delete(&PoolEvent{}).Error
+		if err != nil {
+			return err
+		}
+		txHashes := make([]string, 0, len(removed))
+		for _, event := range removed {
+			txHashes = append(txHashes, event.TxHash)
+		}
+		return tx.Table("referral_attributions").Where("chain_id=? and tx_hash in ? and status<>?", chainId, txHashes, ReferralPending).
+			Updates(map[string]interface{}{
+				"status":      ReferralPending,
+				"reason":      "",
+				"referee":     "",
+				"pool_id":     0,
+				"event":       "",
+				"token":       "",
+				"amount":      "0",
+				"block_time":  0,
+				"verified_at": nil,
+				"updated_at":  utils.GetCurDateTimeFormat(),
+			}).Error
+	})
+	return removed, err
+}
+
+// HasDeposits 地址是否在池子中有存入 (出借或借款)
+func (e *PoolEvent) HasDeposits(chainId string, poolId int, address string) (bool, error) {
+	var count int64
+	err := db.Mysql.Table("pool_events").Where("chain_id=? and pool_id=? and address=?", chainId, poolId, address).Count(&count).Debug().Error
+	if err != nil {
+		return false, errors.New("record select err " + err.Error())
+	}
+	return count > 0, nil
+}
+
+// FirstByTx 交易中的第一个存入事件，没有时返回 gorm.ErrRecordNotFound
+func (e *PoolEvent) FirstByTx(chainId, txHash string) error {
+	return db.Mysql.Table("pool_events").Where("chain_id=? and tx_hash=?", chainId, txHash).
+		Order("log_index asc").First(e).Debug().Error
+}
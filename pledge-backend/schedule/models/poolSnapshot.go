@@ -0,0 +1,78 @@
+package models
+
+import (
+	"pledge-backend/db"
+	"pledge-backend/utils"
+	"time"
+)
+
+// PoolSnapshotInterval 池子数据没有变化时，最多间隔多久补一条快照, s
+const PoolSnapshotInterval = 3600
+
+// PoolSnapshot 池子数据的历史快照，用于前端绘制供给量和状态变化曲线
+type PoolSnapshot struct {
+	Id                 int    `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId            string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);index:idx_chain_pool_time,priority:1"`
+	PoolId             int    `json:"pool_id" gorm:"column:pool_id;index:idx_chain_pool_time,priority:2"`
+	State              string `json:"state" gorm:"column:state"`
+	LendSupply         string `json:"lend_supply" gorm:"column:lend_supply"`
+	BorrowSupply       string `json:"borrow_supply" gorm:"column:borrow_supply"`
+	SettleAmountLend   string `json:"settle_amount_lend" gorm:"column:settle_amount_lend"`
+	SettleAmountBorrow string `json:"settle_amount_borrow" gorm:"column:settle_amount_borrow"`
+	BlockNumber        uint64 `json:"block_number" gorm:"column:block_number"` // 读取数据的区块，同一轮同步的池子相同
+	SnapshotAt         int64  `json:"snapshot_at" gorm:"column:snapshot_at;index:idx_chain_pool_time,priority:3"`
+	CreatedAt          string `json:"created_at" gorm:"column:created_at"`
+}
+
+func NewPoolSnapshot() *PoolSnapshot {
+	return &PoolSnapshot{}
+}
+
+func (p *PoolSnapshot) TableName() string {
+	return "pool_snapshots"
+}
+
+// Append 数据与上一条快照不同，或距上一条快照超过 PoolSnapshotInterval 时写入新快照
+func (p *PoolSnapshot) Append(snapshot *PoolSnapshot) error {
+	now := time.Now().Unix()
+	var latest []PoolSnapshot
+	err := db.Mysql.Table("pool_snapshots").Where("chain_id=? and pool_id=?", snapshot.ChainId, snapshot.PoolId).
+		Order("snapshot_at desc").Limit(1).Find(&latest).Debug().Error
+	if err != nil {
+		return err
+	}
+	if len(latest) > 0 && now-latest[0].SnapshotAt < PoolSnapshotInterval &&
+		latest[0].State == snapshot.State &&
+		latest[0].LendSupply == snapshot.LendSupply &&
+		latest[0].BorrowSupply == snapshot.BorrowSupply &&
+		latest[0].SettleAmountLend == snapshot.SettleAmountLend &&
+		latest[0].SettleAmountBorrow == snapshot.SettleAmountBorrow {
+		return nil
+	}
+
+	snapshot.SnapshotAt = now
+	snapshot.CreatedAt = utils.GetCurDateTimeFormat()
+	return db.Mysql.Table("pool_snapshots").Create(snapshot).Debug().Error
+}
+
+// LatestBefore 每个池子在 ts 之前的最后一条快照
+func (p *PoolSnapshot) LatestBefore(chainId string, ts int64, res *[]PoolSnapshot) error {
+	latest := db.Mysql.Table("pool_snapshots").Select("pool_id, max(snapshot_at)").
+		Where("chain_id=? and snapshot_at<?", chainId, ts).Group("pool_id")
+	return db.Mysql.Table("pool_snapshots").Where("chain_id=? and (pool_id, snapshot_at) in (?)", chainId, latest).
+		Find(res).Debug().Error
+}
+
+// Between [from, to) 内的快照，按池子、时间升序
+func (p *PoolSnapshot) Between(chainId string, from, to int64, res *[]PoolSnapshot) error {
+	return db.Mysql.Table("pool_snapshots").Where("chain_id=? and snapshot_at>=? and snapshot_at<?", chainId, from, to).
+		Order("pool_id asc, snapshot_at asc").Find(res).Debug().Error
+}
+
+// FirstInState 池子第一条处于 state 的快照时间，没有时返回 0
+func (p *PoolSnapshot) FirstInState(chainId string, poolId int, state string) (int64, error) {
+	var first int64
+	err := db.Mysql.Table("pool_snapshots").Select("COALESCE(MIN(snapshot_at), 0)").
+		Where("chain_id=? and pool_id=? and state=?", chainId, poolId, state).Scan(&first).Debug().Error
+	return first, err
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"pledge-backend/db"
+	"pledge-backend/utils"
+)
+
+// PoolWhitelist 池子的链上权限状态，由 SyncPrivileges 写入 Redis pool_whitelist:<chainId>:<poolId>
+// 供 api 的 /pool/:chainId/:poolId/whitelist 读取，前端在发送交易前判断是否会因权限失败
+type PoolWhitelist struct {
+	ChainId   string           `json:"chain_id"`
+	PoolId    int              `json:"pool_id"`
+	Pool      string           `json:"pool"`    // PledgePool 合约地址
+	Paused    bool             `json:"paused"`  // PledgePool.globalPaused，暂停时存入、领取都会失败
+	SpCoin    DebtTokenMinters `json:"sp_coin"` // 出借凭证，claimLend 时由 PledgePool 铸造
+	JpCoin    DebtTokenMinters `json:"jp_coin"` // 借款凭证，claimBorrow 时由 PledgePool 铸造
+	Block     uint64           `json:"block"`   // 读取的区块
+	UpdatedAt int64            `json:"updated_at"`
+}
+
+// DebtTokenMinters DebtToken (继承 AddressPrivileges) 的 minter 白名单
+type DebtTokenMinters struct {
+	Address string   `json:"address"`
+	Minters []string `json:"minters"`
+}
+
+func NewPoolWhitelist() *PoolWhitelist {
+	return &PoolWhitelist{}
+}
+
+// PoolWhitelistRedisKey 池子的权限状态
+func PoolWhitelistRedisKey(chainId string, poolId int) string {
+	return "pool_whitelist:" + chainId + ":" + utils.IntToString(poolId)
+}
+
+// Save 保存权限状态，aliveSeconds 后过期，同步停止时 api 不会一直返回旧的白名单
+func (p *PoolWhitelist) Save(aliveSeconds int) error {
+	return db.RedisSet(PoolWhitelistRedisKey(p.ChainId, p.PoolId), p, aliveSeconds)
+}
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"errors"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// price_backfills.status
+const (
+	BackfillPending = "pending" // 已提交，尚未确定回填的最后一个区块
+	BackfillRunning = "running"
+	BackfillDone    = "done"
+	BackfillFailed  = "failed" // 读取价格失败，例如节点不是归档节点，重新提交后继续
+)
+
+// ErrBackfillMoved 检查点已被其它执行者推进，或回填已被重新提交，本次执行停止
+var ErrBackfillMoved = errors.New("price backfill checkpoint moved")
+
+// PriceBackfill 代币的历史价格回填，每个代币一条，由管理员接口或 pledge backfill-prices 提交，BackfillPriceHistory 执行
+// 从 from_block 开始每隔 [backfill] step_blocks 个区块读取一次 Oracle 价格，next_block 为检查点，中断后从该区块继续
+type PriceBackfill struct {
+	Id        int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId   string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_token,priority:1"`
+	Token     string `json:"token" gorm:"column:token;type:varchar(64);uniqueIndex:uk_chain_token,priority:2"`
+	FromBlock uint64 `json:"from_block" gorm:"column:from_block"`
+	ToBlock   uint64 `json:"to_block" gorm:"column:to_block"`     // 开始执行时确定: 代币第一条价格历史之前的区块，没有历史时为当时的最新区块
+	NextBlock uint64 `json:"next_block" gorm:"column:next_block"` // 下一个读取价格的区块
+	LastPrice string `json:"last_price" gorm:"column:last_price"` // 上一个采样区块的价格，1e8 精度，价格变化时才写入历史
+	Inserted  int64  `json:"inserted" gorm:"column:inserted"`     // 已写入 token_price_history 的记录数
+	Status    string `json:"status" gorm:"column:status;type:varchar(16);index"`
+	Error     string `json:"error" gorm:"column:error;type:varchar(512)"`
+	CreatedAt string `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt string `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func NewPriceBackfill() *PriceBackfill {
+	return &PriceBackfill{}
+}
+
+func (b *PriceBackfill) TableName() string {
+	return "price_backfills"
+}
+
+// Unfinished 等待执行和执行中的回填，先提交的在前
+func (b *PriceBackfill) Unfinished(res *[]PriceBackfill) error {
+	return db.Mysql.Table("price_backfills").Where("status in ?", []string{BackfillPending, BackfillRunning}).
+		Order("id asc").Find(res).Debug().Error
+}
+
+// Submit 提交代币的回填: 未完成的回填保持不变，从检查点继续；
+// 失败的回填 from_block 相同时从检查点继续，其它情况从 fromBlock 重新开始
+func (b *PriceBackfill) Submit(chainId, token string, fromBlock uint64) error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	err := db.Mysql.Table("price_backfills").Where("chain_id=? and token=?", chainId, token).First(b).Debug().Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		*b = PriceBackfill{
+			ChainId:   chainId,
+			Token:     token,
+			FromBlock: fromBlock,
+			NextBlock: fromBlock,
+			Status:    BackfillPending,
+			CreatedAt: nowDateTime,
+			UpdatedAt: nowDateTime,
+		}
+		return db.Mysql.Table("price_backfills").Create(b).Debug().Error
+	} else if err != nil {
+		return err
+	}
+
+	switch {
+	case b.Status == BackfillPending || b.Status == BackfillRunning:
+		return nil
+	case b.Status == BackfillFailed && b.FromBlock == fromBlock:
+		b.Status = BackfillRunning
+		if b.ToBlock == 0 {
+			b.Status = BackfillPending
+		}
+	default:
+		b.FromBlock, b.ToBlock, b.NextBlock = fromBlock, 0, fromBlock
+		b.LastPrice, b.Inserted = "", 0
+		b.Status = BackfillPending
+	}
+	b.Error, b.UpdatedAt = "", nowDateTime
+	return db.Mysql.Table("price_backfills").Where("id=?", b.Id).Updates(map[string]interface{}{
+		"from_block": b.FromBlock,
+		"to_block":   b.ToBlock,
+		"next_block": b.NextBlock,
+		"last_price": b.LastPrice,
+		"inserted":   b.Inserted,
+		"status":     b.Status,
+		"error":      b.Error,
+		"updated_at": b.UpdatedAt,
+	}).Debug().Error
+}
+
+// Start 确定回填的最后一个区块，开始执行
+func (b *PriceBackfill) Start(toBlock uint64) error {
+	return b.update(b.Status, map[string]interface{}{
+		"to_block": toBlock,
+		"status":   BackfillRunning,
+	})
+}
+
+// Checkpoint 写入采样得到的价格变化并推进检查点，在同一个事务中完成，中断后不会重复写入
+func (b *PriceBackfill) Checkpoint(nextBlock uint64, lastPrice string, history []TokenPriceHistory) error {
+	err := db.Mysql.Transaction(func(tx *gorm.DB) error {
+		res := tx.Table("price_backfills").Where("id=? and next_block=? and status=?", b.Id, b.NextBlock, BackfillRunning).Updates(map[string]interface{}{
+			"next_block": nextBlock,
+			"last_price": lastPrice,
+			"inserted":   gorm.Expr("inserted+?", len(history)),
+			"updated_at": utils.GetCurDateTimeFormat(),
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrBackfillMoved
+		}
+		if len(history) == 0 {
+			return nil
+		}
+		return tx.Table("token_price_history").CreateInBatches(&history, 100).Error
+	})
+	if err != nil {
+		return err
+	}
+	b.NextBlock, b.LastPrice = nextBlock, lastPrice
+	b.Inserted += int64(len(history))
+	return nil
+}
+
+// Finish 已回填到 to_block
+func (b *PriceBackfill) Finish() error {
+	return b.update(BackfillRunning, map[string]interface{}{"status": BackfillDone})
+}
+
+// Fail 读取价格失败，保留检查点
+func (b *PriceBackfill) Fail(reason string) error {
+	if len(reason) > 512 {
+		reason = reason[:512]
+	}
+	return b.update(b.Status, map[string]interface{}{"status": BackfillFailed, "error": reason})
+}
+
+// update 只在状态和检查点都没有变化时更新，否则返回 ErrBackfillMoved
+func (b *PriceBackfill) update(status string, fields map[string]interface{}) error {
+	fields["updated_at"] = utils.GetCurDateTimeFormat()
+	res := db.Mysql.Table("price_backfills").Where("id=? and next_block=? and status=?", b.Id, b.NextBlock, status).Updates(fields).Debug()
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrBackfillMoved
+	}
+	if s, ok := fields["status"].(string); ok {
+		b.Status = s
+	}
+	if to, ok := fields["to_block"].(uint64); ok {
+		b.ToBlock = to
+	}
+	return nil
+}
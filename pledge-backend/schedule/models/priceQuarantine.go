@@ -0,0 +1,25 @@
+package models
+
+const (
+	QuarantinePending  = "pending"
+	QuarantineApproved = "approved"
+	QuarantineRejected = "rejected"
+)
+
+// PriceQuarantine 被异常检测拦截的价格，等待管理员审核
+type PriceQuarantine struct {
+	Id        int32  `json:"id" gorm:"column:id;primaryKey"`
+	Token     string `json:"token" gorm:"column:token"`
+	Symbol    string `json:"symbol" gorm:"column:symbol"`
+	ChainId   string `json:"chain_id" gorm:"column:chain_id"`
+	Price     string `json:"price" gorm:"column:price"`
+	LastPrice string `json:"last_price" gorm:"column:last_price"`
+	Reason    string `json:"reason" gorm:"column:reason"`
+	Status    string `json:"status" gorm:"column:status"`
+	CreatedAt string `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt string `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func (m *PriceQuarantine) TableName() string {
+	return "price_quarantine"
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"pledge-backend/db"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ReadBlock 同步任务最近一次读取链上状态的区块，写入 Redis 哈希 read_blocks:<chainId>，字段为任务名称
+// 供 api 的 /network/:chainId 返回，排查接口数据与链上不一致时使用
+type ReadBlock struct {
+	Block  uint64 `json:"block"`   // 读取的区块 = latest - read_lag
+	Latest uint64 `json:"latest"`  // 读取时的最新区块
+	ReadAt int64  `json:"read_at"` // 读取时间, Unix 秒
+}
+
+func NewReadBlock() *ReadBlock {
+	return &ReadBlock{}
+}
+
+// ReadBlocksRedisKey 链上各同步任务读取的区块
+func ReadBlocksRedisKey(chainId string) string {
+	return "read_blocks:" + chainId
+}
+
+// Save 记录任务 job 在链 chainId 上读取的区块
+func (r *ReadBlock) Save(chainId, job string) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return db.RedisSetHash(ReadBlocksRedisKey(chainId), map[string]string{job: string(data)}, nil)
+}
+
+// Get 任务 job 在链 chainId 上最近一次读取的区块，没有记录时返回 redis.ErrNil
+func (r *ReadBlock) Get(chainId, job string) error {
+	blocks, err := db.RedisGetHash(ReadBlocksRedisKey(chainId))
+	if err != nil {
+		return err
+	}
+	data, ok := blocks[job]
+	if !ok {
+		return redis.ErrNil
+	}
+	return json.Unmarshal([]byte(data), r)
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"context"
+	"pledge-backend/db"
+	"pledge-backend/utils"
+)
+
+// referral_attributions.status
+const (
+	ReferralPending  = "pending"
+	ReferralVerified = "verified"
+	ReferralRejected = "rejected"
+)
+
+// ReferralAttribution 存入交易与推荐码的归属，由 api 写入，尚未索引的交易由 VerifyReferrals 校验
+type ReferralAttribution struct {
+	Id         int     `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId    string  `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_chain_tx,priority:1;index:idx_chain_referrer,priority:1"`
+	TxHash     string  `json:"tx_hash" gorm:"column:tx_hash;type:varchar(80);uniqueIndex:uk_chain_tx,priority:2"`
+	Code       string  `json:"code" gorm:"column:code;type:varchar(16)"`
+	Referrer   string  `json:"referrer" gorm:"column:referrer;type:varchar(42);index:idx_chain_referrer,priority:2"`
+	Referee    string  `json:"referee" gorm:"column:referee;type:varchar(42)"`
+	PoolId     int     `json:"pool_id" gorm:"column:pool_id"`
+	Event      string  `json:"event" gorm:"column:event;type:varchar(32)"`
+	Token      string  `json:"token" gorm:"column:token;type:varchar(64)"`
+	Amount     string  `json:"amount" gorm:"column:amount;type:decimal(65,0)"`
+	BlockTime  int64   `json:"block_time" gorm:"column:block_time"`
+	Status     string  `json:"status" gorm:"column:status;type:varchar(16);index"`
+	Reason     string  `json:"reason" gorm:"column:reason;type:varchar(32)"` // rejected 的原因: self / not_found
+	VerifiedAt *string `json:"verified_at" gorm:"column:verified_at"`
+	CreatedAt  string  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt  string  `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func NewReferralAttribution() *ReferralAttribution {
+	return &ReferralAttribution{}
+}
+
+func (r *ReferralAttribution) TableName() string {
+	return "referral_attributions"
+}
+
+// Pending 等待校验的归属，先提交的在前
+func (r *ReferralAttribution) Pending(ctx context.Context, chainId string, res *[]ReferralAttribution) error {
+	return db.Mysql.WithContext(ctx).Table("referral_attributions").Where("chain_id=? and status=?", chainId, ReferralPending).
+		Order("id asc").Find(res).Debug().Error
+}
+
+// Verify 交易已被索引，记录存入信息
+func (r *ReferralAttribution) Verify(event *PoolEvent) error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	return db.Mysql.Table("referral_attributions").Where("id=? and status=?", r.Id, ReferralPending).Updates(map[string]interface{}{
+		"status":      ReferralVerified,
+		"referee":     event.Address,
+		"pool_id":     event.PoolId,
+		"event":       event.Event,
+		"token":       event.Token,
+		"amount":      event.Amount,
+		"block_time":  event.BlockTime,
+		"verified_at": nowDateTime,
+		"updated_at":  nowDateTime,
+	}).Debug().Error
+}
+
+// Reject 标记归属无效
+func (r *ReferralAttribution) Reject(reason string) error {
+	return db.Mysql.Table("referral_attributions").Where("id=? and status=?", r.Id, ReferralPending).Updates(map[string]interface{}{
+		"status":     ReferralRejected,
+		"reason":     reason,
+		"updated_at": utils.GetCurDateTimeFormat(),
+	}).Debug().Error
+}
@@ -0,0 +1,14 @@
+package models
+
+// SearchTerm 代币搜索索引，每个代币按 symbol 的所有后缀、name 的单词、地址拆成多条 term，
+// 模糊搜索转换为 term 的前缀匹配，可以走 (chain_id, term) 索引，不需要对 token_info 做 LIKE '%x%' 扫描
+type SearchTerm struct {
+	Id      int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);index:idx_chain_term,priority:1"`
+	Term    string `json:"term" gorm:"column:term;type:varchar(64);index:idx_chain_term,priority:2"`
+	Token   string `json:"token" gorm:"column:token;type:varchar(64)"`
+}
+
+func (s *SearchTerm) TableName() string {
+	return "search_term"
+}
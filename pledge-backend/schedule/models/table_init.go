@@ -7,4 +7,25 @@ func InitTable() {
 	db.Mysql.AutoMigrate(&PoolData{})
 	db.Mysql.AutoMigrate(&RedisTokenInfo{})
 	db.Mysql.AutoMigrate(&TokenInfo{})
+	db.Mysql.AutoMigrate(&PriceQuarantine{})
+	db.Mysql.AutoMigrate(&TokenLogoOverride{})
+	db.Mysql.AutoMigrate(&SearchTerm{})
+	db.Mysql.AutoMigrate(&PoolSnapshot{})
+	db.Mysql.AutoMigrate(&DailyReport{})
+	db.Mysql.AutoMigrate(&PoolEvent{})
+	db.Mysql.AutoMigrate(&EventCursor{})
+	db.Mysql.AutoMigrate(&IndexedBlock{})
+	db.Mysql.AutoMigrate(&TokenPriceHistory{})
+	db.Mysql.AutoMigrate(&AlertHistory{})
+	db.Mysql.AutoMigrate(&GasSpend{})
+	db.Mysql.AutoMigrate(&ChainHealth{})
+	db.Mysql.AutoMigrate(&JobRun{})
+	db.Mysql.AutoMigrate(&JobRetry{})
+	db.Mysql.AutoMigrate(&PoolArchive{})
+	db.Mysql.AutoMigrate(&KeeperTx{})
+	db.Mysql.AutoMigrate(&EmailSubscription{})
+	db.Mysql.AutoMigrate(&EmailNotification{})
+	db.Mysql.AutoMigrate(&FeeRevenue{})
+	db.Mysql.AutoMigrate(&ReferralAttribution{})
+	db.Mysql.AutoMigrate(&PriceBackfill{})
 }
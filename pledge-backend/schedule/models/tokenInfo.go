@@ -5,25 +5,72 @@ import (
 	"errors"
 	"gorm.io/gorm"
 	"pledge-backend/db"
+	"pledge-backend/utils"
 )
 
 type TokenInfo struct {
-	Id           int    `gorm:"column:id;primaryKey"`
-	Logo         string `json:"logo" gorm:"column:logo"`
-	Token        string `json:"token" gorm:"column:token"`
-	Symbol       string `json:"symbol" gorm:"column:symbol"`
-	ChainId      string `json:"chain_id" gorm:"column:chain_id"`
-	Price        string `json:"price" gorm:"column:price"`
-	Decimals     int    `json:"decimals" gorm:"column:decimals"`
-	AbiFileExist int    `json:"abi_file_exist" gorm:"column:abi_file_exist"`
-	CreatedAt    string `json:"created_at" gorm:"column:created_at"`
-	UpdatedAt    string `json:"updated_at" gorm:"column:updated_at"`
+	Id                 int     `gorm:"column:id;primaryKey"`
+	Logo               string  `json:"logo" gorm:"column:logo"`
+	Token              string  `json:"token" gorm:"column:token"`
+	Symbol             string  `json:"symbol" gorm:"column:symbol"`
+	Name               string  `json:"name" gorm:"column:name"`
+	ChainId            string  `json:"chain_id" gorm:"column:chain_id"`
+	Price              string  `json:"price" gorm:"column:price"`
+	Decimals           int     `json:"decimals" gorm:"column:decimals"`
+	AbiFileExist       int     `json:"abi_file_exist" gorm:"column:abi_file_exist"`
+	CoingeckoId        string  `json:"coingecko_id" gorm:"column:coingecko_id"`
+	ChainlinkPrice     string  `json:"chainlink_price" gorm:"column:chainlink_price"`
+	ChainlinkUpdatedAt int64   `json:"chainlink_updated_at" gorm:"column:chainlink_updated_at"`
+	LogoSource         string  `json:"logo_source" gorm:"column:logo_source"`
+	LogoOriginUrl      string  `json:"logo_origin_url" gorm:"column:logo_origin_url"`
+	PriceSource        string  `json:"price_source" gorm:"column:price_source"`
+	CreatedAt          string  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt          string  `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt          *string `json:"deleted_at" gorm:"column:deleted_at"`
+}
+
+// token_info.price_source，为空时按 交易所 -> Oracle -> CoinGecko 的顺序自动选择
+const (
+	PriceSourceAuto      = ""
+	PriceSourceOracle    = "oracle"
+	PriceSourceExchange  = "exchange"
+	PriceSourceCoingecko = "coingecko"
+)
+
+// TokenMetadata 从代币合约读取的元信息
+type TokenMetadata struct {
+	Name     string
+	Symbol   string
+	Decimals int
 }
 
 func NewTokenInfo() *TokenInfo {
 	return &TokenInfo{}
 }
 
+// SaveTokenInfo 按 chain_id + token 新增或更新代币，并清除代币缓存
+func (t *TokenInfo) SaveTokenInfo(tokenInfo *TokenInfo) error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	tokenInfo.UpdatedAt = nowDateTime
+	exist := TokenInfo{}
+	err := db.Mysql.Table("token_info").Where("chain_id=? and token=?", tokenInfo.ChainId, tokenInfo.Token).First(&exist).Debug().Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("record select err " + err.Error())
+		}
+		tokenInfo.CreatedAt = nowDateTime
+		err = db.Mysql.Table("token_info").Create(tokenInfo).Debug().Error
+	} else {
+		err = db.Mysql.Table("token_info").Where("id=?", exist.Id).Updates(tokenInfo).Debug().Error
+	}
+	if err != nil {
+		return err
+	}
+	_, _ = db.RedisDelete("token_info:" + tokenInfo.ChainId + ":" + tokenInfo.Token)
+	InvalidateTokenInfoCache(tokenInfo.ChainId)
+	return nil
+}
+
 // GetTokenInfo Get token information by token name
 func (t *TokenInfo) GetTokenInfo(token, chainId string) (error, TokenInfo) {
 
@@ -63,3 +110,8 @@ func (t *TokenInfo) GetTokenInfo(token, chainId string) (error, TokenInfo) {
 	}
 
 }
+
+// ListByChain 链上的所有代币，不经过缓存
+func (t *TokenInfo) ListByChain(chainId string, res *[]TokenInfo) error {
+	return db.Mysql.Table("token_info").Where("chain_id=?", chainId).Find(res).Debug().Error
+}
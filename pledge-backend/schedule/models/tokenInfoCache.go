@@ -0,0 +1,53 @@
+package models
+
+import (
+	"pledge-backend/db"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenInfoCacheTTL 进程内代币缓存的有效期
+// schedule 的代币任务写入 token_info 后立即清除缓存，这里只限制 api (管理员修改代币) 写入后的延迟
+const tokenInfoCacheTTL = 2 * time.Minute
+
+// tokenInfoChain 一条链的全部代币，key 为小写的代币地址
+type tokenInfoChain struct {
+	loadedAt time.Time
+	tokens   map[string]TokenInfo
+}
+
+// tokenInfoCache 同步池子时按链整体读取 token_info，一轮同步只查询一次 MySQL，而不是每个池子查询两次
+var tokenInfoCache = struct {
+	sync.Mutex
+	chains map[string]*tokenInfoChain
+}{chains: map[string]*tokenInfoChain{}}
+
+// GetCachedTokenInfo 从进程内缓存读取代币，缓存不存在或过期时读取链上的全部代币
+// 代币不在 token_info 中时返回空的 TokenInfo，与 GetTokenInfo 一致
+func (t *TokenInfo) GetCachedTokenInfo(token, chainId string) (error, TokenInfo) {
+	tokenInfoCache.Lock()
+	defer tokenInfoCache.Unlock()
+
+	chain, ok := tokenInfoCache.chains[chainId]
+	if !ok || time.Since(chain.loadedAt) > tokenInfoCacheTTL {
+		var list []TokenInfo
+		err := db.Mysql.Table("token_info").Where("chain_id=?", chainId).Find(&list).Debug().Error
+		if err != nil {
+			return err, TokenInfo{}
+		}
+		chain = &tokenInfoChain{loadedAt: time.Now(), tokens: make(map[string]TokenInfo, len(list))}
+		for _, v := range list {
+			chain.tokens[strings.ToLower(v.Token)] = v
+		}
+		tokenInfoCache.chains[chainId] = chain
+	}
+	return nil, chain.tokens[strings.ToLower(token)]
+}
+
+// InvalidateTokenInfoCache 清除链的进程内代币缓存，写入 token_info 后调用，下次读取时重新加载
+func InvalidateTokenInfoCache(chainId string) {
+	tokenInfoCache.Lock()
+	defer tokenInfoCache.Unlock()
+	delete(tokenInfoCache.chains, chainId)
+}
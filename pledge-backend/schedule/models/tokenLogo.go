@@ -25,3 +25,30 @@ type Token struct {
 	Decimals int    `json:"decimals"`
 	LogoURI  string `json:"logoURI"`
 }
+
+// TokenLogoOverride 本地覆盖的代币 logo，优先级高于所有远程来源
+// logo 可以是 storage 下的相对路径（例如 storage/img/BTC.png），也可以是完整 URL
+type TokenLogoOverride struct {
+	Id        int    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Token     string `json:"token" gorm:"column:token;type:varchar(64);uniqueIndex:uk_token_chain"`
+	ChainId   string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);uniqueIndex:uk_token_chain"`
+	Symbol    string `json:"symbol" gorm:"column:symbol"`
+	Decimals  int    `json:"decimals" gorm:"column:decimals"`
+	Logo      string `json:"logo" gorm:"column:logo"`
+	CreatedAt string `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt string `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func (t *TokenLogoOverride) TableName() string {
+	return "token_logo_override"
+}
+
+// CoingeckoCoin CoinGecko /coins 接口返回中与 logo 相关的字段
+type CoingeckoCoin struct {
+	Id    string `json:"id"`
+	Image struct {
+		Thumb string `json:"thumb"`
+		Small string `json:"small"`
+		Large string `json:"large"`
+	} `json:"image"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"pledge-backend/db"
+	"pledge-backend/utils"
+	"time"
+)
+
+// TokenPriceHistory 代币价格变化记录，每次价格变化写入一条，用于价格历史查询和数据导出
+type TokenPriceHistory struct {
+	Id        int    `json:"-" gorm:"column:id;primaryKey;autoIncrement"`
+	ChainId   string `json:"chain_id" gorm:"column:chain_id;type:varchar(16);index:idx_chain_token_time,priority:1"`
+	Token     string `json:"token" gorm:"column:token;type:varchar(64);index:idx_chain_token_time,priority:2"`
+	Price     string `json:"price" gorm:"column:price"` // 1e8 精度
+	PriceAt   int64  `json:"price_at" gorm:"column:price_at;index:idx_chain_token_time,priority:3"`
+	CreatedAt string `json:"created_at" gorm:"column:created_at"`
+}
+
+func NewTokenPriceHistory() *TokenPriceHistory {
+	return &TokenPriceHistory{}
+}
+
+func (t *TokenPriceHistory) TableName() string {
+	return "token_price_history"
+}
+
+// Append 记录一次价格变化
+func (t *TokenPriceHistory) Append(token, chainId, price string) error {
+	return db.Mysql.Table("token_price_history").Create(&TokenPriceHistory{
+		ChainId:   chainId,
+		Token:     token,
+		Price:     price,
+		PriceAt:   time.Now().Unix(),
+		CreatedAt: utils.GetCurDateTimeFormat(),
+	}).Debug().Error
+}
+
+// PriceBefore 代币在 ts 时的价格，即 ts 之前最后一次价格变化，没有记录时返回空字符串
+func (t *TokenPriceHistory) PriceBefore(chainId, token string, ts int64) (string, error) {
+	var res []TokenPriceHistory
+	err := db.Mysql.Table("token_price_history").Where("chain_id=? and token=? and price_at<=?", chainId, token, ts).
+		Order("price_at desc").Limit(1).Find(&res).Debug().Error
+	if err != nil || len(res) == 0 {
+		return "", err
+	}
+	return res[0].Price, nil
+}
+
+// FirstPriceAt 代币第一条价格记录的时间，没有记录时返回 0
+func (t *TokenPriceHistory) FirstPriceAt(chainId, token string) (int64, error) {
+	var res []int64
+	err := db.Mysql.Table("token_price_history").Where("chain_id=? and token=?", chainId, token).
+		Order("price_at asc").Limit(1).Pluck("price_at", &res).Debug().Error
+	if err != nil || len(res) == 0 {
+		return 0, err
+	}
+	return res[0], nil
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	"pledge-backend/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// backupIdLayout 备份 ID，即备份开始的 UTC 时间
+	backupIdLayout = "20060102T150405Z"
+	// backupTimeLayout DATETIME 列的写入格式，与 DSN 的 loc=Local 一致，恢复时按原值写回
+	backupTimeLayout = "2006-01-02 15:04:05.999999"
+	// backupBatchSize 恢复时每条 INSERT 的行数
+	backupBatchSize = 500
+)
+
+// BackupManifest 一次备份的清单，全部表上传成功后最后写入，没有清单的备份不完整，不能用于恢复
+type BackupManifest struct {
+	Id        string           `json:"id"`
+	CreatedAt int64            `json:"created_at"`
+	Tables    map[string]int64 `json:"tables"` // 表名: 行数
+}
+
+// Backup 备份链上无法重新同步的数据 (代币 logo、池子展示信息、多签配置等) 到 [export] 配置的 S3 兼容存储
+//
+// 每张表导出为 gzip 压缩的 JSON Lines，每行一个 列名: 值 的对象，NULL 为 null，其余值都是字符串，
+// 恢复时原样写回，由 MySQL 转换为列的类型
+type Backup struct{}
+
+func NewBackup() *Backup {
+	return &Backup{}
+}
+
+// BackupDatabase 备份 [backup] tables，并删除超过 retention_days 的备份，需要 [backup] enabled
+func (s *Backup) BackupDatabase(ctx context.Context) {
+	if !config.Config().Backup.Enabled {
+		return
+	}
+	manifest, err := s.Run(ctx)
+	if err != nil {
+		log.Logger.Sugar().Error("BackupDatabase err ", err)
+		return
+	}
+	log.Logger.Sugar().Info("BackupDatabase ", manifest.Id, " ", manifest.Tables)
+
+	if config.Config().Backup.RetentionDays > 0 {
+		if err = s.prune(config.Config().Backup.RetentionDays); err != nil {
+			log.Logger.Sugar().Error("BackupDatabase prune err ", err)
+		}
+	}
+}
+
+// Run 备份全部表，任一张表失败时不写入清单
+func (s *Backup) Run(ctx context.Context) (*BackupManifest, error) {
+	manifest := &BackupManifest{
+		Id:        time.Now().UTC().Format(backupIdLayout),
+		CreatedAt: time.Now().Unix(),
+		Tables:    map[string]int64{},
+	}
+	for _, table := range config.Config().Backup.Tables {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		count, err := s.dumpTable(ctx, manifest.Id, table)
+		itemCounted(ctx, err)
+		if err != nil {
+			return nil, errors.New(table + ": " + err.Error())
+		}
+		manifest.Tables[table] = count
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, utils.S3PutObject(s.key(manifest.Id, "manifest.json"), data, "application/json")
+}
+
+// dumpTable 导出一张表到 {prefix}/{id}/{table}.jsonl.gz
+func (s *Backup) dumpTable(ctx context.Context, id, table string) (int64, error) {
+	rows, err := db.Mysql.WithContext(ctx).Table(table).Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(zw)
+	values := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	var count int64
+	for rows.Next() {
+		if err = rows.Scan(dest...); err != nil {
+			return 0, err
+		}
+		record := make(map[string]*string, len(columns))
+		for i, column := range columns {
+			record[column] = backupValue(values[i])
+		}
+		if err = encoder.Encode(record); err != nil {
+			return 0, err
+		}
+		count++
+	}
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+	if err = zw.Close(); err != nil {
+		return 0, err
+	}
+	return count, utils.S3PutObject(s.key(id, table+".jsonl.gz"), buf.Bytes(), "application/gzip")
+}
+
+// backupValue 列值转为字符串，NULL 返回 nil
+func backupValue(value interface{}) *string {
+	var str string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	case time.Time:
+		str = v.In(time.Local).Format(backupTimeLayout)
+	case int64:
+		str = strconv.FormatInt(v, 10)
+	case float64:
+		str = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		str = strconv.FormatBool(v)
+	default:
+		data, _ := json.Marshal(v)
+		str = string(data)
+	}
+	return &str
+}
+
+// List 全部完整的备份，按时间从新到旧
+func (s *Backup) List() ([]BackupManifest, error) {
+	ids, err := s.ids()
+	if err != nil {
+		return nil, err
+	}
+	manifests := make([]BackupManifest, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		manifest, err := s.Manifest(ids[i])
+		if err != nil {
+			// 备份进行中或中途失败
+			continue
+		}
+		manifests = append(manifests, *manifest)
+	}
+	return manifests, nil
+}
+
+// Manifest 读取备份的清单
+func (s *Backup) Manifest(id string) (*BackupManifest, error) {
+	data, err := utils.S3GetObject(s.key(id, "manifest.json"))
+	if err != nil {
+		return nil, err
+	}
+	manifest := &BackupManifest{}
+	return manifest, json.Unmarshal(data, manifest)
+}
+
+// Restore 用备份替换表中的全部数据，每张表在一个事务中先删除再写入，失败时该表保持原样
+// tables 为空时恢复备份中的全部表，返回每张表写入的行数
+func (s *Backup) Restore(ctx context.Context, id string, tables []string) (map[string]int64, error) {
+	manifest, err := s.Manifest(id)
+	if err != nil {
+		return nil, errors.New("backup " + id + " not found or incomplete: " + err.Error())
+	}
+	if len(tables) == 0 {
+		for table := range manifest.Tables {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+	}
+	for _, table := range tables {
+		if _, ok := manifest.Tables[table]; !ok {
+			return nil, errors.New("table " + table + " is not in backup " + id)
+		}
+	}
+
+	restored := map[string]int64{}
+	for _, table := range tables {
+		count, err := s.restoreTable(ctx, id, table)
+		if err != nil {
+			return restored, errors.New(table + ": " + err.Error())
+		}
+		restored[table] = count
+	}
+	return restored, nil
+}
+
+func (s *Backup) restoreTable(ctx context.Context, id, table string) (int64, error) {
+	data, err := utils.S3GetObject(s.key(id, table+".jsonl.gz"))
+	if err != nil {
+		return 0, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	defer zr.Close()
+
+	var count int64
+	err = db.Mysql.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM `" + table + "`").Error; err != nil {
+			return err
+		}
+		batch := make([]map[string]interface{}, 0, backupBatchSize)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			err := tx.Table(table).Create(batch).Error
+			batch = batch[:0]
+			return err
+		}
+		decoder := json.NewDecoder(bufio.NewReader(zr))
+		for {
+			record := map[string]*string{}
+			err := decoder.Decode(&record)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return err
+			}
+			row := make(map[string]interface{}, len(record))
+			for column, value := range record {
+				if value == nil {
+					row[column] = nil
+				} else {
+					row[column] = *value
+				}
+			}
+			batch = append(batch, row)
+			count++
+			if len(batch) == backupBatchSize {
+				if err = flush(); err != nil {
+					return err
+				}
+			}
+		}
+		return flush()
+	})
+	return count, err
+}
+
+// prune 删除超过 days 天的备份，包括没有清单的不完整备份
+func (s *Backup) prune(days int) error {
+	ids, err := s.ids()
+	if err != nil {
+		return err
+	}
+	before := time.Now().UTC().AddDate(0, 0, -days)
+	for _, id := range ids {
+		createdAt, err := time.Parse(backupIdLayout, id)
+		if err != nil || !createdAt.Before(before) {
+			continue
+		}
+		keys, err := utils.S3ListObjects(s.key(id, ""))
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err = utils.S3DeleteObject(key); err != nil {
+				return err
+			}
+		}
+		log.Logger.Sugar().Info("BackupDatabase prune ", id)
+	}
+	return nil
+}
+
+// ids 存储中的全部备份 ID，按时间从旧到新
+func (s *Backup) ids() ([]string, error) {
+	prefix := s.key("", "")
+	keys, err := utils.S3ListObjects(prefix)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	ids := make([]string, 0)
+	for _, key := range keys {
+		id := strings.SplitN(strings.TrimPrefix(key, prefix), "/", 2)[0]
+		if _, err := time.Parse(backupIdLayout, id); err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// key 备份对象的 key: {prefix}/{id}/{name}，id 为空时为全部备份的前缀
+func (s *Backup) key(id, name string) string {
+	key := strings.Trim(config.Config().Backup.Prefix, "/") + "/"
+	if id != "" {
+		key += id + "/" + name
+	}
+	return key
+}
@@ -2,11 +2,16 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"pledge-backend/config"
+	"pledge-backend/db"
 	"pledge-backend/log"
+	"pledge-backend/schedule/models"
 	"pledge-backend/utils"
+	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -21,37 +26,172 @@ func NewBalanceMonitor() *BalanceMonitor {
 }
 
 // Monitor Sending email when balance is insufficient
+// 只检查 [testnet] / [mainnet] enabled 的网络
 func (s *BalanceMonitor) Monitor() {
 
 	//check on bsc test-net
-	tokenPoolBalance, err := s.GetBalance(config.Config.TestNet.NetUrl, config.Config.TestNet.PledgePoolToken)
-	thresholdPoolToken, ok := new(big.Int).SetString(config.Config.Threshold.PledgePoolTokenThresholdBnb, 10)
-	if ok && (err == nil) && (tokenPoolBalance.Cmp(thresholdPoolToken) <= 0) {
-		emailBody, err := s.EmailBody(config.Config.TestNet.PledgePoolToken, "TBNB", tokenPoolBalance.String(), thresholdPoolToken.String())
-		if err != nil {
-			log.Logger.Error(err.Error())
-		} else {
-			err = utils.SendEmail(emailBody, 2)
-			if err != nil {
-				log.Logger.Error(err.Error())
-			}
-		}
+	if config.Config().ChainEnabled(JobMonitor, config.Config().TestNet.ChainId) {
+		s.check(config.Config().TestNet.ChainId, models.NewChainHealth().RpcUrl(config.Config().TestNet.ChainId), config.Config().TestNet.PledgePoolToken, "TBNB")
 	}
 
 	//check on bsc main-net
-	// tokenPoolBalance, err = s.GetBalance(config.Config.MainNet.NetUrl, config.Config.MainNet.PledgePoolToken)
-	// thresholdPoolToken, ok = new(big.Int).SetString(config.Config.Threshold.PledgePoolTokenThresholdBnb, 10)
-	// if ok && (err == nil) && (tokenPoolBalance.Cmp(thresholdPoolToken) <= 0) {
-	// 	emailBody, err := s.EmailBody(config.Config.MainNet.PledgePoolToken, "BNB", tokenPoolBalance.String(), thresholdPoolToken.String())
-	// 	if err != nil {
-	// 		log.Logger.Error(err.Error())
-	// 	} else {
-	// 		err = utils.SendEmail(emailBody, 2)
-	// 		if err != nil {
-	// 			log.Logger.Error(err.Error())
-	// 		}
-	// 	}
-	// }
+	if config.Config().ChainEnabled(JobMonitor, config.Config().MainNet.ChainId) {
+		s.check(config.Config().MainNet.ChainId, models.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId), config.Config().MainNet.PledgePoolToken, "BNB")
+	}
+}
+
+// check 合约余额低于 [threshold] 时按 [alert] 告警:
+//   - 冷却时间内不重复发送，告警级别升级时立即发送
+//   - 连续低于阈值的次数达到 telegram_after / pagerduty_after 后升级，低级别渠道继续发送
+//   - 余额恢复后发送恢复通知并关闭 PagerDuty incident
+//
+// 发送的告警记录到 alert_history 表
+func (s *BalanceMonitor) check(chainId, netUrl, token, currency string) {
+	tokenPoolBalance, err := s.GetBalance(netUrl, token)
+	if err != nil {
+		return
+	}
+	thresholdPoolToken, ok := new(big.Int).SetString(config.Config().Threshold.PledgePoolTokenThresholdBnb, 10)
+	if !ok {
+		log.Logger.Sugar().Error("invalid threshold pledge_pool_token_threshold_bnb ", config.Config().Threshold.PledgePoolTokenThresholdBnb)
+		return
+	}
+
+	key := "balance_alert:" + chainId + ":" + token
+	state := s.alertState(key)
+	if tokenPoolBalance.Cmp(thresholdPoolToken) > 0 {
+		if state.Consecutive > 0 {
+			s.resolve(key, chainId, token, currency, tokenPoolBalance.String(), state)
+		}
+		return
+	}
+
+	state.Consecutive++
+	level := alertLevel(state.Consecutive)
+	now := time.Now().Unix()
+	if level <= state.Level && now-state.LastAlertAt < config.Config().Alert.CooldownMinutes*60 {
+		s.saveAlertState(key, state)
+		return
+	}
+
+	emailBody, err := s.EmailBody(token, currency, tokenPoolBalance.String(), thresholdPoolToken.String())
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	text := fmt.Sprintf("Pledge %s balance of %s on chain %s is %s %s, below %s %s for %d consecutive checks, please recharge it in time",
+		currency, token, chainId, weiToBnb(tokenPoolBalance.String()), currency, weiToBnb(thresholdPoolToken.String()), currency, state.Consecutive)
+
+	errs := sendAlert(level, key, emailBody, text)
+	if len(errs) > 0 {
+		log.Logger.Sugar().Error("balance alert send err ", errs)
+	}
+
+	state.Level = level
+	state.LastAlertAt = now
+	s.saveAlertState(key, state)
+	saveAlertHistory("balance", chainId, token, level, state.Consecutive, text, errs)
+}
+
+// resolve 余额恢复，通知已告警的渠道并清除告警状态
+func (s *BalanceMonitor) resolve(key, chainId, token, currency, balance string, state models.BalanceAlert) {
+	_, err := db.RedisDelete(key)
+	if err != nil {
+		log.Logger.Error(err.Error())
+	}
+	if state.Level == models.AlertLevelNone {
+		return
+	}
+
+	text := fmt.Sprintf("Pledge %s balance of %s on chain %s recovered to %s %s", currency, token, chainId, weiToBnb(balance), currency)
+	errs := sendResolve(state.Level, key, text)
+	if len(errs) > 0 {
+		log.Logger.Sugar().Error("balance alert resolve send err ", errs)
+	}
+	saveAlertHistory("balance", chainId, token, models.AlertLevelNone, state.Consecutive, text, errs)
+}
+
+// alertLevel 按连续低于阈值的次数计算告警级别
+func alertLevel(consecutive int) int {
+	if config.Config().Alert.PagerdutyAfter > 0 && consecutive >= config.Config().Alert.PagerdutyAfter {
+		return models.AlertLevelPagerduty
+	}
+	if config.Config().Alert.TelegramAfter > 0 && consecutive >= config.Config().Alert.TelegramAfter {
+		return models.AlertLevelTelegram
+	}
+	return models.AlertLevelEmail
+}
+
+func (s *BalanceMonitor) alertState(key string) models.BalanceAlert {
+	state := models.BalanceAlert{}
+	stateBytes, err := db.RedisGet(key)
+	if err == nil && len(stateBytes) > 0 {
+		_ = json.Unmarshal(stateBytes, &state)
+	}
+	return state
+}
+
+func (s *BalanceMonitor) saveAlertState(key string, state models.BalanceAlert) {
+	if err := db.RedisSet(key, state, 0); err != nil {
+		log.Logger.Error(err.Error())
+	}
+}
+
+// sendAlert 按告警级别发送: 邮件，以及升级后的 Telegram、PagerDuty (dedupKey 合并为同一个 incident)
+// 返回发送失败的渠道和错误
+func sendAlert(level int, dedupKey string, emailBody []byte, text string) []string {
+	errs := make([]string, 0)
+	if err := utils.SendEmail(emailBody, 2); err != nil {
+		errs = append(errs, "email: "+err.Error())
+	}
+	if level >= models.AlertLevelTelegram {
+		if err := utils.SendTelegram(text); err != nil {
+			errs = append(errs, "telegram: "+err.Error())
+		}
+	}
+	if level >= models.AlertLevelPagerduty {
+		if err := utils.SendPagerDuty(utils.PagerdutyTrigger, dedupKey, text); err != nil {
+			errs = append(errs, "pagerduty: "+err.Error())
+		}
+	}
+	return errs
+}
+
+// sendResolve 通知已告警到 level 的渠道问题已恢复，并关闭 PagerDuty incident
+func sendResolve(level int, dedupKey, text string) []string {
+	errs := make([]string, 0)
+	if err := utils.SendEmail([]byte("<p>"+text+"</p>"), 2); err != nil {
+		errs = append(errs, "email: "+err.Error())
+	}
+	if level >= models.AlertLevelTelegram {
+		if err := utils.SendTelegram(text); err != nil {
+			errs = append(errs, "telegram: "+err.Error())
+		}
+	}
+	if level >= models.AlertLevelPagerduty {
+		if err := utils.SendPagerDuty(utils.PagerdutyResolve, dedupKey, ""); err != nil {
+			errs = append(errs, "pagerduty: "+err.Error())
+		}
+	}
+	return errs
+}
+
+// saveAlertHistory 记录一次告警或恢复通知，kind 为告警类型，target 为告警对象
+// 同时发布运维事件，推送给运维面板
+func saveAlertHistory(kind, chainId, target string, level, consecutive int, message string, errs []string) {
+	history := models.AlertHistory{
+		Kind:        kind,
+		ChainId:     chainId,
+		Target:      target,
+		Level:       level,
+		Consecutive: consecutive,
+		Message:     message,
+		Error:       strings.Join(errs, "; "),
+	}
+	if err := models.NewAlertHistory().Save(&history); err != nil {
+		log.Logger.Error(err.Error())
+	}
+	PublishOpsEvent(models.OpsEventAlert, chainId, history)
 }
 
 // GetBalance get balance of ERC20 token
@@ -97,3 +237,12 @@ func (s *BalanceMonitor) EmailBody(token, currency, balance, threshold string) (
 </p>`, token, balanceStr, currency, thresholdStr, currency)
 	return []byte(body), nil
 }
+
+// weiToBnb wei 转换为 BNB
+func weiToBnb(wei string) string {
+	value, err := decimal.NewFromString(wei)
+	if err != nil {
+		return wei
+	}
+	return value.Shift(-18).String()
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"pledge-backend/schedule/cluster"
+	"pledge-backend/schedule/models"
+	"pledge-backend/telemetry"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// chainHealthTimeout 单个节点一次检查的超时时间
+const chainHealthTimeout = 10 * time.Second
+
+// unhealthyTtl 不健康标记和网络状态的有效期 (秒)，健康检查停止或被禁用后自动失效
+const unhealthyTtl = 15 * 60
+
+// networkBlockSample 计算平均出块时间使用的区块数
+const networkBlockSample = 100
+
+// gasPriceMinSamples 最新区块中有 gas 价格的交易少于该数量时，gas 价格建议都使用节点的 eth_gasPrice
+const gasPriceMinSamples = 5
+
+// ChainHealth RPC 节点健康检查
+//
+// 每条启用的链检查 net_url 和 [chain_health] endpoints 中的备用节点: eth_blockNumber 的延迟、区块高度，
+// 以及落后公共参考节点的区块数。结果写入 chain_health 表，不健康的节点在 Redis 中标记 rpc_unhealthy:<chainId>:<url>，
+// 同步任务和 api 通过 models.ChainHealth.RpcUrl 选择节点，net_url 被标记时切换到第一个没有标记的备用节点。
+// 检查后从第一个健康的节点采集网络状态 (最新区块、平均出块时间、gas 价格建议)，写入 Redis 供 api 的 /network/:chainId 使用
+type ChainHealth struct{}
+
+func NewChainHealth() *ChainHealth {
+	return &ChainHealth{}
+}
+
+// UpdateChainHealth 检查所有启用的链的 RPC 节点，多实例部署时只检查分配给本实例的链
+func (s *ChainHealth) UpdateChainHealth(ctx context.Context) {
+	conf := config.Config().ChainHealth
+	if config.Config().ChainEnabled(JobUpdateChainHealth, config.Config().TestNet.ChainId) && cluster.OwnsChain(JobUpdateChainHealth, config.Config().TestNet.ChainId) {
+		s.checkChain(ctx, config.Config().TestNet.ChainId, config.Config().TestNet.NetUrl, conf.TestnetEndpoints, conf.TestnetReferenceUrl)
+	}
+	if config.Config().ChainEnabled(JobUpdateChainHealth, config.Config().MainNet.ChainId) && cluster.OwnsChain(JobUpdateChainHealth, config.Config().MainNet.ChainId) {
+		s.checkChain(ctx, config.Config().MainNet.ChainId, config.Config().MainNet.NetUrl, conf.MainnetEndpoints, conf.MainnetReferenceUrl)
+	}
+}
+
+// checkChain 先查询参考节点的区块高度，再依次检查主节点和备用节点，最后从第一个健康的节点采集网络状态
+func (s *ChainHealth) checkChain(ctx context.Context, chainId, netUrl string, endpoints []string, referenceUrl string) {
+	var referenceBlock uint64
+	if referenceUrl != "" {
+		number, _, err := rpcBlockNumber(ctx, referenceUrl)
+		if err != nil {
+			log.Logger.Sugar().Warn("chain health reference node err ", chainId, " ", err)
+		} else {
+			referenceBlock = number
+		}
+	}
+
+	checked := map[string]bool{}
+	statusUrl := ""
+	for i, url := range append([]string{netUrl}, endpoints...) {
+		if checked[url] {
+			continue
+		}
+		checked[url] = true
+		if s.checkEndpoint(ctx, chainId, url, i == 0, referenceBlock) && statusUrl == "" {
+			statusUrl = url
+		}
+	}
+
+	if statusUrl == "" {
+		return
+	}
+	if err := s.updateNetworkStatus(ctx, chainId, statusUrl); err != nil {
+		log.Logger.Sugar().Warn("network status err ", chainId, " ", err)
+	}
+}
+
+// checkEndpoint 检查一个节点，保存结果并更新不健康标记，返回节点是否健康
+func (s *ChainHealth) checkEndpoint(ctx context.Context, chainId, url string, primary bool, referenceBlock uint64) bool {
+	conf := config.Config().ChainHealth
+	health := models.ChainHealth{
+		ChainId:        chainId,
+		Url:            url,
+		Primary:        primary,
+		ReferenceBlock: referenceBlock,
+	}
+
+	number, latency, err := rpcBlockNumber(ctx, url)
+	health.LatencyMs = latency.Milliseconds()
+	switch {
+	case err != nil:
+		health.Error = err.Error()
+	case health.LatencyMs > conf.MaxLatencyMs:
+		health.Error = fmt.Sprintf("latency %dms over %dms", health.LatencyMs, conf.MaxLatencyMs)
+	}
+	if err == nil {
+		health.BlockNumber = number
+		if referenceBlock > number {
+			health.BlockLag = referenceBlock - number
+		}
+		if health.BlockLag > conf.MaxBlockLag && health.Error == "" {
+			health.Error = fmt.Sprintf("%d blocks behind reference", health.BlockLag)
+		}
+	}
+	health.Healthy = health.Error == ""
+
+	// 健康状态变化时发布运维事件，第一次检查只在不健康时发布
+	previous := models.ChainHealth{}
+	found, err := models.NewChainHealth().Get(chainId, url, &previous)
+	if err != nil {
+		log.Logger.Error(err.Error())
+	}
+	if err = models.NewChainHealth().Save(&health); err != nil {
+		log.Logger.Error(err.Error())
+	}
+	if (found && previous.Healthy != health.Healthy) || (!found && !health.Healthy) {
+		PublishOpsEvent(models.OpsEventChainHealth, chainId, health)
+	}
+	if health.Healthy {
+		err = models.NewChainHealth().MarkHealthy(chainId, url)
+	} else {
+		log.Logger.Sugar().Warn("rpc endpoint unhealthy ", chainId, " ", url, " ", health.Error)
+		err = models.NewChainHealth().MarkUnhealthy(chainId, url, health.Error, unhealthyTtl)
+	}
+	if err != nil {
+		log.Logger.Error(err.Error())
+	}
+	return health.Healthy
+}
+
+// updateNetworkStatus 采集最新区块、最近 networkBlockSample 个区块的平均出块时间和 gas 价格建议
+func (s *ChainHealth) updateNetworkStatus(ctx context.Context, chainId, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, chainHealthTimeout)
+	defer cancel()
+
+	client, err := telemetry.DialEth(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	block, err := client.BlockByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	status := models.NetworkStatus{
+		ChainId:     chainId,
+		BlockNumber: block.NumberU64(),
+		BlockTime:   int64(block.Time()),
+		UpdatedAt:   time.Now().Unix(),
+	}
+	if block.BaseFee() != nil {
+		status.BaseFee = block.BaseFee().String()
+	}
+	if block.NumberU64() > networkBlockSample {
+		sample, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(block.NumberU64()-networkBlockSample))
+		if err != nil {
+			return err
+		}
+		seconds := float64(block.Time()-sample.Time) / networkBlockSample
+		status.AvgBlockTime = math.Round(seconds*100) / 100
+	}
+
+	suggested, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return err
+	}
+	status.GasPrice = gasPriceSuggestions(block, suggested)
+	return status.Save(unhealthyTtl)
+}
+
+// gasPriceSuggestions 最新区块中交易实际支付的 gas 价格的 25%、50%、75% 分位数，分别作为 slow、standard、fast
+// gas 价格为 0 的交易 (BSC 的系统交易) 不参与统计；有价格的交易太少时都使用 suggested
+func gasPriceSuggestions(block *types.Block, suggested *big.Int) models.GasPrice {
+	baseFee := block.BaseFee()
+	prices := make([]*big.Int, 0, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		price := tx.GasPrice()
+		if baseFee != nil {
+			tip, err := tx.EffectiveGasTip(baseFee)
+			if err != nil {
+				continue
+			}
+			price = new(big.Int).Add(baseFee, tip)
+		}
+		if price.Sign() > 0 {
+			prices = append(prices, price)
+		}
+	}
+	if len(prices) < gasPriceMinSamples {
+		return models.GasPrice{Slow: suggested.String(), Standard: suggested.String(), Fast: suggested.String()}
+	}
+
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Cmp(prices[j]) < 0 })
+	percentile := func(p int) string {
+		return prices[(len(prices)-1)*p/100].String()
+	}
+	return models.GasPrice{Slow: percentile(25), Standard: percentile(50), Fast: percentile(75)}
+}
+
+// rpcBlockNumber 查询节点的区块高度，返回请求耗时
+func rpcBlockNumber(ctx context.Context, url string) (uint64, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, chainHealthTimeout)
+	defer cancel()
+
+	start := time.Now()
+	client, err := telemetry.DialEth(ctx, url)
+	if err != nil {
+		return 0, time.Since(start), err
+	}
+	defer client.Close()
+	number, err := client.BlockNumber(ctx)
+	return number, time.Since(start), err
+}
@@ -0,0 +1,122 @@
+/*
+ * ==================================================================================
+ * chainlinkPriceService.go - Chainlink 价格同步服务
+ * ==================================================================================
+ *
+ * 【核心功能】
+ * 从 BSC 主网 Chainlink aggregator 读取代币 USD 价格，作为 BscPledgeOracle 之外的第二价格来源，
+ * 与 Oracle 价格一起存放在 token_info 表中，供前端和风控交叉校验。
+ *
+ * 【调用频率】
+ * UpdateChainlinkPrice(): 每 1 分钟调用一次
+ *
+ * 【数据流向】
+ * Chainlink Aggregator --> chainlinkPriceService --> MySQL (token_info.chainlink_price) + Redis
+ *
+ * 【精度】
+ * 统一换算为 1e8 精度，与 BscPledgeOracle 价格一致
+ * ==================================================================================
+ */
+
+package services
+
+import (
+	"context"
+	"math/big"
+	"pledge-backend/config"
+	"pledge-backend/contract/bindings"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ChainlinkPrice - Chainlink 价格服务结构体
+type ChainlinkPrice struct{}
+
+// NewChainlinkPrice - 工厂函数，创建 ChainlinkPrice 实例
+func NewChainlinkPrice() *ChainlinkPrice {
+	return &ChainlinkPrice{}
+}
+
+// UpdateChainlinkPrice - 读取 [chainlink.feeds] 中配置的所有喂价并保存
+func (s *ChainlinkPrice) UpdateChainlinkPrice() {
+	if len(config.Config().Chainlink.Feeds) == 0 {
+		return
+	}
+
+	ethereumConn, err := ethclient.Dial(models.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId))
+	if nil != err {
+		log.Logger.Error(err.Error())
+		return
+	}
+	defer ethereumConn.Close()
+
+	// 所有喂价在同一个区块上读取，该区块为最新区块之前第 read_lag 个区块
+	opts, err := readOpts(context.Background(), ethereumConn, JobUpdateChainlinkPrice, config.Config().MainNet.ChainId)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+
+	for token, feed := range config.Config().Chainlink.Feeds {
+		err, price, updatedAt := s.GetFeedPrice(ethereumConn, opts, feed)
+		if err != nil {
+			log.Logger.Sugar().Error("UpdateChainlinkPrice err ", token, feed, err)
+			continue
+		}
+
+		err = s.SaveChainlinkPrice(token, config.Config().MainNet.ChainId, price.String(), updatedAt)
+		if err != nil {
+			log.Logger.Sugar().Error("UpdateChainlinkPrice SaveChainlinkPrice err ", token, err)
+		}
+	}
+}
+
+// GetFeedPrice - 读取 aggregator 在 opts 区块上的最新一轮价格
+//
+// 返回:
+//   - error: 错误信息
+//   - *big.Int: 价格 (1e8 精度)
+//   - int64: 该轮价格的更新时间, Unix 秒
+func (s *ChainlinkPrice) GetFeedPrice(ethereumConn *ethclient.Client, opts *bind.CallOpts, feed string) (error, *big.Int, int64) {
+	aggregator, err := bindings.NewChainlinkAggregator(common.HexToAddress(feed), ethereumConn)
+	if err != nil {
+		return err, nil, 0
+	}
+
+	decimals, err := aggregator.Decimals(opts)
+	if err != nil {
+		return err, nil, 0
+	}
+
+	round, err := aggregator.LatestRoundData(opts)
+	if err != nil {
+		return err, nil, 0
+	}
+
+	// 换算为 1e8 精度: answer * 1e8 / 10^decimals
+	price := new(big.Int).Mul(round.Answer, big.NewInt(100000000))
+	price.Div(price, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+
+	return nil, price, round.UpdatedAt.Int64()
+}
+
+// SaveChainlinkPrice - 保存 Chainlink 价格到 MySQL 和 Redis
+func (s *ChainlinkPrice) SaveChainlinkPrice(token, chainId, price string, updatedAt int64) error {
+	token = strings.ToLower(token)
+
+	err := db.RedisSetString("chainlink_price:"+chainId+":"+token, price, 0)
+	if err != nil {
+		return err
+	}
+
+	return db.Mysql.Table("token_info").Where("LOWER(token)=? and chain_id=?", token, chainId).Updates(map[string]interface{}{
+		"chainlink_price":      price,
+		"chainlink_updated_at": updatedAt,
+	}).Debug().Error
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Coingecko - CoinGecko 价格客户端
+// 作为链上 Oracle 和交易所之外的兜底价格来源，请求之间至少间隔 [coingecko] min_interval，
+// 价格在 Redis 中缓存 cache_seconds，避免触发 CoinGecko 的限流
+type Coingecko struct{}
+
+// NewCoingecko - 工厂函数，创建 Coingecko 实例
+func NewCoingecko() *Coingecko {
+	return &Coingecko{}
+}
+
+var coingeckoLock sync.Mutex
+var coingeckoLastCall time.Time
+
+// GetTokenPrice - 获取代币的 USD 价格
+//
+// 参数:
+//   - coingeckoId: CoinGecko 代币 ID，例如 binancecoin
+//
+// 返回:
+//   - error: 错误信息
+//   - int64: 代币价格 (1e8 精度，与 Oracle 合约一致)
+func (c *Coingecko) GetTokenPrice(coingeckoId string) (error, int64) {
+	redisKey := "coingecko_price:" + coingeckoId
+	if priceStr, err := db.RedisGetString(redisKey); err == nil && priceStr != "" {
+		return nil, utils.StringToInt64(priceStr)
+	}
+
+	body, err := c.get("/simple/price?ids=" + url.QueryEscape(coingeckoId) + "&vs_currencies=usd")
+	if err != nil {
+		return err, 0
+	}
+
+	res := map[string]map[string]decimal.Decimal{}
+	if err = json.Unmarshal(body, &res); err != nil {
+		return err, 0
+	}
+	usd, ok := res[coingeckoId]["usd"]
+	if !ok || usd.IsZero() {
+		return errors.New("coingecko price not found " + coingeckoId), 0
+	}
+
+	price := usd.Mul(decimal.NewFromInt(100000000)).IntPart()
+	_ = db.RedisSetString(redisKey, utils.Int64ToString(price), config.Config().Coingecko.CacheSeconds)
+	return nil, price
+}
+
+// get 限流后发送请求，配置了 api_key 时携带在请求头中
+func (c *Coingecko) get(path string) ([]byte, error) {
+	coingeckoLock.Lock()
+	wait := time.Duration(config.Config().Coingecko.MinInterval)*time.Millisecond - time.Since(coingeckoLastCall)
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+	coingeckoLastCall = time.Now()
+	coingeckoLock.Unlock()
+
+	header := map[string]string{}
+	if config.Config().Coingecko.ApiKey != "" {
+		header[config.Config().Coingecko.ApiKeyHeader] = config.Config().Coingecko.ApiKey
+	}
+	return utils.HttpGet(config.Config().Coingecko.ApiUrl+path, header)
+}
+
+// GetTokenImage - 获取代币 logo 地址
+//
+// 参数:
+//   - coingeckoId: CoinGecko 代币 ID，为空时按 BSC 主网合约地址查询
+//   - chainId: 链 ID，测试网代币没有 coingecko_id 时无法查询
+//   - token: 代币合约地址
+//
+// 返回:
+//   - error: 错误信息
+//   - string: logo 原始地址
+func (c *Coingecko) GetTokenImage(coingeckoId, chainId, token string) (error, string) {
+	var path string
+	if coingeckoId != "" {
+		path = "/coins/" + url.PathEscape(coingeckoId) + "?localization=false&tickers=false&market_data=false&community_data=false&developer_data=false"
+	} else if chainId == "56" {
+		path = "/coins/binance-smart-chain/contract/" + strings.ToLower(token)
+	} else {
+		return errors.New("coingecko id not set " + chainId + ":" + token), ""
+	}
+
+	body, err := c.get(path)
+	if err != nil {
+		return err, ""
+	}
+	coin := models.CoingeckoCoin{}
+	if err = json.Unmarshal(body, &coin); err != nil {
+		return err, ""
+	}
+	if coin.Image.Large == "" {
+		return errors.New("coingecko image not found " + chainId + ":" + token), ""
+	}
+	return nil, coin.Image.Large
+}
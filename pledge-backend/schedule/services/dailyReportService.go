@@ -0,0 +1,214 @@
+package services
+
+import (
+	"fmt"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 池子状态，与 PledgePool.sol 的 PoolState 一致
+const (
+	poolStateMatch       = "0"
+	poolStateExecution   = "1"
+	poolStateFinish      = "2"
+	poolStateLiquidation = "3"
+	poolStateUndone      = "4"
+)
+
+type DailyReport struct{}
+
+func NewDailyReport() *DailyReport {
+	return &DailyReport{}
+}
+
+// reportPool 统计需要的池子代币
+type reportPool struct {
+	lendToken   models.TokenInfo
+	borrowToken models.TokenInfo
+}
+
+// GenerateDailyReport 为 [jobs.GenerateDailyReport] 处理的每条链生成前一个 UTC 日的报表，
+// [report] email_enabled 时把各链的摘要合并为一封邮件发送
+func (s *DailyReport) GenerateDailyReport() {
+	day := time.Now().UTC().AddDate(0, 0, -1)
+	body := make([]byte, 0)
+	for _, chainId := range config.Config().EnabledChainIds() {
+		if !config.Config().ChainEnabled(JobGenerateDailyReport, chainId) {
+			continue
+		}
+		report, err := s.Generate(chainId, day)
+		if err != nil {
+			log.Logger.Sugar().Error("GenerateDailyReport chain ", chainId, " err ", err)
+			continue
+		}
+		body = append(body, s.EmailBody(report)...)
+	}
+	if !config.Config().Report.EmailEnabled || len(body) == 0 {
+		return
+	}
+	err := utils.SendEmailWithSubject(config.Config().Report.EmailSubject+" "+day.Format("2006-01-02"), body, 2)
+	if err != nil {
+		log.Logger.Error(err.Error())
+	}
+}
+
+// Generate 根据 pool_snapshots 统计指定 UTC 日的数据并写入 daily_reports
+//
+// 金额按 token_info 的当前价格折算为美元 (1e8 精度)，手续费收入为当天记入 fee_revenues 的合计 (见 AccountFeeRevenue)
+func (s *DailyReport) Generate(chainId string, day time.Time) (*models.DailyReport, error) {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+
+	var before []models.PoolSnapshot
+	err := models.NewPoolSnapshot().LatestBefore(chainId, start.Unix(), &before)
+	if err != nil {
+		return nil, err
+	}
+	var during []models.PoolSnapshot
+	err = models.NewPoolSnapshot().Between(chainId, start.Unix(), end.Unix(), &during)
+	if err != nil {
+		return nil, err
+	}
+	pools, err := s.reportPools(chainId)
+	if err != nil {
+		return nil, err
+	}
+
+	newLend, newBorrow := decimal.Zero, decimal.Zero
+	tvlStart, tvlEnd := decimal.Zero, decimal.Zero
+	report := &models.DailyReport{
+		ChainId:    chainId,
+		ReportDate: start.Format("2006-01-02"),
+	}
+
+	// 每个池子日初和日终的快照
+	last := map[int]models.PoolSnapshot{}
+	for _, v := range before {
+		last[v.PoolId] = v
+		tvlStart = tvlStart.Add(s.tvl(v, pools[v.PoolId]))
+	}
+	for _, v := range during {
+		pool := pools[v.PoolId]
+		prev, ok := last[v.PoolId]
+		if !ok {
+			prev = models.PoolSnapshot{State: poolStateMatch, LendSupply: "0", BorrowSupply: "0"}
+		}
+		// 匹配阶段供给增加视为新存入，减少 (退出) 不抵扣
+		if delta := toDecimal(v.LendSupply).Sub(toDecimal(prev.LendSupply)); delta.IsPositive() {
+			newLend = newLend.Add(usdValue(delta, pool.lendToken))
+		}
+		if delta := toDecimal(v.BorrowSupply).Sub(toDecimal(prev.BorrowSupply)); delta.IsPositive() {
+			newBorrow = newBorrow.Add(usdValue(delta, pool.borrowToken))
+		}
+		if v.State != prev.State {
+			switch v.State {
+			case poolStateExecution:
+				report.SettledPools++
+			case poolStateUndone:
+				report.UndonePools++
+			case poolStateLiquidation:
+				report.LiquidatedPools++
+			}
+		}
+		last[v.PoolId] = v
+	}
+	for _, v := range last {
+		tvlEnd = tvlEnd.Add(s.tvl(v, pools[v.PoolId]))
+	}
+	fees, err := models.NewFeeRevenue().Sum(chainId, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	report.NewLendDeposit = newLend.StringFixed(0)
+	report.NewBorrowDeposit = newBorrow.StringFixed(0)
+	report.LendFeeRevenue = toDecimal(fees.LendFeeUsd).StringFixed(0)
+	report.BorrowFeeRevenue = toDecimal(fees.BorrowFeeUsd).StringFixed(0)
+	report.Tvl = tvlEnd.StringFixed(0)
+	report.TvlChange = tvlEnd.Sub(tvlStart).StringFixed(0)
+
+	err = models.NewDailyReport().SaveDailyReport(report)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// reportPools 读取池子的代币信息，key 为 pool_id
+func (s *DailyReport) reportPools(chainId string) (map[int]reportPool, error) {
+	var bases []models.PoolBase
+	err := db.Mysql.Table("poolbases").Where("chain_id=?", chainId).Find(&bases).Debug().Error
+	if err != nil {
+		return nil, err
+	}
+	var tokens []models.TokenInfo
+	err = db.Mysql.Table("token_info").Where("chain_id=?", chainId).Find(&tokens).Debug().Error
+	if err != nil {
+		return nil, err
+	}
+	tokenMap := map[string]models.TokenInfo{}
+	for _, v := range tokens {
+		tokenMap[strings.ToLower(v.Token)] = v
+	}
+
+	pools := map[int]reportPool{}
+	for _, v := range bases {
+		pools[v.PoolId] = reportPool{
+			lendToken:   tokenMap[strings.ToLower(v.LendToken)],
+			borrowToken: tokenMap[strings.ToLower(v.BorrowToken)],
+		}
+	}
+	return pools, nil
+}
+
+// tvl 匹配和执行阶段池子锁定的出借 + 抵押价值
+func (s *DailyReport) tvl(snapshot models.PoolSnapshot, pool reportPool) decimal.Decimal {
+	if snapshot.State != poolStateMatch && snapshot.State != poolStateExecution {
+		return decimal.Zero
+	}
+	return usdValue(toDecimal(snapshot.LendSupply), pool.lendToken).Add(usdValue(toDecimal(snapshot.BorrowSupply), pool.borrowToken))
+}
+
+// EmailBody email body
+func (s *DailyReport) EmailBody(report *models.DailyReport) []byte {
+	row := func(name, value string) string {
+		return fmt.Sprintf(`<tr><td>%s</td><td style="text-align: right;">%s</td></tr>`, name, value)
+	}
+	body := fmt.Sprintf(`<p>Pledge daily report of chain <strong>%s</strong> on <strong>%s</strong> (UTC)</p><table>`, report.ChainId, report.ReportDate) +
+		row("New lend deposits (USD)", usd(report.NewLendDeposit)) +
+		row("New borrow deposits (USD)", usd(report.NewBorrowDeposit)) +
+		row("Settled pools", utils.IntToString(report.SettledPools)) +
+		row("Undone pools", utils.IntToString(report.UndonePools)) +
+		row("Liquidated pools", utils.IntToString(report.LiquidatedPools)) +
+		row("Lend fee revenue (USD)", usd(report.LendFeeRevenue)) +
+		row("Borrow fee revenue (USD)", usd(report.BorrowFeeRevenue)) +
+		row("TVL (USD)", usd(report.Tvl)) +
+		row("TVL change (USD)", usd(report.TvlChange)) +
+		`</table>`
+	return []byte(body)
+}
+
+// usdValue 代币数量 (最小单位) 按当前价格折算的美元价值, 1e8 精度
+func usdValue(amount decimal.Decimal, token models.TokenInfo) decimal.Decimal {
+	return amount.Shift(int32(-token.Decimals)).Mul(toDecimal(token.Price))
+}
+
+// usd 1e8 精度的美元价值转为两位小数
+func usd(value string) string {
+	return toDecimal(value).Shift(-8).StringFixed(2)
+}
+
+func toDecimal(s string) decimal.Decimal {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero
+	}
+	return d
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"strings"
+	"time"
+)
+
+// Deadline 池子结算倒计时
+//
+// 匹配中池子的 settleTime、执行中池子的 endTime 距当前时间进入 [deadline] lead_times 中的某个提前量时通知一次:
+// 写入 Redis 由 api 推送到 WebSocket / SSE 主题 deadline:<chainId>，POST 到 webhook_urls，并按 alert_level 告警。
+// 同时进入多个提前量时 (例如新池子距结算只剩 50 分钟) 只按最小的一个通知，已通知的记录在 alert_history
+type Deadline struct{}
+
+func NewDeadline() *Deadline {
+	return &Deadline{}
+}
+
+// WatchDeadlines 检查所有启用的链
+func (s *Deadline) WatchDeadlines(ctx context.Context) {
+	leads := config.Config().Deadline.Leads()
+	if len(leads) == 0 {
+		return
+	}
+	for _, chainId := range []string{config.Config().TestNet.ChainId, config.Config().MainNet.ChainId} {
+		if config.Config().ChainEnabled(JobWatchDeadlines, chainId) {
+			s.watchChain(ctx, chainId, leads)
+		}
+	}
+}
+
+func (s *Deadline) watchChain(ctx context.Context, chainId string, leads []time.Duration) {
+	var pools []models.PoolBase
+	if err := models.NewPoolBase().ListByStates(ctx, chainId, []string{poolStateMatch, poolStateExecution}, &pools); err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+
+	now := time.Now()
+	for _, pool := range pools {
+		if ctx.Err() != nil {
+			return
+		}
+		event := models.PoolDeadline{ChainId: chainId, PoolId: pool.PoolId, State: pool.State}
+		if pool.State == poolStateMatch {
+			event.Kind, event.Action, event.Deadline = models.PoolDeadlineSettle, keeperActionSettle, utils.StringToInt64(pool.SettleTime)
+		} else {
+			event.Kind, event.Action, event.Deadline = models.PoolDeadlineEnd, keeperActionFinish, utils.StringToInt64(pool.EndTime)
+		}
+		remaining := time.Unix(event.Deadline, 0).Sub(now)
+		if remaining <= 0 {
+			continue
+		}
+		lead, ok := nearestLead(leads, remaining)
+		if !ok {
+			continue
+		}
+		event.Lead = leadLabel(lead)
+		event.Remaining = int64(remaining.Seconds())
+
+		target := fmt.Sprintf("%d:%s:%d:%s", pool.PoolId, event.Kind, event.Deadline, event.Lead)
+		notified, err := models.NewAlertHistory().Exists("pool_deadline", chainId, target)
+		if err != nil {
+			log.Logger.Error(err.Error())
+			return
+		}
+		if notified {
+			continue
+		}
+		itemCounted(ctx, s.notify(&event, target))
+	}
+}
+
+// notify 发送一次倒计时通知，部分渠道失败时仍记录为已通知，避免重复发送到成功的渠道
+func (s *Deadline) notify(event *models.PoolDeadline, target string) error {
+	conf := config.Config().Deadline
+	event.NotifiedAt = time.Now().UnixMilli()
+	text := fmt.Sprintf("Pledge pool %d on chain %s %s at %s UTC (%s notice), on-chain %s required",
+		event.PoolId, event.ChainId, map[string]string{models.PoolDeadlineSettle: "settles", models.PoolDeadlineEnd: "ends"}[event.Kind],
+		time.Unix(event.Deadline, 0).UTC().Format("2006-01-02 15:04:05"), event.Lead, event.Action)
+	log.Logger.Sugar().Info(text)
+
+	errs := make([]string, 0)
+	if err := models.NewPoolDeadline().Publish(event); err != nil {
+		errs = append(errs, "ws: "+err.Error())
+	}
+	for _, webhook := range conf.WebhookUrls {
+		if err := utils.SendWebhook(webhook, conf.WebhookSecret, event); err != nil {
+			errs = append(errs, "webhook "+webhook+": "+err.Error())
+		}
+	}
+	if conf.AlertLevel > models.AlertLevelNone {
+		errs = append(errs, sendAlert(conf.AlertLevel, "pool_deadline:"+event.ChainId+":"+target, []byte("<p>"+text+"</p>"), text)...)
+	}
+
+	saveAlertHistory("pool_deadline", event.ChainId, target, conf.AlertLevel, 0, text, errs)
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// nearestLead 包含 remaining 的最小提前量，leads 从小到大排序
+func nearestLead(leads []time.Duration, remaining time.Duration) (time.Duration, bool) {
+	for _, lead := range leads {
+		if remaining <= lead {
+			return lead, true
+		}
+	}
+	return 0, false
+}
+
+// leadLabel 去掉 Duration.String() 末尾的 0m0s，例如 24h0m0s -> 24h，5m0s -> 5m
+func leadLabel(lead time.Duration) string {
+	label := lead.String()
+	if strings.HasSuffix(label, "m0s") {
+		label = strings.TrimSuffix(label, "0s")
+	}
+	if strings.HasSuffix(label, "h0m") {
+		label = strings.TrimSuffix(label, "0m")
+	}
+	return label
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"pledge-backend/config"
+	"pledge-backend/contract/artifacts"
+	"pledge-backend/contract/bindings"
+	"pledge-backend/db/seed"
+	"pledge-backend/log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Devnet 本地开发链 (anvil) 的合约部署
+//
+// 合约用 contract/artifacts 中的创建字节码正常部署，构造函数完成初始化:
+// BscPledgeOracle、multiSignature (门限 1，签名人是 [devnet] private_key 的账户)、PledgePool，
+// 部署地址由账户 nonce 决定，新链上与 [devnet] 中配置的地址一致；
+// 池子按 pools.yaml 通过多签批准的 createPoolInfo 创建，池子同步、事件索引和 keeper 与测试网一样读取链上数据
+type Devnet struct{}
+
+func NewDevnet() *Devnet {
+	return &Devnet{}
+}
+
+// Setup 部署缺失的合约、写入代币价格，链上还没有池子时创建 pools 中该链的池子；已部署的合约不会重复部署，可重复执行
+//
+// prices: 代币地址 -> 价格 (1e8 精度)
+func (s *Devnet) Setup(prices map[string]int64, pools []seed.Pool) error {
+	conf := config.Config().Devnet
+	ethereumConn, err := ethclient.Dial(conf.NetUrl)
+	if err != nil {
+		return err
+	}
+	defer ethereumConn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	chainId, err := ethereumConn.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	if chainId.String() != conf.ChainId {
+		return errors.New("devnet chain id " + chainId.String() + " does not match [devnet] chain_id " + conf.ChainId)
+	}
+
+	privateKeyEcdsa, err := crypto.HexToECDSA(conf.PrivateKey)
+	if err != nil {
+		return err
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKeyEcdsa, chainId)
+	if err != nil {
+		return err
+	}
+	auth.Context = ctx
+
+	// Step 1: BscPledgeOracle
+	oracleAddress := common.HexToAddress(conf.BscPledgeOracleToken)
+	code, err := ethereumConn.CodeAt(ctx, oracleAddress, nil)
+	if err != nil {
+		return err
+	}
+	if len(code) == 0 {
+		address, tx, _, err := bindings.DeployBscPledgeOracleTestnetToken(auth, ethereumConn)
+		if err != nil {
+			return err
+		}
+		_, err = bind.WaitDeployed(ctx, ethereumConn, tx)
+		if err != nil {
+			return err
+		}
+		log.Logger.Sugar().Info("Devnet deploy BscPledgeOracle ", address.Hex())
+		if address != oracleAddress {
+			return errors.New("BscPledgeOracle deployed at " + address.Hex() + ", set [devnet] bsc_pledge_oracle_token to this address")
+		}
+	}
+
+	// Step 2: multiSignature，PledgePool 和 DebtToken 的 validCall 由它批准
+	multiSignAddress := common.HexToAddress(conf.MultiSignAddress)
+	err = s.deployOnce(ctx, auth, ethereumConn, artifacts.MultiSignature, multiSignAddress, "multi_sign_address",
+		[]common.Address{auth.From}, big.NewInt(1))
+	if err != nil {
+		return err
+	}
+
+	// Step 3: PledgePool，devnet 没有 UniswapV2Router，swapRouter 和 feeAddress 都填部署账户
+	poolAddress := common.HexToAddress(conf.PledgePoolToken)
+	err = s.deployOnce(ctx, auth, ethereumConn, artifacts.PledgePool, poolAddress, "pledge_pool_token",
+		oracleAddress, auth.From, auth.From, multiSignAddress)
+	if err != nil {
+		return err
+	}
+
+	// Step 4: 写入代币价格
+	oracle, err := bindings.NewBscPledgeOracleTestnetToken(oracleAddress, ethereumConn)
+	if err != nil {
+		return err
+	}
+	for token, price := range prices {
+		tx, err := oracle.SetPrice(auth, common.HexToAddress(token), big.NewInt(price))
+		if err != nil {
+			return err
+		}
+		err = waitSucceeded(ctx, ethereumConn, tx)
+		if err != nil {
+			return err
+		}
+		log.Logger.Sugar().Info("Devnet set price ", token, " ", price)
+	}
+
+	// Step 5: 创建池子
+	return s.createPools(ctx, auth, ethereumConn, multiSignAddress, poolAddress, pools)
+}
+
+// deployOnce address 没有代码时部署 name 对应的合约，部署地址必须是 address
+func (s *Devnet) deployOnce(ctx context.Context, auth *bind.TransactOpts, ethereumConn *ethclient.Client,
+	name string, address common.Address, key string, params ...interface{}) error {
+	code, err := ethereumConn.CodeAt(ctx, address, nil)
+	if err != nil {
+		return err
+	}
+	if len(code) > 0 {
+		return nil
+	}
+	deployed, err := s.deploy(ctx, auth, ethereumConn, name, params...)
+	if err != nil {
+		return err
+	}
+	if deployed != address {
+		return errors.New(name + " deployed at " + deployed.Hex() + ", set [devnet] " + key + " to this address")
+	}
+	return nil
+}
+
+// deploy 部署 contract/artifacts 中 name 对应的合约并等待上链
+func (s *Devnet) deploy(ctx context.Context, auth *bind.TransactOpts, ethereumConn *ethclient.Client,
+	name string, params ...interface{}) (common.Address, error) {
+	contract, err := artifacts.Load(name)
+	if err != nil {
+		return common.Address{}, err
+	}
+	address, tx, _, err := bind.DeployContract(auth, contract.Abi, contract.Bytecode, ethereumConn, params...)
+	if err != nil {
+		return common.Address{}, err
+	}
+	_, err = bind.WaitDeployed(ctx, ethereumConn, tx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	log.Logger.Sugar().Info("Devnet deploy ", name, " ", address.Hex())
+	return address, nil
+}
+
+// createPools PledgePool 上还没有池子时，按 pool_id 顺序创建 pools 中 [devnet] chain_id 的池子，
+// 链上第 i 个池子对应 pool_id i+1；每个池子部署自己的 spCoin / jpCoin (DebtToken)
+//
+// 池子只写入创建参数，lend_supply 等动态数据由链上操作产生，不使用 fixture 中的值
+func (s *Devnet) createPools(ctx context.Context, auth *bind.TransactOpts, ethereumConn *ethclient.Client,
+	multiSignAddress, poolAddress common.Address, pools []seed.Pool) error {
+	pledgePool, err := bindings.NewPledgePoolToken(poolAddress, ethereumConn)
+	if err != nil {
+		return err
+	}
+	length, err := pledgePool.PoolLength(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return err
+	}
+	if length.Sign() > 0 {
+		log.Logger.Sugar().Info("Devnet PledgePool already has ", length, " pools")
+		return nil
+	}
+
+	chainPools := make([]seed.Pool, 0, len(pools))
+	for _, p := range pools {
+		if p.ChainId == config.Config().Devnet.ChainId {
+			chainPools = append(chainPools, p)
+		}
+	}
+	if len(chainPools) == 0 {
+		return nil
+	}
+	sort.Slice(chainPools, func(i, j int) bool {
+		return chainPools[i].PoolId < chainPools[j].PoolId
+	})
+
+	err = s.approve(ctx, auth, ethereumConn, multiSignAddress, poolAddress)
+	if err != nil {
+		return err
+	}
+	for i, p := range chainPools {
+		if p.PoolId != i+1 {
+			return errors.New("devnet pools must be numbered from 1 without gaps, got pool_id " + strconv.Itoa(p.PoolId))
+		}
+		err = s.createPool(ctx, auth, ethereumConn, multiSignAddress, pledgePool, p)
+		if err != nil {
+			return errors.New("create pool " + strconv.Itoa(p.PoolId) + ": " + err.Error())
+		}
+		log.Logger.Sugar().Info("Devnet create pool ", p.PoolId)
+	}
+	return nil
+}
+
+// createPool 部署池子的 spCoin / jpCoin 并调用 createPoolInfo
+func (s *Devnet) createPool(ctx context.Context, auth *bind.TransactOpts, ethereumConn *ethclient.Client,
+	multiSignAddress common.Address, pledgePool *bindings.PledgePoolToken, p seed.Pool) error {
+	poolId := strconv.Itoa(p.PoolId)
+	spCoin, err := s.deploy(ctx, auth, ethereumConn, artifacts.DebtToken, "spToken_"+poolId, "SP_"+poolId, multiSignAddress)
+	if err != nil {
+		return err
+	}
+	jpCoin, err := s.deploy(ctx, auth, ethereumConn, artifacts.DebtToken, "jpToken_"+poolId, "JP_"+poolId, multiSignAddress)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]*big.Int{}
+	for key, value := range map[string]string{
+		"settle_time":              p.SettleTime,
+		"end_time":                 p.EndTime,
+		"interest_rate":            p.InterestRate,
+		"max_supply":               p.MaxSupply,
+		"martgage_rate":            p.MartgageRate,
+		"auto_liquidate_threshold": p.AutoLiquidateThreshold,
+	} {
+		n, ok := new(big.Int).SetString(value, 10)
+		if !ok {
+			return errors.New("invalid " + key + " " + strconv.Quote(value))
+		}
+		values[key] = n
+	}
+	if !values["interest_rate"].IsUint64() {
+		return errors.New("interest_rate out of range")
+	}
+
+	tx, err := pledgePool.CreatePoolInfo(auth, values["settle_time"], values["end_time"], values["interest_rate"].Uint64(),
+		values["max_supply"], values["martgage_rate"], common.HexToAddress(p.LendToken), common.HexToAddress(p.BorrowToken),
+		spCoin, jpCoin, values["auto_liquidate_threshold"])
+	if err != nil {
+		return err
+	}
+	return waitSucceeded(ctx, ethereumConn, tx)
+}
+
+// approve 在 multiSignature 上申请并签名部署账户对 target 的调用，门限为 1，
+// 之后该账户对 target 的所有 validCall (createPoolInfo 等) 都通过
+func (s *Devnet) approve(ctx context.Context, auth *bind.TransactOpts, ethereumConn *ethclient.Client,
+	multiSignAddress, target common.Address) error {
+	contract, err := artifacts.Load(artifacts.MultiSignature)
+	if err != nil {
+		return err
+	}
+	multiSign := bind.NewBoundContract(multiSignAddress, contract.Abi, ethereumConn, ethereumConn, ethereumConn)
+
+	// 与 multiSignatureClient.checkMultiSignature 相同: keccak256(abi.encodePacked(msg.sender, address(this)))
+	msgHash := crypto.Keccak256Hash(auth.From.Bytes(), target.Bytes())
+	var out []interface{}
+	err = multiSign.Call(&bind.CallOpts{Context: ctx}, &out, "getValidSignature", msgHash, big.NewInt(0))
+	if err != nil {
+		return err
+	}
+	if index, ok := out[0].(*big.Int); ok && index.Sign() > 0 {
+		return nil
+	}
+
+	for _, call := range []struct {
+		method string
+		param  interface{}
+	}{
+		{"createApplication", target},
+		{"signApplication", msgHash},
+	} {
+		tx, err := multiSign.Transact(auth, call.method, call.param)
+		if err != nil {
+			return err
+		}
+		err = waitSucceeded(ctx, ethereumConn, tx)
+		if err != nil {
+			return err
+		}
+	}
+	log.Logger.Sugar().Info("Devnet multiSignature approved ", auth.From.Hex(), " -> ", target.Hex())
+	return nil
+}
+
+// waitSucceeded 等待交易上链，交易 revert 时返回错误
+func waitSucceeded(ctx context.Context, ethereumConn *ethclient.Client, tx *types.Transaction) error {
+	receipt, err := bind.WaitMined(ctx, ethereumConn, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return errors.New("transaction " + tx.Hash().Hex() + " reverted")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"pledge-backend/utils"
+)
+
+// ExchangeTrade 把 api 写入 Redis 的交易所成交保存到 MySQL 按月分表，用于核对喂价时的 TWAP/VWAP 和事后分析
+// Redis 中只保留最近约一小时的成交 (见 kucoin.SaveTick)，任务间隔需小于这个时间
+type ExchangeTrade struct{}
+
+func NewExchangeTrade() *ExchangeTrade {
+	return &ExchangeTrade{}
+}
+
+// PersistExchangeTrades 保存各交易对上次之后的成交，并删除超过 [exchange] trade_retention_months 的分表
+// trade_retention_months = 0 时不保存
+func (s *ExchangeTrade) PersistExchangeTrades(ctx context.Context) {
+	months := config.Config().Exchange.TradeRetentionMonths
+	if months <= 0 {
+		return
+	}
+
+	for _, symbol := range config.Config().Exchange.Subscribed() {
+		err := s.persistSymbol(symbol)
+		if err != nil {
+			log.Logger.Sugar().Error("persist exchange trades err ", symbol, err)
+		}
+		itemCounted(ctx, err)
+	}
+
+	dropped, err := models.NewExchangeTrade().Prune(months)
+	if err != nil {
+		log.Logger.Error(err.Error())
+	}
+	if len(dropped) > 0 {
+		log.Logger.Sugar().Info("dropped exchange trade tables ", dropped)
+	}
+}
+
+// persistSymbol 从已保存的最后一笔成交时间开始读取，同一毫秒的成交可能重复，由唯一索引忽略
+func (s *ExchangeTrade) persistSymbol(symbol string) error {
+	last, err := models.NewExchangeTrade().LastTickTime(symbol)
+	if err != nil {
+		return err
+	}
+	members, err := db.RedisZRangeByScore("exchange_ticks:"+symbol, last, math.MaxInt64)
+	if err != nil {
+		return err
+	}
+
+	now := utils.GetCurDateTimeFormat()
+	trades := make([]models.ExchangeTrade, 0, len(members))
+	for _, member := range members {
+		tick := models.ExchangeTick{}
+		if err = json.Unmarshal([]byte(member), &tick); err != nil {
+			continue
+		}
+		trades = append(trades, models.ExchangeTrade{
+			Symbol:    symbol,
+			Sequence:  tick.Sequence,
+			Price:     tick.Price,
+			Size:      tick.Size,
+			TickTime:  tick.Time,
+			CreatedAt: now,
+		})
+	}
+	if len(trades) == 0 {
+		return nil
+	}
+	return models.NewExchangeTrade().Save(trades)
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"bytes"
+	"database/sql"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	"pledge-backend/utils"
+	"strings"
+	"time"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// exportTable 需要导出的表，timeColumn 非空时只导出该列 (Unix 时间戳) 落在导出日内的行，否则导出全表快照
+type exportTable struct {
+	name       string
+	timeColumn string
+}
+
+var exportTables = []exportTable{
+	{name: "poolbases"},
+	{name: "pooldata"},
+	{name: "token_price_history", timeColumn: "price_at"},
+}
+
+type Export struct{}
+
+func NewExport() *Export {
+	return &Export{}
+}
+
+// ExportDaily 将前一个 UTC 日的数据以 Parquet 格式上传到 [export] 配置的 S3 兼容存储
+//
+// 对象 key: {prefix}/{table}/dt={YYYY-MM-DD}/{table}.parquet，重复执行会覆盖同一天的文件
+func (s *Export) ExportDaily() {
+	if !config.Config().Export.Enabled {
+		return
+	}
+	day := time.Now().UTC().AddDate(0, 0, -1)
+	for _, table := range exportTables {
+		err := s.Export(table, day)
+		if err != nil {
+			log.Logger.Sugar().Error("ExportDaily err ", table.name, err)
+		}
+	}
+}
+
+// Export 导出一张表，所有列按字符串写入，NULL 保留为空值
+func (s *Export) Export(table exportTable, day time.Time) error {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+
+	tx := db.Mysql.Table(table.name)
+	if table.timeColumn != "" {
+		tx = tx.Where(table.timeColumn+">=? and "+table.timeColumn+"<?", start.Unix(), end.Unix())
+	}
+	rows, err := tx.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	schema := make([]string, 0, len(columns))
+	for _, column := range columns {
+		schema = append(schema, "name="+column+", type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL")
+	}
+
+	var buf bytes.Buffer
+	pw, err := writer.NewCSVWriterFromWriter(schema, &buf, 1)
+	if err != nil {
+		return err
+	}
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	count := 0
+	for rows.Next() {
+		err = rows.Scan(dest...)
+		if err != nil {
+			return err
+		}
+		record := make([]*string, len(columns))
+		for i := range values {
+			if values[i].Valid {
+				value := values[i].String
+				record[i] = &value
+			}
+		}
+		err = pw.WriteString(record)
+		if err != nil {
+			return err
+		}
+		count++
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	if err = pw.WriteStop(); err != nil {
+		return err
+	}
+
+	key := strings.Trim(config.Config().Export.Prefix, "/") + "/" + table.name + "/dt=" + start.Format("2006-01-02") + "/" + table.name + ".parquet"
+	err = utils.S3PutObject(key, buf.Bytes(), "application/octet-stream")
+	if err != nil {
+		return err
+	}
+	log.Logger.Sugar().Info("Export ", key, " rows ", count)
+	return nil
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// feeStates 收取手续费的池子状态: 完成和清算时合约卖出抵押品偿还出借人，并向 feeAddress 转出 lendFee / borrowFee
+var feeStates = []string{poolStateFinish, poolStateLiquidation}
+
+// FeeRevenue 统计协议的手续费收入
+//
+// 池子完成或清算时 PledgePool 按以下方式收费 (费率为 1e8 精度):
+//   - lendFee: 卖出抵押品换回 lendAmount * (1 + lendFee)，超出 lendAmount 的部分转给 feeAddress，
+//     pooldata 中 finishAmountLend / liquidationAmounLend 为扣除后的 lendAmount，因此手续费为 amountLend * lendFee
+//   - borrowFee: 剩余抵押品 remain 扣除 remain * borrowFee 后记为 finishAmountBorrow / liquidationAmounBorrow，
+//     因此 remain = amountBorrow / (1 - borrowFee)，手续费为 remain - amountBorrow
+//
+// 每个池子只统计一次，写入 fee_revenues
+type FeeRevenue struct{}
+
+func NewFeeRevenue() *FeeRevenue {
+	return &FeeRevenue{}
+}
+
+// AccountFeeRevenue 统计所有启用的链上新完成或清算的池子
+func (s *FeeRevenue) AccountFeeRevenue(ctx context.Context) {
+	for _, chainId := range []string{config.Config().TestNet.ChainId, config.Config().MainNet.ChainId} {
+		if config.Config().ChainEnabled(JobAccountFeeRevenue, chainId) {
+			s.accountChain(ctx, chainId)
+		}
+	}
+}
+
+func (s *FeeRevenue) accountChain(ctx context.Context, chainId string) {
+	var pools []models.PoolBase
+	if err := models.NewFeeRevenue().Unaccounted(ctx, chainId, feeStates, &pools); err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	if len(pools) == 0 {
+		return
+	}
+	var tokens []models.TokenInfo
+	if err := models.NewTokenInfo().ListByChain(chainId, &tokens); err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	tokenMap := make(map[string]models.TokenInfo, len(tokens))
+	for _, v := range tokens {
+		tokenMap[strings.ToLower(v.Token)] = v
+	}
+
+	for i := range pools {
+		if ctx.Err() != nil {
+			return
+		}
+		err := s.account(&pools[i], tokenMap)
+		if err != nil {
+			log.Logger.Sugar().Error("AccountFeeRevenue err ", chainId, " ", pools[i].PoolId, " ", err)
+		}
+		itemCounted(ctx, err)
+	}
+}
+
+// account 计算并记录一个池子的手续费收入
+// tokenMap 为链上的代币，key 为小写地址
+func (s *FeeRevenue) account(pool *models.PoolBase, tokenMap map[string]models.TokenInfo) error {
+	data := models.NewPoolData()
+	if err := data.Get(pool.ChainId, pool.PoolId); err != nil {
+		return err
+	}
+	event, amountLend, amountBorrow := models.FeeEventFinish, data.FinishAmountLend, data.FinishAmountBorrow
+	if pool.State == poolStateLiquidation {
+		event, amountLend, amountBorrow = models.FeeEventLiquidation, data.LiquidationAmounLend, data.LiquidationAmounBorrow
+	}
+
+	lendToken := models.LendToken{}
+	borrowToken := models.BorrowToken{}
+	_ = json.Unmarshal([]byte(pool.LendTokenInfo), &lendToken)
+	_ = json.Unmarshal([]byte(pool.BorrowTokenInfo), &borrowToken)
+	lendFee := lendFeeAmount(toDecimal(amountLend), toDecimal(lendToken.LendFee))
+	borrowFee := borrowFeeAmount(toDecimal(amountBorrow), toDecimal(borrowToken.BorrowFee))
+
+	accruedAt, err := models.NewPoolSnapshot().FirstInState(pool.ChainId, pool.PoolId, pool.State)
+	if err != nil {
+		return err
+	}
+	if accruedAt == 0 {
+		accruedAt = time.Now().Unix()
+	}
+	lendUsd, err := s.usdAt(tokenMap[strings.ToLower(pool.LendToken)], lendFee, accruedAt)
+	if err != nil {
+		return err
+	}
+	borrowUsd, err := s.usdAt(tokenMap[strings.ToLower(pool.BorrowToken)], borrowFee, accruedAt)
+	if err != nil {
+		return err
+	}
+
+	return models.NewFeeRevenue().Create(&models.FeeRevenue{
+		ChainId:         pool.ChainId,
+		PoolId:          pool.PoolId,
+		Event:           event,
+		LendToken:       pool.LendToken,
+		BorrowToken:     pool.BorrowToken,
+		LendFeeAmount:   lendFee.String(),
+		BorrowFeeAmount: borrowFee.String(),
+		LendFeeUsd:      lendUsd.StringFixed(0),
+		BorrowFeeUsd:    borrowUsd.StringFixed(0),
+		TotalUsd:        lendUsd.Add(borrowUsd).StringFixed(0),
+		AccruedAt:       accruedAt,
+	})
+}
+
+// usdAt 代币数量按 ts 时的价格折算的美元价值, 1e8 精度，没有价格记录时使用 token_info 的当前价格
+// 代币不在 token_info 中时价值为 0
+func (s *FeeRevenue) usdAt(tokenInfo models.TokenInfo, amount decimal.Decimal, ts int64) (decimal.Decimal, error) {
+	if tokenInfo.Token == "" {
+		return decimal.Zero, nil
+	}
+	price, err := models.NewTokenPriceHistory().PriceBefore(tokenInfo.ChainId, tokenInfo.Token, ts)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if price != "" {
+		tokenInfo.Price = price
+	}
+	return usdValue(amount, tokenInfo), nil
+}
+
+// lendFeeAmount amountLend * lendFee / 1e8，向下取整
+func lendFeeAmount(amountLend, lendFee decimal.Decimal) decimal.Decimal {
+	return amountLend.Mul(lendFee).Div(feePrecision).Floor()
+}
+
+// borrowFeeAmount 由扣费后的 amountBorrow 反推扣费前的数量 remain，手续费为 remain - amountBorrow
+func borrowFeeAmount(amountBorrow, borrowFee decimal.Decimal) decimal.Decimal {
+	if !borrowFee.IsPositive() || borrowFee.GreaterThanOrEqual(feePrecision) {
+		return decimal.Zero
+	}
+	remain := amountBorrow.Mul(feePrecision).Div(feePrecision.Sub(borrowFee)).Ceil()
+	return remain.Sub(amountBorrow)
+}
+
+// feePrecision lendFee / borrowFee 的精度
+var feePrecision = decimal.NewFromInt(100000000)
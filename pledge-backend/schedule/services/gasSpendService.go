@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+)
+
+// gasSpendDropAfter 发送后超过该时间仍查不到回执的交易视为被丢弃
+const gasSpendDropAfter = time.Hour
+
+// GasSpend 统计后端发送的交易的 gas 花费
+//
+// 发送交易后调用 Record 记录 (status=pending)，定时任务 UpdateGasSpend 查询回执补全 gas_used、fee_wei，
+// 然后检查当月花费是否超出 [gas] 预算
+type GasSpend struct{}
+
+func NewGasSpend() *GasSpend {
+	return &GasSpend{}
+}
+
+// Record 记录一笔已发送的交易，purpose 为交易用途，例如 oracle_set_price
+func (s *GasSpend) Record(chainId, purpose string, tx *types.Transaction) {
+	spend := models.GasSpend{
+		ChainId:  chainId,
+		Purpose:  purpose,
+		TxHash:   tx.Hash().Hex(),
+		Nonce:    tx.Nonce(),
+		GasLimit: tx.Gas(),
+		GasPrice: tx.GasPrice().String(),
+	}
+	if tx.To() != nil {
+		spend.ToAddress = tx.To().Hex()
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err == nil {
+		spend.FromAddress = from.Hex()
+	}
+	if err = models.NewGasSpend().Save(&spend); err != nil {
+		log.Logger.Sugar().Error("GasSpend Record err ", chainId, " ", spend.TxHash, " ", err)
+	}
+	s.publishOracleTx(spend)
+}
+
+// publishOracleTx 喂价交易状态变化时发布运维事件，其他用途的交易不发布
+func (s *GasSpend) publishOracleTx(spend models.GasSpend) {
+	if strings.HasPrefix(spend.Purpose, "oracle_") {
+		PublishOpsEvent(models.OpsEventOracleTx, spend.ChainId, spend)
+	}
+}
+
+// UpdateGasSpend 查询待上链交易的回执，然后检查各链当月花费
+func (s *GasSpend) UpdateGasSpend() {
+	var pending []models.GasSpend
+	err := models.NewGasSpend().Pending(&pending)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+
+	clients := make(map[string]*ethclient.Client)
+	defer func() {
+		for _, client := range clients {
+			client.Close()
+		}
+	}()
+	for _, spend := range pending {
+		client, ok := clients[spend.ChainId]
+		if !ok {
+			netUrl := chainNetUrl(spend.ChainId)
+			if netUrl == "" {
+				continue
+			}
+			client, err = ethclient.Dial(netUrl)
+			if err != nil {
+				log.Logger.Error(err.Error())
+				continue
+			}
+			clients[spend.ChainId] = client
+		}
+		s.updateReceipt(client, spend)
+	}
+
+	if config.Config().ChainEnabled(JobUpdateGasSpend, config.Config().TestNet.ChainId) {
+		s.checkBudget(config.Config().TestNet.ChainId, config.Config().Gas.TestnetMonthlyBudget)
+	}
+	if config.Config().ChainEnabled(JobUpdateGasSpend, config.Config().MainNet.ChainId) {
+		s.checkBudget(config.Config().MainNet.ChainId, config.Config().Gas.MainnetMonthlyBudget)
+	}
+}
+
+// updateReceipt 回执存在时按 gas_used * gas_price 计算花费
+// BSC 使用 legacy 交易，gas_price 即实际价格
+func (s *GasSpend) updateReceipt(client *ethclient.Client, spend models.GasSpend) {
+	receipt, err := client.TransactionReceipt(context.Background(), common.HexToHash(spend.TxHash))
+	if errors.Is(err, ethereum.NotFound) {
+		createdAt, err := time.ParseInLocation("2006-01-02 15:04:05", spend.CreatedAt, time.Local)
+		if err == nil && time.Since(createdAt) > gasSpendDropAfter {
+			err = models.NewGasSpend().UpdateStatus(spend.Id, models.GasSpendDropped, 0, "0", 0)
+			if err != nil {
+				log.Logger.Error(err.Error())
+			}
+			spend.Status = models.GasSpendDropped
+			s.publishOracleTx(spend)
+		}
+		return
+	}
+	if err != nil {
+		log.Logger.Sugar().Error("GasSpend TransactionReceipt err ", spend.ChainId, " ", spend.TxHash, " ", err)
+		return
+	}
+
+	gasPrice, ok := new(big.Int).SetString(spend.GasPrice, 10)
+	if !ok {
+		gasPrice = big.NewInt(0)
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), gasPrice)
+	status := models.GasSpendSuccess
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		status = models.GasSpendFailed
+	}
+	err = models.NewGasSpend().UpdateStatus(spend.Id, status, receipt.GasUsed, fee.String(), receipt.BlockNumber.Uint64())
+	if err != nil {
+		log.Logger.Error(err.Error())
+	}
+	spend.Status, spend.GasUsed, spend.FeeWei, spend.BlockNumber = status, receipt.GasUsed, fee.String(), receipt.BlockNumber.Uint64()
+	s.publishOracleTx(spend)
+}
+
+// checkBudget 当月花费超出预算时发送告警，每条链每月只发送一次
+func (s *GasSpend) checkBudget(chainId, budget string) {
+	budgetBnb, err := decimal.NewFromString(budget)
+	if err != nil || !budgetBnb.IsPositive() {
+		return
+	}
+
+	now := time.Now()
+	month := now.Format("2006-01")
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	feeWei, err := models.NewGasSpend().SumFee(chainId, start.Format("2006-01-02 15:04:05"), start.AddDate(0, 1, 0).Format("2006-01-02 15:04:05"))
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	fee, err := decimal.NewFromString(feeWei)
+	if err != nil || fee.Shift(-18).LessThanOrEqual(budgetBnb) {
+		return
+	}
+
+	alerted, err := models.NewAlertHistory().Exists("gas_budget", chainId, month)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	if alerted {
+		return
+	}
+
+	text := fmt.Sprintf("Pledge gas spend on chain %s in %s is %s BNB, over the monthly budget of %s BNB",
+		chainId, month, fee.Shift(-18).String(), budgetBnb.String())
+	log.Logger.Sugar().Warn(text)
+	var errs []string
+	if err = utils.SendEmail([]byte("<p>"+text+"</p>"), 2); err != nil {
+		log.Logger.Error(err.Error())
+		errs = append(errs, "email: "+err.Error())
+	}
+	saveAlertHistory("gas_budget", chainId, month, models.AlertLevelEmail, 0, text, errs)
+}
+
+// chainNetUrl 链 ID 对应的 RPC 地址，net_url 不健康时为备用节点，见 models.ChainHealth.RpcUrl
+func chainNetUrl(chainId string) string {
+	return models.NewChainHealth().RpcUrl(chainId)
+}
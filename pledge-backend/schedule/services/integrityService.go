@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// integrityTokenColumns PoolBase 中来自 token_info 而不是链上的列，核对池子时不比较
+var integrityTokenColumns = []string{"lend_token_info", "borrow_token_info", "lend_token_symbol", "borrow_token_symbol"}
+
+// integrityStore 保存池子数据的一处存储，base、data 只有一个非空
+type integrityStore struct {
+	name string
+	base *models.PoolBase
+	data *models.PoolData
+}
+
+// Integrity 抽样核对链上数据与 MySQL、Redis，发现增量同步 (Redis 缓存与链上相同时不写 MySQL) 遗漏的更新
+//
+// 池子: 在最新区块之前第 read_lag 个区块读取 PoolBaseInfo、PoolDataInfo，与 poolbases、pooldata 和 Redis 缓存比较；
+// 不一致的字段再与 UpdateAllPoolInfo 最近一次读取的区块比较，两个区块都不一致才计为不一致，
+// 排除上次同步之后链上的正常变化。节点已不保留该区块的状态时只按本次读取的区块比较。
+// 代币: 读取 symbol()、name()、decimals()，与 token_info 和 Redis 缓存 token_info:<chainId>:<token> 比较。
+//
+// [integrity] repair 时清除不一致的池子、代币的缓存并重新写入链上数据；代币精度变化会影响所有金额换算，只记录不修复
+type Integrity struct{}
+
+func NewIntegrity() *Integrity {
+	return &Integrity{}
+}
+
+// VerifyIntegrity 核对所有启用的链，每条链的结果写入 Redis integrity:<chainId>
+func (s *Integrity) VerifyIntegrity(ctx context.Context) {
+	if config.Config().ChainEnabled(JobVerifyIntegrity, config.Config().TestNet.ChainId) {
+		s.verifyChain(ctx, config.Config().TestNet.ChainId, models.NewChainHealth().RpcUrl(config.Config().TestNet.ChainId), config.Config().TestNet.PledgePoolToken)
+	}
+	if config.Config().ChainEnabled(JobVerifyIntegrity, config.Config().MainNet.ChainId) {
+		s.verifyChain(ctx, config.Config().MainNet.ChainId, models.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId), config.Config().MainNet.PledgePoolToken)
+	}
+}
+
+func (s *Integrity) verifyChain(ctx context.Context, chainId, netUrl, poolAddress string) {
+	report := models.IntegrityReport{ChainId: chainId, Drifts: make([]models.IntegrityDrift, 0)}
+	previous := models.IntegrityReport{}
+	if err := previous.Get(chainId); err == nil {
+		report.TotalChecked, report.TotalDrifted = previous.TotalChecked, previous.TotalDrifted
+	}
+
+	// 先取上次同步的区块，dialPool 之后本次读取的区块会记录到 read_blocks
+	syncRead := models.ReadBlock{}
+	if err := syncRead.Get(chainId, JobUpdateAllPoolInfo); err == nil {
+		report.SyncBlock = syncRead.Block
+	}
+
+	if config.Config().Integrity.SamplePools > 0 {
+		s.verifyPools(ctx, &report, netUrl, poolAddress)
+	}
+	if config.Config().Integrity.SampleTokens > 0 {
+		s.verifyTokens(ctx, &report, netUrl)
+	}
+
+	report.TotalChecked += int64(report.PoolsChecked + report.TokensChecked)
+	report.TotalDrifted += int64(report.PoolsDrifted + report.TokensDrifted)
+	report.CheckedAt = time.Now().Unix()
+	if err := report.Save(); err != nil {
+		log.Logger.Error(err.Error())
+	}
+	if report.PoolsDrifted+report.TokensDrifted > 0 {
+		log.Logger.Sugar().Warn("VerifyIntegrity drift ", chainId, " pools ", report.PoolsDrifted, "/", report.PoolsChecked,
+			" tokens ", report.TokensDrifted, "/", report.TokensChecked, " repaired ", report.Repaired)
+	}
+}
+
+// verifyPools 抽查未归档、未软删除的池子
+func (s *Integrity) verifyPools(ctx context.Context, report *models.IntegrityReport, netUrl, poolAddress string) {
+	var pools []models.PoolBase
+	states := []string{poolStateMatch, poolStateExecution, poolStateFinish, poolStateLiquidation, poolStateUndone}
+	if err := models.NewPoolBase().ListByStates(ctx, report.ChainId, states, &pools); err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	if len(pools) == 0 {
+		return
+	}
+	rand.Shuffle(len(pools), func(i, j int) { pools[i], pools[j] = pools[j], pools[i] })
+	if len(pools) > config.Config().Integrity.SamplePools {
+		pools = pools[:config.Config().Integrity.SamplePools]
+	}
+
+	poolService := NewPool()
+	pool, err := poolService.dialPool(ctx, JobVerifyIntegrity, poolAddress, netUrl, report.ChainId)
+	if err != nil {
+		return
+	}
+	defer pool.conn.Close()
+	report.Block = pool.block
+
+	// 上次同步时的链上状态
+	var synced *pledgePool
+	if report.SyncBlock > 0 && report.SyncBlock < pool.block {
+		at := *pool
+		at.callOpts = &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(report.SyncBlock)}
+		synced = &at
+	}
+
+	for i := range pools {
+		if ctx.Err() != nil {
+			return
+		}
+		poolId := utils.IntToString(pools[i].PoolId)
+		drifts, err := s.verifyPool(ctx, poolService, pool, synced, report.ChainId, poolId)
+		itemCounted(ctx, err)
+		if err != nil {
+			log.Logger.Sugar().Error("VerifyIntegrity pool err ", report.ChainId, " ", poolId, " ", err)
+			continue
+		}
+		report.PoolsChecked++
+		if len(drifts) == 0 {
+			continue
+		}
+		report.PoolsDrifted++
+
+		if config.Config().Integrity.Repair {
+			// 清除缓存后 syncPool 不会跳过写入，与数据库逐列比较后更新
+			_, _ = db.RedisDelete(poolBaseCacheKey(report.ChainId, poolId))
+			_, _ = db.RedisDelete(poolDataCacheKey(report.ChainId, poolId))
+			if err = poolService.syncPool(ctx, pool, report.ChainId, poolId); err != nil {
+				log.Logger.Sugar().Error("VerifyIntegrity repair pool err ", report.ChainId, " ", poolId, " ", err)
+			} else {
+				report.Repaired++
+				for j := range drifts {
+					drifts[j].Repaired = true
+				}
+			}
+		}
+		report.Drifts = append(report.Drifts, drifts...)
+	}
+}
+
+// verifyPool 比较一个池子的链上数据与 poolbases、pooldata 和 Redis 缓存，返回不一致的字段
+func (s *Integrity) verifyPool(ctx context.Context, poolService *poolService, pool, synced *pledgePool, chainId, poolId string) ([]models.IntegrityDrift, error) {
+	chainBase, chainData, err := poolService.readPool(pool, chainId, poolId)
+	if err != nil {
+		return nil, err
+	}
+
+	storedBase := models.PoolBase{}
+	err = db.Mysql.WithContext(ctx).Table("poolbases").Where("chain_id=? and pool_id=?", chainId, poolId).First(&storedBase).Error
+	if err != nil {
+		return nil, err
+	}
+	storedData := models.PoolData{}
+	if err = storedData.Get(chainId, utils.StringToInt(poolId)); err != nil {
+		return nil, err
+	}
+	stores := []integrityStore{
+		{name: "poolbases", base: &storedBase},
+		{name: "pooldata", data: &storedData},
+	}
+	cachedBase, cachedData := models.PoolBase{}, models.PoolData{}
+	if poolService.cached(ctx, poolBaseCacheKey(chainId, poolId), &cachedBase) {
+		stores = append(stores, integrityStore{name: models.IntegrityStoreRedis, base: &cachedBase})
+	}
+	if poolService.cached(ctx, poolDataCacheKey(chainId, poolId), &cachedData) {
+		stores = append(stores, integrityStore{name: models.IntegrityStoreRedis, data: &cachedData})
+	}
+
+	// 上次同步时的链上数据，读取失败 (节点不保留该区块的状态) 时只按本次读取的区块比较
+	var syncedBase *models.PoolBase
+	var syncedData *models.PoolData
+	if synced != nil {
+		syncedBase, syncedData, err = poolService.readPool(synced, chainId, poolId)
+		if err != nil {
+			syncedBase, syncedData = nil, nil
+		}
+	}
+
+	drifts := make([]models.IntegrityDrift, 0)
+	for _, store := range stores {
+		var changes, syncedChanges models.ColumnChanges
+		var stored map[string]string
+		if store.base != nil {
+			changes = chainBase.Changes(store.base)
+			for _, column := range integrityTokenColumns {
+				delete(changes, column)
+			}
+			if syncedBase != nil {
+				syncedChanges = syncedBase.Changes(store.base)
+			}
+			stored = poolBaseColumns(store.base)
+		} else {
+			changes = chainData.Changes(store.data)
+			if syncedData != nil {
+				syncedChanges = syncedData.Changes(store.data)
+			}
+			stored = poolDataColumns(store.data)
+		}
+		for column, value := range changes {
+			if syncedChanges != nil {
+				if _, ok := syncedChanges[column]; !ok {
+					// 与上次同步时的链上数据一致，是同步之后链上的正常变化
+					continue
+				}
+			}
+			drifts = append(drifts, models.IntegrityDrift{
+				Kind:    models.IntegrityKindPool,
+				Id:      poolId,
+				Store:   store.name,
+				Column:  column,
+				Stored:  stored[column],
+				OnChain: fmt.Sprint(value),
+			})
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].Store != drifts[j].Store {
+			return drifts[i].Store < drifts[j].Store
+		}
+		return drifts[i].Column < drifts[j].Column
+	})
+	return drifts, nil
+}
+
+// poolBaseColumns PoolBase 中链上数据的列值，与 Changes 的列名一致
+func poolBaseColumns(p *models.PoolBase) map[string]string {
+	return map[string]string{
+		"settle_time":              p.SettleTime,
+		"end_time":                 p.EndTime,
+		"interest_rate":            p.InterestRate,
+		"max_supply":               p.MaxSupply,
+		"lend_supply":              p.LendSupply,
+		"borrow_supply":            p.BorrowSupply,
+		"martgage_rate":            p.MartgageRate,
+		"lend_token":               p.LendToken,
+		"borrow_token":             p.BorrowToken,
+		"state":                    p.State,
+		"sp_coin":                  p.SpCoin,
+		"jp_coin":                  p.JpCoin,
+		"auto_liquidate_threshold": p.AutoLiquidateThreshold,
+	}
+}
+
+// poolDataColumns PoolData 的列值，与 Changes 的列名一致
+func poolDataColumns(p *models.PoolData) map[string]string {
+	return map[string]string{
+		"finish_amount_borrow":     p.FinishAmountBorrow,
+		"finish_amount_lend":       p.FinishAmountLend,
+		"liquidation_amoun_borrow": p.LiquidationAmounBorrow,
+		"liquidation_amoun_lend":   p.LiquidationAmounLend,
+		"settle_amount_borrow":     p.SettleAmountBorrow,
+		"settle_amount_lend":       p.SettleAmountLend,
+	}
+}
+
+// verifyTokens 抽查已读取过元信息的代币，主网只抽查已下载 ABI 文件的代币，与 UpdateContractMetadata 一致
+func (s *Integrity) verifyTokens(ctx context.Context, report *models.IntegrityReport, netUrl string) {
+	var all []models.TokenInfo
+	if err := models.NewTokenInfo().ListByChain(report.ChainId, &all); err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	mainNet := report.ChainId == config.Config().MainNet.ChainId
+	tokens := make([]models.TokenInfo, 0, len(all))
+	for _, t := range all {
+		if t.DeletedAt == nil && t.Token != "" && t.Symbol != "" && (!mainNet || t.AbiFileExist == 1) {
+			tokens = append(tokens, t)
+		}
+	}
+	rand.Shuffle(len(tokens), func(i, j int) { tokens[i], tokens[j] = tokens[j], tokens[i] })
+	if len(tokens) > config.Config().Integrity.SampleTokens {
+		tokens = tokens[:config.Config().Integrity.SampleTokens]
+	}
+
+	symbolService := NewTokenSymbol()
+	repaired := false
+	defer func() {
+		if repaired {
+			refreshTokenList(report.ChainId)
+		}
+	}()
+	for _, t := range tokens {
+		if ctx.Err() != nil {
+			return
+		}
+		abiName := "erc20"
+		if mainNet {
+			abiName = t.Token
+		}
+		err, metadata := symbolService.GetContractMetadata(t.Token, netUrl, abiName)
+		itemCounted(ctx, err)
+		if err != nil {
+			continue
+		}
+		report.TokensChecked++
+
+		drifts := make([]models.IntegrityDrift, 0)
+		drift := func(store, column, stored, onChain string) {
+			if stored != onChain {
+				drifts = append(drifts, models.IntegrityDrift{Kind: models.IntegrityKindToken, Id: t.Token,
+					Store: store, Column: column, Stored: stored, OnChain: onChain})
+			}
+		}
+		drift("token_info", "symbol", t.Symbol, metadata.Symbol)
+		drift("token_info", "name", t.Name, metadata.Name)
+		drift("token_info", "decimals", strconv.Itoa(t.Decimals), strconv.Itoa(metadata.Decimals))
+		cacheKey := "token_info:" + t.ChainId + ":" + t.Token
+		cached := models.RedisTokenInfo{}
+		if NewPool().cached(ctx, cacheKey, &cached) {
+			drift(models.IntegrityStoreRedis, "symbol", cached.Symbol, metadata.Symbol)
+		}
+		if len(drifts) == 0 {
+			continue
+		}
+		report.TokensDrifted++
+
+		if config.Config().Integrity.Repair {
+			if t.Decimals != metadata.Decimals {
+				log.Logger.Sugar().Error("VerifyIntegrity decimals changed, not repaired ", t.Token, t.ChainId, t.Decimals, " -> ", metadata.Decimals)
+			} else if err = symbolService.SaveMetadata(t.Token, t.ChainId, metadata); err == nil {
+				_, _ = db.RedisDelete(cacheKey)
+				report.Repaired++
+				repaired = true
+				for j := range drifts {
+					drifts[j].Repaired = true
+				}
+			}
+		}
+		report.Drifts = append(report.Drifts, drifts...)
+	}
+}
@@ -0,0 +1,31 @@
+package services
+
+// 定时任务名称，对应配置文件中的 [jobs.<任务名称>]，也用于 span 名称和 GET /admin/jobs 的执行统计
+const (
+	JobUpdateAllPoolInfo      = "UpdateAllPoolInfo"
+	JobUpdatePoolEvents       = "UpdatePoolEvents"
+	JobUpdateContractPrice    = "UpdateContractPrice"
+	JobUpdateChainlinkPrice   = "UpdateChainlinkPrice"
+	JobUpdateContractMetadata = "UpdateContractMetadata"
+	JobUpdateTokenLogo        = "UpdateTokenLogo"
+	JobUpdateSearchIndex      = "UpdateSearchIndex"
+	JobMonitor                = "Monitor"
+	JobSaveAllPlgrPrice       = "SaveAllPlgrPrice"
+	JobUpdateGasSpend         = "UpdateGasSpend"
+	JobUpdateChainHealth      = "UpdateChainHealth"
+	JobOracleMonitor          = "OracleMonitor"
+	JobGenerateDailyReport    = "GenerateDailyReport"
+	JobExportDaily            = "ExportDaily"
+	JobBackupDatabase         = "BackupDatabase"
+	JobVerifyIntegrity        = "VerifyIntegrity"
+	JobProcessRetryQueue      = "ProcessRetryQueue"
+	JobArchivePools           = "ArchivePools"
+	JobLiquidatePools         = "LiquidatePools"
+	JobWatchDeadlines         = "WatchDeadlines"
+	JobNotifySubscribers      = "NotifySubscribers"
+	JobAccountFeeRevenue      = "AccountFeeRevenue"
+	JobVerifyReferrals        = "VerifyReferrals"
+	JobPersistExchangeTrades  = "PersistExchangeTrades"
+	JobBackfillPriceHistory   = "BackfillPriceHistory"
+	JobSyncPrivileges         = "SyncPrivileges"
+)
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"pledge-backend/config"
+	"pledge-backend/contract/bindings"
+	"pledge-backend/log"
+	serviceCommon "pledge-backend/schedule/common"
+	"pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// keeper 发送的池子操作，对应 PledgePool.sol 的 settle / finish / liquidate
+const (
+	keeperActionSettle    = "settle"
+	keeperActionFinish    = "finish"
+	keeperActionLiquidate = "liquidate"
+)
+
+// keeperTimeout 单个池子检查和发送交易的时限
+const keeperTimeout = 30 * time.Second
+
+// Keeper 清算执行机器人，[keeper] enabled 时由 LiquidatePools 定时执行:
+//   - 匹配中的池子到达 settleTime 且 checkoutSettle 为 true 时发送 settle
+//   - 执行中的池子到达 endTime 且 checkoutFinish 为 true 时发送 finish
+//   - 执行中的池子 checkoutLiquidate 为 true (抵押价值低于 autoLiquidateThreshold) 时发送 liquidate
+//
+// 每次尝试记录在 keeper_tx 表，已发送的交易同时计入 gas_spend；同一池子的同一操作有交易待上链时不再发送，
+// 其它结果在 [keeper] retry_minutes 之后重新尝试
+type Keeper struct{}
+
+func NewKeeper() *Keeper {
+	return &Keeper{}
+}
+
+// keeperChain 一条链上的 PledgePool 合约和 keeper 账户
+type keeperChain struct {
+	chainId string
+	client  *ethclient.Client
+	pool    *bindings.PledgePoolToken
+	signer  Signer
+}
+
+// LiquidatePools 先更新待上链交易的回执，再检查所有启用的链上需要处理的池子
+func (s *Keeper) LiquidatePools(ctx context.Context) {
+	if !config.Config().Keeper.Enabled {
+		return
+	}
+	s.updateReceipts(ctx)
+
+	key := config.Config().Keeper.SignerKey
+	if key == "" {
+		key = serviceCommon.PlgrAdminPrivateKey
+	}
+	signer, err := NewKeySigner(key)
+	if err != nil {
+		log.Logger.Sugar().Error("Keeper signer err ", err)
+		return
+	}
+
+	if config.Config().ChainEnabled(JobLiquidatePools, config.Config().TestNet.ChainId) {
+		s.executeChain(ctx, signer, config.Config().TestNet.ChainId, models.NewChainHealth().RpcUrl(config.Config().TestNet.ChainId), config.Config().TestNet.PledgePoolToken)
+	}
+	if config.Config().ChainEnabled(JobLiquidatePools, config.Config().MainNet.ChainId) {
+		s.executeChain(ctx, signer, config.Config().MainNet.ChainId, models.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId), config.Config().MainNet.PledgePoolToken)
+	}
+}
+
+func (s *Keeper) executeChain(ctx context.Context, signer Signer, chainId, netUrl, poolAddress string) {
+	var pools []models.PoolBase
+	err := models.NewPoolBase().ListByStates(ctx, chainId, []string{poolStateMatch, poolStateExecution}, &pools)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	if len(pools) == 0 {
+		return
+	}
+
+	client, err := ethclient.Dial(netUrl)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	defer client.Close()
+	pool, err := bindings.NewPledgePoolToken(common.HexToAddress(poolAddress), client)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	chain := &keeperChain{chainId: chainId, client: client, pool: pool, signer: signer}
+
+	for i := range pools {
+		if ctx.Err() != nil {
+			return
+		}
+		poolCtx, cancel := context.WithTimeout(ctx, keeperTimeout)
+		err = s.executePool(poolCtx, chain, &pools[i])
+		cancel()
+		itemCounted(ctx, err)
+		if err != nil {
+			log.Logger.Sugar().Error("Keeper err ", chainId, " ", pools[i].PoolId, " ", err)
+		}
+	}
+}
+
+// executePool 按池子状态和时间选择操作，以合约的 checkout 结果为准，需要时发送交易
+func (s *Keeper) executePool(ctx context.Context, chain *keeperChain, base *models.PoolBase) error {
+	pid := big.NewInt(int64(base.PoolId - 1)) // 合约索引 = pool_id - 1
+	callOpts := &bind.CallOpts{Context: ctx}
+	now := time.Now().Unix()
+
+	var action string
+	var ready bool
+	var err error
+	switch {
+	case base.State == poolStateMatch && now >= utils.StringToInt64(base.SettleTime):
+		action = keeperActionSettle
+		ready, err = chain.pool.CheckoutSettle(callOpts, pid)
+	case base.State == poolStateExecution && now >= utils.StringToInt64(base.EndTime):
+		action = keeperActionFinish
+		ready, err = chain.pool.CheckoutFinish(callOpts, pid)
+	case base.State == poolStateExecution:
+		action = keeperActionLiquidate
+		ready, err = chain.pool.CheckoutLiquidate(callOpts, pid)
+	default:
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("checkout %s: %w", action, err)
+	}
+	if !ready {
+		return nil
+	}
+
+	last := models.KeeperTx{}
+	err = models.NewKeeperTx().Last(chain.chainId, base.PoolId, action, &last)
+	if err == nil && !s.retryDue(&last) {
+		return nil
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return s.submit(ctx, chain, base.PoolId, pid, action)
+}
+
+// retryDue 上一次尝试之后是否可以再次发送，待上链的交易等待回执
+func (s *Keeper) retryDue(last *models.KeeperTx) bool {
+	if last.Status == models.KeeperTxPending {
+		return false
+	}
+	createdAt, err := time.ParseInLocation("2006-01-02 15:04:05", last.CreatedAt, time.Local)
+	if err != nil {
+		return true
+	}
+	return time.Since(createdAt) >= time.Duration(config.Config().Keeper.RetryMinutes)*time.Minute
+}
+
+// submit 签名后检查 gas 上限，dry_run 时只记录，否则广播交易
+func (s *Keeper) submit(ctx context.Context, chain *keeperChain, poolId int, pid *big.Int, action string) error {
+	conf := config.Config().Keeper
+	record := models.KeeperTx{
+		ChainId:     chain.chainId,
+		PoolId:      poolId,
+		Action:      action,
+		FromAddress: chain.signer.Address().Hex(),
+	}
+
+	gasPrice, err := chain.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return err
+	}
+	record.GasPrice = gasPrice.String()
+	maxGasPrice, err := decimal.NewFromString(conf.MaxGasPriceGwei)
+	if err == nil && maxGasPrice.IsPositive() && decimal.NewFromBigInt(gasPrice, -9).GreaterThan(maxGasPrice) {
+		record.Status = models.KeeperTxSkipped
+		record.Error = fmt.Sprintf("gas price %s gwei over max_gas_price_gwei %s", decimal.NewFromBigInt(gasPrice, -9).String(), maxGasPrice.String())
+		return s.save(&record, errors.New(record.Error))
+	}
+
+	chainId, ok := new(big.Int).SetString(chain.chainId, 10)
+	if !ok {
+		return errors.New("invalid chain id " + chain.chainId)
+	}
+	opts, err := chain.signer.TransactOpts(ctx, chainId)
+	if err != nil {
+		return err
+	}
+	opts.GasPrice = gasPrice
+	opts.NoSend = true // 先签名并估算 gas，检查上限后再广播
+
+	var tx *types.Transaction
+	switch action {
+	case keeperActionSettle:
+		tx, err = chain.pool.Settle(opts, pid)
+	case keeperActionFinish:
+		tx, err = chain.pool.Finish(opts, pid)
+	case keeperActionLiquidate:
+		tx, err = chain.pool.Liquidate(opts, pid)
+	}
+	if err != nil {
+		record.Status = models.KeeperTxError
+		record.Error = err.Error()
+		return s.save(&record, err)
+	}
+	record.TxHash = tx.Hash().Hex()
+	record.Nonce = tx.Nonce()
+	record.GasLimit = tx.Gas()
+
+	if tx.Gas() > conf.MaxGasLimit {
+		record.Status = models.KeeperTxSkipped
+		record.Error = fmt.Sprintf("gas limit %d over max_gas_limit %d", tx.Gas(), conf.MaxGasLimit)
+		return s.save(&record, errors.New(record.Error))
+	}
+	if conf.DryRun {
+		record.Status = models.KeeperTxDryRun
+		log.Logger.Sugar().Info("Keeper dry-run ", chain.chainId, " ", poolId, " ", action, " tx ", record.TxHash, " gas ", tx.Gas())
+		return s.save(&record, nil)
+	}
+
+	if err = chain.client.SendTransaction(ctx, tx); err != nil {
+		record.Status = models.KeeperTxError
+		record.Error = err.Error()
+		return s.save(&record, err)
+	}
+	record.Status = models.KeeperTxPending
+	log.Logger.Sugar().Info("Keeper sent ", chain.chainId, " ", poolId, " ", action, " tx ", record.TxHash)
+	NewGasSpend().Record(chain.chainId, "keeper_"+action, tx)
+	return s.save(&record, nil)
+}
+
+// save 记录本次尝试，返回 cause 或记录失败的错误
+func (s *Keeper) save(record *models.KeeperTx, cause error) error {
+	if err := models.NewKeeperTx().Save(record); err != nil {
+		log.Logger.Sugar().Error("Keeper save err ", record.ChainId, " ", record.PoolId, " ", record.TxHash, " ", err)
+		if cause == nil {
+			return err
+		}
+	}
+	return cause
+}
+
+// updateReceipts 查询待上链交易的回执，超过 gasSpendDropAfter 仍查不到的视为被丢弃
+func (s *Keeper) updateReceipts(ctx context.Context) {
+	var pending []models.KeeperTx
+	if err := models.NewKeeperTx().Pending(&pending); err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+
+	clients := make(map[string]*ethclient.Client)
+	defer func() {
+		for _, client := range clients {
+			client.Close()
+		}
+	}()
+	for _, record := range pending {
+		client, ok := clients[record.ChainId]
+		if !ok {
+			netUrl := chainNetUrl(record.ChainId)
+			if netUrl == "" {
+				continue
+			}
+			var err error
+			client, err = ethclient.Dial(netUrl)
+			if err != nil {
+				log.Logger.Error(err.Error())
+				continue
+			}
+			clients[record.ChainId] = client
+		}
+
+		receipt, err := client.TransactionReceipt(ctx, common.HexToHash(record.TxHash))
+		if errors.Is(err, ethereum.NotFound) {
+			createdAt, err := time.ParseInLocation("2006-01-02 15:04:05", record.CreatedAt, time.Local)
+			if err == nil && time.Since(createdAt) > gasSpendDropAfter {
+				log.Logger.Sugar().Warn("Keeper tx dropped ", record.ChainId, " ", record.PoolId, " ", record.Action, " ", record.TxHash)
+				if err = models.NewKeeperTx().UpdateStatus(record.Id, models.KeeperTxDropped, 0, 0); err != nil {
+					log.Logger.Error(err.Error())
+				}
+			}
+			continue
+		}
+		if err != nil {
+			log.Logger.Sugar().Error("Keeper TransactionReceipt err ", record.ChainId, " ", record.TxHash, " ", err)
+			continue
+		}
+
+		status := models.KeeperTxSuccess
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			status = models.KeeperTxFailed
+			log.Logger.Sugar().Warn("Keeper tx failed ", record.ChainId, " ", record.PoolId, " ", record.Action, " ", record.TxHash)
+		}
+		if err = models.NewKeeperTx().UpdateStatus(record.Id, status, receipt.GasUsed, receipt.BlockNumber.Uint64()); err != nil {
+			log.Logger.Error(err.Error())
+		}
+	}
+}
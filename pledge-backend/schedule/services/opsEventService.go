@@ -0,0 +1,21 @@
+package services
+
+import (
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"time"
+)
+
+// PublishOpsEvent 发布一条运维事件，由 api 进程推送到 WebSocket 私有主题 ops，运维面板不必轮询管理接口
+// 发布失败只记录日志，不影响业务
+func PublishOpsEvent(kind, chainId string, data interface{}) {
+	err := models.NewOpsEvent().Publish(&models.OpsEvent{
+		Kind:    kind,
+		ChainId: chainId,
+		Data:    data,
+		At:      time.Now().UnixMilli(),
+	})
+	if err != nil {
+		log.Logger.Sugar().Error("PublishOpsEvent err ", kind, " ", err)
+	}
+}
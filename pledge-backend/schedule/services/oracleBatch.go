@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"pledge-backend/config"
+	"pledge-backend/contract/bindings"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"pledge-backend/telemetry"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// oracleBatchTimeout setPrices 发送、等待上链和读回价格的时限
+const oracleBatchTimeout = 2 * time.Minute
+
+// oracleFeed 一个待写入主网 Oracle 的交易所定价代币
+type oracleFeed struct {
+	Token   string // 主网代币地址
+	Symbol  string // 熔断器和写入决策的名称，例如 PLGR-USDT
+	Route   []string
+	Price   int64 // 1e8 精度
+	Breaker *OracleBreaker
+	Write   *OracleWrite
+}
+
+// OracleFeedTokens 写入主网 Oracle 的代币: PLGR 以及 [oracle] feeds
+func OracleFeedTokens() []string {
+	tokens := []string{config.Config().MainNet.PlgrAddress}
+	for _, token := range config.Config().Oracle.Feeds {
+		if !strings.EqualFold(token, config.Config().MainNet.PlgrAddress) {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// newOracleFeed PLGR 沿用 PlgrRoute 和 PLGR-USDT，其余代币按 [exchange.tokens] 的定价路径
+func newOracleFeed(token string) *oracleFeed {
+	feed := &oracleFeed{Token: token, Symbol: "PLGR-USDT", Route: PlgrRoute()}
+	if !strings.EqualFold(token, config.Config().MainNet.PlgrAddress) {
+		feed.Symbol = config.Config().Exchange.FeedSymbol(token)
+		feed.Route, _ = config.Config().Exchange.TokenRoute(token)
+	}
+	feed.Breaker = NewOracleBreaker(feed.Symbol)
+	feed.Breaker.Feeds = feed.Route
+	feed.Write = NewOracleWrite(config.Config().MainNet.ChainId, feed.Symbol)
+	return feed
+}
+
+// newTransactOpts 喂价交易参数，nonce、gas price 和 gas limit 自动获取
+func (s *TokenPrice) newTransactOpts(ctx context.Context, auth *bind.TransactOpts) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From:      auth.From,
+		Nonce:     nil,         // 自动获取 nonce
+		Signer:    auth.Signer, // 交易签名方法
+		Value:     big.NewInt(0),
+		GasPrice:  nil, // 自动估算 gas price
+		GasFeeCap: nil,
+		GasTipCap: nil,
+		GasLimit:  0, // 自动估算 gas limit
+		Context:   ctx,
+		NoSend:    s.DryRun, // true = 模拟交易, false = 实际发送
+	}
+}
+
+// setPrice 调用 BscPledgeOracle.setPrice(address, uint256) 写入一个代币的价格，并记录熔断器和 gas 消耗
+// 不记录写入结果，由调用方按单独写入或批量写入的回退分别记录
+func (s *TokenPrice) setPrice(oracle *bindings.BscPledgeOracleMainnetToken, auth *bind.TransactOpts, feed *oracleFeed) (*types.Transaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	tx, err := oracle.SetPrice(s.newTransactOpts(ctx, auth), common.HexToAddress(feed.Token), big.NewInt(feed.Price))
+	log.Logger.Sugar().Info("SavePlgrPrice ", feed.Symbol, " ", err)
+	if s.DryRun {
+		s.logDryRun(tx, feed.Price, err)
+		return tx, err
+	}
+	if err != nil {
+		telemetry.CaptureError(context.Background(), err, map[string]string{"symbol": feed.Symbol, "chain_id": config.Config().MainNet.ChainId})
+		feed.Breaker.RecordFailure(err)
+		return nil, err
+	}
+	feed.Breaker.RecordSuccess()
+	NewGasSpend().Record(config.Config().MainNet.ChainId, "oracle_set_price", tx)
+	return tx, nil
+}
+
+// setPrices 调用 BscPledgeOracle.setPrices(uint256[], uint256[]) 用一笔交易写入多个代币的价格
+//
+// 上链后逐个读回链上价格，与写入的一致时记录为 batched；交易发送失败、执行失败或读回的价格不一致的代币
+// 改用 setPrice 单独写入，成功时记录为 mismatch，Error 为批量写入失败的原因
+func (s *TokenPrice) setPrices(conn *ethclient.Client, oracle *bindings.BscPledgeOracleMainnetToken, auth *bind.TransactOpts, feeds []*oracleFeed) {
+	ctx, cancel := context.WithTimeout(context.Background(), oracleBatchTimeout)
+	defer cancel()
+
+	// setPrices 以 uint256 传入代币地址
+	assets := make([]*big.Int, len(feeds))
+	prices := make([]*big.Int, len(feeds))
+	for i, feed := range feeds {
+		assets[i] = new(big.Int).SetBytes(common.HexToAddress(feed.Token).Bytes())
+		prices[i] = big.NewInt(feed.Price)
+	}
+
+	tx, err := oracle.SetPrices(s.newTransactOpts(ctx, auth), assets, prices)
+	log.Logger.Sugar().Info("SavePlgrPrice setPrices ", len(feeds), " ", err)
+	if s.DryRun {
+		for _, feed := range feeds {
+			s.logDryRun(tx, feed.Price, err)
+		}
+		return
+	}
+
+	txHash := ""
+	if err == nil {
+		txHash = tx.Hash().Hex()
+		NewGasSpend().Record(config.Config().MainNet.ChainId, "oracle_set_price", tx)
+		var receipt *types.Receipt
+		receipt, err = bind.WaitMined(ctx, conn, tx)
+		if err == nil && receipt.Status != types.ReceiptStatusSuccessful {
+			err = errors.New("setPrices tx " + txHash + " reverted")
+		}
+	}
+	if err != nil {
+		telemetry.CaptureError(context.Background(), err, map[string]string{"symbol": "setPrices", "chain_id": config.Config().MainNet.ChainId})
+		log.Logger.Sugar().Error("SavePlgrPrice setPrices err ", err)
+	}
+
+	for _, feed := range feeds {
+		feedErr := err
+		if feedErr == nil {
+			var onChain *big.Int
+			onChain, feedErr = oracle.GetPrice(&bind.CallOpts{Context: ctx}, common.HexToAddress(feed.Token))
+			if feedErr == nil && onChain.Cmp(big.NewInt(feed.Price)) == 0 {
+				feed.Breaker.RecordSuccess()
+				feed.Write.RecordResult(models.OracleWriteResultBatched, txHash, nil)
+				continue
+			}
+			if feedErr == nil {
+				feedErr = fmt.Errorf("setPrices tx %s: on-chain price %s, want %d", txHash, onChain, feed.Price)
+			}
+		}
+
+		// 单独写入，写入失败时计入该代币的熔断器
+		log.Logger.Sugar().Info("SavePlgrPrice setPrices fallback to setPrice ", feed.Symbol, " ", feedErr)
+		single, singleErr := s.setPrice(oracle, auth, feed)
+		if singleErr != nil {
+			feed.Write.RecordResult(models.OracleWriteResultFailed, txHash, fmt.Errorf("%v; setPrice: %w", feedErr, singleErr))
+			continue
+		}
+		feed.Write.RecordResult(models.OracleWriteResultMismatch, single.Hash().Hex(), feedErr)
+	}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"pledge-backend/telemetry"
+	"pledge-backend/utils"
+	"time"
+)
+
+const (
+	BreakerClosed = "closed"
+	BreakerOpen   = "open"
+)
+
+// OracleBreaker 喂价熔断器
+//
+// 以下情况拒绝写链并发送告警邮件:
+//   - 交易所价格超过 [oracle] stale_minutes 未更新（Redis 中的价格被冻结），使用换算路径时检查路径上的每个交易对
+//   - 连续 [oracle] max_failures 次 SetPrice 失败，冷却 cooldown_minutes 后允许一次试探写入
+type OracleBreaker struct {
+	Symbol string
+	Feeds  []string // 检查是否停滞的交易对，为空时只检查 Symbol；状态仍按 Symbol 保存
+}
+
+func NewOracleBreaker(symbol string) *OracleBreaker {
+	return &OracleBreaker{Symbol: symbol}
+}
+
+func (b *OracleBreaker) redisKey() string {
+	return "oracle_breaker:" + b.Symbol
+}
+
+// State 读取熔断器状态，不存在时为 closed
+func (b *OracleBreaker) State() models.OracleBreaker {
+	state := models.OracleBreaker{State: BreakerClosed}
+	stateBytes, err := db.RedisGet(b.redisKey())
+	if err == nil && len(stateBytes) > 0 {
+		_ = json.Unmarshal(stateBytes, &state)
+	}
+	return state
+}
+
+// Allow 判断当前是否允许写链，不允许时熔断并告警
+func (b *OracleBreaker) Allow() bool {
+	state := b.State()
+	now := time.Now().Unix()
+
+	reason := ""
+	if feed, stale := b.staleFeed(now); stale {
+		reason = fmt.Sprintf("exchange feed %s not updated for %d minutes", feed, config.Config().Oracle.StaleMinutes)
+	} else if config.Config().Oracle.MaxFailures > 0 && state.Failures >= config.Config().Oracle.MaxFailures &&
+		now-state.LastFailure < config.Config().Oracle.CooldownMinutes*60 {
+		reason = fmt.Sprintf("%d consecutive SetPrice failures", state.Failures)
+	}
+
+	if reason == "" {
+		if state.State == BreakerOpen {
+			// 行情恢复或冷却结束，进入试探写入
+			state.State = BreakerClosed
+			state.Reason = ""
+			b.save(state)
+		}
+		return true
+	}
+
+	b.trip(state, reason)
+	return false
+}
+
+// staleFeed 第一个超过 [oracle] stale_minutes 未更新的交易对
+func (b *OracleBreaker) staleFeed(now int64) (string, bool) {
+	feeds := b.Feeds
+	if len(feeds) == 0 {
+		feeds = []string{b.Symbol}
+	}
+	for _, feed := range feeds {
+		updatedAt, err := db.RedisGetInt64("exchange_price_time:" + feed)
+		if err != nil || now-updatedAt > config.Config().Oracle.StaleMinutes*60 {
+			return feed, true
+		}
+	}
+	return "", false
+}
+
+// RecordSuccess 写链成功，清零失败计数
+func (b *OracleBreaker) RecordSuccess() {
+	b.save(models.OracleBreaker{State: BreakerClosed})
+}
+
+// RecordFailure 写链失败，连续失败次数达到上限时熔断
+func (b *OracleBreaker) RecordFailure(err error) {
+	state := b.State()
+	state.Failures++
+	state.LastFailure = time.Now().Unix()
+	if config.Config().Oracle.MaxFailures > 0 && state.Failures >= config.Config().Oracle.MaxFailures {
+		b.trip(state, fmt.Sprintf("%d consecutive SetPrice failures, last err: %v", state.Failures, err))
+		return
+	}
+	b.save(state)
+}
+
+// trip 熔断，仅在状态从 closed 变为 open 时发送告警，避免每次定时任务重复发送
+func (b *OracleBreaker) trip(state models.OracleBreaker, reason string) {
+	alert := state.State != BreakerOpen
+	state.State = BreakerOpen
+	state.Reason = reason
+	b.save(state)
+
+	log.Logger.Sugar().Error("oracle breaker open ", b.Symbol, " ", reason)
+	if alert {
+		telemetry.CaptureError(context.Background(), errors.New("oracle breaker open: "+reason), map[string]string{"symbol": b.Symbol})
+		body := fmt.Sprintf(`<p>Oracle price submission for <strong>%s</strong> is halted: %s</p>`, b.Symbol, reason)
+		if err := utils.SendEmail([]byte(body), 2); err != nil {
+			log.Logger.Error(err.Error())
+		}
+	}
+}
+
+func (b *OracleBreaker) save(state models.OracleBreaker) {
+	state.UpdatedAt = time.Now().Unix()
+	if err := db.RedisSet(b.redisKey(), state, 0); err != nil {
+		log.Logger.Error(err.Error())
+	}
+}
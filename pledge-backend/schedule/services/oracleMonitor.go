@@ -0,0 +1,158 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OracleMonitor 检查 SavePlgrPrice 写入的主网 Oracle 价格，发现写链静默失败
+//
+// Oracle 合约不记录更新时间，新鲜度取最近一次写链成功的时间: OracleWrite 记录的 PLGR-USDT 写入时间
+// 和 gas_spend 中最近一笔执行成功的 oracle_set_price 交易时间中较早的一个，发送后没有上链的交易不计入。
+// 价格变化不足 min_change_bps 时跳过写入，链上价格不变不代表喂价停止，因此不以价格变化判断
+type OracleMonitor struct {
+}
+
+func NewOracleMonitor() *OracleMonitor {
+	return &OracleMonitor{}
+}
+
+// Monitor 主网 Oracle 中的 PLGR 价格超过 [oracle] freshness_minutes 没有写链成功，
+// 或偏离交易所价格超过 max_divergence 时，按 [alert] 的冷却和升级规则告警
+// 测试网写入固定价格，不检查
+func (s *OracleMonitor) Monitor() {
+	if !config.Config().ChainEnabled(JobOracleMonitor, config.Config().MainNet.ChainId) {
+		return
+	}
+	chainId := config.Config().MainNet.ChainId
+	asset := config.Config().MainNet.PlgrAddress
+
+	err, price := NewTokenPrice().GetMainNetTokenPrice(asset)
+	if err != nil {
+		return
+	}
+
+	key := "oracle_freshness:" + chainId + ":" + strings.ToLower(asset)
+	state := s.state(key)
+	now := time.Now().Unix()
+	if writtenAt := s.writtenAt(chainId); writtenAt > 0 {
+		state.WrittenAt = writtenAt
+	} else if state.WrittenAt == 0 { // 还没有写链记录，从第一次检查开始计时
+		state.WrittenAt = now
+	}
+	state.Price = price
+
+	problems := s.problems(state, now)
+	if len(problems) == 0 {
+		if state.Consecutive > 0 {
+			s.resolve(key, chainId, asset, state)
+		}
+		state.Consecutive = 0
+		state.Level = models.AlertLevelNone
+		s.saveState(key, state)
+		return
+	}
+
+	state.Consecutive++
+	level := alertLevel(state.Consecutive)
+	if level <= state.Level && now-state.LastAlertAt < config.Config().Alert.CooldownMinutes*60 {
+		s.saveState(key, state)
+		return
+	}
+
+	text := fmt.Sprintf("Pledge oracle PLGR price on chain %s is %s: %s, for %d consecutive checks",
+		chainId, decimal.New(price, -8).String(), strings.Join(problems, "; "), state.Consecutive)
+	log.Logger.Sugar().Warn(text)
+	errs := sendAlert(level, key, []byte("<p>"+text+"</p>"), text)
+	if len(errs) > 0 {
+		log.Logger.Sugar().Error("oracle alert send err ", errs)
+	}
+
+	state.Level = level
+	state.LastAlertAt = now
+	s.saveState(key, state)
+	saveAlertHistory("oracle", chainId, asset, level, state.Consecutive, text, errs)
+}
+
+// problems 距上次写链成功的时长和相对交易所价格的偏离，超出阈值时返回问题描述
+func (s *OracleMonitor) problems(state models.OracleFreshness, now int64) []string {
+	problems := make([]string, 0)
+	conf := config.Config().Oracle
+	if conf.FreshnessMinutes > 0 && now-state.WrittenAt > conf.FreshnessMinutes*60 {
+		problems = append(problems, fmt.Sprintf("no successful write for %d minutes", (now-state.WrittenAt)/60))
+	}
+
+	if conf.MaxDivergence > 0 {
+		// 与喂价使用相同的定价路径，例如 PLGR-BTC * BTC-USDT
+		exchange := decimal.NewFromInt(1)
+		for _, symbol := range PlgrRoute() {
+			price, err := NewTokenPrice().LastExchangePrice(symbol)
+			if err != nil {
+				return problems
+			}
+			exchange = exchange.Mul(price)
+		}
+		if !exchange.IsPositive() {
+			return problems
+		}
+		divergence := decimal.New(state.Price, -8).Sub(exchange).Abs().Div(exchange)
+		if divergence.GreaterThan(decimal.NewFromFloat(conf.MaxDivergence)) {
+			problems = append(problems, fmt.Sprintf("%s%% away from exchange price %s", divergence.Shift(2).StringFixed(2), exchange.String()))
+		}
+	}
+	return problems
+}
+
+// resolve 问题消失，通知已告警的渠道
+func (s *OracleMonitor) resolve(key, chainId, asset string, state models.OracleFreshness) {
+	if state.Level == models.AlertLevelNone {
+		return
+	}
+	text := fmt.Sprintf("Pledge oracle PLGR price on chain %s recovered, now %s", chainId, decimal.New(state.Price, -8).String())
+	errs := sendResolve(state.Level, key, text)
+	if len(errs) > 0 {
+		log.Logger.Sugar().Error("oracle alert resolve send err ", errs)
+	}
+	saveAlertHistory("oracle", chainId, asset, models.AlertLevelNone, state.Consecutive, text, errs)
+}
+
+// state 读取监控状态
+func (s *OracleMonitor) state(key string) models.OracleFreshness {
+	state := models.OracleFreshness{}
+	stateBytes, err := db.RedisGet(key)
+	if err == nil && len(stateBytes) > 0 {
+		_ = json.Unmarshal(stateBytes, &state)
+	}
+	return state
+}
+
+// writtenAt PLGR 最近一次写链成功的时间，Unix 秒，没有记录时返回 0
+// OracleWrite 在交易发送后记录，gas_spend 在交易上链后确认；两者都有时取较早的，
+// 发送了但一直没有上链的交易不会让价格显得新鲜。task 启动时 Redis 被清空，只剩 gas_spend 的记录
+func (s *OracleMonitor) writtenAt(chainId string) int64 {
+	writtenAt := NewOracleWrite(chainId, "PLGR-USDT").State().LastWriteAt
+
+	spend := models.GasSpend{}
+	if err := models.NewGasSpend().LastSuccess(chainId, "oracle_set_price", &spend); err != nil {
+		return writtenAt
+	}
+	createdAt, err := time.ParseInLocation("2006-01-02 15:04:05", spend.CreatedAt, time.Local)
+	if err == nil && (writtenAt == 0 || createdAt.Unix() < writtenAt) {
+		writtenAt = createdAt.Unix()
+	}
+	return writtenAt
+}
+
+func (s *OracleMonitor) saveState(key string, state models.OracleFreshness) {
+	if err := db.RedisSet(key, state, 0); err != nil {
+		log.Logger.Error(err.Error())
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"pledge-backend/config"
+	"pledge-backend/contract/bindings"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	OracleWriteDecisionWrite = "write"
+	OracleWriteDecisionSkip  = "skip"
+)
+
+// OracleWrite 喂价写入前先读取链上价格，变化不足 [oracle] min_change_bps 时跳过本次 SetPrice，节省 gas
+//
+// 距上次写链成功超过 max_skip_minutes 时仍然写入，避免链上价格长时间不更新；
+// 链上价格读取失败时照常写入。每次决策记录在 Redis oracle_write:<chainId>:<symbol>，通过 /readyz 查看
+type OracleWrite struct {
+	ChainId string
+	Symbol  string
+}
+
+func NewOracleWrite(chainId, symbol string) *OracleWrite {
+	return &OracleWrite{ChainId: chainId, Symbol: symbol}
+}
+
+func (w *OracleWrite) redisKey() string {
+	return "oracle_write:" + w.ChainId + ":" + w.Symbol
+}
+
+// State 读取最近一次的写入决策
+func (w *OracleWrite) State() models.OracleWrite {
+	state := models.OracleWrite{}
+	stateBytes, err := db.RedisGet(w.redisKey())
+	if err == nil && len(stateBytes) > 0 {
+		_ = json.Unmarshal(stateBytes, &state)
+	}
+	return state
+}
+
+// ShouldWrite 读取 oracle 中 asset 的当前价格，判断是否需要写入 price，并记录决策
+func (w *OracleWrite) ShouldWrite(ctx context.Context, oracle *bindings.BscPledgeOracleMainnetToken, asset string, price int64) bool {
+	state := w.State()
+	state.Price = price
+	state.OnChainPrice = 0
+	state.DeltaBps = 0
+	now := time.Now().Unix()
+	conf := config.Config().Oracle
+
+	write := true
+	onChain, err := oracle.GetPrice(&bind.CallOpts{Context: ctx}, common.HexToAddress(asset))
+	switch {
+	case err != nil:
+		state.Reason = "read on-chain price err: " + err.Error()
+	case onChain.Sign() <= 0:
+		state.Reason = "no on-chain price"
+	default:
+		state.OnChainPrice = onChain.Int64()
+		state.DeltaBps = decimal.NewFromInt(price).Sub(decimal.NewFromBigInt(onChain, 0)).Abs().
+			Shift(4).Div(decimal.NewFromBigInt(onChain, 0)).IntPart()
+		switch {
+		case state.DeltaBps >= conf.MinChangeBps:
+			state.Reason = fmt.Sprintf("price changed %d bps", state.DeltaBps)
+		case conf.MaxSkipMinutes > 0 && now-state.LastWriteAt >= conf.MaxSkipMinutes*60:
+			state.Reason = fmt.Sprintf("no write for %d minutes", conf.MaxSkipMinutes)
+		default:
+			write = false
+			state.Reason = fmt.Sprintf("price changed %d bps, below %d bps", state.DeltaBps, conf.MinChangeBps)
+		}
+	}
+
+	if write {
+		state.Decision = OracleWriteDecisionWrite
+		state.Skips = 0
+	} else {
+		state.Decision = OracleWriteDecisionSkip
+		state.Skips++
+		state.TotalSkips++
+		log.Logger.Sugar().Info("SavePlgrPrice skip ", w.ChainId, " ", w.Symbol, " ", state.Reason)
+	}
+	state.UpdatedAt = now
+	w.save(state)
+	return write
+}
+
+// RecordResult 记录本次写入的结果，写链成功时同时作为下一次 max_skip_minutes 的起点
+func (w *OracleWrite) RecordResult(result, txHash string, err error) {
+	state := w.State()
+	state.Result, state.TxHash, state.Error = result, txHash, ""
+	if err != nil {
+		state.Error = err.Error()
+	}
+	if result != models.OracleWriteResultFailed {
+		state.LastWriteAt = time.Now().Unix()
+	}
+	w.save(state)
+}
+
+func (w *OracleWrite) save(state models.OracleWrite) {
+	if err := db.RedisSet(w.redisKey(), state, 0); err != nil {
+		log.Logger.Error(err.Error())
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// archiveStates 可以归档的池子状态，匹配中和执行中的池子还会在链上结算，超过 endTime 也不归档
+var archiveStates = []string{poolStateFinish, poolStateLiquidation, poolStateUndone}
+
+// PoolArchive 归档已结束的池子
+//
+// endTime 超过 [schedule] archive_grace_days 天、且已完成、清算或未成交的池子复制到 pool_archives，
+// 并标记 poolbases.archived_at，之后 UpdateAllPoolInfo 不再同步，接口默认不返回
+type PoolArchive struct{}
+
+func NewPoolArchive() *PoolArchive {
+	return &PoolArchive{}
+}
+
+// ArchivePools 归档所有启用的链上过了宽限期的池子
+func (s *PoolArchive) ArchivePools(ctx context.Context) {
+	before := s.before()
+	for _, chainId := range []string{config.Config().TestNet.ChainId, config.Config().MainNet.ChainId} {
+		if config.Config().ChainEnabled(JobArchivePools, chainId) {
+			s.archiveChain(ctx, chainId, before)
+		}
+	}
+}
+
+// RetryArchive 重试队列中归档失败的单个池子，池子已归档或不再满足条件时视为成功
+func (s *PoolArchive) RetryArchive(ctx context.Context, chainId, poolId string) error {
+	if !config.Config().ChainEnabled(JobArchivePools, chainId) {
+		return errChainDisabled
+	}
+	pool := models.PoolBase{}
+	err := models.NewPoolArchive().Candidate(ctx, chainId, utils.StringToInt(poolId), archiveStates, s.before(), &pool)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return models.NewPoolArchive().Archive(ctx, &pool)
+}
+
+// before endTime 早于该时间 (Unix 时间戳) 的池子过了宽限期
+func (s *PoolArchive) before() int64 {
+	return time.Now().Add(-time.Duration(config.Config().Schedule.ArchiveGraceDays) * 24 * time.Hour).Unix()
+}
+
+func (s *PoolArchive) archiveChain(ctx context.Context, chainId string, before int64) {
+	var pools []models.PoolBase
+	if err := models.NewPoolArchive().Candidates(ctx, chainId, archiveStates, before, &pools); err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+
+	for i := range pools {
+		if ctx.Err() != nil {
+			return
+		}
+		poolId := utils.IntToString(pools[i].PoolId)
+		if err := models.NewPoolArchive().Archive(ctx, &pools[i]); err != nil {
+			log.Logger.Sugar().Error("ArchivePool err ", chainId, " ", poolId, " ", err)
+			itemFailed(ctx, JobArchivePools, chainId, poolId, err)
+			continue
+		}
+		log.Logger.Sugar().Info("pool archived ", chainId, " ", poolId)
+		itemSucceeded(ctx, JobArchivePools, chainId, poolId)
+	}
+}
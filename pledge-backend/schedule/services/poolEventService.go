@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"pledge-backend/config"
+	"pledge-backend/contract/bindings"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	"pledge-backend/schedule/cluster"
+	"pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type PoolEvent struct{}
+
+func NewPoolEvent() *PoolEvent {
+	return &PoolEvent{}
+}
+
+// UpdatePoolEvents 索引 PledgePool 的 DepositLend / DepositBorrow 事件
+// 只索引 [testnet] / [mainnet] enabled 的网络，多实例部署时只索引分配给本实例的链
+func (s *PoolEvent) UpdatePoolEvents() {
+	if config.Config().ChainEnabled(JobUpdatePoolEvents, config.Config().TestNet.ChainId) && cluster.OwnsChain(JobUpdatePoolEvents, config.Config().TestNet.ChainId) {
+		s.IndexPoolEvents(config.Config().TestNet.PledgePoolToken, models.NewChainHealth().RpcUrl(config.Config().TestNet.ChainId), config.Config().TestNet.ChainId)
+	}
+
+	if config.Config().ChainEnabled(JobUpdatePoolEvents, config.Config().MainNet.ChainId) && cluster.OwnsChain(JobUpdatePoolEvents, config.Config().MainNet.ChainId) {
+		s.IndexPoolEvents(config.Config().MainNet.PledgePoolToken, models.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId), config.Config().MainNet.ChainId)
+	}
+}
+
+// IndexPoolEvents 从上次扫描到的区块之后开始，每次最多扫描 [indexer] batch_blocks 个区块，直到最新区块
+//
+// 距最新区块不足 confirmations 个区块的事件记为 pending，同时记录这些区块的哈希；
+// 每次扫描前先检查记录的哈希是否仍在主链上，发生分叉时删除分叉点之后的 pending 事件并重新扫描，
+// 依赖这些事件的推荐归属恢复为待校验，已缓存的排行榜重新计算。游标 (event_cursor) 只推进到已确认的区块
+//
+// DepositLend / DepositBorrow 事件不包含 pid，从交易 input (depositLend/depositBorrow(_pid, _stakeAmount)) 中解析，
+// 通过其他合约间接调用的存入无法解析 pid，跳过；读取交易或区块失败时放弃本批，游标不推进，下次重新扫描
+func (s *PoolEvent) IndexPoolEvents(contractAddress, network, chainId string) {
+	rpcClient, err := rpc.Dial(network)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	ethereumConn := ethclient.NewClient(rpcClient)
+	defer ethereumConn.Close()
+
+	pledgePoolToken, err := bindings.NewPledgePoolToken(common.HexToAddress(contractAddress), ethereumConn)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	poolAbi, err := abi.JSON(strings.NewReader(bindings.PledgePoolTokenABI))
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+
+	contract := strings.ToLower(contractAddress)
+	err, cursor := models.NewPoolEvent().GetCursor(chainId, contract)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	latest, err := ethereumConn.BlockNumber(context.Background())
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	if latest < config.Config().Indexer.Confirmations {
+		return
+	}
+	finalized := latest - config.Config().Indexer.Confirmations
+	if cursor == 0 {
+		// 第一次索引，start_block 为 0 时只索引之后的新事件
+		cursor = finalized
+		if config.Config().Indexer.StartBlock > 0 {
+			cursor = config.Config().Indexer.StartBlock - 1
+		}
+		err = models.NewPoolEvent().SaveEvents(chainId, contract, nil, nil, cursor)
+		if err != nil {
+			log.Logger.Error(err.Error())
+			return
+		}
+	}
+
+	scanned, err := s.checkReorg(rpcClient, chainId, contract, cursor)
+	if err != nil {
+		log.Logger.Sugar().Error("IndexPoolEvents check reorg err ", chainId, err)
+		return
+	}
+	batch := config.Config().Indexer.BatchBlocks
+	if batch == 0 {
+		batch = 5000
+	}
+
+	for scanned < latest {
+		from := scanned + 1
+		to := from + batch - 1
+		if to > latest {
+			to = latest
+		}
+		opts := &bind.FilterOpts{Start: from, End: &to, Context: context.Background()}
+
+		var events []models.PoolEvent
+		txPid := map[common.Hash]int{}
+		blockTimes := map[uint64]int64{}
+		lendIter, err := pledgePoolToken.FilterDepositLend(opts, nil, nil)
+		if err != nil {
+			log.Logger.Sugar().Error("FilterDepositLend err ", chainId, from, to, err)
+			return
+		}
+		for lendIter.Next() {
+			e := lendIter.Event
+			events, err = s.appendEvent(ethereumConn, poolAbi, txPid, blockTimes, events, chainId, models.PoolEventDepositLend, e.From, e.Token, e.Amount, e.Raw)
+			if err != nil {
+				// 不保存本批，游标不推进，下次从同一区块重新扫描
+				lendIter.Close()
+				log.Logger.Sugar().Error("IndexPoolEvents DepositLend err ", chainId, from, to, err)
+				return
+			}
+		}
+		lendIter.Close()
+		if err = lendIter.Error(); err != nil {
+			log.Logger.Sugar().Error("FilterDepositLend err ", chainId, from, to, err)
+			return
+		}
+
+		borrowIter, err := pledgePoolToken.FilterDepositBorrow(opts, nil, nil)
+		if err != nil {
+			log.Logger.Sugar().Error("FilterDepositBorrow err ", chainId, from, to, err)
+			return
+		}
+		for borrowIter.Next() {
+			e := borrowIter.Event
+			events, err = s.appendEvent(ethereumConn, poolAbi, txPid, blockTimes, events, chainId, models.PoolEventDepositBorrow, e.From, e.Token, e.Amount, e.Raw)
+			if err != nil {
+				borrowIter.Close()
+				log.Logger.Sugar().Error("IndexPoolEvents DepositBorrow err ", chainId, from, to, err)
+				return
+			}
+		}
+		borrowIter.Close()
+		if err = borrowIter.Error(); err != nil {
+			log.Logger.Sugar().Error("FilterDepositBorrow err ", chainId, from, to, err)
+			return
+		}
+
+		// 未确认的区块记录哈希: 有事件的区块使用日志中的哈希，另外记录本批最后一个区块
+		var blocks []models.IndexedBlock
+		if to > finalized {
+			hashes := map[uint64]string{}
+			for i := range events {
+				events[i].Pending = events[i].BlockNumber > finalized
+				if events[i].Pending {
+					hashes[events[i].BlockNumber] = events[i].BlockHash
+				}
+			}
+			if _, ok := hashes[to]; !ok {
+				hash, err := s.blockHash(rpcClient, to)
+				if err != nil {
+					log.Logger.Sugar().Error("IndexPoolEvents block hash err ", chainId, to, err)
+					return
+				}
+				hashes[to] = hash
+			}
+			for number, hash := range hashes {
+				blocks = append(blocks, models.IndexedBlock{BlockNumber: number, BlockHash: hash})
+			}
+		}
+		cursor = to
+		if cursor > finalized {
+			cursor = finalized
+		}
+
+		err = models.NewPoolEvent().SaveEvents(chainId, contract, events, blocks, cursor)
+		if err != nil {
+			log.Logger.Sugar().Error("SavePoolEvents err ", chainId, from, to, err)
+			return
+		}
+		log.Logger.Sugar().Info("IndexPoolEvents ", chainId, " ", from, "-", to, " ", len(events))
+		scanned = to
+	}
+
+	// 没有新区块时也把达到确认数的事件标记为已确认
+	if cursor < finalized {
+		err = models.NewPoolEvent().SaveEvents(chainId, contract, nil, nil, finalized)
+		if err != nil {
+			log.Logger.Error(err.Error())
+		}
+	}
+}
+
+// checkReorg 检查已扫描但未确认的区块是否仍在主链上，返回已扫描到的区块
+//
+// 分叉会改变分叉点之后所有区块的哈希，所以最高的区块哈希不变时之前的区块都不变；
+// 否则从哈希仍然一致的最高区块之后回滚，都不一致时回滚到游标之后
+func (s *PoolEvent) checkReorg(conn *rpc.Client, chainId, contract string, cursor uint64) (uint64, error) {
+	var blocks []models.IndexedBlock
+	if err := models.NewPoolEvent().IndexedBlocks(chainId, contract, &blocks); err != nil {
+		return 0, err
+	}
+	if len(blocks) == 0 {
+		return cursor, nil
+	}
+
+	fork := cursor + 1
+	for i, block := range blocks {
+		hash, err := s.blockHash(conn, block.BlockNumber)
+		if err != nil {
+			return 0, err
+		}
+		if hash == block.BlockHash {
+			if i == 0 {
+				return block.BlockNumber, nil
+			}
+			fork = block.BlockNumber + 1
+			break
+		}
+	}
+
+	removed, err := models.NewPoolEvent().Rollback(chainId, contract, fork)
+	if err != nil {
+		return 0, err
+	}
+	depth := blocks[0].BlockNumber - fork + 1
+	log.Logger.Sugar().Warn("IndexPoolEvents reorg ", chainId, " from block ", fork, " depth ", depth, " removed ", len(removed), " events")
+	if len(removed) > 0 {
+		// 排行榜等缓存的统计在这个时间之前计算的需要重新计算，池子统计和持仓直接查询 pool_events，不需要处理
+		err = db.RedisSetString(models.PoolEventsReorgKey+chainId, utils.Int64ToString(time.Now().Unix()), 0)
+		if err != nil {
+			log.Logger.Error(err.Error())
+		}
+	}
+	PublishOpsEvent(models.OpsEventReorg, chainId, map[string]interface{}{
+		"contract":   contract,
+		"fork_block": fork,
+		"depth":      depth,
+		"removed":    len(removed),
+	})
+	return fork - 1, nil
+}
+
+// blockHash 节点返回的区块哈希
+// 不使用 HeaderByNumber 计算的哈希，BSC 新增的区块头字段可能使本地计算的哈希与链上不一致
+func (s *PoolEvent) blockHash(conn *rpc.Client, number uint64) (string, error) {
+	var block struct {
+		Hash common.Hash `json:"hash"`
+	}
+	err := conn.CallContext(context.Background(), &block, "eth_getBlockByNumber", hexutil.EncodeUint64(number), false)
+	if err != nil {
+		return "", err
+	}
+	if block.Hash == (common.Hash{}) {
+		return "", ethereum.NotFound
+	}
+	return block.Hash.Hex(), nil
+}
+
+// appendEvent 解析事件的 pid 和区块时间后加入 events，无法解析 pid 的交易跳过
+// 读取交易或区块失败时返回错误，不缓存结果，由调用方放弃本批
+func (s *PoolEvent) appendEvent(conn *ethclient.Client, poolAbi abi.ABI, txPid map[common.Hash]int, blockTimes map[uint64]int64, events []models.PoolEvent,
+	chainId, event string, from, token common.Address, amount *big.Int, raw types.Log) ([]models.PoolEvent, error) {
+	pid, ok := txPid[raw.TxHash]
+	if !ok {
+		var err error
+		pid, err = s.txPoolId(conn, poolAbi, raw.TxHash)
+		if err != nil {
+			return events, err
+		}
+		txPid[raw.TxHash] = pid
+	}
+	if pid <= 0 {
+		log.Logger.Sugar().Info("IndexPoolEvents skip tx without pid ", raw.TxHash.Hex())
+		return events, nil
+	}
+	blockTime, ok := blockTimes[raw.BlockNumber]
+	if !ok {
+		var err error
+		blockTime, err = s.blockTime(conn, raw.BlockNumber)
+		if err != nil {
+			return events, err
+		}
+		blockTimes[raw.BlockNumber] = blockTime
+	}
+	return append(events, models.PoolEvent{
+		ChainId:     chainId,
+		PoolId:      pid,
+		Event:       event,
+		Address:     from.Hex(),
+		Token:       token.Hex(),
+		Amount:      amount.String(),
+		BlockNumber: raw.BlockNumber,
+		BlockTime:   blockTime,
+		BlockHash:   raw.BlockHash.Hex(),
+		TxHash:      raw.TxHash.Hex(),
+		LogIndex:    raw.Index,
+	}), nil
+}
+
+// blockTime 区块时间 (Unix 秒)
+func (s *PoolEvent) blockTime(conn *ethclient.Client, number uint64) (int64, error) {
+	header, err := conn.HeaderByNumber(context.Background(), new(big.Int).SetUint64(number))
+	if err != nil {
+		return 0, err
+	}
+	return int64(header.Time), nil
+}
+
+// txPoolId 从交易 input 中解析 _pid，返回数据库中的 pool_id (合约索引 + 1)
+// 不是直接调用 depositLend / depositBorrow 的交易返回 0；读取交易失败时返回错误，不能当作无法解析
+func (s *PoolEvent) txPoolId(conn *ethclient.Client, poolAbi abi.ABI, txHash common.Hash) (int, error) {
+	tx, _, err := conn.TransactionByHash(context.Background(), txHash)
+	if err != nil {
+		return 0, err
+	}
+	input := tx.Data()
+	if len(input) < 4 {
+		return 0, nil
+	}
+	method, err := poolAbi.MethodById(input[:4])
+	if err != nil || (method.Name != "depositLend" && method.Name != "depositBorrow") {
+		return 0, nil
+	}
+	args, err := method.Inputs.Unpack(input[4:])
+	if err != nil || len(args) == 0 {
+		return 0, nil
+	}
+	pid, ok := args[0].(*big.Int)
+	if !ok {
+		return 0, nil
+	}
+	return int(pid.Int64()) + 1, nil
+}
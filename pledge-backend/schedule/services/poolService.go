@@ -8,7 +8,7 @@
  * 并将这些数据同步到 MySQL 数据库和 Redis 缓存中，供 API 服务对外提供查询。
  *
  * 【调用频率】
- * 由 gocron 定时任务调度器每 2 分钟调用一次 UpdateAllPoolInfo()
+ * 由定时任务调度器按 [jobs.UpdateAllPoolInfo] 调用 UpdateAllPoolInfo()，默认每 2 分钟
  *
  * 【与智能合约的关系】
  * - 调用 PledgePool.sol 的 poolLength() 获取池子总数
@@ -17,27 +17,43 @@
  * - 调用 PledgePool.sol 的 borrowFee() 和 lendFee() 获取手续费率
  *
  * 【数据流向】
- * Blockchain (PledgePool.sol) --> poolService --> MySQL (poolbases/pooldata表) + Redis
+ * Blockchain (PledgePool.sol) --> poolService --> MySQL (poolbases/pooldata/pool_snapshots表) + Redis + MQTT
  * ==================================================================================
  */
 
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"math/big"
 	"pledge-backend/config"
 	"pledge-backend/contract/bindings"
 	"pledge-backend/db"
 	"pledge-backend/log"
+	"pledge-backend/schedule/cluster"
 	"pledge-backend/schedule/models"
+	"pledge-backend/telemetry"
 	"pledge-backend/utils"
-	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
+// MqttPool MQTT 池子状态消息
+type MqttPool struct {
+	ChainId      string `json:"chain_id"`
+	PoolId       int    `json:"pool_id"`
+	State        string `json:"state"` // 0=MATCH, 1=EXECUTION, 2=FINISH, 3=LIQUIDATION, 4=UNDONE
+	LendSupply   string `json:"lend_supply"`
+	BorrowSupply string `json:"borrow_supply"`
+	SettleTime   string `json:"settle_time"`
+	EndTime      string `json:"end_time"`
+	Timestamp    int64  `json:"timestamp"`
+}
+
 // poolService - 借贷池同步服务结构体
 // 采用无状态设计，所有配置从 config 包读取
 type poolService struct{}
@@ -49,207 +65,365 @@ func NewPool() *poolService {
 
 // UpdateAllPoolInfo - 更新所有网络上的池子信息
 // 【入口函数】由定时任务调度器调用
-// 当前仅同步测试网 (TestNet)，主网代码已注释
-func (s *poolService) UpdateAllPoolInfo() {
+// 只同步 [testnet] / [mainnet] enabled 的网络，默认只同步测试网；多实例部署时只同步分配给本实例的链或池子
+// ctx 用于链路追踪，RPC、MySQL、Redis 调用记录为定时任务 span 的子 span
+func (s *poolService) UpdateAllPoolInfo(ctx context.Context) {
 	// 同步测试网 (BSC Testnet, chainId: 97) 的池子数据
-	s.UpdatePoolInfo(config.Config.TestNet.PledgePoolToken, config.Config.TestNet.NetUrl, config.Config.TestNet.ChainId)
+	if config.Config().ChainEnabled(JobUpdateAllPoolInfo, config.Config().TestNet.ChainId) && cluster.ServesPools(JobUpdateAllPoolInfo, config.Config().TestNet.ChainId) {
+		s.UpdatePoolInfo(ctx, config.Config().TestNet.PledgePoolToken, models.NewChainHealth().RpcUrl(config.Config().TestNet.ChainId), config.Config().TestNet.ChainId)
+	}
 
-	// 主网同步已禁用 (BSC Mainnet, chainId: 56)
-	// s.UpdatePoolInfo(config.Config.MainNet.PledgePoolToken, config.Config.MainNet.NetUrl, config.Config.MainNet.ChainId)
+	// 同步主网 (BSC Mainnet, chainId: 56) 的池子数据
+	if config.Config().ChainEnabled(JobUpdateAllPoolInfo, config.Config().MainNet.ChainId) && cluster.ServesPools(JobUpdateAllPoolInfo, config.Config().MainNet.ChainId) {
+		s.UpdatePoolInfo(ctx, config.Config().MainNet.PledgePoolToken, models.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId), config.Config().MainNet.ChainId)
+	}
+}
+
+// isDevnet chainId 是否为 [devnet] 本地开发链
+func (s *poolService) isDevnet(chainId string) bool {
+	return config.Config().Devnet.Enabled && chainId == config.Config().Devnet.ChainId
 }
 
 // UpdatePoolInfo - 同步指定网络上的所有借贷池信息
 // 【核心同步函数】
 //
 // 参数:
+//   - ctx: 链路追踪上下文
 //   - contractAddress: PledgePool 智能合约地址
 //   - network: RPC 节点 URL (如 https://bsc-testnet.public.blastapi.io)
 //   - chainId: 链 ID (97=测试网, 56=主网)
 //
 // 执行流程:
-//  1. 连接区块链 RPC 节点
-//  2. 实例化 PledgePool 合约绑定
-//  3. 读取全局费率 (borrowFee, lendFee)
-//  4. 遍历所有池子，读取并同步 poolBaseInfo 和 poolDataInfo
-func (s *poolService) UpdatePoolInfo(contractAddress, network, chainId string) {
+//  1. 连接区块链 RPC 节点，确定本轮读取的区块，实例化 PledgePool 合约绑定，读取全局费率 (dialPool)
+//     本轮所有合约调用都在同一个区块上执行，得到的池子数据是同一时刻的状态
+//  2. 获取池子总数，软删除超出总数的池子 (总数为 0 或本地开发链时跳过)
+//  3. 遍历所有池子，读取并同步 poolBaseInfo 和 poolDataInfo (syncPool)
+//     同步失败的池子放入重试队列，由 ProcessRetryQueue 单独重试，不必等待下一轮全量同步
+func (s *poolService) UpdatePoolInfo(ctx context.Context, contractAddress, network, chainId string) {
 
 	log.Logger.Sugar().Info("UpdatePoolInfo ", contractAddress+" "+network)
 
+	pool, err := s.dialPool(ctx, JobUpdateAllPoolInfo, contractAddress, network, chainId)
+	if err != nil {
+		return
+	}
+	defer pool.conn.Close()
+	log.Logger.Sugar().Info("UpdatePoolInfo ", chainId, " at block ", pool.block)
+
 	// ============================================================
-	// Step 1: 连接区块链 RPC 节点
+	// Step 4: 获取池子总数
+	// 对应 PledgePool.sol 中的 poolLength() 函数
 	// ============================================================
-	ethereumConn, err := ethclient.Dial(network)
+	pLength, err := pool.token.PledgePoolTokenCaller.PoolLength(pool.callOpts)
 	if nil != err {
 		log.Logger.Error(err.Error())
 		return
 	}
 
-	// ============================================================
-	// Step 2: 实例化 PledgePool 合约绑定对象
-	// bindings.NewPledgePoolToken 是由 abigen 工具根据 ABI 自动生成的
-	// ============================================================
-	pledgePoolToken, err := bindings.NewPledgePoolToken(common.HexToAddress(contractAddress), ethereumConn)
-	if nil != err {
+	// 池子总数变少 (例如合约重新部署) 时软删除多出的池子，接口不再返回
+	// poolLength 为 0 时多半是合约地址配错或节点返回异常，不据此删除全部池子；
+	// 本地开发链每次重启都会重新部署，池子总数变化是正常的，也不删除
+	if pLength.Sign() > 0 && !s.isDevnet(chainId) {
+		removed, err := models.NewPoolBase().MarkRemoved(ctx, chainId, int(pLength.Int64()))
+		if err != nil {
+			log.Logger.Error(err.Error())
+		} else if removed > 0 {
+			log.Logger.Sugar().Warn("pools removed from chain ", chainId, " ", removed)
+		}
+	}
+
+	// 已归档的池子不再同步
+	archived, err := models.NewPoolArchive().ArchivedPoolIds(ctx, chainId)
+	if err != nil {
 		log.Logger.Error(err.Error())
 		return
 	}
 
 	// ============================================================
-	// Step 3: 读取全局手续费率
-	// 对应 PledgePool.sol 中的 public 变量 borrowFee 和 lendFee
-	// 这些费率在池子结束时扣除，单位是 1e6 (如 250000 = 25%)
+	// Step 5: 遍历所有池子，同步数据
+	// 注意：合约中池子索引从 0 开始，但数据库中 pool_id 从 1 开始
+	// 多实例部署且 shard_by = "pool" 时只同步分配给本实例的池子
 	// ============================================================
-	borrowFee, err := pledgePoolToken.PledgePoolTokenCaller.BorrowFee(nil)
-	lendFee, err := pledgePoolToken.PledgePoolTokenCaller.LendFee(nil)
+	for i := 0; i <= int(pLength.Int64())-1; i++ {
+		if archived[i+1] || !cluster.OwnsPool(JobUpdateAllPoolInfo, chainId, i+1) {
+			continue
+		}
+		log.Logger.Sugar().Info("UpdatePoolInfo ", i)
+		poolId := utils.IntToString(i + 1) // 数据库中的 pool_id = 合约索引 + 1
+		if err = s.syncPool(ctx, pool, chainId, poolId); err != nil {
+			itemFailed(ctx, JobUpdateAllPoolInfo, chainId, poolId, err)
+		} else {
+			itemSucceeded(ctx, JobUpdateAllPoolInfo, chainId, poolId)
+		}
+	}
+}
+
+// RetryPool 重试队列中同步失败的单个池子
+func (s *poolService) RetryPool(ctx context.Context, chainId, poolId string) error {
+	var contractAddress, network string
+	switch {
+	case chainId == config.Config().TestNet.ChainId && config.Config().ChainEnabled(JobUpdateAllPoolInfo, chainId):
+		contractAddress, network = config.Config().TestNet.PledgePoolToken, models.NewChainHealth().RpcUrl(config.Config().TestNet.ChainId)
+	case chainId == config.Config().MainNet.ChainId && config.Config().ChainEnabled(JobUpdateAllPoolInfo, chainId):
+		contractAddress, network = config.Config().MainNet.PledgePoolToken, models.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId)
+	default:
+		return errChainDisabled
+	}
+
+	pool, err := s.dialPool(ctx, JobUpdateAllPoolInfo, contractAddress, network, chainId)
+	if err != nil {
+		return err
+	}
+	defer pool.conn.Close()
+	return s.syncPool(ctx, pool, chainId, poolId)
+}
 
+// pledgePool 已连接的 PledgePool 合约和全局手续费率
+type pledgePool struct {
+	conn      *ethclient.Client
+	token     *bindings.PledgePoolToken
+	block     uint64         // 本轮读取的区块
+	callOpts  *bind.CallOpts // BlockNumber 固定为 block
+	borrowFee *big.Int
+	lendFee   *big.Int
+}
+
+// dialPool 连接 RPC 节点，以最新区块之前第 read_lag 个区块作为本轮读取的区块，实例化 PledgePool 合约并读取全局手续费率，调用方负责关闭 conn
+// 读取的区块记录为任务 job 的 read_blocks
+func (s *poolService) dialPool(ctx context.Context, job, contractAddress, network, chainId string) (*pledgePool, error) {
 	// ============================================================
-	// Step 4: 获取池子总数
-	// 对应 PledgePool.sol 中的 poolLength() 函数
+	// Step 1: 连接区块链 RPC 节点
 	// ============================================================
-	pLength, err := pledgePoolToken.PledgePoolTokenCaller.PoolLength(nil)
+	ethereumConn, err := telemetry.DialEth(ctx, network)
 	if nil != err {
 		log.Logger.Error(err.Error())
-		return
+		return nil, err
 	}
+	callOpts, err := readOpts(ctx, ethereumConn, job, chainId)
+	if err != nil {
+		log.Logger.Sugar().Error("UpdatePoolInfo BlockNumber err ", chainId, err)
+		ethereumConn.Close()
+		return nil, err
+	}
+	pool := &pledgePool{conn: ethereumConn, block: callOpts.BlockNumber.Uint64(), callOpts: callOpts}
 
 	// ============================================================
-	// Step 5: 遍历所有池子，同步数据
-	// 注意：合约中池子索引从 0 开始，但数据库中 pool_id 从 1 开始
+	// Step 2: 实例化 PledgePool 智能合约绑定对象
+	// bindings.NewPledgePoolToken 是由 abigen 工具根据 ABI 自动生成的
 	// ============================================================
-	for i := 0; i <= int(pLength.Int64())-1; i++ {
+	pool.token, err = bindings.NewPledgePoolToken(common.HexToAddress(contractAddress), ethereumConn)
+	if nil != err {
+		log.Logger.Error(err.Error())
+		ethereumConn.Close()
+		return nil, err
+	}
 
-		log.Logger.Sugar().Info("UpdatePoolInfo ", i)
-		poolId := utils.IntToString(i + 1) // 数据库中的 pool_id = 合约索引 + 1
+	// ============================================================
+	// Step 3: 读取全局手续费率
+	// 对应 PledgePool.sol 中的 public 变量 borrowFee 和 lendFee
+	// 这些费率在池子结束时扣除，单位是 1e6 (如 250000 = 25%)
+	// ============================================================
+	pool.borrowFee, err = pool.token.PledgePoolTokenCaller.BorrowFee(pool.callOpts)
+	if err != nil {
+		log.Logger.Sugar().Error("UpdatePoolInfo BorrowFee err ", chainId, err)
+		ethereumConn.Close()
+		return nil, err
+	}
+	pool.lendFee, err = pool.token.PledgePoolTokenCaller.LendFee(pool.callOpts)
+	if err != nil {
+		log.Logger.Sugar().Error("UpdatePoolInfo LendFee err ", chainId, err)
+		ethereumConn.Close()
+		return nil, err
+	}
+	return pool, nil
+}
 
-		// ------------------------------------------------------------
-		// 5.1: 读取池子基础信息 (PoolBaseInfo)
-		// 对应 PledgePool.sol 中的 poolBaseInfo 数组
-		// 包含: settleTime, endTime, interestRate, maxSupply, state 等
-		// ------------------------------------------------------------
-		baseInfo, err := pledgePoolToken.PledgePoolTokenCaller.PoolBaseInfo(nil, big.NewInt(int64(i)))
+// syncPool 同步一个池子的 PoolBaseInfo、PoolDataInfo 并追加历史快照
+// 读取链上数据或写入 MySQL 失败时返回错误，PoolBase 和 PoolData 在同一个事务中保存；MQTT 推送和快照失败只记录日志
+func (s *poolService) syncPool(ctx context.Context, pool *pledgePool, chainId, poolId string) error {
+	poolBase, poolData, err := s.readPool(pool, chainId, poolId)
+	if err != nil {
+		return err
+	}
+
+	// ------------------------------------------------------------
+	// 5.6: 增量更新检测 - 逐字段比较上次保存的数据
+	// Redis 中缓存上次保存的 PoolBase、PoolData，都没有字段变化时不访问数据库；
+	// 否则在同一个事务中保存两者，各自与数据库中的记录比较，只更新变化的列
+	// ------------------------------------------------------------
+	baseKey := poolBaseCacheKey(chainId, poolId)
+	dataKey := poolDataCacheKey(chainId, poolId)
+	cachedBase := models.PoolBase{}
+	cachedData := models.PoolData{}
+	if s.cached(ctx, baseKey, &cachedBase) && len(poolBase.Changes(&cachedBase)) == 0 &&
+		s.cached(ctx, dataKey, &cachedData) && len(poolData.Changes(&cachedData)) == 0 {
+		s.appendSnapshot(chainId, poolId, pool.block, poolBase, poolData)
+		return nil
+	}
+
+	changed, err := models.NewPoolBase().SavePoolWithData(ctx, chainId, poolId, poolBase, poolData)
+	if err != nil {
+		// 事务回滚，不更新缓存，由重试队列重试
+		log.Logger.Sugar().Error("SavePoolWithData err ", chainId, poolId, err)
+		return err
+	}
+	// 更新 Redis 缓存，30 分钟后过期，之后与数据库重新比较一次
+	_ = db.RedisSetContext(ctx, baseKey, poolBase, 60*30)
+	_ = db.RedisSetContext(ctx, dataKey, poolData, 60*30)
+
+	// ------------------------------------------------------------
+	// 5.7: PoolBase 有变化时推送到 MQTT {topic_prefix}/{chainId}/pool/{poolId}
+	// ------------------------------------------------------------
+	if changed {
+		err = db.MqttPublish(db.MqttTopic(chainId, "pool", poolId), MqttPool{
+			ChainId:      chainId,
+			PoolId:       poolBase.PoolId,
+			State:        poolBase.State,
+			LendSupply:   poolBase.LendSupply,
+			BorrowSupply: poolBase.BorrowSupply,
+			SettleTime:   poolBase.SettleTime,
+			EndTime:      poolBase.EndTime,
+			Timestamp:    time.Now().Unix(),
+		})
 		if err != nil {
-			log.Logger.Sugar().Info("UpdatePoolInfo PoolBaseInfo err", poolId, err)
-			continue
+			log.Logger.Sugar().Error("PublishPool err ", chainId, poolId, err)
 		}
+	}
 
-		// ------------------------------------------------------------
-		// 5.2: 从数据库获取代币元信息 (Logo, Symbol, Price)
-		// 这些信息由 tokenPriceService 和 tokenSymbolService 维护
-		// ------------------------------------------------------------
-		_, borrowToken := models.NewTokenInfo().GetTokenInfo(baseInfo.BorrowToken.String(), chainId)
-		_, lendToken := models.NewTokenInfo().GetTokenInfo(baseInfo.LendToken.String(), chainId)
-
-		// ------------------------------------------------------------
-		// 5.3: 构造 JSON 格式的代币信息，供前端直接使用
-		// ------------------------------------------------------------
-		lendTokenJson, _ := json.Marshal(models.LendToken{
-			LendFee:    lendFee.String(),
-			TokenLogo:  lendToken.Logo,
-			TokenName:  lendToken.Symbol,
-			TokenPrice: lendToken.Price,
-		})
-		borrowTokenJson, _ := json.Marshal(models.BorrowToken{
-			BorrowFee:  borrowFee.String(),
-			TokenLogo:  borrowToken.Logo,
-			TokenName:  borrowToken.Symbol,
-			TokenPrice: borrowToken.Price,
-		})
+	s.appendSnapshot(chainId, poolId, pool.block, poolBase, poolData)
+	return nil
+}
 
-		// ------------------------------------------------------------
-		// 5.4: 组装 PoolBase 结构体
-		// 映射关系: 合约 PoolBaseInfo struct --> Go PoolBase struct --> MySQL poolbases 表
-		// ------------------------------------------------------------
-		poolBase := models.PoolBase{
-			SettleTime:             baseInfo.SettleTime.String(),             // 结算时间 (Unix 时间戳)
-			PoolId:                 utils.StringToInt(poolId),                // 池子 ID
-			ChainId:                chainId,                                  // 链 ID
-			EndTime:                baseInfo.EndTime.String(),                // 结束时间 (Unix 时间戳)
-			InterestRate:           baseInfo.InterestRate.String(),           // 固定利率 (1e8 精度)
-			MaxSupply:              baseInfo.MaxSupply.String(),              // 最大供给量 (wei)
-			LendSupply:             baseInfo.LendSupply.String(),             // 已存入的出借金额 (wei)
-			BorrowSupply:           baseInfo.BorrowSupply.String(),           // 已存入的抵押品金额 (wei)
-			MartgageRate:           baseInfo.MartgageRate.String(),           // 抵押率 (1e8 精度)
-			LendToken:              baseInfo.LendToken.String(),              // 出借代币地址
-			LendTokenSymbol:        lendToken.Symbol,                         // 出借代币符号 (如 BUSD)
-			LendTokenInfo:          string(lendTokenJson),                    // 出借代币详情 JSON
-			BorrowToken:            baseInfo.BorrowToken.String(),            // 抵押代币地址
-			BorrowTokenSymbol:      borrowToken.Symbol,                       // 抵押代币符号 (如 BTC)
-			BorrowTokenInfo:        string(borrowTokenJson),                  // 抵押代币详情 JSON
-			State:                  utils.IntToString(int(baseInfo.State)),   // 池子状态: 0=MATCH, 1=EXECUTION, 2=FINISH, 3=LIQUIDATION, 4=UNDONE
-			SpCoin:                 baseInfo.SpCoin.String(),                 // SP Token 地址 (出借人凭证)
-			JpCoin:                 baseInfo.JpCoin.String(),                 // JP Token 地址 (借款人凭证)
-			AutoLiquidateThreshold: baseInfo.AutoLiquidateThreshold.String(), // 自动清算阈值 (1e8 精度)
-		}
+// readPool 5.1-5.5: 在 pool.callOpts 的区块读取池子的 PoolBaseInfo、PoolDataInfo，转换为 PoolBase、PoolData
+// 代币符号、logo、价格来自 token_info
+func (s *poolService) readPool(pool *pledgePool, chainId, poolId string) (*models.PoolBase, *models.PoolData, error) {
+	i := utils.StringToInt(poolId) - 1 // 合约索引 = pool_id - 1
 
-		// ------------------------------------------------------------
-		// 5.5: 增量更新检测 - 使用 MD5 比较缓存数据
-		// 只有当数据发生变化时才写入数据库，减少不必要的 IO
-		// ------------------------------------------------------------
-		hasInfoData, byteBaseInfoStr, baseInfoMd5Str := s.GetPoolMd5(&poolBase, "base_info:pool_"+chainId+"_"+poolId)
-		if !hasInfoData || (baseInfoMd5Str != byteBaseInfoStr) {
-			// 数据有变化，写入 MySQL
-			err = models.NewPoolBase().SavePoolBase(chainId, poolId, &poolBase)
-			if err != nil {
-				log.Logger.Sugar().Error("SavePoolBase err ", chainId, poolId)
-			}
-			// 更新 Redis 缓存，设置 30 分钟过期时间防止 hash 碰撞
-			_ = db.RedisSet("base_info:pool_"+chainId+"_"+poolId, baseInfoMd5Str, 60*30)
-		}
+	// ------------------------------------------------------------
+	// 5.1: 读取池子基础信息 (PoolBaseInfo)
+	// 对应 PledgePool.sol 中的 poolBaseInfo 数组
+	// 包含: settleTime, endTime, interestRate, maxSupply, state 等
+	// ------------------------------------------------------------
+	baseInfo, err := pool.token.PledgePoolTokenCaller.PoolBaseInfo(pool.callOpts, big.NewInt(int64(i)))
+	if err != nil {
+		log.Logger.Sugar().Error("UpdatePoolInfo PoolBaseInfo err ", chainId, poolId, err)
+		return nil, nil, err
+	}
 
-		// ------------------------------------------------------------
-		// 5.6: 读取池子动态数据 (PoolDataInfo)
-		// 对应 PledgePool.sol 中的 poolDataInfo 数组
-		// 包含: 结算金额、清算金额、完成金额等运行时数据
-		// ------------------------------------------------------------
-		dataInfo, err := pledgePoolToken.PledgePoolTokenCaller.PoolDataInfo(nil, big.NewInt(int64(i)))
-		if err != nil {
-			log.Logger.Sugar().Info("UpdatePoolInfo PoolDataInfo err", poolId, err)
-			continue
-		}
+	// ------------------------------------------------------------
+	// 5.2: 从数据库获取代币元信息 (Logo, Symbol, Price)
+	// 这些信息由 tokenPriceService 和 tokenSymbolService 维护，按链整体缓存在进程内，写入时清除
+	// ------------------------------------------------------------
+	err, borrowToken := models.NewTokenInfo().GetCachedTokenInfo(baseInfo.BorrowToken.String(), chainId)
+	if err != nil {
+		log.Logger.Sugar().Error("UpdatePoolInfo GetTokenInfo err ", chainId, poolId, err)
+		return nil, nil, err
+	}
+	err, lendToken := models.NewTokenInfo().GetCachedTokenInfo(baseInfo.LendToken.String(), chainId)
+	if err != nil {
+		log.Logger.Sugar().Error("UpdatePoolInfo GetTokenInfo err ", chainId, poolId, err)
+		return nil, nil, err
+	}
 
-		// ------------------------------------------------------------
-		// 5.7: 增量更新 PoolData
-		// ------------------------------------------------------------
-		hasPoolData, byteDataInfoStr, dataInfoMd5Str := s.GetPoolMd5(&poolBase, "data_info:pool_"+chainId+"_"+poolId)
-		if !hasPoolData || (dataInfoMd5Str != byteDataInfoStr) {
-			poolData := models.PoolData{
-				PoolId:                 poolId,
-				ChainId:                chainId,
-				FinishAmountBorrow:     dataInfo.FinishAmountBorrow.String(),     // 正常结束时借款人可提取的抵押品
-				FinishAmountLend:       dataInfo.FinishAmountLend.String(),       // 正常结束时出借人可提取的本金+利息
-				LiquidationAmounBorrow: dataInfo.LiquidationAmounBorrow.String(), // 清算时借款人剩余抵押品
-				LiquidationAmounLend:   dataInfo.LiquidationAmounLend.String(),   // 清算时出借人可提取的金额
-				SettleAmountBorrow:     dataInfo.SettleAmountBorrow.String(),     // 结算时锁定的抵押品数量
-				SettleAmountLend:       dataInfo.SettleAmountLend.String(),       // 结算时锁定的出借金额
-			}
-			err = models.NewPoolData().SavePoolData(chainId, poolId, &poolData)
-			if err != nil {
-				log.Logger.Sugar().Error("SavePoolData err ", chainId, poolId)
-			}
-			_ = db.RedisSet("data_info:pool_"+chainId+"_"+poolId, dataInfoMd5Str, 60*30)
-		}
+	// ------------------------------------------------------------
+	// 5.3: 构造 JSON 格式的代币信息，供前端直接使用
+	// ------------------------------------------------------------
+	lendTokenJson, _ := json.Marshal(models.LendToken{
+		LendFee:    pool.lendFee.String(),
+		TokenLogo:  lendToken.Logo,
+		TokenName:  lendToken.Symbol,
+		TokenPrice: lendToken.Price,
+	})
+	borrowTokenJson, _ := json.Marshal(models.BorrowToken{
+		BorrowFee:  pool.borrowFee.String(),
+		TokenLogo:  borrowToken.Logo,
+		TokenName:  borrowToken.Symbol,
+		TokenPrice: borrowToken.Price,
+	})
+
+	// ------------------------------------------------------------
+	// 5.4: 组装 PoolBase 结构体
+	// 映射关系: 合约 PoolBaseInfo struct --> Go PoolBase struct --> MySQL poolbases 表
+	// ------------------------------------------------------------
+	poolBase := models.PoolBase{
+		SettleTime:             baseInfo.SettleTime.String(),             // 结算时间 (Unix 时间戳)
+		PoolId:                 utils.StringToInt(poolId),                // 池子 ID
+		ChainId:                chainId,                                  // 链 ID
+		EndTime:                baseInfo.EndTime.String(),                // 结束时间 (Unix 时间戳)
+		InterestRate:           baseInfo.InterestRate.String(),           // 固定利率 (1e8 精度)
+		MaxSupply:              baseInfo.MaxSupply.String(),              // 最大供给量 (wei)
+		LendSupply:             baseInfo.LendSupply.String(),             // 已存入的出借金额 (wei)
+		BorrowSupply:           baseInfo.BorrowSupply.String(),           // 已存入的抵押品金额 (wei)
+		MartgageRate:           baseInfo.MartgageRate.String(),           // 抵押率 (1e8 精度)
+		LendToken:              baseInfo.LendToken.String(),              // 出借代币地址
+		LendTokenSymbol:        lendToken.Symbol,                         // 出借代币符号 (如 BUSD)
+		LendTokenInfo:          string(lendTokenJson),                    // 出借代币详情 JSON
+		BorrowToken:            baseInfo.BorrowToken.String(),            // 抵押代币地址
+		BorrowTokenSymbol:      borrowToken.Symbol,                       // 抵押代币符号 (如 BTC)
+		BorrowTokenInfo:        string(borrowTokenJson),                  // 抵押代币详情 JSON
+		State:                  utils.IntToString(int(baseInfo.State)),   // 池子状态: 0=MATCH, 1=EXECUTION, 2=FINISH, 3=LIQUIDATION, 4=UNDONE
+		SpCoin:                 baseInfo.SpCoin.String(),                 // SP Token 地址 (出借人凭证)
+		JpCoin:                 baseInfo.JpCoin.String(),                 // JP Token 地址 (借款人凭证)
+		AutoLiquidateThreshold: baseInfo.AutoLiquidateThreshold.String(), // 自动清算阈值 (1e8 精度)
+	}
+
+	// ------------------------------------------------------------
+	// 5.5: 读取池子动态数据 (PoolDataInfo)
+	// 对应 PledgePool.sol 中的 poolDataInfo 数组
+	// 包含: 结算金额、清算金额、完成金额等运行时数据
+	// ------------------------------------------------------------
+	dataInfo, err := pool.token.PledgePoolTokenCaller.PoolDataInfo(pool.callOpts, big.NewInt(int64(i)))
+	if err != nil {
+		log.Logger.Sugar().Error("UpdatePoolInfo PoolDataInfo err ", chainId, poolId, err)
+		return nil, nil, err
+	}
+	poolData := models.PoolData{
+		PoolId:                 poolId,
+		ChainId:                chainId,
+		FinishAmountBorrow:     dataInfo.FinishAmountBorrow.String(),     // 正常结束时借款人可提取的抵押品
+		FinishAmountLend:       dataInfo.FinishAmountLend.String(),       // 正常结束时出借人可提取的本金+利息
+		LiquidationAmounBorrow: dataInfo.LiquidationAmounBorrow.String(), // 清算时借款人剩余抵押品
+		LiquidationAmounLend:   dataInfo.LiquidationAmounLend.String(),   // 清算时出借人可提取的金额
+		SettleAmountBorrow:     dataInfo.SettleAmountBorrow.String(),     // 结算时锁定的抵押品数量
+		SettleAmountLend:       dataInfo.SettleAmountLend.String(),       // 结算时锁定的出借金额
 	}
+
+	return &poolBase, &poolData, nil
 }
 
-// GetPoolMd5 - 计算池子数据的 MD5 哈希，用于增量更新检测
-//
-// 参数:
-//   - baseInfo: 池子基础信息结构体
-//   - key: Redis 缓存 Key
-//
-// 返回:
-//   - hasData: Redis 中是否已有缓存
-//   - cachedMd5: Redis 中缓存的 MD5 值
-//   - currentMd5: 当前数据的 MD5 值
-//
-// 原理: 比较 cachedMd5 和 currentMd5，如果不同则说明链上数据已更新
-func (s *poolService) GetPoolMd5(baseInfo *models.PoolBase, key string) (bool, string, string) {
-	baseInfoBytes, _ := json.Marshal(baseInfo)
-	baseInfoMd5Str := utils.Md5(string(baseInfoBytes))
-	resInfoBytes, _ := db.RedisGet(key)
-	if len(resInfoBytes) > 0 {
-		return true, strings.Trim(string(resInfoBytes), `"`), baseInfoMd5Str
-	} else {
-		return false, strings.Trim(string(resInfoBytes), `"`), baseInfoMd5Str
+// poolBaseCacheKey Redis 中上次保存的 PoolBase
+func poolBaseCacheKey(chainId, poolId string) string {
+	return "base_info:pool_" + chainId + "_" + poolId
+}
+
+// poolDataCacheKey Redis 中上次保存的 PoolData
+func poolDataCacheKey(chainId, poolId string) string {
+	return "data_info:pool_" + chainId + "_" + poolId
+}
+
+// appendSnapshot 5.8: 追加历史快照，失败只记录日志，不放入重试队列
+// 数据有变化时立即写入，没有变化时每小时补一条，供 /pool/:chainId/:poolId/history 绘图；block 为读取数据的区块
+func (s *poolService) appendSnapshot(chainId, poolId string, block uint64, poolBase *models.PoolBase, poolData *models.PoolData) {
+	err := models.NewPoolSnapshot().Append(&models.PoolSnapshot{
+		ChainId:            chainId,
+		PoolId:             utils.StringToInt(poolId),
+		State:              poolBase.State,
+		LendSupply:         poolBase.LendSupply,
+		BorrowSupply:       poolBase.BorrowSupply,
+		SettleAmountLend:   poolData.SettleAmountLend,
+		SettleAmountBorrow: poolData.SettleAmountBorrow,
+		BlockNumber:        block,
+	})
+	if err != nil {
+		log.Logger.Sugar().Error("AppendPoolSnapshot err ", chainId, poolId, err)
+	}
+}
+
+// cached 读取 Redis 中上次保存的池子数据，不存在或无法解析时返回 false
+func (s *poolService) cached(ctx context.Context, key string, v interface{}) bool {
+	cachedBytes, err := db.RedisGetContext(ctx, key)
+	if err != nil || len(cachedBytes) == 0 {
+		return false
 	}
+	return json.Unmarshal(cachedBytes, v) == nil
 }
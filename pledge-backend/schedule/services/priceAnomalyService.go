@@ -0,0 +1,151 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"pledge-backend/config"
+	"pledge-backend/db"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"strings"
+)
+
+// PriceAnomaly - 价格异常检测
+//
+// 新价格写入前与以下数据比较，任一项超出 [anomaly] 配置即视为异常:
+//   - 相对上一次价格的变动比例 (max_deviation)
+//   - 相对最近 history_size 条价格的 z-score (z_score)
+//   - 相对 Chainlink 价格的偏离比例 (max_source_deviation)
+//
+// 异常价格写入 price_quarantine 表等待管理员审核，不会更新 token_info
+type PriceAnomaly struct{}
+
+// NewPriceAnomaly - 工厂函数，创建 PriceAnomaly 实例
+func NewPriceAnomaly() *PriceAnomaly {
+	return &PriceAnomaly{}
+}
+
+func historyRedisKey(token, chainId string) string {
+	return "price_history:" + chainId + ":" + strings.ToLower(token)
+}
+
+// Check - 检测价格是否异常，正常价格记入历史
+// 返回 true 表示价格被隔离，调用方不应保存
+func (a *PriceAnomaly) Check(t models.TokenInfo, price int64) bool {
+	history := a.History(t.Token, t.ChainId)
+
+	reason := a.Detect(t.Token, t.ChainId, price, history)
+	if reason == "" {
+		a.Record(t.Token, t.ChainId, price)
+		return false
+	}
+
+	lastPrice := ""
+	if len(history) > 0 {
+		lastPrice = utils.Float64ToString(history[len(history)-1])
+	}
+	log.Logger.Sugar().Error("price quarantined ", t.Symbol, t.ChainId, price, reason)
+	err := a.Quarantine(t, utils.Int64ToString(price), lastPrice, reason)
+	if err != nil {
+		log.Logger.Error(err.Error())
+	}
+	return true
+}
+
+// Detect - 返回异常原因，正常返回空字符串
+func (a *PriceAnomaly) Detect(token, chainId string, price int64, history []float64) string {
+	conf := config.Config().Anomaly
+	p := float64(price)
+
+	if len(history) > 0 && conf.MaxDeviation > 0 {
+		last := history[len(history)-1]
+		if last > 0 && math.Abs(p-last)/last > conf.MaxDeviation {
+			return fmt.Sprintf("deviation from last price %.0f exceeds %.2f", last, conf.MaxDeviation)
+		}
+	}
+
+	if len(history) >= conf.MinSamples && conf.ZScore > 0 {
+		mean, std := meanStd(history)
+		if std > 0 && math.Abs(p-mean)/std > conf.ZScore {
+			return fmt.Sprintf("z-score %.2f exceeds %.2f", math.Abs(p-mean)/std, conf.ZScore)
+		}
+	}
+
+	if conf.MaxSourceDeviation > 0 {
+		chainlinkPrice, err := db.RedisGetString("chainlink_price:" + chainId + ":" + strings.ToLower(token))
+		if err == nil && chainlinkPrice != "" {
+			ref := utils.StringToFloat64(chainlinkPrice)
+			if ref > 0 && math.Abs(p-ref)/ref > conf.MaxSourceDeviation {
+				return fmt.Sprintf("deviation from chainlink price %.0f exceeds %.2f", ref, conf.MaxSourceDeviation)
+			}
+		}
+	}
+
+	return ""
+}
+
+// History - 最近的价格，按时间从旧到新
+func (a *PriceAnomaly) History(token, chainId string) []float64 {
+	values, err := db.RedisListLRange(historyRedisKey(token, chainId))
+	if err != nil {
+		return nil
+	}
+	history := make([]float64, 0, len(values))
+	for _, v := range values {
+		history = append(history, utils.StringToFloat64(v))
+	}
+	return history
+}
+
+// Record - 记录一条正常价格，只保留最近 history_size 条
+func (a *PriceAnomaly) Record(token, chainId string, price int64) {
+	key := historyRedisKey(token, chainId)
+	if err := db.RedisListRpush(key, utils.Int64ToString(price)); err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	_ = db.RedisListTrim(key, -config.Config().Anomaly.HistorySize, -1)
+}
+
+// Quarantine - 写入隔离表，同一代币只保留一条待审核记录
+func (a *PriceAnomaly) Quarantine(t models.TokenInfo, price, lastPrice, reason string) error {
+	nowDateTime := utils.GetCurDateTimeFormat()
+	quarantine := models.PriceQuarantine{}
+	err := db.Mysql.Table("price_quarantine").Where("token=? and chain_id=? and status=?", t.Token, t.ChainId, models.QuarantinePending).
+		Limit(1).Find(&quarantine).Debug().Error
+	if err != nil {
+		return err
+	}
+	if quarantine.Id > 0 {
+		return db.Mysql.Table("price_quarantine").Where("id=?", quarantine.Id).Updates(map[string]interface{}{
+			"price":      price,
+			"reason":     reason,
+			"updated_at": nowDateTime,
+		}).Debug().Error
+	}
+	return db.Mysql.Table("price_quarantine").Create(&models.PriceQuarantine{
+		Token:     t.Token,
+		Symbol:    t.Symbol,
+		ChainId:   t.ChainId,
+		Price:     price,
+		LastPrice: lastPrice,
+		Reason:    reason,
+		Status:    models.QuarantinePending,
+		CreatedAt: nowDateTime,
+		UpdatedAt: nowDateTime,
+	}).Debug().Error
+}
+
+func meanStd(values []float64) (float64, float64) {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"pledge-backend/config"
+	"pledge-backend/contract/bindings"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// backfillBatch 每读取这么多个采样区块保存一次检查点
+const backfillBatch = 20
+
+// oraclePriceReader 主网和测试网 BscPledgeOracle 绑定共有的 getPrice
+type oraclePriceReader interface {
+	GetPrice(opts *bind.CallOpts, asset common.Address) (*big.Int, error)
+}
+
+// PriceBackfill 回填 token_price_history 中价格服务上线之前的价格，避免价格图表从上线时才开始
+//
+// Oracle 合约没有价格事件，只能每隔 [backfill] step_blocks 个区块在历史区块上调用 getPrice，需要归档节点；
+// 与 UpdateContractPrice 一样只在价格变化时写入，price_at 为区块时间
+type PriceBackfill struct {
+	lastCall time.Time
+}
+
+func NewPriceBackfill() *PriceBackfill {
+	return &PriceBackfill{}
+}
+
+// BackfillPriceHistory 依次执行已提交的回填，超时中断后下次从检查点继续
+func (s *PriceBackfill) BackfillPriceHistory(ctx context.Context) {
+	var backfills []models.PriceBackfill
+	if err := models.NewPriceBackfill().Unfinished(&backfills); err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	for i := range backfills {
+		if ctx.Err() != nil {
+			return
+		}
+		if !config.Config().ChainEnabled(JobBackfillPriceHistory, backfills[i].ChainId) {
+			continue
+		}
+		itemCounted(ctx, s.Run(ctx, &backfills[i]))
+	}
+}
+
+// Run 从检查点回填到 to_block，ctx 结束时保存检查点后返回
+// 读取价格失败时标记为 failed，需要重新提交 (例如换成归档节点之后)
+func (s *PriceBackfill) Run(ctx context.Context, b *models.PriceBackfill) error {
+	err := s.run(ctx, b)
+	if errors.Is(err, models.ErrBackfillMoved) {
+		log.Logger.Sugar().Info("price backfill moved ", b.ChainId, " ", b.Token)
+		return nil
+	}
+	if err != nil && ctx.Err() == nil {
+		log.Logger.Sugar().Error("price backfill err ", b.ChainId, " ", b.Token, " ", b.NextBlock, " ", err)
+		if e := b.Fail(err.Error()); e != nil && !errors.Is(e, models.ErrBackfillMoved) {
+			log.Logger.Error(e.Error())
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *PriceBackfill) run(ctx context.Context, b *models.PriceBackfill) error {
+	var netUrl, oracleAddress string
+	switch b.ChainId {
+	case config.Config().TestNet.ChainId:
+		netUrl, oracleAddress = models.NewChainHealth().RpcUrl(config.Config().TestNet.ChainId), config.Config().TestNet.BscPledgeOracleToken
+	case config.Config().MainNet.ChainId:
+		netUrl, oracleAddress = models.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId), config.Config().MainNet.BscPledgeOracleToken
+	default:
+		return errors.New("unknown chain " + b.ChainId)
+	}
+
+	conn, err := ethclient.Dial(netUrl)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var oracle oraclePriceReader
+	if b.ChainId == config.Config().MainNet.ChainId {
+		oracle, err = bindings.NewBscPledgeOracleMainnetToken(common.HexToAddress(oracleAddress), conn)
+	} else {
+		oracle, err = bindings.NewBscPledgeOracleTestnetToken(common.HexToAddress(oracleAddress), conn)
+	}
+	if err != nil {
+		return err
+	}
+
+	if b.Status == models.BackfillPending {
+		toBlock, err := s.endBlock(ctx, conn, b)
+		if err != nil {
+			return err
+		}
+		if err = b.Start(toBlock); err != nil {
+			return err
+		}
+		log.Logger.Sugar().Info("price backfill start ", b.ChainId, " ", b.Token, " ", b.FromBlock, "-", toBlock)
+	}
+
+	step := config.Config().Backfill.StepBlocks
+	asset := common.HexToAddress(b.Token)
+	for b.NextBlock <= b.ToBlock {
+		next, lastPrice := b.NextBlock, b.LastPrice
+		var history []models.TokenPriceHistory
+		for i := 0; i < backfillBatch && next <= b.ToBlock; i++ {
+			if ctx.Err() != nil {
+				break
+			}
+			block := new(big.Int).SetUint64(next)
+			if err = s.wait(ctx); err != nil {
+				break
+			}
+			price, err := oracle.GetPrice(&bind.CallOpts{BlockNumber: block, Context: ctx}, asset)
+			if err != nil {
+				return err
+			}
+			// 价格为 0 表示当时还没有设置价格
+			if price.Sign() > 0 && price.String() != lastPrice {
+				if err = s.wait(ctx); err != nil {
+					break
+				}
+				header, err := conn.HeaderByNumber(ctx, block)
+				if err != nil {
+					return err
+				}
+				history = append(history, models.TokenPriceHistory{
+					ChainId:   b.ChainId,
+					Token:     b.Token,
+					Price:     price.String(),
+					PriceAt:   int64(header.Time),
+					CreatedAt: utils.GetCurDateTimeFormat(),
+				})
+				lastPrice = price.String()
+			}
+			next += step
+		}
+		if next == b.NextBlock {
+			return ctx.Err()
+		}
+		if err = b.Checkpoint(next, lastPrice, history); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+
+	if err = b.Finish(); err != nil {
+		return err
+	}
+	log.Logger.Sugar().Info("price backfill done ", b.ChainId, " ", b.Token, " ", b.Inserted)
+	return nil
+}
+
+// endBlock 回填的最后一个区块: 代币第一条价格历史之前的区块，没有历史时为最新区块
+// 在 from_block 和最新区块之间二分查找区块时间
+func (s *PriceBackfill) endBlock(ctx context.Context, conn *ethclient.Client, b *models.PriceBackfill) (uint64, error) {
+	if err := s.wait(ctx); err != nil {
+		return 0, err
+	}
+	latest, err := conn.BlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+	first, err := models.NewTokenPriceHistory().FirstPriceAt(b.ChainId, b.Token)
+	if err != nil || first == 0 {
+		return latest, err
+	}
+
+	low, high := b.FromBlock, latest+1
+	for low < high {
+		mid := low + (high-low)/2
+		if err = s.wait(ctx); err != nil {
+			return 0, err
+		}
+		header, err := conn.HeaderByNumber(ctx, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return 0, err
+		}
+		if int64(header.Time) < first {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	// low 为第一个不早于已有价格历史的区块，low = from_block 时没有需要回填的区块
+	return low - 1, nil
+}
+
+// wait 按 [backfill] rate_limit 限制 RPC 请求速率
+func (s *PriceBackfill) wait(ctx context.Context) error {
+	interval := time.Second / time.Duration(config.Config().Backfill.RateLimit)
+	if wait := interval - time.Since(s.lastCall); wait > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	s.lastCall = time.Now()
+	return nil
+}
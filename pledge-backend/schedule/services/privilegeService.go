@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"pledge-backend/config"
+	"pledge-backend/contract/bindings"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"pledge-backend/telemetry"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// poolWhitelistTTL 池子权限状态在 Redis 中的有效期，需大于 [jobs.SyncPrivileges] 的执行间隔
+const poolWhitelistTTL = 3600
+
+// Privilege 同步池子的链上权限状态
+//
+// PledgePool 本身没有按地址的借款白名单，claimLend / claimBorrow 时由 PledgePool 铸造 spCoin / jpCoin，
+// 两个 DebtToken 继承 AddressPrivileges，只有 minter 白名单中的地址可以铸造；PledgePool 不在白名单中或
+// globalPaused 时用户的交易会失败。这里按池子读取两个代币的 minter 列表和 globalPaused，
+// 写入 Redis pool_whitelist:<chainId>:<poolId>，供 api 在用户发送交易前检查
+type Privilege struct{}
+
+func NewPrivilege() *Privilege {
+	return &Privilege{}
+}
+
+// SyncPrivileges 同步所有启用的链上未归档池子的权限状态
+func (s *Privilege) SyncPrivileges(ctx context.Context) {
+	if config.Config().ChainEnabled(JobSyncPrivileges, config.Config().TestNet.ChainId) {
+		s.syncChain(ctx, config.Config().TestNet.ChainId, models.NewChainHealth().RpcUrl(config.Config().TestNet.ChainId), config.Config().TestNet.PledgePoolToken)
+	}
+	if config.Config().ChainEnabled(JobSyncPrivileges, config.Config().MainNet.ChainId) {
+		s.syncChain(ctx, config.Config().MainNet.ChainId, models.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId), config.Config().MainNet.PledgePoolToken)
+	}
+}
+
+func (s *Privilege) syncChain(ctx context.Context, chainId, netUrl, poolAddress string) {
+	var pools []models.PoolBase
+	states := []string{poolStateMatch, poolStateExecution, poolStateFinish, poolStateLiquidation, poolStateUndone}
+	if err := models.NewPoolBase().ListByStates(ctx, chainId, states, &pools); err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	if len(pools) == 0 {
+		return
+	}
+
+	conn, err := telemetry.DialEth(ctx, netUrl)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	defer conn.Close()
+	callOpts, err := readOpts(ctx, conn, JobSyncPrivileges, chainId)
+	if err != nil {
+		log.Logger.Sugar().Error("SyncPrivileges BlockNumber err ", chainId, " ", err)
+		return
+	}
+	pool, err := bindings.NewPledgePoolToken(common.HexToAddress(poolAddress), conn)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	paused, err := pool.GlobalPaused(callOpts)
+	if err != nil {
+		log.Logger.Sugar().Error("SyncPrivileges GlobalPaused err ", chainId, " ", err)
+		return
+	}
+
+	// 多个池子可能使用同一个 spCoin / jpCoin，每个代币每轮只读取一次
+	minters := map[string][]string{}
+	for i := range pools {
+		if ctx.Err() != nil {
+			return
+		}
+		whitelist := models.PoolWhitelist{
+			ChainId:   chainId,
+			PoolId:    pools[i].PoolId,
+			Pool:      poolAddress,
+			Paused:    paused,
+			Block:     callOpts.BlockNumber.Uint64(),
+			UpdatedAt: time.Now().Unix(),
+		}
+		whitelist.SpCoin, err = s.minters(conn, callOpts, pools[i].SpCoin, minters)
+		if err == nil {
+			whitelist.JpCoin, err = s.minters(conn, callOpts, pools[i].JpCoin, minters)
+		}
+		if err == nil {
+			err = whitelist.Save(poolWhitelistTTL)
+		}
+		itemCounted(ctx, err)
+		if err != nil {
+			log.Logger.Sugar().Error("SyncPrivileges err ", chainId, " ", pools[i].PoolId, " ", err)
+		}
+	}
+}
+
+// minters 读取 DebtToken 的 minter 列表，地址统一为小写
+func (s *Privilege) minters(conn *ethclient.Client, callOpts *bind.CallOpts, token string, cache map[string][]string) (models.DebtTokenMinters, error) {
+	token = strings.ToLower(token)
+	res := models.DebtTokenMinters{Address: token, Minters: make([]string, 0)}
+	if list, ok := cache[token]; ok {
+		res.Minters = list
+		return res, nil
+	}
+
+	debtToken, err := bindings.NewDebtToken(common.HexToAddress(token), conn)
+	if err != nil {
+		return res, err
+	}
+	length, err := debtToken.GetMinterLength(callOpts)
+	if err != nil {
+		return res, err
+	}
+	for i := int64(0); i < length.Int64(); i++ {
+		minter, err := debtToken.GetMinter(callOpts, big.NewInt(i))
+		if err != nil {
+			return res, err
+		}
+		res.Minters = append(res.Minters, strings.ToLower(minter.Hex()))
+	}
+	cache[token] = res.Minters
+	return res, nil
+}
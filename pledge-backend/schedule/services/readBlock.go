@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// readOpts 同步任务读取链上状态的 CallOpts，固定在最新区块之前第 read_lag 个区块，
+// 使接口返回的数据不建立在可能被分叉回滚的区块上；读取的区块记录到 Redis read_blocks:<chainId>
+func readOpts(ctx context.Context, conn *ethclient.Client, job, chainId string) (*bind.CallOpts, error) {
+	latest, err := conn.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	block := latest
+	if lag := config.Config().ReadLag(chainId); latest > lag {
+		block = latest - lag
+	}
+
+	read := models.ReadBlock{Block: block, Latest: latest, ReadAt: time.Now().Unix()}
+	if err = read.Save(chainId, job); err != nil {
+		log.Logger.Error(err.Error())
+	}
+	return &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(block)}, nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Referral 校验推荐归属
+//
+// api 提交归属时交易可能还没有被 UpdatePoolEvents 索引，这些归属记为 pending，
+// 之后在 pool_events 中找到交易时记录存入信息；存入地址就是推荐人，或超过 [referral] pending_hours 仍未找到时标记为 rejected
+type Referral struct{}
+
+func NewReferral() *Referral {
+	return &Referral{}
+}
+
+// VerifyReferrals 校验所有启用的链上等待校验的归属
+func (s *Referral) VerifyReferrals(ctx context.Context) {
+	if !config.Config().Referral.Enabled {
+		return
+	}
+	for _, chainId := range []string{config.Config().TestNet.ChainId, config.Config().MainNet.ChainId} {
+		if config.Config().ChainEnabled(JobVerifyReferrals, chainId) {
+			s.verifyChain(ctx, chainId)
+		}
+	}
+}
+
+func (s *Referral) verifyChain(ctx context.Context, chainId string) {
+	var attributions []models.ReferralAttribution
+	if err := models.NewReferralAttribution().Pending(ctx, chainId, &attributions); err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	expired := time.Now().Add(-time.Duration(config.Config().Referral.PendingHours) * time.Hour)
+	for i := range attributions {
+		if ctx.Err() != nil {
+			return
+		}
+		itemCounted(ctx, s.verify(&attributions[i], expired))
+	}
+}
+
+// verify 校验单个归属，交易未找到且提交时间早于 expired 时标记为 rejected
+func (s *Referral) verify(attribution *models.ReferralAttribution, expired time.Time) error {
+	event := models.NewPoolEvent()
+	err := event.FirstByTx(attribution.ChainId, attribution.TxHash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		createdAt, err := time.ParseInLocation("2006-01-02 15:04:05", attribution.CreatedAt, time.Local)
+		if err == nil && createdAt.Before(expired) {
+			return attribution.Reject("not_found")
+		}
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if event.Address == attribution.Referrer {
+		return attribution.Reject("self")
+	}
+	return attribution.Verify(event)
+}
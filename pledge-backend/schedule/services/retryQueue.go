@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"pledge-backend/telemetry"
+	"sync/atomic"
+	"time"
+)
+
+// retryBatch 每次最多重试的条目数
+const retryBatch = 100
+
+// errChainDisabled 条目所在的链已停用，不再重试
+var errChainDisabled = errors.New("chain is not enabled for the job")
+
+// JobProgress 一次任务执行处理成功和失败的条目数，由 runner 放入 ctx，结束后写入 job_runs
+type JobProgress struct {
+	Processed int64
+	Failed    int64
+}
+
+type jobProgressKey struct{}
+
+// WithJobProgress 返回携带执行进度的 ctx
+func WithJobProgress(ctx context.Context) (context.Context, *JobProgress) {
+	progress := &JobProgress{}
+	return context.WithValue(ctx, jobProgressKey{}, progress), progress
+}
+
+func jobProgress(ctx context.Context) *JobProgress {
+	progress, _ := ctx.Value(jobProgressKey{}).(*JobProgress)
+	return progress
+}
+
+// itemSucceeded 条目处理成功，之前失败留在重试队列中的记录一并删除
+func itemSucceeded(ctx context.Context, job, chainId, item string) {
+	if progress := jobProgress(ctx); progress != nil {
+		atomic.AddInt64(&progress.Processed, 1)
+	}
+	if err := models.NewJobRetry().Remove(job, chainId, item); err != nil {
+		log.Logger.Error(err.Error())
+	}
+}
+
+// itemFailed 条目处理失败，放入重试队列
+func itemFailed(ctx context.Context, job, chainId, item string, cause error) {
+	if progress := jobProgress(ctx); progress != nil {
+		atomic.AddInt64(&progress.Failed, 1)
+	}
+	if err := models.NewJobRetry().Enqueue(job, chainId, item, cause.Error()); err != nil {
+		log.Logger.Error(err.Error())
+	}
+}
+
+// itemCounted 只计入执行进度，不进入重试队列，用于自行决定重试时机的任务，例如链上交易和通知
+func itemCounted(ctx context.Context, err error) {
+	progress := jobProgress(ctx)
+	if progress == nil {
+		return
+	}
+	if err != nil {
+		atomic.AddInt64(&progress.Failed, 1)
+	} else {
+		atomic.AddInt64(&progress.Processed, 1)
+	}
+}
+
+// RetryQueue 重试定时任务执行中处理失败的条目
+type RetryQueue struct{}
+
+func NewRetryQueue() *RetryQueue {
+	return &RetryQueue{}
+}
+
+// ProcessRetryQueue 重试到期的条目: 成功后移出队列；失败后按 1, 2, 4 ... 分钟 (最长 1 小时) 退避，
+// 重试 [schedule] retry_max_attempts 次仍然失败时标记为 dead 并上报到 Sentry
+func (s *RetryQueue) ProcessRetryQueue(ctx context.Context) {
+	var retries []models.JobRetry
+	if err := models.NewJobRetry().Due(retryBatch, &retries); err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+
+	for _, retry := range retries {
+		if ctx.Err() != nil {
+			return
+		}
+		handler := s.handler(retry.Job)
+		if handler == nil {
+			s.failed(ctx, retry, errors.New("no retry handler for job "+retry.Job), true)
+			continue
+		}
+
+		err := handler(ctx, retry.ChainId, retry.Item)
+		if errors.Is(err, errChainDisabled) {
+			if err = models.NewJobRetry().Remove(retry.Job, retry.ChainId, retry.Item); err != nil {
+				log.Logger.Error(err.Error())
+			}
+			continue
+		}
+		if err != nil {
+			s.failed(ctx, retry, err, false)
+			continue
+		}
+		itemSucceeded(ctx, retry.Job, retry.ChainId, retry.Item)
+		log.Logger.Sugar().Info("retry succeeded ", retry.Job, " ", retry.ChainId, " ", retry.Item)
+	}
+}
+
+// handler 定时任务重试单个条目的函数，任务不支持重试时返回 nil
+func (s *RetryQueue) handler(job string) func(ctx context.Context, chainId, item string) error {
+	switch job {
+	case JobUpdateAllPoolInfo:
+		return NewPool().RetryPool
+	case JobArchivePools:
+		return NewPoolArchive().RetryArchive
+	}
+	return nil
+}
+
+// failed 记录失败的重试，超过次数或 dead 为 true 时放弃
+func (s *RetryQueue) failed(ctx context.Context, retry models.JobRetry, cause error, dead bool) {
+	if progress := jobProgress(ctx); progress != nil {
+		atomic.AddInt64(&progress.Failed, 1)
+	}
+	attempts := retry.Attempts + 1
+	status := models.JobRetryPending
+	if dead || attempts >= config.Config().Schedule.RetryMaxAttempts {
+		status = models.JobRetryDead
+		log.Logger.Sugar().Error("retry gave up ", retry.Job, " ", retry.ChainId, " ", retry.Item, " ", cause)
+		telemetry.CaptureError(ctx, fmt.Errorf("retry gave up after %d attempts: %w", attempts, cause), map[string]string{
+			"job":      retry.Job,
+			"chain_id": retry.ChainId,
+			"item":     retry.Item,
+		})
+	}
+
+	backoff := time.Hour
+	if attempts <= 6 {
+		backoff = time.Minute << (attempts - 1)
+	}
+	err := models.NewJobRetry().Retried(retry.Id, attempts, status, cause.Error(), time.Now().Add(backoff))
+	if err != nil {
+		log.Logger.Error(err.Error())
+	}
+}
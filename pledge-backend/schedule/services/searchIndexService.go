@@ -0,0 +1,93 @@
+package services
+
+import (
+	"pledge-backend/db"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// searchTermMaxLength term 超出部分截断，前缀匹配不受影响
+const searchTermMaxLength = 64
+
+// SearchIndex - 代币搜索索引服务
+type SearchIndex struct{}
+
+// NewSearchIndex - 工厂函数，创建 SearchIndex 实例
+func NewSearchIndex() *SearchIndex {
+	return &SearchIndex{}
+}
+
+// UpdateSearchIndex - 按 token_info 重建 search_term 索引
+// 【定时任务】每 10 分钟执行一次
+//
+// 每条链在一个事务内先删后写，api 查询不会看到半成品索引
+func (s *SearchIndex) UpdateSearchIndex() {
+	var tokens []models.TokenInfo
+	err := db.Mysql.Table("token_info").Where("deleted_at is null").Find(&tokens).Debug().Error
+	if err != nil {
+		log.Logger.Sugar().Error("UpdateSearchIndex err ", err)
+		return
+	}
+
+	terms := map[string][]models.SearchTerm{}
+	for _, t := range tokens {
+		if t.Token == "" {
+			continue
+		}
+		for _, term := range SearchTerms(t.Token, t.Symbol, t.Name) {
+			terms[t.ChainId] = append(terms[t.ChainId], models.SearchTerm{
+				ChainId: t.ChainId,
+				Term:    term,
+				Token:   strings.ToLower(t.Token),
+			})
+		}
+	}
+
+	for chainId, chainTerms := range terms {
+		err = db.Mysql.Transaction(func(tx *gorm.DB) error {
+			err := tx.Table("search_term").Where("chain_id=?", chainId).Delete(&models.SearchTerm{}).Error
+			if err != nil {
+				return err
+			}
+			return tx.Table("search_term").CreateInBatches(chainTerms, 500).Error
+		})
+		if err != nil {
+			log.Logger.Sugar().Error("UpdateSearchIndex save err ", chainId, err)
+		}
+	}
+}
+
+// SearchTerms - 生成代币的搜索 term，全部小写
+//
+//   - symbol 的所有后缀: 搜索 "usd" 可以前缀匹配到 "busd" 的后缀 "usd"
+//   - name 中的每个单词
+//   - 地址，带和不带 0x 两种
+func SearchTerms(token, symbol, name string) []string {
+	set := map[string]bool{}
+	address := strings.ToLower(token)
+	set[address] = true
+	set[strings.TrimPrefix(address, "0x")] = true
+
+	runes := []rune(strings.ToLower(strings.TrimSpace(symbol)))
+	for i := range runes {
+		set[string(runes[i:])] = true
+	}
+	for _, word := range strings.Fields(strings.ToLower(name)) {
+		set[word] = true
+	}
+
+	terms := make([]string, 0, len(set))
+	for term := range set {
+		if term == "" {
+			continue
+		}
+		if len(term) > searchTermMaxLength {
+			term = term[:searchTermMaxLength]
+		}
+		terms = append(terms, term)
+	}
+	return terms
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer 链上写入交易的签名者，调用方只需要地址和 TransactOpts，不关心私钥的来源
+type Signer interface {
+	Address() common.Address
+	// TransactOpts 返回 chainId 上的交易参数，nonce、gas price、gas limit 为空时由合约绑定自动获取
+	TransactOpts(ctx context.Context, chainId *big.Int) (*bind.TransactOpts, error)
+}
+
+// keySigner 使用本地私钥签名
+type keySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKeySigner hexKey 为十六进制私钥，可以带 0x 前缀
+func NewKeySigner(hexKey string) (Signer, error) {
+	if hexKey == "" {
+		return nil, errors.New("signer private key is not set")
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimPrefix(hexKey, "0x"), "0X"))
+	if err != nil {
+		return nil, err
+	}
+	return &keySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}, nil
+}
+
+func (s *keySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *keySigner) TransactOpts(ctx context.Context, chainId *big.Int) (*bind.TransactOpts, error) {
+	opts, err := bind.NewKeyedTransactorWithChainID(s.key, chainId)
+	if err != nil {
+		return nil, err
+	}
+	opts.Value = big.NewInt(0)
+	opts.Context = ctx
+	return opts, nil
+}
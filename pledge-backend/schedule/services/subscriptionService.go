@@ -0,0 +1,160 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"pledge-backend/config"
+	"pledge-backend/i18n"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"pledge-backend/utils"
+	"strings"
+)
+
+// subscriptionEmailTemplate 池子事件通知邮件，文案见 i18n/notification.go，使用订阅时选择的语言
+var subscriptionEmailTemplate = template.Must(template.New("notify").Parse(`<p>{{.Title}}</p>
+<p>{{call .T "subscription.chain"}}: {{.ChainId}}<br>{{call .T "subscription.pool"}}: {{.PoolId}} ({{.LendToken}} / {{.BorrowToken}})<br>{{call .T "subscription.wallet"}}: {{.Address}}</p>
+<p>{{call .T (printf "subscription.%s.detail" .Event)}}</p>
+<p>{{call .T "subscription.footer" (call .T (printf "subscription.event.%s" .Event))}} <a href="{{.UnsubscribeUrl}}">{{call .T "subscription.unsubscribe"}}</a></p>`))
+
+// Subscription 钱包邮件订阅的事件通知
+//
+// 池子状态变化后，向订阅了该池子且已验证的邮箱发送一次通知，每个订阅的每个事件只发送一次:
+//   - settled: 池子进入执行、完成、清算或未成交状态
+//   - finished / liquidated: 池子进入完成 / 清算状态
+//   - claimable: 池子已完成或已清算，且该钱包在池子中有存入 (pool_events)
+//
+// 验证时已发生的事件不通知；发送失败的通知在下次执行时重试，最多 [subscription] max_attempts 次
+type Subscription struct{}
+
+func NewSubscription() *Subscription {
+	return &Subscription{}
+}
+
+// NotifySubscribers 检查所有启用的链
+func (s *Subscription) NotifySubscribers(ctx context.Context) {
+	if !config.Config().Subscription.Enabled {
+		return
+	}
+	for _, chainId := range []string{config.Config().TestNet.ChainId, config.Config().MainNet.ChainId} {
+		if config.Config().ChainEnabled(JobNotifySubscribers, chainId) {
+			s.notifyChain(ctx, chainId)
+		}
+	}
+}
+
+func (s *Subscription) notifyChain(ctx context.Context, chainId string) {
+	var subscriptions []models.EmailSubscription
+	if err := models.NewEmailSubscription().Verified(ctx, chainId, &subscriptions); err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	// 匹配中的池子还没有可通知的事件
+	var pools []models.PoolBase
+	states := []string{poolStateExecution, poolStateFinish, poolStateLiquidation, poolStateUndone}
+	if err := models.NewPoolBase().ListByStates(ctx, chainId, states, &pools); err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	poolMap := make(map[int]*models.PoolBase, len(pools))
+	for i := range pools {
+		poolMap[pools[i].PoolId] = &pools[i]
+	}
+
+	for i := range subscriptions {
+		subscription := &subscriptions[i]
+		pool, ok := poolMap[subscription.PoolId]
+		if !ok {
+			continue
+		}
+		for _, event := range subscription.EventList() {
+			if ctx.Err() != nil {
+				return
+			}
+			if !subscriptionEventReached(event, pool.State) || subscriptionEventReached(event, subscription.VerifiedState) {
+				continue
+			}
+			notification := models.NewEmailNotification()
+			found, err := notification.Get(subscription.Id, event)
+			if err != nil {
+				log.Logger.Error(err.Error())
+				return
+			}
+			if found && (notification.Status == models.EmailNotificationSent || notification.Attempts >= config.Config().Subscription.MaxAttempts) {
+				continue
+			}
+			if event == models.SubscriptionEventClaimable {
+				deposited, err := models.NewPoolEvent().HasDeposits(chainId, pool.PoolId, subscription.Address)
+				if err != nil {
+					log.Logger.Error(err.Error())
+					return
+				}
+				if !deposited {
+					continue
+				}
+			}
+			itemCounted(ctx, s.notify(subscription, pool, event, notification))
+		}
+	}
+}
+
+// notify 发送一封通知邮件并记录结果
+func (s *Subscription) notify(subscription *models.EmailSubscription, pool *models.PoolBase, event string, notification *models.EmailNotification) error {
+	lang, ok := i18n.Lookup(subscription.Language)
+	if !ok {
+		lang = i18n.Default()
+	}
+	title := i18n.T(lang, "subscription."+event+".title", pool.PoolId)
+	var body bytes.Buffer
+	err := subscriptionEmailTemplate.Execute(&body, map[string]interface{}{
+		"T":              func(key string, args ...interface{}) string { return i18n.T(lang, key, args...) },
+		"Title":          title,
+		"Event":          event,
+		"ChainId":        pool.ChainId,
+		"PoolId":         pool.PoolId,
+		"LendToken":      pool.LendTokenSymbol,
+		"BorrowToken":    pool.BorrowTokenSymbol,
+		"Address":        subscription.Address,
+		"UnsubscribeUrl": strings.TrimSuffix(config.Config().Subscription.LinkBaseUrl, "/") + "/subscription/unsubscribe?token=" + subscription.Token,
+	})
+	if err == nil {
+		err = utils.SendEmailTo([]string{subscription.Email}, title, body.Bytes())
+	}
+
+	notification.SubscriptionId = subscription.Id
+	notification.Event = event
+	notification.ChainId = subscription.ChainId
+	notification.PoolId = subscription.PoolId
+	notification.Attempts++
+	notification.Status = models.EmailNotificationSent
+	notification.Error = ""
+	if err != nil {
+		notification.Status = models.EmailNotificationFailed
+		notification.Error = err.Error()
+		log.Logger.Sugar().Errorf("subscription %d %s email failed: %v", subscription.Id, event, err)
+	}
+	if saveErr := notification.Save(); saveErr != nil {
+		log.Logger.Error(saveErr.Error())
+	}
+	return err
+}
+
+// subscriptionEventReached 池子处于 state 时事件是否已发生，未知状态 (例如验证时池子尚未同步) 视为都未发生
+func subscriptionEventReached(event, state string) bool {
+	switch event {
+	case models.SubscriptionEventSettled:
+		return state == poolStateExecution || state == poolStateFinish || state == poolStateLiquidation || state == poolStateUndone
+	case models.SubscriptionEventFinished:
+		return state == poolStateFinish
+	case models.SubscriptionEventLiquidated:
+		return state == poolStateLiquidation
+	case models.SubscriptionEventClaimable:
+		return state == poolStateFinish || state == poolStateLiquidation
+	}
+	return false
+}
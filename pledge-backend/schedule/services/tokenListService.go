@@ -0,0 +1,22 @@
+package services
+
+import (
+	apiServices "pledge-backend/api/services"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"pledge-backend/utils"
+)
+
+// RefreshTokenList 代币同步任务写入 token_info 后，为 enabled 的链生成新的 Token List 版本
+// 代币列表与最新版本相同时不写入；GET /token 只读取版本，不再在读取时生成
+func RefreshTokenList() {
+	for _, chainId := range config.Config().EnabledChainIds() {
+		refreshTokenList(chainId)
+	}
+}
+
+func refreshTokenList(chainId string) {
+	if err := apiServices.NewTokenList().Refresh(utils.StringToInt(chainId)); err != nil {
+		log.Logger.Sugar().Error("RefreshTokenList chain ", chainId, " err ", err)
+	}
+}
@@ -3,6 +3,12 @@ package services
 import (
 	"encoding/json"
 	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"pledge-backend/api/static"
 	"pledge-backend/config"
 	"pledge-backend/db"
 	"pledge-backend/log"
@@ -10,7 +16,9 @@ import (
 	"pledge-backend/utils"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"gorm.io/gorm"
 )
 
@@ -20,57 +28,245 @@ func NewTokenLogo() *TokenLogo {
 	return &TokenLogo{}
 }
 
+// Logo sources, recorded in token_info.logo_source so broken logos can be traced back
+const (
+	LogoSourceOverride    = "override"
+	LogoSourceTrustwallet = "trustwallet"
+	LogoSourceCoingecko   = "coingecko"
+	LogoSourceTokenList   = "token_list"
+	LogoSourceUpload      = "upload"
+)
+
+// logoMaxSize downloaded logos larger than this are rejected
+const logoMaxSize = 2 << 20
+
+// LogoResult resolved logo and where it came from
+type LogoResult struct {
+	Logo      string
+	Source    string
+	OriginUrl string
+}
+
+// UpdateTokenLogo Resolve logos for every token in token_info.
+// Sources by priority: admin upload, local override table, TrustWallet assets, CoinGecko, PancakeSwap token list.
+// Remote images are downloaded and served from /storage/img/tokens.
 func (s *TokenLogo) UpdateTokenLogo() {
 
-	// update remote logo
-	res, err := utils.HttpGet(config.Config.Token.LogoUrl, map[string]string{})
+	// local override table, tokens in it are always kept in token_info
+	overrides := map[string]models.TokenLogoOverride{}
+	var overrideList []models.TokenLogoOverride
+	err := db.Mysql.Table("token_logo_override").Find(&overrideList).Debug().Error
 	if err != nil {
-		log.Logger.Sugar().Info("UpdateTokenLogo HttpGet err", err)
-	} else {
-		tokenLogoRemote := models.TokenLogoRemote{}
-		err = json.Unmarshal(res, &tokenLogoRemote)
+		log.Logger.Sugar().Error("UpdateTokenLogo get override err ", err)
+	}
+	for _, o := range overrideList {
+		overrides[logoKey(o.ChainId, o.Token)] = o
+		err = s.CheckTokenInfo(o.Token, o.ChainId)
 		if err != nil {
-			log.Logger.Sugar().Error("UpdateTokenLogo json.Unmarshal err ", err)
-			return
+			log.Logger.Sugar().Error("UpdateTokenLogo CheckTokenInfo err ", err)
 		}
-		for _, t := range tokenLogoRemote.Tokens {
-
-			hasNewData, err := s.CheckLogoData(t.Address, utils.IntToString(t.ChainID), t.LogoURI, t.Symbol)
-			if err != nil {
-				log.Logger.Sugar().Error("UpdateTokenLogo CheckLogoData err ", err)
-				continue
-			}
+	}
 
-			if hasNewData {
-				err = s.SaveLogoData(t.Address, utils.IntToString(t.ChainID), t.LogoURI, t.Symbol, t.Decimals)
-				if err != nil {
-					log.Logger.Sugar().Error("UpdateTokenLogo SaveLogoData err ", err)
-					continue
-				}
-			}
+	// remote token list, tokens in it are inserted into token_info as well
+	tokenList := s.GetTokenList()
+	for _, t := range tokenList {
+		err = s.CheckTokenInfo(t.Address, utils.IntToString(t.ChainID))
+		if err != nil {
+			log.Logger.Sugar().Error("UpdateTokenLogo CheckTokenInfo err ", err)
 		}
 	}
 
-	//update local logo,Local logos have high weight,so execute later, local logos are divided by name
-	for _, v := range LocalTokenLogo {
-		for _, t := range v {
-			if t["token"] == "" {
-				continue
+	var tokens []models.TokenInfo
+	err = db.Mysql.Table("token_info").Where("deleted_at is null").Find(&tokens).Debug().Error
+	if err != nil {
+		log.Logger.Sugar().Error("UpdateTokenLogo get token_info err ", err)
+		return
+	}
+
+	for _, t := range tokens {
+		if t.Token == "" {
+			continue
+		}
+
+		symbol, decimals := t.Symbol, t.Decimals
+		if o, ok := overrides[logoKey(t.ChainId, t.Token)]; ok {
+			if o.Symbol != "" {
+				symbol = o.Symbol
+			}
+			if o.Decimals > 0 {
+				decimals = o.Decimals
 			}
-			hasNewData, err := s.CheckLogoData(t["token"], t["chain_id"], t["logo"], t["symbol"])
+		} else if l, ok := tokenList[logoKey(t.ChainId, t.Token)]; ok && symbol == "" {
+			symbol, decimals = l.Symbol, l.Decimals
+		}
+
+		err, result := s.ResolveLogo(t, overrides, tokenList)
+		if err != nil {
+			log.Logger.Sugar().Info("UpdateTokenLogo ResolveLogo ", t.ChainId, ":", t.Token, " ", err)
+			continue
+		}
+
+		hasNewData, err := s.CheckLogoData(t.Token, t.ChainId, result.Logo, symbol)
+		if err != nil {
+			log.Logger.Sugar().Error("UpdateTokenLogo CheckLogoData err ", err)
+			continue
+		}
+
+		if hasNewData || result.Source != t.LogoSource || result.OriginUrl != t.LogoOriginUrl {
+			err = s.SaveLogoData(t.Token, t.ChainId, result, symbol, decimals)
 			if err != nil {
+				log.Logger.Sugar().Error("UpdateTokenLogo SaveLogoData err ", err)
 				continue
 			}
+		}
+	}
+	RefreshTokenList()
+}
 
-			if hasNewData {
-				err = s.SaveLogoData(t["token"], t["chain_id"], t["logo"], t["symbol"], utils.StringToInt(t["decimals"]))
-				if err != nil {
-					log.Logger.Sugar().Error("UpdateTokenLogo SaveLogoData err ", err)
-					continue
-				}
-			}
+// GetTokenList Get the remote token list, keyed by chain id and lower case address
+func (s *TokenLogo) GetTokenList() map[string]models.Token {
+	tokenList := map[string]models.Token{}
+	res, err := utils.HttpGet(config.Config().Token.LogoUrl, map[string]string{})
+	if err != nil {
+		log.Logger.Sugar().Info("UpdateTokenLogo HttpGet err", err)
+		return tokenList
+	}
+	tokenLogoRemote := models.TokenLogoRemote{}
+	err = json.Unmarshal(res, &tokenLogoRemote)
+	if err != nil {
+		log.Logger.Sugar().Error("UpdateTokenLogo json.Unmarshal err ", err)
+		return tokenList
+	}
+	for _, t := range tokenLogoRemote.Tokens {
+		tokenList[logoKey(utils.IntToString(t.ChainID), t.Address)] = t
+	}
+	return tokenList
+}
+
+// ResolveLogo Resolve the logo of a token, the first source that yields a valid image wins
+func (s *TokenLogo) ResolveLogo(t models.TokenInfo, overrides map[string]models.TokenLogoOverride, tokenList map[string]models.Token) (error, LogoResult) {
+
+	// uploaded by an admin, never replaced automatically
+	if t.LogoSource == LogoSourceUpload && s.LogoCached(t.Logo) {
+		return nil, LogoResult{Logo: t.Logo, Source: t.LogoSource, OriginUrl: t.LogoOriginUrl}
+	}
+
+	// local override, relative paths point to files under /storage
+	if o, ok := overrides[logoKey(t.ChainId, t.Token)]; ok && o.Logo != "" {
+		if !strings.HasPrefix(o.Logo, "http://") && !strings.HasPrefix(o.Logo, "https://") {
+			return nil, LogoResult{Logo: GetBaseUrl() + strings.TrimPrefix(o.Logo, "/"), Source: LogoSourceOverride, OriginUrl: o.Logo}
+		}
+		logo, err := s.DownloadLogo(o.Logo, t.ChainId, t.Token)
+		if err == nil {
+			return nil, LogoResult{Logo: logo, Source: LogoSourceOverride, OriginUrl: o.Logo}
+		}
+		log.Logger.Sugar().Error("ResolveLogo override DownloadLogo err ", o.Logo, " ", err)
+	}
+
+	// already cached from a remote source, keep it
+	if t.LogoSource != "" && t.LogoSource != LogoSourceOverride && s.LogoCached(t.Logo) {
+		return nil, LogoResult{Logo: t.Logo, Source: t.LogoSource, OriginUrl: t.LogoOriginUrl}
+	}
+
+	candidates := make([]LogoResult, 0, 3)
+	if t.ChainId == "56" && common.HexToAddress(t.Token) != (common.Address{}) && config.Config().Token.TrustwalletUrl != "" {
+		candidates = append(candidates, LogoResult{
+			Source:    LogoSourceTrustwallet,
+			OriginUrl: config.Config().Token.TrustwalletUrl + common.HexToAddress(t.Token).Hex() + "/logo.png",
+		})
+	}
+	if t.CoingeckoId != "" || t.ChainId == "56" {
+		err, image := NewCoingecko().GetTokenImage(t.CoingeckoId, t.ChainId, t.Token)
+		if err == nil {
+			candidates = append(candidates, LogoResult{Source: LogoSourceCoingecko, OriginUrl: image})
+		}
+	}
+	if l, ok := tokenList[logoKey(t.ChainId, t.Token)]; ok && l.LogoURI != "" {
+		candidates = append(candidates, LogoResult{Source: LogoSourceTokenList, OriginUrl: l.LogoURI})
+	}
+
+	for _, c := range candidates {
+		logo, err := s.DownloadLogo(c.OriginUrl, t.ChainId, t.Token)
+		if err != nil {
+			log.Logger.Sugar().Info("ResolveLogo DownloadLogo ", c.Source, " ", c.OriginUrl, " ", err)
+			continue
 		}
+		c.Logo = logo
+		return nil, c
 	}
+
+	return errors.New("no logo source available"), LogoResult{}
+}
+
+// DownloadLogo Download a logo image into static/img/tokens and return its /storage url
+func (s *TokenLogo) DownloadLogo(originUrl, chainId, token string) (string, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(originUrl)
+	if err != nil {
+		return "", err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.New("unexpected status " + resp.Status)
+	}
+	ext, ok := logoExt[strings.ToLower(strings.Split(resp.Header.Get("Content-Type"), ";")[0])]
+	if !ok {
+		return "", errors.New("unexpected content type " + resp.Header.Get("Content-Type"))
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, logoMaxSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(body) == 0 || len(body) > logoMaxSize {
+		return "", errors.New("invalid image size " + utils.IntToString(len(body)))
+	}
+
+	dir := LogoDir()
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", err
+	}
+	fileName := chainId + "_" + strings.ToLower(token) + ext
+	tmpFile := path.Join(dir, fileName+".tmp")
+	err = ioutil.WriteFile(tmpFile, body, 0644)
+	if err != nil {
+		return "", err
+	}
+	err = os.Rename(tmpFile, path.Join(dir, fileName))
+	if err != nil {
+		return "", err
+	}
+	return GetBaseUrl() + "storage/img/tokens/" + fileName, nil
+}
+
+// LogoCached Whether the logo url points to a file that still exists in static/img/tokens
+func (s *TokenLogo) LogoCached(logo string) bool {
+	prefix := GetBaseUrl() + "storage/img/tokens/"
+	if !strings.HasPrefix(logo, prefix) {
+		return false
+	}
+	_, err := os.Stat(path.Join(LogoDir(), strings.TrimPrefix(logo, prefix)))
+	return err == nil
+}
+
+// LogoDir Directory for downloaded logos, served by the api under /storage/img/tokens
+func LogoDir() string {
+	return static.TokenLogoPath()
+}
+
+var logoExt = map[string]string{
+	"image/png":     ".png",
+	"image/jpeg":    ".jpg",
+	"image/jpg":     ".jpg",
+	"image/webp":    ".webp",
+	"image/svg+xml": ".svg",
+}
+
+func logoKey(chainId, token string) string {
+	return chainId + ":" + strings.ToLower(token)
 }
 
 // CheckLogoData Saving logo data to redis if it has new logo
@@ -131,6 +327,7 @@ func (s *TokenLogo) CheckTokenInfo(token, chainId string) error {
 			if err != nil {
 				return err
 			}
+			models.InvalidateTokenInfoCache(chainId)
 		} else {
 			return err
 		}
@@ -138,181 +335,35 @@ func (s *TokenLogo) CheckTokenInfo(token, chainId string) error {
 	return nil
 }
 
-// SaveLogoData Saving logo data to mysql if it has new logo
-func (s *TokenLogo) SaveLogoData(token, chainId, logoUrl, symbol string, decimals int) error {
+// SaveLogoData Saving logo data and its provenance to mysql if it has new logo
+func (s *TokenLogo) SaveLogoData(token, chainId string, logo LogoResult, symbol string, decimals int) error {
 	nowDateTime := utils.GetCurDateTimeFormat()
 
 	err := db.Mysql.Table("token_info").Where("token=? and chain_id=? ", token, chainId).Updates(map[string]interface{}{
-		"symbol":     symbol,
-		"logo":       logoUrl,
-		"decimals":   decimals,
-		"updated_at": nowDateTime,
+		"symbol":          symbol,
+		"logo":            logo.Logo,
+		"logo_source":     logo.Source,
+		"logo_origin_url": logo.OriginUrl,
+		"decimals":        decimals,
+		"updated_at":      nowDateTime,
 	}).Debug().Error
 	if err != nil {
 		log.Logger.Sugar().Error("UpdateTokenLogo SaveLogoData err ", err)
 		return err
 	}
+	models.InvalidateTokenInfoCache(chainId)
 
 	return nil
 }
 
 func GetBaseUrl() string {
 
-	domainName := config.Config.Env.DomainName
+	domainName := config.Config().Env.DomainName
 	domainNameSlice := strings.Split(domainName, "")
 	pattern := "\\d+" //反斜杠要转义
 	isNumber, _ := regexp.MatchString(pattern, domainNameSlice[0])
 	if isNumber {
-		return config.Config.Env.Protocol + "://" + config.Config.Env.DomainName + ":" + config.Config.Env.Port + "/"
+		return config.Config().Env.Protocol + "://" + config.Config().Env.DomainName + ":" + config.Config().Env.Port + "/"
 	}
-	return config.Config.Env.Protocol + "://" + config.Config.Env.DomainName + "/"
-}
-
-var BaseUrl = GetBaseUrl()
-
-var LocalTokenLogo = map[string]map[string]map[string]string{
-	"BNB": {
-		"test_net": {
-			"chain_id": "97",
-			"decimals": "18",
-			"token":    "0x0000000000000000000000000000000000000000",
-			"symbol":   "BNB",
-			"logo":     BaseUrl + "storage/img/BNB.png",
-		},
-		"main_net": {
-			"chain_id": "56",
-			"decimals": "18",
-			"token":    "0x0000000000000000000000000000000000000000",
-			"symbol":   "BNB",
-			"logo":     BaseUrl + "storage/img/BNB.png",
-		},
-	},
-	"BTC": {
-		"test_net": {
-			"chain_id": "97",
-			"decimals": "8",
-			"token":    "0xB5514a4FA9dDBb48C3DE215Bc9e52d9fCe2D8658",
-			"symbol":   "BTC",
-			"logo":     BaseUrl + "storage/img/BTC.png",
-		},
-		"main_net": {
-			"chain_id": "56",
-			"decimals": "8",
-			"token":    "0x7130d2A12B9BCbFAe4f2634d864A1Ee1Ce3Ead9c",
-			"symbol":   "BTC",
-			"logo":     BaseUrl + "storage/img/BTC.png",
-		},
-	},
-	"BTCB": {
-		"test_net": {
-			"chain_id": "97",
-			"decimals": "8",
-			"token":    "0xB5514a4FA9dDBb48C3DE215Bc9e52d9fCe2D8658",
-			"symbol":   "BTC",
-			"logo":     BaseUrl + "storage/img/BTC.png",
-		},
-		"main_net": {
-			"chain_id": "56",
-			"decimals": "8",
-			"token":    "0x7130d2A12B9BCbFAe4f2634d864A1Ee1Ce3Ead9c",
-			"symbol":   "BTC",
-			"logo":     BaseUrl + "storage/img/BTC.png",
-		},
-	},
-	"BUSD": {
-		"test_net": {
-			"chain_id": "97",
-			"decimals": "18",
-			"token":    "0xE676Dcd74f44023b95E0E2C6436C97991A7497DA",
-			"symbol":   "BUSD",
-			"logo":     BaseUrl + "storage/img/BUSD.png",
-		},
-		"main_net": {
-			"chain_id": "56",
-			"decimals": "18",
-			"token":    "0xe9e7CEA3DedcA5984780Bafc599bD69ADd087D56",
-			"symbol":   "BUSD",
-			"logo":     BaseUrl + "storage/img/BUSD.png",
-		},
-	},
-	"DAI": {
-		"test_net": {
-			"chain_id": "97",
-			"decimals": "18",
-			"token":    "0x490BC3FCc845d37C1686044Cd2d6589585DE9B8B",
-			"symbol":   "DAI",
-			"logo":     BaseUrl + "storage/img/DAI.png",
-		},
-		"main_net": {
-			"chain_id": "56",
-			"decimals": "18",
-			"token":    "0x1AF3F329e8BE154074D8769D1FFa4eE058B1DBc3",
-			"symbol":   "DAI",
-			"logo":     BaseUrl + "storage/img/DAI.png",
-		},
-	},
-	"ETH": {
-		"test_net": {
-			"chain_id": "97",
-			"decimals": "18",
-			"token":    "",
-			"symbol":   "ETH",
-			"logo":     BaseUrl + "storage/img/ETH.png",
-		},
-		"main_net": {
-			"chain_id": "56",
-			"decimals": "18",
-			"token":    "0x2170ed0880ac9a755fd29b2688956bd959f933f8",
-			"symbol":   "ETH",
-			"logo":     BaseUrl + "storage/img/ETH.png",
-		},
-	},
-	"USDT": {
-		"test_net": {
-			"chain_id": "97",
-			"decimals": "18",
-			"token":    "",
-			"symbol":   "USDT",
-			"logo":     BaseUrl + "storage/img/USDT.png",
-		},
-		"main_net": {
-			"chain_id": "56",
-			"decimals": "18",
-			"token":    "0x55d398326f99059ff775485246999027b3197955",
-			"symbol":   "USDT",
-			"logo":     BaseUrl + "storage/img/USDT.png",
-		},
-	},
-	"CAKE": {
-		"test_net": {
-			"chain_id": "97",
-			"decimals": "18",
-			"token":    "0xEAEd08168a2D34Ae2B9ea1c1f920E0BC00F9fA67",
-			"symbol":   "CAKE",
-			"logo":     BaseUrl + "storage/img/CAKE.png",
-		},
-		"main_net": {
-			"chain_id": "56",
-			"decimals": "18",
-			"token":    "0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82",
-			"symbol":   "CAKE",
-			"logo":     BaseUrl + "storage/img/CAKE.png",
-		},
-	},
-	"PLGR": {
-		"test_net": {
-			"chain_id": "97",
-			"decimals": "18",
-			"token":    "",
-			"symbol":   "PLGR",
-			"logo":     BaseUrl + "storage/img/PLGR.png",
-		},
-		"main_net": {
-			"chain_id": "56",
-			"decimals": "18",
-			"token":    "0x6Aa91CbfE045f9D154050226fCc830ddbA886CED",
-			"symbol":   "PLGR",
-			"logo":     BaseUrl + "storage/img/PLGR.png",
-		},
-	},
+	return config.Config().Env.Protocol + "://" + config.Config().Env.DomainName + "/"
 }
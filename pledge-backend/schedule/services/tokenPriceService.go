@@ -10,11 +10,12 @@
  *
  * 【调用频率】
  * - UpdateContractPrice(): 每 1 分钟调用一次（读取链上价格）
- * - SavePlgrPrice() / SavePlgrPriceTestNet(): 每 30 分钟调用一次（写入链上价格）
+ * - SaveAllPlgrPrice(): 每 30 分钟调用一次（写入链上价格，按 [testnet] / [mainnet] enabled 调用 SavePlgrPriceTestNet / SavePlgrPrice）
  *
  * 【与智能合约的关系】
  * - 读取: 调用 BscPledgeOracle.sol 的 getPrice(address) 获取代币价格
- * - 写入: 调用 BscPledgeOracle.sol 的 setPrice(address, uint256) 设置 PLGR 价格
+ * - 写入: 调用 BscPledgeOracle.sol 的 setPrice(address, uint256) 设置 PLGR 价格，
+ *         [oracle] feeds 配置多个代币时调用 setPrices(uint256[], uint256[]) 一笔交易写入
  *
  * 【数据流向】
  * 读取: BscPledgeOracle.sol --> tokenPriceService --> MySQL (token_info.price) + Redis
@@ -36,11 +37,14 @@ import (
 	"pledge-backend/log"
 	serviceCommon "pledge-backend/schedule/common"
 	"pledge-backend/schedule/models"
+	"pledge-backend/telemetry"
 	"pledge-backend/utils"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/shopspring/decimal"
@@ -48,7 +52,9 @@ import (
 )
 
 // TokenPrice - 代币价格服务结构体
-type TokenPrice struct{}
+type TokenPrice struct {
+	DryRun bool // 写入链上价格时只签名和估算 gas，不广播交易 (pledge set-price --dry-run)
+}
 
 // NewTokenPrice - 工厂函数，创建 TokenPrice 实例
 func NewTokenPrice() *TokenPrice {
@@ -59,16 +65,20 @@ func NewTokenPrice() *TokenPrice {
 // 【定时任务】每 1 分钟执行一次
 //
 // 执行流程:
-//  1. 从 MySQL token_info 表查询所有已注册的代币
-//  2. 遍历每个代币，调用 BscPledgeOracle.getPrice(tokenAddress) 获取链上价格
+//  1. 从 MySQL token_info 表查询所有已注册且未删除的代币
+//  2. 遍历每个代币，调用 BscPledgeOracle.getPrice(tokenAddress) 获取链上价格，
+//     配置在 [exchange.tokens] 中的代币改用交易所价格，
+//     都拿不到价格时回退到 CoinGecko（token_info.coingecko_id）；
+//     token_info.price_source 非空时只使用指定的来源
 //  3. 比较价格是否变化（通过 Redis 缓存）
 //  4. 如果价格有变化，更新 MySQL 和 Redis
 //
-// 注意: 主网代码已注释，当前仅同步测试网
+// 注意: 只读取 [testnet] / [mainnet] enabled 的网络的 Oracle，
+// 主网 PLGR 配置在 [exchange.tokens] 中，始终使用 KuCoin 价格 (Oracle 中的 PLGR 价格由 SavePlgrPrice 写入)
 func (s *TokenPrice) UpdateContractPrice() {
 	// Step 1: 从数据库获取所有已注册的代币列表
 	var tokens []models.TokenInfo
-	db.Mysql.Table("token_info").Find(&tokens)
+	db.Mysql.Table("token_info").Where("deleted_at is null").Find(&tokens)
 
 	// Step 2: 遍历每个代币
 	for _, t := range tokens {
@@ -81,20 +91,46 @@ func (s *TokenPrice) UpdateContractPrice() {
 			log.Logger.Sugar().Error("UpdateContractPrice token empty ", t.Symbol, t.ChainId)
 			continue
 		} else {
-			if t.ChainId == config.Config.TestNet.ChainId {
+			route, isExchangeToken := config.Config().Exchange.TokenRoute(t.Token)
+			if t.PriceSource == models.PriceSourceCoingecko {
+				// 管理员指定 CoinGecko 定价
+				err, price = NewCoingecko().GetTokenPrice(t.CoingeckoId)
+			} else if t.PriceSource == models.PriceSourceExchange || (t.PriceSource == models.PriceSourceAuto && isExchangeToken) {
+				// 交易所定价的代币: 直接使用 KuCoin 价格（由 kucoin.GetExchangePrice 写入 Redis）
+				if !isExchangeToken {
+					err = errors.New("exchange symbol not configured " + t.Token)
+				} else {
+					err, price = s.GetExchangeTokenPrice(route)
+				}
+			} else if t.ChainId == config.Config().TestNet.ChainId && config.Config().ChainEnabled(JobUpdateContractPrice, t.ChainId) {
 				// 测试网: 调用 BscPledgeOracle (TestNet) 获取价格
 				err, price = s.GetTestNetTokenPrice(t.Token)
-			} else if t.ChainId == "56" {
-				// 主网: 已禁用
-				// 注释掉的代码展示了主网的特殊处理逻辑:
-				// 对于 PLGR 代币，价格从 KuCoin 交易所获取（存储在 Redis）
-				// 其他代币从 Oracle 合约获取
+			} else if t.ChainId == config.Config().MainNet.ChainId && config.Config().ChainEnabled(JobUpdateContractPrice, t.ChainId) {
+				// 主网: 调用 BscPledgeOracle (MainNet) 获取价格
+				err, price = s.GetMainNetTokenPrice(t.Token)
+			}
+
+			// 自动选择时，Oracle 和交易所都没有可用价格则回退到 CoinGecko
+			if (err != nil || price <= 0) && t.PriceSource == models.PriceSourceAuto && t.CoingeckoId != "" {
+				log.Logger.Sugar().Info("UpdateContractPrice fallback to coingecko ", t.Symbol, t.ChainId, err)
+				err, price = NewCoingecko().GetTokenPrice(t.CoingeckoId)
 			}
 
 			if err != nil {
 				log.Logger.Sugar().Error("UpdateContractPrice err ", t.Symbol, t.ChainId, err)
 				continue
 			}
+
+			// 所有来源都拿不到价格时保留原价格，不写入 0
+			if price <= 0 {
+				log.Logger.Sugar().Error("UpdateContractPrice price empty ", t.Symbol, t.ChainId)
+				continue
+			}
+		}
+
+		// Step 3.1: 异常检测，异常价格进入隔离表等待审核
+		if NewPriceAnomaly().Check(t, price) {
+			continue
 		}
 
 		// Step 4: 检查价格是否有变化
@@ -111,10 +147,72 @@ func (s *TokenPrice) UpdateContractPrice() {
 				log.Logger.Sugar().Error("UpdateContractPrice SavePriceData err ", err)
 				continue
 			}
+			s.PublishPrice(t, utils.Int64ToString(price))
 		}
 	}
 }
 
+// GetExchangeTokenPrice - 从 Redis 读取定价路径上各交易对的交易所价格并换算为 USDT 价格
+//
+// 参数:
+//   - route: 定价路径，例如 [PLGR-USDT] 或 [PLGR-BTC BTC-USDT]
+//
+// 返回:
+//   - error: 错误信息，任一交易对行情停滞时返回错误
+//   - int64: 代币价格 (1e8 精度，与 Oracle 合约一致)
+func (s *TokenPrice) GetExchangeTokenPrice(route []string) (error, int64) {
+	priceF := decimal.NewFromInt(1)
+	for _, symbol := range route {
+		// 行情超过 [oracle] stale_minutes 未更新视为不可用
+		updatedAt, err := db.RedisGetInt64("exchange_price_time:" + symbol)
+		if err != nil || time.Now().Unix()-updatedAt > config.Config().Oracle.StaleMinutes*60 {
+			return errors.New("exchange price stale " + symbol), 0
+		}
+		legPrice, err := s.LastExchangePrice(symbol)
+		if err != nil {
+			return err, 0
+		}
+		priceF = priceF.Mul(legPrice)
+	}
+	return nil, priceF.Mul(decimal.NewFromInt(100000000)).IntPart()
+}
+
+// LastExchangePrice 交易对的最新成交价，由 kucoin.GetExchangePrice 写入 Redis
+func (s *TokenPrice) LastExchangePrice(symbol string) (decimal.Decimal, error) {
+	priceStr, err := db.RedisGetString("exchange_price:" + symbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return decimal.NewFromString(priceStr)
+}
+
+// GetRoutePrice 按定价路径计算均价: 各交易对在 [exchange] average_window 内的 TWAP/VWAP 相乘，
+// 窗口内没有成交的交易对使用最新成交价
+// 各交易对分别取均价再相乘，与先换算再取均价略有差别，窗口内价格平稳时可以忽略
+func (s *TokenPrice) GetRoutePrice(route []string) (decimal.Decimal, error) {
+	priceF := decimal.NewFromInt(1)
+	for _, symbol := range route {
+		legPrice, err := s.GetAveragePrice(symbol)
+		if err != nil {
+			log.Logger.Sugar().Info("GetRoutePrice use last price ", symbol, " ", err)
+			legPrice, err = s.LastExchangePrice(symbol)
+			if err != nil {
+				return decimal.Zero, err
+			}
+		}
+		priceF = priceF.Mul(legPrice)
+	}
+	return priceF, nil
+}
+
+// PlgrRoute 主网 PLGR 的定价路径，取 [exchange.tokens] 中主网 PLGR 地址的配置，未配置时为 PLGR-USDT
+func PlgrRoute() []string {
+	if route, ok := config.Config().Exchange.TokenRoute(config.Config().MainNet.PlgrAddress); ok {
+		return route
+	}
+	return []string{"PLGR-USDT"}
+}
+
 // GetMainNetTokenPrice - 从主网 BscPledgeOracle 合约获取代币价格
 //
 // 参数:
@@ -126,21 +224,26 @@ func (s *TokenPrice) UpdateContractPrice() {
 //
 // 对应合约: BscPledgeOracle.sol 的 getPrice(address) 或 getUnderlyingPrice(uint256)
 func (s *TokenPrice) GetMainNetTokenPrice(token string) (error, int64) {
-	ethereumConn, err := ethclient.Dial(config.Config.MainNet.NetUrl)
+	ethereumConn, err := ethclient.Dial(models.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId))
 	if nil != err {
 		log.Logger.Error(err.Error())
 		return err, 0
 	}
 
 	// 实例化 BscPledgeOracle 合约绑定
-	bscPledgeOracleMainNetToken, err := bindings.NewBscPledgeOracleMainnetToken(common.HexToAddress(config.Config.MainNet.BscPledgeOracleToken), ethereumConn)
+	bscPledgeOracleMainNetToken, err := bindings.NewBscPledgeOracleMainnetToken(common.HexToAddress(config.Config().MainNet.BscPledgeOracleToken), ethereumConn)
 	if nil != err {
 		log.Logger.Error(err.Error())
 		return err, 0
 	}
 
-	// 调用合约的 GetPrice 函数
-	price, err := bscPledgeOracleMainNetToken.GetPrice(nil, common.HexToAddress(token))
+	// 调用合约的 GetPrice 函数，读取最新区块之前第 read_lag 个区块的价格
+	opts, err := readOpts(context.Background(), ethereumConn, JobUpdateContractPrice, config.Config().MainNet.ChainId)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return err, 0
+	}
+	price, err := bscPledgeOracleMainNetToken.GetPrice(opts, common.HexToAddress(token))
 	if err != nil {
 		log.Logger.Error(err.Error())
 		return err, 0
@@ -160,21 +263,26 @@ func (s *TokenPrice) GetMainNetTokenPrice(token string) (error, int64) {
 //
 // 对应合约: BscPledgeOracle.sol (TestNet) 的 getPrice(address)
 func (s *TokenPrice) GetTestNetTokenPrice(token string) (error, int64) {
-	ethereumConn, err := ethclient.Dial(config.Config.TestNet.NetUrl)
+	ethereumConn, err := ethclient.Dial(models.NewChainHealth().RpcUrl(config.Config().TestNet.ChainId))
 	if nil != err {
 		log.Logger.Error(err.Error())
 		return err, 0
 	}
 
 	// 实例化 BscPledgeOracle 合约绑定 (TestNet)
-	bscPledgeOracleTestnetToken, err := bindings.NewBscPledgeOracleTestnetToken(common.HexToAddress(config.Config.TestNet.BscPledgeOracleToken), ethereumConn)
+	bscPledgeOracleTestnetToken, err := bindings.NewBscPledgeOracleTestnetToken(common.HexToAddress(config.Config().TestNet.BscPledgeOracleToken), ethereumConn)
 	if nil != err {
 		log.Logger.Error(err.Error())
 		return err, 0
 	}
 
-	// 调用合约的 GetPrice 函数
-	price, err := bscPledgeOracleTestnetToken.GetPrice(nil, common.HexToAddress(token))
+	// 调用合约的 GetPrice 函数，读取最新区块之前第 read_lag 个区块的价格
+	opts, err := readOpts(context.Background(), ethereumConn, JobUpdateContractPrice, config.Config().TestNet.ChainId)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return err, 0
+	}
+	price, err := bscPledgeOracleTestnetToken.GetPrice(opts, common.HexToAddress(token))
 	if nil != err {
 		log.Logger.Error(err.Error())
 		return err, 0
@@ -240,6 +348,7 @@ func (s *TokenPrice) CheckTokenInfo(token, chainId string) error {
 			if err != nil {
 				return err
 			}
+			models.InvalidateTokenInfoCache(chainId)
 		} else {
 			return err
 		}
@@ -260,47 +369,208 @@ func (s *TokenPrice) SavePriceData(token, chainId, price string) error {
 		log.Logger.Sugar().Error("UpdateContractPrice SavePriceData err ", err)
 		return err
 	}
+	models.InvalidateTokenInfoCache(chainId)
+
+	// 记入价格历史，写入失败不影响价格更新
+	err = models.NewTokenPriceHistory().Append(token, chainId, price)
+	if err != nil {
+		log.Logger.Sugar().Error("UpdateContractPrice SaveTokenPriceHistory err ", err)
+	}
 
 	return nil
 }
 
-// SavePlgrPrice - 将 PLGR 代币价格写入主网 Oracle 合约
+// MqttPrice MQTT 价格消息
+type MqttPrice struct {
+	ChainId   string `json:"chain_id"`
+	Token     string `json:"token"`
+	Symbol    string `json:"symbol"`
+	Price     string `json:"price"` // 1e8 精度
+	Timestamp int64  `json:"timestamp"`
+}
+
+// PublishPrice 发布价格变化到 {topic_prefix}/{chainId}/price/{symbol}，没有 symbol 的代币使用地址
+func (s *TokenPrice) PublishPrice(t models.TokenInfo, price string) {
+	symbol := t.Symbol
+	if symbol == "" {
+		symbol = strings.ToLower(t.Token)
+	}
+	err := db.MqttPublish(db.MqttTopic(t.ChainId, "price", symbol), MqttPrice{
+		ChainId:   t.ChainId,
+		Token:     t.Token,
+		Symbol:    t.Symbol,
+		Price:     price,
+		Timestamp: time.Now().Unix(),
+	})
+	if err != nil {
+		log.Logger.Sugar().Error("PublishPrice err ", t.Symbol, t.ChainId, err)
+	}
+}
+
+// GetAveragePrice - 计算交易对在 [exchange] average_window 窗口内的均价
+//
+// 均价算法由 average_mode 决定:
+//   - twap: 时间加权，每笔成交价的权重为它持续的时间（到下一笔成交或当前时刻），
+//     窗口起点之前的最后一笔成交覆盖窗口开头
+//   - vwap: 成交量加权，只统计窗口内的成交
+//
+// 窗口内没有可用成交时返回错误，由调用方回退到最新成交价
+func (s *TokenPrice) GetAveragePrice(symbol string) (decimal.Decimal, error) {
+	window := config.Config().Exchange.AverageWindow * 1000
+	if window <= 0 {
+		return decimal.Zero, errors.New("average window not configured")
+	}
+	now := time.Now().UnixMilli()
+	start := now - window
+
+	members, err := db.RedisZRangeByScore("exchange_ticks:"+symbol, now-2*window, now)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	var ticks []models.ExchangeTick
+	for _, member := range members {
+		tick := models.ExchangeTick{}
+		if err = json.Unmarshal([]byte(member), &tick); err != nil {
+			continue
+		}
+		ticks = append(ticks, tick)
+	}
+	price, err := averagePrice(ticks, config.Config().Exchange.AverageMode, start, now)
+	if err != nil {
+		return decimal.Zero, errors.New(err.Error() + " " + symbol)
+	}
+	return price, nil
+}
+
+// averagePrice 按 mode 计算 [start, now] 窗口内的均价，ticks 按成交时间升序
+func averagePrice(ticks []models.ExchangeTick, mode string, start, now int64) (decimal.Decimal, error) {
+	sum := decimal.Zero
+	weight := decimal.Zero
+	if mode == "vwap" {
+		for _, tick := range ticks {
+			if tick.Time < start {
+				continue
+			}
+			price, err := decimal.NewFromString(tick.Price)
+			if err != nil {
+				continue
+			}
+			size, err := decimal.NewFromString(tick.Size)
+			if err != nil {
+				continue
+			}
+			sum = sum.Add(price.Mul(size))
+			weight = weight.Add(size)
+		}
+	} else {
+		for i, tick := range ticks {
+			end := now
+			if i+1 < len(ticks) {
+				end = ticks[i+1].Time
+			}
+			begin := tick.Time
+			if begin < start {
+				begin = start
+			}
+			if end <= begin {
+				continue
+			}
+			price, err := decimal.NewFromString(tick.Price)
+			if err != nil {
+				continue
+			}
+			duration := decimal.NewFromInt(end - begin)
+			sum = sum.Add(price.Mul(duration))
+			weight = weight.Add(duration)
+		}
+	}
+
+	if weight.IsZero() {
+		return decimal.Zero, errors.New("no exchange ticks in window")
+	}
+	return sum.Div(weight), nil
+}
+
+// SaveAllPlgrPrice - 将 PLGR 价格写入 [testnet] / [mainnet] enabled 的链上 Oracle
+// 【定时任务】执行计划由 [jobs.SaveAllPlgrPrice] 配置，chains 可以只写入其中一条链
+func (s *TokenPrice) SaveAllPlgrPrice() {
+	if config.Config().ChainEnabled(JobSaveAllPlgrPrice, config.Config().TestNet.ChainId) {
+		s.SavePlgrPriceTestNet()
+	}
+	if config.Config().ChainEnabled(JobSaveAllPlgrPrice, config.Config().MainNet.ChainId) {
+		s.SavePlgrPrice()
+	}
+}
+
+// SavePlgrPrice - 将 PLGR 以及 [oracle] feeds 中代币的交易所价格写入主网 Oracle 合约
 // 【链上写操作】这是后端唯一的链上写操作！
 // 【定时任务】每 30 分钟执行一次
 //
 // 执行流程:
-//  1. 从 Redis 读取 PLGR 价格（由 kucoin.GetExchangePrice 写入）
+//  0. 按代币检查喂价熔断器，定价路径上任一交易对行情停滞或连续写链失败时拒绝写入该代币
+//  1. 按定价路径 (PLGR 为 PlgrRoute) 计算代币在均价窗口内的 TWAP/VWAP，例如 PLGR-BTC * BTC-USDT，
+//     窗口内无成交的交易对使用 Redis 中的最新价格（由 kucoin.GetExchangePrice 写入）
 //  2. 转换价格精度 (乘以 1e8)
-//  3. 使用 Admin 私钥签名交易
-//  4. 调用 BscPledgeOracle.setPrice(plgrAddress, price) 写入链上
+//  3. 读取链上当前价格，变化不足 [oracle] min_change_bps 时跳过该代币 (OracleWrite)
+//  4. 使用 Admin 私钥签名交易
+//  5. 只有一个代币需要写入或 [oracle] batch_mode = "single" 时逐个调用 BscPledgeOracle.setPrice(address, price)，
+//     否则调用 setPrices 一笔交易写入，失败的代币回退到 setPrice (setPrices)
 //
 // 【安全警告】Admin 私钥直接硬编码在代码中，存在严重安全隐患！
 // 生产环境应使用 HSM、Vault 或环境变量管理私钥。
 func (s *TokenPrice) SavePlgrPrice() {
-	// Step 1: 从 Redis 读取 KuCoin 上的 PLGR 价格
-	priceStr, _ := db.RedisGetString("plgr_price")
-	priceF, _ := decimal.NewFromString(priceStr)
-
-	// Step 2: 转换精度 (价格 * 1e8)
+	// Step 0-2: 熔断检查，避免把冻结的价格反复写上链；计算 KuCoin 均价，失败时回退到最新成交价
 	// Oracle 合约使用 1e8 精度存储价格
 	e8 := decimal.NewFromInt(100000000)
-	priceF = priceF.Mul(e8)
-	price := priceF.IntPart()
+	feeds := make([]*oracleFeed, 0)
+	for _, token := range OracleFeedTokens() {
+		feed := newOracleFeed(token)
+		if !feed.Breaker.Allow() {
+			continue
+		}
+		priceF, err := s.GetRoutePrice(feed.Route)
+		if err != nil {
+			log.Logger.Sugar().Error("SavePlgrPrice price err ", feed.Route, err)
+			continue
+		}
+		feed.Price = priceF.Mul(e8).IntPart()
+		feeds = append(feeds, feed)
+	}
+	if len(feeds) == 0 {
+		return
+	}
 
 	// Step 3: 连接区块链 RPC 节点
-	ethereumConn, err := ethclient.Dial(config.Config.MainNet.NetUrl)
+	ethereumConn, err := ethclient.Dial(models.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId))
 	if nil != err {
 		log.Logger.Error(err.Error())
+		for _, feed := range feeds {
+			feed.Breaker.RecordFailure(err)
+		}
 		return
 	}
 
 	// Step 4: 实例化 BscPledgeOracle 合约绑定
-	bscPledgeOracleMainNetToken, err := bindings.NewBscPledgeOracleMainnetToken(common.HexToAddress(config.Config.MainNet.BscPledgeOracleToken), ethereumConn)
+	bscPledgeOracleMainNetToken, err := bindings.NewBscPledgeOracleMainnetToken(common.HexToAddress(config.Config().MainNet.BscPledgeOracleToken), ethereumConn)
 	if nil != err {
 		log.Logger.Error(err.Error())
 		return
 	}
 
+	// Step 4.1: 读取链上当前价格，变化不足 [oracle] min_change_bps 时跳过写入
+	pending := make([]*oracleFeed, 0, len(feeds))
+	for _, feed := range feeds {
+		readCtx, readCancel := context.WithTimeout(context.Background(), time.Second*5)
+		write := feed.Write.ShouldWrite(readCtx, bscPledgeOracleMainNetToken, feed.Token, feed.Price)
+		readCancel()
+		if write {
+			pending = append(pending, feed)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
 	// Step 5: 加载 Admin 私钥
 	// ⚠️ 警告: 私钥硬编码在 schedule/common 包中，这是不安全的做法
 	privateKeyEcdsa, err := crypto.HexToECDSA(serviceCommon.PlgrAdminPrivateKey)
@@ -310,38 +580,34 @@ func (s *TokenPrice) SavePlgrPrice() {
 	}
 
 	// Step 6: 创建交易签名者
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKeyEcdsa, big.NewInt(utils.StringToInt64(config.Config.MainNet.ChainId)))
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKeyEcdsa, big.NewInt(utils.StringToInt64(config.Config().MainNet.ChainId)))
 	if err != nil {
 		log.Logger.Error(err.Error())
 		return
 	}
 
-	// Step 7: 设置交易超时时间 (5秒)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
-
-	// Step 8: 构造交易参数
-	transactOpts := bind.TransactOpts{
-		From:      auth.From,
-		Nonce:     nil,         // 自动获取 nonce
-		Signer:    auth.Signer, // 交易签名方法
-		Value:     big.NewInt(0),
-		GasPrice:  nil, // 自动估算 gas price
-		GasFeeCap: nil,
-		GasTipCap: nil,
-		GasLimit:  0, // 自动估算 gas limit
-		Context:   ctx,
-		NoSend:    false, // true = 模拟交易, false = 实际发送
+	// Step 7: 多个代币时调用合约的 setPrices，否则调用 setPrice
+	if len(pending) > 1 && config.Config().Oracle.BatchMode == config.OracleBatchSetPrices {
+		s.setPrices(ethereumConn, bscPledgeOracleMainNetToken, auth, pending)
+	} else {
+		for _, feed := range pending {
+			tx, err := s.setPrice(bscPledgeOracleMainNetToken, auth, feed)
+			if s.DryRun {
+				continue
+			}
+			if err != nil {
+				feed.Write.RecordResult(models.OracleWriteResultFailed, "", err)
+				continue
+			}
+			feed.Write.RecordResult(models.OracleWriteResultSent, tx.Hash().Hex(), nil)
+		}
+	}
+	if s.DryRun {
+		return
 	}
 
-	// Step 9: 调用合约的 SetPrice 函数
-	// 对应 BscPledgeOracle.sol 的 setPrice(address, uint256)
-	_, err = bscPledgeOracleMainNetToken.SetPrice(&transactOpts, common.HexToAddress(config.Config.MainNet.PlgrAddress), big.NewInt(price))
-
-	log.Logger.Sugar().Info("SavePlgrPrice ", err)
-
-	// Step 10: 验证价格是否写入成功
-	a, d := s.GetMainNetTokenPrice(config.Config.MainNet.PlgrAddress)
+	// Step 8: 验证价格是否写入成功
+	a, d := s.GetMainNetTokenPrice(config.Config().MainNet.PlgrAddress)
 	log.Logger.Sugar().Info("GetMainNetTokenPrice ", a, d)
 }
 
@@ -350,7 +616,7 @@ func (s *TokenPrice) SavePlgrPrice() {
 // 【定时任务】每 30 分钟执行一次
 //
 // 与主网版本的区别:
-//   - 使用固定测试价格 22222 而非从 KuCoin 获取
+//   - 使用固定测试价格 22222 而非从 KuCoin 获取，链上已是该价格时按 max_skip_minutes 定期重写
 //   - 连接测试网 RPC
 //   - 使用测试网 Chain ID
 func (s *TokenPrice) SavePlgrPriceTestNet() {
@@ -359,19 +625,28 @@ func (s *TokenPrice) SavePlgrPriceTestNet() {
 	price := 22222
 
 	// 连接测试网 RPC
-	ethereumConn, err := ethclient.Dial(config.Config.TestNet.NetUrl)
+	ethereumConn, err := ethclient.Dial(models.NewChainHealth().RpcUrl(config.Config().TestNet.ChainId))
 	if nil != err {
 		log.Logger.Error(err.Error())
 		return
 	}
 
 	// 实例化 BscPledgeOracle 合约绑定 (TestNet)
-	bscPledgeOracleTestNetToken, err := bindings.NewBscPledgeOracleMainnetToken(common.HexToAddress(config.Config.TestNet.BscPledgeOracleToken), ethereumConn)
+	bscPledgeOracleTestNetToken, err := bindings.NewBscPledgeOracleMainnetToken(common.HexToAddress(config.Config().TestNet.BscPledgeOracleToken), ethereumConn)
 	if nil != err {
 		log.Logger.Error(err.Error())
 		return
 	}
 
+	// 读取链上当前价格，变化不足 [oracle] min_change_bps 时跳过写入
+	oracleWrite := NewOracleWrite(config.Config().TestNet.ChainId, "PLGR-USDT")
+	readCtx, readCancel := context.WithTimeout(context.Background(), time.Second*5)
+	write := oracleWrite.ShouldWrite(readCtx, bscPledgeOracleTestNetToken, config.Config().TestNet.PlgrAddress, int64(price))
+	readCancel()
+	if !write {
+		return
+	}
+
 	// 加载 Admin 私钥
 	privateKeyEcdsa, err := crypto.HexToECDSA(serviceCommon.PlgrAdminPrivateKey)
 	if err != nil {
@@ -380,7 +655,7 @@ func (s *TokenPrice) SavePlgrPriceTestNet() {
 	}
 
 	// 创建交易签名者 (使用测试网 Chain ID)
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKeyEcdsa, big.NewInt(utils.StringToInt64(config.Config.TestNet.ChainId)))
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKeyEcdsa, big.NewInt(utils.StringToInt64(config.Config().TestNet.ChainId)))
 	if err != nil {
 		log.Logger.Error(err.Error())
 		return
@@ -399,15 +674,35 @@ func (s *TokenPrice) SavePlgrPriceTestNet() {
 		GasTipCap: nil,
 		GasLimit:  0,
 		Context:   ctx,
-		NoSend:    false,
+		NoSend:    s.DryRun,
 	}
 
 	// 调用合约的 SetPrice 函数写入测试价格
-	_, err = bscPledgeOracleTestNetToken.SetPrice(&transactOpts, common.HexToAddress(config.Config.TestNet.PlgrAddress), big.NewInt(int64(price)))
+	tx, err := bscPledgeOracleTestNetToken.SetPrice(&transactOpts, common.HexToAddress(config.Config().TestNet.PlgrAddress), big.NewInt(int64(price)))
 
 	log.Logger.Sugar().Info("SavePlgrPrice ", err)
+	if s.DryRun {
+		s.logDryRun(tx, int64(price), err)
+		return
+	}
+	if err != nil {
+		telemetry.CaptureError(context.Background(), err, map[string]string{"symbol": "PLGR-USDT", "chain_id": config.Config().TestNet.ChainId})
+		oracleWrite.RecordResult(models.OracleWriteResultFailed, "", err)
+	} else {
+		oracleWrite.RecordResult(models.OracleWriteResultSent, tx.Hash().Hex(), nil)
+		NewGasSpend().Record(config.Config().TestNet.ChainId, "oracle_set_price", tx)
+	}
 
 	// 验证价格是否写入成功
-	a, d := s.GetTestNetTokenPrice(config.Config.TestNet.PlgrAddress)
+	a, d := s.GetTestNetTokenPrice(config.Config().TestNet.PlgrAddress)
 	fmt.Println(a, d, 5555)
 }
+
+// logDryRun 输出模拟写入的价格和已签名但未广播的交易
+func (s *TokenPrice) logDryRun(tx *types.Transaction, price int64, err error) {
+	if err != nil {
+		log.Logger.Sugar().Error("SavePlgrPrice dry-run err ", err)
+		return
+	}
+	log.Logger.Sugar().Info("SavePlgrPrice dry-run price ", price, " tx ", tx.Hash().Hex(), " to ", tx.To().Hex(), " gas ", tx.Gas())
+}
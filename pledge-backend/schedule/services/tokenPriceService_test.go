@@ -0,0 +1,97 @@
+package services
+
+import (
+	"pledge-backend/schedule/models"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestAveragePrice(t *testing.T) {
+	const start, now = int64(10000), int64(20000)
+	tick := func(price, size string, time int64) models.ExchangeTick {
+		return models.ExchangeTick{Price: price, Size: size, Time: time}
+	}
+
+	tests := []struct {
+		name    string
+		ticks   []models.ExchangeTick
+		mode    string
+		want    string
+		wantErr bool
+	}{
+		{name: "twap 空窗口", mode: "twap", wantErr: true},
+		{name: "vwap 空窗口", mode: "vwap", wantErr: true},
+		{
+			name:  "twap 单笔成交覆盖到当前时刻",
+			ticks: []models.ExchangeTick{tick("1.5", "2", 15000)},
+			mode:  "twap",
+			want:  "1.5",
+		},
+		{
+			name:  "vwap 单笔成交",
+			ticks: []models.ExchangeTick{tick("1.5", "2", 15000)},
+			mode:  "vwap",
+			want:  "1.5",
+		},
+		{
+			name:  "twap 窗口前的成交覆盖窗口开头",
+			ticks: []models.ExchangeTick{tick("1", "1", 5000), tick("3", "1", 15000)},
+			mode:  "twap",
+			want:  "2",
+		},
+		{
+			name:    "twap 成交时间等于当前时刻没有持续时间",
+			ticks:   []models.ExchangeTick{tick("1", "1", now)},
+			mode:    "twap",
+			wantErr: true,
+		},
+		{
+			name:  "twap 不看成交量",
+			ticks: []models.ExchangeTick{tick("2", "0", 10000), tick("4", "0", 15000)},
+			mode:  "twap",
+			want:  "3",
+		},
+		{
+			name:    "vwap 成交量为 0",
+			ticks:   []models.ExchangeTick{tick("2", "0", 12000), tick("4", "0", 15000)},
+			mode:    "vwap",
+			wantErr: true,
+		},
+		{
+			name:  "vwap 跳过窗口前的成交",
+			ticks: []models.ExchangeTick{tick("100", "10", 5000), tick("2", "1", 12000), tick("5", "2", 15000)},
+			mode:  "vwap",
+			want:  "4",
+		},
+		{
+			name:  "vwap 跳过无法解析的成交",
+			ticks: []models.ExchangeTick{tick("x", "1", 12000), tick("2", "y", 13000), tick("3", "1", 14000)},
+			mode:  "vwap",
+			want:  "3",
+		},
+		{
+			name:  "未知 mode 按 twap 计算",
+			ticks: []models.ExchangeTick{tick("2", "1", 10000)},
+			mode:  "",
+			want:  "2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := averagePrice(tt.ticks, tt.mode, start, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("averagePrice() = %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("averagePrice() err = %v", err)
+			}
+			if !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("averagePrice() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
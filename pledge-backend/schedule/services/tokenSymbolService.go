@@ -3,6 +3,8 @@ package services
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math/big"
 	"os"
 	"pledge-backend/config"
 	abifile "pledge-backend/contract/abi"
@@ -10,6 +12,7 @@ import (
 	"pledge-backend/log"
 	"pledge-backend/schedule/models"
 	"pledge-backend/utils"
+	"strconv"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -25,51 +28,90 @@ func NewTokenSymbol() *TokenSymbol {
 	return &TokenSymbol{}
 }
 
-// UpdateContractSymbol get contract symbol
-func (s *TokenSymbol) UpdateContractSymbol() {
+// UpdateContractMetadata get contract name, symbol and decimals
+// 从代币合约读取 name()、symbol()、decimals()，与 token_info 中的数据比较，有变化时更新
+func (s *TokenSymbol) UpdateContractMetadata() {
 	var tokens []models.TokenInfo
-	db.Mysql.Table("token_info").Find(&tokens)
+	db.Mysql.Table("token_info").Where("deleted_at is null").Find(&tokens)
 	for _, t := range tokens {
 		if t.Token == "" {
-			log.Logger.Sugar().Error("UpdateContractSymbol token empty", t.Symbol, t.ChainId)
+			log.Logger.Sugar().Error("UpdateContractMetadata token empty", t.Symbol, t.ChainId)
 			continue
 		}
 		err := errors.New("")
-		symbol := ""
-		if t.ChainId == config.Config.TestNet.ChainId {
-			err, symbol = s.GetContractSymbolOnTestNet(t.Token, config.Config.TestNet.NetUrl)
-		} else if t.ChainId == config.Config.MainNet.ChainId {
+		metadata := models.TokenMetadata{}
+		if t.ChainId == config.Config().TestNet.ChainId {
+			err, metadata = s.GetContractMetadata(t.Token, models.NewChainHealth().RpcUrl(config.Config().TestNet.ChainId), "erc20")
+		} else if t.ChainId == config.Config().MainNet.ChainId {
 			if t.AbiFileExist == 0 {
 				err = s.GetRemoteAbiFileByToken(t.Token, t.ChainId)
 				if err != nil {
-					log.Logger.Sugar().Error("UpdateContractSymbol GetRemoteAbiFileByToken err ", t.Symbol, t.ChainId, err)
+					log.Logger.Sugar().Error("UpdateContractMetadata GetRemoteAbiFileByToken err ", t.Symbol, t.ChainId, err)
 					continue
 				}
 			}
-			err, symbol = s.GetContractSymbolOnMainNet(t.Token, config.Config.MainNet.NetUrl)
+			err, metadata = s.GetContractMetadata(t.Token, models.NewChainHealth().RpcUrl(config.Config().MainNet.ChainId), t.Token)
 		} else {
-			log.Logger.Sugar().Error("UpdateContractSymbol chain_id err ", t.Symbol, t.ChainId)
+			log.Logger.Sugar().Error("UpdateContractMetadata chain_id err ", t.Symbol, t.ChainId)
 			continue
 		}
 		if err != nil {
-			log.Logger.Sugar().Error("UpdateContractSymbol err ", t.Symbol, t.ChainId, err)
+			log.Logger.Sugar().Error("UpdateContractMetadata err ", t.Symbol, t.ChainId, err)
 			continue
 		}
 
-		hasNewData, err := s.CheckSymbolData(t.Token, t.ChainId, symbol)
+		_, err = s.CheckSymbolData(t.Token, t.ChainId, metadata.Symbol)
 		if err != nil {
-			log.Logger.Sugar().Error("UpdateContractSymbol CheckSymbolData err ", err)
+			log.Logger.Sugar().Error("UpdateContractMetadata CheckSymbolData err ", err)
 			continue
 		}
 
-		if hasNewData {
-			err = s.SaveSymbolData(t.Token, t.ChainId, symbol)
-			if err != nil {
-				log.Logger.Sugar().Error("UpdateContractSymbol SaveSymbolData err ", err)
+		if t.Symbol == metadata.Symbol && t.Name == metadata.Name && t.Decimals == metadata.Decimals {
+			continue
+		}
+		if t.Symbol != "" && t.Decimals != metadata.Decimals {
+			// 已有代币的精度变化会影响所有金额换算，与 VerifyIntegrity 一样不写入，只告警，由人工确认后修改
+			s.alertDecimalsChanged(t, metadata.Decimals)
+			metadata.Decimals = t.Decimals
+			if t.Symbol == metadata.Symbol && t.Name == metadata.Name {
 				continue
 			}
 		}
+		log.Logger.Sugar().Info("UpdateContractMetadata metadata changed ", t.Token, t.ChainId,
+			" symbol: ", t.Symbol, " -> ", metadata.Symbol,
+			" name: ", t.Name, " -> ", metadata.Name,
+			" decimals: ", t.Decimals, " -> ", metadata.Decimals)
+
+		err = s.SaveMetadata(t.Token, t.ChainId, metadata)
+		if err != nil {
+			log.Logger.Sugar().Error("UpdateContractMetadata SaveMetadata err ", err)
+			continue
+		}
+	}
+	RefreshTokenList()
+}
+
+// alertDecimalsChanged 已有代币的链上精度与 token_info 不一致，同一代币的同一新精度只告警一次
+func (s *TokenSymbol) alertDecimalsChanged(t models.TokenInfo, decimals int) {
+	target := t.Token + ":" + strconv.Itoa(decimals)
+	text := fmt.Sprintf("Pledge token %s (%s) on chain %s changed decimals on chain: %d -> %d, token_info not updated, manual confirmation required",
+		t.Symbol, t.Token, t.ChainId, t.Decimals, decimals)
+	log.Logger.Sugar().Error("UpdateContractMetadata ", text)
+
+	alerted, err := models.NewAlertHistory().Exists("token_decimals", t.ChainId, target)
+	if err != nil {
+		log.Logger.Error(err.Error())
+		return
+	}
+	if alerted {
+		return
+	}
+	var errs []string
+	if err = utils.SendEmail([]byte("<p>"+text+"</p>"), 2); err != nil {
+		log.Logger.Error(err.Error())
+		errs = append(errs, "email: "+err.Error())
 	}
+	saveAlertHistory("token_decimals", t.ChainId, target, models.AlertLevelEmail, 0, text, errs)
 }
 
 // GetRemoteAbiFileByToken get and save remote abi file on main net
@@ -132,70 +174,61 @@ func (s *TokenSymbol) FormatAbiJsonStr(result string) string {
 	return resStr
 }
 
-// GetContractSymbolOnMainNet get contract symbol on main net
-func (s *TokenSymbol) GetContractSymbolOnMainNet(token, network string) (error, string) {
+// GetContractMetadata get contract name, symbol and decimals
+// abiName: 主网使用下载的代币 ABI 文件（文件名为代币地址），测试网使用 erc20
+func (s *TokenSymbol) GetContractMetadata(token, network, abiName string) (error, models.TokenMetadata) {
+	metadata := models.TokenMetadata{}
 	ethereumConn, err := ethclient.Dial(network)
 	if nil != err {
-		log.Logger.Sugar().Error("GetContractSymbolOnMainNet err ", token, err)
-		return err, ""
+		log.Logger.Sugar().Error("GetContractMetadata err ", token, err)
+		return err, metadata
 	}
-	abiStr, err := abifile.GetAbiByToken(token)
+	defer ethereumConn.Close()
+
+	abiStr, err := abifile.GetAbiByToken(abiName)
 	if err != nil {
-		log.Logger.Sugar().Error("GetContractSymbolOnMainNet err ", token, err)
-		return err, ""
+		log.Logger.Sugar().Error("GetContractMetadata err ", token, err)
+		return err, metadata
 	}
 	parsed, err := abi.JSON(strings.NewReader(abiStr))
 	if err != nil {
-		log.Logger.Sugar().Error("GetContractSymbolOnMainNet err ", token, err)
-		return err, ""
-	}
-	contract, err := bind.NewBoundContract(common.HexToAddress(token), parsed, ethereumConn, ethereumConn, ethereumConn), nil
-	if err != nil {
-		log.Logger.Sugar().Error("GetContractSymbolOnMainNet err ", token, err)
-		return err, ""
+		log.Logger.Sugar().Error("GetContractMetadata err ", token, err)
+		return err, metadata
 	}
+	contract := bind.NewBoundContract(common.HexToAddress(token), parsed, ethereumConn, ethereumConn, ethereumConn)
 
 	res := make([]interface{}, 0)
 	err = contract.Call(nil, &res, "symbol")
 	if err != nil {
-		log.Logger.Sugar().Error("GetContractSymbolOnMainNet err ", err)
-		return err, ""
+		log.Logger.Sugar().Error("GetContractMetadata symbol err ", token, err)
+		return err, metadata
 	}
+	metadata.Symbol = res[0].(string)
 
-	return nil, res[0].(string)
-}
-
-// GetContractSymbolOnTestNet get contract symbol on test net
-func (s *TokenSymbol) GetContractSymbolOnTestNet(token, network string) (error, string) {
-	ethereumConn, err := ethclient.Dial(network)
-	if nil != err {
-		log.Logger.Sugar().Error("GetContractSymbolOnMainNet err ", token, err)
-		return err, ""
-	}
-	abiStr, err := abifile.GetAbiByToken("erc20")
+	res = make([]interface{}, 0)
+	err = contract.Call(nil, &res, "name")
 	if err != nil {
-		log.Logger.Sugar().Error("GetContractSymbolOnMainNet err ", token, err)
-		return err, ""
-	}
-	parsed, err := abi.JSON(strings.NewReader(abiStr))
-	if err != nil {
-		log.Logger.Sugar().Error("GetContractSymbolOnMainNet err ", token, err)
-		return err, ""
-	}
-	contract, err := bind.NewBoundContract(common.HexToAddress(token), parsed, ethereumConn, ethereumConn, ethereumConn), nil
-	if err != nil {
-		log.Logger.Sugar().Error("GetContractSymbolOnMainNet err ", token, err)
-		return err, ""
+		log.Logger.Sugar().Error("GetContractMetadata name err ", token, err)
+		return err, metadata
 	}
+	metadata.Name = res[0].(string)
 
-	res := make([]interface{}, 0)
-	err = contract.Call(nil, &res, "symbol")
+	res = make([]interface{}, 0)
+	err = contract.Call(nil, &res, "decimals")
 	if err != nil {
-		log.Logger.Sugar().Error("GetContractSymbolOnMainNet err ", token, err)
-		return err, ""
+		log.Logger.Sugar().Error("GetContractMetadata decimals err ", token, err)
+		return err, metadata
+	}
+	switch decimals := res[0].(type) {
+	case uint8:
+		metadata.Decimals = int(decimals)
+	case *big.Int:
+		metadata.Decimals = int(decimals.Int64())
+	default:
+		return errors.New("unexpected decimals type"), metadata
 	}
 
-	return nil, res[0].(string)
+	return nil, metadata
 }
 
 // CheckSymbolData Saving symbol data to redis if it has new symbol
@@ -254,6 +287,7 @@ func (s *TokenSymbol) CheckTokenInfo(token, chainId string) error {
 			if err != nil {
 				return err
 			}
+			models.InvalidateTokenInfoCache(chainId)
 		} else {
 			return err
 		}
@@ -261,18 +295,21 @@ func (s *TokenSymbol) CheckTokenInfo(token, chainId string) error {
 	return nil
 }
 
-// SaveSymbolData Saving symbol data to mysql if it has new symbol
-func (s *TokenSymbol) SaveSymbolData(token, chainId, symbol string) error {
+// SaveMetadata Saving name, symbol and decimals to mysql if they have changed
+func (s *TokenSymbol) SaveMetadata(token, chainId string, metadata models.TokenMetadata) error {
 	nowDateTime := utils.GetCurDateTimeFormat()
 
 	err := db.Mysql.Table("token_info").Where("token=? and chain_id=? ", token, chainId).Updates(map[string]interface{}{
-		"symbol":     symbol,
+		"symbol":     metadata.Symbol,
+		"name":       metadata.Name,
+		"decimals":   metadata.Decimals,
 		"updated_at": nowDateTime,
 	}).Debug().Error
 	if err != nil {
-		log.Logger.Sugar().Error("UpdateContractSymbol SaveSymbolData err ", err)
+		log.Logger.Sugar().Error("UpdateContractMetadata SaveMetadata err ", err)
 		return err
 	}
+	models.InvalidateTokenInfoCache(chainId)
 
 	return nil
 }
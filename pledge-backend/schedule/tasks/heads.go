@@ -0,0 +1,139 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"pledge-backend/telemetry"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// headTimeout 超过这个时间没有收到新区块视为订阅失效，BSC 约 3 秒一个区块
+const headTimeout = time.Minute
+
+// headsLive 订阅正常的链，key=chainId
+var headsLive sync.Map
+
+// watchHeads 为配置了 ws_url 的链订阅 newHeads，新区块到达时执行 on_head 的任务
+// 订阅断开后按 1, 2, 4 ... 秒 (最长 1 分钟) 退避重连，期间这些任务按 cron 执行
+func watchHeads() {
+	heads := make(chan string, 2)
+	chains := []struct {
+		enabled        bool
+		chainId, wsUrl string
+	}{
+		{config.Config().TestNet.Enabled, config.Config().TestNet.ChainId, config.Config().TestNet.WsUrl},
+		{config.Config().MainNet.Enabled, config.Config().MainNet.ChainId, config.Config().MainNet.WsUrl},
+	}
+	subscribed := false
+	for _, chain := range chains {
+		if chain.enabled && chain.wsUrl != "" {
+			go subscribeHeads(chain.chainId, chain.wsUrl, heads)
+			subscribed = true
+		}
+	}
+	if subscribed {
+		go runOnHeads(heads)
+	}
+}
+
+// subscribeHeads 保持一条链的 newHeads 订阅，断开后重连
+func subscribeHeads(chainId, wsUrl string, heads chan<- string) {
+	backoff := time.Second
+	for {
+		received, err := followHeads(chainId, wsUrl, heads)
+		headsLive.Delete(chainId)
+		if received {
+			backoff = time.Second
+		}
+		log.Logger.Sugar().Warn("newHeads subscription ", chainId, " dropped, fall back to cron: ", err)
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > time.Minute {
+			backoff = time.Minute
+		}
+	}
+}
+
+// followHeads 订阅 newHeads 直到出错或超过 headTimeout 没有新区块，返回是否收到过新区块
+func followHeads(chainId, wsUrl string, heads chan<- string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	client, err := telemetry.DialEth(ctx, wsUrl)
+	cancel()
+	if err != nil {
+		return false, err
+	}
+	defer client.Close()
+
+	headers := make(chan *types.Header, 16)
+	sub, err := client.SubscribeNewHead(context.Background(), headers)
+	if err != nil {
+		return false, err
+	}
+	defer sub.Unsubscribe()
+	headsLive.Store(chainId, true)
+	log.Logger.Sugar().Info("newHeads subscribed ", chainId)
+
+	received := false
+	timer := time.NewTimer(headTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case err = <-sub.Err():
+			return received, err
+		case <-timer.C:
+			return received, errors.New("no new head in " + headTimeout.String())
+		case <-headers:
+			received = true
+			timer.Reset(headTimeout)
+			// 上一个区块还没有处理时合并
+			select {
+			case heads <- chainId:
+			default:
+			}
+		}
+	}
+}
+
+// runOnHeads 新区块到达时执行处理该链的 on_head 任务
+// 同一任务两次触发至少间隔 [schedule] head_interval 秒，正在执行时跳过，不记录为 skipped
+func runOnHeads(heads <-chan string) {
+	all := jobs()
+	last := map[string]time.Time{}
+	for chainId := range heads {
+		interval := time.Duration(config.Config().Schedule.HeadInterval) * time.Second
+		for _, j := range all {
+			conf := config.Config().Jobs[j.name]
+			if !conf.Enabled || !conf.OnHead || !config.Config().ChainEnabled(j.name, chainId) {
+				continue
+			}
+			if time.Since(last[j.name]) < interval {
+				continue
+			}
+			if _, busy := running.Load(j.name); busy {
+				continue
+			}
+			last[j.name] = time.Now()
+			go scheduled(j)()
+		}
+	}
+}
+
+// headsCover 任务处理的链是否都有正常的 newHeads 订阅，是时 cron 不再触发 on_head 的任务
+func headsCover(name string) bool {
+	covered := false
+	for _, chainId := range []string{config.Config().TestNet.ChainId, config.Config().MainNet.ChainId} {
+		if !config.Config().ChainEnabled(name, chainId) {
+			continue
+		}
+		if _, ok := headsLive.Load(chainId); !ok {
+			return false
+		}
+		covered = true
+	}
+	return covered
+}
@@ -0,0 +1,134 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"pledge-backend/schedule/models"
+	"pledge-backend/schedule/services"
+	"pledge-backend/telemetry"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// running 正在执行的任务，调度器热加载重建后仍然有效
+var running sync.Map
+
+// statsLock 保护 job_stats 的读-改-写，超时的任务结束时可能与下一次执行同时记录
+var statsLock sync.Mutex
+
+// lastPrune 上一次清理 job_runs 的时间，由 statsLock 保护
+var lastPrune time.Time
+
+// runner 包装定时任务:
+//   - panic 被恢复并上报到 Sentry (telemetry.RunJob)
+//   - 超过 [schedule] job_timeout 后取消 ctx 并停止等待，只有使用 ctx 的任务会真正中断，
+//     其余任务在后台继续执行，结束前后续的执行都被跳过
+//   - 上一次执行仍未结束时跳过本次，避免同一任务并发执行
+//   - 执行结果和耗时记录到 Redis job_stats:<name>，通过 GET /admin/jobs 查看
+//   - 每次执行的开始、结束时间、结果和处理的条目数记录到 job_runs 表，通过 GET /admin/jobs/runs 查看
+func runner(name string, job func(ctx context.Context)) func() {
+	return func() {
+		if _, loaded := running.LoadOrStore(name, true); loaded {
+			log.Logger.Sugar().Warn("job ", name, " is still running, skip")
+			recordJob(name, models.JobSkipped, nil, time.Now(), nil)
+			return
+		}
+
+		timeout := time.Duration(config.Config().Schedule.JobTimeout) * time.Minute
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		ctx, progress := services.WithJobProgress(ctx)
+		start := time.Now()
+		done := make(chan error, 1)
+		go func() {
+			defer running.Delete(name)
+			done <- telemetry.RunJob(ctx, name, job)
+		}()
+
+		select {
+		case err := <-done:
+			status := models.JobSuccess
+			if err != nil {
+				status = models.JobFailed
+			}
+			recordJob(name, status, err, start, progress)
+		case <-ctx.Done():
+			log.Logger.Sugar().Error("job ", name, " timed out after ", timeout)
+			recordJob(name, models.JobTimeout, errors.New("timed out after "+timeout.String()), start, progress)
+		}
+	}
+}
+
+// traced 包装没有 ctx 参数的任务，ctx 超时后任务不会被中断
+func traced(name string, job func()) func() {
+	return runner(name, func(context.Context) { job() })
+}
+
+// recordJob 更新任务的执行统计，除跳过外保存执行记录
+// progress 为超时时已处理的条目数，任务仍可能在后台继续处理
+func recordJob(name, status string, err error, start time.Time, progress *services.JobProgress) {
+	statsLock.Lock()
+	defer statsLock.Unlock()
+
+	duration := time.Since(start)
+	stats := models.NewJobStats().Get(name)
+	stats.LastStatus = status
+	switch status {
+	case models.JobSkipped:
+		stats.Skipped++
+	case models.JobSuccess:
+		stats.LastSuccessAt = start.Add(duration).Unix()
+	case models.JobFailed:
+		stats.Failures++
+	case models.JobTimeout:
+		stats.Timeouts++
+	}
+	if status != models.JobSkipped {
+		stats.Runs++
+		stats.LastRunAt = start.Unix()
+		stats.LastDurationMs = duration.Milliseconds()
+		stats.TotalDurationMs += stats.LastDurationMs
+		if stats.LastDurationMs > stats.MaxDurationMs {
+			stats.MaxDurationMs = stats.LastDurationMs
+		}
+	}
+	if err != nil {
+		stats.LastError = err.Error()
+	}
+
+	if err := models.NewJobStats().Save(stats); err != nil {
+		log.Logger.Error(err.Error())
+	}
+
+	if status == models.JobSkipped {
+		return
+	}
+	run := models.JobRun{
+		Name:       name,
+		Status:     status,
+		Processed:  atomic.LoadInt64(&progress.Processed),
+		Failed:     atomic.LoadInt64(&progress.Failed),
+		DurationMs: duration.Milliseconds(),
+		StartedAt:  start.Format("2006-01-02 15:04:05"),
+		FinishedAt: start.Add(duration).Format("2006-01-02 15:04:05"),
+	}
+	if err != nil {
+		run.Error = err.Error()
+	}
+	if err := models.NewJobRun().Save(&run); err != nil {
+		log.Logger.Error(err.Error())
+	}
+	services.PublishOpsEvent(models.OpsEventJob, "", run)
+
+	// 每小时清理一次超过 [schedule] job_run_retention_days 的执行记录
+	if time.Since(lastPrune) > time.Hour {
+		lastPrune = time.Now()
+		retention := time.Duration(config.Config().Schedule.JobRunRetentionDays) * 24 * time.Hour
+		if err := models.NewJobRun().Prune(time.Now().Add(-retention)); err != nil {
+			log.Logger.Error(err.Error())
+		}
+	}
+}
@@ -5,40 +5,164 @@
  *
  * 【核心功能】
  * 该文件负责编排和调度所有后台定时任务，包括：
- * - 同步借贷池数据 (每 2 分钟)
- * - 更新代币价格 (每 1 分钟)
- * - 更新代币符号 (每 2 小时)
- * - 更新代币 Logo (每 2 小时)
- * - 监控账户余额 (每 30 分钟)
- * - 写入 PLGR 价格到链上 (每 30 分钟)
+ * - 同步借贷池数据 (默认每 2 分钟)
+ * - 索引借贷池存入事件 (默认每 2 分钟)
+ * - 更新代币价格 (默认每 1 分钟)
+ * - 更新 Chainlink 价格 (默认每 1 分钟)
+ * - 更新代币元信息 (默认每 2 小时)
+ * - 更新代币 Logo (默认每 2 小时)
+ * - 重建代币搜索索引 (默认每 10 分钟)
+ * - 监控账户余额 (默认每 30 分钟)
+ * - 写入 PLGR 价格到链上 (默认每 30 分钟)
+ * - 统计交易 gas 花费 (默认每 5 分钟)
+ * - 检查 RPC 节点健康状态，并采集最新区块和 gas 价格建议 (默认每 1 分钟)
+ * - 检查链上 PLGR 价格是否按时更新 (默认每 10 分钟)
+ * - 生成每日协议报表 (默认每天 00:10)
+ * - 导出 Parquet 快照到 S3 (默认每天 00:30)
+ * - 备份数据库到 S3 (默认每天 02:00)
+ * - 抽查池子、代币数据与链上是否一致 (默认每 30 分钟)
+ * - 重试执行中处理失败的条目 (默认每 1 分钟)
+ * - 归档结束超过宽限期的池子 (默认每天 01:00)
+ * - 发送到期池子的结算、完成和清算交易 (默认每 1 分钟，需要 [keeper] enabled)
+ * - 池子结算、结束倒计时通知 (默认每 1 分钟)
+ * - 按钱包订阅发送池子事件邮件 (默认每 2 分钟，需要 [subscription] enabled)
+ * - 统计池子完成、清算时的协议手续费收入 (默认每 10 分钟)
+ * - 校验等待索引的推荐归属交易 (默认每 2 分钟，需要 [referral] enabled)
+ * - 保存交易所成交记录到按月分表 (默认每 5 分钟，需要 [exchange] trade_retention_months)
+ * - 回填管理员提交的历史价格 (默认每 1 分钟，没有提交时不执行)
  *
  * 【技术实现】
- * 使用 gocron 库实现任务调度，所有任务在 UTC 时区运行
+ * 使用 robfig/cron 库实现任务调度，所有任务在 UTC 时区运行
+ * 每次任务执行记录一个 "job <name>" span ([telemetry] enabled 时导出)，panic 被恢复并上报到 Sentry
+ * 任务由 runner 包装: 超过 [schedule] job_timeout 视为超时，上一次未结束时跳过本次，执行结果记录到 GET /admin/jobs 和 job_runs 表
+ * 执行计划由 [jobs.<任务名称>] 的 cron 表达式配置，可以单独停用任务或限制处理的链，修改配置文件后自动重建调度器，无需重启
+ * 链配置了 ws_url 时订阅 newHeads，on_head = true 的同步任务在新区块到达时执行 (见 heads.go)，订阅断开期间按 cron 执行
+ * 多实例部署 ([cluster] enabled) 时链上写入等任务只在 leader 上执行，run = "shard" 的同步任务由所有实例分片执行
  *
  * 【调用关系】
- * pledge_task.go (入口) --> Task() --> 各个 Service
+ * pledge task (cmd/task.go) --> Task() --> 各个 Service
  * ==================================================================================
  */
 
 package tasks
 
 import (
+	"pledge-backend/config"
 	"pledge-backend/db"
+	"pledge-backend/log"
+	"pledge-backend/schedule/cluster"
 	"pledge-backend/schedule/common"
 	"pledge-backend/schedule/services"
 	"time"
 
-	"github.com/jasonlvhit/gocron"
+	"github.com/robfig/cron/v3"
 )
 
+// job 调度器中的一个定时任务
+type job struct {
+	name    string
+	run     func()
+	startup bool // task 启动时立即执行一次
+}
+
+// jobs 所有定时任务，name 对应配置文件中的 [jobs.<任务名称>]
+func jobs() []job {
+	return []job{
+		// 同步借贷池信息 (从链上读取 PoolBaseInfo 和 PoolDataInfo)
+		{services.JobUpdateAllPoolInfo, runner(services.JobUpdateAllPoolInfo, services.NewPool().UpdateAllPoolInfo), true},
+
+		// 索引借贷池存入事件 (DepositLend / DepositBorrow)，从上次扫描到的区块继续，发现分叉时回滚未确认的事件并重新扫描
+		{services.JobUpdatePoolEvents, traced(services.JobUpdatePoolEvents, services.NewPoolEvent().UpdatePoolEvents), true},
+
+		// 更新代币价格 (从链上 Oracle 读取并保存到数据库)
+		{services.JobUpdateContractPrice, traced(services.JobUpdateContractPrice, services.NewTokenPrice().UpdateContractPrice), true},
+
+		// 更新 Chainlink 价格 (第二价格来源)
+		// 从 BSC 主网 Chainlink aggregator 读取，与 Oracle 价格交叉校验
+		{services.JobUpdateChainlinkPrice, traced(services.JobUpdateChainlinkPrice, services.NewChainlinkPrice().UpdateChainlinkPrice), true},
+
+		// 更新代币元信息 (从代币合约读取 name()、symbol()、decimals())
+		// 代币名称、符号、精度变化较少，低频更新即可
+		{services.JobUpdateContractMetadata, traced(services.JobUpdateContractMetadata, services.NewTokenSymbol().UpdateContractMetadata), true},
+
+		// 更新代币 Logo (覆盖表、TrustWallet、CoinGecko、代币列表)
+		{services.JobUpdateTokenLogo, traced(services.JobUpdateTokenLogo, services.NewTokenLogo().UpdateTokenLogo), true},
+
+		// 重建代币搜索索引 (依赖元信息同步得到的 symbol、name)
+		// 管理员新增/删除代币后最多一个周期可被搜索到
+		{services.JobUpdateSearchIndex, traced(services.JobUpdateSearchIndex, services.NewSearchIndex().UpdateSearchIndex), true},
+
+		// 监控账户余额 (检查合约地址的 BNB 余额)，低于阈值时发送告警
+		{services.JobMonitor, traced(services.JobMonitor, services.NewBalanceMonitor().Monitor), true},
+
+		// 写入 PLGR 价格到链上 Oracle
+		// 主网: KuCoin 均价；测试网: 固定测试价格
+		{services.JobSaveAllPlgrPrice, traced(services.JobSaveAllPlgrPrice, services.NewTokenPrice().SaveAllPlgrPrice), true},
+
+		// 统计交易 gas 花费
+		// 查询喂价等交易的回执，当月花费超出 [gas] 预算时告警
+		{services.JobUpdateGasSpend, traced(services.JobUpdateGasSpend, services.NewGasSpend().UpdateGasSpend), false},
+
+		// 检查 RPC 节点健康状态，采集网络状态
+		// 记录延迟、区块高度和落后参考节点的区块数，不健康的节点标记在 Redis 中
+		{services.JobUpdateChainHealth, runner(services.JobUpdateChainHealth, services.NewChainHealth().UpdateChainHealth), false},
+
+		// 检查链上 PLGR 价格是否按时更新
+		// 主网 Oracle 价格长时间未变化或偏离交易所价格时告警，发现 SavePlgrPrice 静默失败
+		{services.JobOracleMonitor, traced(services.JobOracleMonitor, services.NewOracleMonitor().Monitor), false},
+
+		// 生成前一天的协议报表
+		{services.JobGenerateDailyReport, traced(services.JobGenerateDailyReport, services.NewDailyReport().GenerateDailyReport), false},
+
+		// 导出 poolbases、pooldata、token_price_history 到 S3，供数据分析使用，还需要 [export] enabled
+		{services.JobExportDaily, traced(services.JobExportDaily, services.NewExport().ExportDaily), false},
+
+		// 备份代币、池子、池子展示信息、多签等表到 S3，删除超过保留期的备份，还需要 [backup] enabled；恢复见 pledge restore
+		{services.JobBackupDatabase, runner(services.JobBackupDatabase, services.NewBackup().BackupDatabase), false},
+
+		// 抽查池子、代币的链上数据与 MySQL、Redis 是否一致，[integrity] repair 时重新同步不一致的池子、代币
+		{services.JobVerifyIntegrity, runner(services.JobVerifyIntegrity, services.NewIntegrity().VerifyIntegrity), false},
+
+		// 重试执行中处理失败的条目 (例如保存失败的池子)，不必等待下一轮全量同步
+		{services.JobProcessRetryQueue, runner(services.JobProcessRetryQueue, services.NewRetryQueue().ProcessRetryQueue), false},
+
+		// 归档 endTime 超过 [schedule] archive_grace_days 的池子，归档后不再同步，接口默认不返回
+		{services.JobArchivePools, runner(services.JobArchivePools, services.NewPoolArchive().ArchivePools), false},
+
+		// 发送到期池子的 settle / finish / liquidate 交易，需要 [keeper] enabled，交易记录在 keeper_tx 表
+		{services.JobLiquidatePools, runner(services.JobLiquidatePools, services.NewKeeper().LiquidatePools), false},
+
+		// 池子 settleTime / endTime 进入 [deadline] lead_times 时通知 WebSocket、webhook 和管理员
+		{services.JobWatchDeadlines, runner(services.JobWatchDeadlines, services.NewDeadline().WatchDeadlines), false},
+
+		// 池子已结算、已完成、已清算或有可提取资金时，向订阅了该池子的已验证邮箱发送通知，需要 [subscription] enabled
+		{services.JobNotifySubscribers, runner(services.JobNotifySubscribers, services.NewSubscription().NotifySubscribers), false},
+
+		// 统计新完成或清算的池子收取的 lendFee / borrowFee，写入 fee_revenues，供 GET /stats/fees 和每日报表使用
+		{services.JobAccountFeeRevenue, runner(services.JobAccountFeeRevenue, services.NewFeeRevenue().AccountFeeRevenue), false},
+
+		// 在 pool_events 中查找推荐归属的交易，找到后记录存入信息，超过 [referral] pending_hours 未找到时标记为无效，需要 [referral] enabled
+		{services.JobVerifyReferrals, runner(services.JobVerifyReferrals, services.NewReferral().VerifyReferrals), false},
+
+		// 把 api 写入 Redis 的交易所成交保存到 exchange_trades_YYYYMM，删除超过保留期的分表，需要 [exchange] trade_retention_months
+		{services.JobPersistExchangeTrades, runner(services.JobPersistExchangeTrades, services.NewExchangeTrade().PersistExchangeTrades), false},
+
+		// 在历史区块上读取 Oracle 价格，回填 token_price_history 中价格服务上线之前的价格，每次保存检查点，超时后下次继续
+		{services.JobBackfillPriceHistory, runner(services.JobBackfillPriceHistory, services.NewPriceBackfill().BackfillPriceHistory), false},
+
+		// 读取池子 spCoin / jpCoin 的 minter 白名单和 PledgePool 的 globalPaused，写入 Redis 供 /pool/:chainId/:poolId/whitelist 使用
+		{services.JobSyncPrivileges, runner(services.JobSyncPrivileges, services.NewPrivilege().SyncPrivileges), true},
+	}
+}
+
 // Task - 定时任务主函数
-// 【入口函数】由 pledge_task.go 的 main() 调用
+// 【入口函数】由 pledge task 子命令 (cmd/task.go) 调用
 //
 // 执行流程:
 //  1. 加载环境变量
-//  2. 清空 Redis 缓存
-//  3. 立即执行一次所有任务 (初始化)
-//  4. 配置定时任务调度
+//  2. 清空 Redis 缓存，[cluster] enabled 时改为加入集群并参与选主
+//  3. 立即执行一次启用的同步任务 (初始化)
+//  4. 按 [jobs] 配置定时任务调度
 //  5. 启动调度器 (阻塞运行)
 func Task() {
 
@@ -51,72 +175,114 @@ func Task() {
 	// ============================================================
 	// Step 2: 清空 Redis 缓存
 	// 确保服务重启后从链上重新同步所有数据
+	// 多实例部署时其它实例的租约和缓存也在同一个 Redis 中，不能清空，只加入集群并参与选主
 	// ============================================================
-	err := db.RedisFlushDB()
-	if err != nil {
-		panic("clear redis error " + err.Error())
+	if config.Config().Cluster.Enabled {
+		cluster.Start()
+	} else {
+		err := db.RedisFlushDB()
+		if err != nil {
+			panic("clear redis error " + err.Error())
+		}
 	}
 
 	// ============================================================
-	// Step 3: 初始化 - 立即执行一次所有任务
+	// Step 3: 初始化 - 立即执行一次启用的同步任务
 	// 这确保服务启动后立即有可用数据，而不是等待定时器触发
+	// 与定时执行一样经过 runner 包装，记录 span 和执行统计，panic 上报到 Sentry 后继续启动
 	// ============================================================
+	for _, j := range jobs() {
+		if j.startup && config.Config().Jobs[j.name].Enabled {
+			scheduled(j)()
+		}
+	}
 
-	// 同步所有借贷池信息 (从链上读取 PoolBaseInfo 和 PoolDataInfo)
-	services.NewPool().UpdateAllPoolInfo()
-
-	// 更新所有代币价格 (从链上 Oracle 读取)
-	services.NewTokenPrice().UpdateContractPrice()
-
-	// 更新代币符号 (从代币合约读取 symbol())
-	services.NewTokenSymbol().UpdateContractSymbol()
-
-	// 更新代币 Logo (从预配置的 URL 获取)
-	services.NewTokenLogo().UpdateTokenLogo()
-
-	// 监控账户余额 (检查合约地址的 BNB 余额)
-	services.NewBalanceMonitor().Monitor()
-
-	// 写入 PLGR 价格到链上 Oracle (主网已禁用)
-	// services.NewTokenPrice().SavePlgrPrice()
-	// 测试网: 写入固定测试价格
-	services.NewTokenPrice().SavePlgrPriceTestNet()
+	// 配置了 ws_url 的链订阅 newHeads，新区块到达时执行 on_head = true 的任务，ws_url 修改后需要重启
+	watchHeads()
 
 	// ============================================================
 	// Step 4: 配置定时任务调度
-	// 使用 gocron 库，所有任务在 UTC 时区运行
+	// 使用 robfig/cron 库，所有任务在 UTC 时区运行，执行计划由 [jobs] 配置
 	// ============================================================
-	s := gocron.NewScheduler()
-	s.ChangeLoc(time.UTC) // 设置时区为 UTC
+	c := newScheduler()
 
-	// 每 2 分钟: 同步借贷池信息
-	// 从链上读取所有池子的最新状态
-	_ = s.Every(2).Minutes().From(gocron.NextTick()).Do(services.NewPool().UpdateAllPoolInfo)
-
-	// 每 1 分钟: 更新代币价格
-	// 从链上 Oracle 读取代币价格并保存到数据库
-	_ = s.Every(1).Minute().From(gocron.NextTick()).Do(services.NewTokenPrice().UpdateContractPrice)
+	// ============================================================
+	// Step 5: 启动调度器
+	// [schedule] 或 [jobs] 热加载后停止当前调度器，按新配置重建
+	// 正在执行的任务不会被中断，runner 保证重建后同一任务不会并发执行
+	// ============================================================
+	reload := make(chan struct{}, 1)
+	config.OnReload(func(changed []string) {
+		for _, key := range changed {
+			if key == "schedule" || key == "jobs" {
+				select {
+				case reload <- struct{}{}:
+				default:
+				}
+				return
+			}
+		}
+	})
 
-	// 每 2 小时: 更新代币符号
-	// 代币符号变化较少，低频更新即可
-	_ = s.Every(2).Hours().From(gocron.NextTick()).Do(services.NewTokenSymbol().UpdateContractSymbol)
+	c.Start()
+	for range reload {
+		c.Stop()
+		c = newScheduler()
+		c.Start()
+		log.Logger.Sugar().Info("scheduler rebuilt with ", config.Config().Jobs)
+	}
+}
 
-	// 每 2 小时: 更新代币 Logo
-	_ = s.Every(2).Hours().From(gocron.NextTick()).Do(services.NewTokenLogo().UpdateTokenLogo)
+// newScheduler 按当前 [jobs] 配置创建定时任务，未配置或 enabled = false 的任务不调度
+func newScheduler() *cron.Cron {
+	c := cron.New(cron.WithLocation(time.UTC), cron.WithParser(config.CronParser))
 
-	// 每 30 分钟: 监控账户余额
-	// 如果余额低于阈值，发送告警邮件
-	_ = s.Every(30).Minutes().From(gocron.NextTick()).Do(services.NewBalanceMonitor().Monitor)
+	known := map[string]bool{}
+	for _, j := range jobs() {
+		known[j.name] = true
+		conf, ok := config.Config().Jobs[j.name]
+		if !ok {
+			log.Logger.Sugar().Error("job ", j.name, " is not configured in [jobs], not scheduled")
+			continue
+		}
+		if !conf.Enabled {
+			log.Logger.Sugar().Info("job ", j.name, " is disabled")
+			continue
+		}
+		run := scheduled(j)
+		if conf.OnHead {
+			run = onHeadOrCron(j)
+		}
+		if _, err := c.AddFunc(conf.Cron, run); err != nil {
+			log.Logger.Sugar().Error("job ", j.name, " cron err ", err)
+		}
+	}
 
-	// 每 30 分钟: 写入 PLGR 价格到链上 (主网已禁用)
-	// _ = s.Every(30).Minutes().From(gocron.NextTick()).Do(services.NewTokenPrice().SavePlgrPrice)
+	for name := range config.Config().Jobs {
+		if !known[name] {
+			log.Logger.Sugar().Error("unknown job [jobs.", name, "]")
+		}
+	}
+	return c
+}
 
-	// 每 30 分钟: 写入 PLGR 价格到测试网
-	_ = s.Every(30).Minutes().From(gocron.NextTick()).Do(services.NewTokenPrice().SavePlgrPriceTestNet)
+// onHeadOrCron on_head 的任务在处理的链都有正常的 newHeads 订阅时由新区块触发，cron 不再执行，订阅断开后恢复按 cron 执行
+func onHeadOrCron(j job) func() {
+	run := scheduled(j)
+	return func() {
+		if headsCover(j.name) {
+			return
+		}
+		run()
+	}
+}
 
-	// ============================================================
-	// Step 5: 启动调度器
-	// <-s.Start() 会阻塞当前 goroutine，直到调度器停止
-	// ============================================================
-	<-s.Start()
+// scheduled 多实例部署时 run = "shard" 以外的任务只在 leader 上执行，每次触发时检查，leader 切换后立即生效
+func scheduled(j job) func() {
+	return func() {
+		if config.Config().Jobs[j.name].Run != config.JobRunShard && !cluster.IsLeader() {
+			return
+		}
+		j.run()
+	}
 }
@@ -0,0 +1,81 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DialEth 连接节点，http(s) 节点的每次 JSON-RPC 请求记录为请求 ctx (CallOpts.Context 等) 的子 span
+// ws 节点没有 HTTP 请求可以拦截，直接使用 ethclient.DialContext
+func DialEth(ctx context.Context, rawUrl string) (*ethclient.Client, error) {
+	if !strings.HasPrefix(rawUrl, "http://") && !strings.HasPrefix(rawUrl, "https://") {
+		return ethclient.DialContext(ctx, rawUrl)
+	}
+	client, err := rpc.DialHTTPWithClient(rawUrl, &http.Client{Transport: rpcTransport{base: http.DefaultTransport}})
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
+
+// rpcTransport 记录 JSON-RPC 请求的 span，并向节点传递 traceparent
+type rpcTransport struct {
+	base http.RoundTripper
+}
+
+func (t rpcTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if !hasSpan(ctx) {
+		return t.base.RoundTrip(req)
+	}
+
+	// 读取请求体取得 JSON-RPC 方法名，批量请求记为 batch
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	method := "batch"
+	msg := struct {
+		Method string `json:"method"`
+	}{}
+	if json.Unmarshal(body, &msg) == nil {
+		method = msg.Method
+	}
+
+	ctx, span := Tracer().Start(ctx, "eth "+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.RPCSystemKey.String("jsonrpc"), semconv.RPCMethodKey.String(method), semconv.NetPeerNameKey.String(req.URL.Hostname())))
+	defer span.End()
+
+	out := req.Clone(ctx)
+	out.Body = ioutil.NopCloser(bytes.NewReader(body))
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(out.Header))
+	rsp, err := t.base.RoundTrip(out)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(rsp.StatusCode))
+	if rsp.StatusCode/100 != 2 {
+		span.SetStatus(codes.Error, rsp.Status)
+	}
+	return rsp, nil
+}
@@ -0,0 +1,83 @@
+package telemetry
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "telemetry:span"
+
+type gormPlugin struct{}
+
+// GormPlugin 为每条 SQL 创建 span，db.statement 为不含参数值的 SQL
+// 需要通过 db.Mysql.WithContext(ctx) 传入 ctx
+func GormPlugin() gorm.Plugin {
+	return gormPlugin{}
+}
+
+func (gormPlugin) Name() string {
+	return "telemetry"
+}
+
+func (gormPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	errs := []error{
+		callback.Create().Before("gorm:create").Register("telemetry:before_create", gormStart("create")),
+		callback.Create().After("gorm:create").Register("telemetry:after_create", gormEnd),
+		callback.Query().Before("gorm:query").Register("telemetry:before_query", gormStart("query")),
+		callback.Query().After("gorm:query").Register("telemetry:after_query", gormEnd),
+		callback.Update().Before("gorm:update").Register("telemetry:before_update", gormStart("update")),
+		callback.Update().After("gorm:update").Register("telemetry:after_update", gormEnd),
+		callback.Delete().Before("gorm:delete").Register("telemetry:before_delete", gormStart("delete")),
+		callback.Delete().After("gorm:delete").Register("telemetry:after_delete", gormEnd),
+		callback.Row().Before("gorm:row").Register("telemetry:before_row", gormStart("row")),
+		callback.Row().After("gorm:row").Register("telemetry:after_row", gormEnd),
+		callback.Raw().Before("gorm:raw").Register("telemetry:before_raw", gormStart("raw")),
+		callback.Raw().After("gorm:raw").Register("telemetry:after_raw", gormEnd),
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func gormStart(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		ctx := tx.Statement.Context
+		if ctx == nil || !hasSpan(ctx) {
+			return
+		}
+		_, span := Tracer().Start(ctx, "mysql "+operation, trace.WithSpanKind(trace.SpanKindClient))
+		tx.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func gormEnd(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBSystemMySQL,
+		semconv.DBSQLTableKey.String(tx.Statement.Table),
+		semconv.DBStatementKey.String(tx.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(tx.Error)
+		span.SetStatus(codes.Error, tx.Error.Error())
+	}
+}
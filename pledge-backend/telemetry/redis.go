@@ -0,0 +1,52 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// redisConn 为 DoContext 调用创建 span，其它方法直接调用原连接
+type redisConn struct {
+	redis.Conn
+}
+
+// RedisConn 包装连接池中的连接，使用 redis.DoContext(conn, ctx, ...) 时记录为 ctx 的子 span
+func RedisConn(conn redis.Conn) redis.Conn {
+	return redisConn{Conn: conn}
+}
+
+func (c redisConn) DoContext(ctx context.Context, commandName string, args ...interface{}) (interface{}, error) {
+	if !hasSpan(ctx) {
+		return redis.DoContext(c.Conn, ctx, commandName, args...)
+	}
+
+	ctx, span := Tracer().Start(ctx, "redis "+strings.ToLower(commandName),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperationKey.String(strings.ToLower(commandName))))
+	defer span.End()
+
+	reply, err := redis.DoContext(c.Conn, ctx, commandName, args...)
+	if err != nil && err != redis.ErrNil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return reply, err
+}
+
+func (c redisConn) ReceiveContext(ctx context.Context) (interface{}, error) {
+	return redis.ReceiveContext(c.Conn, ctx)
+}
+
+func (c redisConn) DoWithTimeout(timeout time.Duration, commandName string, args ...interface{}) (interface{}, error) {
+	return redis.DoWithTimeout(c.Conn, timeout, commandName, args...)
+}
+
+func (c redisConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
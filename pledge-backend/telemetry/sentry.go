@@ -0,0 +1,85 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"pledge-backend/config"
+	"pledge-backend/log"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// 【Sentry】
+// panic 和严重错误 (喂价写链失败、行情中断) 上报到 Sentry，而不是只写一行没人看的日志。
+// 请求和定时任务的 ctx 中保存各自的 Sentry hub，上报时带上请求信息或任务名称，
+// 有 span 时附带 trace_id，可以在 collector 中找到对应的链路。
+//
+// [sentry] enabled = false 时不初始化客户端，上报为空操作。
+
+// InitSentry 按 [sentry] 配置初始化 Sentry 客户端，serviceName 区分 api 和 task 进程
+// 返回的 flush 在进程退出前调用，发送缓冲中的事件
+func InitSentry(serviceName string) (func(), error) {
+	conf := config.Config().Sentry
+	if !conf.Enabled {
+		return func() {}, nil
+	}
+	err := sentry.Init(sentry.ClientOptions{
+		Dsn:              conf.Dsn,
+		Environment:      conf.Environment,
+		Release:          config.Config().Env.Version,
+		ServerName:       serviceName,
+		AttachStacktrace: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		sentry.Flush(2 * time.Second)
+	}, nil
+}
+
+// RequestContext 返回带有请求信息的 ctx，之后的上报附带请求的 method、url、header
+func RequestContext(r *http.Request) context.Context {
+	hub := sentry.CurrentHub().Clone()
+	hub.Scope().SetRequest(r)
+	return sentry.SetHubOnContext(r.Context(), hub)
+}
+
+// jobContext 返回带有任务名称的 ctx，之后的上报带 job 标签
+func jobContext(ctx context.Context, name string) context.Context {
+	hub := sentry.CurrentHub().Clone()
+	hub.Scope().SetTag("job", name)
+	return sentry.SetHubOnContext(ctx, hub)
+}
+
+// CaptureError 上报严重错误，tags 为错误的上下文，例如 symbol、chain_id
+func CaptureError(ctx context.Context, err error, tags map[string]string) {
+	hub := hubFromContext(ctx)
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetLevel(sentry.LevelError)
+		scope.SetTags(tags)
+		hub.CaptureException(err)
+	})
+}
+
+// ReportPanic 记录 panic 的日志和堆栈并上报，在 recover() 之后调用
+func ReportPanic(ctx context.Context, recovered interface{}) {
+	log.Logger.Error("panic recovered", zap.String("panic", fmt.Sprint(recovered)), zap.Stack("stack"))
+	hubFromContext(ctx).RecoverWithContext(ctx, recovered)
+}
+
+// hubFromContext ctx 中的 hub，没有时使用全局 hub 的副本；有 span 时带 trace_id 标签
+func hubFromContext(ctx context.Context) *sentry.Hub {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = sentry.CurrentHub().Clone()
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		hub.Scope().SetTag("trace_id", spanContext.TraceID().String())
+	}
+	return hub
+}
@@ -0,0 +1,103 @@
+/*
+ * ==================================================================================
+ * telemetry - OpenTelemetry 链路追踪
+ * ==================================================================================
+ *
+ * 【覆盖范围】
+ * - Gin: otelgin 中间件，每个 HTTP 请求一个 span，支持上游 traceparent
+ * - GORM: GormPlugin，每条 SQL 一个 span
+ * - Redis: RedisConn，DoContext 调用一个 span
+ * - 节点 RPC: DialEth，每次 JSON-RPC 请求一个 span
+ * - cron: Job，每次定时任务执行一个根 span
+ *
+ * MySQL、Redis、RPC 只在 ctx 中已有 span 时记录 (即请求或定时任务内部，且传入了 ctx)，
+ * 不会为没有上下文的调用产生孤立的 trace。
+ *
+ * [telemetry] enabled = false 时不设置 TracerProvider，所有 span 都是空操作。
+ *
+ * panic 和严重错误上报到 Sentry，见 sentry.go。
+ * ==================================================================================
+ */
+
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"pledge-backend/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "pledge-backend"
+
+// Init 按 [telemetry] 配置 OTLP/HTTP 导出，serviceName 区分 api 和 task 进程
+// 返回的 shutdown 在进程退出前调用，发送缓冲中的 span
+func Init(serviceName string) (func(context.Context) error, error) {
+	conf := config.Config().Telemetry
+	if !conf.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(conf.Endpoint)}
+	if conf.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(conf.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceVersionKey.String(config.Config().Env.Version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// Tracer pledge-backend 的 tracer
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Job 包装定时任务，每次执行调用一次 RunJob
+func Job(name string, job func(ctx context.Context)) func() {
+	return func() {
+		_ = RunJob(context.Background(), name, job)
+	}
+}
+
+// RunJob 执行一次定时任务并创建根 span，job 内部的 MySQL、Redis、RPC 调用传入 ctx 后成为它的子 span
+// job 中的 panic 被恢复并上报到 Sentry，作为 error 返回，不会导致 task 进程退出
+func RunJob(ctx context.Context, name string, job func(ctx context.Context)) (err error) {
+	ctx, span := Tracer().Start(jobContext(ctx, name), "job "+name)
+	defer span.End()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+			span.SetStatus(codes.Error, err.Error())
+			ReportPanic(ctx, recovered)
+		}
+	}()
+	job(ctx)
+	return nil
+}
+
+// hasSpan ctx 中是否已有 span
+func hasSpan(ctx context.Context) bool {
+	return trace.SpanContextFromContext(ctx).IsValid()
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"pledge-backend/config"
+	"time"
+)
+
+// SendTelegram 通过 [alert] telegram_bot_token 发送消息到 telegram_chat_id
+func SendTelegram(text string) error {
+	uri := "https://api.telegram.org/bot" + config.Config().Alert.TelegramBotToken + "/sendMessage"
+	return postJson(uri, map[string]interface{}{
+		"chat_id": config.Config().Alert.TelegramChatId,
+		"text":    text,
+	})
+}
+
+// PagerDuty 事件类型
+const (
+	PagerdutyTrigger = "trigger"
+	PagerdutyResolve = "resolve"
+)
+
+// SendPagerDuty 发送 PagerDuty Events API v2 事件，相同 dedupKey 的 trigger 合并为一个 incident，resolve 关闭它
+func SendPagerDuty(action, dedupKey, summary string) error {
+	event := map[string]interface{}{
+		"routing_key":  config.Config().Alert.PagerdutyRoutingKey,
+		"event_action": action,
+		"dedup_key":    dedupKey,
+	}
+	if action == PagerdutyTrigger {
+		event["payload"] = map[string]interface{}{
+			"summary":  summary,
+			"source":   "pledge-task",
+			"severity": "critical",
+		}
+	}
+	return postJson(config.Config().Alert.PagerdutyUrl, event)
+}
+
+// SendWebhook 以 POST JSON 发送 webhook，secret 非空时请求头 X-Pledge-Signature 为 sha256=<请求体的 HMAC-SHA256 (hex)>
+func SendWebhook(uri, secret string, data interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		header.Set("X-Pledge-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	return post(uri, body, header)
+}
+
+// postJson 发送 JSON，非 2xx 响应视为失败
+func postJson(uri string, data interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return post(uri, body, http.Header{})
+}
+
+func post(uri string, body []byte, header http.Header) error {
+	req, err := http.NewRequest(http.MethodPost, uri, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{Timeout: 10 * time.Second}
+	rsp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		rspBody, _ := ioutil.ReadAll(rsp.Body)
+		return errors.New(rsp.Status + " " + string(rspBody))
+	}
+	return nil
+}
@@ -9,11 +9,16 @@ import (
 
 // SendEmail dataType 1 test, 2 html
 func SendEmail(data []byte, dataType int) error {
+	return SendEmailWithSubject(config.Config().Email.Subject, data, dataType)
+}
+
+// SendEmailWithSubject dataType 1 test, 2 html
+func SendEmailWithSubject(subject string, data []byte, dataType int) error {
 	e := &email.Email{
-		To:      config.Config.Email.To,      // []string{"test@example.com"},
-		Cc:      config.Config.Email.Cc,      // []string{"test@example.com"},
-		From:    config.Config.Email.From,    // "Jordan Wright <test@gmail.com>",
-		Subject: config.Config.Email.Subject, //"Awesome Subject",
+		To:      config.Config().Email.To,   // []string{"test@example.com"},
+		Cc:      config.Config().Email.Cc,   // []string{"test@example.com"},
+		From:    config.Config().Email.From, // "Jordan Wright <test@gmail.com>",
+		Subject: subject,                    //"Awesome Subject",
 		Headers: textproto.MIMEHeader{},
 	}
 	if dataType == 1 {
@@ -21,16 +26,28 @@ func SendEmail(data []byte, dataType int) error {
 	} else {
 		e.HTML = data
 	}
-	return e.Send(config.Config.Email.Host+":"+config.Config.Email.Port, smtp.PlainAuth("", config.Config.Email.Username, config.Config.Email.Pwd, config.Config.Email.Host))
+	return e.Send(config.Config().Email.Host+":"+config.Config().Email.Port, smtp.PlainAuth("", config.Config().Email.Username, config.Config().Email.Pwd, config.Config().Email.Host))
+}
+
+// SendEmailTo 发送 HTML 邮件到指定收件人，不抄送 [email] cc，用于发给终端用户的邮件
+func SendEmailTo(to []string, subject string, html []byte) error {
+	e := &email.Email{
+		To:      to,
+		From:    config.Config().Email.From,
+		Subject: subject,
+		Headers: textproto.MIMEHeader{},
+		HTML:    html,
+	}
+	return e.Send(config.Config().Email.Host+":"+config.Config().Email.Port, smtp.PlainAuth("", config.Config().Email.Username, config.Config().Email.Pwd, config.Config().Email.Host))
 }
 
 // SendEmailWithAttach dataType 1 test, 2 html
 func SendEmailWithAttach(data []byte, dataType int, filename string) error {
 	e := &email.Email{
-		To:      config.Config.Email.To,
-		Cc:      config.Config.Email.Cc,
-		From:    config.Config.Email.From,
-		Subject: config.Config.Email.Subject,
+		To:      config.Config().Email.To,
+		Cc:      config.Config().Email.Cc,
+		From:    config.Config().Email.From,
+		Subject: config.Config().Email.Subject,
 		Headers: textproto.MIMEHeader{},
 	}
 	if dataType == 1 {
@@ -42,5 +59,5 @@ func SendEmailWithAttach(data []byte, dataType int, filename string) error {
 	if err != nil {
 		return err
 	}
-	return e.Send(config.Config.Email.Host+config.Config.Email.Port, smtp.PlainAuth("", config.Config.Email.Username, config.Config.Email.Pwd, config.Config.Email.Host))
+	return e.Send(config.Config().Email.Host+config.Config().Email.Port, smtp.PlainAuth("", config.Config().Email.Username, config.Config().Email.Pwd, config.Config().Email.Host))
 }
@@ -11,7 +11,7 @@ func CreateToken(username string) (string, error) {
 		"username": username,
 		"exp":      time.Now().Add(time.Hour * 24 * 30).Unix(),
 	})
-	token, err := at.SignedString([]byte(config.Config.Jwt.SecretKey))
+	token, err := at.SignedString([]byte(config.Config().Jwt.SecretKey))
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"pledge-backend/config"
+	"pledge-backend/utils/sigv4"
+	"strings"
+	"time"
+)
+
+// S3PutObject 使用 AWS Signature V4 上传对象到 [export] 配置的 S3 兼容存储 (path-style: {endpoint}/{bucket}/{key})
+func S3PutObject(key string, data []byte, contentType string) error {
+	_, err := s3Do(http.MethodPut, key, nil, data, contentType)
+	return err
+}
+
+// S3GetObject 下载对象
+func S3GetObject(key string) ([]byte, error) {
+	return s3Do(http.MethodGet, key, nil, nil, "")
+}
+
+// S3DeleteObject 删除对象，对象不存在时 S3 同样返回成功
+func S3DeleteObject(key string) error {
+	_, err := s3Do(http.MethodDelete, key, nil, nil, "")
+	return err
+}
+
+// S3ListObjects 列出 prefix 下的全部对象 key (ListObjectsV2，按 key 排序)
+func S3ListObjects(prefix string) ([]string, error) {
+	keys := make([]string, 0)
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {strings.TrimLeft(prefix, "/")}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		body, err := s3Do(http.MethodGet, "", query, nil, "")
+		if err != nil {
+			return nil, err
+		}
+		var result struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		if err = xml.Unmarshal(body, &result); err != nil {
+			return nil, err
+		}
+		for _, content := range result.Contents {
+			keys = append(keys, content.Key)
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// s3Do 发送签名后的请求，key 为空时请求 bucket 本身，返回响应内容
+func s3Do(method, key string, query url.Values, data []byte, contentType string) ([]byte, error) {
+	conf := config.Config().Export
+	endpoint, err := url.Parse(strings.TrimRight(conf.Endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+	path := "/" + conf.Bucket
+	if key != "" {
+		path += "/" + strings.TrimLeft(key, "/")
+	}
+	escapedPath := (&url.URL{Path: path}).EscapedPath()
+	rawUrl := endpoint.Scheme + "://" + endpoint.Host + escapedPath
+	if len(query) > 0 {
+		rawUrl += "?" + strings.ReplaceAll(query.Encode(), "+", "%20")
+	}
+
+	req, err := http.NewRequest(method, rawUrl, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	sigv4.Sign(req, data, conf.Region, "s3", sigv4.Credentials{AccessKey: conf.AccessKey, SecretKey: conf.SecretKey})
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	rsp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	body, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode/100 != 2 {
+		return nil, errors.New("s3 " + strings.ToLower(method) + " " + rsp.Status + " " + string(body))
+	}
+	return body, nil
+}
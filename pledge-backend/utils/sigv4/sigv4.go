@@ -0,0 +1,86 @@
+package sigv4
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Credentials AWS 访问密钥，SessionToken 只在使用临时凭证时需要
+type Credentials struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+// Sign 为请求添加 AWS Signature V4 签名 (X-Amz-Date、X-Amz-Content-Sha256、Authorization)
+// 签名覆盖 host 和请求上已设置的全部请求头，body 必须与请求实际发送的内容一致
+func Sign(req *http.Request, body []byte, region, service string, cred Credentials) {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	shortDate := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if cred.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", cred.SessionToken)
+	}
+
+	// 规范请求头: 小写、按名称排序
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + headers[name] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	// 规范请求
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		strings.ReplaceAll(req.URL.Query().Encode(), "+", "%20"),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	// 待签名字符串和签名
+	scope := shortDate + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	signingKey := hmacSha256([]byte("AWS4"+cred.SecretKey), shortDate)
+	signingKey = hmacSha256(signingKey, region)
+	signingKey = hmacSha256(signingKey, service)
+	signingKey = hmacSha256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+cred.AccessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}